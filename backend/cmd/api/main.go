@@ -1,16 +1,32 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	userentity "roadmap/internal/domain/entities/user"
 	"roadmap/internal/handler"
 	"roadmap/internal/handler/middleware"
+	roadmaphandler "roadmap/internal/handler/roadmap"
 	userhandler "roadmap/internal/handler/user"
 	"roadmap/internal/infrastructure/database"
 	jwtservice "roadmap/internal/pkg/jwt"
+	"roadmap/internal/pkg/mailer"
+	"roadmap/internal/pkg/oidc"
+	"roadmap/internal/pkg/password"
+	"roadmap/internal/pkg/ratelimit"
+	"roadmap/internal/pkg/webauthn"
+	ratelimitrepo "roadmap/internal/repository/ratelimit"
+	roadmaprepo "roadmap/internal/repository/roadmap"
 	userrepo "roadmap/internal/repository/user"
+	roadmapusecase "roadmap/internal/usecase/roadmap"
 	userusecase "roadmap/internal/usecase/user"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -34,19 +50,201 @@ func initDatabase() *database.Database {
 }
 
 func initJWT() *jwtservice.JWTService {
-	secretKey := getEnv("JWT_SECRET_KEY", "your-secret-key-change-in-production")
-	expiresInHoursStr := getEnv("JWT_EXPIRES_IN_HOURS", "24")
+	expiresIn := accessTokenTTL()
 
-	expiresInHours, err := strconv.Atoi(expiresInHoursStr)
-	if err != nil || expiresInHours <= 0 {
-		log.Printf("Invalid JWT_EXPIRES_IN_HOURS value '%s', using default 24 hours", expiresInHoursStr)
-		expiresInHours = 24
+	if keysDir := os.Getenv("JWT_KEYS_DIR"); keysDir != "" {
+		signingKeyID := os.Getenv("JWT_SIGNING_KEY_ID")
+		keySet, err := jwtservice.LoadKeySet(keysDir, signingKeyID)
+		if err != nil {
+			log.Fatalf("Failed to load JWT keys: %v", err)
+		}
+
+		jwtService := jwtservice.NewJWTServiceWithKeySet(keySet, expiresIn)
+		reloadKeySetOnSignal(jwtService, keysDir, signingKeyID)
+
+		log.Printf("JWT service initialized with %s key %q, access token expiration: %s",
+			keySet.SigningKey().Algorithm, keySet.SigningKey().ID, expiresIn)
+		return jwtService
+	}
+
+	if secretKey := os.Getenv("JWT_SECRET_KEY"); secretKey != "" {
+		log.Printf("JWT service initialized with HMAC secret, access token expiration: %s", expiresIn)
+		return jwtservice.NewJWTService(secretKey, expiresIn)
+	}
+
+	key, err := jwtservice.GenerateEd25519Key("ephemeral-" + time.Now().UTC().Format("20060102T150405"))
+	if err != nil {
+		log.Fatalf("Failed to generate JWT key: %v", err)
+	}
+	keySet, err := jwtservice.NewKeySet([]*jwtservice.Key{key}, "")
+	if err != nil {
+		log.Fatalf("Failed to create JWT key set: %v", err)
+	}
+
+	log.Println("WARNING: neither JWT_KEYS_DIR nor JWT_SECRET_KEY is set, " +
+		"signing with an ephemeral key; issued tokens will not survive a restart")
+	return jwtservice.NewJWTServiceWithKeySet(keySet, expiresIn)
+}
+
+// accessTokenTTL reads JWT_ACCESS_TOKEN_TTL. Deployments that still set the
+// deprecated JWT_EXPIRES_IN_HOURS keep their access token lifetime until they
+// switch over.
+func accessTokenTTL() time.Duration {
+	const defaultTTL = 15 * time.Minute
+
+	hours := os.Getenv("JWT_EXPIRES_IN_HOURS")
+	switch {
+	case hours == "":
+		return getEnvDuration("JWT_ACCESS_TOKEN_TTL", defaultTTL)
+	case os.Getenv("JWT_ACCESS_TOKEN_TTL") != "":
+		log.Println("WARNING: JWT_EXPIRES_IN_HOURS is deprecated and ignored because JWT_ACCESS_TOKEN_TTL is set")
+		return getEnvDuration("JWT_ACCESS_TOKEN_TTL", defaultTTL)
 	}
 
-	expiresIn := time.Duration(expiresInHours) * time.Hour
+	n, err := strconv.Atoi(hours)
+	if err != nil || n <= 0 {
+		log.Printf("Invalid JWT_EXPIRES_IN_HOURS value '%s', using default %s", hours, defaultTTL)
+		return defaultTTL
+	}
+
+	log.Printf("WARNING: JWT_EXPIRES_IN_HOURS is deprecated, set JWT_ACCESS_TOKEN_TTL=%dh instead", n)
+	return time.Duration(n) * time.Hour
+}
+
+// reloadKeySetOnSignal re-reads the key directory on SIGHUP so keys can be
+// rotated without a restart.
+func reloadKeySetOnSignal(jwtService *jwtservice.JWTService, keysDir, signingKeyID string) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		for range signals {
+			keySet, err := jwtservice.LoadKeySet(keysDir, signingKeyID)
+			if err != nil {
+				log.Printf("Failed to reload JWT keys, keeping current key set: %v", err)
+				continue
+			}
+			jwtService.SetKeySet(keySet)
+			log.Printf("JWT keys reloaded, signing with key %q", keySet.SigningKey().ID)
+		}
+	}()
+}
+
+func initMailer() mailer.Mailer {
+	if getEnv("MAILER", "log") != "smtp" {
+		log.Println("Mailer initialized in log mode, emails are written to the application log")
+		return mailer.NewLogMailer()
+	}
+
+	config := mailer.SMTPConfig{
+		Host:     getEnv("SMTP_HOST", "localhost"),
+		Port:     getEnv("SMTP_PORT", "1025"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     getEnv("SMTP_FROM", "no-reply@roadmap.local"),
+		Timeout:  getEnvDuration("SMTP_TIMEOUT", 10*time.Second),
+	}
 
-	log.Printf("JWT service initialized with expiration: %d hours", expiresInHours)
-	return jwtservice.NewJWTService(secretKey, expiresIn)
+	log.Printf("Mailer initialized with SMTP server %s:%s", config.Host, config.Port)
+	return mailer.NewSMTPMailer(config)
+}
+
+func initWebAuthn(appBaseURL string) *webauthn.WebAuthn {
+	var origins []string
+	for _, origin := range strings.Split(getEnv("WEBAUTHN_ORIGINS", appBaseURL), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, strings.TrimRight(origin, "/"))
+		}
+	}
+
+	webAuthn, err := webauthn.New(webauthn.Config{
+		RPID:    getEnv("WEBAUTHN_RP_ID", "localhost"),
+		RPName:  getEnv("WEBAUTHN_RP_NAME", "Roadmap"),
+		Origins: origins,
+		Timeout: getEnvDuration("WEBAUTHN_TIMEOUT", 5*time.Minute),
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize WebAuthn: %v", err)
+	}
+
+	return webAuthn
+}
+
+// initOIDCProviders reads the providers named in OIDC_PROVIDERS. Each
+// provider "name" is configured through OIDC_<NAME>_ISSUER, _CLIENT_ID,
+// _CLIENT_SECRET, _REDIRECT_URL and _SCOPES (space separated).
+func initOIDCProviders(appBaseURL string) userusecase.OIDCProviders {
+	var providers []*oidc.Provider
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider, err := oidc.NewProvider(oidc.Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", appBaseURL+"/auth/oidc/"+name+"/callback"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure OIDC provider: %v", err)
+		}
+
+		log.Printf("OIDC provider %q configured with issuer %s", name, os.Getenv(prefix+"ISSUER"))
+		providers = append(providers, provider)
+	}
+
+	return userusecase.NewOIDCProviders(providers...)
+}
+
+// corsAllowedOrigins reads the comma separated CORS_ALLOWED_ORIGINS, which
+// defaults to the frontend at APP_BASE_URL.
+func corsAllowedOrigins() []string {
+	defaultOrigin := strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:3000"), "/")
+
+	var origins []string
+	for _, origin := range strings.Split(getEnv("CORS_ALLOWED_ORIGINS", defaultOrigin), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, strings.TrimRight(origin, "/"))
+		}
+	}
+	return origins
+}
+
+// initCookieAuth enables cookie authentication when AUTH_COOKIE_MODE is
+// true. It returns nil otherwise, leaving tokens to the Authorization header.
+func initCookieAuth() *middleware.CookieAuth {
+	if getEnv("AUTH_COOKIE_MODE", "false") != "true" {
+		return nil
+	}
+
+	sameSite := http.SameSiteLaxMode
+	switch value := getEnv("AUTH_COOKIE_SAMESITE", "lax"); strings.ToLower(value) {
+	case "lax":
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		sameSite = http.SameSiteNoneMode
+	default:
+		log.Printf("Invalid AUTH_COOKIE_SAMESITE value '%s', using default lax", value)
+	}
+
+	secure := getEnv("AUTH_COOKIE_SECURE", "true") == "true"
+	if sameSite == http.SameSiteNoneMode && !secure {
+		log.Fatal("AUTH_COOKIE_SAMESITE=none requires AUTH_COOKIE_SECURE=true")
+	}
+
+	log.Println("Cookie authentication enabled")
+	return middleware.NewCookieAuth(middleware.CookieAuthConfig{
+		Domain:           os.Getenv("AUTH_COOKIE_DOMAIN"),
+		RefreshTokenPath: "/api/v1/users",
+		Secure:           secure,
+		SameSite:         sameSite,
+	})
 }
 
 func getEnv(key, defaultValue string) string {
@@ -56,30 +254,628 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("Invalid %s value '%s', using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+
+	return duration
+}
+
+// configureTrustedProxies decides whose X-Forwarded-For headers are believed
+// when determining client IPs for throttling and logs. Without
+// TRUSTED_PROXIES the connecting address is used as is, so clients cannot
+// pick their own IP.
+func configureTrustedProxies(router *gin.Engine) {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	if err := router.SetTrustedProxies(proxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("Invalid %s value '%s', using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+
+	return n
+}
+
+// startPurge runs purge every interval in the background; what names the
+// purged records in log messages.
+func startPurge(what string, purge func(context.Context) (int64, error), interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			purged, err := purge(context.Background())
+			if err != nil {
+				log.Printf("Failed to purge %s: %v", what, err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Purged %d %s", purged, what)
+			}
+		}
+	}()
+}
+
+// initPasswordHasher configures the Argon2id cost of new password hashes.
+// Changing it upgrades existing hashes as their owners sign in.
+func initPasswordHasher() *password.Hasher {
+	params := password.DefaultArgon2idParams
+	params.Memory = uint32(getEnvInt("ARGON2_MEMORY_KIB", int(params.Memory)))
+	params.Iterations = uint32(getEnvInt("ARGON2_ITERATIONS", int(params.Iterations)))
+	params.Parallelism = uint8(getEnvInt("ARGON2_PARALLELISM", int(params.Parallelism)))
+	return password.NewHasher(params)
+}
+
+// initPasswordValidator configures the policy for new passwords. Breached
+// passwords are only rejected when BREACHED_PASSWORDS_FILE points to a local
+// copy of a breach corpus ordered by hash.
+func initPasswordValidator() *userusecase.PasswordValidator {
+	policy := userusecase.DefaultPasswordPolicy
+	policy.MinStrength = getEnvInt("PASSWORD_MIN_STRENGTH", policy.MinStrength)
+
+	if path := os.Getenv("BREACHED_PASSWORDS_FILE"); path != "" {
+		breached, err := password.OpenBreachedFile(path)
+		if err != nil {
+			log.Fatalf("Failed to load breached passwords: %v", err)
+		}
+		policy.Breached = breached
+	}
+
+	return userusecase.NewPasswordValidator(policy)
+}
+
+// initLoginThrottle configures brute-force protection for password logins.
+// Per-IP limits are looser than per-account ones since many users can share
+// an address.
+func initLoginThrottle(repository userrepo.LoginThrottleRepository, notifier userusecase.LockoutNotifier) *userusecase.LoginThrottle {
+	lockoutDuration := getEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+	forgetAfter := getEnvDuration("LOGIN_FAILURE_WINDOW", time.Hour)
+
+	return userusecase.NewLoginThrottle(
+		repository,
+		userusecase.LoginThrottlePolicy{
+			FreeAttempts:     3,
+			BaseDelay:        time.Second,
+			MaxDelay:         time.Minute,
+			LockoutThreshold: getEnvInt("LOGIN_LOCKOUT_THRESHOLD", 10),
+			LockoutDuration:  lockoutDuration,
+			ForgetAfter:      forgetAfter,
+		},
+		userusecase.LoginThrottlePolicy{
+			FreeAttempts:     20,
+			BaseDelay:        time.Second,
+			MaxDelay:         time.Minute,
+			LockoutThreshold: getEnvInt("LOGIN_IP_LOCKOUT_THRESHOLD", 100),
+			LockoutDuration:  lockoutDuration,
+			ForgetAfter:      forgetAfter,
+		},
+		notifier,
+	)
+}
+
+// initRateLimiter configures request rate limits. Buckets are kept in
+// memory unless RATE_LIMIT_STORE is "postgres", which shares them between
+// API instances.
+func initRateLimiter(db *database.Database) *middleware.RateLimiter {
+	var store ratelimit.Store = ratelimit.NewMemoryStore()
+	if getEnv("RATE_LIMIT_STORE", "memory") == "postgres" {
+		repository := ratelimitrepo.NewBucketRepository(db)
+		startPurge("expired rate limit buckets", func(ctx context.Context) (int64, error) {
+			return repository.DeleteExpired(ctx, time.Now().UTC())
+		}, time.Hour)
+		store = repository
+	}
+
+	return middleware.NewRateLimiter(store,
+		middleware.RateLimitPolicy{
+			Name: "login",
+			Routes: []string{
+				"POST /api/v1/users/login",
+				"POST /api/v1/users/login/2fa",
+				"POST /api/v1/users/passkeys/login/finish",
+				"POST /api/v1/users/oidc/:provider/login/finish",
+				"POST /api/v1/users/password/forgot",
+				"POST /api/v1/users/password/reset",
+			},
+			KeyBy: middleware.RateLimitByIP,
+			Limit: ratelimit.Limit{
+				Requests: getEnvInt("RATE_LIMIT_LOGIN_PER_MINUTE", 10),
+				Period:   time.Minute,
+			},
+		},
+		middleware.RateLimitPolicy{
+			Name:   "register",
+			Routes: []string{"POST /api/v1/users/register"},
+			KeyBy:  middleware.RateLimitByIP,
+			Limit: ratelimit.Limit{
+				Requests: getEnvInt("RATE_LIMIT_REGISTER_PER_HOUR", 20),
+				Period:   time.Hour,
+				Burst:    5,
+			},
+		},
+		middleware.RateLimitPolicy{
+			Name:  "user",
+			KeyBy: middleware.RateLimitByUser,
+			Limit: ratelimit.Limit{
+				Requests: getEnvInt("RATE_LIMIT_USER_PER_MINUTE", 300),
+				Period:   time.Minute,
+			},
+		},
+		middleware.RateLimitPolicy{
+			Name:  "api_key",
+			KeyBy: middleware.RateLimitByAPIKey,
+			Limit: ratelimit.Limit{
+				Requests: getEnvInt("RATE_LIMIT_API_KEY_PER_MINUTE", 120),
+				Period:   time.Minute,
+			},
+		},
+	)
+}
+
+// bootstrapAdmins grants the admin role to the accounts listed in
+// ADMIN_EMAILS so that a fresh deployment has someone who can grant roles.
+// Accounts that do not exist yet are skipped; restart after they register.
+func bootstrapAdmins(userRepository userrepo.UserRepository, roleRepository userrepo.RoleRepository) {
+	ctx := context.Background()
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			continue
+		}
+
+		user, err := userRepository.GetByEmail(ctx, email)
+		if err != nil {
+			log.Printf("Skipping admin bootstrap for %s: %v", email, err)
+			continue
+		}
+
+		err = roleRepository.Grant(ctx, &userentity.UserRole{
+			UserID:    user.ID,
+			Role:      userentity.RoleAdmin,
+			GrantedAt: time.Now(),
+		})
+		if err != nil && !errors.Is(err, userrepo.ErrRoleAlreadyGranted) {
+			log.Fatalf("Failed to grant admin role to %s: %v", email, err)
+		}
+		if err == nil {
+			log.Printf("Granted admin role to %s", email)
+		}
+	}
+}
+
 func main() {
 	db := initDatabase()
 	defer db.Close()
 
 	router := gin.New()
+	configureTrustedProxies(router)
+
+	middleware.SetupMiddleware(router, corsAllowedOrigins())
+
+	rateLimiter := initRateLimiter(db)
+	router.Use(rateLimiter.Middleware())
 
-	middleware.SetupMiddleware(router)
+	cookieAuth := initCookieAuth()
+	if cookieAuth != nil {
+		router.Use(cookieAuth.Middleware())
+	}
 
 	userRepository := userrepo.NewUserRepository(db)
+	refreshTokenRepository := userrepo.NewRefreshTokenRepository(db)
+	tokenRevocationRepository := userrepo.NewTokenRevocationRepository(db)
+	passwordResetTokenRepository := userrepo.NewPasswordResetTokenRepository(db)
+	emailVerificationTokenRepository := userrepo.NewEmailVerificationTokenRepository(db)
+	emailChangeRequestRepository := userrepo.NewEmailChangeRequestRepository(db)
+	totpRepository := userrepo.NewTOTPRepository(db)
+	recoveryCodeRepository := userrepo.NewRecoveryCodeRepository(db)
+	mfaChallengeRepository := userrepo.NewMFAChallengeRepository(db)
+	passkeyRepository := userrepo.NewPasskeyRepository(db)
+	webAuthnChallengeRepository := userrepo.NewWebAuthnChallengeRepository(db)
+	userIdentityRepository := userrepo.NewUserIdentityRepository(db)
+	oidcAuthRequestRepository := userrepo.NewOIDCAuthRequestRepository(db)
+	personalAccessTokenRepository := userrepo.NewPersonalAccessTokenRepository(db)
+	roleRepository := userrepo.NewRoleRepository(db)
+	loginThrottleRepository := userrepo.NewLoginThrottleRepository(db)
+	sessionRepository := userrepo.NewSessionRepository(db)
+	roadmapRepository := roadmaprepo.NewRoadmapRepository(db)
+	graphRepository := roadmaprepo.NewGraphRepository(db)
+	progressRepository := roadmaprepo.NewProgressRepository(db)
+	resourceRepository := roadmaprepo.NewResourceRepository(db)
+	documentRepository := roadmaprepo.NewDocumentRepository(db)
+
+	bootstrapAdmins(userRepository, roleRepository)
+
+	appMailer := initMailer()
+	appBaseURL := strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:3000"), "/")
 
 	jwtService := initJWT()
+	passwordHasher := initPasswordHasher()
+	passwordValidator := initPasswordValidator()
+
+	sessionService := userusecase.NewSessionService(
+		sessionRepository,
+		getEnvDuration("SESSION_CACHE_TTL", 30*time.Second),
+	)
+	startPurge("expired sessions", sessionService.PurgeExpired, time.Hour)
+
+	tokenIssuer := userusecase.NewTokenIssuer(
+		jwtService,
+		refreshTokenRepository,
+		sessionService,
+		getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+	)
+
+	tokenRevocationService := userusecase.NewTokenRevocationService(
+		tokenRevocationRepository,
+		getEnvDuration("TOKEN_REVOCATION_CACHE_TTL", 30*time.Second),
+	)
+
+	startPurge("expired token revocations", tokenRevocationService.PurgeExpired, time.Hour)
 
-	createUserUseCase := userusecase.NewCreateUserUseCase(userRepository)
-	registerUseCase := userusecase.NewRegisterUseCase(userRepository, jwtService)
-	loginUseCase := userusecase.NewLoginUseCase(userRepository, jwtService)
+	loginThrottle := initLoginThrottle(loginThrottleRepository, userusecase.NewMailLockoutNotifier(appMailer))
+	startPurge("stale login throttles", loginThrottle.PurgeStale, time.Hour)
 
-	userHandler := userhandler.NewUserHandler(createUserUseCase, registerUseCase, loginUseCase)
+	emailVerificationSender := userusecase.NewEmailVerificationSender(
+		emailVerificationTokenRepository,
+		appMailer,
+		appBaseURL+"/verify-email",
+		getEnvDuration("EMAIL_VERIFICATION_TOKEN_TTL", 24*time.Hour),
+	)
+
+	passwordResetSender := userusecase.NewPasswordResetSender(
+		passwordResetTokenRepository,
+		appMailer,
+		appBaseURL+"/reset-password",
+		getEnvDuration("PASSWORD_RESET_TOKEN_TTL", time.Hour),
+	)
+
+	suspensionService := userusecase.NewSuspensionService(
+		userRepository,
+		getEnvDuration("SUSPENSION_CACHE_TTL", 30*time.Second),
+	)
+
+	twoFactorChallenger := userusecase.NewTwoFactorChallenger(
+		totpRepository,
+		mfaChallengeRepository,
+		getEnvDuration("MFA_CHALLENGE_TTL", 5*time.Minute),
+	)
+
+	passkeyCeremony := userusecase.NewPasskeyCeremony(webAuthnChallengeRepository, initWebAuthn(appBaseURL))
+	oidcFlow := userusecase.NewOIDCFlow(
+		initOIDCProviders(appBaseURL),
+		oidcAuthRequestRepository,
+		getEnvDuration("OIDC_STATE_TTL", 10*time.Minute),
+	)
+
+	createUserUseCase := userusecase.NewCreateUserUseCase(userRepository, passwordHasher, passwordValidator)
+	registerUseCase := userusecase.NewRegisterUseCase(userRepository, passwordHasher, passwordValidator, tokenIssuer, emailVerificationSender)
+	loginUseCase := userusecase.NewLoginUseCase(userRepository, passwordHasher, tokenIssuer, twoFactorChallenger, loginThrottle)
+	refreshTokenUseCase := userusecase.NewRefreshTokenUseCase(userRepository, refreshTokenRepository, tokenIssuer)
+	logoutUseCase := userusecase.NewLogoutUseCase(refreshTokenRepository, tokenRevocationService)
+	logoutAllUseCase := userusecase.NewLogoutAllUseCase(refreshTokenRepository, tokenRevocationService)
+	forgotPasswordUseCase := userusecase.NewForgotPasswordUseCase(userRepository, passwordResetSender)
+	resetPasswordUseCase := userusecase.NewResetPasswordUseCase(
+		userRepository,
+		passwordHasher,
+		passwordValidator,
+		passwordResetTokenRepository,
+		refreshTokenRepository,
+		tokenRevocationService,
+	)
+	changePasswordUseCase := userusecase.NewChangePasswordUseCase(
+		userRepository,
+		passwordHasher,
+		passwordValidator,
+		refreshTokenRepository,
+		tokenRevocationService,
+		tokenIssuer,
+	)
+	getProfileUseCase := userusecase.NewGetProfileUseCase(userRepository)
+	updateProfileUseCase := userusecase.NewUpdateProfileUseCase(userRepository)
+	verifyEmailUseCase := userusecase.NewVerifyEmailUseCase(userRepository, emailVerificationTokenRepository)
+	resendVerificationUseCase := userusecase.NewResendVerificationUseCase(
+		userRepository,
+		emailVerificationTokenRepository,
+		emailVerificationSender,
+		userusecase.ResendPolicy{
+			Cooldown:     getEnvDuration("EMAIL_VERIFICATION_RESEND_COOLDOWN", time.Minute),
+			MaxPerWindow: 5,
+			Window:       time.Hour,
+		},
+	)
+	requestEmailChangeUseCase := userusecase.NewRequestEmailChangeUseCase(
+		userRepository,
+		passwordHasher,
+		emailChangeRequestRepository,
+		appMailer,
+		userusecase.EmailChangeLinks{
+			ConfirmURL: appBaseURL + "/confirm-email-change",
+			CancelURL:  appBaseURL + "/cancel-email-change",
+		},
+		getEnvDuration("EMAIL_CHANGE_REQUEST_TTL", 24*time.Hour),
+	)
+	confirmEmailChangeUseCase := userusecase.NewConfirmEmailChangeUseCase(
+		userRepository,
+		emailChangeRequestRepository,
+		passwordResetTokenRepository,
+		tokenRevocationService,
+	)
+	cancelEmailChangeUseCase := userusecase.NewCancelEmailChangeUseCase(emailChangeRequestRepository)
+	twoFactorSetupUseCase := userusecase.NewTwoFactorSetupUseCase(
+		userRepository,
+		totpRepository,
+		getEnv("TOTP_ISSUER", "Roadmap"),
+	)
+	twoFactorConfirmUseCase := userusecase.NewTwoFactorConfirmUseCase(totpRepository, recoveryCodeRepository)
+	twoFactorLoginUseCase := userusecase.NewTwoFactorLoginUseCase(
+		userRepository,
+		totpRepository,
+		recoveryCodeRepository,
+		mfaChallengeRepository,
+		tokenIssuer,
+	)
+	beginPasskeyRegistrationUseCase := userusecase.NewBeginPasskeyRegistrationUseCase(
+		userRepository,
+		passkeyRepository,
+		passkeyCeremony,
+	)
+	finishPasskeyRegistrationUseCase := userusecase.NewFinishPasskeyRegistrationUseCase(passkeyRepository, passkeyCeremony)
+	beginPasskeyLoginUseCase := userusecase.NewBeginPasskeyLoginUseCase(passkeyCeremony)
+	finishPasskeyLoginUseCase := userusecase.NewFinishPasskeyLoginUseCase(
+		userRepository,
+		passkeyRepository,
+		passkeyCeremony,
+		tokenIssuer,
+	)
+	listPasskeysUseCase := userusecase.NewListPasskeysUseCase(passkeyRepository)
+	deletePasskeyUseCase := userusecase.NewDeletePasskeyUseCase(passkeyRepository)
+	beginOIDCLoginUseCase := userusecase.NewBeginOIDCLoginUseCase(oidcFlow)
+	finishOIDCLoginUseCase := userusecase.NewFinishOIDCLoginUseCase(
+		userRepository,
+		userIdentityRepository,
+		oidcFlow,
+		tokenIssuer,
+		twoFactorChallenger,
+		emailVerificationSender,
+	)
+	beginOIDCLinkUseCase := userusecase.NewBeginOIDCLinkUseCase(oidcFlow)
+	finishOIDCLinkUseCase := userusecase.NewFinishOIDCLinkUseCase(userIdentityRepository, oidcFlow)
+	createAccessTokenUseCase := userusecase.NewCreateAccessTokenUseCase(personalAccessTokenRepository)
+	listAccessTokensUseCase := userusecase.NewListAccessTokensUseCase(personalAccessTokenRepository)
+	getAccessTokenUseCase := userusecase.NewGetAccessTokenUseCase(personalAccessTokenRepository)
+	updateAccessTokenUseCase := userusecase.NewUpdateAccessTokenUseCase(personalAccessTokenRepository)
+	deleteAccessTokenUseCase := userusecase.NewDeleteAccessTokenUseCase(personalAccessTokenRepository)
+	listSessionsUseCase := userusecase.NewListSessionsUseCase(sessionRepository)
+	revokeSessionUseCase := userusecase.NewRevokeSessionUseCase(sessionService)
+	listRolesUseCase := userusecase.NewListRolesUseCase(roleRepository)
+	getUserRolesUseCase := userusecase.NewGetUserRolesUseCase(userRepository)
+	grantRoleUseCase := userusecase.NewGrantRoleUseCase(userRepository, roleRepository)
+	revokeRoleUseCase := userusecase.NewRevokeRoleUseCase(userRepository, roleRepository, tokenRevocationService)
+	listUsersUseCase := userusecase.NewListUsersUseCase(userRepository)
+	getUserUseCase := userusecase.NewGetUserUseCase(userRepository)
+	suspendUserUseCase := userusecase.NewSuspendUserUseCase(userRepository, suspensionService, refreshTokenRepository)
+	unsuspendUserUseCase := userusecase.NewUnsuspendUserUseCase(userRepository, suspensionService)
+	forcePasswordResetUseCase := userusecase.NewForcePasswordResetUseCase(
+		userRepository,
+		refreshTokenRepository,
+		tokenRevocationService,
+		passwordResetSender,
+	)
+	deleteUserUseCase := userusecase.NewDeleteUserUseCase(userRepository, suspensionService)
+
+	// Aggregates that store data about users register their export and
+	// deletion hooks here.
+	accountDataHooks := userusecase.NewAccountDataHooks()
+	accountDataHooks.RegisterExporter("roadmaps", roadmapusecase.NewExportUserRoadmapsUseCase(roadmapRepository).Execute)
+	accountDataHooks.RegisterExporter("roadmap_progress", roadmapusecase.NewExportUserProgressUseCase(progressRepository).Execute)
+	accountDataHooks.RegisterExporter(
+		"resource_completions",
+		roadmapusecase.NewExportUserResourceCompletionsUseCase(resourceRepository).Execute,
+	)
+	accountDeletionGracePeriod := getEnvDuration("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour)
+	deleteAccountUseCase := userusecase.NewDeleteAccountUseCase(
+		userRepository,
+		refreshTokenRepository,
+		sessionRepository,
+		passwordHasher,
+		suspensionService,
+		accountDeletionGracePeriod,
+	)
+	exportAccountDataUseCase := userusecase.NewExportAccountDataUseCase(
+		userRepository,
+		sessionRepository,
+		passkeyRepository,
+		userIdentityRepository,
+		personalAccessTokenRepository,
+		accountDataHooks,
+	)
+	purgeDeletedAccountsUseCase := userusecase.NewPurgeDeletedAccountsUseCase(
+		userRepository,
+		accountDataHooks,
+		accountDeletionGracePeriod,
+	)
+	startPurge("deleted accounts", purgeDeletedAccountsUseCase.Execute, time.Hour)
+
+	createRoadmapUseCase := roadmapusecase.NewCreateRoadmapUseCase(roadmapRepository)
+	listRoadmapsUseCase := roadmapusecase.NewListRoadmapsUseCase(roadmapRepository)
+	getRoadmapUseCase := roadmapusecase.NewGetRoadmapUseCase(roadmapRepository)
+	updateRoadmapUseCase := roadmapusecase.NewUpdateRoadmapUseCase(roadmapRepository)
+	deleteRoadmapUseCase := roadmapusecase.NewDeleteRoadmapUseCase(roadmapRepository)
+	getRoadmapGraphUseCase := roadmapusecase.NewGetRoadmapGraphUseCase(roadmapRepository, graphRepository)
+	getNodeOrderUseCase := roadmapusecase.NewGetNodeOrderUseCase(roadmapRepository, graphRepository)
+	editRoadmapGraphUseCase := roadmapusecase.NewEditRoadmapGraphUseCase(roadmapRepository, graphRepository)
+	getProgressUseCase := roadmapusecase.NewGetProgressUseCase(roadmapRepository, graphRepository, progressRepository)
+	updateProgressUseCase := roadmapusecase.NewUpdateProgressUseCase(roadmapRepository, progressRepository)
+	listResourcesUseCase := roadmapusecase.NewListResourcesUseCase(roadmapRepository, resourceRepository)
+	createResourceUseCase := roadmapusecase.NewCreateResourceUseCase(roadmapRepository, resourceRepository)
+	updateResourceUseCase := roadmapusecase.NewUpdateResourceUseCase(roadmapRepository, resourceRepository)
+	deleteResourceUseCase := roadmapusecase.NewDeleteResourceUseCase(roadmapRepository, resourceRepository)
+	reorderResourcesUseCase := roadmapusecase.NewReorderResourcesUseCase(roadmapRepository, resourceRepository)
+	setResourceCompletionUseCase := roadmapusecase.NewSetResourceCompletionUseCase(
+		roadmapRepository,
+		graphRepository,
+		resourceRepository,
+		progressRepository,
+	)
+	exportRoadmapUseCase := roadmapusecase.NewExportRoadmapUseCase(roadmapRepository, graphRepository, resourceRepository)
+	importRoadmapUseCase := roadmapusecase.NewImportRoadmapUseCase(documentRepository)
+
+	userHandler := userhandler.NewUserHandler(
+		createUserUseCase,
+		registerUseCase,
+		loginUseCase,
+		refreshTokenUseCase,
+		logoutUseCase,
+		logoutAllUseCase,
+	)
+	passwordHandler := userhandler.NewPasswordHandler(forgotPasswordUseCase, resetPasswordUseCase, changePasswordUseCase)
+	profileHandler := userhandler.NewProfileHandler(getProfileUseCase, updateProfileUseCase)
+	emailVerificationHandler := userhandler.NewEmailVerificationHandler(verifyEmailUseCase, resendVerificationUseCase)
+	emailChangeHandler := userhandler.NewEmailChangeHandler(
+		requestEmailChangeUseCase,
+		confirmEmailChangeUseCase,
+		cancelEmailChangeUseCase,
+	)
+	twoFactorHandler := userhandler.NewTwoFactorHandler(twoFactorSetupUseCase, twoFactorConfirmUseCase, twoFactorLoginUseCase)
+	passkeyHandler := userhandler.NewPasskeyHandler(
+		beginPasskeyRegistrationUseCase,
+		finishPasskeyRegistrationUseCase,
+		beginPasskeyLoginUseCase,
+		finishPasskeyLoginUseCase,
+		listPasskeysUseCase,
+		deletePasskeyUseCase,
+	)
+	oidcHandler := userhandler.NewOIDCHandler(
+		beginOIDCLoginUseCase,
+		finishOIDCLoginUseCase,
+		beginOIDCLinkUseCase,
+		finishOIDCLinkUseCase,
+	)
+	accessTokenHandler := userhandler.NewAccessTokenHandler(
+		createAccessTokenUseCase,
+		listAccessTokensUseCase,
+		getAccessTokenUseCase,
+		updateAccessTokenUseCase,
+		deleteAccessTokenUseCase,
+	)
+	sessionHandler := userhandler.NewSessionHandler(listSessionsUseCase, revokeSessionUseCase)
+	accountHandler := userhandler.NewAccountHandler(deleteAccountUseCase, exportAccountDataUseCase)
+	roleHandler := userhandler.NewRoleHandler(listRolesUseCase, getUserRolesUseCase, grantRoleUseCase, revokeRoleUseCase)
+	adminUserHandler := userhandler.NewAdminUserHandler(
+		listUsersUseCase,
+		getUserUseCase,
+		suspendUserUseCase,
+		unsuspendUserUseCase,
+		forcePasswordResetUseCase,
+		deleteUserUseCase,
+	)
+
+	roadmapHandler := roadmaphandler.NewRoadmapHandler(
+		createRoadmapUseCase,
+		listRoadmapsUseCase,
+		getRoadmapUseCase,
+		updateRoadmapUseCase,
+		deleteRoadmapUseCase,
+	)
+	graphHandler := roadmaphandler.NewGraphHandler(getRoadmapGraphUseCase, getNodeOrderUseCase, editRoadmapGraphUseCase)
+	progressHandler := roadmaphandler.NewProgressHandler(getProgressUseCase, updateProgressUseCase)
+	resourceHandler := roadmaphandler.NewResourceHandler(
+		listResourcesUseCase,
+		createResourceUseCase,
+		updateResourceUseCase,
+		deleteResourceUseCase,
+		reorderResourcesUseCase,
+		setResourceCompletionUseCase,
+	)
+	documentHandler := roadmaphandler.NewDocumentHandler(exportRoadmapUseCase, importRoadmapUseCase)
+
+	authOptions := []middleware.AuthOption{
+		middleware.WithRevocationChecker(tokenRevocationService),
+		middleware.WithSessionChecker(sessionService),
+		middleware.WithSuspensionChecker(suspensionService),
+		middleware.WithRateLimiter(rateLimiter),
+		// A leaked access token must not be enough to take over the account,
+		// so credential and session management needs a real sign-in.
+		middleware.WithAccessTokens(
+			userusecase.NewAccessTokenAuthenticator(personalAccessTokenRepository, userRepository),
+			"/api/v1/users/tokens",
+			"/api/v1/users/logout",
+			"/api/v1/users/logout-all",
+			"/api/v1/users/sessions",
+			"/api/v1/users/me",
+			"/api/v1/users/password/change",
+			"/api/v1/users/email/change",
+			"/api/v1/users/2fa",
+			"/api/v1/users/passkeys",
+			"/api/v1/users/oidc/:provider/link",
+			"/api/v1/admin",
+		),
+	}
+	if cookieAuth != nil {
+		authOptions = append(authOptions, middleware.WithCookieAuth(cookieAuth))
+	}
+	if getEnv("REQUIRE_EMAIL_VERIFICATION", "true") == "true" {
+		// Unverified accounts can only look at their profile, sign out, ask
+		// for another verification email and export or delete their data.
+		authOptions = append(authOptions, middleware.WithEmailVerificationRequired(
+			"/api/v1/users/profile",
+			"/api/v1/users/logout",
+			"/api/v1/users/logout-all",
+			"/api/v1/users/email/resend",
+			"/api/v1/users/me",
+			"/api/v1/users/me/export",
+		))
+	}
+	authMiddleware := middleware.AuthMiddleware(jwtService, authOptions...)
 
-	authMiddleware := middleware.AuthMiddleware(jwtService)
+	router.GET("/.well-known/jwks.json", handler.JWKSHandler(jwtService))
 
 	api := router.Group("/api/v1")
 	{
 		api.GET("/health", handler.HealthHandler)
 		userhandler.SetupUserRoutes(api, userHandler, authMiddleware)
+		userhandler.SetupPasswordRoutes(api, passwordHandler, authMiddleware)
+		userhandler.SetupProfileRoutes(api, profileHandler, authMiddleware)
+		userhandler.SetupEmailVerificationRoutes(api, emailVerificationHandler, authMiddleware)
+		userhandler.SetupEmailChangeRoutes(api, emailChangeHandler, authMiddleware)
+		userhandler.SetupTwoFactorRoutes(api, twoFactorHandler, authMiddleware)
+		userhandler.SetupPasskeyRoutes(api, passkeyHandler, authMiddleware)
+		userhandler.SetupOIDCRoutes(api, oidcHandler, authMiddleware)
+		userhandler.SetupAccessTokenRoutes(api, accessTokenHandler, authMiddleware)
+		userhandler.SetupSessionRoutes(api, sessionHandler, authMiddleware)
+		userhandler.SetupAccountRoutes(api, accountHandler, authMiddleware)
+		userhandler.SetupRoleRoutes(api, roleHandler, authMiddleware)
+		userhandler.SetupAdminUserRoutes(api, adminUserHandler, authMiddleware)
+		roadmaphandler.SetupRoadmapRoutes(api, roadmapHandler, authMiddleware)
+		roadmaphandler.SetupGraphRoutes(api, graphHandler, authMiddleware)
+		roadmaphandler.SetupProgressRoutes(api, progressHandler, authMiddleware)
+		roadmaphandler.SetupResourceRoutes(api, resourceHandler, authMiddleware)
+		roadmaphandler.SetupDocumentRoutes(api, documentHandler, authMiddleware)
 	}
 
 	if err := router.Run(":8080"); err != nil {
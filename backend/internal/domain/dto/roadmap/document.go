@@ -0,0 +1,103 @@
+package roadmap
+
+// RoadmapSchemaVersion is the version of RoadmapDocument written by exports.
+// Imports reject documents of any other version.
+const RoadmapSchemaVersion = 1
+
+// RoadmapDocument is a whole roadmap as exported and imported in JSON or
+// YAML; the schema is described in docs/roadmap-document.md. Nodes refer to
+// each other by key, so documents carry no database IDs and can be kept in
+// version control and imported into any account.
+type RoadmapDocument struct {
+	SchemaVersion int                     `json:"schema_version" yaml:"schema_version"`
+	Roadmap       RoadmapMetadataDocument `json:"roadmap" yaml:"roadmap"`
+	Nodes         []NodeDocument          `json:"nodes" yaml:"nodes"`
+	Edges         []EdgeDocument          `json:"edges" yaml:"edges"`
+}
+
+// RoadmapMetadataDocument identifies the roadmap by Slug among the roadmaps
+// of the importing user.
+type RoadmapMetadataDocument struct {
+	Slug        string `json:"slug" yaml:"slug"`
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Visibility  string `json:"visibility" yaml:"visibility"`
+}
+
+// NodeDocument is identified by Key, which stays the same across imports
+// while everything else about the node may change.
+type NodeDocument struct {
+	Key         string             `json:"key" yaml:"key"`
+	Title       string             `json:"title" yaml:"title"`
+	Description string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Type        string             `json:"type" yaml:"type"`
+	Position    PositionDocument   `json:"position" yaml:"position,flow"`
+	Resources   []ResourceDocument `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+type PositionDocument struct {
+	X float64 `json:"x" yaml:"x"`
+	Y float64 `json:"y" yaml:"y"`
+}
+
+// EdgeDocument makes the node with key Prerequisite a prerequisite of the
+// node with key Node.
+type EdgeDocument struct {
+	Prerequisite string `json:"prerequisite" yaml:"prerequisite"`
+	Node         string `json:"node" yaml:"node"`
+}
+
+// ResourceDocument is identified by its normalized URL.
+type ResourceDocument struct {
+	URL              string `json:"url" yaml:"url"`
+	Title            string `json:"title" yaml:"title"`
+	Kind             string `json:"kind" yaml:"kind"`
+	Free             bool   `json:"free" yaml:"free"`
+	Language         string `json:"language,omitempty" yaml:"language,omitempty"`
+	EstimatedMinutes *int   `json:"estimated_minutes,omitempty" yaml:"estimated_minutes,omitempty"`
+}
+
+type ExportRoadmapRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json yaml"`
+}
+
+// ImportRoadmapRequest is bound from the query string; the document is the
+// request body. Without Format it is derived from the Content-Type header.
+// A DryRun reports the changes without making them.
+type ImportRoadmapRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json yaml"`
+	DryRun bool   `form:"dry_run"`
+}
+
+// ImportProblem is one reason a document was rejected. Line and Column are
+// 1-based and point at the offending value; Path names it, such as
+// nodes[2].resources[0].url.
+type ImportProblem struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportChanges counts what an import changed, or would change on a dry run.
+// Resources of deleted nodes are counted as deleted.
+type ImportChanges struct {
+	RoadmapCreated   bool `json:"roadmap_created"`
+	RoadmapUpdated   bool `json:"roadmap_updated"`
+	NodesCreated     int  `json:"nodes_created"`
+	NodesUpdated     int  `json:"nodes_updated"`
+	NodesDeleted     int  `json:"nodes_deleted"`
+	EdgesAdded       int  `json:"edges_added"`
+	EdgesRemoved     int  `json:"edges_removed"`
+	ResourcesCreated int  `json:"resources_created"`
+	ResourcesUpdated int  `json:"resources_updated"`
+	ResourcesDeleted int  `json:"resources_deleted"`
+}
+
+// ImportRoadmapResponse leaves Roadmap out of a dry run that would create
+// the roadmap.
+type ImportRoadmapResponse struct {
+	DryRun  bool             `json:"dry_run"`
+	Roadmap *RoadmapResponse `json:"roadmap,omitempty"`
+	Changes ImportChanges    `json:"changes"`
+}
@@ -0,0 +1,73 @@
+package roadmap
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateNodeRequest may carry a client-generated ID so that edges in the
+// same batch can refer to the new node.
+type CreateNodeRequest struct {
+	ID          *uuid.UUID `json:"id"`
+	Title       string     `json:"title" binding:"required,max=200"`
+	Description string     `json:"description" binding:"max=5000"`
+	Type        string     `json:"type" binding:"required,oneof=topic subtopic milestone"`
+	PositionX   float64    `json:"position_x"`
+	PositionY   float64    `json:"position_y"`
+}
+
+type UpdateNodeRequest struct {
+	ID          uuid.UUID `json:"id" binding:"required"`
+	Title       *string   `json:"title" binding:"omitempty,min=1,max=200"`
+	Description *string   `json:"description" binding:"omitempty,max=5000"`
+	Type        *string   `json:"type" binding:"omitempty,oneof=topic subtopic milestone"`
+	PositionX   *float64  `json:"position_x"`
+	PositionY   *float64  `json:"position_y"`
+}
+
+// EdgeRequest makes PrerequisiteID a prerequisite of NodeID.
+type EdgeRequest struct {
+	PrerequisiteID uuid.UUID `json:"prerequisite_id" binding:"required"`
+	NodeID         uuid.UUID `json:"node_id" binding:"required"`
+}
+
+// EditGraphRequest is applied as a whole or not at all. Edges are removed
+// first, then nodes are deleted, created and updated, and edges are added
+// last; deleting a node also removes its edges.
+type EditGraphRequest struct {
+	RemoveEdges []EdgeRequest       `json:"remove_edges" binding:"max=1000,dive"`
+	DeleteNodes []uuid.UUID         `json:"delete_nodes" binding:"max=500"`
+	CreateNodes []CreateNodeRequest `json:"create_nodes" binding:"max=500,dive"`
+	UpdateNodes []UpdateNodeRequest `json:"update_nodes" binding:"max=500,dive"`
+	AddEdges    []EdgeRequest       `json:"add_edges" binding:"max=1000,dive"`
+}
+
+type NodeResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Key         string    `json:"key,omitempty"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	PositionX   float64   `json:"position_x"`
+	PositionY   float64   `json:"position_y"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type EdgeResponse struct {
+	PrerequisiteID uuid.UUID `json:"prerequisite_id"`
+	NodeID         uuid.UUID `json:"node_id"`
+}
+
+type GraphResponse struct {
+	RoadmapID uuid.UUID      `json:"roadmap_id"`
+	Nodes     []NodeResponse `json:"nodes"`
+	Edges     []EdgeResponse `json:"edges"`
+}
+
+// NodeOrderResponse lists nodes so that each comes after its prerequisites.
+type NodeOrderResponse struct {
+	RoadmapID uuid.UUID      `json:"roadmap_id"`
+	Nodes     []NodeResponse `json:"nodes"`
+}
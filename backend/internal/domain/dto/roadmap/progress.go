@@ -0,0 +1,55 @@
+package roadmap
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NodeStatusUpdate struct {
+	NodeID uuid.UUID `json:"node_id" binding:"required"`
+	Status string    `json:"status" binding:"required,oneof=not_started in_progress done skipped"`
+}
+
+// UpdateProgressRequest changes the status of many nodes at once; when a
+// node is listed twice the last entry wins. Nodes can only be marked done
+// once their prerequisites are done or skipped, unless
+// OverridePrerequisites is set.
+type UpdateProgressRequest struct {
+	Updates               []NodeStatusUpdate `json:"updates" binding:"required,min=1,max=1000,dive"`
+	OverridePrerequisites bool               `json:"override_prerequisites"`
+}
+
+type NodeProgressResponse struct {
+	NodeID                  uuid.UUID  `json:"node_id"`
+	Status                  string     `json:"status"`
+	PrerequisitesOverridden bool       `json:"prerequisites_overridden"`
+	StartedAt               *time.Time `json:"started_at,omitempty"`
+	CompletedAt             *time.Time `json:"completed_at,omitempty"`
+	UpdatedAt               *time.Time `json:"updated_at,omitempty"`
+}
+
+// ProgressSummaryResponse weighs every node by its type. Skipped nodes are
+// left out of PercentDone, so skipping the remaining nodes of a roadmap
+// completes it.
+type ProgressSummaryResponse struct {
+	RoadmapID   uuid.UUID `json:"roadmap_id"`
+	TotalNodes  int       `json:"total_nodes"`
+	NotStarted  int       `json:"not_started"`
+	InProgress  int       `json:"in_progress"`
+	Done        int       `json:"done"`
+	Skipped     int       `json:"skipped"`
+	PercentDone float64   `json:"percent_done"`
+}
+
+type ProgressResponse struct {
+	Summary ProgressSummaryResponse `json:"summary"`
+	Nodes   []NodeProgressResponse  `json:"nodes"`
+}
+
+// NodeProgressExport is one entry of the progress section of an account
+// data export, which spans every roadmap.
+type NodeProgressExport struct {
+	RoadmapID uuid.UUID `json:"roadmap_id"`
+	NodeProgressResponse
+}
@@ -0,0 +1,81 @@
+package roadmap
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateResourceRequest adds a resource at the end of the resources of
+// NodeID. IsFree defaults to true; Language is a BCP 47 tag such as "en" or
+// "pt-BR".
+type CreateResourceRequest struct {
+	NodeID           uuid.UUID `json:"node_id" binding:"required"`
+	URL              string    `json:"url" binding:"required,max=2048"`
+	Title            string    `json:"title" binding:"required,max=200"`
+	Kind             string    `json:"kind" binding:"required,oneof=article video course book other"`
+	IsFree           *bool     `json:"is_free"`
+	Language         string    `json:"language" binding:"omitempty,bcp47_language_tag"`
+	EstimatedMinutes *int      `json:"estimated_minutes" binding:"omitempty,min=1,max=100000"`
+}
+
+// UpdateResourceRequest changes the given fields. An empty Language and an
+// EstimatedMinutes of 0 clear them.
+type UpdateResourceRequest struct {
+	URL              *string `json:"url" binding:"omitempty,min=1,max=2048"`
+	Title            *string `json:"title" binding:"omitempty,min=1,max=200"`
+	Kind             *string `json:"kind" binding:"omitempty,oneof=article video course book other"`
+	IsFree           *bool   `json:"is_free"`
+	Language         *string `json:"language" binding:"omitempty,bcp47_language_tag"`
+	EstimatedMinutes *int    `json:"estimated_minutes" binding:"omitempty,min=0,max=100000"`
+}
+
+// ListResourcesRequest is bound from the query string. Without NodeID the
+// resources of every node are listed.
+type ListResourcesRequest struct {
+	NodeID string `form:"node_id" binding:"omitempty,uuid"`
+}
+
+// ReorderResourcesRequest lists every resource of NodeID in its new order.
+type ReorderResourcesRequest struct {
+	NodeID      uuid.UUID   `json:"node_id" binding:"required"`
+	ResourceIDs []uuid.UUID `json:"resource_ids" binding:"required,min=1,max=1000"`
+}
+
+// ResourceResponse includes whether the caller has completed the resource.
+type ResourceResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	NodeID           uuid.UUID  `json:"node_id"`
+	URL              string     `json:"url"`
+	Title            string     `json:"title"`
+	Kind             string     `json:"kind"`
+	IsFree           bool       `json:"is_free"`
+	Language         string     `json:"language,omitempty"`
+	EstimatedMinutes *int       `json:"estimated_minutes,omitempty"`
+	Position         int        `json:"position"`
+	Completed        bool       `json:"completed"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+type ListResourcesResponse struct {
+	Resources []ResourceResponse `json:"resources"`
+}
+
+// ResourceCompletionResponse is returned when a resource is completed or
+// uncompleted, together with the progress on its node that the change may
+// have moved.
+type ResourceCompletionResponse struct {
+	Resource ResourceResponse     `json:"resource"`
+	Node     NodeProgressResponse `json:"node"`
+}
+
+// ResourceCompletionExport is one entry of the resource completions section
+// of an account data export.
+type ResourceCompletionExport struct {
+	ResourceID  uuid.UUID `json:"resource_id"`
+	RoadmapID   uuid.UUID `json:"roadmap_id"`
+	NodeID      uuid.UUID `json:"node_id"`
+	CompletedAt time.Time `json:"completed_at"`
+}
@@ -0,0 +1,52 @@
+package roadmap
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const DefaultRoadmapPageSize = 20
+
+// CreateRoadmapRequest leaves Slug optional; it is derived from Title when
+// empty, with a numeric suffix if the owner already uses it. Visibility
+// defaults to private.
+type CreateRoadmapRequest struct {
+	Title       string `json:"title" binding:"required,max=200"`
+	Slug        string `json:"slug" binding:"omitempty,max=100"`
+	Description string `json:"description" binding:"max=5000"`
+	Visibility  string `json:"visibility" binding:"omitempty,oneof=private unlisted public"`
+}
+
+type UpdateRoadmapRequest struct {
+	Title       *string `json:"title" binding:"omitempty,min=1,max=200"`
+	Slug        *string `json:"slug" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=5000"`
+	Visibility  *string `json:"visibility" binding:"omitempty,oneof=private unlisted public"`
+}
+
+// ListRoadmapsRequest is bound from the query string. Without OwnerID the
+// caller's own roadmaps and all public roadmaps are listed.
+type ListRoadmapsRequest struct {
+	OwnerID  string `form:"owner_id" binding:"omitempty,uuid"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type RoadmapResponse struct {
+	ID          uuid.UUID `json:"id"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	Visibility  string    `json:"visibility"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ListRoadmapsResponse struct {
+	Roadmaps []RoadmapResponse `json:"roadmaps"`
+	Total    int               `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+}
@@ -0,0 +1,39 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateAccessTokenRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=read write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type UpdateAccessTokenRequest struct {
+	Name   *string  `json:"name" binding:"omitempty,min=1,max=100"`
+	Scopes []string `json:"scopes" binding:"omitempty,min=1,dive,oneof=read write"`
+}
+
+type AccessTokenResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"token_prefix"`
+	Scopes      []string   `json:"scopes"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreateAccessTokenResponse carries the plaintext token. It is only ever
+// returned here; afterwards the token cannot be recovered.
+type CreateAccessTokenResponse struct {
+	AccessTokenResponse
+	Token string `json:"token"`
+}
+
+type ListAccessTokensResponse struct {
+	AccessTokens []AccessTokenResponse `json:"access_tokens"`
+}
@@ -0,0 +1,35 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeleteAccountRequest confirms an account deletion. Password is required
+// for accounts that have one.
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+type DeleteAccountResponse struct {
+	Message string `json:"message"`
+	// PurgeAt is when the account and its data are permanently removed.
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// AccountExport holds everything stored about a user. Sections maps a
+// section name, such as "account" or "sessions", to its data.
+type AccountExport struct {
+	UserID     uuid.UUID      `json:"user_id"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Sections   map[string]any `json:"sections"`
+}
+
+type IdentityExport struct {
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"subject"`
+	Email       string     `json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
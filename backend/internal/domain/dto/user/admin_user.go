@@ -0,0 +1,46 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const DefaultUserPageSize = 20
+
+// ListUsersRequest is bound from the query string. Email and Username match
+// case-insensitive substrings; the created range is inclusive of
+// created_after and exclusive of created_before.
+type ListUsersRequest struct {
+	Email         string     `form:"email" binding:"max=255"`
+	Username      string     `form:"username" binding:"max=100"`
+	CreatedAfter  *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page          int        `form:"page" binding:"omitempty,min=1"`
+	PageSize      int        `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type AdminUserResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email"`
+	DisplayName      string     `json:"display_name"`
+	Bio              string     `json:"bio"`
+	EmailVerified    bool       `json:"email_verified"`
+	Roles            []string   `json:"roles"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+type ListUsersResponse struct {
+	Users    []AdminUserResponse `json:"users"`
+	Total    int                 `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
@@ -0,0 +1,10 @@
+package user
+
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type EmailChangeTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -6,5 +6,9 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	TokenPair
+
+	// MFAChallenge is set instead of TokenPair when a second factor is
+	// required to finish the login.
+	MFAChallenge *MFAChallengeResponse `json:"-"`
 }
@@ -0,0 +1,5 @@
+package user
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
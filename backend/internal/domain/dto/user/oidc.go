@@ -0,0 +1,26 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OIDCAuthorizationResponse struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// OIDCCallbackRequest carries the query parameters the provider appended to
+// the redirect URL.
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+type UserIdentityResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
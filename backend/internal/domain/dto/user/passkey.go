@@ -0,0 +1,27 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/pkg/webauthn"
+)
+
+type FinishPasskeyRegistrationRequest struct {
+	Name       string                        `json:"name" binding:"omitempty,max=100"`
+	Credential webauthn.RegistrationResponse `json:"credential" binding:"required"`
+}
+
+type PasskeyResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	Transports     []string   `json:"transports"`
+	BackupEligible bool       `json:"backup_eligible"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type ListPasskeysResponse struct {
+	Passkeys []PasskeyResponse `json:"passkeys"`
+}
@@ -0,0 +1,22 @@
+package user
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// ChangePasswordResponse carries new tokens for the session that changed the
+// password; every other session has been signed out.
+type ChangePasswordResponse struct {
+	TokenPair
+	Message string `json:"message"`
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProfileResponse struct {
+	UserID        uuid.UUID `json:"user_id"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email"`
+	DisplayName   string    `json:"display_name"`
+	Bio           string    `json:"bio"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// UpdateProfileRequest is a partial update: fields left out of the request
+// body keep their current value.
+type UpdateProfileRequest struct {
+	Username    *string `json:"username" binding:"omitempty,min=3,max=100"`
+	DisplayName *string `json:"display_name" binding:"omitempty,max=100"`
+	Bio         *string `json:"bio" binding:"omitempty,max=500"`
+}
@@ -0,0 +1,23 @@
+package user
+
+import "github.com/google/uuid"
+
+type GrantRoleRequest struct {
+	Role string `json:"role" binding:"required,max=50"`
+}
+
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type ListRolesResponse struct {
+	Roles []RoleResponse `json:"roles"`
+}
+
+type UserRolesResponse struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Roles       []string  `json:"roles"`
+	Permissions []string  `json:"permissions"`
+}
@@ -0,0 +1,28 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClientInfo describes the device a sign-in came from. It is recorded with
+// the session the sign-in starts.
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	// Current marks the session of the token the listing was requested with.
+	Current bool `json:"current"`
+}
+
+type ListSessionsResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
@@ -0,0 +1,18 @@
+package user
+
+import "time"
+
+type TokenPair struct {
+	Token                 string    `json:"token"`
+	TokenExpiresAt        time.Time `json:"token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type RefreshTokenResponse struct {
+	TokenPair
+}
@@ -0,0 +1,31 @@
+package user
+
+import "time"
+
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorConfirmRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAChallengeResponse is returned by login instead of tokens when the
+// account has two-factor authentication enabled.
+type MFAChallengeResponse struct {
+	MFARequired       bool      `json:"mfa_required"`
+	MFAToken          string    `json:"mfa_token"`
+	MFATokenExpiresAt time.Time `json:"mfa_token_expires_at"`
+}
+
+// TwoFactorLoginRequest completes a login. Code is either a TOTP code or
+// one of the user's recovery codes.
+type TwoFactorLoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
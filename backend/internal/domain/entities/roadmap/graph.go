@@ -0,0 +1,168 @@
+package roadmap
+
+import (
+	"container/heap"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	NodeTypeTopic     = "topic"
+	NodeTypeSubtopic  = "subtopic"
+	NodeTypeMilestone = "milestone"
+)
+
+// Node is a topic of a roadmap. The position is where clients draw it and
+// does not affect the order in which nodes are learned. Key is set on nodes
+// created by an import and matches them on the next import.
+type Node struct {
+	ID          uuid.UUID `json:"id"`
+	RoadmapID   uuid.UUID `json:"roadmap_id"`
+	Key         string    `json:"key"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	PositionX   float64   `json:"position_x"`
+	PositionY   float64   `json:"position_y"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Edge makes PrerequisiteID a prerequisite of NodeID.
+type Edge struct {
+	RoadmapID      uuid.UUID `json:"roadmap_id"`
+	PrerequisiteID uuid.UUID `json:"prerequisite_id"`
+	NodeID         uuid.UUID `json:"node_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Graph holds all nodes and edges of one roadmap.
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+}
+
+// CycleError is returned for graphs whose prerequisites loop back on
+// themselves. NodeIDs lists one such loop, each node being a prerequisite of
+// the next and the last one of the first.
+type CycleError struct {
+	NodeIDs []uuid.UUID
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("prerequisites form a cycle through %d nodes", len(e.NodeIDs))
+}
+
+// Prerequisites maps every node ID to the IDs of its direct prerequisites.
+func (g *Graph) Prerequisites() map[uuid.UUID][]uuid.UUID {
+	prerequisites := make(map[uuid.UUID][]uuid.UUID, len(g.Nodes))
+	for _, edge := range g.Edges {
+		prerequisites[edge.NodeID] = append(prerequisites[edge.NodeID], edge.PrerequisiteID)
+	}
+	return prerequisites
+}
+
+// TopologicalOrder returns the nodes so that every node comes after all of
+// its prerequisites. Nodes that could go in either order keep the order of
+// g.Nodes. It returns a *CycleError if no such order exists.
+func (g *Graph) TopologicalOrder() ([]*Node, error) {
+	index := make(map[uuid.UUID]int, len(g.Nodes))
+	for i, node := range g.Nodes {
+		index[node.ID] = i
+	}
+
+	dependents := make([][]int, len(g.Nodes))
+	remaining := make([]int, len(g.Nodes))
+	for _, edge := range g.Edges {
+		from, fromOK := index[edge.PrerequisiteID]
+		to, toOK := index[edge.NodeID]
+		if !fromOK || !toOK {
+			continue
+		}
+		dependents[from] = append(dependents[from], to)
+		remaining[to]++
+	}
+
+	ready := &indexHeap{}
+	for i, count := range remaining {
+		if count == 0 {
+			heap.Push(ready, i)
+		}
+	}
+
+	order := make([]*Node, 0, len(g.Nodes))
+	for ready.Len() > 0 {
+		i := heap.Pop(ready).(int)
+		order = append(order, g.Nodes[i])
+		for _, dependent := range dependents[i] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				heap.Push(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) < len(g.Nodes) {
+		return nil, g.findCycle(index, remaining)
+	}
+	return order, nil
+}
+
+// findCycle walks prerequisites backwards from a node left over by
+// TopologicalOrder. Every leftover node has a leftover prerequisite, so the
+// walk must eventually revisit a node, which closes a cycle.
+func (g *Graph) findCycle(index map[uuid.UUID]int, remaining []int) *CycleError {
+	prerequisites := g.Prerequisites()
+
+	start := -1
+	for i, count := range remaining {
+		if count > 0 {
+			start = i
+			break
+		}
+	}
+
+	visited := make(map[uuid.UUID]int)
+	var path []uuid.UUID
+	current := g.Nodes[start].ID
+	for {
+		if at, seen := visited[current]; seen {
+			cycle := path[at:]
+			// path runs from dependents to prerequisites; report it the other
+			// way round.
+			for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+				cycle[i], cycle[j] = cycle[j], cycle[i]
+			}
+			return &CycleError{NodeIDs: cycle}
+		}
+		visited[current] = len(path)
+		path = append(path, current)
+
+		for _, prerequisite := range prerequisites[current] {
+			if i, ok := index[prerequisite]; ok && remaining[i] > 0 {
+				current = prerequisite
+				break
+			}
+		}
+	}
+}
+
+// indexHeap is a min-heap of node indexes.
+type indexHeap []int
+
+func (h indexHeap) Len() int           { return len(h) }
+func (h indexHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h indexHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *indexHeap) Push(x any) {
+	*h = append(*h, x.(int))
+}
+
+func (h *indexHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
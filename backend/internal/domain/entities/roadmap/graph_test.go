@@ -0,0 +1,74 @@
+package roadmap
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGraph(nodeCount int, edges ...[2]int) *Graph {
+	graph := &Graph{}
+	for i := 0; i < nodeCount; i++ {
+		graph.Nodes = append(graph.Nodes, &Node{ID: uuid.New(), Title: string(rune('A' + i))})
+	}
+	for _, edge := range edges {
+		graph.Edges = append(graph.Edges, &Edge{
+			PrerequisiteID: graph.Nodes[edge[0]].ID,
+			NodeID:         graph.Nodes[edge[1]].ID,
+		})
+	}
+	return graph
+}
+
+func titles(nodes []*Node) string {
+	var s string
+	for _, node := range nodes {
+		s += node.Title
+	}
+	return s
+}
+
+func TestGraph_TopologicalOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		nodeCount int
+		edges     [][2]int
+		want      string
+	}{
+		{name: "empty", want: ""},
+		{name: "no edges keeps node order", nodeCount: 3, want: "ABC"},
+		{name: "chain against node order", nodeCount: 3, edges: [][2]int{{2, 1}, {1, 0}}, want: "CBA"},
+		{name: "diamond", nodeCount: 4, edges: [][2]int{{3, 1}, {3, 2}, {1, 0}, {2, 0}}, want: "DBCA"},
+		{name: "independent nodes first come first", nodeCount: 4, edges: [][2]int{{3, 0}}, want: "BCDA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := newTestGraph(tt.nodeCount, tt.edges...).TopologicalOrder()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, titles(order))
+		})
+	}
+}
+
+func TestGraph_TopologicalOrder_Cycle(t *testing.T) {
+	// A -> B -> C -> D -> B, with E depending on the cycle.
+	graph := newTestGraph(5, [2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3}, [2]int{3, 1}, [2]int{3, 4})
+
+	_, err := graph.TopologicalOrder()
+
+	var cycleErr *CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	require.Len(t, cycleErr.NodeIDs, 3)
+	assert.ElementsMatch(t, []uuid.UUID{graph.Nodes[1].ID, graph.Nodes[2].ID, graph.Nodes[3].ID}, cycleErr.NodeIDs)
+
+	// Each node is a prerequisite of the next one.
+	prerequisites := graph.Prerequisites()
+	for i, id := range cycleErr.NodeIDs {
+		next := cycleErr.NodeIDs[(i+1)%len(cycleErr.NodeIDs)]
+		assert.Contains(t, prerequisites[next], id)
+	}
+}
@@ -0,0 +1,47 @@
+package roadmap
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ProgressNotStarted = "not_started"
+	ProgressInProgress = "in_progress"
+	ProgressDone       = "done"
+	// ProgressSkipped nodes count as finished for the nodes that depend on
+	// them but are left out of the percentage done.
+	ProgressSkipped = "skipped"
+)
+
+// NodeProgress is where a user stands on one node. PrerequisitesOverridden
+// records that the node was marked done before all of its prerequisites
+// were finished.
+type NodeProgress struct {
+	UserID                  uuid.UUID  `json:"user_id"`
+	RoadmapID               uuid.UUID  `json:"roadmap_id"`
+	NodeID                  uuid.UUID  `json:"node_id"`
+	Status                  string     `json:"status"`
+	PrerequisitesOverridden bool       `json:"prerequisites_overridden"`
+	StartedAt               *time.Time `json:"started_at,omitempty"`
+	CompletedAt             *time.Time `json:"completed_at,omitempty"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+}
+
+func (p *NodeProgress) IsFinished() bool {
+	return p.Status == ProgressDone || p.Status == ProgressSkipped
+}
+
+// NodeWeight is how much a node of the given type counts towards the
+// progress on its roadmap.
+func NodeWeight(nodeType string) int {
+	switch nodeType {
+	case NodeTypeMilestone:
+		return 3
+	case NodeTypeTopic:
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,44 @@
+package roadmap
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ResourceKindArticle = "article"
+	ResourceKindVideo   = "video"
+	ResourceKindCourse  = "course"
+	ResourceKindBook    = "book"
+	ResourceKindOther   = "other"
+)
+
+// Resource is learning material attached to a node. NormalizedURL is the
+// form of URL used to detect the same resource being added twice to a
+// roadmap; Position orders the resources of a node.
+type Resource struct {
+	ID               uuid.UUID `json:"id"`
+	RoadmapID        uuid.UUID `json:"roadmap_id"`
+	NodeID           uuid.UUID `json:"node_id"`
+	URL              string    `json:"url"`
+	NormalizedURL    string    `json:"normalized_url"`
+	Title            string    `json:"title"`
+	Kind             string    `json:"kind"`
+	IsFree           bool      `json:"is_free"`
+	Language         string    `json:"language"`
+	EstimatedMinutes *int      `json:"estimated_minutes,omitempty"`
+	Position         int       `json:"position"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ResourceCompletion records that a user has worked through a resource.
+// RoadmapID and NodeID are those of the resource.
+type ResourceCompletion struct {
+	UserID      uuid.UUID `json:"user_id"`
+	ResourceID  uuid.UUID `json:"resource_id"`
+	RoadmapID   uuid.UUID `json:"roadmap_id"`
+	NodeID      uuid.UUID `json:"node_id"`
+	CompletedAt time.Time `json:"completed_at"`
+}
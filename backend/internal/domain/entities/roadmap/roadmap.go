@@ -0,0 +1,38 @@
+package roadmap
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// VisibilityPrivate roadmaps are only visible to their owner and to
+	// moderators.
+	VisibilityPrivate = "private"
+	// VisibilityUnlisted roadmaps are visible to anyone who knows their ID
+	// but are left out of listings.
+	VisibilityUnlisted = "unlisted"
+	VisibilityPublic   = "public"
+)
+
+type Roadmap struct {
+	ID          uuid.UUID `json:"id"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	Visibility  string    `json:"visibility"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (r *Roadmap) IsOwnedBy(userID uuid.UUID) bool {
+	return r.OwnerID == userID
+}
+
+// IsVisibleTo reports whether userID may read the roadmap without
+// moderation rights.
+func (r *Roadmap) IsVisibleTo(userID uuid.UUID) bool {
+	return r.Visibility != VisibilityPrivate || r.IsOwnedBy(userID)
+}
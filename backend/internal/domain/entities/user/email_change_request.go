@@ -0,0 +1,31 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailChangeRequest is a pending switch from OldEmail to NewEmail. It is
+// confirmed from the new address and can be cancelled from the old one.
+type EmailChangeRequest struct {
+	ID               uuid.UUID  `json:"id"`
+	UserID           uuid.UUID  `json:"user_id"`
+	OldEmail         string     `json:"old_email"`
+	NewEmail         string     `json:"new_email"`
+	ConfirmTokenHash string     `json:"-"`
+	CancelTokenHash  string     `json:"-"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	ConfirmedAt      *time.Time `json:"confirmed_at,omitempty"`
+	CancelledAt      *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+func (r *EmailChangeRequest) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// IsPending reports whether the request has been neither confirmed nor cancelled.
+func (r *EmailChangeRequest) IsPending() bool {
+	return r.ConfirmedAt == nil && r.CancelledAt == nil
+}
@@ -0,0 +1,27 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerificationToken proves ownership of Email, which is the address the
+// token was sent to and not necessarily the user's current address.
+type EmailVerificationToken struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Email     string     `json:"email"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (t *EmailVerificationToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+func (t *EmailVerificationToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
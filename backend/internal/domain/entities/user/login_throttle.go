@@ -0,0 +1,27 @@
+package user
+
+import "time"
+
+const (
+	LoginThrottleScopeAccount = "account"
+	LoginThrottleScopeIP      = "ip"
+)
+
+// LoginThrottle counts recent failed logins for one account (keyed by
+// normalized email) or one client IP. LockedUntil is set while further
+// attempts are refused, either as backoff or as a lockout.
+type LoginThrottle struct {
+	Scope         string     `json:"scope"`
+	Key           string     `json:"key"`
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+}
+
+// RetryAfter returns how long attempts are still refused, or zero.
+func (t *LoginThrottle) RetryAfter(now time.Time) time.Duration {
+	if t.LockedUntil == nil || !now.Before(*t.LockedUntil) {
+		return 0
+	}
+	return t.LockedUntil.Sub(now)
+}
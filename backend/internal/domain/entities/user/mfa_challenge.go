@@ -0,0 +1,25 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MFAChallenge struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	Attempts  int        `json:"attempts"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (c *MFAChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+func (c *MFAChallenge) IsUsed() bool {
+	return c.UsedAt != nil
+}
@@ -0,0 +1,34 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OIDCAuthRequest keeps the nonce and PKCE verifier of an authorization code
+// flow between the redirect to the provider and the callback. UserID is set
+// when a signed-in user is linking a provider to their account.
+type OIDCAuthRequest struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       *uuid.UUID `json:"user_id,omitempty"`
+	Provider     string     `json:"provider"`
+	StateHash    string     `json:"-"`
+	Nonce        string     `json:"-"`
+	CodeVerifier string     `json:"-"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	UsedAt       *time.Time `json:"used_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (r *OIDCAuthRequest) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+func (r *OIDCAuthRequest) IsUsed() bool {
+	return r.UsedAt != nil
+}
+
+func (r *OIDCAuthRequest) IsLink() bool {
+	return r.UserID != nil
+}
@@ -0,0 +1,21 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Passkey struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	CredentialID   []byte     `json:"-"`
+	PublicKey      []byte     `json:"-"`
+	SignCount      uint32     `json:"-"`
+	AAGUID         []byte     `json:"-"`
+	Transports     []string   `json:"transports"`
+	BackupEligible bool       `json:"backup_eligible"`
+	Name           string     `json:"name"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
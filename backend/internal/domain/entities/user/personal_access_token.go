@@ -0,0 +1,26 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessToken lets scripts authenticate as a user without the
+// user's password. Only the hash of the token is stored; TokenPrefix keeps
+// its first characters so users can tell their tokens apart.
+type PersonalAccessToken struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Name        string     `json:"name"`
+	TokenHash   string     `json:"-"`
+	TokenPrefix string     `json:"token_prefix"`
+	Scopes      []string   `json:"scopes"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (t *PersonalAccessToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
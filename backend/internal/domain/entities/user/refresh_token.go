@@ -0,0 +1,29 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is an opaque, rotating credential. Only the SHA-256 hash of the
+// token is persisted. Every token issued by rotation shares the FamilyID of the
+// token it was created from, so a reused token can revoke the whole chain.
+type RefreshToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	FamilyID   uuid.UUID  `json:"family_id"`
+	TokenHash  string     `json:"-"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy *uuid.UUID `json:"replaced_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
@@ -0,0 +1,39 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+	// RoleMember is held implicitly by every account and cannot be granted
+	// or revoked.
+	RoleMember = "member"
+)
+
+const (
+	PermissionUsersCreate      = "users:create"
+	PermissionUsersRead        = "users:read"
+	PermissionUsersManage      = "users:manage"
+	PermissionRolesManage      = "roles:manage"
+	PermissionRoadmapsCreate   = "roadmaps:create"
+	PermissionRoadmapsModerate = "roadmaps:moderate"
+)
+
+// Role is a named set of permissions. Roles and their permissions live in
+// Postgres so they can be adjusted without a deploy.
+type Role struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type UserRole struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	Role      string     `json:"role"`
+	GrantedBy *uuid.UUID `json:"granted_by,omitempty"`
+	GrantedAt time.Time  `json:"granted_at"`
+}
@@ -0,0 +1,24 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is one sign-in on one device. It lasts as long as the refresh token
+// family started by the sign-in, so its ID is the FamilyID of those refresh
+// tokens, and access tokens carry it in their "sid" claim.
+type Session struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
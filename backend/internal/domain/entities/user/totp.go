@@ -0,0 +1,21 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserTOTP is an authenticator secret enrolled by a user. It only protects
+// logins once ConfirmedAt is set.
+type UserTOTP struct {
+	UserID       uuid.UUID  `json:"user_id"`
+	Secret       string     `json:"-"`
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+	LastUsedStep int64      `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (t *UserTOTP) IsEnabled() bool {
+	return t.ConfirmedAt != nil
+}
@@ -7,10 +7,27 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	Username     string    `json:"username"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID               uuid.UUID  `json:"id"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email"`
+	DisplayName      string     `json:"display_name"`
+	Bio              string     `json:"bio"`
+	PasswordHash     string     `json:"-"`
+	EmailVerifiedAt  *time.Time `json:"email_verified_at,omitempty"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	// Roles always includes RoleMember. Permissions is the union of the
+	// permissions of all roles.
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
 }
@@ -0,0 +1,19 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links an account at an external OpenID provider, identified
+// by the provider's subject, to a user.
+type UserIdentity struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"-"`
+	Email       string     `json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
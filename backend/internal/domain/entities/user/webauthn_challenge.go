@@ -0,0 +1,33 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CeremonyRegistration   = "registration"
+	CeremonyAuthentication = "authentication"
+)
+
+// WebAuthnChallenge tracks a challenge handed to the browser between the
+// begin and finish steps of a passkey ceremony. UserID is nil for
+// authentication, where the user is only known once the assertion arrives.
+type WebAuthnChallenge struct {
+	ID            uuid.UUID  `json:"id"`
+	UserID        *uuid.UUID `json:"user_id,omitempty"`
+	ChallengeHash string     `json:"-"`
+	Ceremony      string     `json:"ceremony"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	UsedAt        *time.Time `json:"used_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (c *WebAuthnChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+func (c *WebAuthnChallenge) IsUsed() bool {
+	return c.UsedAt != nil
+}
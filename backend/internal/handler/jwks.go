@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	jwtservice "roadmap/internal/pkg/jwt"
+)
+
+func JWKSHandler(jwtService *jwtservice.JWTService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, jwtService.JWKS())
+	}
+}
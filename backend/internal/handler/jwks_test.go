@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	jwtservice "roadmap/internal/pkg/jwt"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	key, err := jwtservice.GenerateEd25519Key("test-key")
+	require.NoError(t, err)
+	keySet, err := jwtservice.NewKeySet([]*jwtservice.Key{key}, "")
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/.well-known/jwks.json", JWKSHandler(jwtservice.NewJWTServiceWithKeySet(keySet, time.Hour)))
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Cache-Control"), "max-age")
+
+	var response jwtservice.JWKS
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	require.Len(t, response.Keys, 1)
+	assert.Equal(t, "test-key", response.Keys[0].KeyID)
+	assert.Equal(t, "EdDSA", response.Keys[0].Algorithm)
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"log"
 	"net/http"
 	"strings"
 
@@ -13,11 +15,120 @@ const (
 	UserIDKey   = "user_id"
 	UsernameKey = "username"
 	EmailKey    = "email"
+	ClaimsKey   = "claims"
+	AuthTypeKey = "auth_type"
+	ScopesKey   = "scopes"
 )
 
-func AuthMiddleware(jwtService *jwtservice.JWTService) gin.HandlerFunc {
+const (
+	AuthTypeJWT         = "jwt"
+	AuthTypeAccessToken = "access_token"
+)
+
+// Personal access token scopes. A read token may only make safe (GET, HEAD,
+// OPTIONS) requests; a write token may make any request.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+type TokenRevocationChecker interface {
+	IsRevoked(ctx context.Context, claims *jwtservice.Claims) (bool, error)
+}
+
+// SessionChecker reports whether the session a token was issued for has been
+// revoked.
+type SessionChecker interface {
+	IsSessionRevoked(ctx context.Context, sessionID string) (bool, error)
+}
+
+type SuspensionChecker interface {
+	IsSuspended(ctx context.Context, userID string) (bool, error)
+}
+
+type AccessTokenAuthenticator interface {
+	// AuthenticateAccessToken returns the claims of the token owner and the
+	// scopes granted to the token. It returns nil claims and no error if the
+	// token is unknown or expired.
+	AuthenticateAccessToken(ctx context.Context, token string) (*jwtservice.Claims, []string, error)
+}
+
+type authOptions struct {
+	revocationChecker  TokenRevocationChecker
+	sessionChecker     SessionChecker
+	suspensionChecker  SuspensionChecker
+	requireVerified    bool
+	unverifiedAllowed  map[string]bool
+	accessTokens       AccessTokenAuthenticator
+	accessTokensDenied []string
+	rateLimiter        *RateLimiter
+	cookieAuth         *CookieAuth
+}
+
+type AuthOption func(*authOptions)
+
+func WithRevocationChecker(checker TokenRevocationChecker) AuthOption {
+	return func(o *authOptions) {
+		o.revocationChecker = checker
+	}
+}
+
+// WithSessionChecker rejects tokens whose session has been revoked. Tokens
+// without a session are not affected.
+func WithSessionChecker(checker SessionChecker) AuthOption {
+	return func(o *authOptions) {
+		o.sessionChecker = checker
+	}
+}
+
+// WithSuspensionChecker rejects tokens of suspended users, including tokens
+// issued before the suspension.
+func WithSuspensionChecker(checker SuspensionChecker) AuthOption {
+	return func(o *authOptions) {
+		o.suspensionChecker = checker
+	}
+}
+
+// WithEmailVerificationRequired rejects tokens of users with an unverified
+// email address, except on the listed routes. Routes are matched against the
+// full route pattern as registered with gin, e.g. "/api/v1/users/profile".
+func WithEmailVerificationRequired(allowedRoutes ...string) AuthOption {
+	return func(o *authOptions) {
+		o.requireVerified = true
+		o.unverifiedAllowed = make(map[string]bool, len(allowedRoutes))
+		for _, route := range allowedRoutes {
+			o.unverifiedAllowed[route] = true
+		}
+	}
+}
+
+// WithAccessTokens accepts personal access tokens alongside JWTs. Bearer
+// tokens without a dot, which a JWT always has, are handed to authenticator.
+// Access tokens are refused on the listed routes and everything below them,
+// so that a leaked token cannot be used to manage credentials.
+func WithAccessTokens(authenticator AccessTokenAuthenticator, deniedRoutes ...string) AuthOption {
+	return func(o *authOptions) {
+		o.accessTokens = authenticator
+		o.accessTokensDenied = deniedRoutes
+	}
+}
+
+func AuthMiddleware(jwtService *jwtservice.JWTService, opts ...AuthOption) gin.HandlerFunc {
+	var options authOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" && options.cookieAuth != nil {
+			// Personal access tokens are never kept in cookies, so the
+			// cookie always holds a JWT.
+			if token, ok := options.cookieAuth.accessToken(c); ok {
+				authenticateJWT(c, jwtService, &options, token)
+				return
+			}
+		}
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": "Authorization header is required",
@@ -37,31 +148,212 @@ func AuthMiddleware(jwtService *jwtservice.JWTService) gin.HandlerFunc {
 
 		token := parts[1]
 
-		claims, err := jwtService.ValidateToken(token)
-		if err != nil {
-			statusCode := http.StatusUnauthorized
-			errorMessage := "Invalid or expired token"
-
-			switch err {
-			case jwtservice.ErrExpiredToken:
-				errorMessage = "Token has expired"
-			case jwtservice.ErrInvalidToken:
-				errorMessage = "Invalid token"
-			}
+		if options.accessTokens != nil && !strings.Contains(token, ".") {
+			authenticateAccessToken(c, &options, token)
+			return
+		}
+
+		authenticateJWT(c, jwtService, &options, token)
+	}
+}
+
+func authenticateJWT(c *gin.Context, jwtService *jwtservice.JWTService, options *authOptions, token string) {
+	claims, err := jwtService.ValidateToken(token)
+	if err != nil {
+		statusCode := http.StatusUnauthorized
+		errorMessage := "Invalid or expired token"
+
+		switch err {
+		case jwtservice.ErrExpiredToken:
+			errorMessage = "Token has expired"
+		case jwtservice.ErrInvalidToken:
+			errorMessage = "Invalid token"
+		}
 
-			c.JSON(statusCode, gin.H{
-				"error": errorMessage,
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		c.Abort()
+		return
+	}
+
+	if options.revocationChecker != nil {
+		revoked, err := options.revocationChecker.IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			log.Printf("Failed to check token revocation: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to verify token",
 			})
 			c.Abort()
 			return
 		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token has been revoked",
+			})
+			c.Abort()
+			return
+		}
+	}
 
-		c.Set(UserIDKey, claims.UserID)
-		c.Set(UsernameKey, claims.Username)
-		c.Set(EmailKey, claims.Email)
+	if !checkSession(c, options, claims) {
+		return
+	}
 
-		c.Next()
+	if !checkSuspended(c, options, claims) || !checkEmailVerified(c, options, claims) {
+		return
 	}
+
+	setAuthenticatedUser(c, claims)
+	c.Set(AuthTypeKey, AuthTypeJWT)
+
+	if !checkRateLimit(c, options) {
+		return
+	}
+
+	c.Next()
+}
+
+func authenticateAccessToken(c *gin.Context, options *authOptions, token string) {
+	claims, scopes, err := options.accessTokens.AuthenticateAccessToken(c.Request.Context(), token)
+	if err != nil {
+		log.Printf("Failed to authenticate access token: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to verify token",
+		})
+		c.Abort()
+		return
+	}
+	if claims == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid token",
+		})
+		c.Abort()
+		return
+	}
+
+	if accessTokenDenied(options.accessTokensDenied, c.FullPath()) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Personal access tokens cannot be used for this endpoint",
+		})
+		c.Abort()
+		return
+	}
+
+	if !scopeAllows(scopes, c.Request.Method) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Token scope does not allow this request",
+		})
+		c.Abort()
+		return
+	}
+
+	if !checkSuspended(c, options, claims) || !checkEmailVerified(c, options, claims) {
+		return
+	}
+
+	setAuthenticatedUser(c, claims)
+	c.Set(AuthTypeKey, AuthTypeAccessToken)
+	c.Set(ScopesKey, scopes)
+
+	if !checkRateLimit(c, options) {
+		return
+	}
+
+	c.Next()
+}
+
+func checkSession(c *gin.Context, options *authOptions, claims *jwtservice.Claims) bool {
+	if options.sessionChecker == nil || claims.SessionID == "" {
+		return true
+	}
+
+	revoked, err := options.sessionChecker.IsSessionRevoked(c.Request.Context(), claims.SessionID)
+	if err != nil {
+		log.Printf("Failed to check session revocation: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to verify token",
+		})
+		c.Abort()
+		return false
+	}
+	if revoked {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Session has been revoked",
+		})
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+func checkSuspended(c *gin.Context, options *authOptions, claims *jwtservice.Claims) bool {
+	if options.suspensionChecker == nil {
+		return true
+	}
+
+	suspended, err := options.suspensionChecker.IsSuspended(c.Request.Context(), claims.UserID)
+	if err != nil {
+		log.Printf("Failed to check account suspension: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to verify token",
+		})
+		c.Abort()
+		return false
+	}
+	if suspended {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Account is suspended",
+		})
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+func checkEmailVerified(c *gin.Context, options *authOptions, claims *jwtservice.Claims) bool {
+	if options.requireVerified && !claims.EmailVerified && !options.unverifiedAllowed[c.FullPath()] {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Email address is not verified",
+		})
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+func checkRateLimit(c *gin.Context, options *authOptions) bool {
+	if options.rateLimiter == nil {
+		return true
+	}
+	return options.rateLimiter.limit(c, options.rateLimiter.authenticated)
+}
+
+func setAuthenticatedUser(c *gin.Context, claims *jwtservice.Claims) {
+	c.Set(UserIDKey, claims.UserID)
+	c.Set(UsernameKey, claims.Username)
+	c.Set(EmailKey, claims.Email)
+	c.Set(ClaimsKey, claims)
+}
+
+func accessTokenDenied(deniedRoutes []string, route string) bool {
+	for _, denied := range deniedRoutes {
+		if route == denied || strings.HasPrefix(route, denied+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func scopeAllows(scopes []string, method string) bool {
+	safe := isSafeMethod(method)
+
+	for _, scope := range scopes {
+		if scope == ScopeWrite || (scope == ScopeRead && safe) {
+			return true
+		}
+	}
+	return false
 }
 
 func GetUserID(c *gin.Context) (string, bool) {
@@ -87,3 +379,21 @@ func GetEmail(c *gin.Context) (string, bool) {
 	}
 	return email.(string), true
 }
+
+func GetClaims(c *gin.Context) (*jwtservice.Claims, bool) {
+	claims, exists := c.Get(ClaimsKey)
+	if !exists {
+		return nil, false
+	}
+	return claims.(*jwtservice.Claims), true
+}
+
+// GetAuthType reports whether the request was authenticated with a JWT
+// (AuthTypeJWT) or a personal access token (AuthTypeAccessToken).
+func GetAuthType(c *gin.Context) (string, bool) {
+	authType, exists := c.Get(AuthTypeKey)
+	if !exists {
+		return "", false
+	}
+	return authType.(string), true
+}
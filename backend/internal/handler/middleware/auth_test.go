@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -211,3 +213,298 @@ func TestAuthMiddleware_WrongSecretKey(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+type stubRevocationChecker struct {
+	revoked bool
+	err     error
+}
+
+func (s *stubRevocationChecker) IsRevoked(_ context.Context, _ *jwtservice.Claims) (bool, error) {
+	return s.revoked, s.err
+}
+
+func TestAuthMiddleware_RevocationChecker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := jwtservice.NewJWTService("test-secret-key", 24*3600*1000000000)
+	token, _ := jwtService.GenerateToken("user1", "user1", "user1@example.com")
+
+	testCases := []struct {
+		name         string
+		checker      *stubRevocationChecker
+		expectedCode int
+	}{
+		{"not revoked", &stubRevocationChecker{}, http.StatusOK},
+		{"revoked", &stubRevocationChecker{revoked: true}, http.StatusUnauthorized},
+		{"checker error", &stubRevocationChecker{err: errors.New("database error")}, http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(AuthMiddleware(jwtService, WithRevocationChecker(tc.checker)))
+			router.GET("/test", func(c *gin.Context) {
+				claims, exists := GetClaims(c)
+				assert.True(t, exists)
+				assert.NotEmpty(t, claims.ID)
+				c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+}
+
+type stubSuspensionChecker struct {
+	suspended bool
+	err       error
+}
+
+func (s *stubSuspensionChecker) IsSuspended(_ context.Context, _ string) (bool, error) {
+	return s.suspended, s.err
+}
+
+func TestAuthMiddleware_SuspensionChecker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := jwtservice.NewJWTService("test-secret-key", 24*3600*1000000000)
+	token, _ := jwtService.GenerateToken("user1", "user1", "user1@example.com")
+
+	testCases := []struct {
+		name         string
+		checker      *stubSuspensionChecker
+		expectedCode int
+	}{
+		{"active", &stubSuspensionChecker{}, http.StatusOK},
+		{"suspended", &stubSuspensionChecker{suspended: true}, http.StatusForbidden},
+		{"checker error", &stubSuspensionChecker{err: errors.New("database error")}, http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(AuthMiddleware(jwtService, WithSuspensionChecker(tc.checker)))
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+}
+
+type stubSessionChecker struct {
+	revoked bool
+	err     error
+	checked []string
+}
+
+func (s *stubSessionChecker) IsSessionRevoked(_ context.Context, sessionID string) (bool, error) {
+	s.checked = append(s.checked, sessionID)
+	return s.revoked, s.err
+}
+
+func TestAuthMiddleware_SessionChecker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := jwtservice.NewJWTService("test-secret-key", 24*3600*1000000000)
+	token, _ := jwtService.GenerateTokenWithClaims(jwtservice.Claims{
+		UserID:    "user1",
+		Username:  "user1",
+		Email:     "user1@example.com",
+		SessionID: "session1",
+	})
+	sessionless, _ := jwtService.GenerateToken("user1", "user1", "user1@example.com")
+
+	testCases := []struct {
+		name         string
+		token        string
+		checker      *stubSessionChecker
+		expectedCode int
+		checked      []string
+	}{
+		{"active", token, &stubSessionChecker{}, http.StatusOK, []string{"session1"}},
+		{"revoked", token, &stubSessionChecker{revoked: true}, http.StatusUnauthorized, []string{"session1"}},
+		{"checker error", token, &stubSessionChecker{err: errors.New("database error")}, http.StatusInternalServerError, []string{"session1"}},
+		{"token without session", sessionless, &stubSessionChecker{revoked: true}, http.StatusOK, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(AuthMiddleware(jwtService, WithSessionChecker(tc.checker)))
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+			assert.Equal(t, tc.checked, tc.checker.checked)
+		})
+	}
+}
+
+func TestAuthMiddleware_EmailVerificationRequired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := jwtservice.NewJWTService("test-secret-key", 24*3600*1000000000)
+	unverifiedToken, _ := jwtService.GenerateToken("user1", "user1", "user1@example.com")
+	verifiedToken, _ := jwtService.GenerateTokenWithClaims(jwtservice.Claims{
+		UserID:        "user2",
+		Username:      "user2",
+		Email:         "user2@example.com",
+		EmailVerified: true,
+	})
+
+	router := gin.New()
+	router.Use(AuthMiddleware(jwtService, WithEmailVerificationRequired("/allowed")))
+	for _, path := range []string{"/allowed", "/restricted"} {
+		router.GET(path, func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		})
+	}
+
+	testCases := []struct {
+		name         string
+		token        string
+		path         string
+		expectedCode int
+	}{
+		{"unverified on allowed route", unverifiedToken, "/allowed", http.StatusOK},
+		{"unverified on restricted route", unverifiedToken, "/restricted", http.StatusForbidden},
+		{"verified on restricted route", verifiedToken, "/restricted", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+}
+
+func TestGetClaims(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	claims, exists := GetClaims(c)
+	assert.False(t, exists)
+	assert.Nil(t, claims)
+
+	c.Set(ClaimsKey, &jwtservice.Claims{UserID: "test-user-id"})
+	claims, exists = GetClaims(c)
+	assert.True(t, exists)
+	assert.Equal(t, "test-user-id", claims.UserID)
+}
+
+type stubAccessTokenAuthenticator struct {
+	tokens map[string][]string
+	err    error
+}
+
+func (s *stubAccessTokenAuthenticator) AuthenticateAccessToken(
+	_ context.Context,
+	token string,
+) (*jwtservice.Claims, []string, error) {
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	scopes, ok := s.tokens[token]
+	if !ok {
+		return nil, nil, nil
+	}
+	return &jwtservice.Claims{UserID: "pat-user", EmailVerified: true}, scopes, nil
+}
+
+func TestAuthMiddleware_AccessTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := jwtservice.NewJWTService("test-secret-key", 24*3600*1000000000)
+	jwtToken, _ := jwtService.GenerateToken("user1", "user1", "user1@example.com")
+	authenticator := &stubAccessTokenAuthenticator{tokens: map[string][]string{
+		"rmp_read":  {ScopeRead},
+		"rmp_write": {ScopeWrite},
+	}}
+
+	router := gin.New()
+	router.Use(AuthMiddleware(jwtService, WithAccessTokens(authenticator, "/tokens")))
+	handler := func(c *gin.Context) {
+		userID, _ := GetUserID(c)
+		authType, _ := GetAuthType(c)
+		c.JSON(http.StatusOK, gin.H{"user_id": userID, "auth_type": authType})
+	}
+	router.GET("/resource", handler)
+	router.POST("/resource", handler)
+	router.GET("/tokens", handler)
+	router.DELETE("/tokens/:id", handler)
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		token        string
+		expectedCode int
+	}{
+		{"read token on GET", http.MethodGet, "/resource", "rmp_read", http.StatusOK},
+		{"read token on POST", http.MethodPost, "/resource", "rmp_read", http.StatusForbidden},
+		{"write token on POST", http.MethodPost, "/resource", "rmp_write", http.StatusOK},
+		{"unknown token", http.MethodGet, "/resource", "rmp_unknown", http.StatusUnauthorized},
+		{"denied route", http.MethodGet, "/tokens", "rmp_write", http.StatusForbidden},
+		{"below denied route", http.MethodDelete, "/tokens/1", "rmp_write", http.StatusForbidden},
+		{"jwt on denied route", http.MethodGet, "/tokens", jwtToken, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	req.Header.Set("Authorization", "Bearer rmp_read")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.JSONEq(t, `{"user_id":"pat-user","auth_type":"access_token"}`, w.Body.String())
+}
+
+func TestAuthMiddleware_AccessTokenAuthenticatorError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := jwtservice.NewJWTService("test-secret-key", 24*3600*1000000000)
+	authenticator := &stubAccessTokenAuthenticator{err: errors.New("database error")}
+
+	router := gin.New()
+	router.Use(AuthMiddleware(jwtService, WithAccessTokens(authenticator)))
+	router.GET("/resource", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	req.Header.Set("Authorization", "Bearer rmp_token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
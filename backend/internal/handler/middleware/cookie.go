@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cookies set in cookie authentication mode. The CSRF cookie is readable by
+// scripts so the frontend can echo it in CSRFHeader; the others are HttpOnly.
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+	CSRFCookie         = "csrf_token"
+	CSRFHeader         = "X-CSRF-Token"
+)
+
+const cookieAuthKey = "cookie_auth"
+
+// csrfTokenBytes is the amount of randomness in a CSRF token.
+const csrfTokenBytes = 32
+
+type CookieAuthConfig struct {
+	// Domain may be empty to scope the cookies to the API host.
+	Domain string
+	// RefreshTokenPath limits the refresh token cookie to the routes that
+	// need it, such as token refresh and logout.
+	RefreshTokenPath string
+	Secure           bool
+	SameSite         http.SameSite
+}
+
+// CookieAuth lets browsers authenticate with HttpOnly cookies instead of
+// keeping tokens where scripts can read them. Handlers that issue tokens hand
+// them to SetTokens, AuthMiddleware reads the access token cookie when the
+// request has no Authorization header (see WithCookieAuth), and Middleware
+// protects cookie-authenticated requests against CSRF with the
+// double-submit pattern.
+type CookieAuth struct {
+	config CookieAuthConfig
+}
+
+func NewCookieAuth(config CookieAuthConfig) *CookieAuth {
+	return &CookieAuth{
+		config: config,
+	}
+}
+
+// WithCookieAuth makes AuthMiddleware fall back to the access token cookie
+// when a request has no Authorization header.
+func WithCookieAuth(cookieAuth *CookieAuth) AuthOption {
+	return func(o *authOptions) {
+		o.cookieAuth = cookieAuth
+	}
+}
+
+// Middleware makes the CookieAuth available to handlers through
+// GetCookieAuth and rejects state-changing requests that carry an auth cookie
+// but no matching CSRF header. Requests with an Authorization header are not
+// affected since browsers never add that header on their own.
+func (a *CookieAuth) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(cookieAuthKey, a)
+
+		if isSafeMethod(c.Request.Method) || c.GetHeader("Authorization") != "" || !hasAuthCookie(c) {
+			c.Next()
+			return
+		}
+
+		csrfToken, err := c.Cookie(CSRFCookie)
+		header := c.GetHeader(CSRFHeader)
+		if err != nil || csrfToken == "" || subtle.ConstantTimeCompare([]byte(csrfToken), []byte(header)) != 1 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Invalid or missing CSRF token",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// SetTokens stores the tokens of a sign-in or refresh in cookies that expire
+// with them. A CSRF token is issued along with them unless the request
+// already carries one.
+func (a *CookieAuth) SetTokens(
+	c *gin.Context,
+	accessToken string,
+	accessTokenExpiresAt time.Time,
+	refreshToken string,
+	refreshTokenExpiresAt time.Time,
+) error {
+	csrfToken, err := c.Cookie(CSRFCookie)
+	if err != nil || csrfToken == "" {
+		csrfToken, err = generateCSRFToken()
+		if err != nil {
+			return err
+		}
+	}
+
+	a.setCookie(c, AccessTokenCookie, accessToken, "/", accessTokenExpiresAt, true)
+	a.setCookie(c, RefreshTokenCookie, refreshToken, a.refreshTokenPath(), refreshTokenExpiresAt, true)
+	a.setCookie(c, CSRFCookie, csrfToken, "/", refreshTokenExpiresAt, false)
+	return nil
+}
+
+// Clear removes every auth cookie, e.g. on logout.
+func (a *CookieAuth) Clear(c *gin.Context) {
+	expired := time.Unix(0, 0)
+	a.setCookie(c, AccessTokenCookie, "", "/", expired, true)
+	a.setCookie(c, RefreshTokenCookie, "", a.refreshTokenPath(), expired, true)
+	a.setCookie(c, CSRFCookie, "", "/", expired, false)
+}
+
+// RefreshToken returns the refresh token cookie of the request, if any.
+func (a *CookieAuth) RefreshToken(c *gin.Context) (string, bool) {
+	refreshToken, err := c.Cookie(RefreshTokenCookie)
+	if err != nil || refreshToken == "" {
+		return "", false
+	}
+	return refreshToken, true
+}
+
+func (a *CookieAuth) accessToken(c *gin.Context) (string, bool) {
+	accessToken, err := c.Cookie(AccessTokenCookie)
+	if err != nil || accessToken == "" {
+		return "", false
+	}
+	return accessToken, true
+}
+
+func (a *CookieAuth) refreshTokenPath() string {
+	if a.config.RefreshTokenPath == "" {
+		return "/"
+	}
+	return a.config.RefreshTokenPath
+}
+
+func (a *CookieAuth) setCookie(c *gin.Context, name, value, path string, expiresAt time.Time, httpOnly bool) {
+	maxAge := int(time.Until(expiresAt).Seconds())
+	if maxAge <= 0 {
+		maxAge = -1
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   a.config.Domain,
+		Expires:  expiresAt,
+		MaxAge:   maxAge,
+		Secure:   a.config.Secure,
+		HttpOnly: httpOnly,
+		SameSite: a.config.SameSite,
+	})
+}
+
+// GetCookieAuth returns the CookieAuth of the request when cookie
+// authentication is enabled.
+func GetCookieAuth(c *gin.Context) (*CookieAuth, bool) {
+	cookieAuth, exists := c.Get(cookieAuthKey)
+	if !exists {
+		return nil, false
+	}
+	return cookieAuth.(*CookieAuth), true
+}
+
+func hasAuthCookie(c *gin.Context) bool {
+	for _, name := range []string{AccessTokenCookie, RefreshTokenCookie} {
+		if value, err := c.Cookie(name); err == nil && value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func generateCSRFToken() (string, error) {
+	b := make([]byte, csrfTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate csrf token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
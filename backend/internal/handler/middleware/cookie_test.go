@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	jwtservice "roadmap/internal/pkg/jwt"
+)
+
+func newTestCookieAuth() *CookieAuth {
+	return NewCookieAuth(CookieAuthConfig{
+		RefreshTokenPath: "/api/v1/users",
+		Secure:           true,
+		SameSite:         http.SameSiteLaxMode,
+	})
+}
+
+func TestCookieAuth_CSRF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name          string
+		method        string
+		cookies       map[string]string
+		csrfHeader    string
+		authorization string
+		expectedCode  int
+	}{
+		{"safe method", http.MethodGet, map[string]string{AccessTokenCookie: "token"}, "", "", http.StatusOK},
+		{"no auth cookie", http.MethodPost, nil, "", "", http.StatusOK},
+		{
+			"matching token", http.MethodPost,
+			map[string]string{AccessTokenCookie: "token", CSRFCookie: "csrf"}, "csrf", "", http.StatusOK,
+		},
+		{
+			"missing header", http.MethodPost,
+			map[string]string{AccessTokenCookie: "token", CSRFCookie: "csrf"}, "", "", http.StatusForbidden,
+		},
+		{
+			"mismatched header", http.MethodDelete,
+			map[string]string{AccessTokenCookie: "token", CSRFCookie: "csrf"}, "other", "", http.StatusForbidden,
+		},
+		{
+			"missing csrf cookie", http.MethodPost,
+			map[string]string{RefreshTokenCookie: "token"}, "csrf", "", http.StatusForbidden,
+		},
+		{
+			"authorization header", http.MethodPost,
+			map[string]string{AccessTokenCookie: "token"}, "", "Bearer token", http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(newTestCookieAuth().Middleware())
+			router.Handle(tc.method, "/test", func(c *gin.Context) {
+				_, ok := GetCookieAuth(c)
+				assert.True(t, ok)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tc.method, "/test", nil)
+			for name, value := range tc.cookies {
+				req.AddCookie(&http.Cookie{Name: name, Value: value})
+			}
+			if tc.csrfHeader != "" {
+				req.Header.Set(CSRFHeader, tc.csrfHeader)
+			}
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+}
+
+func TestCookieAuth_SetTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cookieAuth := newTestCookieAuth()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/users/login", nil)
+
+	now := time.Now()
+	err := cookieAuth.SetTokens(c, "access", now.Add(15*time.Minute), "refresh", now.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	cookies := make(map[string]*http.Cookie)
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+
+	access := cookies[AccessTokenCookie]
+	require.NotNil(t, access)
+	assert.Equal(t, "access", access.Value)
+	assert.Equal(t, "/", access.Path)
+	assert.True(t, access.HttpOnly)
+	assert.True(t, access.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, access.SameSite)
+
+	refresh := cookies[RefreshTokenCookie]
+	require.NotNil(t, refresh)
+	assert.Equal(t, "refresh", refresh.Value)
+	assert.Equal(t, "/api/v1/users", refresh.Path)
+	assert.True(t, refresh.HttpOnly)
+
+	csrf := cookies[CSRFCookie]
+	require.NotNil(t, csrf)
+	assert.NotEmpty(t, csrf.Value)
+	assert.False(t, csrf.HttpOnly)
+}
+
+func TestCookieAuth_SetTokensKeepsCSRFToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/users/refresh", nil)
+	c.Request.AddCookie(&http.Cookie{Name: CSRFCookie, Value: "existing"})
+
+	now := time.Now()
+	require.NoError(t, newTestCookieAuth().SetTokens(c, "access", now.Add(time.Minute), "refresh", now.Add(time.Hour)))
+
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == CSRFCookie {
+			assert.Equal(t, "existing", cookie.Value)
+			return
+		}
+	}
+	t.Fatal("csrf cookie not set")
+}
+
+func TestCookieAuth_Clear(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/users/logout", nil)
+
+	newTestCookieAuth().Clear(c)
+
+	cookies := w.Result().Cookies()
+	assert.Len(t, cookies, 3)
+	for _, cookie := range cookies {
+		assert.Empty(t, cookie.Value)
+		assert.Negative(t, cookie.MaxAge)
+	}
+}
+
+func TestAuthMiddleware_CookieAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := jwtservice.NewJWTService("test-secret-key", time.Hour)
+	token, _ := jwtService.GenerateToken("user1", "user1", "user1@example.com")
+
+	testCases := []struct {
+		name          string
+		cookie        string
+		authorization string
+		cookieMode    bool
+		expectedCode  int
+	}{
+		{"cookie", token, "", true, http.StatusOK},
+		{"invalid cookie", "invalid", "", true, http.StatusUnauthorized},
+		{"cookie mode disabled", token, "", false, http.StatusUnauthorized},
+		{"header takes precedence", token, "Bearer invalid", true, http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []AuthOption
+			if tc.cookieMode {
+				opts = append(opts, WithCookieAuth(newTestCookieAuth()))
+			}
+
+			router := gin.New()
+			router.Use(AuthMiddleware(jwtService, opts...))
+			router.GET("/test", func(c *gin.Context) {
+				userID, _ := GetUserID(c)
+				assert.Equal(t, "user1", userID)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: tc.cookie})
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+}
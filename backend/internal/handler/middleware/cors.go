@@ -5,10 +5,17 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func CORSMiddleware() gin.HandlerFunc {
+// CORSMiddleware allows cross-origin requests, with credentials, from the
+// listed origins only. Credentials may be cookies in cookie authentication
+// mode, so allowing every origin would let any site act for the user.
+func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
 	config := cors.DefaultConfig()
 
-	config.AllowAllOrigins = true
+	if len(allowedOrigins) > 0 {
+		config.AllowOrigins = allowedOrigins
+	} else {
+		config.AllowOriginFunc = func(string) bool { return false }
+	}
 
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
 
@@ -19,12 +26,18 @@ func CORSMiddleware() gin.HandlerFunc {
 		"Authorization",
 		"Accept",
 		"X-Requested-With",
+		CSRFHeader,
 	}
 
 	config.ExposeHeaders = []string{
 		"Content-Length",
 		"Content-Type",
 		"Authorization",
+		"Retry-After",
+		"RateLimit-Limit",
+		"RateLimit-Remaining",
+		"RateLimit-Reset",
+		"RateLimit-Policy",
 	}
 
 	config.AllowCredentials = true
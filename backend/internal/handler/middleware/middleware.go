@@ -4,10 +4,10 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupMiddleware(router *gin.Engine) {
+func SetupMiddleware(router *gin.Engine, corsOrigins []string) {
 	router.Use(RecoveryMiddleware())
 
-	router.Use(CORSMiddleware())
+	router.Use(CORSMiddleware(corsOrigins))
 
 	router.Use(LoggingMiddleware())
 }
@@ -13,7 +13,7 @@ func TestSetupMiddleware(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	router := gin.New()
-	SetupMiddleware(router)
+	SetupMiddleware(router, []string{"http://localhost:3000"})
 
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "test"})
@@ -31,7 +31,7 @@ func TestSetupMiddleware_WithPanic(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	router := gin.New()
-	SetupMiddleware(router)
+	SetupMiddleware(router, []string{"http://localhost:3000"})
 
 	router.GET("/test", func(c *gin.Context) {
 		panic("test panic")
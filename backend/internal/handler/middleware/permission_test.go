@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	jwtservice "roadmap/internal/pkg/jwt"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := jwtservice.NewJWTService("test-secret-key", 15*time.Minute)
+
+	router := gin.New()
+	router.POST("/admin", AuthMiddleware(jwtService), RequirePermission("users:create"), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	admin, err := jwtService.GenerateTokenWithClaims(jwtservice.Claims{
+		UserID:      "user-1",
+		Roles:       []string{"admin", "member"},
+		Permissions: []string{"roles:manage", "users:create"},
+	})
+	require.NoError(t, err)
+
+	member, err := jwtService.GenerateTokenWithClaims(jwtservice.Claims{
+		UserID:      "user-2",
+		Roles:       []string{"member"},
+		Permissions: []string{"roadmaps:create"},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		token    string
+		expected int
+	}{
+		{"granted", admin, http.StatusOK},
+		{"missing permission", member, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/admin", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+		})
+	}
+}
+
+func TestRequirePermission_WithoutAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/admin", RequirePermission("users:create"), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"roadmap/internal/pkg/ratelimit"
+)
+
+// What a rate limit policy counts requests by.
+const (
+	RateLimitByIP     = "ip"
+	RateLimitByUser   = "user"
+	RateLimitByAPIKey = "api_key"
+)
+
+const rateLimitResultKey = "rate_limit_result"
+
+// RateLimitPolicy gives every client, as identified by KeyBy, its own bucket
+// on the policy's routes. Routes are gin route paths, optionally preceded by
+// a method ("POST /api/v1/users/login"); a path also covers the routes below
+// it. A policy without routes covers every request.
+type RateLimitPolicy struct {
+	Name   string
+	Routes []string
+	KeyBy  string
+	Limit  ratelimit.Limit
+}
+
+func (p *RateLimitPolicy) matches(method, route string) bool {
+	if len(p.Routes) == 0 {
+		return true
+	}
+
+	for _, pattern := range p.Routes {
+		if patternMethod, path, ok := strings.Cut(pattern, " "); ok {
+			if patternMethod != method {
+				continue
+			}
+			pattern = path
+		}
+		if route == pattern || strings.HasPrefix(route, pattern+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimiter enforces rate limit policies. Policies keyed by IP or API key
+// are enforced by Middleware, before routing to handlers; policies keyed by
+// user need an authenticated request and are enforced by AuthMiddleware
+// through WithRateLimiter. Requests a policy has no key for, such as
+// requests without an API key, are not limited by it.
+//
+// Failures of the store are logged and the request is let through.
+type RateLimiter struct {
+	store         ratelimit.Store
+	anonymous     []RateLimitPolicy
+	authenticated []RateLimitPolicy
+}
+
+func NewRateLimiter(store ratelimit.Store, policies ...RateLimitPolicy) *RateLimiter {
+	limiter := &RateLimiter{store: store}
+	for _, policy := range policies {
+		if policy.KeyBy == RateLimitByUser {
+			limiter.authenticated = append(limiter.authenticated, policy)
+		} else {
+			limiter.anonymous = append(limiter.anonymous, policy)
+		}
+	}
+	return limiter
+}
+
+// Middleware must be installed on the router before routes are registered.
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.limit(c, l.anonymous) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// WithRateLimiter makes AuthMiddleware enforce the limiter's user-keyed
+// policies once the user is known.
+func WithRateLimiter(limiter *RateLimiter) AuthOption {
+	return func(o *authOptions) {
+		o.rateLimiter = limiter
+	}
+}
+
+// limit counts the request against every matching policy and aborts it with
+// 429 if one of them is exhausted.
+func (l *RateLimiter) limit(c *gin.Context, policies []RateLimitPolicy) bool {
+	now := time.Now().UTC()
+	route := c.FullPath()
+
+	for i := range policies {
+		policy := &policies[i]
+		if !policy.matches(c.Request.Method, route) {
+			continue
+		}
+
+		key, ok := rateLimitKey(c, policy.KeyBy)
+		if !ok {
+			continue
+		}
+
+		result, err := l.store.Take(c.Request.Context(), policy.Name+":"+key, policy.Limit, now)
+		if err != nil {
+			log.Printf("Failed to apply rate limit %s: %v", policy.Name, err)
+			continue
+		}
+
+		setRateLimitHeaders(c, policy, result)
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": "Too many requests, please try again later",
+			})
+			c.Abort()
+			return false
+		}
+	}
+
+	return true
+}
+
+func rateLimitKey(c *gin.Context, keyBy string) (string, bool) {
+	switch keyBy {
+	case RateLimitByIP:
+		return "ip:" + c.ClientIP(), true
+	case RateLimitByUser:
+		userID, ok := GetUserID(c)
+		if !ok {
+			return "", false
+		}
+		return "user:" + userID, true
+	case RateLimitByAPIKey:
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		// Personal access tokens are the bearer tokens that are not JWTs.
+		if !ok || token == "" || strings.Contains(token, ".") {
+			return "", false
+		}
+		// Keys may be persisted, so the token itself is not used.
+		sum := sha256.Sum256([]byte(token))
+		return "api_key:" + hex.EncodeToString(sum[:16]), true
+	}
+	return "", false
+}
+
+// setRateLimitHeaders reports the policy closest to being exhausted among
+// those the request has been counted against.
+func setRateLimitHeaders(c *gin.Context, policy *RateLimitPolicy, result ratelimit.Result) {
+	if previous, exists := c.Get(rateLimitResultKey); exists &&
+		previous.(ratelimit.Result).Remaining < result.Remaining && result.Allowed {
+		return
+	}
+	c.Set(rateLimitResultKey, result)
+
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+	c.Header("RateLimit-Policy", strconv.Itoa(policy.Limit.Capacity())+";w="+
+		strconv.Itoa(ceilSeconds(policy.Limit.Period)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return max(int(math.Ceil(d.Seconds())), 0)
+}
@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	jwtservice "roadmap/internal/pkg/jwt"
+	"roadmap/internal/pkg/ratelimit"
+)
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(context.Context, string, ratelimit.Limit, time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("database error")
+}
+
+func sendRequest(router *gin.Engine, method, path, remoteAddr, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = remoteAddr
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiter_ByIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), RateLimitPolicy{
+		Name:   "login",
+		Routes: []string{"POST /login"},
+		KeyBy:  RateLimitByIP,
+		Limit:  ratelimit.Limit{Requests: 2, Period: time.Minute},
+	})
+
+	router := gin.New()
+	router.Use(limiter.Middleware())
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+	router.POST("/login", handler)
+	router.GET("/login", handler)
+
+	w := sendRequest(router, http.MethodPost, "/login", "192.0.2.1:1234", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+	assert.Equal(t, http.StatusOK, sendRequest(router, http.MethodPost, "/login", "192.0.2.1:1234", "").Code)
+
+	w = sendRequest(router, http.MethodPost, "/login", "192.0.2.1:1234", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	assert.Equal(t, http.StatusOK, sendRequest(router, http.MethodPost, "/login", "192.0.2.2:1234", "").Code,
+		"other clients have their own bucket")
+
+	w = sendRequest(router, http.MethodGet, "/login", "192.0.2.1:1234", "")
+	assert.Equal(t, http.StatusOK, w.Code, "the policy only covers POST")
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimiter_ByAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), RateLimitPolicy{
+		Name:  "api",
+		KeyBy: RateLimitByAPIKey,
+		Limit: ratelimit.Limit{Requests: 1, Period: time.Minute},
+	})
+	jwtService := jwtservice.NewJWTService("test-secret-key", 24*3600*1000000000)
+	jwtToken, _ := jwtService.GenerateToken("user1", "user1", "user1@example.com")
+
+	router := gin.New()
+	router.Use(limiter.Middleware())
+	router.GET("/resource", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	assert.Equal(t, http.StatusOK, sendRequest(router, http.MethodGet, "/resource", "192.0.2.1:1234", "rmp_a").Code)
+	assert.Equal(t, http.StatusTooManyRequests, sendRequest(router, http.MethodGet, "/resource", "192.0.2.2:1234", "rmp_a").Code)
+	assert.Equal(t, http.StatusOK, sendRequest(router, http.MethodGet, "/resource", "192.0.2.1:1234", "rmp_b").Code)
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, sendRequest(router, http.MethodGet, "/resource", "192.0.2.1:1234", jwtToken).Code,
+			"requests without an API key are not limited")
+		assert.Equal(t, http.StatusOK, sendRequest(router, http.MethodGet, "/resource", "192.0.2.1:1234", "").Code)
+	}
+}
+
+func TestRateLimiter_ByUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(),
+		RateLimitPolicy{
+			Name:  "user",
+			KeyBy: RateLimitByUser,
+			Limit: ratelimit.Limit{Requests: 1, Period: time.Minute, Burst: 2},
+		},
+		RateLimitPolicy{
+			Name:  "ip",
+			KeyBy: RateLimitByIP,
+			Limit: ratelimit.Limit{Requests: 10, Period: time.Minute},
+		},
+	)
+	jwtService := jwtservice.NewJWTService("test-secret-key", 24*3600*1000000000)
+	user1Token, _ := jwtService.GenerateToken("user1", "user1", "user1@example.com")
+	user2Token, _ := jwtService.GenerateToken("user2", "user2", "user2@example.com")
+
+	router := gin.New()
+	router.Use(limiter.Middleware())
+	router.Use(AuthMiddleware(jwtService, WithRateLimiter(limiter)))
+	router.GET("/resource", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	w := sendRequest(router, http.MethodGet, "/resource", "192.0.2.1:1234", user1Token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"), "the most restrictive policy is reported")
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+
+	assert.Equal(t, http.StatusOK, sendRequest(router, http.MethodGet, "/resource", "192.0.2.1:1234", user1Token).Code)
+	assert.Equal(t, http.StatusTooManyRequests,
+		sendRequest(router, http.MethodGet, "/resource", "192.0.2.2:1234", user1Token).Code)
+	assert.Equal(t, http.StatusOK, sendRequest(router, http.MethodGet, "/resource", "192.0.2.1:1234", user2Token).Code)
+}
+
+func TestRateLimiter_StoreErrorLetsRequestsThrough(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := NewRateLimiter(failingRateLimitStore{}, RateLimitPolicy{
+		Name:  "all",
+		KeyBy: RateLimitByIP,
+		Limit: ratelimit.Limit{Requests: 1, Period: time.Minute},
+	})
+
+	router := gin.New()
+	router.Use(limiter.Middleware())
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	w := sendRequest(router, http.MethodGet, "/test", "192.0.2.1:1234", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimitPolicy_Matches(t *testing.T) {
+	policy := RateLimitPolicy{Routes: []string{"POST /api/v1/users/login", "/api/v1/roadmaps"}}
+
+	assert.True(t, policy.matches(http.MethodPost, "/api/v1/users/login"))
+	assert.False(t, policy.matches(http.MethodGet, "/api/v1/users/login"))
+	assert.True(t, policy.matches(http.MethodGet, "/api/v1/roadmaps"))
+	assert.True(t, policy.matches(http.MethodDelete, "/api/v1/roadmaps/:id"))
+	assert.False(t, policy.matches(http.MethodGet, "/api/v1/roadmapsx"))
+	assert.True(t, (&RateLimitPolicy{}).matches(http.MethodGet, ""))
+}
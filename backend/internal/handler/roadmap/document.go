@@ -0,0 +1,160 @@
+package roadmaphandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapusecase "roadmap/internal/usecase/roadmap"
+)
+
+// maxDocumentSize limits the body of POST /roadmaps/import.
+const maxDocumentSize = 5 << 20
+
+type DocumentHandler struct {
+	exportUseCase *roadmapusecase.ExportRoadmapUseCase
+	importUseCase *roadmapusecase.ImportRoadmapUseCase
+}
+
+func NewDocumentHandler(
+	exportUseCase *roadmapusecase.ExportRoadmapUseCase,
+	importUseCase *roadmapusecase.ImportRoadmapUseCase,
+) *DocumentHandler {
+	return &DocumentHandler{
+		exportUseCase: exportUseCase,
+		importUseCase: importUseCase,
+	}
+}
+
+func (h *DocumentHandler) Export(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	var req roadmapdto.ExportRoadmapRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+	if req.Format == "" {
+		req.Format = roadmapusecase.DocumentFormatJSON
+	}
+
+	doc, err := h.exportUseCase.Execute(c.Request.Context(), actor, c.Param("id"))
+	if err != nil {
+		statusCode, errorMessage := roadmapError(err, "Failed to export roadmap")
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "application/json; charset=utf-8"
+	if req.Format == roadmapusecase.DocumentFormatYAML {
+		contentType = "application/yaml; charset=utf-8"
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		err = encoder.Encode(doc)
+		if err == nil {
+			err = encoder.Close()
+		}
+	} else {
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(doc)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to export roadmap",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.Roadmap.Slug+"."+req.Format))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+func (h *DocumentHandler) Import(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	var req roadmapdto.ImportRoadmapRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+	if req.Format == "" {
+		req.Format = documentFormat(c.ContentType())
+	}
+	if req.Format == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unsupported document format, use json or yaml",
+		})
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxDocumentSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": fmt.Sprintf("Document must be at most %d MB", maxDocumentSize>>20),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to read document",
+		})
+		return
+	}
+
+	response, err := h.importUseCase.Execute(c.Request.Context(), actor, data, req.Format, req.DryRun)
+	if err != nil {
+		var documentErr *roadmapusecase.DocumentError
+		if errors.As(err, &documentErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":    "Invalid roadmap document",
+				"problems": documentErr.Problems,
+			})
+			return
+		}
+		resourceError(c, err, "Failed to import roadmap")
+		return
+	}
+
+	statusCode := http.StatusOK
+	if response.Changes.RoadmapCreated && !response.DryRun {
+		statusCode = http.StatusCreated
+	}
+	c.JSON(statusCode, response)
+}
+
+// documentFormat derives the format of an import from its Content-Type,
+// accepting the variants in use such as application/x-yaml and text/yaml.
+func documentFormat(contentType string) string {
+	switch {
+	case strings.HasSuffix(contentType, "json"):
+		return roadmapusecase.DocumentFormatJSON
+	case strings.HasSuffix(contentType, "yaml"), strings.HasSuffix(contentType, "yml"):
+		return roadmapusecase.DocumentFormatYAML
+	}
+	return ""
+}
@@ -0,0 +1,254 @@
+package roadmaphandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+	roadmapusecase "roadmap/internal/usecase/roadmap"
+)
+
+const testDocument = `schema_version: 1
+roadmap:
+  slug: go
+  title: Go
+nodes:
+  - key: basics
+    title: Basics
+    type: topic
+    position: {x: 0, y: 0}
+`
+
+type MockDocumentRepository struct {
+	mock.Mock
+}
+
+// Import runs plan against the roadmap, graph and resources passed to Return
+// and returns the roadmap the planned changes leave behind.
+func (m *MockDocumentRepository) Import(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	slug string,
+	plan roadmaprepo.ImportPlanFunc,
+) (*roadmapentity.Roadmap, error) {
+	args := m.Called(ctx, ownerID, slug, plan)
+	if err := args.Error(3); err != nil {
+		return nil, err
+	}
+	var roadmap *roadmapentity.Roadmap
+	if args.Get(0) != nil {
+		roadmap = args.Get(0).(*roadmapentity.Roadmap)
+	}
+	changes, err := plan(roadmap, args.Get(1).(*roadmapentity.Graph), args.Get(2).([]*roadmapentity.Resource))
+	if err != nil {
+		return nil, err
+	}
+	if changes.Roadmap != nil {
+		return changes.Roadmap, nil
+	}
+	return roadmap, nil
+}
+
+type DocumentHandlerTestSuite struct {
+	suite.Suite
+	roadmapRepo  *MockRoadmapRepository
+	graphRepo    *MockGraphRepository
+	resourceRepo *MockResourceRepository
+	documentRepo *MockDocumentRepository
+	userID       uuid.UUID
+	permissions  []string
+	roadmap      *roadmapentity.Roadmap
+	graph        *roadmapentity.Graph
+	router       *gin.Engine
+}
+
+func (s *DocumentHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.roadmapRepo = new(MockRoadmapRepository)
+	s.graphRepo = new(MockGraphRepository)
+	s.resourceRepo = new(MockResourceRepository)
+	s.documentRepo = new(MockDocumentRepository)
+	s.userID = uuid.New()
+	s.permissions = []string{userentity.PermissionRoadmapsCreate}
+	s.roadmap = &roadmapentity.Roadmap{
+		ID:         uuid.New(),
+		OwnerID:    s.userID,
+		Title:      "Go",
+		Slug:       "go",
+		Visibility: roadmapentity.VisibilityPrivate,
+	}
+	s.graph = &roadmapentity.Graph{
+		Nodes: []*roadmapentity.Node{{
+			ID:        uuid.New(),
+			RoadmapID: s.roadmap.ID,
+			Key:       "basics",
+			Title:     "Basics",
+			Type:      roadmapentity.NodeTypeTopic,
+		}},
+	}
+
+	s.roadmapRepo.On("GetByID", mock.Anything, s.roadmap.ID).Return(s.roadmap, nil).Maybe()
+	s.graphRepo.On("GetGraph", mock.Anything, s.roadmap.ID).Return(s.graph, nil).Maybe()
+	s.resourceRepo.On("ListByRoadmap", mock.Anything, s.roadmap.ID).Return([]*roadmapentity.Resource{}, nil).Maybe()
+	s.documentRepo.On("Import", mock.Anything, s.userID, "go", mock.Anything).
+		Return(s.roadmap, s.graph, []*roadmapentity.Resource{}, nil).Maybe()
+
+	handler := NewDocumentHandler(
+		roadmapusecase.NewExportRoadmapUseCase(s.roadmapRepo, s.graphRepo, s.resourceRepo),
+		roadmapusecase.NewImportRoadmapUseCase(s.documentRepo),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.userID.String())
+		c.Set("claims", &jwtservice.Claims{
+			UserID:      s.userID.String(),
+			Permissions: s.permissions,
+		})
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupDocumentRoutes(s.router.Group("/api/v1"), handler, authMiddleware)
+}
+
+func (s *DocumentHandlerTestSuite) TearDownTest() {
+	s.roadmapRepo.AssertExpectations(s.T())
+	s.graphRepo.AssertExpectations(s.T())
+	s.resourceRepo.AssertExpectations(s.T())
+	s.documentRepo.AssertExpectations(s.T())
+}
+
+func (s *DocumentHandlerTestSuite) request(method, path, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *DocumentHandlerTestSuite) exportPath() string {
+	return "/api/v1/roadmaps/" + s.roadmap.ID.String() + "/export"
+}
+
+func (s *DocumentHandlerTestSuite) TestExport_JSON() {
+	w := s.request(http.MethodGet, s.exportPath(), "", "")
+
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.Contains(w.Header().Get("Content-Disposition"), `filename="go.json"`)
+
+	var doc roadmapdto.RoadmapDocument
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &doc))
+	s.Equal(roadmapdto.RoadmapSchemaVersion, doc.SchemaVersion)
+	s.Equal("basics", doc.Nodes[0].Key)
+}
+
+func (s *DocumentHandlerTestSuite) TestExport_YAML() {
+	w := s.request(http.MethodGet, s.exportPath()+"?format=yaml", "", "")
+
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.Equal("application/yaml; charset=utf-8", w.Header().Get("Content-Type"))
+	s.Contains(w.Body.String(), "position: {x: 0, ")
+
+	var doc roadmapdto.RoadmapDocument
+	s.Require().NoError(yaml.Unmarshal(w.Body.Bytes(), &doc))
+	s.Equal("go", doc.Roadmap.Slug)
+}
+
+func (s *DocumentHandlerTestSuite) TestExport_UnsupportedFormat() {
+	w := s.request(http.MethodGet, s.exportPath()+"?format=xml", "", "")
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *DocumentHandlerTestSuite) TestExport_NotFound() {
+	missing := uuid.New()
+	s.roadmapRepo.On("GetByID", mock.Anything, missing).Return(nil, roadmaprepo.ErrRoadmapNotFound)
+
+	w := s.request(http.MethodGet, "/api/v1/roadmaps/"+missing.String()+"/export", "", "")
+
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *DocumentHandlerTestSuite) TestImport_DryRun() {
+	w := s.request(http.MethodPost, "/api/v1/roadmaps/import?dry_run=true", "application/x-yaml",
+		strings.Replace(testDocument, "title: Basics", "title: Go basics", 1))
+
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	var response roadmapdto.ImportRoadmapResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.True(response.DryRun)
+	s.Equal(roadmapdto.ImportChanges{NodesUpdated: 1}, response.Changes)
+}
+
+func (s *DocumentHandlerTestSuite) TestImport_CreatesRoadmap() {
+	s.documentRepo.On("Import", mock.Anything, s.userID, "web", mock.Anything).
+		Return(nil, &roadmapentity.Graph{}, []*roadmapentity.Resource(nil), nil)
+
+	w := s.request(http.MethodPost, "/api/v1/roadmaps/import", "application/json",
+		`{"schema_version": 1, "roadmap": {"slug": "web", "title": "Web"}}`)
+
+	s.Equal(http.StatusCreated, w.Code)
+}
+
+func (s *DocumentHandlerTestSuite) TestImport_InvalidDocument() {
+	w := s.request(http.MethodPost, "/api/v1/roadmaps/import?format=yaml", "text/plain",
+		strings.Replace(testDocument, "type: topic", "type: chapter", 1))
+
+	s.Require().Equal(http.StatusBadRequest, w.Code)
+
+	var response struct {
+		Error    string                     `json:"error"`
+		Problems []roadmapdto.ImportProblem `json:"problems"`
+	}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal("Invalid roadmap document", response.Error)
+	s.Equal([]roadmapdto.ImportProblem{{
+		Line:    8,
+		Column:  11,
+		Path:    "nodes[0].type",
+		Message: "must be one of topic, subtopic, milestone",
+	}}, response.Problems)
+}
+
+func (s *DocumentHandlerTestSuite) TestImport_UnknownFormat() {
+	w := s.request(http.MethodPost, "/api/v1/roadmaps/import", "text/plain", testDocument)
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *DocumentHandlerTestSuite) TestImport_TooLarge() {
+	w := s.request(http.MethodPost, "/api/v1/roadmaps/import", "application/yaml",
+		testDocument+"#"+strings.Repeat("x", maxDocumentSize))
+
+	s.Equal(http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func (s *DocumentHandlerTestSuite) TestImport_RequiresCreatePermission() {
+	s.permissions = nil
+
+	w := s.request(http.MethodPost, "/api/v1/roadmaps/import", "application/yaml", testDocument)
+
+	s.Equal(http.StatusForbidden, w.Code)
+}
+
+func TestDocumentHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(DocumentHandlerTestSuite))
+}
@@ -0,0 +1,127 @@
+package roadmaphandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	roadmapusecase "roadmap/internal/usecase/roadmap"
+)
+
+type GraphHandler struct {
+	getUseCase   *roadmapusecase.GetRoadmapGraphUseCase
+	orderUseCase *roadmapusecase.GetNodeOrderUseCase
+	editUseCase  *roadmapusecase.EditRoadmapGraphUseCase
+}
+
+func NewGraphHandler(
+	getUseCase *roadmapusecase.GetRoadmapGraphUseCase,
+	orderUseCase *roadmapusecase.GetNodeOrderUseCase,
+	editUseCase *roadmapusecase.EditRoadmapGraphUseCase,
+) *GraphHandler {
+	return &GraphHandler{
+		getUseCase:   getUseCase,
+		orderUseCase: orderUseCase,
+		editUseCase:  editUseCase,
+	}
+}
+
+// graphError responds to errors of the graph use cases. Errors about a
+// single change of a batch carry details naming the offending node or edge.
+func graphError(c *gin.Context, err error, fallback string) {
+	var cycleErr *roadmapentity.CycleError
+	if errors.As(err, &cycleErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Prerequisites would form a cycle",
+			"cycle": cycleErr.NodeIDs,
+		})
+		return
+	}
+
+	statusCode, errorMessage := roadmapError(err, fallback)
+	withDetails := true
+	switch {
+	case errors.Is(err, roadmapusecase.ErrNodeNotFound):
+		statusCode, errorMessage = http.StatusBadRequest, "Node not found"
+	case errors.Is(err, roadmapusecase.ErrEdgeNotFound):
+		statusCode, errorMessage = http.StatusBadRequest, "Edge not found"
+	case errors.Is(err, roadmapusecase.ErrSelfPrerequisite):
+		statusCode, errorMessage = http.StatusBadRequest, "A node cannot be its own prerequisite"
+	case errors.Is(err, roadmapusecase.ErrNodeExists):
+		statusCode, errorMessage = http.StatusConflict, "Node already exists"
+	case errors.Is(err, roadmapusecase.ErrEdgeExists):
+		statusCode, errorMessage = http.StatusConflict, "Edge already exists"
+	case errors.Is(err, roadmapusecase.ErrBlankTitle):
+		statusCode, errorMessage = http.StatusBadRequest, "Title must not be blank"
+	case errors.Is(err, roadmapusecase.ErrGraphTooLarge):
+		statusCode, errorMessage = http.StatusBadRequest, "Roadmap has too many nodes"
+		withDetails = false
+	default:
+		withDetails = false
+	}
+
+	response := gin.H{
+		"error": errorMessage,
+	}
+	if withDetails {
+		response["details"] = err.Error()
+	}
+	c.JSON(statusCode, response)
+}
+
+func (h *GraphHandler) Get(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.getUseCase.Execute(c.Request.Context(), actor, c.Param("id"))
+	if err != nil {
+		graphError(c, err, "Failed to get roadmap graph")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *GraphHandler) Order(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.orderUseCase.Execute(c.Request.Context(), actor, c.Param("id"))
+	if err != nil {
+		graphError(c, err, "Failed to order roadmap nodes")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *GraphHandler) Edit(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	var req roadmapdto.EditGraphRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.editUseCase.Execute(c.Request.Context(), actor, c.Param("id"), req)
+	if err != nil {
+		graphError(c, err, "Failed to edit roadmap graph")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
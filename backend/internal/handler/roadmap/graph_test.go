@@ -0,0 +1,221 @@
+package roadmaphandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	jwtservice "roadmap/internal/pkg/jwt"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+	roadmapusecase "roadmap/internal/usecase/roadmap"
+)
+
+type MockGraphRepository struct {
+	mock.Mock
+}
+
+func (m *MockGraphRepository) GetGraph(ctx context.Context, roadmapID uuid.UUID) (*roadmapentity.Graph, error) {
+	args := m.Called(ctx, roadmapID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Graph), args.Error(1)
+}
+
+// EditGraph runs edit against the graph passed to Return and returns that
+// graph unchanged.
+func (m *MockGraphRepository) EditGraph(
+	ctx context.Context,
+	roadmapID uuid.UUID,
+	edit roadmaprepo.GraphEditFunc,
+) (*roadmapentity.Graph, error) {
+	args := m.Called(ctx, roadmapID, edit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	graph := args.Get(0).(*roadmapentity.Graph)
+	if _, err := edit(graph); err != nil {
+		return nil, err
+	}
+	return graph, args.Error(1)
+}
+
+type GraphHandlerTestSuite struct {
+	suite.Suite
+	roadmapRepo *MockRoadmapRepository
+	graphRepo   *MockGraphRepository
+	userID      uuid.UUID
+	roadmap     *roadmapentity.Roadmap
+	graph       *roadmapentity.Graph
+	router      *gin.Engine
+}
+
+func (s *GraphHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.roadmapRepo = new(MockRoadmapRepository)
+	s.graphRepo = new(MockGraphRepository)
+	s.userID = uuid.New()
+	s.roadmap = &roadmapentity.Roadmap{
+		ID:         uuid.New(),
+		OwnerID:    s.userID,
+		Title:      "Go",
+		Slug:       "go",
+		Visibility: roadmapentity.VisibilityPrivate,
+	}
+
+	basics := &roadmapentity.Node{ID: uuid.New(), Title: "Basics", Type: roadmapentity.NodeTypeTopic}
+	syntax := &roadmapentity.Node{ID: uuid.New(), Title: "Syntax", Type: roadmapentity.NodeTypeTopic}
+	s.graph = &roadmapentity.Graph{
+		Nodes: []*roadmapentity.Node{syntax, basics},
+		Edges: []*roadmapentity.Edge{{PrerequisiteID: basics.ID, NodeID: syntax.ID}},
+	}
+
+	s.roadmapRepo.On("GetByID", mock.Anything, s.roadmap.ID).Return(s.roadmap, nil).Maybe()
+
+	handler := NewGraphHandler(
+		roadmapusecase.NewGetRoadmapGraphUseCase(s.roadmapRepo, s.graphRepo),
+		roadmapusecase.NewGetNodeOrderUseCase(s.roadmapRepo, s.graphRepo),
+		roadmapusecase.NewEditRoadmapGraphUseCase(s.roadmapRepo, s.graphRepo),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.userID.String())
+		c.Set("claims", &jwtservice.Claims{UserID: s.userID.String()})
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupGraphRoutes(s.router.Group("/api/v1"), handler, authMiddleware)
+}
+
+func (s *GraphHandlerTestSuite) TearDownTest() {
+	s.roadmapRepo.AssertExpectations(s.T())
+	s.graphRepo.AssertExpectations(s.T())
+}
+
+func (s *GraphHandlerTestSuite) request(method, path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *GraphHandlerTestSuite) path() string {
+	return "/api/v1/roadmaps/" + s.roadmap.ID.String() + "/graph"
+}
+
+func (s *GraphHandlerTestSuite) TestGet() {
+	s.graphRepo.On("GetGraph", mock.Anything, s.roadmap.ID).Return(s.graph, nil)
+
+	w := s.request(http.MethodGet, s.path(), nil)
+
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var response roadmapdto.GraphResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Len(response.Nodes, 2)
+	s.Len(response.Edges, 1)
+}
+
+func (s *GraphHandlerTestSuite) TestOrder() {
+	s.graphRepo.On("GetGraph", mock.Anything, s.roadmap.ID).Return(s.graph, nil)
+
+	w := s.request(http.MethodGet, s.path()+"/order", nil)
+
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var response roadmapdto.NodeOrderResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Require().Len(response.Nodes, 2)
+	s.Equal("Basics", response.Nodes[0].Title)
+}
+
+func (s *GraphHandlerTestSuite) TestEdit() {
+	s.graphRepo.On("EditGraph", mock.Anything, s.roadmap.ID, mock.Anything).Return(s.graph, nil)
+
+	w := s.request(http.MethodPatch, s.path(), gin.H{
+		"create_nodes": []gin.H{{"title": "Generics", "type": "subtopic", "position_x": 10.5}},
+	})
+
+	s.Equal(http.StatusOK, w.Code, w.Body.String())
+}
+
+func (s *GraphHandlerTestSuite) TestEdit_Cycle() {
+	s.graphRepo.On("EditGraph", mock.Anything, s.roadmap.ID, mock.Anything).Return(s.graph, nil)
+
+	w := s.request(http.MethodPatch, s.path(), roadmapdto.EditGraphRequest{
+		AddEdges: []roadmapdto.EdgeRequest{{PrerequisiteID: s.graph.Nodes[0].ID, NodeID: s.graph.Nodes[1].ID}},
+	})
+
+	s.Require().Equal(http.StatusConflict, w.Code, w.Body.String())
+	var response struct {
+		Error string      `json:"error"`
+		Cycle []uuid.UUID `json:"cycle"`
+	}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.ElementsMatch([]uuid.UUID{s.graph.Nodes[0].ID, s.graph.Nodes[1].ID}, response.Cycle)
+}
+
+func (s *GraphHandlerTestSuite) TestEdit_UnknownNode() {
+	s.graphRepo.On("EditGraph", mock.Anything, s.roadmap.ID, mock.Anything).Return(s.graph, nil)
+	missing := uuid.New()
+
+	w := s.request(http.MethodPatch, s.path(), roadmapdto.EditGraphRequest{DeleteNodes: []uuid.UUID{missing}})
+
+	s.Require().Equal(http.StatusBadRequest, w.Code)
+	var response map[string]interface{}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal("Node not found", response["error"])
+	s.Contains(response["details"], missing.String())
+}
+
+func (s *GraphHandlerTestSuite) TestEdit_BlankTitle() {
+	s.graphRepo.On("EditGraph", mock.Anything, s.roadmap.ID, mock.Anything).Return(s.graph, nil)
+	node := s.graph.Nodes[0].ID
+
+	w := s.request(http.MethodPatch, s.path(), gin.H{
+		"update_nodes": []gin.H{{"id": node, "title": "   "}},
+	})
+
+	s.Require().Equal(http.StatusBadRequest, w.Code)
+	var response map[string]interface{}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal("Title must not be blank", response["error"])
+	s.Contains(response["details"], node.String())
+}
+
+func (s *GraphHandlerTestSuite) TestEdit_InvalidRequest() {
+	w := s.request(http.MethodPatch, s.path(), gin.H{
+		"create_nodes": []gin.H{{"title": "Generics", "type": "chapter"}},
+	})
+	s.Equal(http.StatusBadRequest, w.Code)
+
+	w = s.request(http.MethodPatch, s.path(), gin.H{
+		"add_edges": []gin.H{{"prerequisite_id": "not-a-uuid", "node_id": uuid.NewString()}},
+	})
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *GraphHandlerTestSuite) TestEdit_NotOwner() {
+	s.roadmap.OwnerID = uuid.New()
+	s.roadmap.Visibility = roadmapentity.VisibilityPublic
+
+	w := s.request(http.MethodPatch, s.path(), roadmapdto.EditGraphRequest{})
+
+	s.Equal(http.StatusForbidden, w.Code)
+}
+
+func TestGraphHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(GraphHandlerTestSuite))
+}
@@ -0,0 +1,95 @@
+package roadmaphandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapusecase "roadmap/internal/usecase/roadmap"
+)
+
+type ProgressHandler struct {
+	getUseCase    *roadmapusecase.GetProgressUseCase
+	updateUseCase *roadmapusecase.UpdateProgressUseCase
+}
+
+func NewProgressHandler(
+	getUseCase *roadmapusecase.GetProgressUseCase,
+	updateUseCase *roadmapusecase.UpdateProgressUseCase,
+) *ProgressHandler {
+	return &ProgressHandler{
+		getUseCase:    getUseCase,
+		updateUseCase: updateUseCase,
+	}
+}
+
+// progressError responds to errors of the progress use cases. Nodes marked
+// done too early are listed with their unfinished prerequisites.
+func progressError(c *gin.Context, err error, fallback string) {
+	var unfinishedErr *roadmapusecase.PrerequisitesUnfinishedError
+	if errors.As(err, &unfinishedErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "Prerequisites are not finished",
+			"unfinished": unfinishedErr.Unfinished,
+		})
+		return
+	}
+
+	graphError(c, err, fallback)
+}
+
+func (h *ProgressHandler) Get(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.getUseCase.Execute(c.Request.Context(), actor, c.Param("id"))
+	if err != nil {
+		progressError(c, err, "Failed to get progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ProgressHandler) Summary(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.getUseCase.Execute(c.Request.Context(), actor, c.Param("id"))
+	if err != nil {
+		progressError(c, err, "Failed to get progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Summary)
+}
+
+func (h *ProgressHandler) Update(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	var req roadmapdto.UpdateProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.updateUseCase.Execute(c.Request.Context(), actor, c.Param("id"), req)
+	if err != nil {
+		progressError(c, err, "Failed to update progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
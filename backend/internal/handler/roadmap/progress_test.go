@@ -0,0 +1,212 @@
+package roadmaphandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	jwtservice "roadmap/internal/pkg/jwt"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+	roadmapusecase "roadmap/internal/usecase/roadmap"
+)
+
+type MockProgressRepository struct {
+	mock.Mock
+}
+
+func (m *MockProgressRepository) ListByRoadmap(
+	ctx context.Context,
+	userID uuid.UUID,
+	roadmapID uuid.UUID,
+) ([]*roadmapentity.NodeProgress, error) {
+	args := m.Called(ctx, userID, roadmapID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*roadmapentity.NodeProgress), args.Error(1)
+}
+
+func (m *MockProgressRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*roadmapentity.NodeProgress, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*roadmapentity.NodeProgress), args.Error(1)
+}
+
+// EditProgress runs edit against the graph and progress passed to Return.
+func (m *MockProgressRepository) EditProgress(
+	ctx context.Context,
+	userID, roadmapID uuid.UUID,
+	edit roadmaprepo.ProgressEditFunc,
+) error {
+	args := m.Called(ctx, userID, roadmapID, edit)
+	if err := args.Error(2); err != nil {
+		return err
+	}
+	_, err := edit(args.Get(0).(*roadmapentity.Graph), args.Get(1).([]*roadmapentity.NodeProgress))
+	return err
+}
+
+type ProgressHandlerTestSuite struct {
+	suite.Suite
+	roadmapRepo  *MockRoadmapRepository
+	graphRepo    *MockGraphRepository
+	progressRepo *MockProgressRepository
+	userID       uuid.UUID
+	roadmap      *roadmapentity.Roadmap
+	basics       *roadmapentity.Node
+	syntax       *roadmapentity.Node
+	router       *gin.Engine
+}
+
+func (s *ProgressHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.roadmapRepo = new(MockRoadmapRepository)
+	s.graphRepo = new(MockGraphRepository)
+	s.progressRepo = new(MockProgressRepository)
+	s.userID = uuid.New()
+	s.roadmap = &roadmapentity.Roadmap{
+		ID:         uuid.New(),
+		OwnerID:    uuid.New(),
+		Title:      "Go",
+		Slug:       "go",
+		Visibility: roadmapentity.VisibilityPublic,
+	}
+
+	s.basics = &roadmapentity.Node{ID: uuid.New(), Title: "Basics", Type: roadmapentity.NodeTypeTopic}
+	s.syntax = &roadmapentity.Node{ID: uuid.New(), Title: "Syntax", Type: roadmapentity.NodeTypeTopic}
+	graph := &roadmapentity.Graph{
+		Nodes: []*roadmapentity.Node{s.basics, s.syntax},
+		Edges: []*roadmapentity.Edge{{PrerequisiteID: s.basics.ID, NodeID: s.syntax.ID}},
+	}
+
+	s.roadmapRepo.On("GetByID", mock.Anything, s.roadmap.ID).Return(s.roadmap, nil).Maybe()
+	s.graphRepo.On("GetGraph", mock.Anything, s.roadmap.ID).Return(graph, nil).Maybe()
+	s.progressRepo.On("ListByRoadmap", mock.Anything, s.userID, s.roadmap.ID).
+		Return([]*roadmapentity.NodeProgress{}, nil).Maybe()
+	s.progressRepo.On("EditProgress", mock.Anything, s.userID, s.roadmap.ID, mock.Anything).
+		Return(graph, []*roadmapentity.NodeProgress{}, nil).Maybe()
+
+	handler := NewProgressHandler(
+		roadmapusecase.NewGetProgressUseCase(s.roadmapRepo, s.graphRepo, s.progressRepo),
+		roadmapusecase.NewUpdateProgressUseCase(s.roadmapRepo, s.progressRepo),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.userID.String())
+		c.Set("claims", &jwtservice.Claims{UserID: s.userID.String()})
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupProgressRoutes(s.router.Group("/api/v1"), handler, authMiddleware)
+}
+
+func (s *ProgressHandlerTestSuite) TearDownTest() {
+	s.roadmapRepo.AssertExpectations(s.T())
+	s.graphRepo.AssertExpectations(s.T())
+	s.progressRepo.AssertExpectations(s.T())
+}
+
+func (s *ProgressHandlerTestSuite) request(method, path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *ProgressHandlerTestSuite) path() string {
+	return "/api/v1/roadmaps/" + s.roadmap.ID.String() + "/progress"
+}
+
+func (s *ProgressHandlerTestSuite) TestGet() {
+	w := s.request(http.MethodGet, s.path(), nil)
+
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	var response roadmapdto.ProgressResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Len(response.Nodes, 2)
+	s.Equal(2, response.Summary.NotStarted)
+}
+
+func (s *ProgressHandlerTestSuite) TestSummary() {
+	w := s.request(http.MethodGet, s.path()+"/summary", nil)
+
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	var response roadmapdto.ProgressSummaryResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal(s.roadmap.ID, response.RoadmapID)
+	s.Equal(2, response.TotalNodes)
+}
+
+func (s *ProgressHandlerTestSuite) TestUpdate() {
+	w := s.request(http.MethodPatch, s.path(), roadmapdto.UpdateProgressRequest{
+		Updates: []roadmapdto.NodeStatusUpdate{
+			{NodeID: s.basics.ID, Status: roadmapentity.ProgressDone},
+		},
+	})
+
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	var response roadmapdto.ProgressResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal(50.0, response.Summary.PercentDone)
+}
+
+func (s *ProgressHandlerTestSuite) TestUpdate_UnfinishedPrerequisites() {
+	w := s.request(http.MethodPatch, s.path(), roadmapdto.UpdateProgressRequest{
+		Updates: []roadmapdto.NodeStatusUpdate{
+			{NodeID: s.syntax.ID, Status: roadmapentity.ProgressDone},
+		},
+	})
+
+	s.Equal(http.StatusConflict, w.Code)
+
+	var response struct {
+		Unfinished map[string][]string `json:"unfinished"`
+	}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal(map[string][]string{s.syntax.ID.String(): {s.basics.ID.String()}}, response.Unfinished)
+}
+
+func (s *ProgressHandlerTestSuite) TestUpdate_UnknownNode() {
+	w := s.request(http.MethodPatch, s.path(), roadmapdto.UpdateProgressRequest{
+		Updates: []roadmapdto.NodeStatusUpdate{
+			{NodeID: uuid.New(), Status: roadmapentity.ProgressDone},
+		},
+	})
+
+	s.Equal(http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal("Node not found", response["error"])
+}
+
+func (s *ProgressHandlerTestSuite) TestUpdate_InvalidStatus() {
+	w := s.request(http.MethodPatch, s.path(), map[string]interface{}{
+		"updates": []map[string]string{{"node_id": s.basics.ID.String(), "status": "finished"}},
+	})
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func TestProgressHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ProgressHandlerTestSuite))
+}
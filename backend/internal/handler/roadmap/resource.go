@@ -0,0 +1,196 @@
+package roadmaphandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapusecase "roadmap/internal/usecase/roadmap"
+)
+
+type ResourceHandler struct {
+	listUseCase       *roadmapusecase.ListResourcesUseCase
+	createUseCase     *roadmapusecase.CreateResourceUseCase
+	updateUseCase     *roadmapusecase.UpdateResourceUseCase
+	deleteUseCase     *roadmapusecase.DeleteResourceUseCase
+	reorderUseCase    *roadmapusecase.ReorderResourcesUseCase
+	completionUseCase *roadmapusecase.SetResourceCompletionUseCase
+}
+
+func NewResourceHandler(
+	listUseCase *roadmapusecase.ListResourcesUseCase,
+	createUseCase *roadmapusecase.CreateResourceUseCase,
+	updateUseCase *roadmapusecase.UpdateResourceUseCase,
+	deleteUseCase *roadmapusecase.DeleteResourceUseCase,
+	reorderUseCase *roadmapusecase.ReorderResourcesUseCase,
+	completionUseCase *roadmapusecase.SetResourceCompletionUseCase,
+) *ResourceHandler {
+	return &ResourceHandler{
+		listUseCase:       listUseCase,
+		createUseCase:     createUseCase,
+		updateUseCase:     updateUseCase,
+		deleteUseCase:     deleteUseCase,
+		reorderUseCase:    reorderUseCase,
+		completionUseCase: completionUseCase,
+	}
+}
+
+// resourceError responds to errors of the resource use cases.
+func resourceError(c *gin.Context, err error, fallback string) {
+	var statusCode int
+	var errorMessage string
+	switch {
+	case errors.Is(err, roadmapusecase.ErrResourceNotFound):
+		statusCode, errorMessage = http.StatusNotFound, "Resource not found"
+	case errors.Is(err, roadmapusecase.ErrResourceURLTaken):
+		statusCode, errorMessage = http.StatusConflict, "Resource already added to this roadmap"
+	case errors.Is(err, roadmapusecase.ErrInvalidResourceURL):
+		statusCode, errorMessage = http.StatusBadRequest, "URL must be an absolute http or https URL"
+	case errors.Is(err, roadmapusecase.ErrInvalidOrder):
+		statusCode, errorMessage = http.StatusBadRequest, "Order must list every resource of the node once"
+	default:
+		graphError(c, err, fallback)
+		return
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error": errorMessage,
+	})
+}
+
+func (h *ResourceHandler) List(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	var req roadmapdto.ListResourcesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.listUseCase.Execute(c.Request.Context(), actor, c.Param("id"), req)
+	if err != nil {
+		resourceError(c, err, "Failed to list resources")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ResourceHandler) Create(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	var req roadmapdto.CreateResourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.createUseCase.Execute(c.Request.Context(), actor, c.Param("id"), req)
+	if err != nil {
+		resourceError(c, err, "Failed to create resource")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *ResourceHandler) Update(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	var req roadmapdto.UpdateResourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.updateUseCase.Execute(c.Request.Context(), actor, c.Param("id"), c.Param("resourceId"), req)
+	if err != nil {
+		resourceError(c, err, "Failed to update resource")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ResourceHandler) Delete(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	if err := h.deleteUseCase.Execute(c.Request.Context(), actor, c.Param("id"), c.Param("resourceId")); err != nil {
+		resourceError(c, err, "Failed to delete resource")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Resource deleted",
+	})
+}
+
+func (h *ResourceHandler) Reorder(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	var req roadmapdto.ReorderResourcesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.reorderUseCase.Execute(c.Request.Context(), actor, c.Param("id"), req)
+	if err != nil {
+		resourceError(c, err, "Failed to reorder resources")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ResourceHandler) Complete(c *gin.Context) {
+	h.setCompletion(c, true)
+}
+
+func (h *ResourceHandler) Uncomplete(c *gin.Context) {
+	h.setCompletion(c, false)
+}
+
+func (h *ResourceHandler) setCompletion(c *gin.Context, completed bool) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.completionUseCase.Execute(c.Request.Context(), actor, c.Param("id"), c.Param("resourceId"), completed)
+	if err != nil {
+		resourceError(c, err, "Failed to update resource completion")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
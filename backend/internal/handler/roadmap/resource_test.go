@@ -0,0 +1,306 @@
+package roadmaphandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	jwtservice "roadmap/internal/pkg/jwt"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+	roadmapusecase "roadmap/internal/usecase/roadmap"
+)
+
+type MockResourceRepository struct {
+	mock.Mock
+}
+
+func (m *MockResourceRepository) Create(ctx context.Context, resource *roadmapentity.Resource) (*roadmapentity.Resource, error) {
+	args := m.Called(ctx, resource)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Resource), args.Error(1)
+}
+
+func (m *MockResourceRepository) GetByID(ctx context.Context, id uuid.UUID) (*roadmapentity.Resource, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Resource), args.Error(1)
+}
+
+func (m *MockResourceRepository) ListByRoadmap(ctx context.Context, roadmapID uuid.UUID) ([]*roadmapentity.Resource, error) {
+	args := m.Called(ctx, roadmapID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*roadmapentity.Resource), args.Error(1)
+}
+
+func (m *MockResourceRepository) ListByNode(ctx context.Context, nodeID uuid.UUID) ([]*roadmapentity.Resource, error) {
+	args := m.Called(ctx, nodeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*roadmapentity.Resource), args.Error(1)
+}
+
+func (m *MockResourceRepository) Update(ctx context.Context, resource *roadmapentity.Resource) (*roadmapentity.Resource, error) {
+	args := m.Called(ctx, resource)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Resource), args.Error(1)
+}
+
+func (m *MockResourceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockResourceRepository) Reorder(ctx context.Context, nodeID uuid.UUID, resourceIDs []uuid.UUID) error {
+	args := m.Called(ctx, nodeID, resourceIDs)
+	return args.Error(0)
+}
+
+func (m *MockResourceRepository) SetCompleted(ctx context.Context, userID, resourceID uuid.UUID, completedAt *time.Time) error {
+	args := m.Called(ctx, userID, resourceID, completedAt)
+	return args.Error(0)
+}
+
+func (m *MockResourceRepository) ListCompletionsByRoadmap(
+	ctx context.Context,
+	userID, roadmapID uuid.UUID,
+) ([]*roadmapentity.ResourceCompletion, error) {
+	args := m.Called(ctx, userID, roadmapID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*roadmapentity.ResourceCompletion), args.Error(1)
+}
+
+func (m *MockResourceRepository) ListCompletionsByUser(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]*roadmapentity.ResourceCompletion, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*roadmapentity.ResourceCompletion), args.Error(1)
+}
+
+type ResourceHandlerTestSuite struct {
+	suite.Suite
+	roadmapRepo  *MockRoadmapRepository
+	graphRepo    *MockGraphRepository
+	resourceRepo *MockResourceRepository
+	progressRepo *MockProgressRepository
+	userID       uuid.UUID
+	roadmap      *roadmapentity.Roadmap
+	node         *roadmapentity.Node
+	resource     *roadmapentity.Resource
+	router       *gin.Engine
+}
+
+func (s *ResourceHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.roadmapRepo = new(MockRoadmapRepository)
+	s.graphRepo = new(MockGraphRepository)
+	s.resourceRepo = new(MockResourceRepository)
+	s.progressRepo = new(MockProgressRepository)
+	s.userID = uuid.New()
+	s.roadmap = &roadmapentity.Roadmap{
+		ID:         uuid.New(),
+		OwnerID:    s.userID,
+		Title:      "Go",
+		Slug:       "go",
+		Visibility: roadmapentity.VisibilityPrivate,
+	}
+	s.node = &roadmapentity.Node{ID: uuid.New(), Title: "Basics", Type: roadmapentity.NodeTypeTopic}
+	s.resource = &roadmapentity.Resource{
+		ID:        uuid.New(),
+		RoadmapID: s.roadmap.ID,
+		NodeID:    s.node.ID,
+		URL:       "https://go.dev/tour",
+		Title:     "A Tour of Go",
+		Kind:      roadmapentity.ResourceKindCourse,
+		IsFree:    true,
+	}
+
+	s.roadmapRepo.On("GetByID", mock.Anything, s.roadmap.ID).Return(s.roadmap, nil).Maybe()
+	s.resourceRepo.On("GetByID", mock.Anything, s.resource.ID).Return(s.resource, nil).Maybe()
+
+	handler := NewResourceHandler(
+		roadmapusecase.NewListResourcesUseCase(s.roadmapRepo, s.resourceRepo),
+		roadmapusecase.NewCreateResourceUseCase(s.roadmapRepo, s.resourceRepo),
+		roadmapusecase.NewUpdateResourceUseCase(s.roadmapRepo, s.resourceRepo),
+		roadmapusecase.NewDeleteResourceUseCase(s.roadmapRepo, s.resourceRepo),
+		roadmapusecase.NewReorderResourcesUseCase(s.roadmapRepo, s.resourceRepo),
+		roadmapusecase.NewSetResourceCompletionUseCase(s.roadmapRepo, s.graphRepo, s.resourceRepo, s.progressRepo),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.userID.String())
+		c.Set("claims", &jwtservice.Claims{UserID: s.userID.String()})
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupResourceRoutes(s.router.Group("/api/v1"), handler, authMiddleware)
+}
+
+func (s *ResourceHandlerTestSuite) TearDownTest() {
+	s.roadmapRepo.AssertExpectations(s.T())
+	s.graphRepo.AssertExpectations(s.T())
+	s.resourceRepo.AssertExpectations(s.T())
+	s.progressRepo.AssertExpectations(s.T())
+}
+
+func (s *ResourceHandlerTestSuite) request(method, path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *ResourceHandlerTestSuite) path() string {
+	return "/api/v1/roadmaps/" + s.roadmap.ID.String() + "/resources"
+}
+
+func (s *ResourceHandlerTestSuite) TestList() {
+	s.resourceRepo.On("ListByNode", mock.Anything, s.node.ID).Return([]*roadmapentity.Resource{s.resource}, nil)
+	s.resourceRepo.On("ListCompletionsByRoadmap", mock.Anything, s.userID, s.roadmap.ID).
+		Return([]*roadmapentity.ResourceCompletion{}, nil)
+
+	w := s.request(http.MethodGet, s.path()+"?node_id="+s.node.ID.String(), nil)
+
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	var response roadmapdto.ListResourcesResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Require().Len(response.Resources, 1)
+	s.False(response.Resources[0].Completed)
+}
+
+func (s *ResourceHandlerTestSuite) TestCreate() {
+	s.resourceRepo.On("Create", mock.Anything, mock.Anything).Return(s.resource, nil)
+
+	w := s.request(http.MethodPost, s.path(), roadmapdto.CreateResourceRequest{
+		NodeID:   s.node.ID,
+		URL:      "https://go.dev/tour",
+		Title:    "A Tour of Go",
+		Kind:     roadmapentity.ResourceKindCourse,
+		Language: "en",
+	})
+
+	s.Equal(http.StatusCreated, w.Code)
+}
+
+func (s *ResourceHandlerTestSuite) TestCreate_InvalidLanguage() {
+	w := s.request(http.MethodPost, s.path(), roadmapdto.CreateResourceRequest{
+		NodeID:   s.node.ID,
+		URL:      "https://go.dev/tour",
+		Title:    "A Tour of Go",
+		Kind:     roadmapentity.ResourceKindCourse,
+		Language: "not a language",
+	})
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *ResourceHandlerTestSuite) TestCreate_Duplicate() {
+	s.resourceRepo.On("Create", mock.Anything, mock.Anything).Return(nil, roadmaprepo.ErrResourceURLTaken)
+
+	w := s.request(http.MethodPost, s.path(), roadmapdto.CreateResourceRequest{
+		NodeID: s.node.ID,
+		URL:    "http://www.go.dev/tour/",
+		Title:  "A Tour of Go",
+		Kind:   roadmapentity.ResourceKindCourse,
+	})
+
+	s.Equal(http.StatusConflict, w.Code)
+}
+
+func (s *ResourceHandlerTestSuite) TestCreate_UnknownNode() {
+	s.resourceRepo.On("Create", mock.Anything, mock.Anything).Return(nil, roadmaprepo.ErrNodeNotFound)
+
+	w := s.request(http.MethodPost, s.path(), roadmapdto.CreateResourceRequest{
+		NodeID: uuid.New(),
+		URL:    "https://go.dev/tour",
+		Title:  "A Tour of Go",
+		Kind:   roadmapentity.ResourceKindCourse,
+	})
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *ResourceHandlerTestSuite) TestUpdate_NotOwner() {
+	s.roadmap.OwnerID = uuid.New()
+	s.roadmap.Visibility = roadmapentity.VisibilityPublic
+
+	title := "Renamed"
+	w := s.request(http.MethodPatch, s.path()+"/"+s.resource.ID.String(), roadmapdto.UpdateResourceRequest{Title: &title})
+
+	s.Equal(http.StatusForbidden, w.Code)
+}
+
+func (s *ResourceHandlerTestSuite) TestDelete_NotFound() {
+	missing := uuid.New()
+	s.resourceRepo.On("GetByID", mock.Anything, missing).Return(nil, roadmaprepo.ErrResourceNotFound)
+
+	w := s.request(http.MethodDelete, s.path()+"/"+missing.String(), nil)
+
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *ResourceHandlerTestSuite) TestReorder() {
+	s.resourceRepo.On("ListByNode", mock.Anything, s.node.ID).Return([]*roadmapentity.Resource{s.resource}, nil)
+	s.resourceRepo.On("Reorder", mock.Anything, s.node.ID, []uuid.UUID{s.resource.ID}).Return(nil)
+	s.resourceRepo.On("ListCompletionsByRoadmap", mock.Anything, s.userID, s.roadmap.ID).
+		Return([]*roadmapentity.ResourceCompletion{}, nil)
+
+	w := s.request(http.MethodPut, s.path()+"/order", roadmapdto.ReorderResourcesRequest{
+		NodeID:      s.node.ID,
+		ResourceIDs: []uuid.UUID{s.resource.ID},
+	})
+
+	s.Equal(http.StatusOK, w.Code)
+}
+
+func (s *ResourceHandlerTestSuite) TestComplete() {
+	s.resourceRepo.On("SetCompleted", mock.Anything, s.userID, s.resource.ID, mock.AnythingOfType("*time.Time")).Return(nil)
+	s.resourceRepo.On("ListCompletionsByRoadmap", mock.Anything, s.userID, s.roadmap.ID).
+		Return([]*roadmapentity.ResourceCompletion{{UserID: s.userID, ResourceID: s.resource.ID, CompletedAt: time.Now()}}, nil)
+	s.resourceRepo.On("ListByNode", mock.Anything, s.node.ID).Return([]*roadmapentity.Resource{s.resource}, nil)
+	s.progressRepo.On("EditProgress", mock.Anything, s.userID, s.roadmap.ID, mock.Anything).
+		Return(&roadmapentity.Graph{Nodes: []*roadmapentity.Node{s.node}}, []*roadmapentity.NodeProgress{}, nil)
+
+	w := s.request(http.MethodPut, s.path()+"/"+s.resource.ID.String()+"/completion", nil)
+
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	var response roadmapdto.ResourceCompletionResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.True(response.Resource.Completed)
+	s.Equal(roadmapentity.ProgressDone, response.Node.Status)
+}
+
+func TestResourceHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ResourceHandlerTestSuite))
+}
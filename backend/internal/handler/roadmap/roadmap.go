@@ -0,0 +1,192 @@
+package roadmaphandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/handler/middleware"
+	roadmapusecase "roadmap/internal/usecase/roadmap"
+)
+
+type RoadmapHandler struct {
+	createUseCase *roadmapusecase.CreateRoadmapUseCase
+	listUseCase   *roadmapusecase.ListRoadmapsUseCase
+	getUseCase    *roadmapusecase.GetRoadmapUseCase
+	updateUseCase *roadmapusecase.UpdateRoadmapUseCase
+	deleteUseCase *roadmapusecase.DeleteRoadmapUseCase
+}
+
+func NewRoadmapHandler(
+	createUseCase *roadmapusecase.CreateRoadmapUseCase,
+	listUseCase *roadmapusecase.ListRoadmapsUseCase,
+	getUseCase *roadmapusecase.GetRoadmapUseCase,
+	updateUseCase *roadmapusecase.UpdateRoadmapUseCase,
+	deleteUseCase *roadmapusecase.DeleteRoadmapUseCase,
+) *RoadmapHandler {
+	return &RoadmapHandler{
+		createUseCase: createUseCase,
+		listUseCase:   listUseCase,
+		getUseCase:    getUseCase,
+		updateUseCase: updateUseCase,
+		deleteUseCase: deleteUseCase,
+	}
+}
+
+// getActor writes a 401 response and returns false when the request carries
+// no authenticated user.
+func getActor(c *gin.Context) (roadmapusecase.Actor, bool) {
+	claims, exists := middleware.GetClaims(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return roadmapusecase.Actor{}, false
+	}
+
+	return roadmapusecase.Actor{
+		UserID:      claims.UserID,
+		CanModerate: claims.HasPermission(userentity.PermissionRoadmapsModerate),
+	}, true
+}
+
+// roadmapError maps the errors shared by the roadmap use cases. It returns
+// fallback with a 500 for anything else.
+func roadmapError(err error, fallback string) (int, string) {
+	switch {
+	case errors.Is(err, roadmapusecase.ErrRoadmapNotFound):
+		return http.StatusNotFound, "Roadmap not found"
+	case errors.Is(err, roadmapusecase.ErrNotRoadmapEditor):
+		return http.StatusForbidden, "Only the owner or a moderator can change this roadmap"
+	case errors.Is(err, roadmapusecase.ErrRoadmapSlugTaken):
+		return http.StatusConflict, "Slug already in use"
+	case errors.Is(err, roadmapusecase.ErrInvalidSlug):
+		return http.StatusBadRequest, "Slug must consist of lowercase letters, digits and single hyphens"
+	case errors.Is(err, roadmapusecase.ErrBlankTitle):
+		return http.StatusBadRequest, "Title must not be blank"
+	case errors.Is(err, roadmapusecase.ErrInvalidTokenClaims):
+		return http.StatusUnauthorized, "Invalid token"
+	}
+	return http.StatusInternalServerError, fallback
+}
+
+func (h *RoadmapHandler) Create(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	var req roadmapdto.CreateRoadmapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.createUseCase.Execute(c.Request.Context(), actor, req)
+	if err != nil {
+		statusCode, errorMessage := roadmapError(err, "Failed to create roadmap")
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *RoadmapHandler) List(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	var req roadmapdto.ListRoadmapsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.listUseCase.Execute(c.Request.Context(), actor, req)
+	if err != nil {
+		statusCode, errorMessage := roadmapError(err, "Failed to list roadmaps")
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RoadmapHandler) Get(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.getUseCase.Execute(c.Request.Context(), actor, c.Param("id"))
+	if err != nil {
+		statusCode, errorMessage := roadmapError(err, "Failed to get roadmap")
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RoadmapHandler) Update(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	var req roadmapdto.UpdateRoadmapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.updateUseCase.Execute(c.Request.Context(), actor, c.Param("id"), req)
+	if err != nil {
+		statusCode, errorMessage := roadmapError(err, "Failed to update roadmap")
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RoadmapHandler) Delete(c *gin.Context) {
+	actor, ok := getActor(c)
+	if !ok {
+		return
+	}
+
+	if err := h.deleteUseCase.Execute(c.Request.Context(), actor, c.Param("id")); err != nil {
+		statusCode, errorMessage := roadmapError(err, "Failed to delete roadmap")
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Roadmap deleted",
+	})
+}
@@ -0,0 +1,267 @@
+package roadmaphandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+	roadmapusecase "roadmap/internal/usecase/roadmap"
+)
+
+type MockRoadmapRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoadmapRepository) Create(
+	ctx context.Context,
+	roadmap *roadmapentity.Roadmap,
+) (*roadmapentity.Roadmap, error) {
+	args := m.Called(ctx, roadmap)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Roadmap), args.Error(1)
+}
+
+func (m *MockRoadmapRepository) GetByID(ctx context.Context, id uuid.UUID) (*roadmapentity.Roadmap, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Roadmap), args.Error(1)
+}
+
+func (m *MockRoadmapRepository) GetByOwnerAndSlug(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	slug string,
+) (*roadmapentity.Roadmap, error) {
+	args := m.Called(ctx, ownerID, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Roadmap), args.Error(1)
+}
+
+func (m *MockRoadmapRepository) List(
+	ctx context.Context,
+	filter roadmaprepo.RoadmapListFilter,
+) ([]*roadmapentity.Roadmap, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*roadmapentity.Roadmap), args.Int(1), args.Error(2)
+}
+
+func (m *MockRoadmapRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*roadmapentity.Roadmap, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*roadmapentity.Roadmap), args.Error(1)
+}
+
+func (m *MockRoadmapRepository) Update(
+	ctx context.Context,
+	roadmap *roadmapentity.Roadmap,
+) (*roadmapentity.Roadmap, error) {
+	args := m.Called(ctx, roadmap)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Roadmap), args.Error(1)
+}
+
+func (m *MockRoadmapRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type RoadmapHandlerTestSuite struct {
+	suite.Suite
+	roadmapRepo *MockRoadmapRepository
+	userID      uuid.UUID
+	permissions []string
+	roadmap     *roadmapentity.Roadmap
+	router      *gin.Engine
+}
+
+func (s *RoadmapHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.roadmapRepo = new(MockRoadmapRepository)
+	s.userID = uuid.New()
+	s.permissions = []string{userentity.PermissionRoadmapsCreate}
+
+	now := time.Now().UTC()
+	s.roadmap = &roadmapentity.Roadmap{
+		ID:         uuid.New(),
+		OwnerID:    s.userID,
+		Title:      "Backend Developer",
+		Slug:       "backend-developer",
+		Visibility: roadmapentity.VisibilityPrivate,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	handler := NewRoadmapHandler(
+		roadmapusecase.NewCreateRoadmapUseCase(s.roadmapRepo),
+		roadmapusecase.NewListRoadmapsUseCase(s.roadmapRepo),
+		roadmapusecase.NewGetRoadmapUseCase(s.roadmapRepo),
+		roadmapusecase.NewUpdateRoadmapUseCase(s.roadmapRepo),
+		roadmapusecase.NewDeleteRoadmapUseCase(s.roadmapRepo),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.userID.String())
+		c.Set("claims", &jwtservice.Claims{
+			UserID:      s.userID.String(),
+			Permissions: s.permissions,
+		})
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupRoadmapRoutes(s.router.Group("/api/v1"), handler, authMiddleware)
+}
+
+func (s *RoadmapHandlerTestSuite) TearDownTest() {
+	s.roadmapRepo.AssertExpectations(s.T())
+}
+
+func (s *RoadmapHandlerTestSuite) request(method, path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *RoadmapHandlerTestSuite) TestCreate() {
+	s.roadmapRepo.On("Create", mock.Anything, mock.MatchedBy(func(roadmap *roadmapentity.Roadmap) bool {
+		return roadmap.OwnerID == s.userID && roadmap.Slug == "backend-developer"
+	})).Return(s.roadmap, nil)
+
+	w := s.request(http.MethodPost, "/api/v1/roadmaps", roadmapdto.CreateRoadmapRequest{Title: "Backend Developer"})
+
+	s.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var response roadmapdto.RoadmapResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal(s.roadmap.ID, response.ID)
+}
+
+func (s *RoadmapHandlerTestSuite) TestCreate_WithoutPermission() {
+	s.permissions = nil
+
+	w := s.request(http.MethodPost, "/api/v1/roadmaps", roadmapdto.CreateRoadmapRequest{Title: "Backend Developer"})
+
+	s.Equal(http.StatusForbidden, w.Code)
+}
+
+func (s *RoadmapHandlerTestSuite) TestCreate_InvalidRequest() {
+	w := s.request(http.MethodPost, "/api/v1/roadmaps", gin.H{"title": "Backend", "visibility": "secret"})
+	s.Equal(http.StatusBadRequest, w.Code)
+
+	w = s.request(http.MethodPost, "/api/v1/roadmaps", gin.H{"title": "Backend", "slug": "Back End"})
+	s.Equal(http.StatusBadRequest, w.Code)
+
+	w = s.request(http.MethodPost, "/api/v1/roadmaps", gin.H{"title": "   "})
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *RoadmapHandlerTestSuite) TestCreate_SlugTaken() {
+	s.roadmapRepo.On("Create", mock.Anything, mock.AnythingOfType("*roadmap.Roadmap")).Return(nil, roadmaprepo.ErrSlugTaken)
+
+	w := s.request(http.MethodPost, "/api/v1/roadmaps", roadmapdto.CreateRoadmapRequest{Title: "Backend Developer", Slug: "backend"})
+
+	s.Equal(http.StatusConflict, w.Code)
+}
+
+func (s *RoadmapHandlerTestSuite) TestList() {
+	s.roadmapRepo.On("List", mock.Anything, mock.MatchedBy(func(filter roadmaprepo.RoadmapListFilter) bool {
+		return filter.ViewerID == s.userID && filter.OwnerID == nil && filter.Limit == 5 && filter.Offset == 5
+	})).Return([]*roadmapentity.Roadmap{s.roadmap}, 6, nil)
+
+	w := s.request(http.MethodGet, "/api/v1/roadmaps?page=2&page_size=5", nil)
+
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var response roadmapdto.ListRoadmapsResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal(6, response.Total)
+	s.Len(response.Roadmaps, 1)
+}
+
+func (s *RoadmapHandlerTestSuite) TestList_InvalidOwner() {
+	w := s.request(http.MethodGet, "/api/v1/roadmaps?owner_id=me", nil)
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *RoadmapHandlerTestSuite) TestGet_PrivateRoadmapOfOtherUser() {
+	s.roadmap.OwnerID = uuid.New()
+	s.roadmapRepo.On("GetByID", mock.Anything, s.roadmap.ID).Return(s.roadmap, nil)
+
+	w := s.request(http.MethodGet, "/api/v1/roadmaps/"+s.roadmap.ID.String(), nil)
+
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *RoadmapHandlerTestSuite) TestUpdate_NotOwner() {
+	s.roadmap.OwnerID = uuid.New()
+	s.roadmap.Visibility = roadmapentity.VisibilityPublic
+	s.roadmapRepo.On("GetByID", mock.Anything, s.roadmap.ID).Return(s.roadmap, nil)
+
+	w := s.request(http.MethodPatch, "/api/v1/roadmaps/"+s.roadmap.ID.String(), gin.H{"title": "Mine now"})
+
+	s.Equal(http.StatusForbidden, w.Code)
+}
+
+func (s *RoadmapHandlerTestSuite) TestUpdate_Moderator() {
+	s.roadmap.OwnerID = uuid.New()
+	s.permissions = []string{userentity.PermissionRoadmapsModerate}
+	s.roadmapRepo.On("GetByID", mock.Anything, s.roadmap.ID).Return(s.roadmap, nil)
+	s.roadmapRepo.On("Update", mock.Anything, mock.MatchedBy(func(roadmap *roadmapentity.Roadmap) bool {
+		return roadmap.Visibility == roadmapentity.VisibilityUnlisted
+	})).Return(s.roadmap, nil)
+
+	w := s.request(http.MethodPatch, "/api/v1/roadmaps/"+s.roadmap.ID.String(), gin.H{"visibility": "unlisted"})
+
+	s.Equal(http.StatusOK, w.Code, w.Body.String())
+}
+
+func (s *RoadmapHandlerTestSuite) TestDelete() {
+	s.roadmapRepo.On("GetByID", mock.Anything, s.roadmap.ID).Return(s.roadmap, nil)
+	s.roadmapRepo.On("Delete", mock.Anything, s.roadmap.ID).Return(nil)
+
+	w := s.request(http.MethodDelete, "/api/v1/roadmaps/"+s.roadmap.ID.String(), nil)
+
+	s.Equal(http.StatusOK, w.Code)
+}
+
+func (s *RoadmapHandlerTestSuite) TestDelete_NotFound() {
+	s.roadmapRepo.On("GetByID", mock.Anything, s.roadmap.ID).Return(nil, roadmaprepo.ErrRoadmapNotFound)
+
+	w := s.request(http.MethodDelete, "/api/v1/roadmaps/"+s.roadmap.ID.String(), nil)
+
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func TestRoadmapHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(RoadmapHandlerTestSuite))
+}
@@ -0,0 +1,63 @@
+package roadmaphandler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/handler/middleware"
+)
+
+func SetupRoadmapRoutes(router *gin.RouterGroup, handler *RoadmapHandler, authMiddleware gin.HandlerFunc) {
+	roadmaps := router.Group("/roadmaps")
+	roadmaps.Use(authMiddleware)
+	{
+		roadmaps.POST("", middleware.RequirePermission(userentity.PermissionRoadmapsCreate), handler.Create)
+		roadmaps.GET("", handler.List)
+		roadmaps.GET(":id", handler.Get)
+		roadmaps.PATCH(":id", handler.Update)
+		roadmaps.DELETE(":id", handler.Delete)
+	}
+}
+
+func SetupGraphRoutes(router *gin.RouterGroup, handler *GraphHandler, authMiddleware gin.HandlerFunc) {
+	graph := router.Group("/roadmaps/:id/graph")
+	graph.Use(authMiddleware)
+	{
+		graph.GET("", handler.Get)
+		graph.GET("order", handler.Order)
+		graph.PATCH("", handler.Edit)
+	}
+}
+
+func SetupProgressRoutes(router *gin.RouterGroup, handler *ProgressHandler, authMiddleware gin.HandlerFunc) {
+	progress := router.Group("/roadmaps/:id/progress")
+	progress.Use(authMiddleware)
+	{
+		progress.GET("", handler.Get)
+		progress.GET("summary", handler.Summary)
+		progress.PATCH("", handler.Update)
+	}
+}
+
+func SetupResourceRoutes(router *gin.RouterGroup, handler *ResourceHandler, authMiddleware gin.HandlerFunc) {
+	resources := router.Group("/roadmaps/:id/resources")
+	resources.Use(authMiddleware)
+	{
+		resources.GET("", handler.List)
+		resources.POST("", handler.Create)
+		resources.PUT("order", handler.Reorder)
+		resources.PATCH(":resourceId", handler.Update)
+		resources.DELETE(":resourceId", handler.Delete)
+		resources.PUT(":resourceId/completion", handler.Complete)
+		resources.DELETE(":resourceId/completion", handler.Uncomplete)
+	}
+}
+
+func SetupDocumentRoutes(router *gin.RouterGroup, handler *DocumentHandler, authMiddleware gin.HandlerFunc) {
+	roadmaps := router.Group("/roadmaps")
+	roadmaps.Use(authMiddleware)
+	{
+		roadmaps.POST("import", middleware.RequirePermission(userentity.PermissionRoadmapsCreate), handler.Import)
+		roadmaps.GET(":id/export", handler.Export)
+	}
+}
@@ -0,0 +1,213 @@
+package userhandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type AccessTokenHandler struct {
+	createUseCase *userusecase.CreateAccessTokenUseCase
+	listUseCase   *userusecase.ListAccessTokensUseCase
+	getUseCase    *userusecase.GetAccessTokenUseCase
+	updateUseCase *userusecase.UpdateAccessTokenUseCase
+	deleteUseCase *userusecase.DeleteAccessTokenUseCase
+}
+
+func NewAccessTokenHandler(
+	createUseCase *userusecase.CreateAccessTokenUseCase,
+	listUseCase *userusecase.ListAccessTokensUseCase,
+	getUseCase *userusecase.GetAccessTokenUseCase,
+	updateUseCase *userusecase.UpdateAccessTokenUseCase,
+	deleteUseCase *userusecase.DeleteAccessTokenUseCase,
+) *AccessTokenHandler {
+	return &AccessTokenHandler{
+		createUseCase: createUseCase,
+		listUseCase:   listUseCase,
+		getUseCase:    getUseCase,
+		updateUseCase: updateUseCase,
+		deleteUseCase: deleteUseCase,
+	}
+}
+
+func (h *AccessTokenHandler) Create(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	var req userdto.CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.createUseCase.Execute(c.Request.Context(), userID, req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to create access token"
+
+		if errors.Is(err, userusecase.ErrAccessTokenNameTaken) {
+			statusCode = http.StatusConflict
+			errorMessage = "Access token name already in use"
+		} else if errors.Is(err, userusecase.ErrInvalidAccessTokenExpiry) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Expiry must be in the future"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *AccessTokenHandler) List(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	response, err := h.listUseCase.Execute(c.Request.Context(), userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to list access tokens"
+
+		if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AccessTokenHandler) Get(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	response, err := h.getUseCase.Execute(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to get access token"
+
+		if errors.Is(err, userusecase.ErrAccessTokenNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "Access token not found"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AccessTokenHandler) Update(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	var req userdto.UpdateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.updateUseCase.Execute(c.Request.Context(), userID, c.Param("id"), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to update access token"
+
+		if errors.Is(err, userusecase.ErrAccessTokenNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "Access token not found"
+		} else if errors.Is(err, userusecase.ErrAccessTokenNameTaken) {
+			statusCode = http.StatusConflict
+			errorMessage = "Access token name already in use"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AccessTokenHandler) Delete(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	if err := h.deleteUseCase.Execute(c.Request.Context(), userID, c.Param("id")); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to delete access token"
+
+		if errors.Is(err, userusecase.ErrAccessTokenNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "Access token not found"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Access token deleted",
+	})
+}
@@ -0,0 +1,245 @@
+package userhandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/handler/middleware"
+	jwtservice "roadmap/internal/pkg/jwt"
+	userrepo "roadmap/internal/repository/user"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type MockPersonalAccessTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockPersonalAccessTokenRepository) Create(
+	ctx context.Context,
+	token *userentity.PersonalAccessToken,
+) (*userentity.PersonalAccessToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) GetByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.PersonalAccessToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) GetByID(
+	ctx context.Context,
+	id uuid.UUID,
+	userID uuid.UUID,
+) (*userentity.PersonalAccessToken, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) ListByUser(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]*userentity.PersonalAccessToken, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*userentity.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) Update(
+	ctx context.Context,
+	token *userentity.PersonalAccessToken,
+) (*userentity.PersonalAccessToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+func (m *MockPersonalAccessTokenRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+type AccessTokenHandlerTestSuite struct {
+	suite.Suite
+	router     *gin.Engine
+	jwtService *jwtservice.JWTService
+	userRepo   *MockUserRepository
+	tokenRepo  *MockPersonalAccessTokenRepository
+	user       *userentity.User
+	jwt        string
+}
+
+func (s *AccessTokenHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.userRepo = new(MockUserRepository)
+	s.tokenRepo = new(MockPersonalAccessTokenRepository)
+	s.jwtService = jwtservice.NewJWTService("test-secret", 15*time.Minute)
+
+	verifiedAt := time.Now()
+	s.user = &userentity.User{
+		ID:              uuid.New(),
+		Username:        "testuser",
+		Email:           "test@example.com",
+		EmailVerifiedAt: &verifiedAt,
+	}
+
+	var err error
+	s.jwt, err = s.jwtService.GenerateToken(s.user.ID.String(), s.user.Username, s.user.Email)
+	s.Require().NoError(err)
+
+	handler := NewAccessTokenHandler(
+		userusecase.NewCreateAccessTokenUseCase(s.tokenRepo),
+		userusecase.NewListAccessTokensUseCase(s.tokenRepo),
+		userusecase.NewGetAccessTokenUseCase(s.tokenRepo),
+		userusecase.NewUpdateAccessTokenUseCase(s.tokenRepo),
+		userusecase.NewDeleteAccessTokenUseCase(s.tokenRepo),
+	)
+
+	authMiddleware := middleware.AuthMiddleware(
+		s.jwtService,
+		middleware.WithAccessTokens(
+			userusecase.NewAccessTokenAuthenticator(s.tokenRepo, s.userRepo),
+			"/api/v1/users/tokens",
+		),
+	)
+
+	s.router = gin.New()
+	api := s.router.Group("/api/v1")
+	SetupAccessTokenRoutes(api, handler, authMiddleware)
+	api.GET("/whoami", authMiddleware, func(c *gin.Context) {
+		userID, _ := middleware.GetUserID(c)
+		c.JSON(http.StatusOK, gin.H{"user_id": userID})
+	})
+}
+
+func (s *AccessTokenHandlerTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.tokenRepo.AssertExpectations(s.T())
+}
+
+func (s *AccessTokenHandlerTestSuite) request(method, path, bearer string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *AccessTokenHandlerTestSuite) TestCreateAndUseToken() {
+	var stored *userentity.PersonalAccessToken
+	s.tokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.PersonalAccessToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*userentity.PersonalAccessToken) }).
+		Return(&userentity.PersonalAccessToken{ID: uuid.New(), Name: "CI", Scopes: []string{"read"}}, nil)
+
+	w := s.request(http.MethodPost, "/api/v1/users/tokens", s.jwt, userdto.CreateAccessTokenRequest{
+		Name:   "CI",
+		Scopes: []string{"read"},
+	})
+	s.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+
+	var created userdto.CreateAccessTokenResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &created))
+	s.Require().NotEmpty(created.Token)
+
+	s.tokenRepo.On("GetByHash", mock.Anything, stored.TokenHash).Return(stored, nil)
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.tokenRepo.On("UpdateLastUsed", mock.Anything, stored.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	w = s.request(http.MethodGet, "/api/v1/whoami", created.Token, nil)
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(s.T(), w.Body.String(), s.user.ID.String())
+
+	// Tokens cannot be used to manage tokens
+	w = s.request(http.MethodGet, "/api/v1/users/tokens", created.Token, nil)
+	assert.Equal(s.T(), http.StatusForbidden, w.Code)
+}
+
+func (s *AccessTokenHandlerTestSuite) TestCreate_InvalidScope() {
+	w := s.request(http.MethodPost, "/api/v1/users/tokens", s.jwt, gin.H{
+		"name":   "CI",
+		"scopes": []string{"admin"},
+	})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *AccessTokenHandlerTestSuite) TestCreate_NameTaken() {
+	s.tokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.PersonalAccessToken")).
+		Return(nil, userrepo.ErrAccessTokenNameTaken)
+
+	w := s.request(http.MethodPost, "/api/v1/users/tokens", s.jwt, userdto.CreateAccessTokenRequest{
+		Name:   "CI",
+		Scopes: []string{"write"},
+	})
+
+	assert.Equal(s.T(), http.StatusConflict, w.Code)
+}
+
+func (s *AccessTokenHandlerTestSuite) TestList() {
+	s.tokenRepo.On("ListByUser", mock.Anything, s.user.ID).Return([]*userentity.PersonalAccessToken{
+		{ID: uuid.New(), UserID: s.user.ID, Name: "CI", TokenPrefix: "rmp_abcdefgh", Scopes: []string{"read"}},
+	}, nil)
+
+	w := s.request(http.MethodGet, "/api/v1/users/tokens", s.jwt, nil)
+
+	s.Require().Equal(http.StatusOK, w.Code)
+	var response userdto.ListAccessTokensResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Require().Len(response.AccessTokens, 1)
+	assert.Equal(s.T(), "rmp_abcdefgh", response.AccessTokens[0].TokenPrefix)
+	assert.NotContains(s.T(), w.Body.String(), "token_hash")
+}
+
+func (s *AccessTokenHandlerTestSuite) TestDelete() {
+	tokenID := uuid.New()
+	s.tokenRepo.On("Delete", mock.Anything, tokenID, s.user.ID).Return(nil).Once()
+
+	w := s.request(http.MethodDelete, "/api/v1/users/tokens/"+tokenID.String(), s.jwt, nil)
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	s.tokenRepo.On("Delete", mock.Anything, tokenID, s.user.ID).Return(userrepo.ErrAccessTokenNotFound).Once()
+
+	w = s.request(http.MethodDelete, "/api/v1/users/tokens/"+tokenID.String(), s.jwt, nil)
+	assert.Equal(s.T(), http.StatusNotFound, w.Code)
+}
+
+func TestAccessTokenHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(AccessTokenHandlerTestSuite))
+}
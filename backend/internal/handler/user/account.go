@@ -0,0 +1,190 @@
+package userhandler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+// Formats of GET /users/me/export, chosen with the format query parameter.
+const (
+	exportFormatZIP  = "zip"
+	exportFormatJSON = "json"
+)
+
+type AccountHandler struct {
+	deleteUseCase *userusecase.DeleteAccountUseCase
+	exportUseCase *userusecase.ExportAccountDataUseCase
+}
+
+func NewAccountHandler(
+	deleteUseCase *userusecase.DeleteAccountUseCase,
+	exportUseCase *userusecase.ExportAccountDataUseCase,
+) *AccountHandler {
+	return &AccountHandler{
+		deleteUseCase: deleteUseCase,
+		exportUseCase: exportUseCase,
+	}
+}
+
+func (h *AccountHandler) Delete(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	var req userdto.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var sessionID string
+	if claims, exists := middleware.GetClaims(c); exists {
+		sessionID = claims.SessionID
+	}
+
+	response, err := h.deleteUseCase.Execute(c.Request.Context(), userID, sessionID, req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to delete account"
+
+		if errors.Is(err, userusecase.ErrIncorrectPassword) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Password is incorrect"
+		} else if errors.Is(err, userusecase.ErrReauthenticationRequired) {
+			statusCode = http.StatusForbidden
+			errorMessage = "Sign in again to delete your account"
+		} else if errors.Is(err, userusecase.ErrUserNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "User not found"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	clearAuthCookies(c)
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AccountHandler) Export(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", exportFormatZIP)
+	if format != exportFormatZIP && format != exportFormatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unsupported export format, use zip or json",
+		})
+		return
+	}
+
+	export, err := h.exportUseCase.Execute(c.Request.Context(), userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to export account data"
+
+		if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("account-export-%s.%s", export.ExportedAt.Format("20060102T150405Z"), format)
+
+	if format == exportFormatJSON {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.JSON(http.StatusOK, export)
+		return
+	}
+
+	archive, err := zipAccountExport(export)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to export account data",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// zipAccountExport writes every section of export to its own JSON file, next
+// to an export.json describing the archive.
+func zipAccountExport(export *userdto.AccountExport) ([]byte, error) {
+	sections := make([]string, 0, len(export.Sections))
+	for section := range export.Sections {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	writeFile := func(name string, data any) error {
+		w, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	}
+
+	manifest := gin.H{
+		"user_id":     export.UserID,
+		"exported_at": export.ExportedAt,
+		"sections":    sections,
+	}
+	if err := writeFile("export.json", manifest); err != nil {
+		return nil, err
+	}
+	for _, section := range sections {
+		if err := writeFile(section+".json", export.Sections[section]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
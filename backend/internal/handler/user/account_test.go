@@ -0,0 +1,221 @@
+package userhandler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/handler/middleware"
+	jwtservice "roadmap/internal/pkg/jwt"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type AccountHandlerTestSuite struct {
+	suite.Suite
+	userRepo     *MockUserRepository
+	refreshRepo  *MockRefreshTokenRepository
+	sessionRepo  *MockSessionRepository
+	passkeyRepo  *MockPasskeyRepository
+	identityRepo *MockUserIdentityRepository
+	tokenRepo    *MockPersonalAccessTokenRepository
+	user         *userentity.User
+	jwtService   *jwtservice.JWTService
+	jwt          string
+	router       *gin.Engine
+}
+
+func (s *AccountHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.userRepo = new(MockUserRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.sessionRepo = new(MockSessionRepository)
+	s.passkeyRepo = new(MockPasskeyRepository)
+	s.identityRepo = new(MockUserIdentityRepository)
+	s.tokenRepo = new(MockPersonalAccessTokenRepository)
+
+	passwordHash, _ := testPasswordHasher.Hash("SecurePass123!")
+	s.user = &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser",
+		Email:        "test@example.com",
+		PasswordHash: passwordHash,
+	}
+
+	s.jwtService = jwtservice.NewJWTService("test-secret", 15*time.Minute)
+	var err error
+	s.jwt, err = s.jwtService.GenerateToken(s.user.ID.String(), s.user.Username, s.user.Email)
+	s.Require().NoError(err)
+
+	hooks := userusecase.NewAccountDataHooks()
+	handler := NewAccountHandler(
+		userusecase.NewDeleteAccountUseCase(
+			s.userRepo,
+			s.refreshRepo,
+			s.sessionRepo,
+			testPasswordHasher,
+			userusecase.NewSuspensionService(s.userRepo, time.Minute),
+			30*24*time.Hour,
+		),
+		userusecase.NewExportAccountDataUseCase(s.userRepo, s.sessionRepo, s.passkeyRepo, s.identityRepo, s.tokenRepo, hooks),
+	)
+
+	s.router = gin.New()
+	SetupAccountRoutes(s.router.Group("/api/v1"), handler, middleware.AuthMiddleware(s.jwtService))
+}
+
+func (s *AccountHandlerTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.sessionRepo.AssertExpectations(s.T())
+	s.passkeyRepo.AssertExpectations(s.T())
+	s.identityRepo.AssertExpectations(s.T())
+	s.tokenRepo.AssertExpectations(s.T())
+}
+
+func (s *AccountHandlerTestSuite) do(method, path string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+s.jwt)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *AccountHandlerTestSuite) expectExport() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.sessionRepo.On("ListActiveByUser", mock.Anything, s.user.ID, mock.AnythingOfType("time.Time")).
+		Return([]*userentity.Session{}, nil)
+	s.passkeyRepo.On("ListByUser", mock.Anything, s.user.ID).Return([]*userentity.Passkey{}, nil)
+	s.identityRepo.On("ListByUser", mock.Anything, s.user.ID).Return([]*userentity.UserIdentity{}, nil)
+	s.tokenRepo.On("ListByUser", mock.Anything, s.user.ID).Return([]*userentity.PersonalAccessToken{}, nil)
+}
+
+func (s *AccountHandlerTestSuite) TestDelete_Success() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("SoftDelete", mock.Anything, s.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("RevokeAllForUser", mock.Anything, s.user.ID).Return(nil)
+
+	body, _ := json.Marshal(userdto.DeleteAccountRequest{Password: "SecurePass123!"})
+	w := s.do(http.MethodDelete, "/api/v1/users/me", body)
+
+	s.Equal(http.StatusOK, w.Code)
+
+	var response userdto.DeleteAccountResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.True(response.PurgeAt.After(time.Now().Add(29 * 24 * time.Hour)))
+}
+
+func (s *AccountHandlerTestSuite) TestDelete_IncorrectPassword() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+
+	body, _ := json.Marshal(userdto.DeleteAccountRequest{Password: "wrong-password"})
+	w := s.do(http.MethodDelete, "/api/v1/users/me", body)
+
+	s.Equal(http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal("Password is incorrect", response["error"])
+}
+
+func (s *AccountHandlerTestSuite) TestDelete_MissingPassword() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+
+	w := s.do(http.MethodDelete, "/api/v1/users/me", []byte("{}"))
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *AccountHandlerTestSuite) TestDelete_WithoutPassword() {
+	s.user.PasswordHash = ""
+	session := &userentity.Session{ID: uuid.New(), UserID: s.user.ID, CreatedAt: time.Now().UTC().Add(-time.Hour)}
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.sessionRepo.On("ListActiveByUser", mock.Anything, s.user.ID, mock.AnythingOfType("time.Time")).
+		Return([]*userentity.Session{session}, nil)
+
+	var err error
+	s.jwt, err = s.jwtService.GenerateTokenWithClaims(jwtservice.Claims{
+		UserID:    s.user.ID.String(),
+		Username:  s.user.Username,
+		Email:     s.user.Email,
+		SessionID: session.ID.String(),
+	})
+	s.Require().NoError(err)
+
+	w := s.do(http.MethodDelete, "/api/v1/users/me", []byte("{}"))
+
+	s.Equal(http.StatusForbidden, w.Code)
+
+	var response map[string]interface{}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal("Sign in again to delete your account", response["error"])
+}
+
+func (s *AccountHandlerTestSuite) TestExport_ZIP() {
+	s.expectExport()
+
+	w := s.do(http.MethodGet, "/api/v1/users/me/export", nil)
+
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.Equal("application/zip", w.Header().Get("Content-Type"))
+	s.Contains(w.Header().Get("Content-Disposition"), "attachment")
+
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	s.Require().NoError(err)
+
+	files := make(map[string][]byte)
+	for _, file := range archive.File {
+		r, err := file.Open()
+		s.Require().NoError(err)
+		content, err := io.ReadAll(r)
+		s.Require().NoError(err)
+		s.Require().NoError(r.Close())
+		files[file.Name] = content
+	}
+
+	s.Contains(files, "export.json")
+	s.Contains(files, "sessions.json")
+	s.Contains(files, "passkeys.json")
+	s.Contains(files, "identities.json")
+	s.Contains(files, "access_tokens.json")
+
+	var account userdto.ProfileResponse
+	s.Require().NoError(json.Unmarshal(files["account.json"], &account))
+	s.Equal(s.user.Email, account.Email)
+}
+
+func (s *AccountHandlerTestSuite) TestExport_JSON() {
+	s.expectExport()
+
+	w := s.do(http.MethodGet, "/api/v1/users/me/export?format=json", nil)
+
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	var export map[string]interface{}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &export))
+	s.Equal(s.user.ID.String(), export["user_id"])
+	s.Contains(export["sections"], userusecase.ExportSectionAccount)
+}
+
+func (s *AccountHandlerTestSuite) TestExport_UnsupportedFormat() {
+	w := s.do(http.MethodGet, "/api/v1/users/me/export?format=xml", nil)
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func TestAccountHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountHandlerTestSuite))
+}
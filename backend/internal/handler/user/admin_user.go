@@ -0,0 +1,223 @@
+package userhandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type AdminUserHandler struct {
+	listUsersUseCase          *userusecase.ListUsersUseCase
+	getUserUseCase            *userusecase.GetUserUseCase
+	suspendUserUseCase        *userusecase.SuspendUserUseCase
+	unsuspendUserUseCase      *userusecase.UnsuspendUserUseCase
+	forcePasswordResetUseCase *userusecase.ForcePasswordResetUseCase
+	deleteUserUseCase         *userusecase.DeleteUserUseCase
+}
+
+func NewAdminUserHandler(
+	listUsersUseCase *userusecase.ListUsersUseCase,
+	getUserUseCase *userusecase.GetUserUseCase,
+	suspendUserUseCase *userusecase.SuspendUserUseCase,
+	unsuspendUserUseCase *userusecase.UnsuspendUserUseCase,
+	forcePasswordResetUseCase *userusecase.ForcePasswordResetUseCase,
+	deleteUserUseCase *userusecase.DeleteUserUseCase,
+) *AdminUserHandler {
+	return &AdminUserHandler{
+		listUsersUseCase:          listUsersUseCase,
+		getUserUseCase:            getUserUseCase,
+		suspendUserUseCase:        suspendUserUseCase,
+		unsuspendUserUseCase:      unsuspendUserUseCase,
+		forcePasswordResetUseCase: forcePasswordResetUseCase,
+		deleteUserUseCase:         deleteUserUseCase,
+	}
+}
+
+func (h *AdminUserHandler) ListUsers(c *gin.Context) {
+	var req userdto.ListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.listUsersUseCase.Execute(c.Request.Context(), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to list users"
+
+		if errors.Is(err, userusecase.ErrInvalidCreatedRange) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "created_before must be later than created_after"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AdminUserHandler) GetUser(c *gin.Context) {
+	response, err := h.getUserUseCase.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to get user"
+
+		if errors.Is(err, userusecase.ErrUserNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "User not found"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AdminUserHandler) SuspendUser(c *gin.Context) {
+	actorID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	var req userdto.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.suspendUserUseCase.Execute(c.Request.Context(), actorID, c.Param("id"), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to suspend user"
+
+		if errors.Is(err, userusecase.ErrUserNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "User not found"
+		} else if errors.Is(err, userusecase.ErrCannotModifySelf) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "You cannot suspend your own account"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AdminUserHandler) UnsuspendUser(c *gin.Context) {
+	response, err := h.unsuspendUserUseCase.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to unsuspend user"
+
+		if errors.Is(err, userusecase.ErrUserNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "User not found"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AdminUserHandler) ForcePasswordReset(c *gin.Context) {
+	actorID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	err := h.forcePasswordResetUseCase.Execute(c.Request.Context(), actorID, c.Param("id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to reset password"
+
+		if errors.Is(err, userusecase.ErrUserNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "User not found"
+		} else if errors.Is(err, userusecase.ErrCannotModifySelf) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Use the change password endpoint for your own account"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password has been reset and a reset link sent to the user",
+	})
+}
+
+func (h *AdminUserHandler) DeleteUser(c *gin.Context) {
+	actorID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	err := h.deleteUserUseCase.Execute(c.Request.Context(), actorID, c.Param("id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to delete user"
+
+		if errors.Is(err, userusecase.ErrUserNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "User not found"
+		} else if errors.Is(err, userusecase.ErrCannotModifySelf) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "You cannot delete your own account"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User deleted",
+	})
+}
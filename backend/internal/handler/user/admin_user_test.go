@@ -0,0 +1,184 @@
+package userhandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	userrepo "roadmap/internal/repository/user"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type AdminUserHandlerTestSuite struct {
+	suite.Suite
+	router         *gin.Engine
+	userRepo       *MockUserRepository
+	refreshRepo    *MockRefreshTokenRepository
+	revocationRepo *MockTokenRevocationRepository
+	resetRepo      *MockPasswordResetTokenRepository
+	mailer         *MockMailer
+	admin          *userentity.User
+	user           *userentity.User
+}
+
+func (s *AdminUserHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.userRepo = new(MockUserRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.revocationRepo = new(MockTokenRevocationRepository)
+	s.resetRepo = new(MockPasswordResetTokenRepository)
+	s.mailer = new(MockMailer)
+
+	s.admin = &userentity.User{ID: uuid.New(), Roles: []string{userentity.RoleAdmin, userentity.RoleMember}}
+	s.user = &userentity.User{
+		ID:        uuid.New(),
+		Username:  "testuser",
+		Email:     "test@example.com",
+		Roles:     []string{userentity.RoleMember},
+		CreatedAt: time.Now(),
+	}
+
+	suspensionService := userusecase.NewSuspensionService(s.userRepo, time.Minute)
+	handler := NewAdminUserHandler(
+		userusecase.NewListUsersUseCase(s.userRepo),
+		userusecase.NewGetUserUseCase(s.userRepo),
+		userusecase.NewSuspendUserUseCase(s.userRepo, suspensionService, s.refreshRepo),
+		userusecase.NewUnsuspendUserUseCase(s.userRepo, suspensionService),
+		userusecase.NewForcePasswordResetUseCase(
+			s.userRepo,
+			s.refreshRepo,
+			userusecase.NewTokenRevocationService(s.revocationRepo, time.Minute),
+			userusecase.NewPasswordResetSender(s.resetRepo, s.mailer, "http://localhost:3000/reset-password", time.Hour),
+		),
+		userusecase.NewDeleteUserUseCase(s.userRepo, suspensionService),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.admin.ID.String())
+		c.Set("claims", &jwtservice.Claims{
+			UserID:      s.admin.ID.String(),
+			Permissions: []string{userentity.PermissionUsersRead, userentity.PermissionUsersManage},
+		})
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupAdminUserRoutes(s.router.Group("/api/v1"), handler, authMiddleware)
+}
+
+func (s *AdminUserHandlerTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.revocationRepo.AssertExpectations(s.T())
+	s.resetRepo.AssertExpectations(s.T())
+	s.mailer.AssertExpectations(s.T())
+}
+
+func (s *AdminUserHandlerTestSuite) request(method, path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *AdminUserHandlerTestSuite) TestListUsers() {
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.userRepo.On("List", mock.Anything, mock.MatchedBy(func(filter userrepo.UserListFilter) bool {
+		return filter.Email == "example.com" && filter.CreatedAfter != nil && filter.CreatedAfter.Equal(after) &&
+			filter.Limit == 5 && filter.Offset == 5
+	})).Return([]*userentity.User{s.user}, 6, nil)
+
+	w := s.request(http.MethodGet,
+		"/api/v1/admin/users?email=example.com&created_after=2024-01-01T00:00:00Z&page=2&page_size=5", nil)
+
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var response userdto.ListUsersResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(s.T(), 6, response.Total)
+	s.Require().Len(response.Users, 1)
+	assert.Equal(s.T(), s.user.Email, response.Users[0].Email)
+}
+
+func (s *AdminUserHandlerTestSuite) TestListUsers_InvalidQuery() {
+	w := s.request(http.MethodGet, "/api/v1/admin/users?page_size=1000", nil)
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+
+	w = s.request(http.MethodGet, "/api/v1/admin/users?created_after=yesterday", nil)
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+
+	w = s.request(http.MethodGet,
+		"/api/v1/admin/users?created_after=2024-02-01T00:00:00Z&created_before=2024-01-01T00:00:00Z", nil)
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *AdminUserHandlerTestSuite) TestGetUser_NotFound() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(nil, fmt.Errorf("user not found: %w", pgx.ErrNoRows))
+
+	w := s.request(http.MethodGet, "/api/v1/admin/users/"+s.user.ID.String(), nil)
+
+	assert.Equal(s.T(), http.StatusNotFound, w.Code)
+}
+
+func (s *AdminUserHandlerTestSuite) TestSuspendUser() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("Suspend", mock.Anything, s.user.ID, "spam", mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("RevokeAllForUser", mock.Anything, s.user.ID).Return(nil)
+
+	w := s.request(http.MethodPost, "/api/v1/admin/users/"+s.user.ID.String()+"/suspend",
+		userdto.SuspendUserRequest{Reason: "spam"})
+
+	assert.Equal(s.T(), http.StatusOK, w.Code, w.Body.String())
+}
+
+func (s *AdminUserHandlerTestSuite) TestSuspendUser_Self() {
+	s.userRepo.On("GetByID", mock.Anything, s.admin.ID).Return(s.admin, nil)
+
+	w := s.request(http.MethodPost, "/api/v1/admin/users/"+s.admin.ID.String()+"/suspend", userdto.SuspendUserRequest{})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *AdminUserHandlerTestSuite) TestForcePasswordReset() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("UpdatePassword", mock.Anything, s.user.ID, "").Return(nil)
+	s.revocationRepo.On("SetTokensValidAfter", mock.Anything, s.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("RevokeAllForUser", mock.Anything, s.user.ID).Return(nil)
+	s.resetRepo.On("InvalidateForUser", mock.Anything, s.user.ID).Return(nil)
+	s.resetRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.PasswordResetToken")).
+		Return(&userentity.PasswordResetToken{}, nil)
+	s.mailer.On("Send", mock.Anything, mock.AnythingOfType("mailer.Message")).Return(nil)
+
+	w := s.request(http.MethodPost, "/api/v1/admin/users/"+s.user.ID.String()+"/password-reset", nil)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code, w.Body.String())
+}
+
+func (s *AdminUserHandlerTestSuite) TestDeleteUser() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("Delete", mock.Anything, s.user.ID).Return(nil)
+
+	w := s.request(http.MethodDelete, "/api/v1/admin/users/"+s.user.ID.String(), nil)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestAdminUserHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminUserHandlerTestSuite))
+}
@@ -0,0 +1,48 @@
+package userhandler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+)
+
+// setAuthCookies stores issued tokens in cookies when cookie authentication
+// is enabled. The tokens stay in the response body either way, for clients
+// that send them in the Authorization header. It responds with an error and
+// returns false if the cookies cannot be set.
+func setAuthCookies(c *gin.Context, tokens userdto.TokenPair) bool {
+	cookieAuth, enabled := middleware.GetCookieAuth(c)
+	if !enabled {
+		return true
+	}
+
+	err := cookieAuth.SetTokens(c, tokens.Token, tokens.TokenExpiresAt, tokens.RefreshToken, tokens.RefreshTokenExpiresAt)
+	if err != nil {
+		log.Printf("Failed to set auth cookies: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to login",
+		})
+		return false
+	}
+	return true
+}
+
+func clearAuthCookies(c *gin.Context) {
+	if cookieAuth, enabled := middleware.GetCookieAuth(c); enabled {
+		cookieAuth.Clear(c)
+	}
+}
+
+// refreshTokenCookie returns the refresh token cookie when cookie
+// authentication is enabled and the request carries one.
+func refreshTokenCookie(c *gin.Context) (string, bool) {
+	cookieAuth, enabled := middleware.GetCookieAuth(c)
+	if !enabled {
+		return "", false
+	}
+	return cookieAuth.RefreshToken(c)
+}
@@ -0,0 +1,142 @@
+package userhandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type EmailChangeHandler struct {
+	requestEmailChangeUseCase *userusecase.RequestEmailChangeUseCase
+	confirmEmailChangeUseCase *userusecase.ConfirmEmailChangeUseCase
+	cancelEmailChangeUseCase  *userusecase.CancelEmailChangeUseCase
+}
+
+func NewEmailChangeHandler(
+	requestEmailChangeUseCase *userusecase.RequestEmailChangeUseCase,
+	confirmEmailChangeUseCase *userusecase.ConfirmEmailChangeUseCase,
+	cancelEmailChangeUseCase *userusecase.CancelEmailChangeUseCase,
+) *EmailChangeHandler {
+	return &EmailChangeHandler{
+		requestEmailChangeUseCase: requestEmailChangeUseCase,
+		confirmEmailChangeUseCase: confirmEmailChangeUseCase,
+		cancelEmailChangeUseCase:  cancelEmailChangeUseCase,
+	}
+}
+
+func (h *EmailChangeHandler) RequestEmailChange(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	var req userdto.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.requestEmailChangeUseCase.Execute(c.Request.Context(), userID, req); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to request email change"
+
+		if errors.Is(err, userusecase.ErrIncorrectPassword) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Password is incorrect"
+		} else if errors.Is(err, userusecase.ErrEmailUnchanged) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "New email must differ from the current email"
+		} else if errors.Is(err, userusecase.ErrEmailAlreadyExists) {
+			statusCode = http.StatusConflict
+			errorMessage = "Email already exists"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Confirmation link sent to the new email address",
+	})
+}
+
+func (h *EmailChangeHandler) ConfirmEmailChange(c *gin.Context) {
+	var req userdto.EmailChangeTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.confirmEmailChangeUseCase.Execute(c.Request.Context(), req); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to confirm email change"
+
+		if errors.Is(err, userusecase.ErrInvalidEmailChangeToken) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Invalid or expired email change token"
+		} else if errors.Is(err, userusecase.ErrEmailAlreadyExists) {
+			statusCode = http.StatusConflict
+			errorMessage = "Email already exists"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email address changed, please sign in again",
+	})
+}
+
+func (h *EmailChangeHandler) CancelEmailChange(c *gin.Context) {
+	var req userdto.EmailChangeTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.cancelEmailChangeUseCase.Execute(c.Request.Context(), req); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to cancel email change"
+
+		if errors.Is(err, userusecase.ErrInvalidEmailChangeToken) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Invalid or expired email change token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email change cancelled",
+	})
+}
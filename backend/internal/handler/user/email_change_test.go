@@ -0,0 +1,205 @@
+package userhandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type MockEmailChangeRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailChangeRequestRepository) Create(
+	ctx context.Context,
+	request *userentity.EmailChangeRequest,
+) (*userentity.EmailChangeRequest, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.EmailChangeRequest), args.Error(1)
+}
+
+func (m *MockEmailChangeRequestRepository) GetByConfirmHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.EmailChangeRequest, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.EmailChangeRequest), args.Error(1)
+}
+
+func (m *MockEmailChangeRequestRepository) GetByCancelHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.EmailChangeRequest, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.EmailChangeRequest), args.Error(1)
+}
+
+func (m *MockEmailChangeRequestRepository) MarkConfirmed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEmailChangeRequestRepository) MarkCancelled(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEmailChangeRequestRepository) CancelPendingForUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type EmailChangeHandlerTestSuite struct {
+	suite.Suite
+	router         *gin.Engine
+	userRepo       *MockUserRepository
+	changeRepo     *MockEmailChangeRequestRepository
+	resetRepo      *MockPasswordResetTokenRepository
+	revocationRepo *MockTokenRevocationRepository
+	mailer         *MockMailer
+	user           *userentity.User
+}
+
+func (s *EmailChangeHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.userRepo = new(MockUserRepository)
+	s.changeRepo = new(MockEmailChangeRequestRepository)
+	s.resetRepo = new(MockPasswordResetTokenRepository)
+	s.revocationRepo = new(MockTokenRevocationRepository)
+	s.mailer = new(MockMailer)
+
+	passwordHash, _ := testPasswordHasher.Hash("Password123!")
+	s.user = &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser",
+		Email:        "old@example.com",
+		PasswordHash: passwordHash,
+	}
+
+	handler := NewEmailChangeHandler(
+		userusecase.NewRequestEmailChangeUseCase(s.userRepo, testPasswordHasher, s.changeRepo, s.mailer, userusecase.EmailChangeLinks{
+			ConfirmURL: "http://localhost:3000/confirm-email-change",
+			CancelURL:  "http://localhost:3000/cancel-email-change",
+		}, time.Hour),
+		userusecase.NewConfirmEmailChangeUseCase(
+			s.userRepo, s.changeRepo, s.resetRepo, userusecase.NewTokenRevocationService(s.revocationRepo, time.Minute),
+		),
+		userusecase.NewCancelEmailChangeUseCase(s.changeRepo),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.user.ID.String())
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupEmailChangeRoutes(s.router.Group("/api/v1"), handler, authMiddleware)
+}
+
+func (s *EmailChangeHandlerTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.changeRepo.AssertExpectations(s.T())
+	s.resetRepo.AssertExpectations(s.T())
+	s.revocationRepo.AssertExpectations(s.T())
+	s.mailer.AssertExpectations(s.T())
+}
+
+func (s *EmailChangeHandlerTestSuite) post(path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *EmailChangeHandlerTestSuite) TestRequestEmailChange_Success() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("EmailExists", mock.Anything, "new@example.com").Return(false, nil)
+	s.changeRepo.On("CancelPendingForUser", mock.Anything, s.user.ID).Return(nil)
+	s.changeRepo.On("Create", mock.Anything, mock.Anything).Return(&userentity.EmailChangeRequest{}, nil)
+	s.mailer.On("Send", mock.Anything, mock.Anything).Return(nil).Twice()
+
+	w := s.post("/api/v1/users/email/change", userdto.ChangeEmailRequest{
+		NewEmail: "new@example.com",
+		Password: "Password123!",
+	})
+
+	assert.Equal(s.T(), http.StatusAccepted, w.Code)
+}
+
+func (s *EmailChangeHandlerTestSuite) TestRequestEmailChange_EmailTaken() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("EmailExists", mock.Anything, "new@example.com").Return(true, nil)
+
+	w := s.post("/api/v1/users/email/change", userdto.ChangeEmailRequest{
+		NewEmail: "new@example.com",
+		Password: "Password123!",
+	})
+
+	assert.Equal(s.T(), http.StatusConflict, w.Code)
+}
+
+func (s *EmailChangeHandlerTestSuite) TestRequestEmailChange_InvalidEmail() {
+	w := s.post("/api/v1/users/email/change", map[string]string{"new_email": "nope", "password": "Password123!"})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *EmailChangeHandlerTestSuite) TestConfirmEmailChange_Success() {
+	request := &userentity.EmailChangeRequest{
+		ID:        uuid.New(),
+		UserID:    s.user.ID,
+		OldEmail:  s.user.Email,
+		NewEmail:  "new@example.com",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	s.changeRepo.On("GetByConfirmHash", mock.Anything, mock.Anything).Return(request, nil)
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("EmailExists", mock.Anything, "new@example.com").Return(false, nil)
+	s.changeRepo.On("MarkConfirmed", mock.Anything, request.ID).Return(nil)
+	s.userRepo.On("UpdateEmail", mock.Anything, s.user.ID, "new@example.com").Return(nil)
+	s.resetRepo.On("InvalidateForUser", mock.Anything, s.user.ID).Return(nil)
+	s.revocationRepo.On("SetTokensValidAfter", mock.Anything, s.user.ID, mock.Anything).Return(nil)
+
+	w := s.post("/api/v1/users/email/change/confirm", userdto.EmailChangeTokenRequest{Token: "confirm-token"})
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+}
+
+func (s *EmailChangeHandlerTestSuite) TestCancelEmailChange_InvalidToken() {
+	s.changeRepo.On("GetByCancelHash", mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+
+	w := s.post("/api/v1/users/email/change/cancel", userdto.EmailChangeTokenRequest{Token: "bogus"})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func TestEmailChangeHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailChangeHandlerTestSuite))
+}
@@ -0,0 +1,103 @@
+package userhandler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type EmailVerificationHandler struct {
+	verifyEmailUseCase        *userusecase.VerifyEmailUseCase
+	resendVerificationUseCase *userusecase.ResendVerificationUseCase
+}
+
+func NewEmailVerificationHandler(
+	verifyEmailUseCase *userusecase.VerifyEmailUseCase,
+	resendVerificationUseCase *userusecase.ResendVerificationUseCase,
+) *EmailVerificationHandler {
+	return &EmailVerificationHandler{
+		verifyEmailUseCase:        verifyEmailUseCase,
+		resendVerificationUseCase: resendVerificationUseCase,
+	}
+}
+
+func (h *EmailVerificationHandler) VerifyEmail(c *gin.Context) {
+	var req userdto.VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.verifyEmailUseCase.Execute(c.Request.Context(), req); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to verify email"
+
+		if errors.Is(err, userusecase.ErrInvalidVerificationToken) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Invalid or expired verification token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email address verified",
+	})
+}
+
+func (h *EmailVerificationHandler) ResendVerification(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	if err := h.resendVerificationUseCase.Execute(c.Request.Context(), userID); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to resend verification email"
+
+		var throttledErr *userusecase.ThrottledError
+		if errors.As(err, &throttledErr) {
+			setRetryAfter(c, throttledErr)
+			statusCode = http.StatusTooManyRequests
+			errorMessage = "Too many verification emails requested, please try again later"
+		} else if errors.Is(err, userusecase.ErrEmailAlreadyVerified) {
+			statusCode = http.StatusConflict
+			errorMessage = "Email address is already verified"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Verification email sent",
+	})
+}
+
+// setRetryAfter sets the Retry-After header in whole seconds, rounded up.
+func setRetryAfter(c *gin.Context, err *userusecase.ThrottledError) {
+	seconds := int(math.Ceil(err.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(max(seconds, 1)))
+}
@@ -0,0 +1,189 @@
+package userhandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type MockEmailVerificationTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationTokenRepository) Create(
+	ctx context.Context,
+	token *userentity.EmailVerificationToken,
+) (*userentity.EmailVerificationToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.EmailVerificationToken), args.Error(1)
+}
+
+func (m *MockEmailVerificationTokenRepository) GetByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.EmailVerificationToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.EmailVerificationToken), args.Error(1)
+}
+
+func (m *MockEmailVerificationTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationTokenRepository) CreatedSince(
+	ctx context.Context,
+	userID uuid.UUID,
+	since time.Time,
+) ([]time.Time, error) {
+	args := m.Called(ctx, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+type EmailVerificationHandlerTestSuite struct {
+	suite.Suite
+	router     *gin.Engine
+	userRepo   *MockUserRepository
+	verifyRepo *MockEmailVerificationTokenRepository
+	mailer     *MockMailer
+	user       *userentity.User
+}
+
+func (s *EmailVerificationHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.userRepo = new(MockUserRepository)
+	s.verifyRepo = new(MockEmailVerificationTokenRepository)
+	s.mailer = new(MockMailer)
+	s.user = &userentity.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com"}
+
+	sender := userusecase.NewEmailVerificationSender(s.verifyRepo, s.mailer, "http://localhost:3000/verify-email", time.Hour)
+	handler := NewEmailVerificationHandler(
+		userusecase.NewVerifyEmailUseCase(s.userRepo, s.verifyRepo),
+		userusecase.NewResendVerificationUseCase(s.userRepo, s.verifyRepo, sender, userusecase.ResendPolicy{
+			Cooldown:     time.Minute,
+			MaxPerWindow: 5,
+			Window:       time.Hour,
+		}),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.user.ID.String())
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupEmailVerificationRoutes(s.router.Group("/api/v1"), handler, authMiddleware)
+}
+
+func (s *EmailVerificationHandlerTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.verifyRepo.AssertExpectations(s.T())
+	s.mailer.AssertExpectations(s.T())
+}
+
+func (s *EmailVerificationHandlerTestSuite) post(path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *EmailVerificationHandlerTestSuite) TestVerifyEmail_Success() {
+	token := &userentity.EmailVerificationToken{
+		ID:        uuid.New(),
+		UserID:    s.user.ID,
+		Email:     s.user.Email,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	s.verifyRepo.On("GetByHash", mock.Anything, mock.Anything).Return(token, nil)
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.verifyRepo.On("MarkUsed", mock.Anything, token.ID).Return(nil)
+	s.userRepo.On("MarkEmailVerified", mock.Anything, s.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	w := s.post("/api/v1/users/email/verify", userdto.VerifyEmailRequest{Token: "verify-token"})
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+}
+
+func (s *EmailVerificationHandlerTestSuite) TestVerifyEmail_InvalidToken() {
+	s.verifyRepo.On("GetByHash", mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+
+	w := s.post("/api/v1/users/email/verify", userdto.VerifyEmailRequest{Token: "bogus"})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *EmailVerificationHandlerTestSuite) TestVerifyEmail_MissingToken() {
+	w := s.post("/api/v1/users/email/verify", map[string]string{})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *EmailVerificationHandlerTestSuite) TestResendVerification_Success() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.verifyRepo.On("CreatedSince", mock.Anything, s.user.ID, mock.Anything).Return([]time.Time{}, nil)
+	s.verifyRepo.On("InvalidateForUser", mock.Anything, s.user.ID).Return(nil)
+	s.verifyRepo.On("Create", mock.Anything, mock.Anything).Return(&userentity.EmailVerificationToken{}, nil)
+	s.mailer.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	w := s.post("/api/v1/users/email/resend", nil)
+
+	assert.Equal(s.T(), http.StatusAccepted, w.Code)
+}
+
+func (s *EmailVerificationHandlerTestSuite) TestResendVerification_Throttled() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.verifyRepo.On("CreatedSince", mock.Anything, s.user.ID, mock.Anything).
+		Return([]time.Time{time.Now().UTC().Add(-10 * time.Second)}, nil)
+
+	w := s.post("/api/v1/users/email/resend", nil)
+
+	assert.Equal(s.T(), http.StatusTooManyRequests, w.Code)
+	assert.Equal(s.T(), "50", w.Header().Get("Retry-After"))
+}
+
+func (s *EmailVerificationHandlerTestSuite) TestResendVerification_AlreadyVerified() {
+	verifiedAt := time.Now()
+	s.user.EmailVerifiedAt = &verifiedAt
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+
+	w := s.post("/api/v1/users/email/resend", nil)
+
+	assert.Equal(s.T(), http.StatusConflict, w.Code)
+}
+
+func TestEmailVerificationHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailVerificationHandlerTestSuite))
+}
@@ -0,0 +1,189 @@
+package userhandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type OIDCHandler struct {
+	beginLoginUseCase  *userusecase.BeginOIDCLoginUseCase
+	finishLoginUseCase *userusecase.FinishOIDCLoginUseCase
+	beginLinkUseCase   *userusecase.BeginOIDCLinkUseCase
+	finishLinkUseCase  *userusecase.FinishOIDCLinkUseCase
+}
+
+func NewOIDCHandler(
+	beginLoginUseCase *userusecase.BeginOIDCLoginUseCase,
+	finishLoginUseCase *userusecase.FinishOIDCLoginUseCase,
+	beginLinkUseCase *userusecase.BeginOIDCLinkUseCase,
+	finishLinkUseCase *userusecase.FinishOIDCLinkUseCase,
+) *OIDCHandler {
+	return &OIDCHandler{
+		beginLoginUseCase:  beginLoginUseCase,
+		finishLoginUseCase: finishLoginUseCase,
+		beginLinkUseCase:   beginLinkUseCase,
+		finishLinkUseCase:  finishLinkUseCase,
+	}
+}
+
+func (h *OIDCHandler) BeginLogin(c *gin.Context) {
+	response, err := h.beginLoginUseCase.Execute(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to start sign-in"
+
+		if errors.Is(err, userusecase.ErrUnknownOIDCProvider) {
+			statusCode = http.StatusNotFound
+			errorMessage = "Unknown identity provider"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *OIDCHandler) FinishLogin(c *gin.Context) {
+	var req userdto.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.finishLoginUseCase.Execute(c.Request.Context(), c.Param("provider"), req, clientInfo(c))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to login"
+
+		if errors.Is(err, userusecase.ErrUnknownOIDCProvider) {
+			statusCode = http.StatusNotFound
+			errorMessage = "Unknown identity provider"
+		} else if errors.Is(err, userusecase.ErrInvalidOIDCState) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid or expired sign-in state"
+		} else if errors.Is(err, userusecase.ErrOIDCVerification) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Identity provider sign-in failed"
+		} else if errors.Is(err, userusecase.ErrOIDCEmailMissing) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Identity provider did not share an email address"
+		} else if errors.Is(err, userusecase.ErrEmailAlreadyExists) {
+			statusCode = http.StatusConflict
+			errorMessage = "An account with this email already exists, sign in and link the provider from your account"
+		} else if errors.Is(err, userusecase.ErrIdentityAlreadyLinked) {
+			statusCode = http.StatusConflict
+			errorMessage = "Identity already linked to an account"
+		} else if errors.Is(err, userusecase.ErrAccountSuspended) {
+			statusCode = http.StatusForbidden
+			errorMessage = "Account is suspended"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	if response.MFAChallenge != nil {
+		c.JSON(http.StatusOK, response.MFAChallenge)
+		return
+	}
+
+	if !setAuthCookies(c, response.TokenPair) {
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *OIDCHandler) BeginLink(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	response, err := h.beginLinkUseCase.Execute(c.Request.Context(), userID, c.Param("provider"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to start linking"
+
+		if errors.Is(err, userusecase.ErrUnknownOIDCProvider) {
+			statusCode = http.StatusNotFound
+			errorMessage = "Unknown identity provider"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *OIDCHandler) FinishLink(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	var req userdto.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.finishLinkUseCase.Execute(c.Request.Context(), userID, c.Param("provider"), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to link identity"
+
+		if errors.Is(err, userusecase.ErrUnknownOIDCProvider) {
+			statusCode = http.StatusNotFound
+			errorMessage = "Unknown identity provider"
+		} else if errors.Is(err, userusecase.ErrInvalidOIDCState) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Invalid or expired sign-in state"
+		} else if errors.Is(err, userusecase.ErrOIDCVerification) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Identity provider sign-in failed"
+		} else if errors.Is(err, userusecase.ErrIdentityAlreadyLinked) {
+			statusCode = http.StatusConflict
+			errorMessage = "Identity already linked to an account"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,287 @@
+package userhandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	"roadmap/internal/pkg/oidc"
+	"roadmap/internal/pkg/oidc/oidctest"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type MockUserIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserIdentityRepository) Create(
+	ctx context.Context,
+	identity *userentity.UserIdentity,
+) (*userentity.UserIdentity, error) {
+	args := m.Called(ctx, identity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.UserIdentity), args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) CreateWithUser(
+	ctx context.Context,
+	user *userentity.User,
+	identity *userentity.UserIdentity,
+) (*userentity.User, *userentity.UserIdentity, error) {
+	args := m.Called(ctx, user, identity)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*userentity.User), args.Get(1).(*userentity.UserIdentity), args.Error(2)
+}
+
+func (m *MockUserIdentityRepository) GetByProviderSubject(
+	ctx context.Context,
+	provider, subject string,
+) (*userentity.UserIdentity, error) {
+	args := m.Called(ctx, provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.UserIdentity), args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*userentity.UserIdentity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*userentity.UserIdentity), args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, lastLoginAt time.Time) error {
+	args := m.Called(ctx, id, lastLoginAt)
+	return args.Error(0)
+}
+
+type MockOIDCAuthRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockOIDCAuthRequestRepository) Create(
+	ctx context.Context,
+	request *userentity.OIDCAuthRequest,
+) (*userentity.OIDCAuthRequest, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.OIDCAuthRequest), args.Error(1)
+}
+
+func (m *MockOIDCAuthRequestRepository) GetByStateHash(
+	ctx context.Context,
+	stateHash string,
+) (*userentity.OIDCAuthRequest, error) {
+	args := m.Called(ctx, stateHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.OIDCAuthRequest), args.Error(1)
+}
+
+func (m *MockOIDCAuthRequestRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type OIDCHandlerTestSuite struct {
+	suite.Suite
+	router       *gin.Engine
+	server       *oidctest.Server
+	userRepo     *MockUserRepository
+	identityRepo *MockUserIdentityRepository
+	requestRepo  *MockOIDCAuthRequestRepository
+	refreshRepo  *MockRefreshTokenRepository
+	totpRepo     *MockTOTPRepository
+	user         *userentity.User
+}
+
+func (s *OIDCHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	var err error
+	s.server, err = oidctest.NewServer("roadmap", "secret")
+	s.Require().NoError(err)
+
+	provider, err := oidc.NewProvider(oidc.Config{
+		Name:         "mock",
+		Issuer:       s.server.URL,
+		ClientID:     "roadmap",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:3000/auth/oidc/mock/callback",
+	})
+	s.Require().NoError(err)
+
+	s.userRepo = new(MockUserRepository)
+	s.identityRepo = new(MockUserIdentityRepository)
+	s.requestRepo = new(MockOIDCAuthRequestRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.totpRepo = new(MockTOTPRepository)
+	s.totpRepo.On("GetByUserID", mock.Anything, mock.Anything).Return(nil, pgx.ErrNoRows).Maybe()
+
+	s.user = &userentity.User{
+		ID:       uuid.New(),
+		Username: "testuser",
+		Email:    "test@example.com",
+	}
+
+	flow := userusecase.NewOIDCFlow(userusecase.NewOIDCProviders(provider), s.requestRepo, 10*time.Minute)
+	tokenIssuer := userusecase.NewTokenIssuer(
+		jwtservice.NewJWTService("test-secret", 15*time.Minute),
+		s.refreshRepo,
+		nil,
+		30*24*time.Hour,
+	)
+
+	handler := NewOIDCHandler(
+		userusecase.NewBeginOIDCLoginUseCase(flow),
+		userusecase.NewFinishOIDCLoginUseCase(
+			s.userRepo,
+			s.identityRepo,
+			flow,
+			tokenIssuer,
+			userusecase.NewTwoFactorChallenger(s.totpRepo, new(MockMFAChallengeRepository), 5*time.Minute),
+			nil,
+		),
+		userusecase.NewBeginOIDCLinkUseCase(flow),
+		userusecase.NewFinishOIDCLinkUseCase(s.identityRepo, flow),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.user.ID.String())
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupOIDCRoutes(s.router.Group("/api/v1"), handler, authMiddleware)
+}
+
+func (s *OIDCHandlerTestSuite) TearDownTest() {
+	s.server.Close()
+	s.userRepo.AssertExpectations(s.T())
+	s.identityRepo.AssertExpectations(s.T())
+	s.requestRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+}
+
+func (s *OIDCHandlerTestSuite) request(method, path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// authorize calls the begin endpoint at path and lets the mock provider
+// approve the resulting authorization URL.
+func (s *OIDCHandlerTestSuite) authorize(path string) userdto.OIDCCallbackRequest {
+	var stored *userentity.OIDCAuthRequest
+	s.requestRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.OIDCAuthRequest")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*userentity.OIDCAuthRequest) }).
+		Return(&userentity.OIDCAuthRequest{ExpiresAt: time.Now().Add(10 * time.Minute)}, nil).Once()
+
+	w := s.request(http.MethodPost, path, nil)
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	var response userdto.OIDCAuthorizationResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+
+	code, state, err := s.server.Authorize(response.AuthorizationURL)
+	s.Require().NoError(err)
+
+	s.requestRepo.On("GetByStateHash", mock.Anything, mock.Anything).Return(stored, nil).Once()
+	s.requestRepo.On("MarkUsed", mock.Anything, stored.ID).Return(nil).Once()
+
+	return userdto.OIDCCallbackRequest{Code: code, State: state}
+}
+
+func (s *OIDCHandlerTestSuite) TestLogin_ExistingIdentity() {
+	callback := s.authorize("/api/v1/users/oidc/mock/login/begin")
+
+	identity := &userentity.UserIdentity{ID: uuid.New(), UserID: s.user.ID}
+	s.identityRepo.On("GetByProviderSubject", mock.Anything, "mock", "oidctest-user").Return(identity, nil)
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.identityRepo.On("UpdateLastLogin", mock.Anything, identity.ID, mock.Anything).Return(nil)
+	s.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
+
+	w := s.request(http.MethodPost, "/api/v1/users/oidc/mock/login/finish", callback)
+
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var response userdto.LoginResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(s.T(), response.Token)
+}
+
+func (s *OIDCHandlerTestSuite) TestLogin_EmailOfExistingAccount() {
+	callback := s.authorize("/api/v1/users/oidc/mock/login/begin")
+
+	s.identityRepo.On("GetByProviderSubject", mock.Anything, "mock", "oidctest-user").
+		Return(nil, fmt.Errorf("user identity not found: %w", pgx.ErrNoRows))
+	s.userRepo.On("EmailExists", mock.Anything, "oidc@example.com").Return(true, nil)
+
+	w := s.request(http.MethodPost, "/api/v1/users/oidc/mock/login/finish", callback)
+
+	assert.Equal(s.T(), http.StatusConflict, w.Code)
+}
+
+func (s *OIDCHandlerTestSuite) TestLogin_UnknownProvider() {
+	w := s.request(http.MethodPost, "/api/v1/users/oidc/unknown/login/begin", nil)
+
+	assert.Equal(s.T(), http.StatusNotFound, w.Code)
+}
+
+func (s *OIDCHandlerTestSuite) TestLogin_InvalidState() {
+	s.requestRepo.On("GetByStateHash", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("oidc auth request not found: %w", pgx.ErrNoRows))
+
+	w := s.request(http.MethodPost, "/api/v1/users/oidc/mock/login/finish", userdto.OIDCCallbackRequest{
+		Code:  "code",
+		State: "state",
+	})
+
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+}
+
+func (s *OIDCHandlerTestSuite) TestLink() {
+	callback := s.authorize("/api/v1/users/oidc/mock/link/begin")
+
+	s.identityRepo.On("GetByProviderSubject", mock.Anything, "mock", "oidctest-user").
+		Return(nil, fmt.Errorf("user identity not found: %w", pgx.ErrNoRows))
+	s.identityRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.UserIdentity")).
+		Return(&userentity.UserIdentity{ID: uuid.New(), Provider: "mock", Email: "oidc@example.com"}, nil)
+
+	w := s.request(http.MethodPost, "/api/v1/users/oidc/mock/link/finish", callback)
+
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var response userdto.UserIdentityResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(s.T(), "mock", response.Provider)
+}
+
+func TestOIDCHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCHandlerTestSuite))
+}
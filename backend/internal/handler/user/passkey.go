@@ -0,0 +1,228 @@
+package userhandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+	"roadmap/internal/pkg/webauthn"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type PasskeyHandler struct {
+	beginRegistrationUseCase  *userusecase.BeginPasskeyRegistrationUseCase
+	finishRegistrationUseCase *userusecase.FinishPasskeyRegistrationUseCase
+	beginLoginUseCase         *userusecase.BeginPasskeyLoginUseCase
+	finishLoginUseCase        *userusecase.FinishPasskeyLoginUseCase
+	listUseCase               *userusecase.ListPasskeysUseCase
+	deleteUseCase             *userusecase.DeletePasskeyUseCase
+}
+
+func NewPasskeyHandler(
+	beginRegistrationUseCase *userusecase.BeginPasskeyRegistrationUseCase,
+	finishRegistrationUseCase *userusecase.FinishPasskeyRegistrationUseCase,
+	beginLoginUseCase *userusecase.BeginPasskeyLoginUseCase,
+	finishLoginUseCase *userusecase.FinishPasskeyLoginUseCase,
+	listUseCase *userusecase.ListPasskeysUseCase,
+	deleteUseCase *userusecase.DeletePasskeyUseCase,
+) *PasskeyHandler {
+	return &PasskeyHandler{
+		beginRegistrationUseCase:  beginRegistrationUseCase,
+		finishRegistrationUseCase: finishRegistrationUseCase,
+		beginLoginUseCase:         beginLoginUseCase,
+		finishLoginUseCase:        finishLoginUseCase,
+		listUseCase:               listUseCase,
+		deleteUseCase:             deleteUseCase,
+	}
+}
+
+func (h *PasskeyHandler) BeginRegistration(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	options, err := h.beginRegistrationUseCase.Execute(c.Request.Context(), userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to start passkey registration"
+
+		if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+func (h *PasskeyHandler) FinishRegistration(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	var req userdto.FinishPasskeyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.finishRegistrationUseCase.Execute(c.Request.Context(), userID, req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to register passkey"
+
+		if errors.Is(err, userusecase.ErrInvalidPasskeyChallenge) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Invalid or expired passkey challenge"
+		} else if errors.Is(err, userusecase.ErrPasskeyVerification) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Passkey verification failed"
+		} else if errors.Is(err, userusecase.ErrPasskeyAlreadyRegistered) {
+			statusCode = http.StatusConflict
+			errorMessage = "Passkey already registered"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *PasskeyHandler) BeginLogin(c *gin.Context) {
+	options, err := h.beginLoginUseCase.Execute(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to start passkey login",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+func (h *PasskeyHandler) FinishLogin(c *gin.Context) {
+	var req webauthn.AssertionResponse
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.finishLoginUseCase.Execute(c.Request.Context(), req, clientInfo(c))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to login"
+
+		if errors.Is(err, userusecase.ErrInvalidPasskeyChallenge) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid or expired passkey challenge"
+		} else if errors.Is(err, userusecase.ErrPasskeyVerification) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Passkey verification failed"
+		} else if errors.Is(err, userusecase.ErrPasskeyCloned) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Passkey rejected, the authenticator may have been cloned"
+		} else if errors.Is(err, userusecase.ErrAccountSuspended) {
+			statusCode = http.StatusForbidden
+			errorMessage = "Account is suspended"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	if !setAuthCookies(c, response.TokenPair) {
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PasskeyHandler) List(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	response, err := h.listUseCase.Execute(c.Request.Context(), userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to list passkeys"
+
+		if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PasskeyHandler) Delete(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	if err := h.deleteUseCase.Execute(c.Request.Context(), userID, c.Param("id")); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to delete passkey"
+
+		if errors.Is(err, userusecase.ErrPasskeyNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "Passkey not found"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Passkey deleted",
+	})
+}
@@ -0,0 +1,261 @@
+package userhandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	"roadmap/internal/pkg/webauthn"
+	"roadmap/internal/pkg/webauthn/webauthntest"
+	userrepo "roadmap/internal/repository/user"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type MockPasskeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasskeyRepository) Create(ctx context.Context, passkey *userentity.Passkey) (*userentity.Passkey, error) {
+	args := m.Called(ctx, passkey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.Passkey), args.Error(1)
+}
+
+func (m *MockPasskeyRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*userentity.Passkey, error) {
+	args := m.Called(ctx, credentialID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.Passkey), args.Error(1)
+}
+
+func (m *MockPasskeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*userentity.Passkey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*userentity.Passkey), args.Error(1)
+}
+
+func (m *MockPasskeyRepository) UpdateSignCount(ctx context.Context, id uuid.UUID, signCount uint32, usedAt time.Time) error {
+	args := m.Called(ctx, id, signCount, usedAt)
+	return args.Error(0)
+}
+
+func (m *MockPasskeyRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+type MockWebAuthnChallengeRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebAuthnChallengeRepository) Create(
+	ctx context.Context,
+	challenge *userentity.WebAuthnChallenge,
+) (*userentity.WebAuthnChallenge, error) {
+	args := m.Called(ctx, challenge)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.WebAuthnChallenge), args.Error(1)
+}
+
+func (m *MockWebAuthnChallengeRepository) GetByHash(
+	ctx context.Context,
+	challengeHash string,
+) (*userentity.WebAuthnChallenge, error) {
+	args := m.Called(ctx, challengeHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.WebAuthnChallenge), args.Error(1)
+}
+
+func (m *MockWebAuthnChallengeRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type PasskeyHandlerTestSuite struct {
+	suite.Suite
+	router        *gin.Engine
+	userRepo      *MockUserRepository
+	refreshRepo   *MockRefreshTokenRepository
+	passkeyRepo   *MockPasskeyRepository
+	challengeRepo *MockWebAuthnChallengeRepository
+	authenticator *webauthntest.Authenticator
+	user          *userentity.User
+}
+
+func (s *PasskeyHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.userRepo = new(MockUserRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.passkeyRepo = new(MockPasskeyRepository)
+	s.challengeRepo = new(MockWebAuthnChallengeRepository)
+
+	s.user = &userentity.User{
+		ID:       uuid.New(),
+		Username: "testuser",
+		Email:    "test@example.com",
+	}
+
+	var err error
+	s.authenticator, err = webauthntest.NewAuthenticator("localhost", "http://localhost:3000")
+	s.Require().NoError(err)
+	s.authenticator.UserHandle = s.user.ID[:]
+
+	webAuthn, err := webauthn.New(webauthn.Config{
+		RPID:    "localhost",
+		RPName:  "Roadmap",
+		Origins: []string{"http://localhost:3000"},
+	})
+	s.Require().NoError(err)
+	ceremony := userusecase.NewPasskeyCeremony(s.challengeRepo, webAuthn)
+	tokenIssuer := userusecase.NewTokenIssuer(
+		jwtservice.NewJWTService("test-secret", 15*time.Minute),
+		s.refreshRepo,
+		nil,
+		30*24*time.Hour,
+	)
+
+	handler := NewPasskeyHandler(
+		userusecase.NewBeginPasskeyRegistrationUseCase(s.userRepo, s.passkeyRepo, ceremony),
+		userusecase.NewFinishPasskeyRegistrationUseCase(s.passkeyRepo, ceremony),
+		userusecase.NewBeginPasskeyLoginUseCase(ceremony),
+		userusecase.NewFinishPasskeyLoginUseCase(s.userRepo, s.passkeyRepo, ceremony, tokenIssuer),
+		userusecase.NewListPasskeysUseCase(s.passkeyRepo),
+		userusecase.NewDeletePasskeyUseCase(s.passkeyRepo),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.user.ID.String())
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupPasskeyRoutes(s.router.Group("/api/v1"), handler, authMiddleware)
+}
+
+func (s *PasskeyHandlerTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.passkeyRepo.AssertExpectations(s.T())
+	s.challengeRepo.AssertExpectations(s.T())
+}
+
+func (s *PasskeyHandlerTestSuite) request(method, path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *PasskeyHandlerTestSuite) TestRegisterAndLogin() {
+	var issued *userentity.WebAuthnChallenge
+	s.challengeRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.WebAuthnChallenge")).
+		Run(func(args mock.Arguments) { issued = args.Get(1).(*userentity.WebAuthnChallenge) }).
+		Return(&userentity.WebAuthnChallenge{}, nil)
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.passkeyRepo.On("ListByUser", mock.Anything, s.user.ID).Return([]*userentity.Passkey{}, nil)
+
+	w := s.request(http.MethodPost, "/api/v1/users/passkeys/register/begin", nil)
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	var creation webauthn.CreationOptions
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &creation))
+	assert.Equal(s.T(), "localhost", creation.PublicKey.RP.ID)
+
+	var stored *userentity.Passkey
+	s.challengeRepo.On("GetByHash", mock.Anything, issued.ChallengeHash).Return(issued, nil).Once()
+	s.challengeRepo.On("MarkUsed", mock.Anything, issued.ID).Return(nil).Once()
+	s.passkeyRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.Passkey")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*userentity.Passkey) }).
+		Return(&userentity.Passkey{ID: uuid.New(), Name: "Laptop"}, nil)
+
+	w = s.request(http.MethodPost, "/api/v1/users/passkeys/register/finish", map[string]interface{}{
+		"name":       "Laptop",
+		"credential": s.authenticator.Register(creation.PublicKey.Challenge),
+	})
+	s.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+
+	w = s.request(http.MethodPost, "/api/v1/users/passkeys/login/begin", nil)
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	var request webauthn.RequestOptions
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &request))
+
+	s.challengeRepo.On("GetByHash", mock.Anything, issued.ChallengeHash).Return(issued, nil).Once()
+	s.challengeRepo.On("MarkUsed", mock.Anything, issued.ID).Return(nil).Once()
+	s.passkeyRepo.On("GetByCredentialID", mock.Anything, mock.Anything).Return(stored, nil)
+	s.passkeyRepo.On("UpdateSignCount", mock.Anything, stored.ID, uint32(1), mock.Anything).Return(nil)
+	s.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
+
+	w = s.request(http.MethodPost, "/api/v1/users/passkeys/login/finish", s.authenticator.Assert(request.PublicKey.Challenge))
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	var response userdto.LoginResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(s.T(), response.Token)
+	assert.NotEmpty(s.T(), response.RefreshToken)
+}
+
+func (s *PasskeyHandlerTestSuite) TestFinishLogin_UnknownChallenge() {
+	s.challengeRepo.On("GetByHash", mock.Anything, mock.Anything).Return(nil, userrepo.ErrTokenAlreadyUsed)
+
+	w := s.request(http.MethodPost, "/api/v1/users/passkeys/login/finish", s.authenticator.Assert("never-issued"))
+
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+}
+
+func (s *PasskeyHandlerTestSuite) TestList() {
+	s.passkeyRepo.On("ListByUser", mock.Anything, s.user.ID).Return([]*userentity.Passkey{
+		{ID: uuid.New(), UserID: s.user.ID, Name: "Phone"},
+	}, nil)
+
+	w := s.request(http.MethodGet, "/api/v1/users/passkeys", nil)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response userdto.ListPasskeysResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Require().Len(response.Passkeys, 1)
+	assert.Equal(s.T(), "Phone", response.Passkeys[0].Name)
+}
+
+func (s *PasskeyHandlerTestSuite) TestDelete() {
+	passkeyID := uuid.New()
+	s.passkeyRepo.On("Delete", mock.Anything, passkeyID, s.user.ID).Return(nil).Once()
+
+	w := s.request(http.MethodDelete, "/api/v1/users/passkeys/"+passkeyID.String(), nil)
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	s.passkeyRepo.On("Delete", mock.Anything, passkeyID, s.user.ID).Return(userrepo.ErrPasskeyNotFound).Once()
+
+	w = s.request(http.MethodDelete, "/api/v1/users/passkeys/"+passkeyID.String(), nil)
+	assert.Equal(s.T(), http.StatusNotFound, w.Code)
+}
+
+func TestPasskeyHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(PasskeyHandlerTestSuite))
+}
@@ -0,0 +1,146 @@
+package userhandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type PasswordHandler struct {
+	forgotPasswordUseCase *userusecase.ForgotPasswordUseCase
+	resetPasswordUseCase  *userusecase.ResetPasswordUseCase
+	changePasswordUseCase *userusecase.ChangePasswordUseCase
+}
+
+func NewPasswordHandler(
+	forgotPasswordUseCase *userusecase.ForgotPasswordUseCase,
+	resetPasswordUseCase *userusecase.ResetPasswordUseCase,
+	changePasswordUseCase *userusecase.ChangePasswordUseCase,
+) *PasswordHandler {
+	return &PasswordHandler{
+		forgotPasswordUseCase: forgotPasswordUseCase,
+		resetPasswordUseCase:  resetPasswordUseCase,
+		changePasswordUseCase: changePasswordUseCase,
+	}
+}
+
+func (h *PasswordHandler) ForgotPassword(c *gin.Context) {
+	var req userdto.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.forgotPasswordUseCase.Execute(c.Request.Context(), req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to process password reset request",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "If an account with that email exists, a password reset link has been sent",
+	})
+}
+
+func (h *PasswordHandler) ResetPassword(c *gin.Context) {
+	var req userdto.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.resetPasswordUseCase.Execute(c.Request.Context(), req); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to reset password"
+
+		if errors.Is(err, userusecase.ErrInvalidResetToken) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Invalid or expired password reset token"
+		} else {
+			var passwordErr *userusecase.PasswordValidationError
+			if errors.As(err, &passwordErr) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   passwordErr.Error(),
+					"reasons": passwordErr.Reasons,
+				})
+				return
+			}
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password has been reset",
+	})
+}
+
+func (h *PasswordHandler) ChangePassword(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	var req userdto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.changePasswordUseCase.Execute(c.Request.Context(), userID, req, clientInfo(c))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to change password"
+
+		if errors.Is(err, userusecase.ErrIncorrectPassword) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Current password is incorrect"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		} else {
+			var passwordErr *userusecase.PasswordValidationError
+			if errors.As(err, &passwordErr) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   passwordErr.Error(),
+					"reasons": passwordErr.Reasons,
+				})
+				return
+			}
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	if !setAuthCookies(c, response.TokenPair) {
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,270 @@
+package userhandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	"roadmap/internal/pkg/mailer"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type MockPasswordResetTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetTokenRepository) Create(
+	ctx context.Context,
+	token *userentity.PasswordResetToken,
+) (*userentity.PasswordResetToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetTokenRepository) GetByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.PasswordResetToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(ctx context.Context, msg mailer.Message) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+type PasswordHandlerTestSuite struct {
+	suite.Suite
+	router         *gin.Engine
+	userRepo       *MockUserRepository
+	resetRepo      *MockPasswordResetTokenRepository
+	refreshRepo    *MockRefreshTokenRepository
+	revocationRepo *MockTokenRevocationRepository
+	mailer         *MockMailer
+	forgotUseCase  *userusecase.ForgotPasswordUseCase
+	userID         uuid.UUID
+}
+
+func (s *PasswordHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.userRepo = new(MockUserRepository)
+	s.resetRepo = new(MockPasswordResetTokenRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.revocationRepo = new(MockTokenRevocationRepository)
+	s.mailer = new(MockMailer)
+	s.userID = uuid.New()
+
+	s.forgotUseCase = userusecase.NewForgotPasswordUseCase(
+		s.userRepo, userusecase.NewPasswordResetSender(s.resetRepo, s.mailer, "http://localhost:3000/reset-password", time.Hour),
+	)
+	resetUseCase := userusecase.NewResetPasswordUseCase(
+		s.userRepo, testPasswordHasher, testPasswordValidator, s.resetRepo, s.refreshRepo, userusecase.NewTokenRevocationService(s.revocationRepo, time.Minute),
+	)
+
+	changeUseCase := userusecase.NewChangePasswordUseCase(
+		s.userRepo,
+		testPasswordHasher,
+		testPasswordValidator,
+		s.refreshRepo,
+		userusecase.NewTokenRevocationService(s.revocationRepo, time.Minute),
+		userusecase.NewTokenIssuer(jwtservice.NewJWTService("test-secret", 15*time.Minute), s.refreshRepo, nil, 24*time.Hour),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.userID.String())
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupPasswordRoutes(
+		s.router.Group("/api/v1"),
+		NewPasswordHandler(s.forgotUseCase, resetUseCase, changeUseCase),
+		authMiddleware,
+	)
+}
+
+func (s *PasswordHandlerTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.resetRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.revocationRepo.AssertExpectations(s.T())
+	s.mailer.AssertExpectations(s.T())
+}
+
+func (s *PasswordHandlerTestSuite) post(path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *PasswordHandlerTestSuite) TestForgotPassword_KnownEmail() {
+	user := &userentity.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com"}
+	s.userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	s.resetRepo.On("InvalidateForUser", mock.Anything, user.ID).Return(nil)
+	s.resetRepo.On("Create", mock.Anything, mock.Anything).Return(&userentity.PasswordResetToken{}, nil)
+	s.mailer.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	w := s.post("/api/v1/users/password/forgot", userdto.ForgotPasswordRequest{Email: user.Email})
+	s.forgotUseCase.Wait()
+
+	assert.Equal(s.T(), http.StatusAccepted, w.Code)
+}
+
+func (s *PasswordHandlerTestSuite) TestForgotPassword_UnknownEmailLooksTheSame() {
+	s.userRepo.On("GetByEmail", mock.Anything, "unknown@example.com").Return(nil, errors.New("user not found"))
+
+	w := s.post("/api/v1/users/password/forgot", userdto.ForgotPasswordRequest{Email: "unknown@example.com"})
+
+	assert.Equal(s.T(), http.StatusAccepted, w.Code)
+}
+
+func (s *PasswordHandlerTestSuite) TestForgotPassword_InvalidEmail() {
+	w := s.post("/api/v1/users/password/forgot", map[string]string{"email": "not-an-email"})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *PasswordHandlerTestSuite) TestResetPassword_InvalidToken() {
+	s.resetRepo.On("GetByHash", mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+
+	w := s.post("/api/v1/users/password/reset", userdto.ResetPasswordRequest{
+		Token:       "bogus",
+		NewPassword: "NewPassword123!",
+	})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "Invalid or expired password reset token", response["error"])
+}
+
+func (s *PasswordHandlerTestSuite) TestResetPassword_Success() {
+	token := &userentity.PasswordResetToken{ID: uuid.New(), UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}
+	s.resetRepo.On("GetByHash", mock.Anything, mock.Anything).Return(token, nil)
+	s.userRepo.On("GetByID", mock.Anything, token.UserID).Return(&userentity.User{ID: token.UserID, Username: "testuser"}, nil)
+	s.resetRepo.On("MarkUsed", mock.Anything, token.ID).Return(nil)
+	s.userRepo.On("UpdatePassword", mock.Anything, token.UserID, mock.AnythingOfType("string")).Return(nil)
+	s.resetRepo.On("InvalidateForUser", mock.Anything, token.UserID).Return(nil)
+	s.revocationRepo.On("SetTokensValidAfter", mock.Anything, token.UserID, mock.Anything).Return(nil)
+	s.refreshRepo.On("RevokeAllForUser", mock.Anything, token.UserID).Return(nil)
+
+	w := s.post("/api/v1/users/password/reset", userdto.ResetPasswordRequest{
+		Token:       "reset-token",
+		NewPassword: "NewPassword123!",
+	})
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+}
+
+func (s *PasswordHandlerTestSuite) TestResetPassword_WeakPassword() {
+	token := &userentity.PasswordResetToken{ID: uuid.New(), UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}
+	s.resetRepo.On("GetByHash", mock.Anything, mock.Anything).Return(token, nil)
+	s.userRepo.On("GetByID", mock.Anything, token.UserID).Return(&userentity.User{ID: token.UserID, Username: "testuser"}, nil)
+
+	w := s.post("/api/v1/users/password/reset", userdto.ResetPasswordRequest{Token: "reset-token", NewPassword: "weak"})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *PasswordHandlerTestSuite) userWithPassword(password string) *userentity.User {
+	passwordHash, _ := testPasswordHasher.Hash(password)
+	return &userentity.User{ID: s.userID, Username: "testuser", PasswordHash: passwordHash}
+}
+
+func (s *PasswordHandlerTestSuite) TestChangePassword_Success() {
+	s.userRepo.On("GetByID", mock.Anything, s.userID).Return(s.userWithPassword("OldPassword123!"), nil)
+	s.userRepo.On("UpdatePassword", mock.Anything, s.userID, mock.AnythingOfType("string")).Return(nil)
+	s.revocationRepo.On("SetTokensValidAfter", mock.Anything, s.userID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("RevokeAllForUser", mock.Anything, s.userID).Return(nil)
+	s.refreshRepo.On("Create", mock.Anything, mock.Anything).Return(&userentity.RefreshToken{}, nil)
+
+	w := s.post("/api/v1/users/password/change", userdto.ChangePasswordRequest{
+		CurrentPassword: "OldPassword123!",
+		NewPassword:     "NewPassword123!",
+	})
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response userdto.ChangePasswordResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(s.T(), response.Token)
+	assert.NotEmpty(s.T(), response.RefreshToken)
+}
+
+func (s *PasswordHandlerTestSuite) TestChangePassword_WrongCurrentPassword() {
+	s.userRepo.On("GetByID", mock.Anything, s.userID).Return(s.userWithPassword("OldPassword123!"), nil)
+
+	w := s.post("/api/v1/users/password/change", userdto.ChangePasswordRequest{
+		CurrentPassword: "WrongPassword123!",
+		NewPassword:     "NewPassword123!",
+	})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "Current password is incorrect", response["error"])
+}
+
+func (s *PasswordHandlerTestSuite) TestChangePassword_WeakNewPassword() {
+	s.userRepo.On("GetByID", mock.Anything, s.userID).Return(s.userWithPassword("OldPassword123!"), nil)
+
+	w := s.post("/api/v1/users/password/change", userdto.ChangePasswordRequest{
+		CurrentPassword: "OldPassword123!",
+		NewPassword:     "weak",
+	})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "password must be at least 8 characters long", response["error"])
+	assert.Equal(s.T(), []interface{}{userusecase.PasswordReasonTooShort}, response["reasons"])
+}
+
+func TestPasswordHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordHandlerTestSuite))
+}
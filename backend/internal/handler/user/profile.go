@@ -0,0 +1,95 @@
+package userhandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type ProfileHandler struct {
+	getProfileUseCase    *userusecase.GetProfileUseCase
+	updateProfileUseCase *userusecase.UpdateProfileUseCase
+}
+
+func NewProfileHandler(
+	getProfileUseCase *userusecase.GetProfileUseCase,
+	updateProfileUseCase *userusecase.UpdateProfileUseCase,
+) *ProfileHandler {
+	return &ProfileHandler{
+		getProfileUseCase:    getProfileUseCase,
+		updateProfileUseCase: updateProfileUseCase,
+	}
+}
+
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	response, err := h.getProfileUseCase.Execute(c.Request.Context(), userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to get profile"
+
+		if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	var req userdto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.updateProfileUseCase.Execute(c.Request.Context(), userID, req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to update profile"
+
+		if errors.Is(err, userusecase.ErrUsernameAlreadyExists) {
+			statusCode = http.StatusConflict
+			errorMessage = "Username already exists"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,159 @@
+package userhandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type ProfileHandlerTestSuite struct {
+	suite.Suite
+	handler  *ProfileHandler
+	router   *gin.Engine
+	userRepo *MockUserRepository
+	user     *userentity.User
+}
+
+func (s *ProfileHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.userRepo = new(MockUserRepository)
+	verifiedAt := time.Now()
+	s.user = &userentity.User{
+		ID:              uuid.New(),
+		Username:        "testuser",
+		Email:           "test@example.com",
+		DisplayName:     "Test User",
+		EmailVerifiedAt: &verifiedAt,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	s.handler = NewProfileHandler(
+		userusecase.NewGetProfileUseCase(s.userRepo),
+		userusecase.NewUpdateProfileUseCase(s.userRepo),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.user.ID.String())
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupProfileRoutes(s.router.Group("/api/v1"), s.handler, authMiddleware)
+}
+
+func (s *ProfileHandlerTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+}
+
+func (s *ProfileHandlerTestSuite) request(method string, body interface{}) *httptest.ResponseRecorder {
+	var reqBody *bytes.Buffer
+	if body != nil {
+		jsonBody, _ := json.Marshal(body)
+		reqBody = bytes.NewBuffer(jsonBody)
+	} else {
+		reqBody = bytes.NewBuffer(nil)
+	}
+
+	req := httptest.NewRequest(method, "/api/v1/users/profile", reqBody)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *ProfileHandlerTestSuite) TestGetProfile_Success() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+
+	w := s.request(http.MethodGet, nil)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), s.user.ID.String(), response["user_id"])
+	assert.Equal(s.T(), "testuser", response["username"])
+	assert.Equal(s.T(), "test@example.com", response["email"])
+	assert.Equal(s.T(), "Test User", response["display_name"])
+	assert.Equal(s.T(), true, response["email_verified"])
+}
+
+func (s *ProfileHandlerTestSuite) TestGetProfile_NoUserID() {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/users/profile", nil)
+
+	s.handler.GetProfile(c)
+
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "User ID not found in context", response["error"])
+}
+
+func (s *ProfileHandlerTestSuite) TestGetProfile_RepositoryError() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(nil, errors.New("database error"))
+
+	w := s.request(http.MethodGet, nil)
+
+	assert.Equal(s.T(), http.StatusInternalServerError, w.Code)
+}
+
+func (s *ProfileHandlerTestSuite) TestUpdateProfile_Success() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("UsernameExists", mock.Anything, "newname").Return(false, nil)
+	// The use case edits the loaded user in place, so the mock hands it back.
+	s.userRepo.On("UpdateProfile", mock.Anything, s.user).Return(s.user, nil)
+
+	username := "newname"
+	bio := "  Learning Go  "
+	w := s.request(http.MethodPatch, userdto.UpdateProfileRequest{Username: &username, Bio: &bio})
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response userdto.ProfileResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "newname", response.Username)
+	assert.Equal(s.T(), "Learning Go", response.Bio)
+	assert.Equal(s.T(), "Test User", response.DisplayName)
+}
+
+func (s *ProfileHandlerTestSuite) TestUpdateProfile_UsernameTaken() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("UsernameExists", mock.Anything, "taken").Return(true, nil)
+
+	username := "taken"
+	w := s.request(http.MethodPatch, userdto.UpdateProfileRequest{Username: &username})
+
+	assert.Equal(s.T(), http.StatusConflict, w.Code)
+}
+
+func (s *ProfileHandlerTestSuite) TestUpdateProfile_InvalidUsername() {
+	username := "ab"
+	w := s.request(http.MethodPatch, userdto.UpdateProfileRequest{Username: &username})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func TestProfileHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ProfileHandlerTestSuite))
+}
@@ -0,0 +1,157 @@
+package userhandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type RoleHandler struct {
+	listRolesUseCase    *userusecase.ListRolesUseCase
+	getUserRolesUseCase *userusecase.GetUserRolesUseCase
+	grantRoleUseCase    *userusecase.GrantRoleUseCase
+	revokeRoleUseCase   *userusecase.RevokeRoleUseCase
+}
+
+func NewRoleHandler(
+	listRolesUseCase *userusecase.ListRolesUseCase,
+	getUserRolesUseCase *userusecase.GetUserRolesUseCase,
+	grantRoleUseCase *userusecase.GrantRoleUseCase,
+	revokeRoleUseCase *userusecase.RevokeRoleUseCase,
+) *RoleHandler {
+	return &RoleHandler{
+		listRolesUseCase:    listRolesUseCase,
+		getUserRolesUseCase: getUserRolesUseCase,
+		grantRoleUseCase:    grantRoleUseCase,
+		revokeRoleUseCase:   revokeRoleUseCase,
+	}
+}
+
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	response, err := h.listRolesUseCase.Execute(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list roles",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RoleHandler) GetUserRoles(c *gin.Context) {
+	response, err := h.getUserRolesUseCase.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to get user roles"
+
+		if errors.Is(err, userusecase.ErrUserNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "User not found"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RoleHandler) GrantRole(c *gin.Context) {
+	actorID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	var req userdto.GrantRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.grantRoleUseCase.Execute(c.Request.Context(), actorID, c.Param("id"), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to grant role"
+
+		if errors.Is(err, userusecase.ErrUserNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "User not found"
+		} else if errors.Is(err, userusecase.ErrRoleNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "Role not found"
+		} else if errors.Is(err, userusecase.ErrRoleAlreadyGranted) {
+			statusCode = http.StatusConflict
+			errorMessage = "User already has this role"
+		} else if errors.Is(err, userusecase.ErrRoleNotAssignable) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "The member role is held by every user and cannot be granted"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RoleHandler) RevokeRole(c *gin.Context) {
+	actorID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	response, err := h.revokeRoleUseCase.Execute(c.Request.Context(), actorID, c.Param("id"), c.Param("role"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to revoke role"
+
+		if errors.Is(err, userusecase.ErrUserNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "User not found"
+		} else if errors.Is(err, userusecase.ErrRoleNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "Role not found"
+		} else if errors.Is(err, userusecase.ErrRoleNotGranted) {
+			statusCode = http.StatusNotFound
+			errorMessage = "User does not have this role"
+		} else if errors.Is(err, userusecase.ErrRoleNotAssignable) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "The member role is held by every user and cannot be revoked"
+		} else if errors.Is(err, userusecase.ErrCannotRevokeOwnAdmin) {
+			statusCode = http.StatusConflict
+			errorMessage = "You cannot revoke your own admin role"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,172 @@
+package userhandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) List(ctx context.Context) ([]*userentity.Role, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*userentity.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) GetByName(ctx context.Context, name string) (*userentity.Role, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) Grant(ctx context.Context, userRole *userentity.UserRole) error {
+	args := m.Called(ctx, userRole)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Revoke(ctx context.Context, userID uuid.UUID, role string) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
+type RoleHandlerTestSuite struct {
+	suite.Suite
+	router         *gin.Engine
+	userRepo       *MockUserRepository
+	roleRepo       *MockRoleRepository
+	revocationRepo *MockTokenRevocationRepository
+	admin          *userentity.User
+	user           *userentity.User
+}
+
+func (s *RoleHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.userRepo = new(MockUserRepository)
+	s.roleRepo = new(MockRoleRepository)
+	s.revocationRepo = new(MockTokenRevocationRepository)
+
+	s.admin = &userentity.User{
+		ID:    uuid.New(),
+		Roles: []string{userentity.RoleAdmin, userentity.RoleMember},
+	}
+	s.user = &userentity.User{
+		ID:    uuid.New(),
+		Roles: []string{userentity.RoleMember},
+	}
+
+	handler := NewRoleHandler(
+		userusecase.NewListRolesUseCase(s.roleRepo),
+		userusecase.NewGetUserRolesUseCase(s.userRepo),
+		userusecase.NewGrantRoleUseCase(s.userRepo, s.roleRepo),
+		userusecase.NewRevokeRoleUseCase(
+			s.userRepo,
+			s.roleRepo,
+			userusecase.NewTokenRevocationService(s.revocationRepo, time.Minute),
+		),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.admin.ID.String())
+		c.Set("claims", &jwtservice.Claims{
+			UserID:      s.admin.ID.String(),
+			Permissions: []string{userentity.PermissionRolesManage},
+		})
+		c.Next()
+	}
+
+	s.router = gin.New()
+	SetupRoleRoutes(s.router.Group("/api/v1"), handler, authMiddleware)
+}
+
+func (s *RoleHandlerTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.roleRepo.AssertExpectations(s.T())
+	s.revocationRepo.AssertExpectations(s.T())
+}
+
+func (s *RoleHandlerTestSuite) request(method, path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *RoleHandlerTestSuite) TestListRoles() {
+	s.roleRepo.On("List", mock.Anything).Return([]*userentity.Role{
+		{Name: userentity.RoleAdmin, Permissions: []string{userentity.PermissionRolesManage}},
+	}, nil)
+
+	w := s.request(http.MethodGet, "/api/v1/admin/roles", nil)
+
+	s.Require().Equal(http.StatusOK, w.Code)
+	var response userdto.ListRolesResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Require().Len(response.Roles, 1)
+	assert.Equal(s.T(), userentity.RoleAdmin, response.Roles[0].Name)
+}
+
+func (s *RoleHandlerTestSuite) TestGrantRole() {
+	s.roleRepo.On("GetByName", mock.Anything, userentity.RoleModerator).
+		Return(&userentity.Role{Name: userentity.RoleModerator}, nil)
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.roleRepo.On("Grant", mock.Anything, mock.AnythingOfType("*user.UserRole")).Return(nil)
+
+	w := s.request(http.MethodPost, "/api/v1/admin/users/"+s.user.ID.String()+"/roles", userdto.GrantRoleRequest{
+		Role: userentity.RoleModerator,
+	})
+
+	assert.Equal(s.T(), http.StatusOK, w.Code, w.Body.String())
+}
+
+func (s *RoleHandlerTestSuite) TestGrantRole_Member() {
+	w := s.request(http.MethodPost, "/api/v1/admin/users/"+s.user.ID.String()+"/roles", userdto.GrantRoleRequest{
+		Role: userentity.RoleMember,
+	})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *RoleHandlerTestSuite) TestRevokeRole_OwnAdmin() {
+	s.roleRepo.On("GetByName", mock.Anything, userentity.RoleAdmin).
+		Return(&userentity.Role{Name: userentity.RoleAdmin}, nil)
+	s.userRepo.On("GetByID", mock.Anything, s.admin.ID).Return(s.admin, nil)
+
+	w := s.request(http.MethodDelete, "/api/v1/admin/users/"+s.admin.ID.String()+"/roles/admin", nil)
+
+	assert.Equal(s.T(), http.StatusConflict, w.Code)
+}
+
+func (s *RoleHandlerTestSuite) TestGetUserRoles_UnknownUser() {
+	w := s.request(http.MethodGet, "/api/v1/admin/users/not-a-uuid/roles", nil)
+
+	assert.Equal(s.T(), http.StatusNotFound, w.Code)
+}
+
+func TestRoleHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(RoleHandlerTestSuite))
+}
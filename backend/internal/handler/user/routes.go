@@ -2,19 +2,157 @@ package userhandler
 
 import (
 	"github.com/gin-gonic/gin"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/handler/middleware"
 )
 
 func SetupUserRoutes(router *gin.RouterGroup, handler *UserHandler, authMiddleware gin.HandlerFunc) {
 	users := router.Group("/users")
 	{
-		users.POST("create", handler.CreateUser)
+		users.POST("create", authMiddleware, middleware.RequirePermission(userentity.PermissionUsersCreate), handler.CreateUser)
 		users.POST("register", handler.Register)
 		users.POST("login", handler.Login)
+		users.POST("token/refresh", handler.RefreshToken)
 
 		protected := users.Group("")
 		protected.Use(authMiddleware)
 		{
-			protected.GET("profile", handler.GetProfile)
+			protected.POST("logout", handler.Logout)
+			protected.POST("logout-all", handler.LogoutAll)
+		}
+	}
+}
+
+func SetupPasswordRoutes(router *gin.RouterGroup, handler *PasswordHandler, authMiddleware gin.HandlerFunc) {
+	password := router.Group("/users/password")
+	{
+		password.POST("forgot", handler.ForgotPassword)
+		password.POST("reset", handler.ResetPassword)
+		password.POST("change", authMiddleware, handler.ChangePassword)
+	}
+}
+
+func SetupProfileRoutes(router *gin.RouterGroup, handler *ProfileHandler, authMiddleware gin.HandlerFunc) {
+	profile := router.Group("/users/profile")
+	profile.Use(authMiddleware)
+	{
+		profile.GET("", handler.GetProfile)
+		profile.PATCH("", handler.UpdateProfile)
+	}
+}
+
+func SetupEmailVerificationRoutes(router *gin.RouterGroup, handler *EmailVerificationHandler, authMiddleware gin.HandlerFunc) {
+	email := router.Group("/users/email")
+	{
+		email.POST("verify", handler.VerifyEmail)
+		email.POST("resend", authMiddleware, handler.ResendVerification)
+	}
+}
+
+func SetupEmailChangeRoutes(router *gin.RouterGroup, handler *EmailChangeHandler, authMiddleware gin.HandlerFunc) {
+	change := router.Group("/users/email/change")
+	{
+		change.POST("", authMiddleware, handler.RequestEmailChange)
+		change.POST("confirm", handler.ConfirmEmailChange)
+		change.POST("cancel", handler.CancelEmailChange)
+	}
+}
+
+func SetupTwoFactorRoutes(router *gin.RouterGroup, handler *TwoFactorHandler, authMiddleware gin.HandlerFunc) {
+	users := router.Group("/users")
+	{
+		users.POST("login/2fa", handler.VerifyLogin)
+
+		twoFactor := users.Group("/2fa")
+		twoFactor.Use(authMiddleware)
+		{
+			twoFactor.POST("setup", handler.Setup)
+			twoFactor.POST("confirm", handler.Confirm)
+		}
+	}
+}
+
+func SetupPasskeyRoutes(router *gin.RouterGroup, handler *PasskeyHandler, authMiddleware gin.HandlerFunc) {
+	passkeys := router.Group("/users/passkeys")
+	{
+		passkeys.POST("login/begin", handler.BeginLogin)
+		passkeys.POST("login/finish", handler.FinishLogin)
+
+		protected := passkeys.Group("")
+		protected.Use(authMiddleware)
+		{
+			protected.GET("", handler.List)
+			protected.POST("register/begin", handler.BeginRegistration)
+			protected.POST("register/finish", handler.FinishRegistration)
+			protected.DELETE(":id", handler.Delete)
 		}
 	}
 }
+
+func SetupOIDCRoutes(router *gin.RouterGroup, handler *OIDCHandler, authMiddleware gin.HandlerFunc) {
+	oidc := router.Group("/users/oidc/:provider")
+	{
+		oidc.POST("login/begin", handler.BeginLogin)
+		oidc.POST("login/finish", handler.FinishLogin)
+		oidc.POST("link/begin", authMiddleware, handler.BeginLink)
+		oidc.POST("link/finish", authMiddleware, handler.FinishLink)
+	}
+}
+
+func SetupAccessTokenRoutes(router *gin.RouterGroup, handler *AccessTokenHandler, authMiddleware gin.HandlerFunc) {
+	tokens := router.Group("/users/tokens")
+	tokens.Use(authMiddleware)
+	{
+		tokens.POST("", handler.Create)
+		tokens.GET("", handler.List)
+		tokens.GET(":id", handler.Get)
+		tokens.PATCH(":id", handler.Update)
+		tokens.DELETE(":id", handler.Delete)
+	}
+}
+
+func SetupRoleRoutes(router *gin.RouterGroup, handler *RoleHandler, authMiddleware gin.HandlerFunc) {
+	admin := router.Group("/admin")
+	admin.Use(authMiddleware, middleware.RequirePermission(userentity.PermissionRolesManage))
+	{
+		admin.GET("roles", handler.ListRoles)
+		admin.GET("users/:id/roles", handler.GetUserRoles)
+		admin.POST("users/:id/roles", handler.GrantRole)
+		admin.DELETE("users/:id/roles/:role", handler.RevokeRole)
+	}
+}
+
+func SetupAdminUserRoutes(router *gin.RouterGroup, handler *AdminUserHandler, authMiddleware gin.HandlerFunc) {
+	readUsers := middleware.RequirePermission(userentity.PermissionUsersRead)
+	manageUsers := middleware.RequirePermission(userentity.PermissionUsersManage)
+
+	users := router.Group("/admin/users")
+	users.Use(authMiddleware)
+	{
+		users.GET("", readUsers, handler.ListUsers)
+		users.GET(":id", readUsers, handler.GetUser)
+		users.POST(":id/suspend", manageUsers, handler.SuspendUser)
+		users.POST(":id/unsuspend", manageUsers, handler.UnsuspendUser)
+		users.POST(":id/password-reset", manageUsers, handler.ForcePasswordReset)
+		users.DELETE(":id", manageUsers, handler.DeleteUser)
+	}
+}
+
+func SetupSessionRoutes(router *gin.RouterGroup, handler *SessionHandler, authMiddleware gin.HandlerFunc) {
+	sessions := router.Group("/users/sessions")
+	sessions.Use(authMiddleware)
+	{
+		sessions.GET("", handler.List)
+		sessions.DELETE(":id", handler.Revoke)
+	}
+}
+
+func SetupAccountRoutes(router *gin.RouterGroup, handler *AccountHandler, authMiddleware gin.HandlerFunc) {
+	me := router.Group("/users/me")
+	me.Use(authMiddleware)
+	{
+		me.DELETE("", handler.Delete)
+		me.GET("export", handler.Export)
+	}
+}
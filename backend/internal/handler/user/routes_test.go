@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -18,11 +19,13 @@ func TestSetupUserRoutes(t *testing.T) {
 	router := gin.New()
 
 	// Create real use cases with nil repositories (they won't be called in this test)
-	createUseCase := userusecase.NewCreateUserUseCase(nil)
-	registerUseCase := userusecase.NewRegisterUseCase(nil, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	loginUseCase := userusecase.NewLoginUseCase(nil, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
+	createUseCase := userusecase.NewCreateUserUseCase(nil, testPasswordHasher, testPasswordValidator)
+	tokenIssuer := userusecase.NewTokenIssuer(jwtservice.NewJWTService("test-secret", 15*time.Minute), nil, nil, 24*time.Hour)
+	registerUseCase := userusecase.NewRegisterUseCase(nil, testPasswordHasher, testPasswordValidator, tokenIssuer, nil)
+	loginUseCase := userusecase.NewLoginUseCase(nil, testPasswordHasher, tokenIssuer, nil, nil)
+	refreshUseCase := userusecase.NewRefreshTokenUseCase(nil, nil, tokenIssuer)
 
-	handler := NewUserHandler(createUseCase, registerUseCase, loginUseCase)
+	handler := NewUserHandler(createUseCase, registerUseCase, loginUseCase, refreshUseCase, nil, nil)
 	authMiddleware := func(c *gin.Context) {
 		c.Set("user_id", "test-user-id")
 		c.Next()
@@ -49,10 +52,317 @@ func TestSetupUserRoutes(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.NotEqual(t, http.StatusNotFound, w.Code, "login route should exist")
 
-	// Test protected profile route with auth middleware
-	req = httptest.NewRequest(http.MethodGet, "/api/v1/users/profile", nil)
+	// Test refresh route exists
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/users/token/refresh", nil)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
-	// Should be 200 OK because auth middleware sets user_id
-	assert.Equal(t, http.StatusOK, w.Code, "profile route should work with auth")
+	assert.NotEqual(t, http.StatusNotFound, w.Code, "refresh route should exist")
+
+	// Test protected logout routes exist
+	for _, path := range []string{"/api/v1/users/logout", "/api/v1/users/logout-all"} {
+		req = httptest.NewRequest(http.MethodPost, path, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.NotEqual(t, http.StatusNotFound, w.Code, "%s route should exist", path)
+	}
+}
+
+func TestSetupPasswordRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	handler := NewPasswordHandler(nil, nil, nil)
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "test-user-id")
+		c.Next()
+	}
+
+	api := router.Group("/api/v1")
+	SetupPasswordRoutes(api, handler, authMiddleware)
+
+	// Requests without a body fail validation before reaching the use cases
+	for _, path := range []string{
+		"/api/v1/users/password/forgot",
+		"/api/v1/users/password/reset",
+		"/api/v1/users/password/change",
+	} {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "%s route should exist", path)
+	}
+}
+
+func TestSetupProfileRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	handler := NewProfileHandler(nil, nil)
+	authMiddleware := func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}
+
+	api := router.Group("/api/v1")
+	SetupProfileRoutes(api, handler, authMiddleware)
+
+	for _, method := range []string{http.MethodGet, http.MethodPatch} {
+		req := httptest.NewRequest(method, "/api/v1/users/profile", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s profile route should be protected", method)
+	}
+}
+
+func TestSetupEmailChangeRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	handler := NewEmailChangeHandler(nil, nil, nil)
+	authMiddleware := func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}
+
+	api := router.Group("/api/v1")
+	SetupEmailChangeRoutes(api, handler, authMiddleware)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/email/change", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "change route should be protected")
+
+	// Confirm and cancel links are opened without a session
+	for _, path := range []string{"/api/v1/users/email/change/confirm", "/api/v1/users/email/change/cancel"} {
+		req = httptest.NewRequest(http.MethodPost, path, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "%s route should be public", path)
+	}
+}
+
+func TestSetupTwoFactorRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	handler := NewTwoFactorHandler(nil, nil, nil)
+	authMiddleware := func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}
+
+	api := router.Group("/api/v1")
+	SetupTwoFactorRoutes(api, handler, authMiddleware)
+
+	for _, path := range []string{"/api/v1/users/2fa/setup", "/api/v1/users/2fa/confirm"} {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s route should be protected", path)
+	}
+
+	// The second login step happens before the user holds an access token
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/login/2fa", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "login/2fa route should be public")
+}
+
+func TestSetupPasskeyRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	handler := NewPasskeyHandler(nil, nil, nil, nil, nil, nil)
+	authMiddleware := func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}
+
+	api := router.Group("/api/v1")
+	SetupPasskeyRoutes(api, handler, authMiddleware)
+
+	protected := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/api/v1/users/passkeys"},
+		{http.MethodPost, "/api/v1/users/passkeys/register/begin"},
+		{http.MethodPost, "/api/v1/users/passkeys/register/finish"},
+		{http.MethodDelete, "/api/v1/users/passkeys/some-id"},
+	}
+	for _, route := range protected {
+		req := httptest.NewRequest(route.method, route.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s %s should be protected", route.method, route.path)
+	}
+
+	// Finishing a login is public; an empty body fails validation
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/passkeys/login/finish", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "login/finish route should be public")
+}
+
+func TestSetupOIDCRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	handler := NewOIDCHandler(nil, nil, nil, nil)
+	authMiddleware := func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}
+
+	api := router.Group("/api/v1")
+	SetupOIDCRoutes(api, handler, authMiddleware)
+
+	for _, path := range []string{"/api/v1/users/oidc/google/link/begin", "/api/v1/users/oidc/google/link/finish"} {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s should be protected", path)
+	}
+
+	// Finishing a login is public; an empty body fails validation
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/oidc/google/login/finish", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "login/finish route should be public")
+}
+
+func TestSetupAccessTokenRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	handler := NewAccessTokenHandler(nil, nil, nil, nil, nil)
+	authMiddleware := func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}
+
+	api := router.Group("/api/v1")
+	SetupAccessTokenRoutes(api, handler, authMiddleware)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/api/v1/users/tokens"},
+		{http.MethodGet, "/api/v1/users/tokens"},
+		{http.MethodGet, "/api/v1/users/tokens/123"},
+		{http.MethodPatch, "/api/v1/users/tokens/123"},
+		{http.MethodDelete, "/api/v1/users/tokens/123"},
+	}
+
+	for _, route := range routes {
+		req := httptest.NewRequest(route.method, route.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s %s should be protected", route.method, route.path)
+	}
+}
+
+func TestSetupUserRoutes_CreateRequiresPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	handler := NewUserHandler(userusecase.NewCreateUserUseCase(nil, testPasswordHasher, testPasswordValidator), nil, nil, nil, nil, nil)
+
+	var permissions []string
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "test-user-id")
+		c.Set("claims", &jwtservice.Claims{UserID: "test-user-id", Permissions: permissions})
+		c.Next()
+	}
+
+	api := router.Group("/api/v1")
+	SetupUserRoutes(api, handler, authMiddleware)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/create", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code, "create should require users:create")
+
+	permissions = []string{"users:create"}
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/users/create", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "create should reach the handler with users:create")
+}
+
+func TestSetupRoleRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	handler := NewRoleHandler(nil, nil, nil, nil)
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "test-user-id")
+		c.Set("claims", &jwtservice.Claims{UserID: "test-user-id", Permissions: []string{"users:read"}})
+		c.Next()
+	}
+
+	api := router.Group("/api/v1")
+	SetupRoleRoutes(api, handler, authMiddleware)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/api/v1/admin/roles"},
+		{http.MethodGet, "/api/v1/admin/users/123/roles"},
+		{http.MethodPost, "/api/v1/admin/users/123/roles"},
+		{http.MethodDelete, "/api/v1/admin/users/123/roles/admin"},
+	}
+
+	for _, route := range routes {
+		req := httptest.NewRequest(route.method, route.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, "%s %s should require roles:manage", route.method, route.path)
+	}
+}
+
+func TestSetupAdminUserRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	handler := NewAdminUserHandler(nil, nil, nil, nil, nil, nil)
+
+	var permissions []string
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", "test-user-id")
+		c.Set("claims", &jwtservice.Claims{UserID: "test-user-id", Permissions: permissions})
+		c.Next()
+	}
+
+	api := router.Group("/api/v1")
+	SetupRoleRoutes(api, NewRoleHandler(nil, nil, nil, nil), authMiddleware)
+	SetupAdminUserRoutes(api, handler, authMiddleware)
+
+	readRoutes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/api/v1/admin/users"},
+		{http.MethodGet, "/api/v1/admin/users/123"},
+	}
+	manageRoutes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/api/v1/admin/users/123/suspend"},
+		{http.MethodPost, "/api/v1/admin/users/123/unsuspend"},
+		{http.MethodPost, "/api/v1/admin/users/123/password-reset"},
+		{http.MethodDelete, "/api/v1/admin/users/123"},
+	}
+
+	for _, route := range append(readRoutes, manageRoutes...) {
+		req := httptest.NewRequest(route.method, route.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, "%s %s should require a permission", route.method, route.path)
+	}
+
+	permissions = []string{"users:read"}
+	for _, route := range manageRoutes {
+		req := httptest.NewRequest(route.method, route.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, "%s %s should require users:manage", route.method, route.path)
+	}
 }
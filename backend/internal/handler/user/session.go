@@ -0,0 +1,100 @@
+package userhandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type SessionHandler struct {
+	listUseCase   *userusecase.ListSessionsUseCase
+	revokeUseCase *userusecase.RevokeSessionUseCase
+}
+
+func NewSessionHandler(
+	listUseCase *userusecase.ListSessionsUseCase,
+	revokeUseCase *userusecase.RevokeSessionUseCase,
+) *SessionHandler {
+	return &SessionHandler{
+		listUseCase:   listUseCase,
+		revokeUseCase: revokeUseCase,
+	}
+}
+
+func (h *SessionHandler) List(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	var currentSessionID string
+	if claims, exists := middleware.GetClaims(c); exists {
+		currentSessionID = claims.SessionID
+	}
+
+	response, err := h.listUseCase.Execute(c.Request.Context(), userID, currentSessionID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to list sessions"
+
+		if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	if err := h.revokeUseCase.Execute(c.Request.Context(), userID, c.Param("id")); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to revoke session"
+
+		if errors.Is(err, userusecase.ErrSessionNotFound) {
+			statusCode = http.StatusNotFound
+			errorMessage = "Session not found"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Session revoked",
+	})
+}
+
+// clientInfo describes the device of a sign-in request for its session.
+func clientInfo(c *gin.Context) userdto.ClientInfo {
+	return userdto.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
@@ -0,0 +1,167 @@
+package userhandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/handler/middleware"
+	jwtservice "roadmap/internal/pkg/jwt"
+	userrepo "roadmap/internal/repository/user"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(ctx context.Context, session *userentity.Session) (*userentity.Session, error) {
+	args := m.Called(ctx, session)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) ListActiveByUser(
+	ctx context.Context,
+	userID uuid.UUID,
+	now time.Time,
+) ([]*userentity.Session, error) {
+	args := m.Called(ctx, userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*userentity.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Touch(ctx context.Context, id uuid.UUID, lastSeenAt time.Time) error {
+	args := m.Called(ctx, id, lastSeenAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) Revoke(ctx context.Context, id uuid.UUID, userID uuid.UUID, revokedAt time.Time) error {
+	args := m.Called(ctx, id, userID, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) IsRevoked(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type SessionHandlerTestSuite struct {
+	suite.Suite
+	router      *gin.Engine
+	sessionRepo *MockSessionRepository
+	userID      uuid.UUID
+	sessionID   uuid.UUID
+	jwt         string
+}
+
+func (s *SessionHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.sessionRepo = new(MockSessionRepository)
+	s.userID = uuid.New()
+	s.sessionID = uuid.New()
+
+	jwtService := jwtservice.NewJWTService("test-secret", 15*time.Minute)
+	var err error
+	s.jwt, err = jwtService.GenerateTokenWithClaims(jwtservice.Claims{
+		UserID:    s.userID.String(),
+		Username:  "testuser",
+		Email:     "test@example.com",
+		SessionID: s.sessionID.String(),
+	})
+	s.Require().NoError(err)
+
+	sessionService := userusecase.NewSessionService(s.sessionRepo, time.Minute)
+	handler := NewSessionHandler(
+		userusecase.NewListSessionsUseCase(s.sessionRepo),
+		userusecase.NewRevokeSessionUseCase(sessionService),
+	)
+	authMiddleware := middleware.AuthMiddleware(jwtService, middleware.WithSessionChecker(sessionService))
+
+	s.router = gin.New()
+	SetupSessionRoutes(s.router.Group("/api/v1"), handler, authMiddleware)
+}
+
+func (s *SessionHandlerTestSuite) TearDownTest() {
+	s.sessionRepo.AssertExpectations(s.T())
+}
+
+func (s *SessionHandlerTestSuite) request(method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+s.jwt)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *SessionHandlerTestSuite) TestList() {
+	now := time.Now().UTC()
+	s.sessionRepo.On("IsRevoked", mock.Anything, s.sessionID).Return(false, nil)
+	s.sessionRepo.On("ListActiveByUser", mock.Anything, s.userID, mock.AnythingOfType("time.Time")).
+		Return([]*userentity.Session{
+			{ID: s.sessionID, UserID: s.userID, UserAgent: "Firefox", IPAddress: "192.0.2.1", CreatedAt: now, LastSeenAt: now},
+			{ID: uuid.New(), UserID: s.userID, UserAgent: "curl", IPAddress: "192.0.2.2", CreatedAt: now, LastSeenAt: now},
+		}, nil)
+
+	w := s.request(http.MethodGet, "/api/v1/users/sessions")
+
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var response userdto.ListSessionsResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Require().Len(response.Sessions, 2)
+	assert.True(s.T(), response.Sessions[0].Current)
+	assert.Equal(s.T(), "192.0.2.1", response.Sessions[0].IPAddress)
+	assert.False(s.T(), response.Sessions[1].Current)
+}
+
+func (s *SessionHandlerTestSuite) TestRevoke_RejectsTokensOfSession() {
+	s.sessionRepo.On("IsRevoked", mock.Anything, s.sessionID).Return(false, nil).Once()
+	s.sessionRepo.On("Revoke", mock.Anything, s.sessionID, s.userID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	w := s.request(http.MethodDelete, "/api/v1/users/sessions/"+s.sessionID.String())
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	w = s.request(http.MethodGet, "/api/v1/users/sessions")
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+
+	var response map[string]interface{}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(s.T(), "Session has been revoked", response["error"])
+}
+
+func (s *SessionHandlerTestSuite) TestRevoke_NotFound() {
+	otherID := uuid.New()
+	s.sessionRepo.On("IsRevoked", mock.Anything, s.sessionID).Return(false, nil)
+	s.sessionRepo.On("Revoke", mock.Anything, otherID, s.userID, mock.Anything).Return(userrepo.ErrSessionNotFound)
+
+	w := s.request(http.MethodDelete, "/api/v1/users/sessions/"+otherID.String())
+	assert.Equal(s.T(), http.StatusNotFound, w.Code)
+
+	w = s.request(http.MethodDelete, "/api/v1/users/sessions/not-a-uuid")
+	assert.Equal(s.T(), http.StatusNotFound, w.Code)
+}
+
+func TestSessionHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionHandlerTestSuite))
+}
@@ -0,0 +1,146 @@
+package userhandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	userdto "roadmap/internal/domain/dto/user"
+	"roadmap/internal/handler/middleware"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type TwoFactorHandler struct {
+	setupUseCase   *userusecase.TwoFactorSetupUseCase
+	confirmUseCase *userusecase.TwoFactorConfirmUseCase
+	loginUseCase   *userusecase.TwoFactorLoginUseCase
+}
+
+func NewTwoFactorHandler(
+	setupUseCase *userusecase.TwoFactorSetupUseCase,
+	confirmUseCase *userusecase.TwoFactorConfirmUseCase,
+	loginUseCase *userusecase.TwoFactorLoginUseCase,
+) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		setupUseCase:   setupUseCase,
+		confirmUseCase: confirmUseCase,
+		loginUseCase:   loginUseCase,
+	}
+}
+
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	response, err := h.setupUseCase.Execute(c.Request.Context(), userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to set up two-factor authentication"
+
+		if errors.Is(err, userusecase.ErrTwoFactorAlreadyEnabled) {
+			statusCode = http.StatusConflict
+			errorMessage = "Two-factor authentication is already enabled"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User ID not found in context",
+		})
+		return
+	}
+
+	var req userdto.TwoFactorConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.confirmUseCase.Execute(c.Request.Context(), userID, req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to confirm two-factor authentication"
+
+		if errors.Is(err, userusecase.ErrInvalidTwoFactorCode) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Invalid two-factor code"
+		} else if errors.Is(err, userusecase.ErrTwoFactorNotPending) {
+			statusCode = http.StatusBadRequest
+			errorMessage = "Two-factor setup has not been started"
+		} else if errors.Is(err, userusecase.ErrTwoFactorAlreadyEnabled) {
+			statusCode = http.StatusConflict
+			errorMessage = "Two-factor authentication is already enabled"
+		} else if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *TwoFactorHandler) VerifyLogin(c *gin.Context) {
+	var req userdto.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.loginUseCase.Execute(c.Request.Context(), req, clientInfo(c))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to login"
+
+		if errors.Is(err, userusecase.ErrInvalidTwoFactorCode) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid two-factor code"
+		} else if errors.Is(err, userusecase.ErrInvalidMFAChallenge) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid or expired MFA token"
+		} else if errors.Is(err, userusecase.ErrAccountSuspended) {
+			statusCode = http.StatusForbidden
+			errorMessage = "Account is suspended"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	if !setAuthCookies(c, response.TokenPair) {
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,296 @@
+package userhandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	"roadmap/internal/pkg/totp"
+	userrepo "roadmap/internal/repository/user"
+	userusecase "roadmap/internal/usecase/user"
+)
+
+type MockTOTPRepository struct {
+	mock.Mock
+}
+
+func (m *MockTOTPRepository) Upsert(ctx context.Context, enrolled *userentity.UserTOTP) (*userentity.UserTOTP, error) {
+	args := m.Called(ctx, enrolled)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.UserTOTP), args.Error(1)
+}
+
+func (m *MockTOTPRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*userentity.UserTOTP, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.UserTOTP), args.Error(1)
+}
+
+func (m *MockTOTPRepository) Confirm(ctx context.Context, userID uuid.UUID, step int64) error {
+	args := m.Called(ctx, userID, step)
+	return args.Error(0)
+}
+
+func (m *MockTOTPRepository) RecordUsedStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	args := m.Called(ctx, userID, step)
+	return args.Error(0)
+}
+
+type MockRecoveryCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecoveryCodeRepository) Replace(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	args := m.Called(ctx, userID, codeHashes)
+	return args.Error(0)
+}
+
+func (m *MockRecoveryCodeRepository) Consume(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Error(0)
+}
+
+type MockMFAChallengeRepository struct {
+	mock.Mock
+}
+
+func (m *MockMFAChallengeRepository) Create(
+	ctx context.Context,
+	challenge *userentity.MFAChallenge,
+) (*userentity.MFAChallenge, error) {
+	args := m.Called(ctx, challenge)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.MFAChallenge), args.Error(1)
+}
+
+func (m *MockMFAChallengeRepository) GetByHash(ctx context.Context, tokenHash string) (*userentity.MFAChallenge, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.MFAChallenge), args.Error(1)
+}
+
+func (m *MockMFAChallengeRepository) ReserveAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) error {
+	args := m.Called(ctx, id, maxAttempts)
+	return args.Error(0)
+}
+
+func (m *MockMFAChallengeRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type TwoFactorHandlerTestSuite struct {
+	suite.Suite
+	router        *gin.Engine
+	userRepo      *MockUserRepository
+	refreshRepo   *MockRefreshTokenRepository
+	totpRepo      *MockTOTPRepository
+	recoveryRepo  *MockRecoveryCodeRepository
+	challengeRepo *MockMFAChallengeRepository
+	user          *userentity.User
+	secret        string
+}
+
+func (s *TwoFactorHandlerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.userRepo = new(MockUserRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.totpRepo = new(MockTOTPRepository)
+	s.recoveryRepo = new(MockRecoveryCodeRepository)
+	s.challengeRepo = new(MockMFAChallengeRepository)
+
+	passwordHash, _ := testPasswordHasher.Hash("Password123!")
+	s.user = &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser",
+		Email:        "test@example.com",
+		PasswordHash: passwordHash,
+	}
+
+	secret, err := totp.GenerateSecret()
+	s.Require().NoError(err)
+	s.secret = secret
+
+	tokenIssuer := userusecase.NewTokenIssuer(
+		jwtservice.NewJWTService("test-secret", 15*time.Minute),
+		s.refreshRepo,
+		nil,
+		30*24*time.Hour,
+	)
+	loginUseCase := userusecase.NewLoginUseCase(
+		s.userRepo, testPasswordHasher, tokenIssuer, userusecase.NewTwoFactorChallenger(s.totpRepo, s.challengeRepo, 5*time.Minute), nil,
+	)
+	userHandler := NewUserHandler(nil, nil, loginUseCase, nil, nil, nil)
+	handler := NewTwoFactorHandler(
+		userusecase.NewTwoFactorSetupUseCase(s.userRepo, s.totpRepo, "Roadmap"),
+		userusecase.NewTwoFactorConfirmUseCase(s.totpRepo, s.recoveryRepo),
+		userusecase.NewTwoFactorLoginUseCase(s.userRepo, s.totpRepo, s.recoveryRepo, s.challengeRepo, tokenIssuer),
+	)
+
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", s.user.ID.String())
+		c.Next()
+	}
+
+	s.router = gin.New()
+	api := s.router.Group("/api/v1")
+	api.POST("/users/login", userHandler.Login)
+	SetupTwoFactorRoutes(api, handler, authMiddleware)
+}
+
+func (s *TwoFactorHandlerTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.totpRepo.AssertExpectations(s.T())
+	s.recoveryRepo.AssertExpectations(s.T())
+	s.challengeRepo.AssertExpectations(s.T())
+}
+
+func (s *TwoFactorHandlerTestSuite) post(path string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *TwoFactorHandlerTestSuite) currentCode() string {
+	code, err := totp.Code(s.secret, totp.Step(time.Now()))
+	s.Require().NoError(err)
+	return code
+}
+
+func (s *TwoFactorHandlerTestSuite) enrolled() *userentity.UserTOTP {
+	confirmedAt := time.Now().Add(-time.Hour)
+	return &userentity.UserTOTP{UserID: s.user.ID, Secret: s.secret, ConfirmedAt: &confirmedAt}
+}
+
+func (s *TwoFactorHandlerTestSuite) TestSetup_Success() {
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.totpRepo.On("Upsert", mock.Anything, mock.AnythingOfType("*user.UserTOTP")).Return(&userentity.UserTOTP{}, nil)
+
+	w := s.post("/api/v1/users/2fa/setup", nil)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response userdto.TwoFactorSetupResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(s.T(), response.Secret)
+	assert.Contains(s.T(), response.OTPAuthURI, "otpauth://totp/")
+}
+
+func (s *TwoFactorHandlerTestSuite) TestConfirm_Success() {
+	s.totpRepo.On("GetByUserID", mock.Anything, s.user.ID).Return(&userentity.UserTOTP{UserID: s.user.ID, Secret: s.secret}, nil)
+	s.recoveryRepo.On("Replace", mock.Anything, s.user.ID, mock.Anything).Return(nil)
+	s.totpRepo.On("Confirm", mock.Anything, s.user.ID, mock.Anything).Return(nil)
+
+	w := s.post("/api/v1/users/2fa/confirm", userdto.TwoFactorConfirmRequest{Code: s.currentCode()})
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response userdto.TwoFactorConfirmResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(s.T(), response.RecoveryCodes, 10)
+}
+
+func (s *TwoFactorHandlerTestSuite) TestConfirm_InvalidCode() {
+	s.totpRepo.On("GetByUserID", mock.Anything, s.user.ID).Return(&userentity.UserTOTP{UserID: s.user.ID, Secret: s.secret}, nil)
+
+	w := s.post("/api/v1/users/2fa/confirm", userdto.TwoFactorConfirmRequest{Code: "abcdef"})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *TwoFactorHandlerTestSuite) TestConfirm_NotStarted() {
+	s.totpRepo.On("GetByUserID", mock.Anything, s.user.ID).Return(nil, pgx.ErrNoRows)
+
+	w := s.post("/api/v1/users/2fa/confirm", userdto.TwoFactorConfirmRequest{Code: "123456"})
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+}
+
+func (s *TwoFactorHandlerTestSuite) TestLogin_ReturnsChallengeInsteadOfTokens() {
+	s.userRepo.On("GetByEmail", mock.Anything, s.user.Email).Return(s.user, nil)
+	s.totpRepo.On("GetByUserID", mock.Anything, s.user.ID).Return(s.enrolled(), nil)
+	s.challengeRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.MFAChallenge")).
+		Return(&userentity.MFAChallenge{ExpiresAt: time.Now().Add(5 * time.Minute)}, nil)
+
+	w := s.post("/api/v1/users/login", userdto.LoginRequest{Email: s.user.Email, Password: "Password123!"})
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(s.T(), true, response["mfa_required"])
+	assert.NotEmpty(s.T(), response["mfa_token"])
+	assert.NotContains(s.T(), response, "token")
+	assert.NotContains(s.T(), response, "refresh_token")
+}
+
+func (s *TwoFactorHandlerTestSuite) TestVerifyLogin_Success() {
+	challenge := &userentity.MFAChallenge{ID: uuid.New(), UserID: s.user.ID, ExpiresAt: time.Now().Add(time.Minute)}
+	s.challengeRepo.On("GetByHash", mock.Anything, mock.Anything).Return(challenge, nil)
+	s.challengeRepo.On("ReserveAttempt", mock.Anything, challenge.ID, mock.Anything).Return(nil)
+	s.totpRepo.On("GetByUserID", mock.Anything, s.user.ID).Return(s.enrolled(), nil)
+	s.totpRepo.On("RecordUsedStep", mock.Anything, s.user.ID, mock.Anything).Return(nil)
+	s.challengeRepo.On("MarkUsed", mock.Anything, challenge.ID).Return(nil)
+	s.userRepo.On("GetByID", mock.Anything, s.user.ID).Return(s.user, nil)
+	s.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
+
+	w := s.post("/api/v1/users/login/2fa", userdto.TwoFactorLoginRequest{MFAToken: "mfa-token", Code: s.currentCode()})
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response userdto.LoginResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(s.T(), response.Token)
+	assert.NotEmpty(s.T(), response.RefreshToken)
+}
+
+func (s *TwoFactorHandlerTestSuite) TestVerifyLogin_InvalidCode() {
+	challenge := &userentity.MFAChallenge{ID: uuid.New(), UserID: s.user.ID, ExpiresAt: time.Now().Add(time.Minute)}
+	s.challengeRepo.On("GetByHash", mock.Anything, mock.Anything).Return(challenge, nil)
+	s.challengeRepo.On("ReserveAttempt", mock.Anything, challenge.ID, mock.Anything).Return(nil)
+	s.totpRepo.On("GetByUserID", mock.Anything, s.user.ID).Return(s.enrolled(), nil)
+	s.recoveryRepo.On("Consume", mock.Anything, s.user.ID, mock.Anything).Return(userrepo.ErrTokenAlreadyUsed)
+
+	w := s.post("/api/v1/users/login/2fa", userdto.TwoFactorLoginRequest{MFAToken: "mfa-token", Code: "AAAAA-BBBBB"})
+
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+}
+
+func (s *TwoFactorHandlerTestSuite) TestVerifyLogin_UnknownChallenge() {
+	s.challengeRepo.On("GetByHash", mock.Anything, mock.Anything).Return(nil, pgx.ErrNoRows)
+
+	w := s.post("/api/v1/users/login/2fa", userdto.TwoFactorLoginRequest{MFAToken: "bogus", Code: "123456"})
+
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+}
+
+func TestTwoFactorHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorHandlerTestSuite))
+}
@@ -2,6 +2,7 @@ package userhandler
 
 import (
 	"errors"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -15,17 +16,26 @@ type UserHandler struct {
 	createUserUseCase *userusecase.CreateUserUseCase
 	registerUseCase   *userusecase.RegisterUseCase
 	loginUseCase      *userusecase.LoginUseCase
+	refreshUseCase    *userusecase.RefreshTokenUseCase
+	logoutUseCase     *userusecase.LogoutUseCase
+	logoutAllUseCase  *userusecase.LogoutAllUseCase
 }
 
 func NewUserHandler(
 	createUserUseCase *userusecase.CreateUserUseCase,
 	registerUseCase *userusecase.RegisterUseCase,
 	loginUseCase *userusecase.LoginUseCase,
+	refreshUseCase *userusecase.RefreshTokenUseCase,
+	logoutUseCase *userusecase.LogoutUseCase,
+	logoutAllUseCase *userusecase.LogoutAllUseCase,
 ) *UserHandler {
 	return &UserHandler{
 		createUserUseCase: createUserUseCase,
 		registerUseCase:   registerUseCase,
 		loginUseCase:      loginUseCase,
+		refreshUseCase:    refreshUseCase,
+		logoutUseCase:     logoutUseCase,
+		logoutAllUseCase:  logoutAllUseCase,
 	}
 }
 
@@ -54,8 +64,11 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		} else {
 			var passwordErr *userusecase.PasswordValidationError
 			if errors.As(err, &passwordErr) {
-				statusCode = http.StatusBadRequest
-				errorMessage = passwordErr.Error()
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   passwordErr.Error(),
+					"reasons": passwordErr.Reasons,
+				})
+				return
 			}
 		}
 
@@ -79,7 +92,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 		return
 	}
 
-	response, err := h.registerUseCase.Execute(c.Request.Context(), req)
+	response, err := h.registerUseCase.Execute(c.Request.Context(), req, clientInfo(c))
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		errorMessage := "Failed to register user"
@@ -93,8 +106,11 @@ func (h *UserHandler) Register(c *gin.Context) {
 		} else {
 			var passwordErr *userusecase.PasswordValidationError
 			if errors.As(err, &passwordErr) {
-				statusCode = http.StatusBadRequest
-				errorMessage = passwordErr.Error()
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   passwordErr.Error(),
+					"reasons": passwordErr.Reasons,
+				})
+				return
 			}
 		}
 
@@ -104,6 +120,10 @@ func (h *UserHandler) Register(c *gin.Context) {
 		return
 	}
 
+	if !setAuthCookies(c, response.TokenPair) {
+		return
+	}
+
 	c.JSON(http.StatusCreated, response)
 }
 
@@ -118,14 +138,22 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.loginUseCase.Execute(c.Request.Context(), req)
+	response, err := h.loginUseCase.Execute(c.Request.Context(), req, clientInfo(c))
 	if err != nil {
 		var statusCode int
 		var errorMessage string
 
-		if errors.Is(err, userusecase.ErrInvalidCredentials) {
+		var throttledErr *userusecase.ThrottledError
+		if errors.As(err, &throttledErr) {
+			setRetryAfter(c, throttledErr)
+			statusCode = http.StatusTooManyRequests
+			errorMessage = "Too many failed login attempts, please try again later"
+		} else if errors.Is(err, userusecase.ErrInvalidCredentials) {
 			statusCode = http.StatusUnauthorized
 			errorMessage = "Invalid email or password"
+		} else if errors.Is(err, userusecase.ErrAccountSuspended) {
+			statusCode = http.StatusForbidden
+			errorMessage = "Account is suspended"
 		} else {
 			statusCode = http.StatusInternalServerError
 			errorMessage = "Failed to login"
@@ -137,10 +165,104 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
+	if response.MFAChallenge != nil {
+		c.JSON(http.StatusOK, response.MFAChallenge)
+		return
+	}
+
+	if !setAuthCookies(c, response.TokenPair) {
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
-func (h *UserHandler) GetProfile(c *gin.Context) {
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var req userdto.RefreshTokenRequest
+
+	if refreshToken, ok := refreshTokenCookie(c); ok {
+		req.RefreshToken = refreshToken
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.refreshUseCase.Execute(c.Request.Context(), req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to refresh token"
+
+		if errors.Is(err, userusecase.ErrRefreshTokenReused) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Refresh token has already been used"
+		} else if errors.Is(err, userusecase.ErrInvalidRefreshToken) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid or expired refresh token"
+		} else if errors.Is(err, userusecase.ErrAccountSuspended) {
+			statusCode = http.StatusForbidden
+			errorMessage = "Account is suspended"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	if !setAuthCookies(c, response.TokenPair) {
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) Logout(c *gin.Context) {
+	claims, exists := middleware.GetClaims(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Token claims not found in context",
+		})
+		return
+	}
+
+	var req userdto.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+	if req.RefreshToken == "" {
+		req.RefreshToken, _ = refreshTokenCookie(c)
+	}
+
+	if err := h.logoutUseCase.Execute(c.Request.Context(), claims, req); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to logout"
+
+		if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	clearAuthCookies(c)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out",
+	})
+}
+
+func (h *UserHandler) LogoutAll(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{
@@ -149,12 +271,24 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
-	username, _ := middleware.GetUsername(c)
-	email, _ := middleware.GetEmail(c)
+	if err := h.logoutAllUseCase.Execute(c.Request.Context(), userID); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to logout from all devices"
+
+		if errors.Is(err, userusecase.ErrInvalidTokenClaims) {
+			statusCode = http.StatusUnauthorized
+			errorMessage = "Invalid token"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+
+	clearAuthCookies(c)
 
 	c.JSON(http.StatusOK, gin.H{
-		"user_id":  userID,
-		"username": username,
-		"email":    email,
+		"message": "Logged out from all devices",
 	})
 }
@@ -12,19 +12,35 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
-	"golang.org/x/crypto/bcrypt"
 
 	userdto "roadmap/internal/domain/dto/user"
 	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/handler/middleware"
 	jwtservice "roadmap/internal/pkg/jwt"
+	"roadmap/internal/pkg/password"
 	userrepo "roadmap/internal/repository/user"
 	userusecase "roadmap/internal/usecase/user"
 )
 
+// testPasswordHasher keeps hashing in tests cheap.
+var testPasswordHasher = password.NewHasher(password.Argon2idParams{
+	Memory:      64,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+})
+
+// testPasswordValidator only applies the length and character class rules,
+// which the password fixtures are written against.
+var testPasswordValidator = userusecase.NewPasswordValidator(userusecase.PasswordPolicy{})
+
 type MockUserRepository struct {
 	mock.Mock
 }
@@ -63,26 +79,256 @@ func (m *MockUserRepository) UsernameExists(ctx context.Context, username string
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockUserRepository) UpdateProfile(ctx context.Context, user *userentity.User) (*userentity.User, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	args := m.Called(ctx, id, email)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	args := m.Called(ctx, id, verifiedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) List(
+	ctx context.Context,
+	filter userrepo.UserListFilter,
+) ([]*userentity.User, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*userentity.User), args.Int(1), args.Error(2)
+}
+
+func (m *MockUserRepository) IsSuspended(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Suspend(ctx context.Context, id uuid.UUID, reason string, suspendedAt time.Time) error {
+	args := m.Called(ctx, id, reason, suspendedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Unsuspend(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SoftDelete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
+	args := m.Called(ctx, id, deletedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(
+	ctx context.Context,
+	token *userentity.RefreshToken,
+) (*userentity.RefreshToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*userentity.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Rotate(
+	ctx context.Context,
+	oldID uuid.UUID,
+	replacement *userentity.RefreshToken,
+) (*userentity.RefreshToken, error) {
+	args := m.Called(ctx, oldID, replacement)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type MockTokenRevocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRevocationRepository) RevokeToken(
+	ctx context.Context,
+	jti string,
+	userID uuid.UUID,
+	expiresAt time.Time,
+) error {
+	args := m.Called(ctx, jti, userID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	args := m.Called(ctx, jti)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRevocationRepository) SetTokensValidAfter(
+	ctx context.Context,
+	userID uuid.UUID,
+	validAfter time.Time,
+) error {
+	args := m.Called(ctx, userID, validAfter)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) GetTokensValidAfter(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockTokenRevocationRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockLoginThrottleRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginThrottleRepository) Get(ctx context.Context, scope, key string) (*userentity.LoginThrottle, error) {
+	args := m.Called(ctx, scope, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.LoginThrottle), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) RecordFailure(
+	ctx context.Context,
+	scope, key string,
+	now, forgetBefore time.Time,
+) (*userentity.LoginThrottle, error) {
+	args := m.Called(ctx, scope, key, now, forgetBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.LoginThrottle), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) Lock(ctx context.Context, scope, key string, until time.Time) error {
+	args := m.Called(ctx, scope, key, until)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleRepository) Reset(ctx context.Context, scope, key string) error {
+	args := m.Called(ctx, scope, key)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleRepository) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 type UserHandlerTestSuite struct {
 	suite.Suite
-	handler  *UserHandler
-	mockRepo *MockUserRepository
-	useCase  *userusecase.CreateUserUseCase
-	router   *gin.Engine
+	handler     *UserHandler
+	mockRepo    *MockUserRepository
+	refreshRepo *MockRefreshTokenRepository
+	verifyRepo  *MockEmailVerificationTokenRepository
+	mailer      *MockMailer
+	totpRepo    *MockTOTPRepository
+	mfaRepo     *MockMFAChallengeRepository
+	useCase     *userusecase.CreateUserUseCase
+	router      *gin.Engine
+}
+
+func (s *UserHandlerTestSuite) newTokenIssuer() *userusecase.TokenIssuer {
+	return userusecase.NewTokenIssuer(
+		jwtservice.NewJWTService("test-secret", 15*time.Minute),
+		s.refreshRepo,
+		nil,
+		30*24*time.Hour,
+	)
+}
+
+func (s *UserHandlerTestSuite) newVerificationSender() *userusecase.EmailVerificationSender {
+	return userusecase.NewEmailVerificationSender(s.verifyRepo, s.mailer, "http://localhost:3000/verify-email", 24*time.Hour)
+}
+
+func (s *UserHandlerTestSuite) newTwoFactorChallenger() *userusecase.TwoFactorChallenger {
+	return userusecase.NewTwoFactorChallenger(s.totpRepo, s.mfaRepo, 5*time.Minute)
+}
+
+func newTestCookieAuth() *middleware.CookieAuth {
+	return middleware.NewCookieAuth(middleware.CookieAuthConfig{
+		RefreshTokenPath: "/api/v1/users",
+		Secure:           true,
+		SameSite:         http.SameSiteLaxMode,
+	})
 }
 
 func (s *UserHandlerTestSuite) SetupTest() {
 	gin.SetMode(gin.TestMode)
 	s.mockRepo = new(MockUserRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.verifyRepo = new(MockEmailVerificationTokenRepository)
+	s.mailer = new(MockMailer)
+	s.totpRepo = new(MockTOTPRepository)
+	s.totpRepo.On("GetByUserID", mock.Anything, mock.Anything).Return(nil, pgx.ErrNoRows).Maybe()
+	s.mfaRepo = new(MockMFAChallengeRepository)
 	var repo userrepo.UserRepository = s.mockRepo
-	s.useCase = userusecase.NewCreateUserUseCase(repo)
-	s.handler = NewUserHandler(s.useCase, nil, nil)
+	s.useCase = userusecase.NewCreateUserUseCase(repo, testPasswordHasher, testPasswordValidator)
+	s.handler = NewUserHandler(s.useCase, nil, nil, nil, nil, nil)
 	s.router = gin.New()
 	s.router.POST("/api/v1/users", s.handler.CreateUser)
 }
 
 func (s *UserHandlerTestSuite) TearDownTest() {
 	s.mockRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.verifyRepo.AssertExpectations(s.T())
+	s.mailer.AssertExpectations(s.T())
+	s.totpRepo.AssertExpectations(s.T())
+	s.mfaRepo.AssertExpectations(s.T())
 }
 
 func (s *UserHandlerTestSuite) TestCreateUser_Success() {
@@ -900,9 +1146,9 @@ func (s *UserHandlerTestSuite) TestRegister_Success() {
 	s.mockRepo.ExpectedCalls = nil
 	s.mockRepo.Calls = nil
 
-	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase)
+	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, testPasswordHasher, testPasswordValidator, s.newTokenIssuer(), s.newVerificationSender())
+	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, testPasswordHasher, s.newTokenIssuer(), s.newTwoFactorChallenger(), nil)
+	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase, nil, nil, nil)
 	s.router = gin.New()
 	s.router.POST("/api/v1/users/register", s.handler.Register)
 
@@ -926,6 +1172,10 @@ func (s *UserHandlerTestSuite) TestRegister_Success() {
 	s.mockRepo.On("EmailExists", mock.Anything, requestBody.Email).Return(false, nil)
 	s.mockRepo.On("UsernameExists", mock.Anything, requestBody.Username).Return(false, nil)
 	s.mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.User")).Return(createdUser, nil)
+	s.verifyRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.EmailVerificationToken")).
+		Return(&userentity.EmailVerificationToken{}, nil)
+	s.mailer.On("Send", mock.Anything, mock.AnythingOfType("mailer.Message")).Return(nil)
+	s.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
 
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/register", bytes.NewBuffer(body))
@@ -949,9 +1199,9 @@ func (s *UserHandlerTestSuite) TestRegister_EmailAlreadyExists() {
 	s.mockRepo.ExpectedCalls = nil
 	s.mockRepo.Calls = nil
 
-	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase)
+	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, testPasswordHasher, testPasswordValidator, s.newTokenIssuer(), s.newVerificationSender())
+	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, testPasswordHasher, s.newTokenIssuer(), s.newTwoFactorChallenger(), nil)
+	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase, nil, nil, nil)
 	s.router = gin.New()
 	s.router.POST("/api/v1/users/register", s.handler.Register)
 
@@ -982,10 +1232,10 @@ func (s *UserHandlerTestSuite) TestLogin_Success() {
 	s.mockRepo.ExpectedCalls = nil
 	s.mockRepo.Calls = nil
 
-	jwtService := jwtservice.NewJWTService("test-secret", 24*3600*1000000000)
-	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, jwtService)
-	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, jwtService)
-	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase)
+	tokenIssuer := s.newTokenIssuer()
+	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, testPasswordHasher, testPasswordValidator, tokenIssuer, s.newVerificationSender())
+	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, testPasswordHasher, tokenIssuer, s.newTwoFactorChallenger(), nil)
+	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase, nil, nil, nil)
 	s.router = gin.New()
 	s.router.POST("/api/v1/users/login", s.handler.Login)
 
@@ -994,19 +1244,20 @@ func (s *UserHandlerTestSuite) TestLogin_Success() {
 		Password: "SecurePass123!",
 	}
 
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(requestBody.Password), bcrypt.DefaultCost)
+	hashedPassword, _ := testPasswordHasher.Hash(requestBody.Password)
 	userID := uuid.New()
 	now := time.Now()
 	user := &userentity.User{
 		ID:           userID,
 		Username:     "testuser",
 		Email:        requestBody.Email,
-		PasswordHash: string(hashedPassword),
+		PasswordHash: hashedPassword,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
 
 	s.mockRepo.On("GetByEmail", mock.Anything, requestBody.Email).Return(user, nil)
+	s.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
 
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/login", bytes.NewBuffer(body))
@@ -1021,16 +1272,65 @@ func (s *UserHandlerTestSuite) TestLogin_Success() {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(s.T(), err)
 	assert.NotEmpty(s.T(), response.Token)
+	assert.NotEmpty(s.T(), response.RefreshToken)
+}
+
+func (s *UserHandlerTestSuite) TestLogin_CookieMode() {
+	s.mockRepo.ExpectedCalls = nil
+	s.mockRepo.Calls = nil
+
+	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, testPasswordHasher, s.newTokenIssuer(), s.newTwoFactorChallenger(), nil)
+	s.handler = NewUserHandler(s.useCase, nil, loginUseCase, nil, nil, nil)
+	s.router = gin.New()
+	s.router.Use(newTestCookieAuth().Middleware())
+	s.router.POST("/api/v1/users/login", s.handler.Login)
+
+	hashedPassword, _ := testPasswordHasher.Hash("SecurePass123!")
+	user := &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser",
+		Email:        "test@example.com",
+		PasswordHash: hashedPassword,
+	}
+
+	s.mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	s.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
+
+	body, _ := json.Marshal(userdto.LoginRequest{Email: user.Email, Password: "SecurePass123!"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response userdto.LoginResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+
+	cookies := make(map[string]*http.Cookie)
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	if assert.Contains(s.T(), cookies, middleware.AccessTokenCookie) {
+		assert.Equal(s.T(), response.Token, cookies[middleware.AccessTokenCookie].Value)
+		assert.True(s.T(), cookies[middleware.AccessTokenCookie].HttpOnly)
+	}
+	if assert.Contains(s.T(), cookies, middleware.RefreshTokenCookie) {
+		assert.Equal(s.T(), response.RefreshToken, cookies[middleware.RefreshTokenCookie].Value)
+	}
+	assert.Contains(s.T(), cookies, middleware.CSRFCookie)
 }
 
 func (s *UserHandlerTestSuite) TestLogin_InvalidCredentials() {
 	s.mockRepo.ExpectedCalls = nil
 	s.mockRepo.Calls = nil
 
-	jwtService := jwtservice.NewJWTService("test-secret", 24*3600*1000000000)
-	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, jwtService)
-	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, jwtService)
-	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase)
+	tokenIssuer := s.newTokenIssuer()
+	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, testPasswordHasher, testPasswordValidator, tokenIssuer, s.newVerificationSender())
+	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, testPasswordHasher, tokenIssuer, s.newTwoFactorChallenger(), nil)
+	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase, nil, nil, nil)
 	s.router = gin.New()
 	s.router.POST("/api/v1/users/login", s.handler.Login)
 
@@ -1039,14 +1339,14 @@ func (s *UserHandlerTestSuite) TestLogin_InvalidCredentials() {
 		Password: "WrongPassword123!",
 	}
 
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("SecurePass123!"), bcrypt.DefaultCost)
+	hashedPassword, _ := testPasswordHasher.Hash("SecurePass123!")
 	userID := uuid.New()
 	now := time.Now()
 	user := &userentity.User{
 		ID:           userID,
 		Username:     "testuser",
 		Email:        requestBody.Email,
-		PasswordHash: string(hashedPassword),
+		PasswordHash: hashedPassword,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -1068,70 +1368,88 @@ func (s *UserHandlerTestSuite) TestLogin_InvalidCredentials() {
 	assert.Equal(s.T(), "Invalid email or password", response["error"])
 }
 
-func (s *UserHandlerTestSuite) TestGetProfile_Success() {
+func (s *UserHandlerTestSuite) TestLogin_Suspended() {
 	s.mockRepo.ExpectedCalls = nil
 	s.mockRepo.Calls = nil
 
-	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase)
+	tokenIssuer := s.newTokenIssuer()
+	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, testPasswordHasher, testPasswordValidator, tokenIssuer, s.newVerificationSender())
+	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, testPasswordHasher, tokenIssuer, s.newTwoFactorChallenger(), nil)
+	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase, nil, nil, nil)
 	s.router = gin.New()
-	s.router.GET("/api/v1/users/profile", s.handler.GetProfile)
+	s.router.POST("/api/v1/users/login", s.handler.Login)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/profile", nil)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request = req
+	requestBody := userdto.LoginRequest{
+		Email:    "test@example.com",
+		Password: "SecurePass123!",
+	}
+
+	hashedPassword, _ := testPasswordHasher.Hash(requestBody.Password)
+	now := time.Now()
+	user := &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser",
+		Email:        requestBody.Email,
+		PasswordHash: hashedPassword,
+		SuspendedAt:  &now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
 
-	c.Set("user_id", "550e8400-e29b-41d4-a716-446655440000")
-	c.Set("username", "testuser")
-	c.Set("email", "test@example.com")
+	s.mockRepo.On("GetByEmail", mock.Anything, requestBody.Email).Return(user, nil)
 
-	s.handler.GetProfile(c)
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
 
-	assert.Equal(s.T(), http.StatusOK, w.Code)
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusForbidden, w.Code)
 
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(s.T(), err)
-	assert.Equal(s.T(), "550e8400-e29b-41d4-a716-446655440000", response["user_id"])
-	assert.Equal(s.T(), "testuser", response["username"])
-	assert.Equal(s.T(), "test@example.com", response["email"])
+	assert.Equal(s.T(), "Account is suspended", response["error"])
 }
 
-func (s *UserHandlerTestSuite) TestGetProfile_NoUserID() {
-	s.mockRepo.ExpectedCalls = nil
-	s.mockRepo.Calls = nil
-
-	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase)
+func (s *UserHandlerTestSuite) TestLogin_Throttled() {
+	throttleRepo := new(MockLoginThrottleRepository)
+	lockedUntil := time.Now().UTC().Add(90 * time.Second)
+	throttleRepo.On("Get", mock.Anything, userentity.LoginThrottleScopeAccount, "test@example.com").
+		Return(&userentity.LoginThrottle{Failures: 10, LockedUntil: &lockedUntil}, nil)
+	throttleRepo.On("Get", mock.Anything, userentity.LoginThrottleScopeIP, "192.0.2.1").
+		Return(nil, pgx.ErrNoRows)
+
+	throttle := userusecase.NewLoginThrottle(
+		throttleRepo, userusecase.LoginThrottlePolicy{}, userusecase.LoginThrottlePolicy{}, nil,
+	)
+	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, testPasswordHasher, s.newTokenIssuer(), s.newTwoFactorChallenger(), throttle)
+	s.handler = NewUserHandler(s.useCase, nil, loginUseCase, nil, nil, nil)
 	s.router = gin.New()
-	s.router.GET("/api/v1/users/profile", s.handler.GetProfile)
+	s.router.POST("/api/v1/users/login", s.handler.Login)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/profile", nil)
+	body, _ := json.Marshal(userdto.LoginRequest{Email: "test@example.com", Password: "SecurePass123!"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "192.0.2.1:4321"
 	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request = req
-
-	s.handler.GetProfile(c)
 
-	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+	s.router.ServeHTTP(w, req)
 
-	var response map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	assert.NoError(s.T(), err)
-	assert.Equal(s.T(), "User ID not found in context", response["error"])
+	assert.Equal(s.T(), http.StatusTooManyRequests, w.Code)
+	assert.Equal(s.T(), "90", w.Header().Get("Retry-After"))
+	throttleRepo.AssertExpectations(s.T())
 }
 
 func (s *UserHandlerTestSuite) TestLogin_InternalServerError() {
 	s.mockRepo.ExpectedCalls = nil
 	s.mockRepo.Calls = nil
 
-	jwtService := jwtservice.NewJWTService("test-secret", 24*3600*1000000000)
-	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, jwtService)
-	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, jwtService)
-	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase)
+	tokenIssuer := s.newTokenIssuer()
+	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, testPasswordHasher, testPasswordValidator, tokenIssuer, s.newVerificationSender())
+	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, testPasswordHasher, tokenIssuer, s.newTwoFactorChallenger(), nil)
+	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase, nil, nil, nil)
 	s.router = gin.New()
 	s.router.POST("/api/v1/users/login", s.handler.Login)
 
@@ -1173,9 +1491,9 @@ func (s *UserHandlerTestSuite) TestRegister_PasswordValidationError() {
 	s.mockRepo.ExpectedCalls = nil
 	s.mockRepo.Calls = nil
 
-	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase)
+	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, testPasswordHasher, testPasswordValidator, s.newTokenIssuer(), s.newVerificationSender())
+	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, testPasswordHasher, s.newTokenIssuer(), s.newTwoFactorChallenger(), nil)
+	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase, nil, nil, nil)
 	s.router = gin.New()
 	s.router.POST("/api/v1/users/register", s.handler.Register)
 
@@ -1207,9 +1525,9 @@ func (s *UserHandlerTestSuite) TestRegister_InternalServerError() {
 	s.mockRepo.ExpectedCalls = nil
 	s.mockRepo.Calls = nil
 
-	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, jwtservice.NewJWTService("test-secret", 24*3600*1000000000))
-	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase)
+	registerUseCase := userusecase.NewRegisterUseCase(s.mockRepo, testPasswordHasher, testPasswordValidator, s.newTokenIssuer(), s.newVerificationSender())
+	loginUseCase := userusecase.NewLoginUseCase(s.mockRepo, testPasswordHasher, s.newTokenIssuer(), s.newTwoFactorChallenger(), nil)
+	s.handler = NewUserHandler(s.useCase, registerUseCase, loginUseCase, nil, nil, nil)
 	s.router = gin.New()
 	s.router.POST("/api/v1/users/register", s.handler.Register)
 
@@ -1236,6 +1554,318 @@ func (s *UserHandlerTestSuite) TestRegister_InternalServerError() {
 	assert.Equal(s.T(), "Failed to register user", response["error"])
 }
 
+func (s *UserHandlerTestSuite) setupRefreshRouter() {
+	s.mockRepo.ExpectedCalls = nil
+	s.mockRepo.Calls = nil
+
+	refreshUseCase := userusecase.NewRefreshTokenUseCase(s.mockRepo, s.refreshRepo, s.newTokenIssuer())
+	s.handler = NewUserHandler(s.useCase, nil, nil, refreshUseCase, nil, nil)
+	s.router = gin.New()
+	s.router.POST("/api/v1/users/token/refresh", s.handler.RefreshToken)
+}
+
+func (s *UserHandlerTestSuite) TestRefreshToken_Success() {
+	s.setupRefreshRouter()
+
+	now := time.Now()
+	user := &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser",
+		Email:        "test@example.com",
+		PasswordHash: "$2a$10$hashed",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	current := &userentity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  uuid.New(),
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	}
+
+	s.refreshRepo.On("GetByHash", mock.Anything, mock.AnythingOfType("string")).Return(current, nil)
+	s.mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	s.refreshRepo.On("Rotate", mock.Anything, current.ID, mock.AnythingOfType("*user.RefreshToken")).Return(current, nil)
+
+	body, _ := json.Marshal(userdto.RefreshTokenRequest{RefreshToken: "old-refresh-token"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/token/refresh", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response userdto.RefreshTokenResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), response.Token)
+	assert.NotEmpty(s.T(), response.RefreshToken)
+	assert.NotEqual(s.T(), "old-refresh-token", response.RefreshToken)
+}
+
+func (s *UserHandlerTestSuite) TestRefreshToken_InvalidToken() {
+	s.setupRefreshRouter()
+
+	s.refreshRepo.On("GetByHash", mock.Anything, mock.AnythingOfType("string")).Return(nil, errors.New("not found"))
+
+	body, _ := json.Marshal(userdto.RefreshTokenRequest{RefreshToken: "unknown"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/token/refresh", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "Invalid or expired refresh token", response["error"])
+}
+
+func (s *UserHandlerTestSuite) TestRefreshToken_Reused() {
+	s.setupRefreshRouter()
+
+	revokedAt := time.Now()
+	current := &userentity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+		RevokedAt: &revokedAt,
+	}
+
+	s.refreshRepo.On("GetByHash", mock.Anything, mock.AnythingOfType("string")).Return(current, nil)
+	s.refreshRepo.On("RevokeFamily", mock.Anything, current.FamilyID).Return(nil)
+
+	body, _ := json.Marshal(userdto.RefreshTokenRequest{RefreshToken: "reused"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/token/refresh", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "Refresh token has already been used", response["error"])
+}
+
+func (s *UserHandlerTestSuite) TestRefreshToken_MissingToken() {
+	s.setupRefreshRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/token/refresh", bytes.NewBufferString("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+	s.refreshRepo.AssertNotCalled(s.T(), "GetByHash", mock.Anything, mock.Anything)
+}
+
+func (s *UserHandlerTestSuite) TestRefreshToken_FromCookie() {
+	s.setupRefreshRouter()
+	s.router = gin.New()
+	s.router.Use(newTestCookieAuth().Middleware())
+	s.router.POST("/api/v1/users/token/refresh", s.handler.RefreshToken)
+
+	now := time.Now()
+	user := &userentity.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com"}
+	current := &userentity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  uuid.New(),
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	}
+
+	s.refreshRepo.On("GetByHash", mock.Anything, mock.AnythingOfType("string")).Return(current, nil)
+	s.mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	s.refreshRepo.On("Rotate", mock.Anything, current.ID, mock.AnythingOfType("*user.RefreshToken")).Return(current, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/token/refresh", nil)
+	req.AddCookie(&http.Cookie{Name: middleware.RefreshTokenCookie, Value: "old-refresh-token"})
+	req.AddCookie(&http.Cookie{Name: middleware.CSRFCookie, Value: "csrf"})
+	req.Header.Set(middleware.CSRFHeader, "csrf")
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var refreshCookie *http.Cookie
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == middleware.RefreshTokenCookie {
+			refreshCookie = cookie
+		}
+	}
+	if assert.NotNil(s.T(), refreshCookie) {
+		assert.NotEqual(s.T(), "old-refresh-token", refreshCookie.Value)
+	}
+}
+
+func (s *UserHandlerTestSuite) setupLogoutRouter(revocationRepo *MockTokenRevocationRepository, claims *jwtservice.Claims) {
+	revocationService := userusecase.NewTokenRevocationService(revocationRepo, time.Minute)
+	logoutUseCase := userusecase.NewLogoutUseCase(s.refreshRepo, revocationService)
+	logoutAllUseCase := userusecase.NewLogoutAllUseCase(s.refreshRepo, revocationService)
+	s.handler = NewUserHandler(s.useCase, nil, nil, nil, logoutUseCase, logoutAllUseCase)
+	s.router = gin.New()
+	setClaims := func(c *gin.Context) {
+		if claims != nil {
+			c.Set("user_id", claims.UserID)
+			c.Set("claims", claims)
+		}
+		c.Next()
+	}
+	s.router.POST("/api/v1/users/logout", setClaims, s.handler.Logout)
+	s.router.POST("/api/v1/users/logout-all", setClaims, s.handler.LogoutAll)
+}
+
+func (s *UserHandlerTestSuite) TestLogout_Success() {
+	revocationRepo := new(MockTokenRevocationRepository)
+	userID := uuid.New()
+	claims := &jwtservice.Claims{UserID: userID.String()}
+	claims.ID = uuid.NewString()
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Minute))
+	s.setupLogoutRouter(revocationRepo, claims)
+
+	refreshToken := &userentity.RefreshToken{ID: uuid.New(), UserID: userID, FamilyID: uuid.New()}
+	revocationRepo.On("RevokeToken", mock.Anything, claims.ID, userID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("GetByHash", mock.Anything, mock.AnythingOfType("string")).Return(refreshToken, nil)
+	s.refreshRepo.On("RevokeFamily", mock.Anything, refreshToken.FamilyID).Return(nil)
+
+	body, _ := json.Marshal(userdto.LogoutRequest{RefreshToken: "refresh"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/logout", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	revocationRepo.AssertExpectations(s.T())
+}
+
+func (s *UserHandlerTestSuite) TestLogout_EmptyBody() {
+	revocationRepo := new(MockTokenRevocationRepository)
+	userID := uuid.New()
+	claims := &jwtservice.Claims{UserID: userID.String()}
+	claims.ID = uuid.NewString()
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Minute))
+	s.setupLogoutRouter(revocationRepo, claims)
+
+	revocationRepo.On("RevokeToken", mock.Anything, claims.ID, userID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/logout", nil)
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	revocationRepo.AssertExpectations(s.T())
+}
+
+func (s *UserHandlerTestSuite) TestLogout_CookieMode() {
+	revocationRepo := new(MockTokenRevocationRepository)
+	userID := uuid.New()
+	claims := &jwtservice.Claims{UserID: userID.String()}
+	claims.ID = uuid.NewString()
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Minute))
+	s.setupLogoutRouter(revocationRepo, claims)
+	s.router = gin.New()
+	s.router.Use(newTestCookieAuth().Middleware())
+	s.router.POST("/api/v1/users/logout", func(c *gin.Context) {
+		c.Set("user_id", claims.UserID)
+		c.Set("claims", claims)
+	}, s.handler.Logout)
+
+	refreshToken := &userentity.RefreshToken{ID: uuid.New(), UserID: userID, FamilyID: uuid.New()}
+	revocationRepo.On("RevokeToken", mock.Anything, claims.ID, userID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("GetByHash", mock.Anything, mock.AnythingOfType("string")).Return(refreshToken, nil)
+	s.refreshRepo.On("RevokeFamily", mock.Anything, refreshToken.FamilyID).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/logout", nil)
+	req.AddCookie(&http.Cookie{Name: middleware.RefreshTokenCookie, Value: "refresh"})
+	req.AddCookie(&http.Cookie{Name: middleware.CSRFCookie, Value: "csrf"})
+	req.Header.Set(middleware.CSRFHeader, "csrf")
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	revocationRepo.AssertExpectations(s.T())
+
+	cookies := w.Result().Cookies()
+	assert.Len(s.T(), cookies, 3)
+	for _, cookie := range cookies {
+		assert.Empty(s.T(), cookie.Value)
+	}
+}
+
+func (s *UserHandlerTestSuite) TestLogout_CookieModeWithoutCSRFToken() {
+	s.setupLogoutRouter(new(MockTokenRevocationRepository), nil)
+	s.router = gin.New()
+	s.router.Use(newTestCookieAuth().Middleware())
+	s.router.POST("/api/v1/users/logout", s.handler.Logout)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/logout", nil)
+	req.AddCookie(&http.Cookie{Name: middleware.AccessTokenCookie, Value: "token"})
+	req.AddCookie(&http.Cookie{Name: middleware.CSRFCookie, Value: "csrf"})
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusForbidden, w.Code)
+}
+
+func (s *UserHandlerTestSuite) TestLogout_NoClaims() {
+	s.setupLogoutRouter(new(MockTokenRevocationRepository), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/logout", nil)
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+}
+
+func (s *UserHandlerTestSuite) TestLogoutAll_Success() {
+	revocationRepo := new(MockTokenRevocationRepository)
+	userID := uuid.New()
+	s.setupLogoutRouter(revocationRepo, &jwtservice.Claims{UserID: userID.String()})
+
+	revocationRepo.On("SetTokensValidAfter", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("RevokeAllForUser", mock.Anything, userID).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/logout-all", nil)
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	revocationRepo.AssertExpectations(s.T())
+}
+
+func (s *UserHandlerTestSuite) TestLogoutAll_InternalServerError() {
+	revocationRepo := new(MockTokenRevocationRepository)
+	userID := uuid.New()
+	s.setupLogoutRouter(revocationRepo, &jwtservice.Claims{UserID: userID.String()})
+
+	revocationRepo.On("SetTokensValidAfter", mock.Anything, userID, mock.AnythingOfType("time.Time")).
+		Return(errors.New("database error"))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/logout-all", nil)
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusInternalServerError, w.Code)
+}
+
 func TestUserHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(UserHandlerTestSuite))
 }
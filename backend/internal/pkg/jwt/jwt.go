@@ -2,9 +2,11 @@ package jwt
 
 import (
 	"errors"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
@@ -12,18 +14,54 @@ var (
 	ErrExpiredToken = errors.New("token expired")
 )
 
+// JWTService signs access tokens either with a shared HMAC secret (legacy) or
+// with the asymmetric signing key of a KeySet, in which case the "kid" header
+// selects the verification key and the public keys can be published as JWKS.
 type JWTService struct {
 	secretKey []byte
+	keySet    atomic.Pointer[KeySet]
 	expiresIn time.Duration
 }
 
 type Claims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	UserID        string   `json:"user_id"`
+	Username      string   `json:"username"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	Roles         []string `json:"roles,omitempty"`
+	Permissions   []string `json:"permissions,omitempty"`
+	// SessionID identifies the sign-in the token was issued for. Tokens that
+	// are not bound to a session leave it empty.
+	SessionID string `json:"sid,omitempty"`
+	// IssuedAtMicros is the issue time in Unix microseconds. The registered
+	// iat claim only has whole seconds, which cannot tell a token issued just
+	// before a revocation from one issued just after it.
+	IssuedAtMicros int64 `json:"iat_us,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// IssuedAtTime returns when the token was issued, to the microsecond if the
+// token records it and to the second otherwise. It is zero if the token has
+// no issue time.
+func (c *Claims) IssuedAtTime() time.Time {
+	if c.IssuedAtMicros != 0 {
+		return time.UnixMicro(c.IssuedAtMicros)
+	}
+	if c.IssuedAt != nil {
+		return c.IssuedAt.Time
+	}
+	return time.Time{}
+}
+
+func (c *Claims) HasPermission(permission string) bool {
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
 func NewJWTService(secretKey string, expiresIn time.Duration) *JWTService {
 	return &JWTService{
 		secretKey: []byte(secretKey),
@@ -31,28 +69,83 @@ func NewJWTService(secretKey string, expiresIn time.Duration) *JWTService {
 	}
 }
 
+func NewJWTServiceWithKeySet(keySet *KeySet, expiresIn time.Duration) *JWTService {
+	service := &JWTService{
+		expiresIn: expiresIn,
+	}
+	service.keySet.Store(keySet)
+	return service
+}
+
+// SetKeySet atomically replaces the key set, e.g. after keys were rotated on disk.
+func (s *JWTService) SetKeySet(keySet *KeySet) {
+	s.keySet.Store(keySet)
+}
+
+// JWKS returns the public keys that verify tokens issued by this service.
+// It is empty when the service uses an HMAC secret.
+func (s *JWTService) JWKS() JWKS {
+	keySet := s.keySet.Load()
+	if keySet == nil {
+		return JWKS{Keys: []JWK{}}
+	}
+	return keySet.JWKS()
+}
+
+func (s *JWTService) ExpiresIn() time.Duration {
+	return s.expiresIn
+}
+
 func (s *JWTService) GenerateToken(userID, username, email string) (string, error) {
-	claims := &Claims{
+	return s.GenerateTokenWithClaims(Claims{
 		UserID:   userID,
 		Username: username,
 		Email:    email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.expiresIn)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
+	})
+}
+
+// GenerateTokenWithClaims signs the given application claims. The registered
+// claims (jti, iat, nbf, exp) and IssuedAtMicros are always set by the
+// service.
+func (s *JWTService) GenerateTokenWithClaims(claims Claims) (string, error) {
+	now := time.Now()
+	claims.IssuedAtMicros = now.UnixMicro()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		ExpiresAt: jwt.NewNumericDate(now.Add(s.expiresIn)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+
+	keySet := s.keySet.Load()
+	if keySet == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims)
+		return token.SignedString(s.secretKey)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.secretKey)
+	signingKey := keySet.SigningKey()
+	token := jwt.NewWithClaims(signingKey.signingMethod(), &claims)
+	token.Header["kid"] = signingKey.ID
+	return token.SignedString(signingKey.privateKey)
 }
 
 func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
+	keySet := s.keySet.Load()
+
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if keySet == nil {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, ErrInvalidToken
+			}
+			return s.secretKey, nil
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keySet.Key(kid)
+		if !ok || token.Method.Alg() != key.Algorithm {
 			return nil, ErrInvalidToken
 		}
-		return s.secretKey, nil
+		return key.publicKey, nil
 	})
 
 	if err != nil {
@@ -137,6 +137,8 @@ func TestJWTService_TokenContainsCorrectClaims(t *testing.T) {
 	assert.NotNil(t, claims.ExpiresAt)
 	assert.NotNil(t, claims.IssuedAt)
 	assert.NotNil(t, claims.NotBefore)
+	assert.Equal(t, claims.IssuedAt.Unix(), claims.IssuedAtTime().Unix())
+	assert.WithinDuration(t, time.Now(), claims.IssuedAtTime(), time.Minute)
 }
 
 func TestJWTService_TokenExpiration(t *testing.T) {
@@ -198,3 +200,62 @@ func TestJWTService_ValidateToken_NonStandardError(t *testing.T) {
 	assert.Nil(t, claims)
 	assert.Equal(t, ErrInvalidToken, err)
 }
+
+func TestJWTService_ExpiresIn(t *testing.T) {
+	service := NewJWTService("test-secret-key", 15*time.Minute)
+
+	assert.Equal(t, 15*time.Minute, service.ExpiresIn())
+}
+
+func TestJWTService_TokenHasUniqueID(t *testing.T) {
+	service := NewJWTService("test-secret-key", 24*time.Hour)
+
+	token1, _ := service.GenerateToken("user1", "user1", "user1@example.com")
+	token2, _ := service.GenerateToken("user1", "user1", "user1@example.com")
+
+	claims1, err := service.ValidateToken(token1)
+	assert.NoError(t, err)
+	claims2, err := service.ValidateToken(token2)
+	assert.NoError(t, err)
+
+	assert.NotEmpty(t, claims1.ID)
+	assert.NotEqual(t, claims1.ID, claims2.ID)
+}
+
+func TestJWTService_GenerateTokenWithClaims(t *testing.T) {
+	service := NewJWTService("test-secret-key", time.Hour)
+
+	token, err := service.GenerateTokenWithClaims(Claims{
+		UserID:        "user1",
+		Username:      "user1",
+		Email:         "user1@example.com",
+		EmailVerified: true,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(100 * time.Hour)),
+		},
+	})
+	assert.NoError(t, err)
+
+	claims, err := service.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.True(t, claims.EmailVerified)
+	assert.NotEmpty(t, claims.ID)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), claims.ExpiresAt.Time, time.Minute)
+}
+
+func TestJWTService_RolesAndPermissions(t *testing.T) {
+	service := NewJWTService("test-secret-key", time.Hour)
+
+	token, err := service.GenerateTokenWithClaims(Claims{
+		UserID:      "user1",
+		Roles:       []string{"admin", "member"},
+		Permissions: []string{"roles:manage", "users:create"},
+	})
+	assert.NoError(t, err)
+
+	claims, err := service.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin", "member"}, claims.Roles)
+	assert.True(t, claims.HasPermission("users:create"))
+	assert.False(t, claims.HasPermission("users:manage"))
+}
@@ -0,0 +1,287 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	minRSAKeyBits = 2048
+
+	privateKeySuffix = ".pem"
+	publicKeySuffix  = ".pub.pem"
+)
+
+var (
+	ErrNoSigningKey       = errors.New("key set has no signing key")
+	ErrUnsupportedKeyType = errors.New("unsupported key type")
+)
+
+// Key is a single entry of a KeySet. Keys without a private part can only
+// verify tokens; they are kept around during rotation until every token they
+// signed has expired.
+type Key struct {
+	ID         string
+	Algorithm  string
+	privateKey crypto.Signer
+	publicKey  crypto.PublicKey
+}
+
+func NewSigningKey(id string, privateKey crypto.Signer) (*Key, error) {
+	key, err := NewVerificationKey(id, privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	key.privateKey = privateKey
+	return key, nil
+}
+
+func NewVerificationKey(id string, publicKey crypto.PublicKey) (*Key, error) {
+	if id == "" {
+		return nil, errors.New("key id is required")
+	}
+
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("rsa key %q is %d bits, at least %d required", id, pub.N.BitLen(), minRSAKeyBits)
+		}
+		return &Key{ID: id, Algorithm: AlgorithmRS256, publicKey: pub}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: id, Algorithm: AlgorithmEdDSA, publicKey: pub}, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, publicKey)
+	}
+}
+
+func GenerateEd25519Key(id string) (*Key, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ed25519 key: %w", err)
+	}
+	return NewSigningKey(id, privateKey)
+}
+
+func (k *Key) CanSign() bool {
+	return k.privateKey != nil
+}
+
+func (k *Key) signingMethod() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+type KeySet struct {
+	keys       map[string]*Key
+	signingKey *Key
+}
+
+// NewKeySet builds a key set that signs with signingKeyID. When signingKeyID
+// is empty the private key with the greatest id is used, so naming keys by
+// creation date (e.g. "2024-06-01") makes the newest key the active one.
+func NewKeySet(keys []*Key, signingKeyID string) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key, len(keys))}
+
+	for _, key := range keys {
+		if _, exists := ks.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		ks.keys[key.ID] = key
+
+		if !key.CanSign() {
+			continue
+		}
+		if signingKeyID == "" && (ks.signingKey == nil || key.ID > ks.signingKey.ID) {
+			ks.signingKey = key
+		}
+	}
+
+	if signingKeyID != "" {
+		key, ok := ks.keys[signingKeyID]
+		if !ok || !key.CanSign() {
+			return nil, fmt.Errorf("signing key %q not found or has no private key", signingKeyID)
+		}
+		ks.signingKey = key
+	}
+
+	if ks.signingKey == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	return ks, nil
+}
+
+// LoadKeySet reads every "<kid>.pem" (PKCS#8 or PKCS#1 private key) and
+// "<kid>.pub.pem" (PKIX public key, verification only) file in dir. A public
+// key next to the private key of the same kid, as openssl writes them, must
+// match it and is otherwise ignored.
+//
+// To rotate keys, add the new private key and make it the signing key; keep
+// the previous key, or just its public half, until tokens it signed expire,
+// then delete it.
+func LoadKeySet(dir, signingKeyID string) (*KeySet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key directory: %w", err)
+	}
+
+	var keys []*Key
+	loaded := make(map[string]int)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, privateKeySuffix) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name)) // #nosec G304 -- operator-configured key directory
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file %s: %w", name, err)
+		}
+
+		var key *Key
+		if strings.HasSuffix(name, publicKeySuffix) {
+			key, err = parsePublicKeyPEM(strings.TrimSuffix(name, publicKeySuffix), data)
+		} else {
+			key, err = parsePrivateKeyPEM(strings.TrimSuffix(name, privateKeySuffix), data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key file %s: %w", name, err)
+		}
+
+		if i, ok := loaded[key.ID]; ok {
+			if keys[i].CanSign() == key.CanSign() || !samePublicKey(keys[i].publicKey, key.publicKey) {
+				return nil, fmt.Errorf("key file %s does not match the other key with id %q", name, key.ID)
+			}
+			if key.CanSign() {
+				keys[i] = key
+			}
+			continue
+		}
+
+		loaded[key.ID] = len(keys)
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(keys, signingKeyID)
+}
+
+func samePublicKey(a, b crypto.PublicKey) bool {
+	equaler, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && equaler.Equal(b)
+}
+
+func parsePrivateKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, parsed)
+		}
+		return NewSigningKey(id, signer)
+	}
+
+	rsaKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.New("private key is neither PKCS#8 nor PKCS#1")
+	}
+	return NewSigningKey(id, rsaKey)
+}
+
+func parsePublicKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	return NewVerificationKey(id, publicKey)
+}
+
+func (ks *KeySet) SigningKey() *Key {
+	return ks.signingKey
+}
+
+func (ks *KeySet) Key(id string) (*Key, bool) {
+	key, ok := ks.keys[id]
+	return key, ok
+}
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public part of every key, signing key first.
+func (ks *KeySet) JWKS() JWKS {
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		if id != ks.signingKey.ID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	ids = append([]string{ks.signingKey.ID}, ids...)
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(ids))}
+	for _, id := range ids {
+		jwks.Keys = append(jwks.Keys, ks.keys[id].jwk())
+	}
+	return jwks
+}
+
+func (k *Key) jwk() JWK {
+	jwk := JWK{
+		Use:       "sig",
+		Algorithm: k.Algorithm,
+		KeyID:     k.ID,
+	}
+
+	switch pub := k.publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
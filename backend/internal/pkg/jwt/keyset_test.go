@@ -0,0 +1,232 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePrivateKey(t *testing.T, dir, kid string, key any) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600))
+}
+
+func writePublicKey(t *testing.T, dir, kid string, key any) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pub.pem"), data, 0o600))
+}
+
+func TestLoadKeySet_RSAAndEd25519(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	writePrivateKey(t, dir, "2024-01-rsa", rsaKey)
+	writePrivateKey(t, dir, "2024-02-ed", edKey)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("ignored"), 0o600))
+
+	keySet, err := LoadKeySet(dir, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "2024-02-ed", keySet.SigningKey().ID)
+	assert.Equal(t, AlgorithmEdDSA, keySet.SigningKey().Algorithm)
+
+	rsaEntry, ok := keySet.Key("2024-01-rsa")
+	require.True(t, ok)
+	assert.Equal(t, AlgorithmRS256, rsaEntry.Algorithm)
+
+	keySet, err = LoadKeySet(dir, "2024-01-rsa")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-rsa", keySet.SigningKey().ID)
+}
+
+func TestLoadKeySet_Errors(t *testing.T) {
+	_, err := LoadKeySet(filepath.Join(t.TempDir(), "missing"), "")
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writePublicKey(t, dir, "retired", edKey.Public())
+
+	_, err = LoadKeySet(dir, "")
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+
+	_, err = LoadKeySet(dir, "retired")
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not pem"), 0o600))
+	_, err = LoadKeySet(dir, "")
+	assert.Error(t, err)
+}
+
+func TestLoadKeySet_PrivateAndPublicKeyPair(t *testing.T) {
+	dir := t.TempDir()
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writePrivateKey(t, dir, "2024-02-ed", edKey)
+	writePublicKey(t, dir, "2024-02-ed", edKey.Public())
+
+	keySet, err := LoadKeySet(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-02-ed", keySet.SigningKey().ID)
+	assert.True(t, keySet.SigningKey().CanSign())
+	assert.Len(t, keySet.JWKS().Keys, 1)
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writePublicKey(t, dir, "2024-02-ed", otherKey.Public())
+
+	_, err = LoadKeySet(dir, "")
+	assert.ErrorContains(t, err, "does not match")
+}
+
+func TestNewVerificationKey_RejectsWeakRSA(t *testing.T) {
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	_, err = NewSigningKey("weak", weakKey)
+
+	assert.Error(t, err)
+}
+
+func TestJWTService_KeySet_SignAndValidate(t *testing.T) {
+	for _, alg := range []string{AlgorithmRS256, AlgorithmEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			var key *Key
+			if alg == AlgorithmRS256 {
+				rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+				require.NoError(t, err)
+				key, err = NewSigningKey("rsa", rsaKey)
+				require.NoError(t, err)
+			} else {
+				var err error
+				key, err = GenerateEd25519Key("ed")
+				require.NoError(t, err)
+			}
+
+			keySet, err := NewKeySet([]*Key{key}, "")
+			require.NoError(t, err)
+			service := NewJWTServiceWithKeySet(keySet, time.Hour)
+
+			token, err := service.GenerateToken("user1", "user1", "user1@example.com")
+			require.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+			require.NoError(t, err)
+			assert.Equal(t, key.ID, parsed.Header["kid"])
+			assert.Equal(t, alg, parsed.Method.Alg())
+
+			claims, err := service.ValidateToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, "user1", claims.UserID)
+		})
+	}
+}
+
+func TestJWTService_KeySet_Rotation(t *testing.T) {
+	oldKey, err := GenerateEd25519Key("2024-01")
+	require.NoError(t, err)
+	newKey, err := GenerateEd25519Key("2024-02")
+	require.NoError(t, err)
+
+	oldKeySet, err := NewKeySet([]*Key{oldKey}, "")
+	require.NoError(t, err)
+	service := NewJWTServiceWithKeySet(oldKeySet, time.Hour)
+
+	oldToken, err := service.GenerateToken("user1", "user1", "user1@example.com")
+	require.NoError(t, err)
+
+	rotatedKeySet, err := NewKeySet([]*Key{oldKey, newKey}, "")
+	require.NoError(t, err)
+	service.SetKeySet(rotatedKeySet)
+
+	newToken, err := service.GenerateToken("user1", "user1", "user1@example.com")
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-02", parsed.Header["kid"])
+
+	_, err = service.ValidateToken(oldToken)
+	assert.NoError(t, err, "tokens signed by the previous key must still verify")
+
+	retiredKeySet, err := NewKeySet([]*Key{newKey}, "")
+	require.NoError(t, err)
+	service.SetKeySet(retiredKeySet)
+
+	_, err = service.ValidateToken(oldToken)
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = service.ValidateToken(newToken)
+	assert.NoError(t, err)
+}
+
+func TestJWTService_KeySet_RejectsHMACAndUnknownKid(t *testing.T) {
+	key, err := GenerateEd25519Key("ed")
+	require.NoError(t, err)
+	keySet, err := NewKeySet([]*Key{key}, "")
+	require.NoError(t, err)
+	service := NewJWTServiceWithKeySet(keySet, time.Hour)
+
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserID: "user1"})
+	hmacToken.Header["kid"] = "ed"
+	signed, err := hmacToken.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	_, err = service.ValidateToken(signed)
+	assert.Equal(t, ErrInvalidToken, err)
+
+	otherKey, err := GenerateEd25519Key("other")
+	require.NoError(t, err)
+	otherKeySet, err := NewKeySet([]*Key{otherKey}, "")
+	require.NoError(t, err)
+	otherToken, err := NewJWTServiceWithKeySet(otherKeySet, time.Hour).GenerateToken("user1", "user1", "user1@example.com")
+	require.NoError(t, err)
+
+	_, err = service.ValidateToken(otherToken)
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestJWTService_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaEntry, err := NewSigningKey("a-rsa", rsaKey)
+	require.NoError(t, err)
+	edEntry, err := GenerateEd25519Key("b-ed")
+	require.NoError(t, err)
+
+	keySet, err := NewKeySet([]*Key{rsaEntry, edEntry}, "")
+	require.NoError(t, err)
+
+	jwks := NewJWTServiceWithKeySet(keySet, time.Hour).JWKS()
+
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "b-ed", jwks.Keys[0].KeyID, "signing key is listed first")
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+	assert.NotEmpty(t, jwks.Keys[0].X)
+
+	assert.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	assert.Equal(t, AlgorithmRS256, jwks.Keys[1].Algorithm)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+	assert.NotEmpty(t, jwks.Keys[1].N)
+
+	assert.Empty(t, NewJWTService("secret", time.Hour).JWKS().Keys)
+}
@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogMailer writes messages to the application log instead of delivering
+// them. It is meant for local development.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	log.Printf("Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,107 @@
+// Package mailertest runs a minimal in-process SMTP server for tests. It
+// accepts every message without authentication and hands the raw message
+// data to the test.
+package mailertest
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+type SMTPSink struct {
+	Host string
+	Port string
+
+	listener net.Listener
+	messages chan string
+}
+
+func NewSMTPSink() (*SMTPSink, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	s := &SMTPSink{
+		Host:     host,
+		Port:     port,
+		listener: listener,
+		messages: make(chan string, 16),
+	}
+	go s.serve()
+	return s, nil
+}
+
+func (s *SMTPSink) Close() {
+	_ = s.listener.Close()
+}
+
+// Receive returns the data of the next message delivered to the sink.
+func (s *SMTPSink) Receive(timeout time.Duration) (string, error) {
+	select {
+	case message := <-s.messages:
+		return message, nil
+	case <-time.After(timeout):
+		return "", errors.New("no message received")
+	}
+}
+
+func (s *SMTPSink) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *SMTPSink) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP sink")
+	var data strings.Builder
+	inData := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		if inData {
+			if line == ".\r\n" {
+				inData = false
+				s.messages <- data.String()
+				data.Reset()
+				reply("250 OK")
+				continue
+			}
+			data.WriteString(line)
+			continue
+		}
+
+		switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "DATA"):
+			inData = true
+			reply("354 End data with <CR><LF>.<CR><LF>")
+		case strings.HasPrefix(cmd, "QUIT"):
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
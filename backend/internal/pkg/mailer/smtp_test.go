@@ -0,0 +1,115 @@
+package mailer
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"roadmap/internal/pkg/mailer/mailertest"
+)
+
+func TestSMTPMailer_Send(t *testing.T) {
+	sink, err := mailertest.NewSMTPSink()
+	require.NoError(t, err)
+	defer sink.Close()
+
+	mailer := NewSMTPMailer(SMTPConfig{Host: sink.Host, Port: sink.Port, From: "noreply@example.com"})
+
+	err = mailer.Send(context.Background(), Message{
+		To:      "user@example.com",
+		Subject: "Reset your password",
+		Body:    "Follow the link:\nhttps://example.com/reset?token=abc",
+	})
+	require.NoError(t, err)
+
+	message, err := sink.Receive(5 * time.Second)
+	require.NoError(t, err)
+	assert.Contains(t, message, "To: user@example.com\r\n")
+	assert.Contains(t, message, "From: noreply@example.com\r\n")
+	assert.Contains(t, message, "Subject: Reset your password\r\n")
+	assert.Contains(t, message, "https://example.com/reset?token=abc\r\n")
+}
+
+func TestSMTPMailer_RejectsHeaderInjection(t *testing.T) {
+	mailer := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: "1", From: "noreply@example.com"})
+
+	err := mailer.Send(context.Background(), Message{
+		To:      "user@example.com\r\nBcc: victim@example.com",
+		Subject: "Hello",
+	})
+
+	assert.Error(t, err)
+}
+
+func TestSMTPMailer_ConnectionError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, listener.Close())
+
+	mailer := NewSMTPMailer(SMTPConfig{Host: host, Port: port, From: "noreply@example.com"})
+
+	err = mailer.Send(context.Background(), Message{To: "user@example.com", Subject: "Hello"})
+
+	assert.Error(t, err)
+}
+
+// silentServer accepts connections but never sends the SMTP greeting.
+func silentServer(t *testing.T) (host, port string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(io.Discard, conn)
+			}()
+		}
+	}()
+
+	host, port, err = net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return host, port
+}
+
+func TestSMTPMailer_Timeout(t *testing.T) {
+	host, port := silentServer(t)
+	mailer := NewSMTPMailer(SMTPConfig{Host: host, Port: port, From: "noreply@example.com", Timeout: 100 * time.Millisecond})
+
+	start := time.Now()
+	err := mailer.Send(context.Background(), Message{To: "user@example.com", Subject: "Hello"})
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestSMTPMailer_ContextCanceled(t *testing.T) {
+	host, port := silentServer(t)
+	mailer := NewSMTPMailer(SMTPConfig{Host: host, Port: port, From: "noreply@example.com", Timeout: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := mailer.Send(ctx, Message{To: "user@example.com", Subject: "Hello"})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestLogMailer_Send(t *testing.T) {
+	err := NewLogMailer().Send(context.Background(), Message{To: "user@example.com", Subject: "Hello"})
+
+	assert.NoError(t, err)
+}
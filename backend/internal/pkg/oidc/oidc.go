@@ -0,0 +1,230 @@
+// Package oidc implements the relying party side of the OpenID Connect
+// authorization code flow with PKCE: discovery, the authorization redirect,
+// the code exchange and ID token verification.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+
+	defaultHTTPTimeout = 10 * time.Second
+	maxResponseBytes   = 1 << 20
+)
+
+var (
+	ErrDiscovery      = errors.New("oidc discovery failed")
+	ErrTokenExchange  = errors.New("oidc code exchange failed")
+	ErrInvalidIDToken = errors.New("invalid oidc id token")
+
+	providerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+)
+
+var defaultScopes = []string{"openid", "email", "profile"}
+
+type Config struct {
+	// Name identifies the provider in URLs and in stored identities, e.g.
+	// "google". It must be lower case.
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+
+	// Scopes defaults to openid, email and profile.
+	Scopes []string
+
+	HTTPClient *http.Client
+}
+
+// Metadata is the subset of the discovery document the relying party uses.
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Token is the successful response of the token endpoint.
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Provider talks to a single OpenID provider. The discovery document and
+// signing keys are fetched on first use and cached.
+type Provider struct {
+	config Config
+
+	mu       sync.Mutex
+	metadata *Metadata
+	keys     *keyCache
+}
+
+func NewProvider(config Config) (*Provider, error) {
+	if !providerNamePattern.MatchString(config.Name) {
+		return nil, fmt.Errorf("invalid oidc provider name %q", config.Name)
+	}
+	if config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return nil, fmt.Errorf("oidc provider %q needs an issuer, client id and redirect url", config.Name)
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = defaultScopes
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	return &Provider{config: config}, nil
+}
+
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+func (p *Provider) discover(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var metadata Metadata
+	if err := p.getJSON(ctx, strings.TrimRight(p.config.Issuer, "/")+discoveryPath, &metadata); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+
+	// The issuer in the document must match the configured one exactly,
+	// otherwise ID tokens could be accepted from a different issuer.
+	if metadata.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("%w: issuer %q does not match configured %q", ErrDiscovery, metadata.Issuer, p.config.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: discovery document is missing endpoints", ErrDiscovery)
+	}
+
+	p.metadata = &metadata
+	p.keys = newKeyCache(metadata.JWKSURI)
+	return p.metadata, nil
+}
+
+// AuthCodeURL returns the URL the browser is sent to. codeVerifier is kept
+// by the caller and passed to Exchange once the provider redirects back.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid authorization endpoint: %v", ErrDiscovery, err)
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange redeems an authorization code at the token endpoint.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", p.config.ClientID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenExchange, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenExchange, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenExchange, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var tokenError struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		_ = json.Unmarshal(body, &tokenError)
+		return nil, fmt.Errorf("%w: status %d: %s %s", ErrTokenExchange, resp.StatusCode, tokenError.Error, tokenError.Description)
+	}
+
+	var token Token
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenExchange, err)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("%w: response has no id_token", ErrTokenExchange)
+	}
+
+	return &token, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, endpoint string, v any) error {
+	return getJSON(ctx, p.config.HTTPClient, endpoint, v)
+}
+
+func getJSON(ctx context.Context, client *http.Client, endpoint string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned status %d", endpoint, resp.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(v)
+}
@@ -0,0 +1,128 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"roadmap/internal/pkg/oidc/oidctest"
+)
+
+const testRedirectURL = "http://localhost:3000/auth/oidc/mock/callback"
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.Server) {
+	t.Helper()
+
+	server, err := oidctest.NewServer("roadmap", "secret")
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	provider, err := NewProvider(Config{
+		Name:         "mock",
+		Issuer:       server.URL,
+		ClientID:     "roadmap",
+		ClientSecret: "secret",
+		RedirectURL:  testRedirectURL,
+	})
+	require.NoError(t, err)
+
+	return provider, server
+}
+
+func TestNewProvider_Validation(t *testing.T) {
+	_, err := NewProvider(Config{Name: "Google", Issuer: "https://accounts.google.com", ClientID: "id", RedirectURL: "x"})
+	assert.Error(t, err)
+
+	_, err = NewProvider(Config{Name: "google", ClientID: "id", RedirectURL: "x"})
+	assert.Error(t, err)
+}
+
+func TestProvider_CodeFlow(t *testing.T) {
+	provider, server := newTestProvider(t)
+	ctx := context.Background()
+
+	verifier, err := NewCodeVerifier()
+	require.NoError(t, err)
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+	assert.Equal(t, CodeChallenge(verifier), parsed.Query().Get("code_challenge"))
+	assert.Equal(t, "openid email profile", parsed.Query().Get("scope"))
+
+	code, state, err := server.Authorize(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "state-1", state)
+
+	token, err := provider.Exchange(ctx, code, verifier)
+	require.NoError(t, err)
+
+	idToken, err := provider.VerifyIDToken(ctx, token.IDToken, "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, "oidctest-user", idToken.Subject)
+	assert.Equal(t, "oidc@example.com", idToken.Email)
+	assert.True(t, idToken.EmailVerified)
+}
+
+func TestProvider_Exchange_WrongVerifier(t *testing.T) {
+	provider, server := newTestProvider(t)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier-one-verifier-one-verifier-one-00")
+	require.NoError(t, err)
+	code, _, err := server.Authorize(authURL)
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(ctx, code, "verifier-two-verifier-two-verifier-two-00")
+	assert.ErrorIs(t, err, ErrTokenExchange)
+}
+
+func TestProvider_VerifyIDToken_Rejects(t *testing.T) {
+	provider, server := newTestProvider(t)
+	ctx := context.Background()
+	identity := oidctest.Identity{Subject: "user-1", Email: "user@example.com"}
+
+	valid, err := server.SignIDToken(identity, "roadmap", "nonce")
+	require.NoError(t, err)
+	_, err = provider.VerifyIDToken(ctx, valid, "nonce")
+	require.NoError(t, err)
+
+	_, err = provider.VerifyIDToken(ctx, valid, "other-nonce")
+	assert.ErrorIs(t, err, ErrInvalidIDToken, "nonce mismatch")
+
+	otherAudience, err := server.SignIDToken(identity, "someone-else", "nonce")
+	require.NoError(t, err)
+	_, err = provider.VerifyIDToken(ctx, otherAudience, "nonce")
+	assert.ErrorIs(t, err, ErrInvalidIDToken, "audience mismatch")
+
+	otherServer, err := oidctest.NewServer("roadmap", "secret")
+	require.NoError(t, err)
+	defer otherServer.Close()
+	forged, err := otherServer.SignIDToken(identity, "roadmap", "nonce")
+	require.NoError(t, err)
+	_, err = provider.VerifyIDToken(ctx, forged, "nonce")
+	assert.ErrorIs(t, err, ErrInvalidIDToken, "issuer and key mismatch")
+}
+
+func TestProvider_DiscoveryIssuerMismatch(t *testing.T) {
+	server, err := oidctest.NewServer("roadmap", "")
+	require.NoError(t, err)
+	defer server.Close()
+
+	provider, err := NewProvider(Config{
+		Name:        "mock",
+		Issuer:      server.URL + "/",
+		ClientID:    "roadmap",
+		RedirectURL: testRedirectURL,
+	})
+	require.NoError(t, err)
+
+	_, err = provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	assert.ErrorIs(t, err, ErrDiscovery)
+}
@@ -0,0 +1,287 @@
+// Package oidctest runs an in-process OpenID provider for tests. It
+// implements discovery, the authorization endpoint (approving every request
+// straight away), the token endpoint with PKCE checks and a JWKS endpoint.
+package oidctest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "oidctest"
+
+// Identity is the end user the provider signs in.
+type Identity struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+type authorization struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	identity      Identity
+}
+
+type Server struct {
+	// URL is the issuer identifier.
+	URL          string
+	ClientID     string
+	ClientSecret string
+
+	server *httptest.Server
+	key    *ecdsa.PrivateKey
+
+	mu       sync.Mutex
+	identity Identity
+	codes    map[string]authorization
+}
+
+func NewServer(clientID, clientSecret string) (*Server, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		key:          key,
+		codes:        make(map[string]authorization),
+		identity: Identity{
+			Subject:       "oidctest-user",
+			Email:         "oidc@example.com",
+			EmailVerified: true,
+			Name:          "OIDC User",
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("GET /authorize", s.authorize)
+	mux.HandleFunc("POST /token", s.token)
+	mux.HandleFunc("GET /jwks", s.jwks)
+
+	s.server = httptest.NewServer(mux)
+	s.URL = s.server.URL
+	return s, nil
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// SetIdentity changes the user signed in by subsequent authorizations.
+func (s *Server) SetIdentity(identity Identity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.identity = identity
+}
+
+// Authorize plays the browser: it follows authURL and returns the code and
+// state the provider redirects back with.
+func (s *Server) Authorize(authURL string) (code, state string, err error) {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return "", "", fmt.Errorf("authorization returned status %d", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+	return location.Query().Get("code"), location.Query().Get("state"), nil
+}
+
+func (s *Server) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                s.URL,
+		"authorization_endpoint":                s.URL + "/authorize",
+		"token_endpoint":                        s.URL + "/token",
+		"jwks_uri":                              s.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"ES256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != s.ClientID ||
+		query.Get("redirect_uri") == "" || query.Get("code_challenge_method") != "S256" ||
+		query.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+
+	s.mu.Lock()
+	s.codes[code] = authorization{
+		clientID:      query.Get("client_id"),
+		redirectURI:   query.Get("redirect_uri"),
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		identity:      s.identity,
+	}
+	s.mu.Unlock()
+
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirect.RawQuery = params.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	if err := s.authenticateClient(r); err != nil {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	s.mu.Lock()
+	auth, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	if !ok || auth.redirectURI != r.PostForm.Get("redirect_uri") ||
+		subtle.ConstantTimeCompare([]byte(challenge), []byte(auth.codeChallenge)) != 1 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken, err := s.SignIDToken(auth.identity, auth.clientID, auth.nonce)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (s *Server) authenticateClient(r *http.Request) error {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+
+	if clientID != s.ClientID || clientSecret != s.ClientSecret {
+		return errors.New("client authentication failed")
+	}
+	return nil
+}
+
+// SignIDToken issues an ID token for identity as this provider would.
+func (s *Server) SignIDToken(identity Identity, audience, nonce string) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            s.URL,
+		"sub":            identity.Subject,
+		"aud":            audience,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          nonce,
+		"email":          identity.Email,
+		"email_verified": identity.EmailVerified,
+	}
+	if identity.Name != "" {
+		claims["name"] = identity.Name
+	}
+	if identity.PreferredUsername != "" {
+		claims["preferred_username"] = identity.PreferredUsername
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = keyID
+	return token.SignedString(s.key)
+}
+
+func (s *Server) jwks(w http.ResponseWriter, _ *http.Request) {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	s.key.X.FillBytes(x)
+	s.key.Y.FillBytes(y)
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "EC",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "ES256",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(x),
+			"y":   base64.RawURLEncoding.EncodeToString(y),
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func randomString() string {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,122 @@
+package password
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// breachedLineChunk is how much of the file is read at a time while looking
+// for line boundaries; a line of the corpus is about 50 bytes.
+const breachedLineChunk = 128
+
+// BreachedFile looks passwords up in a local copy of a breached password
+// corpus such as Pwned Passwords, downloaded ordered by hash: one hex SHA-1
+// hash per line, optionally followed by ":" and a count. The file is
+// binary-searched by hash, so it is never loaded into memory and passwords
+// never leave the server.
+type BreachedFile struct {
+	file *os.File
+	size int64
+}
+
+func OpenBreachedFile(path string) (*BreachedFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to stat breached password file: %w", err)
+	}
+
+	return &BreachedFile{
+		file: file,
+		size: info.Size(),
+	}, nil
+}
+
+func (f *BreachedFile) Close() error {
+	return f.file.Close()
+}
+
+// Contains reports whether password appears in the corpus.
+func (f *BreachedFile) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	target := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	// lo always is the start of a line; lines before lo hash below target
+	// and lines starting at or after hi above it.
+	lo, hi := int64(0), f.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, err := f.lineStart(lo, mid)
+		if err != nil {
+			return false, err
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+
+		line, err := f.readLine(start)
+		if err != nil {
+			return false, err
+		}
+
+		hash, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+		switch strings.Compare(strings.ToUpper(hash), target) {
+		case 0:
+			return true, nil
+		case -1:
+			lo = start + int64(len(line)) + 1
+		default:
+			hi = start
+		}
+	}
+
+	return false, nil
+}
+
+// lineStart returns the start of the first line beginning at or after
+// offset, or the file size if there is none. lo is a known line start.
+func (f *BreachedFile) lineStart(lo, offset int64) (int64, error) {
+	if offset == lo {
+		return lo, nil
+	}
+
+	line, err := f.readLine(offset - 1)
+	if err != nil {
+		return 0, err
+	}
+	return offset + int64(len(line)), nil
+}
+
+// readLine returns the bytes from offset up to the next newline or the end
+// of the file.
+func (f *BreachedFile) readLine(offset int64) (string, error) {
+	var line []byte
+	buf := make([]byte, breachedLineChunk)
+	for {
+		n, err := f.file.ReadAt(buf, offset)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return string(append(line, buf[:i]...)), nil
+		}
+		line = append(line, buf[:n]...)
+		offset += int64(n)
+
+		if errors.Is(err, io.EOF) {
+			return string(line), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read breached password file: %w", err)
+		}
+	}
+}
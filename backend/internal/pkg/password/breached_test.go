@@ -0,0 +1,77 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeBreachedFile(t *testing.T, passwords ...string) string {
+	t.Helper()
+
+	lines := make([]string, 0, len(passwords)+200)
+	for _, password := range passwords {
+		sum := sha1.Sum([]byte(password))
+		lines = append(lines, fmt.Sprintf("%s:%d", strings.ToUpper(hex.EncodeToString(sum[:])), len(password)))
+	}
+	for i := 0; i < 200; i++ {
+		sum := sha1.Sum([]byte(fmt.Sprintf("filler-%d", i)))
+		lines = append(lines, fmt.Sprintf("%s:1", strings.ToUpper(hex.EncodeToString(sum[:]))))
+	}
+	sort.Strings(lines)
+
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o600))
+	return path
+}
+
+func TestBreachedFile_Contains(t *testing.T) {
+	breached := []string{"password", "Tr0ub4dor&3", "letmein"}
+	file, err := OpenBreachedFile(writeBreachedFile(t, breached...))
+	require.NoError(t, err)
+	defer file.Close()
+
+	for _, password := range breached {
+		found, err := file.Contains(password)
+		require.NoError(t, err)
+		assert.True(t, found, password)
+	}
+
+	for i := 0; i < 200; i += 37 {
+		found, err := file.Contains(fmt.Sprintf("filler-%d", i))
+		require.NoError(t, err)
+		assert.True(t, found)
+	}
+
+	for _, password := range []string{"Xk9#mQ2$vL7!", "", "Password"} {
+		found, err := file.Contains(password)
+		require.NoError(t, err)
+		assert.False(t, found, password)
+	}
+}
+
+func TestBreachedFile_Empty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	file, err := OpenBreachedFile(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	found, err := file.Contains("password")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestOpenBreachedFile_Missing(t *testing.T) {
+	_, err := OpenBreachedFile(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package password
+
+import (
+	_ "embed"
+	"strings"
+	"unicode"
+)
+
+// common.txt lists frequently used passwords, most common first.
+//
+//go:embed common.txt
+var commonList string
+
+// commonRanks maps every common password to its position in the list,
+// starting at 1.
+var commonRanks = rankedDictionary(strings.Fields(commonList))
+
+func rankedDictionary(words []string) map[string]int {
+	ranks := make(map[string]int, len(words))
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if _, exists := ranks[word]; !exists {
+			ranks[word] = i + 1
+		}
+	}
+	return ranks
+}
+
+// IsCommon reports whether password is on the bundled list of common
+// passwords, ignoring case and digits or symbols added before or after it,
+// as in "Password1!".
+func IsCommon(password string) bool {
+	lower := strings.ToLower(password)
+	if _, ok := commonRanks[lower]; ok {
+		return true
+	}
+
+	base := strings.TrimFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(base) < 4 {
+		return false
+	}
+	_, ok := commonRanks[base]
+	return ok
+}
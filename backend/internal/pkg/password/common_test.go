@@ -0,0 +1,27 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCommon(t *testing.T) {
+	testCases := []struct {
+		password string
+		common   bool
+	}{
+		{"password", true},
+		{"PASSWORD", true},
+		{"Password1!", true},
+		{"!!qwerty2024", true},
+		{"abc1!", false},
+		{"Xk9#mQ2$vL7!", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.password, func(t *testing.T) {
+			assert.Equal(t, tc.common, IsCommon(tc.password))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Package password hashes passwords with Argon2id and encodes the hashes in
+// PHC string format:
+//
+//	$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+//
+// Hashes made with bcrypt are still verified so that existing accounts keep
+// working; NeedsRehash reports them for upgrading.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// Argon2idParams are the cost parameters of new hashes. Memory is in KiB.
+type Argon2idParams struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2idParams follow the recommendations of RFC 9106 for
+// memory-constrained environments.
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+type Hasher struct {
+	params Argon2idParams
+}
+
+func NewHasher(params Argon2idParams) *Hasher {
+	return &Hasher{
+		params: params,
+	}
+}
+
+// Hash returns the Argon2id hash of password in PHC string format.
+func (h *Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism,
+		h.params.KeyLength)
+
+	return encodeArgon2id(h.params, salt, key), nil
+}
+
+// Verify reports whether password matches hash. An empty hash, as stored
+// for accounts without a password, matches nothing.
+func (h *Hasher) Verify(password, hash string) (bool, error) {
+	switch {
+	case hash == "":
+		return false, nil
+	case strings.HasPrefix(hash, "$argon2id$"):
+		params, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return false, err
+		}
+		computed := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism,
+			params.KeyLength)
+		return subtle.ConstantTimeCompare(key, computed) == 1, nil
+	case isBcrypt(hash):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	return false, ErrUnknownHashFormat
+}
+
+// NeedsRehash reports whether hash was made with another algorithm or other
+// parameters than new hashes are. It should be replaced by a new hash the
+// next time the password is known.
+func (h *Hasher) NeedsRehash(hash string) bool {
+	if hash == "" {
+		return false
+	}
+
+	params, _, _, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return params != h.params
+}
+
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func encodeArgon2id(params Argon2idParams, salt, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		params.Memory,
+		params.Iterations,
+		params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func decodeArgon2id(hash string) (Argon2idParams, []byte, []byte, error) {
+	var params Argon2idParams
+
+	// The leading "$" yields an empty first part.
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2id version %q", parts[2])
+	}
+
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, fmt.Errorf("invalid argon2id parameters %q", parts[3])
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errors.New("invalid argon2id hash")
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
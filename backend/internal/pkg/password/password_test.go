@@ -0,0 +1,95 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testParams = Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestHasher_HashAndVerify(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	hash, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+
+	matches, err := hasher.Verify("correct horse", hash)
+	require.NoError(t, err)
+	assert.True(t, matches)
+
+	matches, err = hasher.Verify("wrong horse", hash)
+	require.NoError(t, err)
+	assert.False(t, matches)
+
+	other, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "hashes must be salted")
+}
+
+func TestHasher_VerifyBcrypt(t *testing.T) {
+	hasher := NewHasher(testParams)
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	matches, err := hasher.Verify("correct horse", string(hash))
+	require.NoError(t, err)
+	assert.True(t, matches)
+
+	matches, err = hasher.Verify("wrong horse", string(hash))
+	require.NoError(t, err)
+	assert.False(t, matches)
+}
+
+func TestHasher_VerifyInvalidHash(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	matches, err := hasher.Verify("anything", "")
+	assert.NoError(t, err, "accounts without a password have an empty hash")
+	assert.False(t, matches)
+
+	testCases := []struct {
+		name string
+		hash string
+	}{
+		{"unknown algorithm", "$scrypt$ln=16,r=8,p=1$c2FsdA$aGFzaA"},
+		{"plain text", "correct horse"},
+		{"wrong version", "$argon2id$v=16$m=64,t=1,p=1$c29tZXNhbHQ$aGFzaA"},
+		{"missing parameters", "$argon2id$v=19$m=64$c29tZXNhbHQ$aGFzaA"},
+		{"invalid salt", "$argon2id$v=19$m=64,t=1,p=1$!!!$aGFzaA"},
+		{"missing hash", "$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := hasher.Verify("anything", tc.hash)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	current, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	assert.False(t, hasher.NeedsRehash(current))
+
+	stronger := testParams
+	stronger.Iterations = 2
+	assert.True(t, NewHasher(stronger).NeedsRehash(current), "outdated cost")
+
+	longerKey := testParams
+	longerKey.KeyLength = 64
+	assert.True(t, NewHasher(longerKey).NeedsRehash(current))
+
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	require.NoError(t, err)
+	assert.True(t, hasher.NeedsRehash(string(bcryptHash)), "outdated algorithm")
+
+	assert.False(t, hasher.NeedsRehash(""))
+}
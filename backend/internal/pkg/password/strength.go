@@ -0,0 +1,330 @@
+package password
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Strength scores range from 0, guessable within a few hundred attempts, to
+// 4, which takes more than 10^10 guesses.
+const (
+	StrengthVeryWeak = iota
+	StrengthWeak
+	StrengthFair
+	StrengthStrong
+	StrengthVeryStrong
+)
+
+// maxEstimatedLength bounds the work spent on estimating. Passwords longer
+// than this count as very strong.
+const maxEstimatedLength = 128
+
+var keyboardRows = []string{
+	"1234567890",
+	"qwertyuiop",
+	"asdfghjkl",
+	"zxcvbnm",
+}
+
+var leetSubstitutions = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i', '!': 'i',
+	'|': 'i', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z',
+}
+
+// Strength estimates how hard password is to guess, the way zxcvbn does: it
+// finds the guessable patterns in the password (common passwords, words from
+// userInputs such as the email address and username, sequences, repeats,
+// keyboard rows and years), charges everything else as brute force, and
+// scores the cheapest way of putting the password together from them.
+func Strength(password string, userInputs ...string) int {
+	guesses := estimateGuesses([]rune(password), userDictionary(userInputs))
+
+	switch {
+	case guesses < 1e3+5:
+		return StrengthVeryWeak
+	case guesses < 1e6+5:
+		return StrengthWeak
+	case guesses < 1e8+5:
+		return StrengthFair
+	case guesses < 1e10+5:
+		return StrengthStrong
+	}
+	return StrengthVeryStrong
+}
+
+// userDictionary ranks the user's own data above every common password.
+// Email addresses contribute their local part as well.
+func userDictionary(userInputs []string) map[string]int {
+	var words []string
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		if input == "" {
+			continue
+		}
+		words = append(words, input)
+		if local, _, ok := strings.Cut(input, "@"); ok && local != "" {
+			words = append(words, local)
+		}
+	}
+	return rankedDictionary(words)
+}
+
+// match is a pattern covering password[i:j+1] and the number of guesses it
+// takes to find it.
+type match struct {
+	i, j    int
+	guesses float64
+}
+
+func estimateGuesses(password []rune, userWords map[string]int) float64 {
+	n := len(password)
+	if n == 0 {
+		return 1
+	}
+	if n > maxEstimatedLength {
+		return math.Inf(1)
+	}
+
+	byEnd := make([][]match, n)
+	for _, m := range findMatches(password, userWords) {
+		byEnd[m.j] = append(byEnd[m.j], m)
+	}
+	for j := 0; j < n; j++ {
+		for i := 0; i <= j; i++ {
+			byEnd[j] = append(byEnd[j], match{i: i, j: j, guesses: math.Pow(10, float64(j-i+1))})
+		}
+	}
+
+	// best[k][l] is the lowest product of guesses of l matches that cover
+	// the first k runes.
+	best := make([][]float64, n+1)
+	for k := range best {
+		best[k] = make([]float64, n+1)
+		for l := range best[k] {
+			best[k][l] = math.Inf(1)
+		}
+	}
+	best[0][0] = 1
+
+	for k := 1; k <= n; k++ {
+		for _, m := range byEnd[k-1] {
+			guesses := m.guesses
+			if m.j == m.i {
+				guesses = math.Max(guesses, 10)
+			} else {
+				guesses = math.Max(guesses, 50)
+			}
+			for l := 0; l < k; l++ {
+				if product := best[m.i][l] * guesses; product < best[k][l+1] {
+					best[k][l+1] = product
+				}
+			}
+		}
+	}
+
+	// More patterns take more attempts to combine in the right order.
+	guesses := math.Inf(1)
+	factorial := 1.0
+	for l := 1; l <= n; l++ {
+		factorial *= float64(l)
+		if math.IsInf(best[n][l], 1) {
+			continue
+		}
+		guesses = math.Min(guesses, factorial*best[n][l]+math.Pow(1e4, float64(l-1)))
+	}
+	return guesses
+}
+
+func findMatches(password []rune, userWords map[string]int) []match {
+	var matches []match
+	matches = append(matches, dictionaryMatches(password, userWords)...)
+	matches = append(matches, sequenceMatches(password)...)
+	matches = append(matches, repeatMatches(password)...)
+	matches = append(matches, keyboardMatches(password)...)
+	matches = append(matches, yearMatches(password)...)
+	return matches
+}
+
+func dictionaryMatches(password []rune, userWords map[string]int) []match {
+	lower := []rune(strings.ToLower(string(password)))
+	unleet := make([]rune, len(lower))
+	for i, r := range lower {
+		if substitute, ok := leetSubstitutions[r]; ok {
+			unleet[i] = substitute
+		} else {
+			unleet[i] = r
+		}
+	}
+
+	var matches []match
+	for i := range lower {
+		for j := i + 2; j < len(lower); j++ {
+			variations := uppercaseVariations(password[i : j+1])
+
+			word := string(lower[i : j+1])
+			if rank, ok := dictionaryRank(word, userWords); ok {
+				matches = append(matches, match{i: i, j: j, guesses: float64(rank) * variations})
+				continue
+			}
+			if leet := string(unleet[i : j+1]); leet != word {
+				if rank, ok := dictionaryRank(leet, userWords); ok {
+					matches = append(matches, match{i: i, j: j, guesses: float64(rank) * variations * 2})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+func dictionaryRank(word string, userWords map[string]int) (int, bool) {
+	if rank, ok := userWords[word]; ok {
+		return rank, true
+	}
+	rank, ok := commonRanks[word]
+	return rank, ok
+}
+
+// uppercaseVariations counts the capitalizations of a word that have to be
+// tried. Capitalizing the first or last letter or all of them is common and
+// cheap.
+func uppercaseVariations(word []rune) float64 {
+	upper, lower := 0, 0
+	for _, r := range word {
+		if unicode.IsUpper(r) {
+			upper++
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return 1
+	case lower == 0, upper == 1 && (unicode.IsUpper(word[0]) || unicode.IsUpper(word[len(word)-1])):
+		return 2
+	}
+
+	variations := 0.0
+	for k := 1; k <= min(upper, lower); k++ {
+		variations += binomial(upper+lower, k)
+	}
+	return variations
+}
+
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
+
+// sequenceMatches finds runs like "abc", "123" or "987".
+func sequenceMatches(password []rune) []match {
+	var matches []match
+	for start := 0; start < len(password)-2; {
+		delta := password[start+1] - password[start]
+		end := start + 1
+		for (delta == 1 || delta == -1) && end+1 < len(password) && password[end+1]-password[end] == delta {
+			end++
+		}
+		if (delta != 1 && delta != -1) || end-start < 2 {
+			start++
+			continue
+		}
+
+		for i := start; i <= end-2; i++ {
+			for j := i + 2; j <= end; j++ {
+				base := 26.0
+				switch {
+				case strings.ContainsRune("aAzZ019", password[i]):
+					base = 4
+				case unicode.IsDigit(password[i]):
+					base = 10
+				}
+				if delta < 0 {
+					base *= 2
+				}
+				matches = append(matches, match{i: i, j: j, guesses: base * float64(j-i+1)})
+			}
+		}
+		start = end
+	}
+	return matches
+}
+
+// repeatMatches finds runs of one character like "aaa".
+func repeatMatches(password []rune) []match {
+	var matches []match
+	for start := 0; start < len(password); {
+		end := start
+		for end+1 < len(password) && password[end+1] == password[start] {
+			end++
+		}
+
+		cardinality := 33.0
+		switch {
+		case unicode.IsDigit(password[start]):
+			cardinality = 10
+		case unicode.IsLetter(password[start]):
+			cardinality = 26
+		}
+		for i := start; i <= end-2; i++ {
+			for j := i + 2; j <= end; j++ {
+				matches = append(matches, match{i: i, j: j, guesses: cardinality * float64(j-i+1)})
+			}
+		}
+		start = end + 1
+	}
+	return matches
+}
+
+// keyboardMatches finds runs of adjacent keys like "qwer" or "lkjh".
+func keyboardMatches(password []rune) []match {
+	lower := strings.ToLower(string(password))
+	runes := []rune(lower)
+
+	var matches []match
+	for i := range runes {
+		for j := i + 3; j < len(runes); j++ {
+			run := string(runes[i : j+1])
+			if !onKeyboardRow(run) {
+				break
+			}
+			matches = append(matches, match{i: i, j: j, guesses: 40 * float64(j-i+1)})
+		}
+	}
+	return matches
+}
+
+func onKeyboardRow(run string) bool {
+	reversed := []rune(run)
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+	for _, row := range keyboardRows {
+		if strings.Contains(row, run) || strings.Contains(row, string(reversed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// yearMatches finds recent years, which are guessed close to the current one.
+func yearMatches(password []rune) []match {
+	currentYear := time.Now().Year()
+
+	var matches []match
+	for i := 0; i+4 <= len(password); i++ {
+		year, err := strconv.Atoi(string(password[i : i+4]))
+		if err != nil || year < 1900 || year > 2099 {
+			continue
+		}
+		distance := math.Abs(float64(year - currentYear))
+		matches = append(matches, match{i: i, j: i + 3, guesses: math.Max(distance, 20)})
+	}
+	return matches
+}
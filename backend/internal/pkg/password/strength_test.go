@@ -0,0 +1,42 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrength(t *testing.T) {
+	testCases := []struct {
+		password string
+		min      int
+		max      int
+	}{
+		{"password", StrengthVeryWeak, StrengthVeryWeak},
+		{"Password1!", StrengthVeryWeak, StrengthVeryWeak},
+		{"qwertyuiop", StrengthVeryWeak, StrengthVeryWeak},
+		{"abcdefgh123", StrengthVeryWeak, StrengthWeak},
+		{"aaaaaaaaaaaa", StrengthVeryWeak, StrengthWeak},
+		{"Xk9#mQ2$vL7!", StrengthVeryStrong, StrengthVeryStrong},
+		{"correct horse battery staple", StrengthStrong, StrengthVeryStrong},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.password, func(t *testing.T) {
+			score := Strength(tc.password)
+			assert.GreaterOrEqual(t, score, tc.min)
+			assert.LessOrEqual(t, score, tc.max)
+		})
+	}
+}
+
+func TestStrength_UserInputs(t *testing.T) {
+	password := "Jzwierzchowski!9"
+
+	assert.Greater(t, Strength(password), Strength(password, "j.zwierzchowski@example.com", "jzwierzchowski"))
+}
+
+func TestStrength_LongPassword(t *testing.T) {
+	assert.Equal(t, StrengthVeryStrong, Strength(strings.Repeat("Xk9#", maxEstimatedLength)))
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const maxMemoryBuckets = 100000
+
+// MemoryStore keeps buckets in process. Limits are enforced per API
+// instance; use a shared store when running several.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+type memoryBucket struct {
+	Bucket
+	expiresAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*memoryBucket),
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		if len(s.buckets) >= maxMemoryBuckets {
+			s.pruneLocked(now)
+		}
+		bucket = &memoryBucket{Bucket: NewBucket(limit, now)}
+		s.buckets[key] = bucket
+	}
+
+	result := bucket.Take(limit, now)
+	bucket.expiresAt = bucket.ExpiresAt(limit)
+	return result, nil
+}
+
+// pruneLocked drops buckets that have refilled completely.
+func (s *MemoryStore) pruneLocked(now time.Time) {
+	for key, bucket := range s.buckets {
+		if !now.Before(bucket.expiresAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package ratelimit implements token bucket rate limiting. A bucket holds up
+// to Limit.Burst tokens and refills at Limit.Requests tokens per
+// Limit.Period; every request takes one token and is refused while the
+// bucket is empty.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	// Burst is the bucket capacity. Zero means Requests.
+	Burst int
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// rate returns the refill rate in tokens per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Capacity returns the number of requests allowed in a burst.
+func (l Limit) Capacity() int {
+	return int(l.capacity())
+}
+
+// Result describes the bucket after a request has been counted.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long a refused request has to wait for a token.
+	RetryAfter time.Duration
+	// ResetAfter is how long it takes until the bucket is full again.
+	ResetAfter time.Duration
+}
+
+// Bucket is the stored state of one key.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// NewBucket returns a full bucket.
+func NewBucket(limit Limit, now time.Time) Bucket {
+	return Bucket{Tokens: limit.capacity(), UpdatedAt: now}
+}
+
+// Take refills the bucket for the time elapsed since it was last updated and
+// takes one token from it if there is one.
+func (b *Bucket) Take(limit Limit, now time.Time) Result {
+	capacity := limit.capacity()
+	rate := limit.rate()
+
+	if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+elapsed.Seconds()*rate)
+		b.UpdatedAt = now
+	}
+
+	result := Result{Limit: int(capacity)}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.Tokens) / rate)
+	}
+
+	result.Remaining = int(b.Tokens)
+	result.ResetAfter = secondsToDuration((capacity - b.Tokens) / rate)
+	return result
+}
+
+// ExpiresAt returns when the bucket will be full again; from then on it is
+// indistinguishable from a new bucket and can be dropped.
+func (b *Bucket) ExpiresAt(limit Limit) time.Time {
+	return b.UpdatedAt.Add(secondsToDuration((limit.capacity() - b.Tokens) / limit.rate()))
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
+
+// Store keeps buckets by key. Implementations must make Take atomic per key.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucket_Take(t *testing.T) {
+	limit := Limit{Requests: 1, Period: time.Second, Burst: 3}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := NewBucket(limit, now)
+
+	for remaining := 2; remaining >= 0; remaining-- {
+		result := bucket.Take(limit, now)
+		require.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, remaining, result.Remaining)
+	}
+
+	result := bucket.Take(limit, now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.ResetAfter)
+
+	// Half a second refills half a token, which is not enough.
+	result = bucket.Take(limit, now.Add(500*time.Millisecond))
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	result = bucket.Take(limit, now.Add(time.Second))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// The bucket never holds more than its capacity.
+	result = bucket.Take(limit, now.Add(time.Hour))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+	assert.Equal(t, now.Add(time.Hour+time.Second), bucket.ExpiresAt(limit))
+}
+
+func TestLimit_DefaultBurst(t *testing.T) {
+	limit := Limit{Requests: 10, Period: time.Minute}
+
+	assert.Equal(t, 10, limit.Capacity())
+
+	bucket := NewBucket(limit, time.Now())
+	assert.Equal(t, 10.0, bucket.Tokens)
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 2, Period: time.Minute}
+	now := time.Now().UTC()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := store.Take(ctx, "a", limit, now)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+
+	result, err := store.Take(ctx, "a", limit, now)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 30*time.Second, result.RetryAfter)
+
+	result, err = store.Take(ctx, "b", limit, now)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "keys have separate buckets")
+}
+
+func TestMemoryStore_Prune(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 1, Period: time.Second}
+	now := time.Now().UTC()
+
+	_, _ = store.Take(context.Background(), "full", limit, now.Add(-time.Minute))
+	_, _ = store.Take(context.Background(), "empty", limit, now)
+
+	store.pruneLocked(now)
+
+	assert.NotContains(t, store.buckets, "full")
+	assert.Contains(t, store.buckets, "empty")
+}
@@ -0,0 +1,91 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 seed from RFC 6238 appendix B.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, v := range vectors {
+		code, err := Code(rfcSecret, Step(time.Unix(v.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, v.code, code, "time %d", v.unix)
+	}
+}
+
+func TestCode_InvalidSecret(t *testing.T) {
+	_, err := Code("not base32!", 1)
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	current := Step(now)
+
+	code, err := Code(secret, current)
+	require.NoError(t, err)
+	step, ok := Validate(secret, code, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, current, step)
+
+	previous, err := Code(secret, current-1)
+	require.NoError(t, err)
+	step, ok = Validate(secret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, current-1, step)
+
+	stale, err := Code(secret, current-2)
+	require.NoError(t, err)
+	_, ok = Validate(secret, stale, now, 1)
+	assert.False(t, ok)
+
+	_, ok = Validate(secret, "12345", now, 1)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	require.NoError(t, err)
+	second, err := GenerateSecret()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Roadmap", "user@example.com", "JBSWY3DPEHPK3PXP")
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Roadmap:user@example.com", parsed.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+	assert.Equal(t, "Roadmap", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+	assert.Equal(t, "30", parsed.Query().Get("period"))
+}
@@ -0,0 +1,163 @@
+package webauthn
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// maxCBORDepth bounds nesting so hostile input cannot exhaust the stack.
+const maxCBORDepth = 16
+
+var errCBORTruncated = errors.New("cbor: unexpected end of data")
+
+// decodeCBOR decodes the first CBOR item in data and returns it together with
+// the bytes that follow it. Only the subset of CBOR used by WebAuthn is
+// supported: integers, byte and text strings, arrays, maps, tags, booleans and
+// null, all with definite lengths. Integers decode to int64, maps to
+// map[any]any keyed by int64 or string.
+func decodeCBOR(data []byte) (any, []byte, error) {
+	d := &cborDecoder{data: data}
+	value, err := d.value(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return value, data[d.pos:], nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) value(depth int) (any, error) {
+	if depth > maxCBORDepth {
+		return nil, errors.New("cbor: nesting too deep")
+	}
+
+	head, err := d.take(1)
+	if err != nil {
+		return nil, err
+	}
+	major := head[0] >> 5
+	info := head[0] & 0x1f
+
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("cbor: unsupported simple value %d", info)
+		}
+	}
+
+	arg, err := d.argument(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case 0:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor: integer overflow")
+		}
+		return int64(arg), nil
+	case 1:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor: integer overflow")
+		}
+		return -1 - int64(arg), nil
+	case 2:
+		raw, err := d.take(arg)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), raw...), nil
+	case 3:
+		raw, err := d.take(arg)
+		if err != nil {
+			return nil, err
+		}
+		return string(raw), nil
+	case 4:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, errCBORTruncated
+		}
+		items := make([]any, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case 5:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, errCBORTruncated
+		}
+		entries := make(map[any]any, arg)
+		for i := uint64(0); i < arg; i++ {
+			key, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, errors.New("cbor: unsupported map key type")
+			}
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			entries[key] = item
+		}
+		return entries, nil
+	default:
+		// Tags carry no meaning for WebAuthn structures; decode the content.
+		return d.value(depth + 1)
+	}
+}
+
+func (d *cborDecoder) argument(info byte) (uint64, error) {
+	var size int
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, errors.New("cbor: indefinite lengths are not supported")
+	}
+
+	raw, err := d.take(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+
+	var arg uint64
+	for _, b := range raw {
+		arg = arg<<8 | uint64(b)
+	}
+	return arg, nil
+}
+
+func (d *cborDecoder) take(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errCBORTruncated
+	}
+	start := d.pos
+	d.pos += int(n)
+	return d.data[start:d.pos], nil
+}
@@ -0,0 +1,121 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE algorithm identifiers accepted for credentials.
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// SupportedAlgorithms lists the algorithms offered to authenticators, in
+// order of preference.
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+const (
+	coseKeyType = 1
+	coseAlg     = 3
+	coseCrv     = -1
+	coseX       = -2
+	coseY       = -3
+
+	coseKtyOKP = 1
+	coseKtyEC2 = 2
+	coseKtyRSA = 3
+
+	coseCrvP256    = 1
+	coseCrvEd25519 = 6
+)
+
+type coseKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+func parseCOSEKey(data []byte) (*coseKey, error) {
+	decoded, rest, err := decodeCBOR(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential public key: %w", err)
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("invalid credential public key: trailing data")
+	}
+
+	fields, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, errors.New("invalid credential public key: not a map")
+	}
+
+	kty, _ := fields[int64(coseKeyType)].(int64)
+	alg, _ := fields[int64(coseAlg)].(int64)
+
+	switch {
+	case kty == coseKtyEC2 && alg == AlgES256:
+		crv, _ := fields[int64(coseCrv)].(int64)
+		x, _ := fields[int64(coseX)].([]byte)
+		y, _ := fields[int64(coseY)].([]byte)
+		if crv != coseCrvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid EC2 public key")
+		}
+		point := append(append([]byte{0x04}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, errors.New("invalid EC2 public key: point not on curve")
+		}
+		return &coseKey{alg: alg, key: &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}}, nil
+
+	case kty == coseKtyOKP && alg == AlgEdDSA:
+		crv, _ := fields[int64(coseCrv)].(int64)
+		x, _ := fields[int64(coseX)].([]byte)
+		if crv != coseCrvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid OKP public key")
+		}
+		return &coseKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+
+	case kty == coseKtyRSA && alg == AlgRS256:
+		n, _ := fields[int64(-1)].([]byte)
+		e, _ := fields[int64(-2)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RSA public key")
+		}
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		return &coseKey{alg: alg, key: &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: exponent,
+		}}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported credential key type %d with algorithm %d", kty, alg)
+}
+
+func (k *coseKey) verify(data, signature []byte) bool {
+	switch pub := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(pub, digest[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(pub, data, signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
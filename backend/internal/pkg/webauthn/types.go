@@ -0,0 +1,122 @@
+package webauthn
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// Base64URL is a byte slice that travels as unpadded base64url in JSON, the
+// encoding used by PublicKeyCredential.toJSON() in browsers.
+type Base64URL []byte
+
+func (b Base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+func (b *Base64URL) UnmarshalJSON(data []byte) error {
+	var encoded string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return err
+	}
+
+	*b = decoded
+	return nil
+}
+
+type RelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserEntity struct {
+	ID          Base64URL `json:"id"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+}
+
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type CredentialDescriptor struct {
+	Type       string    `json:"type"`
+	ID         Base64URL `json:"id"`
+	Transports []string  `json:"transports,omitempty"`
+}
+
+func NewCredentialDescriptor(id []byte, transports []string) CredentialDescriptor {
+	return CredentialDescriptor{Type: credentialType, ID: id, Transports: transports}
+}
+
+type AuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+type PublicKeyCredentialCreationOptions struct {
+	RP                     RelyingParty           `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	Challenge              string                 `json:"challenge"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// CreationOptions is the argument for navigator.credentials.create().
+type CreationOptions struct {
+	PublicKey PublicKeyCredentialCreationOptions `json:"publicKey"`
+}
+
+type PublicKeyCredentialRequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int64                  `json:"timeout"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// RequestOptions is the argument for navigator.credentials.get().
+type RequestOptions struct {
+	PublicKey PublicKeyCredentialRequestOptions `json:"publicKey"`
+}
+
+type AttestationResponse struct {
+	ClientDataJSON    Base64URL `json:"clientDataJSON" binding:"required"`
+	AttestationObject Base64URL `json:"attestationObject" binding:"required"`
+	Transports        []string  `json:"transports"`
+}
+
+// RegistrationResponse is the JSON form of the PublicKeyCredential returned
+// by navigator.credentials.create().
+type RegistrationResponse struct {
+	ID       string              `json:"id"`
+	RawID    Base64URL           `json:"rawId"`
+	Type     string              `json:"type" binding:"required"`
+	Response AttestationResponse `json:"response" binding:"required"`
+}
+
+type AuthenticatorAssertionResponse struct {
+	ClientDataJSON    Base64URL `json:"clientDataJSON" binding:"required"`
+	AuthenticatorData Base64URL `json:"authenticatorData" binding:"required"`
+	Signature         Base64URL `json:"signature" binding:"required"`
+	UserHandle        Base64URL `json:"userHandle"`
+}
+
+// AssertionResponse is the JSON form of the PublicKeyCredential returned by
+// navigator.credentials.get().
+type AssertionResponse struct {
+	ID       string                         `json:"id"`
+	RawID    Base64URL                      `json:"rawId" binding:"required"`
+	Type     string                         `json:"type" binding:"required"`
+	Response AuthenticatorAssertionResponse `json:"response" binding:"required"`
+}
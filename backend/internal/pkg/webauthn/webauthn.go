@@ -0,0 +1,335 @@
+// Package webauthn implements the relying party side of WebAuthn
+// registration and authentication ceremonies for passkeys.
+//
+// Attestation statements are not verified: credentials are requested with
+// attestation "none" and trusted on first use, which is what passkey
+// providers expect from consumer relying parties.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+
+	credentialType = "public-key"
+	challengeSize  = 32
+
+	flagUserPresent   = 0x01
+	flagUserVerified  = 0x04
+	flagBackupElig    = 0x08
+	flagAttestedCreds = 0x40
+
+	minAuthDataLength = 37
+)
+
+var (
+	ErrVerificationFailed  = errors.New("webauthn verification failed")
+	ErrSignCountRegression = errors.New("authenticator sign count did not increase")
+)
+
+type Config struct {
+	// RPID is the relying party identifier, i.e. the registrable domain the
+	// credentials are scoped to.
+	RPID   string
+	RPName string
+
+	// Origins lists the exact origins (scheme://host[:port]) allowed to run
+	// ceremonies.
+	Origins []string
+
+	Timeout time.Duration
+}
+
+type WebAuthn struct {
+	config   Config
+	rpIDHash [32]byte
+}
+
+func New(config Config) (*WebAuthn, error) {
+	if config.RPID == "" {
+		return nil, errors.New("webauthn: relying party ID is required")
+	}
+	if len(config.Origins) == 0 {
+		return nil, errors.New("webauthn: at least one origin is required")
+	}
+	if config.RPName == "" {
+		config.RPName = config.RPID
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Minute
+	}
+
+	return &WebAuthn{
+		config:   config,
+		rpIDHash: sha256.Sum256([]byte(config.RPID)),
+	}, nil
+}
+
+func (w *WebAuthn) Timeout() time.Duration {
+	return w.config.Timeout
+}
+
+// NewChallenge returns a random challenge encoded as unpadded base64url, the
+// form in which it comes back inside clientDataJSON.
+func NewChallenge() (string, error) {
+	buf := make([]byte, challengeSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webauthn challenge: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+type User struct {
+	ID          []byte
+	Name        string
+	DisplayName string
+}
+
+// Credential is a verified, newly registered public key credential.
+type Credential struct {
+	ID             []byte
+	PublicKey      []byte
+	SignCount      uint32
+	AAGUID         []byte
+	Transports     []string
+	BackupEligible bool
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// ChallengeFromClientData extracts the challenge echoed in clientDataJSON so
+// the server can look up the ceremony it belongs to. The value is not
+// trusted until the response has been verified.
+func ChallengeFromClientData(clientDataJSON []byte) (string, error) {
+	var data clientData
+	if err := json.Unmarshal(clientDataJSON, &data); err != nil || data.Challenge == "" {
+		return "", fmt.Errorf("%w: malformed client data", ErrVerificationFailed)
+	}
+	return data.Challenge, nil
+}
+
+func (w *WebAuthn) CreationOptions(user User, challenge string, exclude []CredentialDescriptor) CreationOptions {
+	params := make([]CredentialParameter, 0, len(SupportedAlgorithms))
+	for _, alg := range SupportedAlgorithms {
+		params = append(params, CredentialParameter{Type: credentialType, Alg: alg})
+	}
+
+	return CreationOptions{PublicKey: PublicKeyCredentialCreationOptions{
+		RP: RelyingParty{ID: w.config.RPID, Name: w.config.RPName},
+		User: UserEntity{
+			ID:          user.ID,
+			Name:        user.Name,
+			DisplayName: user.DisplayName,
+		},
+		Challenge:          challenge,
+		PubKeyCredParams:   params,
+		Timeout:            w.config.Timeout.Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   "required",
+		},
+		Attestation: "none",
+	}}
+}
+
+func (w *WebAuthn) RequestOptions(challenge string, allow []CredentialDescriptor) RequestOptions {
+	return RequestOptions{PublicKey: PublicKeyCredentialRequestOptions{
+		Challenge:        challenge,
+		Timeout:          w.config.Timeout.Milliseconds(),
+		RPID:             w.config.RPID,
+		AllowCredentials: allow,
+		UserVerification: "required",
+	}}
+}
+
+// VerifyRegistration checks an attestation response against the challenge
+// issued for it and returns the credential to store.
+func (w *WebAuthn) VerifyRegistration(response RegistrationResponse, challenge string) (*Credential, error) {
+	if response.Type != credentialType {
+		return nil, fmt.Errorf("%w: unexpected credential type", ErrVerificationFailed)
+	}
+
+	if err := w.verifyClientData(response.Response.ClientDataJSON, ceremonyCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	decoded, _, err := decodeCBOR(response.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed attestation object", ErrVerificationFailed)
+	}
+	attestation, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed attestation object", ErrVerificationFailed)
+	}
+	rawAuthData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing authenticator data", ErrVerificationFailed)
+	}
+
+	authData, err := w.verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.flags&flagAttestedCreds == 0 || len(authData.credentialID) == 0 {
+		return nil, fmt.Errorf("%w: no attested credential data", ErrVerificationFailed)
+	}
+	if len(response.RawID) > 0 && !bytes.Equal(response.RawID, authData.credentialID) {
+		return nil, fmt.Errorf("%w: credential ID mismatch", ErrVerificationFailed)
+	}
+
+	if _, err := parseCOSEKey(authData.credentialPublicKey); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrVerificationFailed, err)
+	}
+
+	return &Credential{
+		ID:             authData.credentialID,
+		PublicKey:      authData.credentialPublicKey,
+		SignCount:      authData.signCount,
+		AAGUID:         authData.aaguid,
+		Transports:     response.Response.Transports,
+		BackupEligible: authData.flags&flagBackupElig != 0,
+	}, nil
+}
+
+// VerifyAssertion checks an assertion made with a stored credential and
+// returns the authenticator's new sign count. A count that does not advance
+// past storedSignCount suggests a cloned authenticator and is rejected with
+// ErrSignCountRegression; authenticators that always report zero are exempt.
+func (w *WebAuthn) VerifyAssertion(
+	response AssertionResponse,
+	challenge string,
+	publicKey []byte,
+	storedSignCount uint32,
+) (uint32, error) {
+	if response.Type != credentialType {
+		return 0, fmt.Errorf("%w: unexpected credential type", ErrVerificationFailed)
+	}
+
+	if err := w.verifyClientData(response.Response.ClientDataJSON, ceremonyGet, challenge); err != nil {
+		return 0, err
+	}
+
+	authData, err := w.verifyAuthenticatorData(response.Response.AuthenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := parseCOSEKey(publicKey)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrVerificationFailed, err)
+	}
+
+	clientDataHash := sha256.Sum256(response.Response.ClientDataJSON)
+	signed := append(append([]byte(nil), response.Response.AuthenticatorData...), clientDataHash[:]...)
+	if !key.verify(signed, response.Response.Signature) {
+		return 0, fmt.Errorf("%w: invalid signature", ErrVerificationFailed)
+	}
+
+	if (authData.signCount != 0 || storedSignCount != 0) && authData.signCount <= storedSignCount {
+		return 0, ErrSignCountRegression
+	}
+
+	return authData.signCount, nil
+}
+
+func (w *WebAuthn) verifyClientData(raw []byte, ceremony, challenge string) error {
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("%w: malformed client data", ErrVerificationFailed)
+	}
+
+	if data.Type != ceremony {
+		return fmt.Errorf("%w: unexpected ceremony type %q", ErrVerificationFailed, data.Type)
+	}
+	if subtle.ConstantTimeCompare([]byte(data.Challenge), []byte(challenge)) != 1 {
+		return fmt.Errorf("%w: challenge mismatch", ErrVerificationFailed)
+	}
+	if !w.allowedOrigin(data.Origin) {
+		return fmt.Errorf("%w: origin %q is not allowed", ErrVerificationFailed, data.Origin)
+	}
+
+	return nil
+}
+
+func (w *WebAuthn) allowedOrigin(origin string) bool {
+	for _, allowed := range w.config.Origins {
+		if strings.EqualFold(strings.TrimRight(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+type authenticatorData struct {
+	flags               byte
+	signCount           uint32
+	aaguid              []byte
+	credentialID        []byte
+	credentialPublicKey []byte
+}
+
+func (w *WebAuthn) verifyAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < minAuthDataLength {
+		return nil, fmt.Errorf("%w: authenticator data too short", ErrVerificationFailed)
+	}
+
+	if subtle.ConstantTimeCompare(raw[:32], w.rpIDHash[:]) != 1 {
+		return nil, fmt.Errorf("%w: relying party ID mismatch", ErrVerificationFailed)
+	}
+
+	data := &authenticatorData{
+		flags:     raw[32],
+		signCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+
+	if data.flags&flagUserPresent == 0 {
+		return nil, fmt.Errorf("%w: user not present", ErrVerificationFailed)
+	}
+	if data.flags&flagUserVerified == 0 {
+		return nil, fmt.Errorf("%w: user not verified", ErrVerificationFailed)
+	}
+
+	if data.flags&flagAttestedCreds != 0 {
+		rest := raw[minAuthDataLength:]
+		if len(rest) < 18 {
+			return nil, fmt.Errorf("%w: attested credential data too short", ErrVerificationFailed)
+		}
+		data.aaguid = append([]byte(nil), rest[:16]...)
+		idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if len(rest) < idLength {
+			return nil, fmt.Errorf("%w: attested credential data too short", ErrVerificationFailed)
+		}
+		data.credentialID = append([]byte(nil), rest[:idLength]...)
+		rest = rest[idLength:]
+
+		// The public key is followed by optional extension data, so its
+		// length is only known after decoding it.
+		_, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed credential public key", ErrVerificationFailed)
+		}
+		data.credentialPublicKey = append([]byte(nil), rest[:len(rest)-len(after)]...)
+	}
+
+	return data, nil
+}
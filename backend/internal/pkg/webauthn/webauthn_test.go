@@ -0,0 +1,308 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://example.com"
+)
+
+// encodeCBOR is a minimal encoder for building authenticator responses in
+// tests. Map keys are written in sorted order for determinism.
+func encodeCBOR(value any) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n < 1<<8:
+			return []byte{major<<5 | 24, byte(n)}
+		case n < 1<<16:
+			return []byte{major<<5 | 25, byte(n >> 8), byte(n)}
+		default:
+			buf := make([]byte, 5)
+			buf[0] = major<<5 | 26
+			binary.BigEndian.PutUint32(buf[1:], uint32(n))
+			return buf
+		}
+	}
+
+	switch v := value.(type) {
+	case int:
+		if v >= 0 {
+			return head(0, uint64(v))
+		}
+		return head(1, uint64(-1-v))
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case map[any]any:
+		keys := make([]any, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return string(encodeCBOR(keys[i])) < string(encodeCBOR(keys[j]))
+		})
+		out := head(5, uint64(len(v)))
+		for _, k := range keys {
+			out = append(out, encodeCBOR(k)...)
+			out = append(out, encodeCBOR(v[k])...)
+		}
+		return out
+	}
+	panic("unsupported type")
+}
+
+type testAuthenticator struct {
+	credentialID []byte
+	signer       func([]byte) []byte
+	coseKey      []byte
+	signCount    uint32
+}
+
+func newES256Authenticator(t *testing.T) *testAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	key.X.FillBytes(x)
+	key.Y.FillBytes(y)
+
+	return &testAuthenticator{
+		credentialID: []byte("es256-credential"),
+		coseKey:      encodeCBOR(map[any]any{1: 2, 3: -7, -1: 1, -2: x, -3: y}),
+		signer: func(data []byte) []byte {
+			digest := sha256.Sum256(data)
+			sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+			require.NoError(t, err)
+			return sig
+		},
+	}
+}
+
+func newEd25519Authenticator(t *testing.T) *testAuthenticator {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return &testAuthenticator{
+		credentialID: []byte("ed25519-credential"),
+		coseKey:      encodeCBOR(map[any]any{1: 1, 3: -8, -1: 6, -2: []byte(pub)}),
+		signer: func(data []byte) []byte {
+			return ed25519.Sign(priv, data)
+		},
+	}
+}
+
+func (a *testAuthenticator) authData(rpID string, flags byte, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append([]byte(nil), rpIDHash[:]...)
+	if attested {
+		flags |= flagAttestedCreds
+	}
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+
+	if attested {
+		data = append(data, make([]byte, 16)...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.coseKey...)
+	}
+	return data
+}
+
+func clientDataJSON(ceremony, challenge, origin string) []byte {
+	data, _ := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    origin,
+	})
+	return data
+}
+
+func (a *testAuthenticator) register(challenge string) RegistrationResponse {
+	attestation := encodeCBOR(map[any]any{
+		"fmt":      "none",
+		"attStmt":  map[any]any{},
+		"authData": a.authData(testRPID, flagUserPresent|flagUserVerified, true),
+	})
+
+	return RegistrationResponse{
+		ID:    "ignored",
+		RawID: a.credentialID,
+		Type:  "public-key",
+		Response: AttestationResponse{
+			ClientDataJSON:    clientDataJSON(ceremonyCreate, challenge, testOrigin),
+			AttestationObject: attestation,
+			Transports:        []string{"internal"},
+		},
+	}
+}
+
+func (a *testAuthenticator) assert(challenge, origin string) AssertionResponse {
+	a.signCount++
+	authData := a.authData(testRPID, flagUserPresent|flagUserVerified, false)
+	clientData := clientDataJSON(ceremonyGet, challenge, origin)
+	clientDataHash := sha256.Sum256(clientData)
+
+	return AssertionResponse{
+		RawID: a.credentialID,
+		Type:  "public-key",
+		Response: AuthenticatorAssertionResponse{
+			ClientDataJSON:    clientData,
+			AuthenticatorData: authData,
+			Signature:         a.signer(append(append([]byte(nil), authData...), clientDataHash[:]...)),
+		},
+	}
+}
+
+func newTestWebAuthn(t *testing.T) *WebAuthn {
+	w, err := New(Config{RPID: testRPID, RPName: "Example", Origins: []string{testOrigin}})
+	require.NoError(t, err)
+	return w
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	for name, newAuthenticator := range map[string]func(*testing.T) *testAuthenticator{
+		"ES256":   newES256Authenticator,
+		"Ed25519": newEd25519Authenticator,
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := newTestWebAuthn(t)
+			authenticator := newAuthenticator(t)
+
+			challenge, err := NewChallenge()
+			require.NoError(t, err)
+
+			credential, err := w.VerifyRegistration(authenticator.register(challenge), challenge)
+			require.NoError(t, err)
+			assert.Equal(t, authenticator.credentialID, credential.ID)
+			assert.Equal(t, authenticator.coseKey, credential.PublicKey)
+			assert.Equal(t, []string{"internal"}, credential.Transports)
+
+			loginChallenge, err := NewChallenge()
+			require.NoError(t, err)
+
+			signCount, err := w.VerifyAssertion(
+				authenticator.assert(loginChallenge, testOrigin), loginChallenge, credential.PublicKey, 0,
+			)
+			require.NoError(t, err)
+			assert.Equal(t, uint32(1), signCount)
+		})
+	}
+}
+
+func TestVerifyRegistration_Rejects(t *testing.T) {
+	w := newTestWebAuthn(t)
+	authenticator := newES256Authenticator(t)
+
+	_, err := w.VerifyRegistration(authenticator.register("challenge-a"), "challenge-b")
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+
+	response := authenticator.register("challenge")
+	response.Response.ClientDataJSON = clientDataJSON(ceremonyCreate, "challenge", "https://evil.example")
+	_, err = w.VerifyRegistration(response, "challenge")
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+
+	response = authenticator.register("challenge")
+	response.Response.ClientDataJSON = clientDataJSON(ceremonyGet, "challenge", testOrigin)
+	_, err = w.VerifyRegistration(response, "challenge")
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+
+	response = authenticator.register("challenge")
+	response.Response.AttestationObject = encodeCBOR(map[any]any{
+		"fmt":      "none",
+		"attStmt":  map[any]any{},
+		"authData": authenticator.authData("other.com", flagUserPresent|flagUserVerified, true),
+	})
+	_, err = w.VerifyRegistration(response, "challenge")
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+
+	response = authenticator.register("challenge")
+	response.Response.AttestationObject = encodeCBOR(map[any]any{
+		"fmt":      "none",
+		"attStmt":  map[any]any{},
+		"authData": authenticator.authData(testRPID, flagUserPresent, true),
+	})
+	_, err = w.VerifyRegistration(response, "challenge")
+	assert.ErrorIs(t, err, ErrVerificationFailed, "user verification is required")
+}
+
+func TestVerifyAssertion_Rejects(t *testing.T) {
+	w := newTestWebAuthn(t)
+	authenticator := newES256Authenticator(t)
+	credential, err := w.VerifyRegistration(authenticator.register("register"), "register")
+	require.NoError(t, err)
+
+	_, err = w.VerifyAssertion(authenticator.assert("login", "https://evil.example"), "login", credential.PublicKey, 0)
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+
+	response := authenticator.assert("login", testOrigin)
+	response.Response.Signature[len(response.Response.Signature)-1] ^= 0xff
+	_, err = w.VerifyAssertion(response, "login", credential.PublicKey, 0)
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+
+	other := newES256Authenticator(t)
+	_, err = w.VerifyAssertion(other.assert("login", testOrigin), "login", credential.PublicKey, 0)
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+}
+
+func TestVerifyAssertion_SignCountRegression(t *testing.T) {
+	w := newTestWebAuthn(t)
+	authenticator := newES256Authenticator(t)
+	credential, err := w.VerifyRegistration(authenticator.register("register"), "register")
+	require.NoError(t, err)
+
+	authenticator.signCount = 9
+	_, err = w.VerifyAssertion(authenticator.assert("login", testOrigin), "login", credential.PublicKey, 10)
+	assert.ErrorIs(t, err, ErrSignCountRegression)
+
+	signCount, err := w.VerifyAssertion(authenticator.assert("login", testOrigin), "login", credential.PublicKey, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(11), signCount)
+}
+
+func TestChallengeFromClientData(t *testing.T) {
+	challenge, err := ChallengeFromClientData(clientDataJSON(ceremonyGet, "abc", testOrigin))
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", challenge)
+
+	_, err = ChallengeFromClientData([]byte("not json"))
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+}
+
+func TestDecodeCBOR_RejectsTruncatedInput(t *testing.T) {
+	encoded := encodeCBOR(map[any]any{"key": []byte("value")})
+
+	_, _, err := decodeCBOR(encoded[:len(encoded)-2])
+	assert.Error(t, err)
+
+	// A huge declared length must not trigger a huge allocation.
+	_, _, err = decodeCBOR([]byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	assert.Error(t, err)
+}
+
+func TestBase64URL_JSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(Base64URL{0xfb, 0xff})
+	require.NoError(t, err)
+	assert.Equal(t, `"-_8"`, string(data))
+
+	var decoded Base64URL
+	require.NoError(t, json.Unmarshal([]byte(`"-_8="`), &decoded))
+	assert.Equal(t, Base64URL{0xfb, 0xff}, decoded)
+}
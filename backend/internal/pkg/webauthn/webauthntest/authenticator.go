@@ -0,0 +1,137 @@
+// Package webauthntest provides a software authenticator that produces
+// WebAuthn responses for tests.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+
+	"roadmap/internal/pkg/webauthn"
+)
+
+// Authenticator holds a single ES256 discoverable credential. It always
+// reports user presence and user verification.
+type Authenticator struct {
+	RPID         string
+	Origin       string
+	CredentialID []byte
+	UserHandle   []byte
+	SignCount    uint32
+
+	key *ecdsa.PrivateKey
+}
+
+func NewAuthenticator(rpID, origin string) (*Authenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		return nil, err
+	}
+
+	return &Authenticator{
+		RPID:         rpID,
+		Origin:       origin,
+		CredentialID: credentialID,
+		key:          key,
+	}, nil
+}
+
+// PublicKey returns the credential public key in COSE form, as stored by the
+// relying party.
+func (a *Authenticator) PublicKey() []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+
+	// {1: 2, 3: -7, -1: 1, -2: x, -3: y} in canonical CBOR
+	key := []byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x58, 0x20}
+	key = append(key, x...)
+	key = append(key, 0x22, 0x58, 0x20)
+	return append(key, y...)
+}
+
+func (a *Authenticator) Register(challenge string) webauthn.RegistrationResponse {
+	authData := a.authData(true)
+
+	// {"fmt": "none", "attStmt": {}, "authData": authData}
+	attestation := []byte{0xa3}
+	attestation = append(attestation, 0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e')
+	attestation = append(attestation, 0x67, 'a', 't', 't', 'S', 't', 'm', 't', 0xa0)
+	attestation = append(attestation, 0x68, 'a', 'u', 't', 'h', 'D', 'a', 't', 'a')
+	attestation = append(attestation, 0x59, byte(len(authData)>>8), byte(len(authData)))
+	attestation = append(attestation, authData...)
+
+	return webauthn.RegistrationResponse{
+		ID:    "",
+		RawID: a.CredentialID,
+		Type:  "public-key",
+		Response: webauthn.AttestationResponse{
+			ClientDataJSON:    a.clientData("webauthn.create", challenge),
+			AttestationObject: attestation,
+			Transports:        []string{"internal"},
+		},
+	}
+}
+
+// Assert signs challenge with the credential, advancing the sign count.
+func (a *Authenticator) Assert(challenge string) webauthn.AssertionResponse {
+	a.SignCount++
+	authData := a.authData(false)
+	clientData := a.clientData("webauthn.get", challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return webauthn.AssertionResponse{
+		RawID: a.CredentialID,
+		Type:  "public-key",
+		Response: webauthn.AuthenticatorAssertionResponse{
+			ClientDataJSON:    clientData,
+			AuthenticatorData: authData,
+			Signature:         signature,
+			UserHandle:        a.UserHandle,
+		},
+	}
+}
+
+func (a *Authenticator) authData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.RPID))
+	flags := byte(0x01 | 0x04)
+	if attested {
+		flags |= 0x40
+	}
+
+	data := append([]byte(nil), rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.SignCount)
+
+	if attested {
+		data = append(data, make([]byte, 16)...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.CredentialID)))
+		data = append(data, a.CredentialID...)
+		data = append(data, a.PublicKey()...)
+	}
+	return data
+}
+
+func (a *Authenticator) clientData(ceremony, challenge string) []byte {
+	data, _ := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    a.Origin,
+	})
+	return data
+}
@@ -0,0 +1,95 @@
+// Package ratelimit stores rate limit buckets in Postgres so that limits
+// hold across API instances.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"roadmap/internal/infrastructure/database"
+	"roadmap/internal/pkg/ratelimit"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type BucketRepository interface {
+	ratelimit.Store
+
+	// DeleteExpired removes buckets that have refilled completely by now.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type bucketRepository struct {
+	db *database.Database
+}
+
+func NewBucketRepository(db *database.Database) BucketRepository {
+	return &bucketRepository{
+		db: db,
+	}
+}
+
+// Take locks the key's row for the duration of the update, so concurrent
+// requests from different instances are counted one after the other.
+func (r *bucketRepository) Take(
+	ctx context.Context,
+	key string,
+	limit ratelimit.Limit,
+	now time.Time,
+) (ratelimit.Result, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			log.Printf("Failed to rollback rate limit update: %v", rollbackErr)
+		}
+	}()
+
+	bucket := ratelimit.NewBucket(limit, now)
+	_, err = tx.Exec(ctx, `
+		INSERT INTO rate_limit_buckets (key, tokens, updated_at, expires_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (key) DO NOTHING
+	`, key, bucket.Tokens, bucket.UpdatedAt)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("failed to create rate limit bucket: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, `SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE`, key).
+		Scan(&bucket.Tokens, &bucket.UpdatedAt)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("failed to get rate limit bucket: %w", err)
+	}
+
+	result := bucket.Take(limit, now)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3, expires_at = $4
+		WHERE key = $1
+	`, key, bucket.Tokens, bucket.UpdatedAt, bucket.ExpiresAt(limit))
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("failed to update rate limit bucket: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ratelimit.Result{}, fmt.Errorf("failed to commit rate limit update: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *bucketRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := `DELETE FROM rate_limit_buckets WHERE expires_at < $1`
+
+	tag, err := r.db.Pool.Exec(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired rate limit buckets: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
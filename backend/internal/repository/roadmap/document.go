@@ -0,0 +1,114 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type documentRepository struct {
+	db *database.Database
+}
+
+func NewDocumentRepository(db *database.Database) DocumentRepository {
+	return &documentRepository{
+		db: db,
+	}
+}
+
+func (r *documentRepository) Import(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	slug string,
+	plan ImportPlanFunc,
+) (*roadmapentity.Roadmap, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			log.Printf("Failed to rollback roadmap import: %v", rollbackErr)
+		}
+	}()
+
+	// The lock conflicts with the one EditGraph takes, which serializes the
+	// import with graph edits and other imports of the same roadmap.
+	query := `SELECT ` + roadmapColumns + ` FROM roadmaps WHERE owner_id = $1 AND slug = $2 FOR NO KEY UPDATE`
+	roadmap, err := scanRoadmap(tx.QueryRow(ctx, query, ownerID, slug))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to lock roadmap: %w", err)
+	}
+
+	graph := &roadmapentity.Graph{}
+	var resources []*roadmapentity.Resource
+	if roadmap != nil {
+		if graph, err = getGraph(ctx, tx, roadmap.ID); err != nil {
+			return nil, fmt.Errorf("failed to get roadmap graph: %w", err)
+		}
+		if resources, err = listResources(ctx, tx, resourcesByRoadmapQuery, roadmap.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := plan(roadmap, graph, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case roadmap == nil:
+		roadmap, err = createRoadmap(ctx, tx, changes.Roadmap)
+	case changes.Roadmap != nil:
+		roadmap, err = updateRoadmap(ctx, tx, changes.Roadmap)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyGraphChanges(ctx, tx, roadmap.ID, &changes.Graph); err != nil {
+		return nil, err
+	}
+
+	if err := applyResourceChanges(ctx, tx, &changes.Resources); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit roadmap import: %w", err)
+	}
+
+	return roadmap, nil
+}
+
+func applyResourceChanges(ctx context.Context, q querier, changes *ResourceChanges) error {
+	for _, id := range changes.DeleteResources {
+		// Resources of deleted nodes are already gone with their node.
+		if err := deleteResource(ctx, q, id); err != nil && !errors.Is(err, ErrResourceNotFound) {
+			return err
+		}
+	}
+	for _, resource := range changes.UpdateResources {
+		if _, err := updateResource(ctx, q, resource); err != nil {
+			return err
+		}
+	}
+	for _, resource := range changes.CreateResources {
+		if _, err := createResource(ctx, q, resource); err != nil {
+			return err
+		}
+	}
+	for _, order := range changes.Reorders {
+		if err := reorderResources(ctx, q, order.NodeID, order.ResourceIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	"roadmap/internal/infrastructure/database"
+)
+
+type DocumentRepositoryIntegrationTestSuite struct {
+	suite.Suite
+	repo         *documentRepository
+	roadmapRepo  *roadmapRepository
+	graphRepo    *graphRepository
+	resourceRepo *resourceRepository
+	db           *database.Database
+	ctx          context.Context
+	ownerID      uuid.UUID
+}
+
+func (s *DocumentRepositoryIntegrationTestSuite) SetupSuite() {
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		s.T().Skip("Skipping integration tests: TEST_DB_DSN not set")
+		return
+	}
+
+	cfg := &database.Config{
+		Host:     "localhost",
+		Port:     "5432",
+		User:     "postgres",
+		Password: "postgres",
+		DBName:   "roadmap_test",
+		SSLMode:  "disable",
+	}
+
+	var err error
+	s.db, err = database.NewDatabase(cfg)
+	require.NoError(s.T(), err, "Failed to connect to test database")
+
+	s.repo = NewDocumentRepository(s.db).(*documentRepository)
+	s.roadmapRepo = NewRoadmapRepository(s.db).(*roadmapRepository)
+	s.graphRepo = NewGraphRepository(s.db).(*graphRepository)
+	s.resourceRepo = NewResourceRepository(s.db).(*resourceRepository)
+	s.ctx = context.Background()
+}
+
+func (s *DocumentRepositoryIntegrationTestSuite) TearDownSuite() {
+	if s.db != nil {
+		s.db.Close()
+	}
+}
+
+func (s *DocumentRepositoryIntegrationTestSuite) SetupTest() {
+	if s.db == nil {
+		return
+	}
+	s.cleanupTestData()
+
+	s.ownerID = createTestUser(s.T(), s.ctx, s.db, "test_document@example.com")
+}
+
+func (s *DocumentRepositoryIntegrationTestSuite) TearDownTest() {
+	s.cleanupTestData()
+}
+
+func (s *DocumentRepositoryIntegrationTestSuite) cleanupTestData() {
+	if s.db == nil {
+		return
+	}
+
+	_, err := s.db.Pool.Exec(s.ctx, "DELETE FROM users WHERE email = 'test_document@example.com'")
+	if err != nil {
+		s.T().Logf("Warning: Failed to cleanup test data: %v", err)
+	}
+}
+
+func newKeyedNode(key string) *roadmapentity.Node {
+	node := newTestNode(key)
+	node.Key = key
+	return node
+}
+
+func newTestResource(roadmapID, nodeID uuid.UUID, url string) *roadmapentity.Resource {
+	return &roadmapentity.Resource{
+		ID:            uuid.New(),
+		RoadmapID:     roadmapID,
+		NodeID:        nodeID,
+		URL:           "https://" + url,
+		NormalizedURL: url,
+		Title:         url,
+		Kind:          roadmapentity.ResourceKindArticle,
+		IsFree:        true,
+	}
+}
+
+// importNew imports a roadmap with the slug that does not exist yet, with a
+// node for each key and a resource with the URL on the first node.
+func (s *DocumentRepositoryIntegrationTestSuite) importNew(slug, url string, keys ...string) *roadmapentity.Roadmap {
+	imported, err := s.repo.Import(s.ctx, s.ownerID, slug,
+		func(roadmap *roadmapentity.Roadmap, graph *roadmapentity.Graph, resources []*roadmapentity.Resource) (*ImportChanges, error) {
+			require.Nil(s.T(), roadmap)
+			assert.Empty(s.T(), graph.Nodes)
+			assert.Empty(s.T(), resources)
+
+			changes := &ImportChanges{Roadmap: newTestRoadmap(s.ownerID, slug)}
+			for _, key := range keys {
+				changes.Graph.CreateNodes = append(changes.Graph.CreateNodes, newKeyedNode(key))
+			}
+			changes.Resources.CreateResources = []*roadmapentity.Resource{
+				newTestResource(changes.Roadmap.ID, changes.Graph.CreateNodes[0].ID, url),
+			}
+			return changes, nil
+		})
+	require.NoError(s.T(), err)
+	return imported
+}
+
+func (s *DocumentRepositoryIntegrationTestSuite) TestDocumentRepository_NodeKeys() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	roadmap, err := s.roadmapRepo.Create(s.ctx, newTestRoadmap(s.ownerID, "keys"))
+	require.NoError(s.T(), err)
+
+	keyed, unkeyed := newKeyedNode("basics"), newTestNode("Unkeyed")
+	_, err = s.graphRepo.EditGraph(s.ctx, roadmap.ID, func(*roadmapentity.Graph) (*GraphChanges, error) {
+		return &GraphChanges{CreateNodes: []*roadmapentity.Node{keyed, unkeyed}}, nil
+	})
+	require.NoError(s.T(), err)
+
+	graph, err := s.graphRepo.GetGraph(s.ctx, roadmap.ID)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), findNode(graph, keyed.ID))
+	assert.Equal(s.T(), "basics", findNode(graph, keyed.ID).Key)
+	require.NotNil(s.T(), findNode(graph, unkeyed.ID))
+	assert.Empty(s.T(), findNode(graph, unkeyed.ID).Key)
+
+	// Nodes without a key do not conflict with each other.
+	_, err = s.graphRepo.EditGraph(s.ctx, roadmap.ID, func(*roadmapentity.Graph) (*GraphChanges, error) {
+		return &GraphChanges{CreateNodes: []*roadmapentity.Node{newTestNode("Another")}}, nil
+	})
+	require.NoError(s.T(), err)
+
+	_, err = s.graphRepo.EditGraph(s.ctx, roadmap.ID, func(*roadmapentity.Graph) (*GraphChanges, error) {
+		return &GraphChanges{CreateNodes: []*roadmapentity.Node{newKeyedNode("basics")}}, nil
+	})
+	assert.ErrorIs(s.T(), err, ErrNodeKeyTaken)
+
+	// Keys are only unique per roadmap.
+	other := s.importNew("other", "a.example", "basics")
+	assert.NotEqual(s.T(), roadmap.ID, other.ID)
+}
+
+func (s *DocumentRepositoryIntegrationTestSuite) TestDocumentRepository_ImportCreates() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	imported := s.importNew("imported", "a.example", "basics", "advanced")
+
+	found, err := s.roadmapRepo.GetByOwnerAndSlug(s.ctx, s.ownerID, "imported")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), imported.ID, found.ID)
+
+	graph, err := s.graphRepo.GetGraph(s.ctx, imported.ID)
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), graph.Nodes, 2)
+
+	resources, err := s.resourceRepo.ListByRoadmap(s.ctx, imported.ID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), resources, 1)
+	assert.Equal(s.T(), "a.example", resources[0].NormalizedURL)
+}
+
+func (s *DocumentRepositoryIntegrationTestSuite) TestDocumentRepository_ImportUpdates() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	imported := s.importNew("imported", "a.example", "basics", "advanced")
+
+	updated, err := s.repo.Import(s.ctx, s.ownerID, "imported",
+		func(roadmap *roadmapentity.Roadmap, graph *roadmapentity.Graph, resources []*roadmapentity.Resource) (*ImportChanges, error) {
+			require.NotNil(s.T(), roadmap)
+			assert.Equal(s.T(), imported.ID, roadmap.ID)
+			require.Len(s.T(), graph.Nodes, 2)
+			require.Len(s.T(), resources, 1)
+
+			renamed := *roadmap
+			renamed.Title = "Renamed"
+
+			var basics, advanced *roadmapentity.Node
+			for _, node := range graph.Nodes {
+				if node.Key == "basics" {
+					basics = node
+				} else {
+					advanced = node
+				}
+			}
+			return &ImportChanges{
+				Roadmap: &renamed,
+				Graph:   GraphChanges{DeleteNodes: []uuid.UUID{advanced.ID}},
+				Resources: ResourceChanges{
+					DeleteResources: []uuid.UUID{resources[0].ID},
+					CreateResources: []*roadmapentity.Resource{
+						newTestResource(roadmap.ID, basics.ID, "b.example"),
+						newTestResource(roadmap.ID, basics.ID, "a.example"),
+					},
+				},
+			}, nil
+		})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), imported.ID, updated.ID)
+	assert.Equal(s.T(), "Renamed", updated.Title)
+
+	graph, err := s.graphRepo.GetGraph(s.ctx, imported.ID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), graph.Nodes, 1)
+	assert.Equal(s.T(), "basics", graph.Nodes[0].Key)
+
+	// The deleted resource's URL can be used again in the same import, and
+	// the new resources are appended from the first position.
+	resources, err := s.resourceRepo.ListByRoadmap(s.ctx, imported.ID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), resources, 2)
+	assert.Equal(s.T(), "b.example", resources[0].NormalizedURL)
+	assert.Equal(s.T(), 0, resources[0].Position)
+	assert.Equal(s.T(), "a.example", resources[1].NormalizedURL)
+	assert.Equal(s.T(), 1, resources[1].Position)
+}
+
+func (s *DocumentRepositoryIntegrationTestSuite) TestDocumentRepository_ImportFailureStoresNothing() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	// The duplicate URL fails the last statement of the import, after the
+	// roadmap and its nodes were already written.
+	_, err := s.repo.Import(s.ctx, s.ownerID, "imported",
+		func(*roadmapentity.Roadmap, *roadmapentity.Graph, []*roadmapentity.Resource) (*ImportChanges, error) {
+			roadmap := newTestRoadmap(s.ownerID, "imported")
+			node := newKeyedNode("basics")
+			return &ImportChanges{
+				Roadmap: roadmap,
+				Graph:   GraphChanges{CreateNodes: []*roadmapentity.Node{node}},
+				Resources: ResourceChanges{
+					CreateResources: []*roadmapentity.Resource{
+						newTestResource(roadmap.ID, node.ID, "a.example"),
+						newTestResource(roadmap.ID, node.ID, "a.example"),
+					},
+				},
+			}, nil
+		})
+	assert.ErrorIs(s.T(), err, ErrResourceURLTaken)
+
+	_, err = s.roadmapRepo.GetByOwnerAndSlug(s.ctx, s.ownerID, "imported")
+	assert.ErrorIs(s.T(), err, ErrRoadmapNotFound)
+
+	var nodes int
+	err = s.db.Pool.QueryRow(s.ctx,
+		"SELECT COUNT(*) FROM roadmap_nodes n JOIN roadmaps r ON r.id = n.roadmap_id WHERE r.owner_id = $1",
+		s.ownerID).Scan(&nodes)
+	require.NoError(s.T(), err)
+	assert.Zero(s.T(), nodes)
+}
+
+func (s *DocumentRepositoryIntegrationTestSuite) TestDocumentRepository_ImportPlanError() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	imported := s.importNew("imported", "a.example", "basics")
+
+	planErr := errors.New("dry run")
+	_, err := s.repo.Import(s.ctx, s.ownerID, "imported",
+		func(*roadmapentity.Roadmap, *roadmapentity.Graph, []*roadmapentity.Resource) (*ImportChanges, error) {
+			return nil, planErr
+		})
+	assert.ErrorIs(s.T(), err, planErr)
+
+	graph, err := s.graphRepo.GetGraph(s.ctx, imported.ID)
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), graph.Nodes, 1)
+}
+
+func (s *DocumentRepositoryIntegrationTestSuite) TestDocumentRepository_ImportSlugTaken() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	s.importNew("taken", "a.example", "basics")
+	imported := s.importNew("imported", "b.example", "basics")
+
+	_, err := s.repo.Import(s.ctx, s.ownerID, "imported",
+		func(roadmap *roadmapentity.Roadmap, _ *roadmapentity.Graph, _ []*roadmapentity.Resource) (*ImportChanges, error) {
+			renamed := *roadmap
+			renamed.Slug = "taken"
+			return &ImportChanges{Roadmap: &renamed}, nil
+		})
+	assert.ErrorIs(s.T(), err, ErrSlugTaken)
+
+	found, err := s.roadmapRepo.GetByOwnerAndSlug(s.ctx, s.ownerID, "imported")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), imported.ID, found.ID)
+}
+
+func TestDocumentRepositoryIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(DocumentRepositoryIntegrationTestSuite))
+}
@@ -0,0 +1,196 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrNodeIDTaken  = errors.New("node id already in use")
+	ErrNodeKeyTaken = errors.New("node key already in use")
+)
+
+// nodeKeyIndex is the unique index on the key of roadmap_nodes.
+const nodeKeyIndex = "idx_roadmap_nodes_roadmap_id_key"
+
+// graphQuery aggregates nodes and edges into JSON arrays whose keys match
+// the JSON tags of the entities. Timestamps are converted to timestamptz so
+// they are encoded with an offset.
+const graphQuery = `
+	SELECT
+		COALESCE((
+			SELECT json_agg(json_build_object(
+				'id', id,
+				'roadmap_id', roadmap_id,
+				'key', COALESCE(key, ''),
+				'title', title,
+				'description', description,
+				'type', type,
+				'position_x', position_x,
+				'position_y', position_y,
+				'created_at', created_at AT TIME ZONE 'UTC',
+				'updated_at', updated_at AT TIME ZONE 'UTC'
+			) ORDER BY created_at, id)
+			FROM roadmap_nodes WHERE roadmap_id = $1
+		), '[]'),
+		COALESCE((
+			SELECT json_agg(json_build_object(
+				'roadmap_id', roadmap_id,
+				'prerequisite_id', prerequisite_id,
+				'node_id', node_id,
+				'created_at', created_at AT TIME ZONE 'UTC'
+			) ORDER BY created_at, prerequisite_id, node_id)
+			FROM roadmap_edges WHERE roadmap_id = $1
+		), '[]')`
+
+// querier is satisfied by both the pool and a transaction.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults
+}
+
+type graphRepository struct {
+	db *database.Database
+}
+
+func NewGraphRepository(db *database.Database) GraphRepository {
+	return &graphRepository{
+		db: db,
+	}
+}
+
+func getGraph(ctx context.Context, q querier, roadmapID uuid.UUID) (*roadmapentity.Graph, error) {
+	var graph roadmapentity.Graph
+	if err := q.QueryRow(ctx, graphQuery, roadmapID).Scan(&graph.Nodes, &graph.Edges); err != nil {
+		return nil, err
+	}
+	return &graph, nil
+}
+
+func (r *graphRepository) GetGraph(ctx context.Context, roadmapID uuid.UUID) (*roadmapentity.Graph, error) {
+	graph, err := getGraph(ctx, r.db.Pool, roadmapID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roadmap graph: %w", err)
+	}
+
+	return graph, nil
+}
+
+func (r *graphRepository) EditGraph(
+	ctx context.Context,
+	roadmapID uuid.UUID,
+	edit GraphEditFunc,
+) (*roadmapentity.Graph, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			log.Printf("Failed to rollback roadmap graph edit: %v", rollbackErr)
+		}
+	}()
+
+	// Touching the roadmap locks its row until the transaction ends, which
+	// serializes edits of the same graph.
+	tag, err := tx.Exec(ctx, `UPDATE roadmaps SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`, roadmapID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock roadmap: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrRoadmapNotFound
+	}
+
+	graph, err := getGraph(ctx, tx, roadmapID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roadmap graph: %w", err)
+	}
+
+	changes, err := edit(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyGraphChanges(ctx, tx, roadmapID, changes); err != nil {
+		return nil, err
+	}
+
+	graph, err = getGraph(ctx, tx, roadmapID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roadmap graph: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit roadmap graph edit: %w", err)
+	}
+
+	return graph, nil
+}
+
+// applyGraphChanges runs the changes as one batch. Unique violations are
+// reported as ErrNodeKeyTaken or ErrNodeIDTaken.
+func applyGraphChanges(ctx context.Context, q querier, roadmapID uuid.UUID, changes *GraphChanges) error {
+	batch := queueGraphChanges(roadmapID, changes)
+	results := q.SendBatch(ctx, batch)
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := results.Exec(); err != nil {
+			if closeErr := results.Close(); closeErr != nil {
+				log.Printf("Failed to close roadmap graph batch: %v", closeErr)
+			}
+			if isUniqueViolation(err) {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.ConstraintName == nodeKeyIndex {
+					return ErrNodeKeyTaken
+				}
+				return ErrNodeIDTaken
+			}
+			return fmt.Errorf("failed to edit roadmap graph: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("failed to edit roadmap graph: %w", err)
+	}
+	return nil
+}
+
+func queueGraphChanges(roadmapID uuid.UUID, changes *GraphChanges) *pgx.Batch {
+	batch := &pgx.Batch{}
+
+	for _, edge := range changes.RemoveEdges {
+		batch.Queue(`DELETE FROM roadmap_edges WHERE roadmap_id = $1 AND prerequisite_id = $2 AND node_id = $3`,
+			roadmapID, edge.PrerequisiteID, edge.NodeID)
+	}
+	for _, id := range changes.DeleteNodes {
+		batch.Queue(`DELETE FROM roadmap_nodes WHERE roadmap_id = $1 AND id = $2`, roadmapID, id)
+	}
+	for _, node := range changes.CreateNodes {
+		batch.Queue(`
+			INSERT INTO roadmap_nodes (id, roadmap_id, title, description, type, position_x, position_y, key)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))`,
+			node.ID, roadmapID, node.Title, node.Description, node.Type, node.PositionX, node.PositionY, node.Key)
+	}
+	for _, node := range changes.UpdateNodes {
+		batch.Queue(`
+			UPDATE roadmap_nodes SET title = $3, description = $4, type = $5, position_x = $6, position_y = $7,
+				key = NULLIF($8, '')
+			WHERE roadmap_id = $1 AND id = $2`,
+			roadmapID, node.ID, node.Title, node.Description, node.Type, node.PositionX, node.PositionY, node.Key)
+	}
+	for _, edge := range changes.AddEdges {
+		batch.Queue(`INSERT INTO roadmap_edges (roadmap_id, prerequisite_id, node_id) VALUES ($1, $2, $3)`,
+			roadmapID, edge.PrerequisiteID, edge.NodeID)
+	}
+
+	return batch
+}
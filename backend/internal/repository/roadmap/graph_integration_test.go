@@ -0,0 +1,285 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	"roadmap/internal/infrastructure/database"
+)
+
+type GraphRepositoryIntegrationTestSuite struct {
+	suite.Suite
+	repo        *graphRepository
+	roadmapRepo *roadmapRepository
+	db          *database.Database
+	ctx         context.Context
+	roadmap     *roadmapentity.Roadmap
+}
+
+func (s *GraphRepositoryIntegrationTestSuite) SetupSuite() {
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		s.T().Skip("Skipping integration tests: TEST_DB_DSN not set")
+		return
+	}
+
+	cfg := &database.Config{
+		Host:     "localhost",
+		Port:     "5432",
+		User:     "postgres",
+		Password: "postgres",
+		DBName:   "roadmap_test",
+		SSLMode:  "disable",
+	}
+
+	var err error
+	s.db, err = database.NewDatabase(cfg)
+	require.NoError(s.T(), err, "Failed to connect to test database")
+
+	s.repo = NewGraphRepository(s.db).(*graphRepository)
+	s.roadmapRepo = NewRoadmapRepository(s.db).(*roadmapRepository)
+	s.ctx = context.Background()
+}
+
+func (s *GraphRepositoryIntegrationTestSuite) TearDownSuite() {
+	if s.db != nil {
+		s.db.Close()
+	}
+}
+
+func (s *GraphRepositoryIntegrationTestSuite) SetupTest() {
+	if s.db == nil {
+		return
+	}
+	s.cleanupTestData()
+
+	ownerID := createTestUser(s.T(), s.ctx, s.db, "test_graph@example.com")
+
+	var err error
+	s.roadmap, err = s.roadmapRepo.Create(s.ctx, newTestRoadmap(ownerID, "graph"))
+	require.NoError(s.T(), err)
+}
+
+func (s *GraphRepositoryIntegrationTestSuite) TearDownTest() {
+	s.cleanupTestData()
+}
+
+func (s *GraphRepositoryIntegrationTestSuite) cleanupTestData() {
+	if s.db == nil {
+		return
+	}
+
+	_, err := s.db.Pool.Exec(s.ctx, "DELETE FROM users WHERE email = 'test_graph@example.com'")
+	if err != nil {
+		s.T().Logf("Warning: Failed to cleanup test data: %v", err)
+	}
+}
+
+func newTestNode(title string) *roadmapentity.Node {
+	return &roadmapentity.Node{
+		ID:          uuid.New(),
+		Title:       title,
+		Description: title + " description",
+		Type:        roadmapentity.NodeTypeTopic,
+		PositionX:   1.5,
+		PositionY:   -2,
+	}
+}
+
+// createNodes adds the nodes to the test roadmap, each one a prerequisite of
+// the next.
+func (s *GraphRepositoryIntegrationTestSuite) createNodes(nodes ...*roadmapentity.Node) *roadmapentity.Graph {
+	changes := &GraphChanges{CreateNodes: nodes}
+	for i := 1; i < len(nodes); i++ {
+		changes.AddEdges = append(changes.AddEdges, &roadmapentity.Edge{
+			PrerequisiteID: nodes[i-1].ID,
+			NodeID:         nodes[i].ID,
+		})
+	}
+
+	graph, err := s.repo.EditGraph(s.ctx, s.roadmap.ID, func(*roadmapentity.Graph) (*GraphChanges, error) {
+		return changes, nil
+	})
+	require.NoError(s.T(), err)
+	return graph
+}
+
+// findNode returns the node with the ID. Nodes created in one transaction
+// share their creation time, so their order in the graph is by ID.
+func findNode(graph *roadmapentity.Graph, id uuid.UUID) *roadmapentity.Node {
+	for _, node := range graph.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+	return nil
+}
+
+func (s *GraphRepositoryIntegrationTestSuite) TestGraphRepository_GetGraph_Empty() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	graph, err := s.repo.GetGraph(s.ctx, s.roadmap.ID)
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), graph.Nodes)
+	assert.Empty(s.T(), graph.Edges)
+
+	graph, err = s.repo.GetGraph(s.ctx, uuid.New())
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), graph.Nodes)
+	assert.Empty(s.T(), graph.Edges)
+}
+
+func (s *GraphRepositoryIntegrationTestSuite) TestGraphRepository_GetGraph() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	first, second := newTestNode("First"), newTestNode("Second")
+	s.createNodes(first, second)
+
+	graph, err := s.repo.GetGraph(s.ctx, s.roadmap.ID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), graph.Nodes, 2)
+	require.Len(s.T(), graph.Edges, 1)
+
+	node := findNode(graph, first.ID)
+	require.NotNil(s.T(), node)
+	assert.Equal(s.T(), s.roadmap.ID, node.RoadmapID)
+	assert.Equal(s.T(), "First", node.Title)
+	assert.Equal(s.T(), "First description", node.Description)
+	assert.Equal(s.T(), roadmapentity.NodeTypeTopic, node.Type)
+	assert.Equal(s.T(), 1.5, node.PositionX)
+	assert.Equal(s.T(), -2.0, node.PositionY)
+	assert.Empty(s.T(), node.Key)
+
+	edge := graph.Edges[0]
+	assert.Equal(s.T(), s.roadmap.ID, edge.RoadmapID)
+	assert.Equal(s.T(), first.ID, edge.PrerequisiteID)
+	assert.Equal(s.T(), second.ID, edge.NodeID)
+	assert.False(s.T(), edge.CreatedAt.IsZero())
+}
+
+func (s *GraphRepositoryIntegrationTestSuite) TestGraphRepository_GetGraph_Timestamps() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	node := newTestNode("First")
+	s.createNodes(node)
+
+	// The JSON timestamps must describe the same instants pgx reads from the
+	// columns directly.
+	var createdAt, updatedAt time.Time
+	err := s.db.Pool.QueryRow(s.ctx, "SELECT created_at, updated_at FROM roadmap_nodes WHERE id = $1", node.ID).
+		Scan(&createdAt, &updatedAt)
+	require.NoError(s.T(), err)
+
+	graph, err := s.repo.GetGraph(s.ctx, s.roadmap.ID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), graph.Nodes, 1)
+	assert.True(s.T(), createdAt.Equal(graph.Nodes[0].CreatedAt), "created_at %v != %v", createdAt, graph.Nodes[0].CreatedAt)
+	assert.True(s.T(), updatedAt.Equal(graph.Nodes[0].UpdatedAt), "updated_at %v != %v", updatedAt, graph.Nodes[0].UpdatedAt)
+}
+
+func (s *GraphRepositoryIntegrationTestSuite) TestGraphRepository_EditGraph() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	first, second, third := newTestNode("First"), newTestNode("Second"), newTestNode("Third")
+	s.createNodes(first, second, third)
+
+	var seen *roadmapentity.Graph
+	graph, err := s.repo.EditGraph(s.ctx, s.roadmap.ID, func(graph *roadmapentity.Graph) (*GraphChanges, error) {
+		seen = graph
+		renamed := *first
+		renamed.Title = "Renamed"
+		renamed.Type = roadmapentity.NodeTypeMilestone
+		return &GraphChanges{
+			RemoveEdges: []*roadmapentity.Edge{{PrerequisiteID: first.ID, NodeID: second.ID}},
+			DeleteNodes: []uuid.UUID{third.ID},
+			UpdateNodes: []*roadmapentity.Node{&renamed},
+			AddEdges:    []*roadmapentity.Edge{{PrerequisiteID: second.ID, NodeID: first.ID}},
+		}, nil
+	})
+	require.NoError(s.T(), err)
+
+	require.NotNil(s.T(), seen)
+	assert.Len(s.T(), seen.Nodes, 3)
+	assert.Len(s.T(), seen.Edges, 2)
+
+	// Deleting the third node also removes its edge from the second.
+	require.Len(s.T(), graph.Nodes, 2)
+	assert.Nil(s.T(), findNode(graph, third.ID))
+	renamed := findNode(graph, first.ID)
+	require.NotNil(s.T(), renamed)
+	assert.Equal(s.T(), "Renamed", renamed.Title)
+	assert.Equal(s.T(), roadmapentity.NodeTypeMilestone, renamed.Type)
+	require.Len(s.T(), graph.Edges, 1)
+	assert.Equal(s.T(), second.ID, graph.Edges[0].PrerequisiteID)
+	assert.Equal(s.T(), first.ID, graph.Edges[0].NodeID)
+}
+
+func (s *GraphRepositoryIntegrationTestSuite) TestGraphRepository_EditGraph_EditError() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	editErr := errors.New("rejected")
+	_, err := s.repo.EditGraph(s.ctx, s.roadmap.ID, func(*roadmapentity.Graph) (*GraphChanges, error) {
+		return nil, editErr
+	})
+	assert.ErrorIs(s.T(), err, editErr)
+}
+
+func (s *GraphRepositoryIntegrationTestSuite) TestGraphRepository_EditGraph_NodeIDTaken() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	node := newTestNode("First")
+	s.createNodes(node)
+
+	// The whole batch is rolled back when one statement fails.
+	added := newTestNode("Added")
+	duplicate := newTestNode("Duplicate")
+	duplicate.ID = node.ID
+	_, err := s.repo.EditGraph(s.ctx, s.roadmap.ID, func(*roadmapentity.Graph) (*GraphChanges, error) {
+		return &GraphChanges{CreateNodes: []*roadmapentity.Node{added, duplicate}}, nil
+	})
+	assert.ErrorIs(s.T(), err, ErrNodeIDTaken)
+
+	graph, err := s.repo.GetGraph(s.ctx, s.roadmap.ID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), graph.Nodes, 1)
+	assert.Equal(s.T(), "First", graph.Nodes[0].Title)
+}
+
+func (s *GraphRepositoryIntegrationTestSuite) TestGraphRepository_EditGraph_RoadmapNotFound() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	called := false
+	_, err := s.repo.EditGraph(s.ctx, uuid.New(), func(*roadmapentity.Graph) (*GraphChanges, error) {
+		called = true
+		return &GraphChanges{}, nil
+	})
+	assert.ErrorIs(s.T(), err, ErrRoadmapNotFound)
+	assert.False(s.T(), called)
+}
+
+func TestGraphRepositoryIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(GraphRepositoryIntegrationTestSuite))
+}
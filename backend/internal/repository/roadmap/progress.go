@@ -0,0 +1,165 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const nodeProgressColumns = `user_id, roadmap_id, node_id, status, prerequisites_overridden, started_at, completed_at, updated_at`
+
+type progressRepository struct {
+	db *database.Database
+}
+
+func NewProgressRepository(db *database.Database) ProgressRepository {
+	return &progressRepository{
+		db: db,
+	}
+}
+
+func scanNodeProgress(row pgx.Row) (*roadmapentity.NodeProgress, error) {
+	var progress roadmapentity.NodeProgress
+	err := row.Scan(
+		&progress.UserID,
+		&progress.RoadmapID,
+		&progress.NodeID,
+		&progress.Status,
+		&progress.PrerequisitesOverridden,
+		&progress.StartedAt,
+		&progress.CompletedAt,
+		&progress.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+func (r *progressRepository) list(ctx context.Context, query string, args ...any) ([]*roadmapentity.NodeProgress, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node progress: %w", err)
+	}
+
+	progress, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*roadmapentity.NodeProgress, error) {
+		return scanNodeProgress(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+func (r *progressRepository) ListByRoadmap(
+	ctx context.Context,
+	userID, roadmapID uuid.UUID,
+) ([]*roadmapentity.NodeProgress, error) {
+	query := `SELECT ` + nodeProgressColumns + ` FROM node_progress WHERE user_id = $1 AND roadmap_id = $2`
+
+	return r.list(ctx, query, userID, roadmapID)
+}
+
+func (r *progressRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*roadmapentity.NodeProgress, error) {
+	query := `SELECT ` + nodeProgressColumns + ` FROM node_progress WHERE user_id = $1 ORDER BY roadmap_id, updated_at`
+
+	return r.list(ctx, query, userID)
+}
+
+func (r *progressRepository) EditProgress(
+	ctx context.Context,
+	userID, roadmapID uuid.UUID,
+	edit ProgressEditFunc,
+) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			log.Printf("Failed to rollback node progress edit: %v", rollbackErr)
+		}
+	}()
+
+	// The lock conflicts with the one EditGraph takes, so the graph cannot
+	// change between the check and the save, and with concurrent progress
+	// edits of the same roadmap.
+	var locked int
+	err = tx.QueryRow(ctx, `SELECT 1 FROM roadmaps WHERE id = $1 FOR NO KEY UPDATE`, roadmapID).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrRoadmapNotFound
+		}
+		return fmt.Errorf("failed to lock roadmap: %w", err)
+	}
+
+	graph, err := getGraph(ctx, tx, roadmapID)
+	if err != nil {
+		return fmt.Errorf("failed to get roadmap graph: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT `+nodeProgressColumns+` FROM node_progress WHERE user_id = $1 AND roadmap_id = $2`,
+		userID, roadmapID)
+	if err != nil {
+		return fmt.Errorf("failed to list node progress: %w", err)
+	}
+	progress, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*roadmapentity.NodeProgress, error) {
+		return scanNodeProgress(row)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list node progress: %w", err)
+	}
+
+	changes, err := edit(graph, progress)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.SendBatch(ctx, queueProgress(changes)).Close(); err != nil {
+		return fmt.Errorf("failed to save node progress: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit node progress edit: %w", err)
+	}
+
+	return nil
+}
+
+// queueProgress upserts each entry, or deletes it if it is not started.
+func queueProgress(progress []*roadmapentity.NodeProgress) *pgx.Batch {
+	batch := &pgx.Batch{}
+	for _, entry := range progress {
+		if entry.Status == roadmapentity.ProgressNotStarted {
+			batch.Queue(`DELETE FROM node_progress WHERE user_id = $1 AND node_id = $2`, entry.UserID, entry.NodeID)
+			continue
+		}
+		batch.Queue(`
+			INSERT INTO node_progress (`+nodeProgressColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (user_id, node_id) DO UPDATE SET
+				status = EXCLUDED.status,
+				prerequisites_overridden = EXCLUDED.prerequisites_overridden,
+				started_at = EXCLUDED.started_at,
+				completed_at = EXCLUDED.completed_at,
+				updated_at = EXCLUDED.updated_at`,
+			entry.UserID,
+			entry.RoadmapID,
+			entry.NodeID,
+			entry.Status,
+			entry.PrerequisitesOverridden,
+			entry.StartedAt,
+			entry.CompletedAt,
+			entry.UpdatedAt,
+		)
+	}
+	return batch
+}
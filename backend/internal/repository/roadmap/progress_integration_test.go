@@ -0,0 +1,250 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	"roadmap/internal/infrastructure/database"
+)
+
+type ProgressRepositoryIntegrationTestSuite struct {
+	suite.Suite
+	repo    *progressRepository
+	db      *database.Database
+	ctx     context.Context
+	userID  uuid.UUID
+	roadmap *roadmapentity.Roadmap
+	first   *roadmapentity.Node
+	second  *roadmapentity.Node
+}
+
+func (s *ProgressRepositoryIntegrationTestSuite) SetupSuite() {
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		s.T().Skip("Skipping integration tests: TEST_DB_DSN not set")
+		return
+	}
+
+	cfg := &database.Config{
+		Host:     "localhost",
+		Port:     "5432",
+		User:     "postgres",
+		Password: "postgres",
+		DBName:   "roadmap_test",
+		SSLMode:  "disable",
+	}
+
+	var err error
+	s.db, err = database.NewDatabase(cfg)
+	require.NoError(s.T(), err, "Failed to connect to test database")
+
+	s.repo = NewProgressRepository(s.db).(*progressRepository)
+	s.ctx = context.Background()
+}
+
+func (s *ProgressRepositoryIntegrationTestSuite) TearDownSuite() {
+	if s.db != nil {
+		s.db.Close()
+	}
+}
+
+func (s *ProgressRepositoryIntegrationTestSuite) SetupTest() {
+	if s.db == nil {
+		return
+	}
+	s.cleanupTestData()
+
+	s.userID = createTestUser(s.T(), s.ctx, s.db, "test_progress@example.com")
+
+	var err error
+	s.roadmap, err = NewRoadmapRepository(s.db).Create(s.ctx, newTestRoadmap(s.userID, "progress"))
+	require.NoError(s.T(), err)
+
+	s.first, s.second = newTestNode("First"), newTestNode("Second")
+	_, err = NewGraphRepository(s.db).EditGraph(s.ctx, s.roadmap.ID, func(*roadmapentity.Graph) (*GraphChanges, error) {
+		return &GraphChanges{
+			CreateNodes: []*roadmapentity.Node{s.first, s.second},
+			AddEdges:    []*roadmapentity.Edge{{PrerequisiteID: s.first.ID, NodeID: s.second.ID}},
+		}, nil
+	})
+	require.NoError(s.T(), err)
+}
+
+func (s *ProgressRepositoryIntegrationTestSuite) TearDownTest() {
+	s.cleanupTestData()
+}
+
+func (s *ProgressRepositoryIntegrationTestSuite) cleanupTestData() {
+	if s.db == nil {
+		return
+	}
+
+	_, err := s.db.Pool.Exec(s.ctx, "DELETE FROM users WHERE email = 'test_progress@example.com'")
+	if err != nil {
+		s.T().Logf("Warning: Failed to cleanup test data: %v", err)
+	}
+}
+
+func (s *ProgressRepositoryIntegrationTestSuite) newProgress(node *roadmapentity.Node, status string) *roadmapentity.NodeProgress {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	progress := &roadmapentity.NodeProgress{
+		UserID:    s.userID,
+		RoadmapID: s.roadmap.ID,
+		NodeID:    node.ID,
+		Status:    status,
+		StartedAt: &now,
+		UpdatedAt: now,
+	}
+	if status == roadmapentity.ProgressDone {
+		progress.CompletedAt = &now
+	}
+	return progress
+}
+
+func (s *ProgressRepositoryIntegrationTestSuite) listProgress() map[uuid.UUID]*roadmapentity.NodeProgress {
+	progress, err := s.repo.ListByRoadmap(s.ctx, s.userID, s.roadmap.ID)
+	require.NoError(s.T(), err)
+
+	byNode := make(map[uuid.UUID]*roadmapentity.NodeProgress, len(progress))
+	for _, entry := range progress {
+		byNode[entry.NodeID] = entry
+	}
+	return byNode
+}
+
+// save stores the entries through EditProgress.
+func (s *ProgressRepositoryIntegrationTestSuite) save(progress ...*roadmapentity.NodeProgress) error {
+	return s.repo.EditProgress(s.ctx, s.userID, s.roadmap.ID,
+		func(*roadmapentity.Graph, []*roadmapentity.NodeProgress) ([]*roadmapentity.NodeProgress, error) {
+			return progress, nil
+		})
+}
+
+func (s *ProgressRepositoryIntegrationTestSuite) TestProgressRepository_EditProgress_Upserts() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	err := s.save(s.newProgress(s.first, roadmapentity.ProgressInProgress))
+	require.NoError(s.T(), err)
+
+	progress := s.listProgress()
+	require.Len(s.T(), progress, 1)
+	assert.Equal(s.T(), roadmapentity.ProgressInProgress, progress[s.first.ID].Status)
+	assert.Nil(s.T(), progress[s.first.ID].CompletedAt)
+
+	// Saving the same node again updates its entry.
+	done := s.newProgress(s.first, roadmapentity.ProgressDone)
+	done.PrerequisitesOverridden = true
+	err = s.save(done, s.newProgress(s.second, roadmapentity.ProgressSkipped))
+	require.NoError(s.T(), err)
+
+	progress = s.listProgress()
+	require.Len(s.T(), progress, 2)
+	assert.Equal(s.T(), roadmapentity.ProgressDone, progress[s.first.ID].Status)
+	assert.True(s.T(), progress[s.first.ID].PrerequisitesOverridden)
+	require.NotNil(s.T(), progress[s.first.ID].CompletedAt)
+	assert.True(s.T(), done.CompletedAt.Equal(*progress[s.first.ID].CompletedAt))
+	assert.Equal(s.T(), roadmapentity.ProgressSkipped, progress[s.second.ID].Status)
+}
+
+func (s *ProgressRepositoryIntegrationTestSuite) TestProgressRepository_EditProgress_NotStartedDeletes() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	err := s.save(
+		s.newProgress(s.first, roadmapentity.ProgressDone),
+		s.newProgress(s.second, roadmapentity.ProgressInProgress),
+	)
+	require.NoError(s.T(), err)
+
+	err = s.save(&roadmapentity.NodeProgress{
+		UserID: s.userID, RoadmapID: s.roadmap.ID, NodeID: s.second.ID, Status: roadmapentity.ProgressNotStarted,
+	})
+	require.NoError(s.T(), err)
+
+	progress := s.listProgress()
+	require.Len(s.T(), progress, 1)
+	assert.Contains(s.T(), progress, s.first.ID)
+
+	// Resetting a node without progress is not an error.
+	err = s.save(&roadmapentity.NodeProgress{
+		UserID: s.userID, RoadmapID: s.roadmap.ID, NodeID: s.second.ID, Status: roadmapentity.ProgressNotStarted,
+	})
+	assert.NoError(s.T(), err)
+}
+
+func (s *ProgressRepositoryIntegrationTestSuite) TestProgressRepository_EditProgress() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	err := s.save(s.newProgress(s.first, roadmapentity.ProgressInProgress))
+	require.NoError(s.T(), err)
+
+	var seenGraph *roadmapentity.Graph
+	var seenProgress []*roadmapentity.NodeProgress
+	err = s.repo.EditProgress(s.ctx, s.userID, s.roadmap.ID,
+		func(graph *roadmapentity.Graph, progress []*roadmapentity.NodeProgress) ([]*roadmapentity.NodeProgress, error) {
+			seenGraph, seenProgress = graph, progress
+			return []*roadmapentity.NodeProgress{
+				s.newProgress(s.first, roadmapentity.ProgressDone),
+				s.newProgress(s.second, roadmapentity.ProgressInProgress),
+			}, nil
+		})
+	require.NoError(s.T(), err)
+
+	require.NotNil(s.T(), seenGraph)
+	assert.Len(s.T(), seenGraph.Nodes, 2)
+	assert.Len(s.T(), seenGraph.Edges, 1)
+	require.Len(s.T(), seenProgress, 1)
+	assert.Equal(s.T(), roadmapentity.ProgressInProgress, seenProgress[0].Status)
+
+	progress := s.listProgress()
+	require.Len(s.T(), progress, 2)
+	assert.Equal(s.T(), roadmapentity.ProgressDone, progress[s.first.ID].Status)
+	assert.Equal(s.T(), roadmapentity.ProgressInProgress, progress[s.second.ID].Status)
+}
+
+func (s *ProgressRepositoryIntegrationTestSuite) TestProgressRepository_EditProgress_EditError() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	editErr := errors.New("prerequisites not met")
+	err := s.repo.EditProgress(s.ctx, s.userID, s.roadmap.ID,
+		func(*roadmapentity.Graph, []*roadmapentity.NodeProgress) ([]*roadmapentity.NodeProgress, error) {
+			return nil, editErr
+		})
+	assert.ErrorIs(s.T(), err, editErr)
+	assert.Empty(s.T(), s.listProgress())
+}
+
+func (s *ProgressRepositoryIntegrationTestSuite) TestProgressRepository_EditProgress_RoadmapNotFound() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	called := false
+	err := s.repo.EditProgress(s.ctx, s.userID, uuid.New(),
+		func(*roadmapentity.Graph, []*roadmapentity.NodeProgress) ([]*roadmapentity.NodeProgress, error) {
+			called = true
+			return nil, nil
+		})
+	assert.ErrorIs(s.T(), err, ErrRoadmapNotFound)
+	assert.False(s.T(), called)
+}
+
+func TestProgressRepositoryIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(ProgressRepositoryIntegrationTestSuite))
+}
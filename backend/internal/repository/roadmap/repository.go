@@ -0,0 +1,168 @@
+package roadmap
+
+import (
+	"context"
+	"time"
+
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+
+	"github.com/google/uuid"
+)
+
+type RoadmapRepository interface {
+	// Create returns ErrSlugTaken if the owner already has a roadmap with the
+	// same slug.
+	Create(ctx context.Context, roadmap *roadmapentity.Roadmap) (*roadmapentity.Roadmap, error)
+
+	GetByID(ctx context.Context, id uuid.UUID) (*roadmapentity.Roadmap, error)
+
+	GetByOwnerAndSlug(ctx context.Context, ownerID uuid.UUID, slug string) (*roadmapentity.Roadmap, error)
+
+	// List returns one page of roadmaps matching filter, newest first, and
+	// the number of matching roadmaps across all pages.
+	List(ctx context.Context, filter RoadmapListFilter) ([]*roadmapentity.Roadmap, int, error)
+
+	// ListByOwner returns every roadmap owned by ownerID regardless of its
+	// visibility, oldest first.
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*roadmapentity.Roadmap, error)
+
+	// Update persists the title, slug, description and visibility. It
+	// returns ErrSlugTaken like Create.
+	Update(ctx context.Context, roadmap *roadmapentity.Roadmap) (*roadmapentity.Roadmap, error)
+
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// RoadmapListFilter narrows RoadmapRepository.List to the roadmaps ViewerID
+// owns and the public roadmaps of everyone else. OwnerID, when set, further
+// restricts the listing to one owner.
+type RoadmapListFilter struct {
+	ViewerID uuid.UUID
+	OwnerID  *uuid.UUID
+	Limit    int
+	Offset   int
+}
+
+type GraphRepository interface {
+	// GetGraph loads every node and edge of the roadmap in one query. A
+	// roadmap without nodes, or one that does not exist, has an empty graph.
+	GetGraph(ctx context.Context, roadmapID uuid.UUID) (*roadmapentity.Graph, error)
+
+	// EditGraph locks the roadmap, passes its current graph to edit and
+	// applies the returned changes in the same transaction, so concurrent
+	// edits are validated against each other's results. An error from edit
+	// is returned as is and nothing is changed. EditGraph returns the graph
+	// after the changes, or ErrRoadmapNotFound.
+	EditGraph(ctx context.Context, roadmapID uuid.UUID, edit GraphEditFunc) (*roadmapentity.Graph, error)
+}
+
+type GraphEditFunc func(graph *roadmapentity.Graph) (*GraphChanges, error)
+
+// GraphChanges are applied in field order: edges are removed before nodes
+// are deleted, and nodes are created before edges are added.
+type GraphChanges struct {
+	RemoveEdges []*roadmapentity.Edge
+	DeleteNodes []uuid.UUID
+	CreateNodes []*roadmapentity.Node
+	UpdateNodes []*roadmapentity.Node
+	AddEdges    []*roadmapentity.Edge
+}
+
+type DocumentRepository interface {
+	// Import locks the owner's roadmap with the slug like EditGraph, passes
+	// it with its graph and resources to plan and applies the returned
+	// changes in the same transaction, so an import is stored completely or
+	// not at all. The roadmap is nil if the owner has none with the slug. An
+	// error from plan is returned as is and nothing is changed. Import
+	// returns the roadmap after the changes, or ErrSlugTaken,
+	// ErrNodeIDTaken, ErrNodeKeyTaken or ErrResourceURLTaken if the changes
+	// conflict with stored data.
+	Import(ctx context.Context, ownerID uuid.UUID, slug string, plan ImportPlanFunc) (*roadmapentity.Roadmap, error)
+}
+
+type ImportPlanFunc func(
+	roadmap *roadmapentity.Roadmap,
+	graph *roadmapentity.Graph,
+	resources []*roadmapentity.Resource,
+) (*ImportChanges, error)
+
+// ImportChanges are applied in field order. Roadmap is created if there was
+// none and updated otherwise; nil leaves an existing roadmap as is.
+type ImportChanges struct {
+	Roadmap   *roadmapentity.Roadmap
+	Graph     GraphChanges
+	Resources ResourceChanges
+}
+
+// ResourceChanges are applied in field order. Deletes come first so that a
+// resource moving to another node can be created there with the same URL.
+// Created resources are appended to their node before Reorders run.
+type ResourceChanges struct {
+	DeleteResources []uuid.UUID
+	UpdateResources []*roadmapentity.Resource
+	CreateResources []*roadmapentity.Resource
+	Reorders        []ResourceOrder
+}
+
+// ResourceOrder lists every resource of a node in its new order.
+type ResourceOrder struct {
+	NodeID      uuid.UUID
+	ResourceIDs []uuid.UUID
+}
+
+type ProgressRepository interface {
+	ListByRoadmap(ctx context.Context, userID, roadmapID uuid.UUID) ([]*roadmapentity.NodeProgress, error)
+
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*roadmapentity.NodeProgress, error)
+
+	// EditProgress locks the roadmap like EditGraph, passes its current graph
+	// and the user's progress on it to edit and saves the returned entries in
+	// the same transaction, so the graph cannot change between checking and
+	// storing them. Entries whose status is ProgressNotStarted are removed
+	// rather than stored. An error from edit is returned as is and
+	// nothing is changed. EditProgress returns ErrRoadmapNotFound if the
+	// roadmap does not exist.
+	EditProgress(ctx context.Context, userID, roadmapID uuid.UUID, edit ProgressEditFunc) error
+}
+
+type ProgressEditFunc func(
+	graph *roadmapentity.Graph,
+	progress []*roadmapentity.NodeProgress,
+) ([]*roadmapentity.NodeProgress, error)
+
+type ResourceRepository interface {
+	// Create appends the resource to the resources of its node, ignoring
+	// Position. It returns ErrResourceURLTaken if the roadmap already has a
+	// resource with the same normalized URL and ErrNodeNotFound if the node
+	// is not part of the roadmap.
+	Create(ctx context.Context, resource *roadmapentity.Resource) (*roadmapentity.Resource, error)
+
+	GetByID(ctx context.Context, id uuid.UUID) (*roadmapentity.Resource, error)
+
+	// ListByRoadmap returns the resources of every node of the roadmap,
+	// grouped by node and in order within each node.
+	ListByRoadmap(ctx context.Context, roadmapID uuid.UUID) ([]*roadmapentity.Resource, error)
+
+	ListByNode(ctx context.Context, nodeID uuid.UUID) ([]*roadmapentity.Resource, error)
+
+	// Update persists everything but the node and the position. It returns
+	// ErrResourceURLTaken like Create.
+	Update(ctx context.Context, resource *roadmapentity.Resource) (*roadmapentity.Resource, error)
+
+	// Delete removes the resource and closes the gap it leaves in the order
+	// of its node.
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// Reorder gives the resources of the node the positions of their IDs in
+	// resourceIDs, which must list every resource of the node.
+	Reorder(ctx context.Context, nodeID uuid.UUID, resourceIDs []uuid.UUID) error
+
+	// SetCompleted marks the resource as completed by the user at completedAt,
+	// or unmarks it when completedAt is nil. Completing it again keeps the
+	// first completion time.
+	SetCompleted(ctx context.Context, userID, resourceID uuid.UUID, completedAt *time.Time) error
+
+	ListCompletionsByRoadmap(ctx context.Context, userID, roadmapID uuid.UUID) ([]*roadmapentity.ResourceCompletion, error)
+
+	ListCompletionsByUser(ctx context.Context, userID uuid.UUID) ([]*roadmapentity.ResourceCompletion, error)
+}
@@ -0,0 +1,334 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// foreignKeyViolationCode is the Postgres SQLSTATE for foreign_key_violation.
+const foreignKeyViolationCode = "23503"
+
+var (
+	ErrResourceNotFound = errors.New("resource not found")
+	ErrResourceURLTaken = errors.New("resource url already in roadmap")
+	ErrNodeNotFound     = errors.New("node not found")
+)
+
+const resourceColumns = `id, roadmap_id, node_id, url, normalized_url, title, kind, is_free, language, ` +
+	`estimated_minutes, position, created_at, updated_at`
+
+type resourceRepository struct {
+	db *database.Database
+}
+
+func NewResourceRepository(db *database.Database) ResourceRepository {
+	return &resourceRepository{
+		db: db,
+	}
+}
+
+func scanResource(row pgx.Row) (*roadmapentity.Resource, error) {
+	var resource roadmapentity.Resource
+	err := row.Scan(
+		&resource.ID,
+		&resource.RoadmapID,
+		&resource.NodeID,
+		&resource.URL,
+		&resource.NormalizedURL,
+		&resource.Title,
+		&resource.Kind,
+		&resource.IsFree,
+		&resource.Language,
+		&resource.EstimatedMinutes,
+		&resource.Position,
+		&resource.CreatedAt,
+		&resource.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode
+}
+
+func (r *resourceRepository) Create(ctx context.Context, resource *roadmapentity.Resource) (*roadmapentity.Resource, error) {
+	return createResource(ctx, r.db.Pool, resource)
+}
+
+func createResource(ctx context.Context, q querier, resource *roadmapentity.Resource) (*roadmapentity.Resource, error) {
+	query := `
+		INSERT INTO node_resources (id, roadmap_id, node_id, url, normalized_url, title, kind, is_free, language,
+			estimated_minutes, position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+			(SELECT COALESCE(MAX(position) + 1, 0) FROM node_resources WHERE node_id = $3))
+		RETURNING ` + resourceColumns
+
+	created, err := scanResource(q.QueryRow(ctx, query,
+		resource.ID,
+		resource.RoadmapID,
+		resource.NodeID,
+		resource.URL,
+		resource.NormalizedURL,
+		resource.Title,
+		resource.Kind,
+		resource.IsFree,
+		resource.Language,
+		resource.EstimatedMinutes,
+	))
+	if err != nil {
+		switch {
+		case isUniqueViolation(err):
+			return nil, ErrResourceURLTaken
+		case isForeignKeyViolation(err):
+			return nil, ErrNodeNotFound
+		}
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *resourceRepository) GetByID(ctx context.Context, id uuid.UUID) (*roadmapentity.Resource, error) {
+	query := `SELECT ` + resourceColumns + ` FROM node_resources WHERE id = $1`
+
+	resource, err := scanResource(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get resource: %w", err)
+	}
+
+	return resource, nil
+}
+
+func (r *resourceRepository) list(ctx context.Context, query string, args ...any) ([]*roadmapentity.Resource, error) {
+	return listResources(ctx, r.db.Pool, query, args...)
+}
+
+func listResources(ctx context.Context, q querier, query string, args ...any) ([]*roadmapentity.Resource, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	resources, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*roadmapentity.Resource, error) {
+		return scanResource(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	return resources, nil
+}
+
+// resourcesByRoadmapQuery lists the resources of a roadmap grouped by node.
+const resourcesByRoadmapQuery = `SELECT ` + resourceColumns +
+	` FROM node_resources WHERE roadmap_id = $1 ORDER BY node_id, position, created_at, id`
+
+func (r *resourceRepository) ListByRoadmap(ctx context.Context, roadmapID uuid.UUID) ([]*roadmapentity.Resource, error) {
+	return r.list(ctx, resourcesByRoadmapQuery, roadmapID)
+}
+
+func (r *resourceRepository) ListByNode(ctx context.Context, nodeID uuid.UUID) ([]*roadmapentity.Resource, error) {
+	query := `SELECT ` + resourceColumns + ` FROM node_resources WHERE node_id = $1 ORDER BY position, created_at, id`
+
+	return r.list(ctx, query, nodeID)
+}
+
+func (r *resourceRepository) Update(ctx context.Context, resource *roadmapentity.Resource) (*roadmapentity.Resource, error) {
+	return updateResource(ctx, r.db.Pool, resource)
+}
+
+func updateResource(ctx context.Context, q querier, resource *roadmapentity.Resource) (*roadmapentity.Resource, error) {
+	query := `
+		UPDATE node_resources SET url = $2, normalized_url = $3, title = $4, kind = $5, is_free = $6, language = $7,
+			estimated_minutes = $8
+		WHERE id = $1
+		RETURNING ` + resourceColumns
+
+	updated, err := scanResource(q.QueryRow(ctx, query,
+		resource.ID,
+		resource.URL,
+		resource.NormalizedURL,
+		resource.Title,
+		resource.Kind,
+		resource.IsFree,
+		resource.Language,
+		resource.EstimatedMinutes,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrResourceURLTaken
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to update resource: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r *resourceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			log.Printf("Failed to rollback resource deletion: %v", rollbackErr)
+		}
+	}()
+
+	if err := deleteResource(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit resource deletion: %w", err)
+	}
+
+	return nil
+}
+
+// deleteResource removes the resource and moves the resources after it one
+// position up. q should be a transaction.
+func deleteResource(ctx context.Context, q querier, id uuid.UUID) error {
+	var nodeID uuid.UUID
+	var position int
+	err := q.QueryRow(ctx, `DELETE FROM node_resources WHERE id = $1 RETURNING node_id, position`, id).
+		Scan(&nodeID, &position)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrResourceNotFound
+		}
+		return fmt.Errorf("failed to delete resource: %w", err)
+	}
+
+	query := `UPDATE node_resources SET position = position - 1 WHERE node_id = $1 AND position > $2`
+	if _, err := q.Exec(ctx, query, nodeID, position); err != nil {
+		return fmt.Errorf("failed to reorder resources: %w", err)
+	}
+
+	return nil
+}
+
+func (r *resourceRepository) Reorder(ctx context.Context, nodeID uuid.UUID, resourceIDs []uuid.UUID) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			log.Printf("Failed to rollback resource reordering: %v", rollbackErr)
+		}
+	}()
+
+	if err := reorderResources(ctx, tx, nodeID, resourceIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit resource reordering: %w", err)
+	}
+
+	return nil
+}
+
+func reorderResources(ctx context.Context, q querier, nodeID uuid.UUID, resourceIDs []uuid.UUID) error {
+	batch := &pgx.Batch{}
+	for position, id := range resourceIDs {
+		batch.Queue(`UPDATE node_resources SET position = $3 WHERE id = $1 AND node_id = $2`, id, nodeID, position)
+	}
+
+	if err := q.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to reorder resources: %w", err)
+	}
+
+	return nil
+}
+
+func (r *resourceRepository) SetCompleted(ctx context.Context, userID, resourceID uuid.UUID, completedAt *time.Time) error {
+	if completedAt == nil {
+		query := `DELETE FROM resource_completions WHERE user_id = $1 AND resource_id = $2`
+		if _, err := r.db.Pool.Exec(ctx, query, userID, resourceID); err != nil {
+			return fmt.Errorf("failed to uncomplete resource: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO resource_completions (user_id, resource_id, completed_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, resource_id) DO NOTHING`
+
+	if _, err := r.db.Pool.Exec(ctx, query, userID, resourceID, *completedAt); err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrResourceNotFound
+		}
+		return fmt.Errorf("failed to complete resource: %w", err)
+	}
+
+	return nil
+}
+
+func (r *resourceRepository) listCompletions(
+	ctx context.Context,
+	where string,
+	args ...any,
+) ([]*roadmapentity.ResourceCompletion, error) {
+	query := `
+		SELECT c.user_id, c.resource_id, r.roadmap_id, r.node_id, c.completed_at
+		FROM resource_completions c
+		JOIN node_resources r ON r.id = c.resource_id
+		WHERE ` + where + `
+		ORDER BY r.roadmap_id, c.completed_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource completions: %w", err)
+	}
+
+	completions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*roadmapentity.ResourceCompletion, error) {
+		var completion roadmapentity.ResourceCompletion
+		err := row.Scan(
+			&completion.UserID,
+			&completion.ResourceID,
+			&completion.RoadmapID,
+			&completion.NodeID,
+			&completion.CompletedAt,
+		)
+		return &completion, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource completions: %w", err)
+	}
+
+	return completions, nil
+}
+
+func (r *resourceRepository) ListCompletionsByRoadmap(
+	ctx context.Context,
+	userID, roadmapID uuid.UUID,
+) ([]*roadmapentity.ResourceCompletion, error) {
+	return r.listCompletions(ctx, `c.user_id = $1 AND r.roadmap_id = $2`, userID, roadmapID)
+}
+
+func (r *resourceRepository) ListCompletionsByUser(ctx context.Context, userID uuid.UUID) ([]*roadmapentity.ResourceCompletion, error) {
+	return r.listCompletions(ctx, `c.user_id = $1`, userID)
+}
@@ -0,0 +1,222 @@
+package roadmap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	"roadmap/internal/infrastructure/database"
+)
+
+type ResourceRepositoryIntegrationTestSuite struct {
+	suite.Suite
+	repo    *resourceRepository
+	db      *database.Database
+	ctx     context.Context
+	roadmap *roadmapentity.Roadmap
+	node    *roadmapentity.Node
+	other   *roadmapentity.Node
+}
+
+func (s *ResourceRepositoryIntegrationTestSuite) SetupSuite() {
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		s.T().Skip("Skipping integration tests: TEST_DB_DSN not set")
+		return
+	}
+
+	cfg := &database.Config{
+		Host:     "localhost",
+		Port:     "5432",
+		User:     "postgres",
+		Password: "postgres",
+		DBName:   "roadmap_test",
+		SSLMode:  "disable",
+	}
+
+	var err error
+	s.db, err = database.NewDatabase(cfg)
+	require.NoError(s.T(), err, "Failed to connect to test database")
+
+	s.repo = NewResourceRepository(s.db).(*resourceRepository)
+	s.ctx = context.Background()
+}
+
+func (s *ResourceRepositoryIntegrationTestSuite) TearDownSuite() {
+	if s.db != nil {
+		s.db.Close()
+	}
+}
+
+func (s *ResourceRepositoryIntegrationTestSuite) SetupTest() {
+	if s.db == nil {
+		return
+	}
+	s.cleanupTestData()
+
+	ownerID := createTestUser(s.T(), s.ctx, s.db, "test_resource@example.com")
+
+	var err error
+	s.roadmap, err = NewRoadmapRepository(s.db).Create(s.ctx, newTestRoadmap(ownerID, "resources"))
+	require.NoError(s.T(), err)
+
+	s.node, s.other = newTestNode("Node"), newTestNode("Other")
+	_, err = NewGraphRepository(s.db).EditGraph(s.ctx, s.roadmap.ID, func(*roadmapentity.Graph) (*GraphChanges, error) {
+		return &GraphChanges{CreateNodes: []*roadmapentity.Node{s.node, s.other}}, nil
+	})
+	require.NoError(s.T(), err)
+}
+
+func (s *ResourceRepositoryIntegrationTestSuite) TearDownTest() {
+	s.cleanupTestData()
+}
+
+func (s *ResourceRepositoryIntegrationTestSuite) cleanupTestData() {
+	if s.db == nil {
+		return
+	}
+
+	_, err := s.db.Pool.Exec(s.ctx, "DELETE FROM users WHERE email = 'test_resource@example.com'")
+	if err != nil {
+		s.T().Logf("Warning: Failed to cleanup test data: %v", err)
+	}
+}
+
+func (s *ResourceRepositoryIntegrationTestSuite) newResource(node *roadmapentity.Node, url string) *roadmapentity.Resource {
+	return &roadmapentity.Resource{
+		ID:            uuid.New(),
+		RoadmapID:     s.roadmap.ID,
+		NodeID:        node.ID,
+		URL:           "https://" + url,
+		NormalizedURL: url,
+		Title:         url,
+		Kind:          roadmapentity.ResourceKindArticle,
+		IsFree:        true,
+	}
+}
+
+// createResources creates a resource for each URL on the node, in order.
+func (s *ResourceRepositoryIntegrationTestSuite) createResources(
+	node *roadmapentity.Node,
+	urls ...string,
+) []*roadmapentity.Resource {
+	resources := make([]*roadmapentity.Resource, 0, len(urls))
+	for _, url := range urls {
+		resource, err := s.repo.Create(s.ctx, s.newResource(node, url))
+		require.NoError(s.T(), err)
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+// titlesAndPositions lists the resources of the node as "title@position".
+func (s *ResourceRepositoryIntegrationTestSuite) titlesAndPositions(node *roadmapentity.Node) []string {
+	resources, err := s.repo.ListByNode(s.ctx, node.ID)
+	require.NoError(s.T(), err)
+
+	var listed []string
+	for _, resource := range resources {
+		listed = append(listed, fmt.Sprintf("%s@%d", resource.Title, resource.Position))
+	}
+	return listed
+}
+
+func (s *ResourceRepositoryIntegrationTestSuite) TestResourceRepository_CreateAppends() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	created := s.createResources(s.node, "a.example", "b.example", "c.example")
+	assert.Equal(s.T(), 0, created[0].Position)
+	assert.Equal(s.T(), 2, created[2].Position)
+
+	// Positions are counted per node.
+	other := s.createResources(s.other, "d.example")
+	assert.Equal(s.T(), 0, other[0].Position)
+
+	assert.Equal(s.T(), []string{"a.example@0", "b.example@1", "c.example@2"}, s.titlesAndPositions(s.node))
+}
+
+func (s *ResourceRepositoryIntegrationTestSuite) TestResourceRepository_Create_URLTaken() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	s.createResources(s.node, "a.example")
+
+	// The URL is unique within the roadmap, not just the node.
+	_, err := s.repo.Create(s.ctx, s.newResource(s.other, "a.example"))
+	assert.ErrorIs(s.T(), err, ErrResourceURLTaken)
+}
+
+func (s *ResourceRepositoryIntegrationTestSuite) TestResourceRepository_Create_NodeNotFound() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	_, err := s.repo.Create(s.ctx, s.newResource(newTestNode("Missing"), "a.example"))
+	assert.ErrorIs(s.T(), err, ErrNodeNotFound)
+}
+
+func (s *ResourceRepositoryIntegrationTestSuite) TestResourceRepository_Update_URLTaken() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	resources := s.createResources(s.node, "a.example", "b.example")
+
+	resources[1].NormalizedURL = "a.example"
+	_, err := s.repo.Update(s.ctx, resources[1])
+	assert.ErrorIs(s.T(), err, ErrResourceURLTaken)
+
+	_, err = s.repo.Update(s.ctx, s.newResource(s.node, "c.example"))
+	assert.ErrorIs(s.T(), err, ErrResourceNotFound)
+}
+
+func (s *ResourceRepositoryIntegrationTestSuite) TestResourceRepository_DeleteCompactsPositions() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	resources := s.createResources(s.node, "a.example", "b.example", "c.example", "d.example")
+	s.createResources(s.other, "e.example", "f.example")
+
+	err := s.repo.Delete(s.ctx, resources[1].ID)
+	require.NoError(s.T(), err)
+
+	assert.Equal(s.T(), []string{"a.example@0", "c.example@1", "d.example@2"}, s.titlesAndPositions(s.node))
+	assert.Equal(s.T(), []string{"e.example@0", "f.example@1"}, s.titlesAndPositions(s.other))
+
+	// A resource created after the deletion goes to the end without a gap.
+	created := s.createResources(s.node, "g.example")
+	assert.Equal(s.T(), 3, created[0].Position)
+
+	err = s.repo.Delete(s.ctx, resources[1].ID)
+	assert.ErrorIs(s.T(), err, ErrResourceNotFound)
+}
+
+func (s *ResourceRepositoryIntegrationTestSuite) TestResourceRepository_Reorder() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	resources := s.createResources(s.node, "a.example", "b.example", "c.example")
+	s.createResources(s.other, "d.example")
+
+	err := s.repo.Reorder(s.ctx, s.node.ID, []uuid.UUID{resources[2].ID, resources[0].ID, resources[1].ID})
+	require.NoError(s.T(), err)
+
+	assert.Equal(s.T(), []string{"c.example@0", "a.example@1", "b.example@2"}, s.titlesAndPositions(s.node))
+	assert.Equal(s.T(), []string{"d.example@0"}, s.titlesAndPositions(s.other))
+}
+
+func TestResourceRepositoryIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(ResourceRepositoryIntegrationTestSuite))
+}
@@ -0,0 +1,216 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolationCode is the Postgres SQLSTATE for unique_violation.
+const uniqueViolationCode = "23505"
+
+var (
+	ErrRoadmapNotFound = errors.New("roadmap not found")
+	ErrSlugTaken       = errors.New("roadmap slug already in use")
+)
+
+const roadmapColumns = `id, owner_id, title, slug, description, visibility, created_at, updated_at`
+
+type roadmapRepository struct {
+	db *database.Database
+}
+
+func NewRoadmapRepository(db *database.Database) RoadmapRepository {
+	return &roadmapRepository{
+		db: db,
+	}
+}
+
+func scanRoadmap(row pgx.Row) (*roadmapentity.Roadmap, error) {
+	var roadmap roadmapentity.Roadmap
+	err := row.Scan(
+		&roadmap.ID,
+		&roadmap.OwnerID,
+		&roadmap.Title,
+		&roadmap.Slug,
+		&roadmap.Description,
+		&roadmap.Visibility,
+		&roadmap.CreatedAt,
+		&roadmap.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &roadmap, nil
+}
+
+func collectRoadmaps(rows pgx.Rows) ([]*roadmapentity.Roadmap, error) {
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*roadmapentity.Roadmap, error) {
+		return scanRoadmap(row)
+	})
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
+func (r *roadmapRepository) Create(ctx context.Context, roadmap *roadmapentity.Roadmap) (*roadmapentity.Roadmap, error) {
+	return createRoadmap(ctx, r.db.Pool, roadmap)
+}
+
+func createRoadmap(ctx context.Context, q querier, roadmap *roadmapentity.Roadmap) (*roadmapentity.Roadmap, error) {
+	query := `
+		INSERT INTO roadmaps (id, owner_id, title, slug, description, visibility)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + roadmapColumns
+
+	created, err := scanRoadmap(q.QueryRow(ctx, query,
+		roadmap.ID,
+		roadmap.OwnerID,
+		roadmap.Title,
+		roadmap.Slug,
+		roadmap.Description,
+		roadmap.Visibility,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrSlugTaken
+		}
+		return nil, fmt.Errorf("failed to create roadmap: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *roadmapRepository) GetByID(ctx context.Context, id uuid.UUID) (*roadmapentity.Roadmap, error) {
+	query := `SELECT ` + roadmapColumns + ` FROM roadmaps WHERE id = $1`
+
+	roadmap, err := scanRoadmap(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRoadmapNotFound
+		}
+		return nil, fmt.Errorf("failed to get roadmap: %w", err)
+	}
+
+	return roadmap, nil
+}
+
+func (r *roadmapRepository) GetByOwnerAndSlug(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	slug string,
+) (*roadmapentity.Roadmap, error) {
+	query := `SELECT ` + roadmapColumns + ` FROM roadmaps WHERE owner_id = $1 AND slug = $2`
+
+	roadmap, err := scanRoadmap(r.db.Pool.QueryRow(ctx, query, ownerID, slug))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRoadmapNotFound
+		}
+		return nil, fmt.Errorf("failed to get roadmap: %w", err)
+	}
+
+	return roadmap, nil
+}
+
+func (r *roadmapRepository) List(
+	ctx context.Context,
+	filter RoadmapListFilter,
+) ([]*roadmapentity.Roadmap, int, error) {
+	where := ` WHERE (owner_id = $1 OR visibility = '` + roadmapentity.VisibilityPublic + `')`
+	args := []any{filter.ViewerID}
+	if filter.OwnerID != nil {
+		args = append(args, *filter.OwnerID)
+		where += fmt.Sprintf(" AND owner_id = $%d", len(args))
+	}
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM roadmaps`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count roadmaps: %w", err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT `+roadmapColumns+` FROM roadmaps%s ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d`,
+		where, len(args)-1, len(args))
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list roadmaps: %w", err)
+	}
+
+	roadmaps, err := collectRoadmaps(rows)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list roadmaps: %w", err)
+	}
+
+	return roadmaps, total, nil
+}
+
+func (r *roadmapRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*roadmapentity.Roadmap, error) {
+	query := `SELECT ` + roadmapColumns + ` FROM roadmaps WHERE owner_id = $1 ORDER BY created_at, id`
+
+	rows, err := r.db.Pool.Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roadmaps: %w", err)
+	}
+
+	roadmaps, err := collectRoadmaps(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roadmaps: %w", err)
+	}
+
+	return roadmaps, nil
+}
+
+func (r *roadmapRepository) Update(ctx context.Context, roadmap *roadmapentity.Roadmap) (*roadmapentity.Roadmap, error) {
+	return updateRoadmap(ctx, r.db.Pool, roadmap)
+}
+
+func updateRoadmap(ctx context.Context, q querier, roadmap *roadmapentity.Roadmap) (*roadmapentity.Roadmap, error) {
+	query := `
+		UPDATE roadmaps SET title = $2, slug = $3, description = $4, visibility = $5
+		WHERE id = $1
+		RETURNING ` + roadmapColumns
+
+	updated, err := scanRoadmap(q.QueryRow(ctx, query,
+		roadmap.ID,
+		roadmap.Title,
+		roadmap.Slug,
+		roadmap.Description,
+		roadmap.Visibility,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrSlugTaken
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRoadmapNotFound
+		}
+		return nil, fmt.Errorf("failed to update roadmap: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r *roadmapRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM roadmaps WHERE id = $1`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete roadmap: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRoadmapNotFound
+	}
+
+	return nil
+}
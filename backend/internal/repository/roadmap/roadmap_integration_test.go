@@ -0,0 +1,187 @@
+package roadmap
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	"roadmap/internal/infrastructure/database"
+)
+
+type RoadmapRepositoryIntegrationTestSuite struct {
+	suite.Suite
+	repo  *roadmapRepository
+	db    *database.Database
+	ctx   context.Context
+	owner uuid.UUID
+	other uuid.UUID
+}
+
+func (s *RoadmapRepositoryIntegrationTestSuite) SetupSuite() {
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		s.T().Skip("Skipping integration tests: TEST_DB_DSN not set")
+		return
+	}
+
+	cfg := &database.Config{
+		Host:     "localhost",
+		Port:     "5432",
+		User:     "postgres",
+		Password: "postgres",
+		DBName:   "roadmap_test",
+		SSLMode:  "disable",
+	}
+
+	var err error
+	s.db, err = database.NewDatabase(cfg)
+	require.NoError(s.T(), err, "Failed to connect to test database")
+
+	s.repo = NewRoadmapRepository(s.db).(*roadmapRepository)
+	s.ctx = context.Background()
+}
+
+func (s *RoadmapRepositoryIntegrationTestSuite) TearDownSuite() {
+	if s.db != nil {
+		s.db.Close()
+	}
+}
+
+func (s *RoadmapRepositoryIntegrationTestSuite) SetupTest() {
+	if s.db == nil {
+		return
+	}
+	s.cleanupTestData()
+
+	s.owner = createTestUser(s.T(), s.ctx, s.db, "test_roadmap@example.com")
+	s.other = createTestUser(s.T(), s.ctx, s.db, "test_roadmap_other@example.com")
+}
+
+func (s *RoadmapRepositoryIntegrationTestSuite) TearDownTest() {
+	s.cleanupTestData()
+}
+
+func (s *RoadmapRepositoryIntegrationTestSuite) cleanupTestData() {
+	if s.db == nil {
+		return
+	}
+
+	// Deleting the users cascades to their roadmaps.
+	_, err := s.db.Pool.Exec(s.ctx,
+		"DELETE FROM users WHERE email IN ('test_roadmap@example.com', 'test_roadmap_other@example.com')")
+	if err != nil {
+		s.T().Logf("Warning: Failed to cleanup test data: %v", err)
+	}
+}
+
+// createTestUser inserts a user for roadmaps to belong to and returns its ID.
+func createTestUser(t *testing.T, ctx context.Context, db *database.Database, email string) uuid.UUID {
+	id := uuid.New()
+	_, err := db.Pool.Exec(ctx,
+		"INSERT INTO users (id, username, email, password_hash) VALUES ($1, $2, $3, $4)",
+		id, "testuser_roadmap", email, "$2a$10$testhash")
+	require.NoError(t, err)
+	return id
+}
+
+func newTestRoadmap(ownerID uuid.UUID, slug string) *roadmapentity.Roadmap {
+	return &roadmapentity.Roadmap{
+		ID:          uuid.New(),
+		OwnerID:     ownerID,
+		Title:       "Test Roadmap",
+		Slug:        slug,
+		Description: "A roadmap for tests",
+		Visibility:  roadmapentity.VisibilityPrivate,
+	}
+}
+
+func (s *RoadmapRepositoryIntegrationTestSuite) TestRoadmapRepository_CreateAndGetByOwnerAndSlug() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	roadmap := newTestRoadmap(s.owner, "go-basics")
+	created, err := s.repo.Create(s.ctx, roadmap)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), roadmap.ID, created.ID)
+	assert.False(s.T(), created.CreatedAt.IsZero())
+
+	found, err := s.repo.GetByOwnerAndSlug(s.ctx, s.owner, "go-basics")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), roadmap.ID, found.ID)
+	assert.Equal(s.T(), "Test Roadmap", found.Title)
+	assert.Equal(s.T(), roadmapentity.VisibilityPrivate, found.Visibility)
+}
+
+func (s *RoadmapRepositoryIntegrationTestSuite) TestRoadmapRepository_GetByOwnerAndSlug_NotFound() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	_, err := s.repo.Create(s.ctx, newTestRoadmap(s.owner, "go-basics"))
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.GetByOwnerAndSlug(s.ctx, s.owner, "rust-basics")
+	assert.ErrorIs(s.T(), err, ErrRoadmapNotFound)
+
+	_, err = s.repo.GetByOwnerAndSlug(s.ctx, s.other, "go-basics")
+	assert.ErrorIs(s.T(), err, ErrRoadmapNotFound)
+}
+
+func (s *RoadmapRepositoryIntegrationTestSuite) TestRoadmapRepository_Create_SlugTaken() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	_, err := s.repo.Create(s.ctx, newTestRoadmap(s.owner, "go-basics"))
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.Create(s.ctx, newTestRoadmap(s.owner, "go-basics"))
+	assert.ErrorIs(s.T(), err, ErrSlugTaken)
+
+	// Slugs are only unique per owner.
+	_, err = s.repo.Create(s.ctx, newTestRoadmap(s.other, "go-basics"))
+	assert.NoError(s.T(), err)
+}
+
+func (s *RoadmapRepositoryIntegrationTestSuite) TestRoadmapRepository_Update_SlugTaken() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	_, err := s.repo.Create(s.ctx, newTestRoadmap(s.owner, "go-basics"))
+	require.NoError(s.T(), err)
+	roadmap, err := s.repo.Create(s.ctx, newTestRoadmap(s.owner, "rust-basics"))
+	require.NoError(s.T(), err)
+
+	roadmap.Slug = "go-basics"
+	_, err = s.repo.Update(s.ctx, roadmap)
+	assert.ErrorIs(s.T(), err, ErrSlugTaken)
+
+	roadmap.Slug = "rust-advanced"
+	updated, err := s.repo.Update(s.ctx, roadmap)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "rust-advanced", updated.Slug)
+
+	_, err = s.repo.GetByOwnerAndSlug(s.ctx, s.owner, "rust-basics")
+	assert.ErrorIs(s.T(), err, ErrRoadmapNotFound)
+}
+
+func (s *RoadmapRepositoryIntegrationTestSuite) TestRoadmapRepository_Update_NotFound() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	_, err := s.repo.Update(s.ctx, newTestRoadmap(s.owner, "go-basics"))
+	assert.ErrorIs(s.T(), err, ErrRoadmapNotFound)
+}
+
+func TestRoadmapRepositoryIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(RoadmapRepositoryIntegrationTestSuite))
+}
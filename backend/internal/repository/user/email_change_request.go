@@ -0,0 +1,145 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const emailChangeRequestColumns = `id, user_id, old_email, new_email, confirm_token_hash, cancel_token_hash,
+	expires_at, confirmed_at, cancelled_at, created_at`
+
+type emailChangeRequestRepository struct {
+	db *database.Database
+}
+
+func NewEmailChangeRequestRepository(db *database.Database) EmailChangeRequestRepository {
+	return &emailChangeRequestRepository{
+		db: db,
+	}
+}
+
+func scanEmailChangeRequest(row pgx.Row) (*userentity.EmailChangeRequest, error) {
+	var request userentity.EmailChangeRequest
+	err := row.Scan(
+		&request.ID,
+		&request.UserID,
+		&request.OldEmail,
+		&request.NewEmail,
+		&request.ConfirmTokenHash,
+		&request.CancelTokenHash,
+		&request.ExpiresAt,
+		&request.ConfirmedAt,
+		&request.CancelledAt,
+		&request.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *emailChangeRequestRepository) Create(
+	ctx context.Context,
+	request *userentity.EmailChangeRequest,
+) (*userentity.EmailChangeRequest, error) {
+	query := `
+		INSERT INTO email_change_requests
+			(id, user_id, old_email, new_email, confirm_token_hash, cancel_token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + emailChangeRequestColumns
+
+	createdRequest, err := scanEmailChangeRequest(r.db.Pool.QueryRow(ctx, query,
+		request.ID,
+		request.UserID,
+		request.OldEmail,
+		request.NewEmail,
+		request.ConfirmTokenHash,
+		request.CancelTokenHash,
+		request.ExpiresAt,
+		request.CreatedAt,
+	))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email change request: %w", err)
+	}
+
+	return createdRequest, nil
+}
+
+func (r *emailChangeRequestRepository) GetByConfirmHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.EmailChangeRequest, error) {
+	return r.getBy(ctx, "confirm_token_hash", tokenHash)
+}
+
+func (r *emailChangeRequestRepository) GetByCancelHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.EmailChangeRequest, error) {
+	return r.getBy(ctx, "cancel_token_hash", tokenHash)
+}
+
+// getBy looks a request up by one of its token hash columns. column is always
+// a constant from this file, never user input.
+func (r *emailChangeRequestRepository) getBy(
+	ctx context.Context,
+	column string,
+	tokenHash string,
+) (*userentity.EmailChangeRequest, error) {
+	query := `SELECT ` + emailChangeRequestColumns + ` FROM email_change_requests WHERE ` + column + ` = $1`
+
+	request, err := scanEmailChangeRequest(r.db.Pool.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("email change request not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get email change request: %w", err)
+	}
+
+	return request, nil
+}
+
+func (r *emailChangeRequestRepository) MarkConfirmed(ctx context.Context, id uuid.UUID) error {
+	return r.close(ctx, id, "confirmed_at")
+}
+
+func (r *emailChangeRequestRepository) MarkCancelled(ctx context.Context, id uuid.UUID) error {
+	return r.close(ctx, id, "cancelled_at")
+}
+
+func (r *emailChangeRequestRepository) close(ctx context.Context, id uuid.UUID, column string) error {
+	query := `
+		UPDATE email_change_requests SET ` + column + ` = CURRENT_TIMESTAMP
+		WHERE id = $1 AND confirmed_at IS NULL AND cancelled_at IS NULL
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to update email change request: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTokenAlreadyUsed
+	}
+
+	return nil
+}
+
+func (r *emailChangeRequestRepository) CancelPendingForUser(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE email_change_requests SET cancelled_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND confirmed_at IS NULL AND cancelled_at IS NULL
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to cancel pending email change requests: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type emailVerificationTokenRepository struct {
+	db *database.Database
+}
+
+func NewEmailVerificationTokenRepository(db *database.Database) EmailVerificationTokenRepository {
+	return &emailVerificationTokenRepository{
+		db: db,
+	}
+}
+
+func (r *emailVerificationTokenRepository) Create(
+	ctx context.Context,
+	token *userentity.EmailVerificationToken,
+) (*userentity.EmailVerificationToken, error) {
+	query := `
+		INSERT INTO email_verification_tokens (id, user_id, email, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, user_id, email, token_hash, expires_at, used_at, created_at
+	`
+
+	var createdToken userentity.EmailVerificationToken
+	err := r.db.Pool.QueryRow(ctx, query,
+		token.ID,
+		token.UserID,
+		token.Email,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	).Scan(
+		&createdToken.ID,
+		&createdToken.UserID,
+		&createdToken.Email,
+		&createdToken.TokenHash,
+		&createdToken.ExpiresAt,
+		&createdToken.UsedAt,
+		&createdToken.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email verification token: %w", err)
+	}
+
+	return &createdToken, nil
+}
+
+func (r *emailVerificationTokenRepository) GetByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.EmailVerificationToken, error) {
+	query := `
+		SELECT id, user_id, email, token_hash, expires_at, used_at, created_at
+		FROM email_verification_tokens
+		WHERE token_hash = $1
+	`
+
+	var token userentity.EmailVerificationToken
+	err := r.db.Pool.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Email,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("email verification token not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get email verification token by hash: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *emailVerificationTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE email_verification_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = $1 AND used_at IS NULL`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark email verification token as used: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTokenAlreadyUsed
+	}
+
+	return nil
+}
+
+func (r *emailVerificationTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE email_verification_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND used_at IS NULL`
+
+	if _, err := r.db.Pool.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to invalidate email verification tokens: %w", err)
+	}
+
+	return nil
+}
+
+func (r *emailVerificationTokenRepository) CreatedSince(
+	ctx context.Context,
+	userID uuid.UUID,
+	since time.Time,
+) ([]time.Time, error) {
+	query := `
+		SELECT created_at
+		FROM email_verification_tokens
+		WHERE user_id = $1 AND created_at >= $2
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list email verification tokens: %w", err)
+	}
+
+	createdAt, err := pgx.CollectRows(rows, pgx.RowTo[time.Time])
+	if err != nil {
+		return nil, fmt.Errorf("failed to list email verification tokens: %w", err)
+	}
+
+	return createdAt, nil
+}
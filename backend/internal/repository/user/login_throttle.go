@@ -0,0 +1,117 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const loginThrottleColumns = `scope, key, failures, last_failure_at, locked_until`
+
+type loginThrottleRepository struct {
+	db *database.Database
+}
+
+func NewLoginThrottleRepository(db *database.Database) LoginThrottleRepository {
+	return &loginThrottleRepository{
+		db: db,
+	}
+}
+
+func scanLoginThrottle(row pgx.Row) (*userentity.LoginThrottle, error) {
+	var throttle userentity.LoginThrottle
+	err := row.Scan(
+		&throttle.Scope,
+		&throttle.Key,
+		&throttle.Failures,
+		&throttle.LastFailureAt,
+		&throttle.LockedUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *loginThrottleRepository) Get(ctx context.Context, scope, key string) (*userentity.LoginThrottle, error) {
+	query := `SELECT ` + loginThrottleColumns + ` FROM login_throttles WHERE scope = $1 AND key = $2`
+
+	throttle, err := scanLoginThrottle(r.db.Pool.QueryRow(ctx, query, scope, key))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("login throttle not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get login throttle: %w", err)
+	}
+
+	return throttle, nil
+}
+
+func (r *loginThrottleRepository) RecordFailure(
+	ctx context.Context,
+	scope, key string,
+	now, forgetBefore time.Time,
+) (*userentity.LoginThrottle, error) {
+	query := `
+		INSERT INTO login_throttles (scope, key, failures, last_failure_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			failures = CASE
+				WHEN login_throttles.last_failure_at < $4 THEN 1
+				ELSE login_throttles.failures + 1
+			END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING ` + loginThrottleColumns
+
+	throttle, err := scanLoginThrottle(r.db.Pool.QueryRow(ctx, query, scope, key, now, forgetBefore))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	return throttle, nil
+}
+
+func (r *loginThrottleRepository) Lock(ctx context.Context, scope, key string, until time.Time) error {
+	query := `
+		UPDATE login_throttles SET locked_until = GREATEST(COALESCE(locked_until, $3), $3)
+		WHERE scope = $1 AND key = $2`
+
+	tag, err := r.db.Pool.Exec(ctx, query, scope, key, until)
+	if err != nil {
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("login throttle not found: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *loginThrottleRepository) Reset(ctx context.Context, scope, key string) error {
+	query := `DELETE FROM login_throttles WHERE scope = $1 AND key = $2`
+
+	if _, err := r.db.Pool.Exec(ctx, query, scope, key); err != nil {
+		return fmt.Errorf("failed to reset login throttle: %w", err)
+	}
+
+	return nil
+}
+
+func (r *loginThrottleRepository) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM login_throttles
+		WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $1)`
+
+	tag, err := r.db.Pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete stale login throttles: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,112 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrMFAAttemptsExhausted = errors.New("mfa challenge attempts exhausted")
+
+const mfaChallengeColumns = "id, user_id, token_hash, expires_at, attempts, used_at, created_at"
+
+type mfaChallengeRepository struct {
+	db *database.Database
+}
+
+func NewMFAChallengeRepository(db *database.Database) MFAChallengeRepository {
+	return &mfaChallengeRepository{
+		db: db,
+	}
+}
+
+func scanMFAChallenge(row pgx.Row) (*userentity.MFAChallenge, error) {
+	var challenge userentity.MFAChallenge
+	err := row.Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.TokenHash,
+		&challenge.ExpiresAt,
+		&challenge.Attempts,
+		&challenge.UsedAt,
+		&challenge.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *mfaChallengeRepository) Create(
+	ctx context.Context,
+	challenge *userentity.MFAChallenge,
+) (*userentity.MFAChallenge, error) {
+	query := `
+		INSERT INTO mfa_challenges (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + mfaChallengeColumns
+
+	created, err := scanMFAChallenge(r.db.Pool.QueryRow(ctx, query,
+		challenge.ID,
+		challenge.UserID,
+		challenge.TokenHash,
+		challenge.ExpiresAt,
+		challenge.CreatedAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mfa challenge: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *mfaChallengeRepository) GetByHash(ctx context.Context, tokenHash string) (*userentity.MFAChallenge, error) {
+	query := `SELECT ` + mfaChallengeColumns + ` FROM mfa_challenges WHERE token_hash = $1`
+
+	challenge, err := scanMFAChallenge(r.db.Pool.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("mfa challenge not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get mfa challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+func (r *mfaChallengeRepository) ReserveAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) error {
+	query := `
+		UPDATE mfa_challenges SET attempts = attempts + 1
+		WHERE id = $1 AND attempts < $2 AND used_at IS NULL
+		RETURNING attempts`
+
+	var attempts int
+	if err := r.db.Pool.QueryRow(ctx, query, id, maxAttempts).Scan(&attempts); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrMFAAttemptsExhausted
+		}
+		return fmt.Errorf("failed to reserve mfa attempt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *mfaChallengeRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE mfa_challenges SET used_at = CURRENT_TIMESTAMP WHERE id = $1 AND used_at IS NULL`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark mfa challenge used: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTokenAlreadyUsed
+	}
+
+	return nil
+}
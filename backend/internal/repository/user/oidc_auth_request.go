@@ -0,0 +1,101 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const oidcAuthRequestColumns = "id, user_id, provider, state_hash, nonce, code_verifier, expires_at, used_at, created_at"
+
+type oidcAuthRequestRepository struct {
+	db *database.Database
+}
+
+func NewOIDCAuthRequestRepository(db *database.Database) OIDCAuthRequestRepository {
+	return &oidcAuthRequestRepository{
+		db: db,
+	}
+}
+
+func scanOIDCAuthRequest(row pgx.Row) (*userentity.OIDCAuthRequest, error) {
+	var request userentity.OIDCAuthRequest
+	err := row.Scan(
+		&request.ID,
+		&request.UserID,
+		&request.Provider,
+		&request.StateHash,
+		&request.Nonce,
+		&request.CodeVerifier,
+		&request.ExpiresAt,
+		&request.UsedAt,
+		&request.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *oidcAuthRequestRepository) Create(
+	ctx context.Context,
+	request *userentity.OIDCAuthRequest,
+) (*userentity.OIDCAuthRequest, error) {
+	query := `
+		INSERT INTO oidc_auth_requests (id, user_id, provider, state_hash, nonce, code_verifier, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + oidcAuthRequestColumns
+
+	created, err := scanOIDCAuthRequest(r.db.Pool.QueryRow(ctx, query,
+		request.ID,
+		request.UserID,
+		request.Provider,
+		request.StateHash,
+		request.Nonce,
+		request.CodeVerifier,
+		request.ExpiresAt,
+		request.CreatedAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oidc auth request: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *oidcAuthRequestRepository) GetByStateHash(
+	ctx context.Context,
+	stateHash string,
+) (*userentity.OIDCAuthRequest, error) {
+	query := `SELECT ` + oidcAuthRequestColumns + ` FROM oidc_auth_requests WHERE state_hash = $1`
+
+	request, err := scanOIDCAuthRequest(r.db.Pool.QueryRow(ctx, query, stateHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("oidc auth request not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get oidc auth request: %w", err)
+	}
+
+	return request, nil
+}
+
+func (r *oidcAuthRequestRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE oidc_auth_requests SET used_at = CURRENT_TIMESTAMP WHERE id = $1 AND used_at IS NULL`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark oidc auth request used: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTokenAlreadyUsed
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrPasskeyNotFound             = errors.New("passkey not found")
+	ErrCredentialAlreadyRegistered = errors.New("credential already registered")
+)
+
+const passkeyColumns = `id, user_id, credential_id, public_key, sign_count, aaguid, transports,
+	backup_eligible, name, last_used_at, created_at`
+
+type passkeyRepository struct {
+	db *database.Database
+}
+
+func NewPasskeyRepository(db *database.Database) PasskeyRepository {
+	return &passkeyRepository{
+		db: db,
+	}
+}
+
+func scanPasskey(row pgx.Row) (*userentity.Passkey, error) {
+	var passkey userentity.Passkey
+	var signCount int64
+	err := row.Scan(
+		&passkey.ID,
+		&passkey.UserID,
+		&passkey.CredentialID,
+		&passkey.PublicKey,
+		&signCount,
+		&passkey.AAGUID,
+		&passkey.Transports,
+		&passkey.BackupEligible,
+		&passkey.Name,
+		&passkey.LastUsedAt,
+		&passkey.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	passkey.SignCount = uint32(signCount)
+	return &passkey, nil
+}
+
+func (r *passkeyRepository) Create(ctx context.Context, passkey *userentity.Passkey) (*userentity.Passkey, error) {
+	query := `
+		INSERT INTO passkey_credentials
+			(id, user_id, credential_id, public_key, sign_count, aaguid, transports, backup_eligible, name, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING ` + passkeyColumns
+
+	transports := passkey.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+
+	created, err := scanPasskey(r.db.Pool.QueryRow(ctx, query,
+		passkey.ID,
+		passkey.UserID,
+		passkey.CredentialID,
+		passkey.PublicKey,
+		int64(passkey.SignCount),
+		passkey.AAGUID,
+		transports,
+		passkey.BackupEligible,
+		passkey.Name,
+		passkey.CreatedAt,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return nil, ErrCredentialAlreadyRegistered
+		}
+		return nil, fmt.Errorf("failed to create passkey: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *passkeyRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*userentity.Passkey, error) {
+	query := `SELECT ` + passkeyColumns + ` FROM passkey_credentials WHERE credential_id = $1`
+
+	passkey, err := scanPasskey(r.db.Pool.QueryRow(ctx, query, credentialID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("passkey not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get passkey: %w", err)
+	}
+
+	return passkey, nil
+}
+
+func (r *passkeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*userentity.Passkey, error) {
+	query := `SELECT ` + passkeyColumns + ` FROM passkey_credentials WHERE user_id = $1 ORDER BY created_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+
+	passkeys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*userentity.Passkey, error) {
+		return scanPasskey(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+
+	return passkeys, nil
+}
+
+func (r *passkeyRepository) UpdateSignCount(ctx context.Context, id uuid.UUID, signCount uint32, usedAt time.Time) error {
+	query := `UPDATE passkey_credentials SET sign_count = $2, last_used_at = $3 WHERE id = $1`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, int64(signCount), usedAt); err != nil {
+		return fmt.Errorf("failed to update passkey sign count: %w", err)
+	}
+
+	return nil
+}
+
+func (r *passkeyRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	query := `DELETE FROM passkey_credentials WHERE id = $1 AND user_id = $2`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete passkey: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPasskeyNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrTokenAlreadyUsed = errors.New("token already used")
+
+type passwordResetTokenRepository struct {
+	db *database.Database
+}
+
+func NewPasswordResetTokenRepository(db *database.Database) PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{
+		db: db,
+	}
+}
+
+func (r *passwordResetTokenRepository) Create(
+	ctx context.Context,
+	token *userentity.PasswordResetToken,
+) (*userentity.PasswordResetToken, error) {
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, user_id, token_hash, expires_at, used_at, created_at
+	`
+
+	var createdToken userentity.PasswordResetToken
+	err := r.db.Pool.QueryRow(ctx, query,
+		token.ID,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	).Scan(
+		&createdToken.ID,
+		&createdToken.UserID,
+		&createdToken.TokenHash,
+		&createdToken.ExpiresAt,
+		&createdToken.UsedAt,
+		&createdToken.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	return &createdToken, nil
+}
+
+func (r *passwordResetTokenRepository) GetByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.PasswordResetToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM password_reset_tokens
+		WHERE token_hash = $1
+	`
+
+	var token userentity.PasswordResetToken
+	err := r.db.Pool.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("password reset token not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get password reset token by hash: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *passwordResetTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = $1 AND used_at IS NULL`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark password reset token as used: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTokenAlreadyUsed
+	}
+
+	return nil
+}
+
+func (r *passwordResetTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND used_at IS NULL`
+
+	if _, err := r.db.Pool.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to invalidate password reset tokens: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrAccessTokenNotFound  = errors.New("access token not found")
+	ErrAccessTokenNameTaken = errors.New("access token name already in use")
+)
+
+const personalAccessTokenColumns = `id, user_id, name, token_hash, token_prefix, scopes, expires_at, last_used_at, created_at`
+
+type personalAccessTokenRepository struct {
+	db *database.Database
+}
+
+func NewPersonalAccessTokenRepository(db *database.Database) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{
+		db: db,
+	}
+}
+
+func scanPersonalAccessToken(row pgx.Row) (*userentity.PersonalAccessToken, error) {
+	var token userentity.PersonalAccessToken
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.TokenHash,
+		&token.TokenPrefix,
+		&token.Scopes,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *personalAccessTokenRepository) Create(
+	ctx context.Context,
+	token *userentity.PersonalAccessToken,
+) (*userentity.PersonalAccessToken, error) {
+	query := `
+		INSERT INTO personal_access_tokens (id, user_id, name, token_hash, token_prefix, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + personalAccessTokenColumns
+
+	created, err := scanPersonalAccessToken(r.db.Pool.QueryRow(ctx, query,
+		token.ID,
+		token.UserID,
+		token.Name,
+		token.TokenHash,
+		token.TokenPrefix,
+		token.Scopes,
+		token.ExpiresAt,
+		token.CreatedAt,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return nil, ErrAccessTokenNameTaken
+		}
+		return nil, fmt.Errorf("failed to create access token: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *personalAccessTokenRepository) GetByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.PersonalAccessToken, error) {
+	query := `SELECT ` + personalAccessTokenColumns + ` FROM personal_access_tokens WHERE token_hash = $1`
+
+	token, err := scanPersonalAccessToken(r.db.Pool.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("access token not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *personalAccessTokenRepository) GetByID(
+	ctx context.Context,
+	id uuid.UUID,
+	userID uuid.UUID,
+) (*userentity.PersonalAccessToken, error) {
+	query := `SELECT ` + personalAccessTokenColumns + ` FROM personal_access_tokens WHERE id = $1 AND user_id = $2`
+
+	token, err := scanPersonalAccessToken(r.db.Pool.QueryRow(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAccessTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *personalAccessTokenRepository) ListByUser(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]*userentity.PersonalAccessToken, error) {
+	query := `SELECT ` + personalAccessTokenColumns + ` FROM personal_access_tokens WHERE user_id = $1 ORDER BY created_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access tokens: %w", err)
+	}
+
+	tokens, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*userentity.PersonalAccessToken, error) {
+		return scanPersonalAccessToken(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *personalAccessTokenRepository) Update(
+	ctx context.Context,
+	token *userentity.PersonalAccessToken,
+) (*userentity.PersonalAccessToken, error) {
+	query := `
+		UPDATE personal_access_tokens SET name = $3, scopes = $4
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + personalAccessTokenColumns
+
+	updated, err := scanPersonalAccessToken(r.db.Pool.QueryRow(ctx, query,
+		token.ID,
+		token.UserID,
+		token.Name,
+		token.Scopes,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return nil, ErrAccessTokenNameTaken
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAccessTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to update access token: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r *personalAccessTokenRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	query := `UPDATE personal_access_tokens SET last_used_at = $2 WHERE id = $1`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, usedAt); err != nil {
+		return fmt.Errorf("failed to update access token last use: %w", err)
+	}
+
+	return nil
+}
+
+func (r *personalAccessTokenRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	query := `DELETE FROM personal_access_tokens WHERE id = $1 AND user_id = $2`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete access token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAccessTokenNotFound
+	}
+
+	return nil
+}
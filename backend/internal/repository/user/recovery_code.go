@@ -0,0 +1,72 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type recoveryCodeRepository struct {
+	db *database.Database
+}
+
+func NewRecoveryCodeRepository(db *database.Database) RecoveryCodeRepository {
+	return &recoveryCodeRepository{
+		db: db,
+	}
+}
+
+func (r *recoveryCodeRepository) Replace(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			log.Printf("Failed to rollback recovery code replacement: %v", rollbackErr)
+		}
+	}()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	for _, codeHash := range codeHashes {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`,
+			userID, codeHash,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert recovery code: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit recovery code replacement: %w", err)
+	}
+
+	return nil
+}
+
+func (r *recoveryCodeRepository) Consume(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	query := `
+		UPDATE recovery_codes SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTokenAlreadyUsed
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrRefreshTokenRevoked = errors.New("refresh token already revoked")
+
+type refreshTokenRepository struct {
+	db *database.Database
+}
+
+func NewRefreshTokenRepository(db *database.Database) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db: db,
+	}
+}
+
+const refreshTokenColumns = `id, user_id, family_id, token_hash, expires_at, revoked_at, replaced_by, created_at`
+
+func scanRefreshToken(row pgx.Row) (*userentity.RefreshToken, error) {
+	var token userentity.RefreshToken
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.ReplacedBy,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenRepository) Create(
+	ctx context.Context,
+	token *userentity.RefreshToken,
+) (*userentity.RefreshToken, error) {
+	return insertRefreshToken(ctx, r.db.Pool, token)
+}
+
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func insertRefreshToken(
+	ctx context.Context,
+	q queryRower,
+	token *userentity.RefreshToken,
+) (*userentity.RefreshToken, error) {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + refreshTokenColumns
+
+	createdToken, err := scanRefreshToken(q.QueryRow(ctx, query,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return createdToken, nil
+}
+
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*userentity.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = $1`
+
+	token, err := scanRefreshToken(r.db.Pool.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("refresh token not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get refresh token by hash: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *refreshTokenRepository) Rotate(
+	ctx context.Context,
+	oldID uuid.UUID,
+	replacement *userentity.RefreshToken,
+) (*userentity.RefreshToken, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			log.Printf("Failed to rollback refresh token rotation: %v", rollbackErr)
+		}
+	}()
+
+	createdToken, err := insertRefreshToken(ctx, tx, replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP, replaced_by = $2
+		WHERE id = $1 AND revoked_at IS NULL
+	`, oldID, createdToken.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	if tag.RowsAffected() != 1 {
+		return nil, ErrRefreshTokenRevoked
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit refresh token rotation: %w", err)
+	}
+
+	return createdToken, nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE family_id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.db.Pool.Exec(ctx, query, familyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.db.Pool.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens for user: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package user
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+)
+
+type RefreshTokenRepositoryIntegrationTestSuite struct {
+	suite.Suite
+	repo     *refreshTokenRepository
+	userRepo *userRepository
+	db       *database.Database
+	ctx      context.Context
+	user     *userentity.User
+}
+
+func (s *RefreshTokenRepositoryIntegrationTestSuite) SetupSuite() {
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		s.T().Skip("Skipping integration tests: TEST_DB_DSN not set")
+		return
+	}
+
+	cfg := &database.Config{
+		Host:     "localhost",
+		Port:     "5432",
+		User:     "postgres",
+		Password: "postgres",
+		DBName:   "roadmap_test",
+		SSLMode:  "disable",
+	}
+
+	var err error
+	s.db, err = database.NewDatabase(cfg)
+	require.NoError(s.T(), err, "Failed to connect to test database")
+
+	s.repo = NewRefreshTokenRepository(s.db).(*refreshTokenRepository)
+	s.userRepo = NewUserRepository(s.db).(*userRepository)
+	s.ctx = context.Background()
+}
+
+func (s *RefreshTokenRepositoryIntegrationTestSuite) TearDownSuite() {
+	if s.db != nil {
+		s.db.Close()
+	}
+}
+
+func (s *RefreshTokenRepositoryIntegrationTestSuite) SetupTest() {
+	if s.db == nil {
+		return
+	}
+	s.cleanupTestData()
+
+	var err error
+	s.user, err = s.userRepo.Create(s.ctx, &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser_refresh",
+		Email:        "test_refresh@example.com",
+		PasswordHash: "$2a$10$testhash",
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	})
+	require.NoError(s.T(), err)
+}
+
+func (s *RefreshTokenRepositoryIntegrationTestSuite) TearDownTest() {
+	s.cleanupTestData()
+}
+
+func (s *RefreshTokenRepositoryIntegrationTestSuite) cleanupTestData() {
+	if s.db == nil {
+		return
+	}
+
+	_, err := s.db.Pool.Exec(s.ctx, "DELETE FROM users WHERE email = 'test_refresh@example.com'")
+	if err != nil {
+		s.T().Logf("Warning: Failed to cleanup test data: %v", err)
+	}
+}
+
+func (s *RefreshTokenRepositoryIntegrationTestSuite) newToken(familyID uuid.UUID, hash string) *userentity.RefreshToken {
+	return &userentity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    s.user.ID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+}
+
+func (s *RefreshTokenRepositoryIntegrationTestSuite) TestRefreshTokenRepository_CreateAndGetByHash() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	token := s.newToken(uuid.New(), "hash-create")
+	_, err := s.repo.Create(s.ctx, token)
+	require.NoError(s.T(), err)
+
+	found, err := s.repo.GetByHash(s.ctx, "hash-create")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), token.ID, found.ID)
+	assert.Equal(s.T(), token.FamilyID, found.FamilyID)
+	assert.False(s.T(), found.IsRevoked())
+}
+
+func (s *RefreshTokenRepositoryIntegrationTestSuite) TestRefreshTokenRepository_Rotate() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	familyID := uuid.New()
+	current := s.newToken(familyID, "hash-rotate-1")
+	_, err := s.repo.Create(s.ctx, current)
+	require.NoError(s.T(), err)
+
+	replacement := s.newToken(familyID, "hash-rotate-2")
+	_, err = s.repo.Rotate(s.ctx, current.ID, replacement)
+	require.NoError(s.T(), err)
+
+	old, err := s.repo.GetByHash(s.ctx, "hash-rotate-1")
+	require.NoError(s.T(), err)
+	assert.True(s.T(), old.IsRevoked())
+	require.NotNil(s.T(), old.ReplacedBy)
+	assert.Equal(s.T(), replacement.ID, *old.ReplacedBy)
+
+	_, err = s.repo.Rotate(s.ctx, current.ID, s.newToken(familyID, "hash-rotate-3"))
+	assert.ErrorIs(s.T(), err, ErrRefreshTokenRevoked)
+
+	_, err = s.repo.GetByHash(s.ctx, "hash-rotate-3")
+	assert.Error(s.T(), err, "failed rotation must not persist the replacement")
+}
+
+func (s *RefreshTokenRepositoryIntegrationTestSuite) TestRefreshTokenRepository_RevokeFamily() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	familyID := uuid.New()
+	_, err := s.repo.Create(s.ctx, s.newToken(familyID, "hash-family-1"))
+	require.NoError(s.T(), err)
+	_, err = s.repo.Create(s.ctx, s.newToken(uuid.New(), "hash-family-2"))
+	require.NoError(s.T(), err)
+
+	require.NoError(s.T(), s.repo.RevokeFamily(s.ctx, familyID))
+
+	revoked, err := s.repo.GetByHash(s.ctx, "hash-family-1")
+	require.NoError(s.T(), err)
+	assert.True(s.T(), revoked.IsRevoked())
+
+	other, err := s.repo.GetByHash(s.ctx, "hash-family-2")
+	require.NoError(s.T(), err)
+	assert.False(s.T(), other.IsRevoked())
+}
+
+func TestRefreshTokenRepositoryIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenRepositoryIntegrationTestSuite))
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	userentity "roadmap/internal/domain/entities/user"
 
@@ -18,4 +19,291 @@ type UserRepository interface {
 	EmailExists(ctx context.Context, email string) (bool, error)
 
 	UsernameExists(ctx context.Context, username string) (bool, error)
+
+	// UpdateProfile persists the user's username, display name and bio.
+	UpdateProfile(ctx context.Context, user *userentity.User) (*userentity.User, error)
+
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+
+	// UpdateEmail replaces the user's email and marks it verified. It returns
+	// ErrEmailTaken if another account already uses the address.
+	UpdateEmail(ctx context.Context, id uuid.UUID, email string) error
+
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error
+
+	// List returns one page of users matching filter, newest first, and the
+	// number of matching users across all pages.
+	List(ctx context.Context, filter UserListFilter) ([]*userentity.User, int, error)
+
+	IsSuspended(ctx context.Context, id uuid.UUID) (bool, error)
+
+	Suspend(ctx context.Context, id uuid.UUID, reason string, suspendedAt time.Time) error
+
+	Unsuspend(ctx context.Context, id uuid.UUID) error
+
+	// Delete removes the user and, through cascading foreign keys, everything
+	// that belongs to them.
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// SoftDelete marks the user deleted. Deleted users are left out of
+	// GetByID, GetByEmail and List and count as suspended until they are
+	// purged, but keep their email address and username reserved.
+	SoftDelete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error
+
+	// ListDeletedBefore returns up to limit users soft deleted before before,
+	// longest deleted first.
+	ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+}
+
+// UserListFilter narrows UserRepository.List. Email and Username match
+// case-insensitive substrings; zero values do not filter.
+type UserListFilter struct {
+	Email         string
+	Username      string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Limit         int
+	Offset        int
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *userentity.RefreshToken) (*userentity.RefreshToken, error)
+
+	GetByHash(ctx context.Context, tokenHash string) (*userentity.RefreshToken, error)
+
+	// Rotate revokes the token identified by oldID and stores its replacement
+	// in a single transaction. It returns ErrRefreshTokenRevoked if oldID has
+	// already been revoked, e.g. by a concurrent rotation.
+	Rotate(ctx context.Context, oldID uuid.UUID, replacement *userentity.RefreshToken) (*userentity.RefreshToken, error)
+
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type TokenRevocationRepository interface {
+	RevokeToken(ctx context.Context, jti string, userID uuid.UUID, expiresAt time.Time) error
+
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+
+	SetTokensValidAfter(ctx context.Context, userID uuid.UUID, validAfter time.Time) error
+
+	// GetTokensValidAfter returns the zero time if no cutoff has been set.
+	GetTokensValidAfter(ctx context.Context, userID uuid.UUID) (time.Time, error)
+
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *userentity.PasswordResetToken) (*userentity.PasswordResetToken, error)
+
+	GetByHash(ctx context.Context, tokenHash string) (*userentity.PasswordResetToken, error)
+
+	// MarkUsed consumes the token. It returns ErrTokenAlreadyUsed if the token
+	// was consumed before, so a token can only ever be redeemed once.
+	MarkUsed(ctx context.Context, id uuid.UUID) error
+
+	InvalidateForUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type EmailVerificationTokenRepository interface {
+	Create(ctx context.Context, token *userentity.EmailVerificationToken) (*userentity.EmailVerificationToken, error)
+
+	GetByHash(ctx context.Context, tokenHash string) (*userentity.EmailVerificationToken, error)
+
+	// MarkUsed consumes the token. It returns ErrTokenAlreadyUsed if the token
+	// was consumed before.
+	MarkUsed(ctx context.Context, id uuid.UUID) error
+
+	InvalidateForUser(ctx context.Context, userID uuid.UUID) error
+
+	// CreatedSince returns the creation times of the user's tokens issued at or
+	// after since, oldest first. It backs resend throttling.
+	CreatedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error)
+}
+
+type EmailChangeRequestRepository interface {
+	Create(ctx context.Context, request *userentity.EmailChangeRequest) (*userentity.EmailChangeRequest, error)
+
+	GetByConfirmHash(ctx context.Context, tokenHash string) (*userentity.EmailChangeRequest, error)
+
+	GetByCancelHash(ctx context.Context, tokenHash string) (*userentity.EmailChangeRequest, error)
+
+	// MarkConfirmed and MarkCancelled only succeed while the request is still
+	// pending and return ErrTokenAlreadyUsed otherwise.
+	MarkConfirmed(ctx context.Context, id uuid.UUID) error
+
+	MarkCancelled(ctx context.Context, id uuid.UUID) error
+
+	CancelPendingForUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type TOTPRepository interface {
+	// Upsert stores a new pending secret for the user, replacing any earlier
+	// unconfirmed one. It returns ErrTOTPAlreadyConfirmed if the user already
+	// has an active authenticator.
+	Upsert(ctx context.Context, totp *userentity.UserTOTP) (*userentity.UserTOTP, error)
+
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*userentity.UserTOTP, error)
+
+	// Confirm activates the pending secret and records step as used.
+	Confirm(ctx context.Context, userID uuid.UUID, step int64) error
+
+	// RecordUsedStep returns ErrTokenAlreadyUsed unless step is newer than the
+	// last accepted one, so a code cannot be replayed.
+	RecordUsedStep(ctx context.Context, userID uuid.UUID, step int64) error
+}
+
+type RecoveryCodeRepository interface {
+	// Replace discards the user's recovery codes and stores the given hashes
+	// in a single transaction.
+	Replace(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+
+	// Consume marks a code as used. It returns ErrTokenAlreadyUsed if no
+	// unused code with that hash exists.
+	Consume(ctx context.Context, userID uuid.UUID, codeHash string) error
+}
+
+type MFAChallengeRepository interface {
+	Create(ctx context.Context, challenge *userentity.MFAChallenge) (*userentity.MFAChallenge, error)
+
+	GetByHash(ctx context.Context, tokenHash string) (*userentity.MFAChallenge, error)
+
+	// ReserveAttempt atomically counts one verification attempt against the
+	// challenge. It returns ErrMFAAttemptsExhausted if maxAttempts were
+	// already made or the challenge was consumed.
+	ReserveAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) error
+
+	// MarkUsed consumes the challenge. It returns ErrTokenAlreadyUsed if the
+	// challenge was consumed before.
+	MarkUsed(ctx context.Context, id uuid.UUID) error
+}
+
+type PasskeyRepository interface {
+	// Create returns ErrCredentialAlreadyRegistered if the credential ID is
+	// already stored.
+	Create(ctx context.Context, passkey *userentity.Passkey) (*userentity.Passkey, error)
+
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*userentity.Passkey, error)
+
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*userentity.Passkey, error)
+
+	UpdateSignCount(ctx context.Context, id uuid.UUID, signCount uint32, usedAt time.Time) error
+
+	// Delete removes one of the user's passkeys and returns ErrPasskeyNotFound
+	// if the user has no passkey with that ID.
+	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+}
+
+type WebAuthnChallengeRepository interface {
+	Create(ctx context.Context, challenge *userentity.WebAuthnChallenge) (*userentity.WebAuthnChallenge, error)
+
+	GetByHash(ctx context.Context, challengeHash string) (*userentity.WebAuthnChallenge, error)
+
+	// MarkUsed consumes the challenge. It returns ErrTokenAlreadyUsed if the
+	// challenge was consumed before.
+	MarkUsed(ctx context.Context, id uuid.UUID) error
+}
+
+type UserIdentityRepository interface {
+	// Create returns ErrIdentityAlreadyLinked if the external account is
+	// linked to any user, or the user already has an identity at the provider.
+	Create(ctx context.Context, identity *userentity.UserIdentity) (*userentity.UserIdentity, error)
+
+	// CreateWithUser stores a new user together with its first identity in a
+	// single transaction. It returns ErrEmailTaken if the email is in use.
+	CreateWithUser(
+		ctx context.Context,
+		user *userentity.User,
+		identity *userentity.UserIdentity,
+	) (*userentity.User, *userentity.UserIdentity, error)
+
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*userentity.UserIdentity, error)
+
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*userentity.UserIdentity, error)
+
+	UpdateLastLogin(ctx context.Context, id uuid.UUID, lastLoginAt time.Time) error
+}
+
+type OIDCAuthRequestRepository interface {
+	Create(ctx context.Context, request *userentity.OIDCAuthRequest) (*userentity.OIDCAuthRequest, error)
+
+	GetByStateHash(ctx context.Context, stateHash string) (*userentity.OIDCAuthRequest, error)
+
+	// MarkUsed consumes the request. It returns ErrTokenAlreadyUsed if the
+	// request was consumed before.
+	MarkUsed(ctx context.Context, id uuid.UUID) error
+}
+
+type PersonalAccessTokenRepository interface {
+	// Create returns ErrAccessTokenNameTaken if the user already has a token
+	// with that name.
+	Create(ctx context.Context, token *userentity.PersonalAccessToken) (*userentity.PersonalAccessToken, error)
+
+	GetByHash(ctx context.Context, tokenHash string) (*userentity.PersonalAccessToken, error)
+
+	// GetByID returns ErrAccessTokenNotFound unless the token belongs to userID.
+	GetByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*userentity.PersonalAccessToken, error)
+
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*userentity.PersonalAccessToken, error)
+
+	// Update persists the token's name and scopes. It returns
+	// ErrAccessTokenNameTaken if the new name is in use.
+	Update(ctx context.Context, token *userentity.PersonalAccessToken) (*userentity.PersonalAccessToken, error)
+
+	UpdateLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+
+	// Delete returns ErrAccessTokenNotFound unless the token belongs to userID.
+	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+}
+
+type RoleRepository interface {
+	List(ctx context.Context) ([]*userentity.Role, error)
+
+	GetByName(ctx context.Context, name string) (*userentity.Role, error)
+
+	// Grant returns ErrRoleAlreadyGranted if the user already holds the role.
+	Grant(ctx context.Context, userRole *userentity.UserRole) error
+
+	// Revoke returns ErrRoleNotGranted if the user does not hold the role.
+	Revoke(ctx context.Context, userID uuid.UUID, role string) error
+}
+
+type LoginThrottleRepository interface {
+	Get(ctx context.Context, scope, key string) (*userentity.LoginThrottle, error)
+
+	// RecordFailure counts a failed login for key and returns the updated
+	// throttle. Failures recorded before forgetBefore are discarded first.
+	RecordFailure(ctx context.Context, scope, key string, now, forgetBefore time.Time) (*userentity.LoginThrottle, error)
+
+	// Lock refuses attempts for key until the given time. An existing lock
+	// that lasts longer is kept.
+	Lock(ctx context.Context, scope, key string, until time.Time) error
+
+	Reset(ctx context.Context, scope, key string) error
+
+	// DeleteStale removes throttles without failures or locks since before.
+	DeleteStale(ctx context.Context, before time.Time) (int64, error)
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *userentity.Session) (*userentity.Session, error)
+
+	// ListActiveByUser returns the user's sessions that are not revoked and
+	// still hold a refresh token usable at now, most recently seen first.
+	ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]*userentity.Session, error)
+
+	Touch(ctx context.Context, id uuid.UUID, lastSeenAt time.Time) error
+
+	// Revoke marks the session revoked and revokes its refresh tokens in a
+	// single transaction. It returns ErrSessionNotFound unless the session
+	// belongs to userID.
+	Revoke(ctx context.Context, id uuid.UUID, userID uuid.UUID, revokedAt time.Time) error
+
+	// IsRevoked reports false for sessions that do not exist.
+	IsRevoked(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// DeleteExpired removes sessions without a refresh token that is still
+	// valid at before.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
 }
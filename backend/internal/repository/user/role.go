@@ -0,0 +1,111 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrRoleAlreadyGranted = errors.New("role already granted")
+	ErrRoleNotGranted     = errors.New("role not granted")
+)
+
+const roleColumns = `name, description,
+	ARRAY(SELECT permission FROM role_permissions WHERE role_permissions.role = roles.name ORDER BY permission)`
+
+type roleRepository struct {
+	db *database.Database
+}
+
+func NewRoleRepository(db *database.Database) RoleRepository {
+	return &roleRepository{
+		db: db,
+	}
+}
+
+func scanRole(row pgx.Row) (*userentity.Role, error) {
+	var role userentity.Role
+	err := row.Scan(
+		&role.Name,
+		&role.Description,
+		&role.Permissions,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) List(ctx context.Context) ([]*userentity.Role, error) {
+	query := `SELECT ` + roleColumns + ` FROM roles ORDER BY name`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	roles, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*userentity.Role, error) {
+		return scanRole(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) GetByName(ctx context.Context, name string) (*userentity.Role, error) {
+	query := `SELECT ` + roleColumns + ` FROM roles WHERE name = $1`
+
+	role, err := scanRole(r.db.Pool.QueryRow(ctx, query, name))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("role not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (r *roleRepository) Grant(ctx context.Context, userRole *userentity.UserRole) error {
+	query := `INSERT INTO user_roles (user_id, role, granted_by, granted_at) VALUES ($1, $2, $3, $4)`
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		userRole.UserID,
+		userRole.Role,
+		userRole.GrantedBy,
+		userRole.GrantedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return ErrRoleAlreadyGranted
+		}
+		return fmt.Errorf("failed to grant role: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleRepository) Revoke(ctx context.Context, userID uuid.UUID, role string) error {
+	query := `DELETE FROM user_roles WHERE user_id = $1 AND role = $2`
+
+	tag, err := r.db.Pool.Exec(ctx, query, userID, role)
+	if err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRoleNotGranted
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+const sessionColumns = `id, user_id, user_agent, ip_address, created_at, last_seen_at, revoked_at`
+
+type sessionRepository struct {
+	db *database.Database
+}
+
+func NewSessionRepository(db *database.Database) SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+func scanSession(row pgx.Row) (*userentity.Session, error) {
+	var session userentity.Session
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *userentity.Session) (*userentity.Session, error) {
+	query := `
+		INSERT INTO sessions (id, user_id, user_agent, ip_address, created_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + sessionColumns
+
+	created, err := scanSession(r.db.Pool.QueryRow(ctx, query,
+		session.ID,
+		session.UserID,
+		session.UserAgent,
+		session.IPAddress,
+		session.CreatedAt,
+		session.LastSeenAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *sessionRepository) ListActiveByUser(
+	ctx context.Context,
+	userID uuid.UUID,
+	now time.Time,
+) ([]*userentity.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + ` FROM sessions s
+		WHERE user_id = $1 AND revoked_at IS NULL AND EXISTS (
+			SELECT 1 FROM refresh_tokens t
+			WHERE t.family_id = s.id AND t.revoked_at IS NULL AND t.expires_at > $2
+		)
+		ORDER BY last_seen_at DESC`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	sessions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*userentity.Session, error) {
+		return scanSession(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (r *sessionRepository) Touch(ctx context.Context, id uuid.UUID, lastSeenAt time.Time) error {
+	query := `UPDATE sessions SET last_seen_at = $2 WHERE id = $1`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, lastSeenAt); err != nil {
+		return fmt.Errorf("failed to update session last seen: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, id uuid.UUID, userID uuid.UUID, revokedAt time.Time) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			log.Printf("Failed to rollback session revocation: %v", rollbackErr)
+		}
+	}()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE sessions SET revoked_at = COALESCE(revoked_at, $3)
+		WHERE id = $1 AND user_id = $2
+	`, id, userID, revokedAt)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrSessionNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE refresh_tokens SET revoked_at = $2
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`, id, revokedAt); err != nil {
+		return fmt.Errorf("failed to revoke session refresh tokens: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit session revocation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) IsRevoked(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `SELECT revoked_at IS NOT NULL FROM sessions WHERE id = $1`
+
+	var revoked bool
+	if err := r.db.Pool.QueryRow(ctx, query, id).Scan(&revoked); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check session revocation: %w", err)
+	}
+
+	return revoked, nil
+}
+
+func (r *sessionRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM sessions s
+		WHERE NOT EXISTS (
+			SELECT 1 FROM refresh_tokens t
+			WHERE t.family_id = s.id AND t.expires_at > $1
+		)`
+
+	tag, err := r.db.Pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,97 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type tokenRevocationRepository struct {
+	db *database.Database
+}
+
+func NewTokenRevocationRepository(db *database.Database) TokenRevocationRepository {
+	return &tokenRevocationRepository{
+		db: db,
+	}
+}
+
+func (r *tokenRevocationRepository) RevokeToken(
+	ctx context.Context,
+	jti string,
+	userID uuid.UUID,
+	expiresAt time.Time,
+) error {
+	query := `
+		INSERT INTO revoked_access_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, jti, userID, expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *tokenRevocationRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM revoked_access_tokens WHERE jti = $1)`
+
+	var exists bool
+	if err := r.db.Pool.QueryRow(ctx, query, jti).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check access token revocation: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *tokenRevocationRepository) SetTokensValidAfter(
+	ctx context.Context,
+	userID uuid.UUID,
+	validAfter time.Time,
+) error {
+	query := `
+		INSERT INTO user_token_cutoffs (user_id, tokens_valid_after)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET tokens_valid_after = GREATEST(user_token_cutoffs.tokens_valid_after, EXCLUDED.tokens_valid_after)
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, userID, validAfter); err != nil {
+		return fmt.Errorf("failed to set tokens valid after: %w", err)
+	}
+
+	return nil
+}
+
+func (r *tokenRevocationRepository) GetTokensValidAfter(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	query := `SELECT tokens_valid_after FROM user_token_cutoffs WHERE user_id = $1`
+
+	var validAfter time.Time
+	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(&validAfter)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to get tokens valid after: %w", err)
+	}
+
+	return validAfter, nil
+}
+
+func (r *tokenRevocationRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := `DELETE FROM revoked_access_tokens WHERE expires_at < $1`
+
+	tag, err := r.db.Pool.Exec(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired access token revocations: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,107 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrTOTPAlreadyConfirmed = errors.New("totp already confirmed")
+
+const totpColumns = "user_id, secret, confirmed_at, last_used_step, created_at"
+
+type totpRepository struct {
+	db *database.Database
+}
+
+func NewTOTPRepository(db *database.Database) TOTPRepository {
+	return &totpRepository{
+		db: db,
+	}
+}
+
+func scanTOTP(row pgx.Row) (*userentity.UserTOTP, error) {
+	var totp userentity.UserTOTP
+	err := row.Scan(
+		&totp.UserID,
+		&totp.Secret,
+		&totp.ConfirmedAt,
+		&totp.LastUsedStep,
+		&totp.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &totp, nil
+}
+
+func (r *totpRepository) Upsert(ctx context.Context, totp *userentity.UserTOTP) (*userentity.UserTOTP, error) {
+	query := `
+		INSERT INTO user_totp (user_id, secret, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+			SET secret = EXCLUDED.secret, last_used_step = 0, created_at = EXCLUDED.created_at
+			WHERE user_totp.confirmed_at IS NULL
+		RETURNING ` + totpColumns
+
+	stored, err := scanTOTP(r.db.Pool.QueryRow(ctx, query, totp.UserID, totp.Secret, totp.CreatedAt))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTOTPAlreadyConfirmed
+		}
+		return nil, fmt.Errorf("failed to store totp secret: %w", err)
+	}
+
+	return stored, nil
+}
+
+func (r *totpRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*userentity.UserTOTP, error) {
+	query := `SELECT ` + totpColumns + ` FROM user_totp WHERE user_id = $1`
+
+	totp, err := scanTOTP(r.db.Pool.QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("totp not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get totp: %w", err)
+	}
+
+	return totp, nil
+}
+
+func (r *totpRepository) Confirm(ctx context.Context, userID uuid.UUID, step int64) error {
+	query := `
+		UPDATE user_totp SET confirmed_at = CURRENT_TIMESTAMP, last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NULL
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to confirm totp: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTOTPAlreadyConfirmed
+	}
+
+	return nil
+}
+
+func (r *totpRepository) RecordUsedStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	query := `UPDATE user_totp SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2`
+
+	tag, err := r.db.Pool.Exec(ctx, query, userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to record totp step: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTokenAlreadyUsed
+	}
+
+	return nil
+}
@@ -2,15 +2,40 @@ package user
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	userentity "roadmap/internal/domain/entities/user"
 	"roadmap/internal/infrastructure/database"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// uniqueViolationCode is the Postgres SQLSTATE for unique_violation.
+const uniqueViolationCode = "23505"
+
+var ErrEmailTaken = errors.New("email already in use")
+
+// userColumns also selects the user's roles and permissions. The member role
+// is implicit and therefore added here rather than stored in user_roles.
+const userColumns = `id, email, password_hash, username, display_name, bio, email_verified_at,
+	suspended_at, suspension_reason, created_at, updated_at,
+	ARRAY(
+		SELECT '` + userentity.RoleMember + `'
+		UNION SELECT role FROM user_roles WHERE user_roles.user_id = users.id
+		ORDER BY 1
+	),
+	ARRAY(
+		SELECT DISTINCT permission FROM role_permissions
+		WHERE role = '` + userentity.RoleMember + `'
+			OR role IN (SELECT role FROM user_roles WHERE user_roles.user_id = users.id)
+		ORDER BY 1
+	)`
+
 type userRepository struct {
 	db *database.Database
 }
@@ -21,53 +46,67 @@ func NewUserRepository(db *database.Database) UserRepository {
 	}
 }
 
-func (r *userRepository) Create(ctx context.Context, user *userentity.User) (*userentity.User, error) {
+func scanUser(row pgx.Row) (*userentity.User, error) {
+	var user userentity.User
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Username,
+		&user.DisplayName,
+		&user.Bio,
+		&user.EmailVerifiedAt,
+		&user.SuspendedAt,
+		&user.SuspensionReason,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.Roles,
+		&user.Permissions,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// rowQuerier is satisfied by both the pool and a transaction.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func insertUser(ctx context.Context, q rowQuerier, user *userentity.User) (*userentity.User, error) {
 	query := `
-		INSERT INTO users (id, email, password_hash, username, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, email, password_hash, username, created_at, updated_at
-	`
+		INSERT INTO users (id, email, password_hash, username, display_name, bio, email_verified_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING ` + userColumns
 
-	var createdUser userentity.User
-	err := r.db.Pool.QueryRow(ctx, query,
+	return scanUser(q.QueryRow(ctx, query,
 		user.ID,
 		user.Email,
 		user.PasswordHash,
 		user.Username,
+		user.DisplayName,
+		user.Bio,
+		user.EmailVerifiedAt,
 		user.CreatedAt,
 		user.UpdatedAt,
-	).Scan(
-		&createdUser.ID,
-		&createdUser.Email,
-		&createdUser.PasswordHash,
-		&createdUser.Username,
-		&createdUser.CreatedAt,
-		&createdUser.UpdatedAt,
-	)
+	))
+}
+
+func (r *userRepository) Create(ctx context.Context, user *userentity.User) (*userentity.User, error) {
+	createdUser, err := insertUser(ctx, r.db.Pool, user)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	return &createdUser, nil
+	return createdUser, nil
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*userentity.User, error) {
-	query := `
-		SELECT id, email, password_hash, username, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND deleted_at IS NULL`
 
-	var user userentity.User
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.Username,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	user, err := scanUser(r.db.Pool.QueryRow(ctx, query, id))
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -76,25 +115,13 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*userentity
 		return nil, fmt.Errorf("failed to get user by id: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*userentity.User, error) {
-	query := `
-		SELECT id, email, password_hash, username, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1 AND deleted_at IS NULL`
 
-	var user userentity.User
-	err := r.db.Pool.QueryRow(ctx, query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.Username,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	user, err := scanUser(r.db.Pool.QueryRow(ctx, query, email))
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -103,7 +130,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*userent
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 func (r *userRepository) EmailExists(ctx context.Context, email string) (bool, error) {
@@ -129,3 +156,208 @@ func (r *userRepository) UsernameExists(ctx context.Context, username string) (b
 
 	return exists, nil
 }
+
+func (r *userRepository) UpdateProfile(ctx context.Context, user *userentity.User) (*userentity.User, error) {
+	query := `
+		UPDATE users SET username = $2, display_name = $3, bio = $4
+		WHERE id = $1
+		RETURNING ` + userColumns
+
+	updatedUser, err := scanUser(r.db.Pool.QueryRow(ctx, query,
+		user.ID,
+		user.Username,
+		user.DisplayName,
+		user.Bio,
+	))
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("user not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return updatedUser, nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $2 WHERE id = $1`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id, passwordHash)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user not found: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	query := `UPDATE users SET email = $2, email_verified_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id, email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return ErrEmailTaken
+		}
+		return fmt.Errorf("failed to update email: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user not found: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	query := `UPDATE users SET email_verified_at = $2 WHERE id = $1 AND email_verified_at IS NULL`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, verifiedAt); err != nil {
+		return fmt.Errorf("failed to mark email as verified: %w", err)
+	}
+
+	return nil
+}
+
+// likePattern escapes LIKE wildcards in s and wraps it for a substring match.
+func likePattern(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(s) + "%"
+}
+
+func (r *userRepository) List(ctx context.Context, filter UserListFilter) ([]*userentity.User, int, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []any
+
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Email != "" {
+		addCondition("email ILIKE $%d", likePattern(filter.Email))
+	}
+	if filter.Username != "" {
+		addCondition("username ILIKE $%d", likePattern(filter.Username))
+	}
+	if filter.CreatedAfter != nil {
+		addCondition("created_at >= $%d", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		addCondition("created_at < $%d", *filter.CreatedBefore)
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM users`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT `+userColumns+` FROM users%s ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d`,
+		where, len(args)-1, len(args))
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*userentity.User, error) {
+		return scanUser(row)
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+func (r *userRepository) IsSuspended(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `SELECT suspended_at IS NOT NULL OR deleted_at IS NOT NULL FROM users WHERE id = $1`
+
+	var suspended bool
+	if err := r.db.Pool.QueryRow(ctx, query, id).Scan(&suspended); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, fmt.Errorf("user not found: %w", err)
+		}
+		return false, fmt.Errorf("failed to check suspension: %w", err)
+	}
+
+	return suspended, nil
+}
+
+func (r *userRepository) Suspend(ctx context.Context, id uuid.UUID, reason string, suspendedAt time.Time) error {
+	query := `UPDATE users SET suspended_at = $2, suspension_reason = $3 WHERE id = $1`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id, suspendedAt, reason)
+	if err != nil {
+		return fmt.Errorf("failed to suspend user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user not found: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *userRepository) Unsuspend(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE users SET suspended_at = NULL, suspension_reason = '' WHERE id = $1`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to unsuspend user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user not found: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM users WHERE id = $1`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user not found: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *userRepository) SoftDelete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
+	query := `UPDATE users SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id, deletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to soft delete user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user not found: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *userRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	query := `SELECT id FROM users WHERE deleted_at < $1 ORDER BY deleted_at LIMIT $2`
+
+	rows, err := r.db.Pool.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted users: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted users: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,165 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var ErrIdentityAlreadyLinked = errors.New("identity already linked")
+
+const userIdentityColumns = "id, user_id, provider, subject, email, last_login_at, created_at"
+
+type userIdentityRepository struct {
+	db *database.Database
+}
+
+func NewUserIdentityRepository(db *database.Database) UserIdentityRepository {
+	return &userIdentityRepository{
+		db: db,
+	}
+}
+
+func scanUserIdentity(row pgx.Row) (*userentity.UserIdentity, error) {
+	var identity userentity.UserIdentity
+	err := row.Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.LastLoginAt,
+		&identity.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func insertUserIdentity(ctx context.Context, q rowQuerier, identity *userentity.UserIdentity) (*userentity.UserIdentity, error) {
+	query := `
+		INSERT INTO user_identities (id, user_id, provider, subject, email, last_login_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + userIdentityColumns
+
+	return scanUserIdentity(q.QueryRow(ctx, query,
+		identity.ID,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+		identity.LastLoginAt,
+		identity.CreatedAt,
+	))
+}
+
+func (r *userIdentityRepository) Create(
+	ctx context.Context,
+	identity *userentity.UserIdentity,
+) (*userentity.UserIdentity, error) {
+	created, err := insertUserIdentity(ctx, r.db.Pool, identity)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return nil, ErrIdentityAlreadyLinked
+		}
+		return nil, fmt.Errorf("failed to create user identity: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *userIdentityRepository) CreateWithUser(
+	ctx context.Context,
+	user *userentity.User,
+	identity *userentity.UserIdentity,
+) (*userentity.User, *userentity.UserIdentity, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			log.Printf("Failed to rollback user identity creation: %v", rollbackErr)
+		}
+	}()
+
+	createdUser, err := insertUser(ctx, tx, user)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return nil, nil, ErrEmailTaken
+		}
+		return nil, nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	createdIdentity, err := insertUserIdentity(ctx, tx, identity)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return nil, nil, ErrIdentityAlreadyLinked
+		}
+		return nil, nil, fmt.Errorf("failed to create user identity: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit user identity creation: %w", err)
+	}
+
+	return createdUser, createdIdentity, nil
+}
+
+func (r *userIdentityRepository) GetByProviderSubject(
+	ctx context.Context,
+	provider, subject string,
+) (*userentity.UserIdentity, error) {
+	query := `SELECT ` + userIdentityColumns + ` FROM user_identities WHERE provider = $1 AND subject = $2`
+
+	identity, err := scanUserIdentity(r.db.Pool.QueryRow(ctx, query, provider, subject))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("user identity not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get user identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+func (r *userIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*userentity.UserIdentity, error) {
+	query := `SELECT ` + userIdentityColumns + ` FROM user_identities WHERE user_id = $1 ORDER BY created_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user identities: %w", err)
+	}
+
+	identities, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*userentity.UserIdentity, error) {
+		return scanUserIdentity(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user identities: %w", err)
+	}
+
+	return identities, nil
+}
+
+func (r *userIdentityRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, lastLoginAt time.Time) error {
+	query := `UPDATE user_identities SET last_login_at = $2 WHERE id = $1`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, lastLoginAt); err != nil {
+		return fmt.Errorf("failed to update user identity last login: %w", err)
+	}
+
+	return nil
+}
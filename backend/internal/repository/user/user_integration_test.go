@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -115,11 +116,7 @@ func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_Create_Duplicate
 	user2 := &userentity.User{
 		ID:           uuid.New(),
 		Username:     "testuser2",
-<<<<<<< HEAD
 		Email:        "duplicate@example.com",
-=======
-		Email:        "duplicate@example.com", 
->>>>>>> 1962dcb (feat: register + login + tests)
 		PasswordHash: "$2a$10$testhash2",
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
@@ -275,6 +272,66 @@ func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_UsernameExists_F
 	assert.False(s.T(), exists)
 }
 
+func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_List_Filters() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	for i, name := range []string{"testuser_list_a", "testuser_list_b", "testuser_other"} {
+		_, err := s.repo.Create(s.ctx, &userentity.User{
+			ID:           uuid.New(),
+			Username:     name,
+			Email:        "test_" + name + "@example.com",
+			PasswordHash: "$2a$10$testhash",
+			CreatedAt:    time.Now().Add(time.Duration(i) * time.Second),
+			UpdatedAt:    time.Now(),
+		})
+		require.NoError(s.T(), err)
+	}
+
+	users, total, err := s.repo.List(s.ctx, UserListFilter{Username: "TESTUSER_LIST", Limit: 1})
+
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, total)
+	require.Len(s.T(), users, 1)
+	assert.Contains(s.T(), []string{"testuser_list_a", "testuser_list_b"}, users[0].Username)
+
+	users, _, err = s.repo.List(s.ctx, UserListFilter{Username: "testuser%other", Limit: 10})
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), users, "wildcards in the filter must match literally")
+}
+
+func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_Suspend() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	user, err := s.repo.Create(s.ctx, &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser_suspend",
+		Email:        "test_suspend@example.com",
+		PasswordHash: "$2a$10$testhash",
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	})
+	require.NoError(s.T(), err)
+
+	require.NoError(s.T(), s.repo.Suspend(s.ctx, user.ID, "spam", time.Now()))
+	suspended, err := s.repo.IsSuspended(s.ctx, user.ID)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), suspended)
+
+	require.NoError(s.T(), s.repo.Unsuspend(s.ctx, user.ID))
+	loaded, err := s.repo.GetByID(s.ctx, user.ID)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), loaded.IsSuspended())
+	assert.Empty(s.T(), loaded.SuspensionReason)
+
+	require.NoError(s.T(), s.repo.Delete(s.ctx, user.ID))
+	_, err = s.repo.IsSuspended(s.ctx, user.ID)
+	assert.ErrorIs(s.T(), err, pgx.ErrNoRows)
+}
+
 func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_ConcurrentAccess() {
 	if s.db == nil {
 		s.T().Skip("Database not available")
@@ -336,11 +393,7 @@ func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_Create_ErrorHand
 				return &userentity.User{
 					ID:           uuid.New(),
 					Username:     "uniqueuser2",
-<<<<<<< HEAD
 					Email:        "duplicate_email@example.com",
-=======
-					Email:        "duplicate_email@example.com", 
->>>>>>> 1962dcb (feat: register + login + tests)
 					PasswordHash: "$2a$10$testhash2",
 					CreatedAt:    time.Now(),
 					UpdatedAt:    time.Now(),
@@ -368,11 +421,7 @@ func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_Create_ErrorHand
 
 				return &userentity.User{
 					ID:           uuid.New(),
-<<<<<<< HEAD
 					Username:     "duplicate_username",
-=======
-					Username:     "duplicate_username", 
->>>>>>> 1962dcb (feat: register + login + tests)
 					Email:        "unique2@example.com",
 					PasswordHash: "$2a$10$testhash2",
 					CreatedAt:    time.Now(),
@@ -439,11 +488,7 @@ func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_GetByID_ErrorHan
 		{
 			name: "non-existent ID returns not found error",
 			setupID: func() uuid.UUID {
-<<<<<<< HEAD
 				return uuid.New()
-=======
-				return uuid.New() 
->>>>>>> 1962dcb (feat: register + login + tests)
 			},
 			expectError: true,
 			errorCheck: func(t *testing.T, err error) {
@@ -555,11 +600,7 @@ func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_EmailExists_Edge
 			name:      "case sensitive email check",
 			email:     "Test@Example.com",
 			setupUser: true,
-<<<<<<< HEAD
 			expected:  false,
-=======
-			expected:  false, 
->>>>>>> 1962dcb (feat: register + login + tests)
 		},
 		{
 			name:      "email with special characters",
@@ -618,11 +659,7 @@ func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_UsernameExists_E
 			name:      "case sensitive username check",
 			username:  "TestUser",
 			setupUser: true,
-<<<<<<< HEAD
 			expected:  false,
-=======
-			expected:  false, 
->>>>>>> 1962dcb (feat: register + login + tests)
 		},
 		{
 			name:      "username with numbers",
@@ -749,6 +786,60 @@ func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_TransactionIsola
 	assert.Equal(s.T(), created2.ID, retrieved2.ID)
 }
 
+func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_UpdateProfile() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	createdUser, err := s.repo.Create(s.ctx, &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser_profile",
+		Email:        "test_profile@example.com",
+		PasswordHash: "$2a$10$testhash",
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	})
+	require.NoError(s.T(), err)
+
+	createdUser.Username = "testuser_renamed"
+	createdUser.DisplayName = "Test User"
+	createdUser.Bio = "Learning Go"
+
+	updatedUser, err := s.repo.UpdateProfile(s.ctx, createdUser)
+
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "testuser_renamed", updatedUser.Username)
+	assert.Equal(s.T(), "Test User", updatedUser.DisplayName)
+	assert.Equal(s.T(), "Learning Go", updatedUser.Bio)
+	assert.Equal(s.T(), createdUser.Email, updatedUser.Email)
+}
+
+func (s *UserRepositoryIntegrationTestSuite) TestUserRepository_UpdatePassword() {
+	if s.db == nil {
+		s.T().Skip("Database not available")
+	}
+
+	createdUser, err := s.repo.Create(s.ctx, &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser_password",
+		Email:        "test_password@example.com",
+		PasswordHash: "$2a$10$oldhash",
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	})
+	require.NoError(s.T(), err)
+
+	err = s.repo.UpdatePassword(s.ctx, createdUser.ID, "$2a$10$newhash")
+	require.NoError(s.T(), err)
+
+	retrievedUser, err := s.repo.GetByID(s.ctx, createdUser.ID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "$2a$10$newhash", retrievedUser.PasswordHash)
+
+	err = s.repo.UpdatePassword(s.ctx, uuid.New(), "$2a$10$newhash")
+	assert.Error(s.T(), err)
+}
+
 func TestUserRepositoryIntegrationTestSuite(t *testing.T) {
 	suite.Run(t, new(UserRepositoryIntegrationTestSuite))
 }
@@ -227,6 +227,11 @@ func TestUserRepository_ScanOrder(t *testing.T) {
 	assert.Equal(t, "updated_at", expectedOrder[5])
 }
 
+func TestLikePattern(t *testing.T) {
+	assert.Equal(t, "%example%", likePattern("example"))
+	assert.Equal(t, `%100\%\_off\\%`, likePattern(`100%_off\`))
+}
+
 func TestUserRepository_ErrorMessages(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,97 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const webAuthnChallengeColumns = "id, user_id, challenge_hash, ceremony, expires_at, used_at, created_at"
+
+type webAuthnChallengeRepository struct {
+	db *database.Database
+}
+
+func NewWebAuthnChallengeRepository(db *database.Database) WebAuthnChallengeRepository {
+	return &webAuthnChallengeRepository{
+		db: db,
+	}
+}
+
+func scanWebAuthnChallenge(row pgx.Row) (*userentity.WebAuthnChallenge, error) {
+	var challenge userentity.WebAuthnChallenge
+	err := row.Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.ChallengeHash,
+		&challenge.Ceremony,
+		&challenge.ExpiresAt,
+		&challenge.UsedAt,
+		&challenge.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *webAuthnChallengeRepository) Create(
+	ctx context.Context,
+	challenge *userentity.WebAuthnChallenge,
+) (*userentity.WebAuthnChallenge, error) {
+	query := `
+		INSERT INTO webauthn_challenges (id, user_id, challenge_hash, ceremony, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + webAuthnChallengeColumns
+
+	created, err := scanWebAuthnChallenge(r.db.Pool.QueryRow(ctx, query,
+		challenge.ID,
+		challenge.UserID,
+		challenge.ChallengeHash,
+		challenge.Ceremony,
+		challenge.ExpiresAt,
+		challenge.CreatedAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webauthn challenge: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *webAuthnChallengeRepository) GetByHash(
+	ctx context.Context,
+	challengeHash string,
+) (*userentity.WebAuthnChallenge, error) {
+	query := `SELECT ` + webAuthnChallengeColumns + ` FROM webauthn_challenges WHERE challenge_hash = $1`
+
+	challenge, err := scanWebAuthnChallenge(r.db.Pool.QueryRow(ctx, query, challengeHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("webauthn challenge not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get webauthn challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+func (r *webAuthnChallengeRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE webauthn_challenges SET used_at = CURRENT_TIMESTAMP WHERE id = $1 AND used_at IS NULL`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark webauthn challenge used: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTokenAlreadyUsed
+	}
+
+	return nil
+}
@@ -0,0 +1,630 @@
+package roadmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+)
+
+// Formats of roadmap documents.
+const (
+	DocumentFormatJSON = "json"
+	DocumentFormatYAML = "yaml"
+)
+
+const (
+	// maxDocumentProblems caps the problems reported for one document; the
+	// rest are not recorded.
+	maxDocumentProblems = 100
+	// maxNodeKeyLength matches the roadmap_nodes.key column.
+	maxNodeKeyLength     = 100
+	maxTitleLength       = 200
+	maxDescriptionLength = 5000
+	maxURLLength         = 2048
+	maxLanguageLength    = 35
+	maxEstimatedMinutes  = 100000
+)
+
+var (
+	nodeKeyPattern   = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	yamlErrorPattern = regexp.MustCompile(`^yaml: line (\d+): (.+)$`)
+)
+
+// parseDocumentTree parses data into the yaml.v3 node tree of its top-level
+// value. JSON is not read with the YAML parser, which rejects some valid
+// JSON, but converted into the same tree.
+func parseDocumentTree(data []byte, format string) (*yaml.Node, error) {
+	if format == DocumentFormatJSON {
+		return parseJSONTree(data)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		problem := roadmapdto.ImportProblem{Line: 1, Message: err.Error()}
+		if match := yamlErrorPattern.FindStringSubmatch(err.Error()); match != nil {
+			problem.Line, _ = strconv.Atoi(match[1])
+			problem.Message = match[2]
+		}
+		return nil, &DocumentError{Problems: []roadmapdto.ImportProblem{problem}}
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return nil, &DocumentError{Problems: []roadmapdto.ImportProblem{{Line: 1, Message: "document is empty"}}}
+	}
+	if problems := aliasProblems(root.Content[0]); len(problems) > 0 {
+		return nil, &DocumentError{Problems: problems}
+	}
+	return root.Content[0], nil
+}
+
+// aliasProblems reports the aliases in the tree. They are rejected rather
+// than resolved, since a few nested aliases can make a small document expand
+// into billions of nodes. The tree itself holds every alias once.
+func aliasProblems(node *yaml.Node) []roadmapdto.ImportProblem {
+	var problems []roadmapdto.ImportProblem
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if len(problems) >= maxDocumentProblems {
+			return
+		}
+		if node.Kind == yaml.AliasNode {
+			problems = append(problems, roadmapdto.ImportProblem{
+				Line:    node.Line,
+				Column:  node.Column,
+				Message: "aliases are not supported",
+			})
+			return
+		}
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+	walk(node)
+	return problems
+}
+
+// jsonTree builds a yaml.v3 node tree from the tokens of a JSON document,
+// positioning every node at the first byte of its token.
+type jsonTree struct {
+	data    []byte
+	decoder *json.Decoder
+	// lineStarts holds the offset of the first byte of every line.
+	lineStarts []int
+}
+
+func parseJSONTree(data []byte) (*yaml.Node, error) {
+	t := &jsonTree{
+		data:       data,
+		decoder:    json.NewDecoder(bytes.NewReader(data)),
+		lineStarts: []int{0},
+	}
+	t.decoder.UseNumber()
+	for i, b := range data {
+		if b == '\n' {
+			t.lineStarts = append(t.lineStarts, i+1)
+		}
+	}
+
+	node, err := t.value()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(bytes.TrimSpace(data)) == 0 {
+			return nil, &DocumentError{Problems: []roadmapdto.ImportProblem{{Line: 1, Message: "document is empty"}}}
+		}
+		return nil, t.syntaxError(err)
+	}
+
+	offset := t.next()
+	if _, err := t.decoder.Token(); !errors.Is(err, io.EOF) {
+		line, column := t.position(offset)
+		return nil, &DocumentError{Problems: []roadmapdto.ImportProblem{{
+			Line:    line,
+			Column:  column,
+			Message: "unexpected data after the document",
+		}}}
+	}
+
+	return node, nil
+}
+
+// next returns the offset of the next token.
+func (t *jsonTree) next() int {
+	offset := int(t.decoder.InputOffset())
+	for offset < len(t.data) && strings.IndexByte(" \t\r\n,:", t.data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+func (t *jsonTree) position(offset int) (int, int) {
+	line := sort.Search(len(t.lineStarts), func(i int) bool { return t.lineStarts[i] > offset })
+	return line, offset - t.lineStarts[line-1] + 1
+}
+
+func (t *jsonTree) syntaxError(err error) error {
+	problem := roadmapdto.ImportProblem{Message: err.Error()}
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &syntaxErr) && int(syntaxErr.Offset) < len(t.data):
+		problem.Line, problem.Column = t.position(max(int(syntaxErr.Offset)-1, 0))
+	case syntaxErr != nil, errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		problem.Line, problem.Column = t.position(len(t.data))
+		problem.Message = "unexpected end of document"
+	default:
+		problem.Line, problem.Column = t.position(t.next())
+	}
+	return &DocumentError{Problems: []roadmapdto.ImportProblem{problem}}
+}
+
+func (t *jsonTree) value() (*yaml.Node, error) {
+	line, column := t.position(t.next())
+	token, err := t.decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &yaml.Node{Kind: yaml.ScalarNode, Line: line, Column: column}
+	switch token := token.(type) {
+	case json.Delim:
+		node.Kind, node.Tag = yaml.SequenceNode, "!!seq"
+		if token == '{' {
+			node.Kind, node.Tag = yaml.MappingNode, "!!map"
+		}
+		for t.decoder.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := t.value()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, key)
+			}
+			item, err := t.value()
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, item)
+		}
+		// The closing delimiter.
+		if _, err := t.decoder.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		node.Tag, node.Value = "!!str", token
+	case json.Number:
+		node.Tag, node.Value = "!!int", token.String()
+		if strings.ContainsAny(node.Value, ".eE") {
+			node.Tag = "!!float"
+		}
+	case bool:
+		node.Tag, node.Value = "!!bool", strconv.FormatBool(token)
+	case nil:
+		node.Tag, node.Value = "!!null", "null"
+	}
+	return node, nil
+}
+
+// documentParser reads a roadmap document from its node tree, collecting
+// every problem instead of stopping at the first.
+type documentParser struct {
+	problems []roadmapdto.ImportProblem
+	// nodeKeys and resourceURLs hold where each node key and normalized
+	// resource URL was first used.
+	nodeKeys     map[string]*yaml.Node
+	resourceURLs map[string]*yaml.Node
+}
+
+// edgeSource is where an edge of the document was read from.
+type edgeSource struct {
+	path         string
+	item         *yaml.Node
+	prerequisite *yaml.Node
+	node         *yaml.Node
+}
+
+type fieldHandlers map[string]func(node *yaml.Node, path string)
+
+// decodeRoadmapDocument parses data and checks it against the document
+// schema. All problems found are returned in one *DocumentError.
+func decodeRoadmapDocument(data []byte, format string) (*roadmapdto.RoadmapDocument, error) {
+	root, err := parseDocumentTree(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &documentParser{
+		nodeKeys:     make(map[string]*yaml.Node),
+		resourceURLs: make(map[string]*yaml.Node),
+	}
+
+	var doc roadmapdto.RoadmapDocument
+	var edges []edgeSource
+	p.fields(root, "", fieldHandlers{
+		"schema_version": func(node *yaml.Node, path string) {
+			version, ok := p.integer(node, path)
+			if ok && version != roadmapdto.RoadmapSchemaVersion {
+				p.add(node, path, "unsupported schema version %d, expected %d", version, roadmapdto.RoadmapSchemaVersion)
+			}
+			doc.SchemaVersion = version
+		},
+		"roadmap": func(node *yaml.Node, path string) {
+			doc.Roadmap = p.roadmap(node, path)
+		},
+		"nodes": func(node *yaml.Node, path string) {
+			doc.Nodes = p.nodes(node, path)
+		},
+		"edges": func(node *yaml.Node, path string) {
+			doc.Edges, edges = p.edges(node, path)
+		},
+	}, "schema_version", "roadmap")
+	p.checkEdges(doc.Edges, edges)
+
+	if len(p.problems) > 0 {
+		sort.SliceStable(p.problems, func(i, j int) bool {
+			a, b := p.problems[i], p.problems[j]
+			return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+		})
+		return nil, &DocumentError{Problems: p.problems}
+	}
+	return &doc, nil
+}
+
+func (p *documentParser) add(node *yaml.Node, path, format string, args ...any) {
+	if len(p.problems) >= maxDocumentProblems {
+		return
+	}
+	p.problems = append(p.problems, roadmapdto.ImportProblem{
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func fieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// fields calls the handler of every key of node, which must be a mapping,
+// and reports unknown, duplicate and missing keys.
+func (p *documentParser) fields(node *yaml.Node, path string, handlers fieldHandlers, required ...string) {
+	if node.Kind != yaml.MappingNode {
+		p.add(node, path, "must be an object")
+		return
+	}
+
+	seen := make(map[string]bool, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		keyPath := fieldPath(path, key.Value)
+		if seen[key.Value] {
+			p.add(key, keyPath, "duplicate field")
+			continue
+		}
+		seen[key.Value] = true
+
+		handler, ok := handlers[key.Value]
+		if !ok {
+			p.add(key, keyPath, "unknown field")
+			continue
+		}
+		handler(value, keyPath)
+	}
+
+	for _, name := range required {
+		if !seen[name] {
+			p.add(node, fieldPath(path, name), "missing required field")
+		}
+	}
+}
+
+func (p *documentParser) sequence(node *yaml.Node, path string) []*yaml.Node {
+	if node.Kind != yaml.SequenceNode {
+		p.add(node, path, "must be a list")
+		return nil
+	}
+	return node.Content
+}
+
+// text reads a string of at most maxLength characters, with surrounding
+// whitespace removed.
+func (p *documentParser) text(node *yaml.Node, path string, maxLength int, required bool) string {
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!str" {
+		p.add(node, path, "must be a string")
+		return ""
+	}
+
+	value := strings.TrimSpace(node.Value)
+	switch {
+	case required && value == "":
+		p.add(node, path, "must not be empty")
+	case utf8.RuneCountInString(value) > maxLength:
+		p.add(node, path, "must be at most %d characters", maxLength)
+	}
+	return value
+}
+
+func (p *documentParser) oneOf(node *yaml.Node, path string, options ...string) string {
+	value := p.text(node, path, maxTitleLength, true)
+	for _, option := range options {
+		if value == option {
+			return value
+		}
+	}
+	if value != "" {
+		p.add(node, path, "must be one of %s", strings.Join(options, ", "))
+	}
+	return value
+}
+
+func (p *documentParser) integer(node *yaml.Node, path string) (int, bool) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!int" {
+		if value, err := strconv.ParseInt(node.Value, 0, 32); err == nil {
+			return int(value), true
+		}
+	}
+	p.add(node, path, "must be an integer")
+	return 0, false
+}
+
+func (p *documentParser) number(node *yaml.Node, path string) float64 {
+	if node.Kind == yaml.ScalarNode && (node.Tag == "!!int" || node.Tag == "!!float") {
+		value, err := strconv.ParseFloat(strings.ReplaceAll(node.Value, "_", ""), 64)
+		if err == nil && !math.IsInf(value, 0) && !math.IsNaN(value) {
+			return value
+		}
+	}
+	p.add(node, path, "must be a finite number")
+	return 0
+}
+
+func (p *documentParser) boolean(node *yaml.Node, path string) bool {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!bool" {
+		if value, err := strconv.ParseBool(node.Value); err == nil {
+			return value
+		}
+	}
+	p.add(node, path, "must be true or false")
+	return false
+}
+
+func (p *documentParser) roadmap(node *yaml.Node, path string) roadmapdto.RoadmapMetadataDocument {
+	roadmap := roadmapdto.RoadmapMetadataDocument{
+		Visibility: roadmapentity.VisibilityPrivate,
+	}
+	p.fields(node, path, fieldHandlers{
+		"slug": func(node *yaml.Node, path string) {
+			roadmap.Slug = p.text(node, path, maxSlugLength, true)
+			if roadmap.Slug != "" && validateSlug(roadmap.Slug) != nil {
+				p.add(node, path, "must consist of lowercase letters, digits and single hyphens")
+			}
+		},
+		"title": func(node *yaml.Node, path string) {
+			roadmap.Title = p.text(node, path, maxTitleLength, true)
+		},
+		"description": func(node *yaml.Node, path string) {
+			roadmap.Description = p.text(node, path, maxDescriptionLength, false)
+		},
+		"visibility": func(node *yaml.Node, path string) {
+			roadmap.Visibility = p.oneOf(node, path,
+				roadmapentity.VisibilityPrivate, roadmapentity.VisibilityUnlisted, roadmapentity.VisibilityPublic)
+		},
+	}, "slug", "title")
+	return roadmap
+}
+
+func (p *documentParser) nodes(node *yaml.Node, path string) []roadmapdto.NodeDocument {
+	items := p.sequence(node, path)
+	if len(items) > maxRoadmapNodes {
+		p.add(node, path, "must have at most %d nodes", maxRoadmapNodes)
+		return nil
+	}
+
+	nodes := make([]roadmapdto.NodeDocument, 0, len(items))
+	for i, item := range items {
+		var doc roadmapdto.NodeDocument
+		p.fields(item, fmt.Sprintf("%s[%d]", path, i), fieldHandlers{
+			"key": func(node *yaml.Node, path string) {
+				doc.Key = p.text(node, path, maxNodeKeyLength, true)
+				switch first, taken := p.nodeKeys[doc.Key]; {
+				case doc.Key == "":
+				case !nodeKeyPattern.MatchString(doc.Key):
+					p.add(node, path, "must consist of lowercase letters, digits, dots, underscores and hyphens")
+				case taken:
+					p.add(node, path, "duplicate node key, first used on line %d", first.Line)
+				default:
+					p.nodeKeys[doc.Key] = node
+				}
+			},
+			"title": func(node *yaml.Node, path string) {
+				doc.Title = p.text(node, path, maxTitleLength, true)
+			},
+			"description": func(node *yaml.Node, path string) {
+				doc.Description = p.text(node, path, maxDescriptionLength, false)
+			},
+			"type": func(node *yaml.Node, path string) {
+				doc.Type = p.oneOf(node, path,
+					roadmapentity.NodeTypeTopic, roadmapentity.NodeTypeSubtopic, roadmapentity.NodeTypeMilestone)
+			},
+			"position": func(node *yaml.Node, path string) {
+				p.fields(node, path, fieldHandlers{
+					"x": func(node *yaml.Node, path string) { doc.Position.X = p.number(node, path) },
+					"y": func(node *yaml.Node, path string) { doc.Position.Y = p.number(node, path) },
+				}, "x", "y")
+			},
+			"resources": func(node *yaml.Node, path string) {
+				doc.Resources = p.resources(node, path)
+			},
+		}, "key", "title", "type")
+		nodes = append(nodes, doc)
+	}
+	return nodes
+}
+
+func (p *documentParser) resources(node *yaml.Node, path string) []roadmapdto.ResourceDocument {
+	items := p.sequence(node, path)
+	resources := make([]roadmapdto.ResourceDocument, 0, len(items))
+	for i, item := range items {
+		doc := roadmapdto.ResourceDocument{Free: true}
+		p.fields(item, fmt.Sprintf("%s[%d]", path, i), fieldHandlers{
+			"url": func(node *yaml.Node, path string) {
+				doc.URL = p.text(node, path, maxURLLength, true)
+				if doc.URL == "" {
+					return
+				}
+				_, normalizedURL, err := normalizeResourceURL(doc.URL)
+				if err != nil {
+					p.add(node, path, "must be an absolute http or https URL")
+				} else if first, taken := p.resourceURLs[normalizedURL]; taken {
+					p.add(node, path, "duplicate of the resource on line %d", first.Line)
+				} else {
+					p.resourceURLs[normalizedURL] = node
+				}
+			},
+			"title": func(node *yaml.Node, path string) {
+				doc.Title = p.text(node, path, maxTitleLength, true)
+			},
+			"kind": func(node *yaml.Node, path string) {
+				doc.Kind = p.oneOf(node, path,
+					roadmapentity.ResourceKindArticle,
+					roadmapentity.ResourceKindVideo,
+					roadmapentity.ResourceKindCourse,
+					roadmapentity.ResourceKindBook,
+					roadmapentity.ResourceKindOther,
+				)
+			},
+			"free": func(node *yaml.Node, path string) {
+				doc.Free = p.boolean(node, path)
+			},
+			"language": func(node *yaml.Node, path string) {
+				doc.Language = p.text(node, path, maxLanguageLength, false)
+				if doc.Language != "" {
+					if _, err := language.Parse(doc.Language); err != nil {
+						p.add(node, path, "must be a BCP 47 language tag such as en or pt-BR")
+					}
+				}
+			},
+			"estimated_minutes": func(node *yaml.Node, path string) {
+				minutes, ok := p.integer(node, path)
+				if !ok {
+					return
+				}
+				if minutes < 1 || minutes > maxEstimatedMinutes {
+					p.add(node, path, "must be between 1 and %d", maxEstimatedMinutes)
+				}
+				doc.EstimatedMinutes = &minutes
+			},
+		}, "url", "title", "kind")
+		resources = append(resources, doc)
+	}
+	return resources
+}
+
+func (p *documentParser) edges(node *yaml.Node, path string) ([]roadmapdto.EdgeDocument, []edgeSource) {
+	items := p.sequence(node, path)
+	edges := make([]roadmapdto.EdgeDocument, 0, len(items))
+	sources := make([]edgeSource, 0, len(items))
+	for i, item := range items {
+		var edge roadmapdto.EdgeDocument
+		source := edgeSource{path: fmt.Sprintf("%s[%d]", path, i), item: item}
+		p.fields(item, source.path, fieldHandlers{
+			"prerequisite": func(node *yaml.Node, path string) {
+				edge.Prerequisite = p.text(node, path, maxNodeKeyLength, true)
+				source.prerequisite = node
+			},
+			"node": func(node *yaml.Node, path string) {
+				edge.Node = p.text(node, path, maxNodeKeyLength, true)
+				source.node = node
+			},
+		}, "prerequisite", "node")
+		edges = append(edges, edge)
+		sources = append(sources, source)
+	}
+	return edges, sources
+}
+
+// checkEdges reports edges between unknown nodes, repeated edges and
+// prerequisites that form a cycle.
+func (p *documentParser) checkEdges(edges []roadmapdto.EdgeDocument, sources []edgeSource) {
+	// Keys are mapped to name-based UUIDs so the graph entity can look for
+	// cycles.
+	ids := make(map[string]uuid.UUID, len(p.nodeKeys))
+	keys := make(map[uuid.UUID]string, len(p.nodeKeys))
+	graph := &roadmapentity.Graph{}
+	for key := range p.nodeKeys {
+		id := uuid.NewSHA1(uuid.Nil, []byte(key))
+		ids[key], keys[id] = id, key
+		graph.Nodes = append(graph.Nodes, &roadmapentity.Node{ID: id})
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool { return keys[graph.Nodes[i].ID] < keys[graph.Nodes[j].ID] })
+
+	seen := make(map[roadmapdto.EdgeDocument]*yaml.Node, len(edges))
+	for i, edge := range edges {
+		source := sources[i]
+		if edge.Prerequisite == "" || edge.Node == "" {
+			continue
+		}
+
+		valid := true
+		if _, ok := ids[edge.Prerequisite]; !ok {
+			p.add(source.prerequisite, source.path+".prerequisite", "unknown node key %q", edge.Prerequisite)
+			valid = false
+		}
+		if _, ok := ids[edge.Node]; !ok {
+			p.add(source.node, source.path+".node", "unknown node key %q", edge.Node)
+			valid = false
+		}
+		if !valid {
+			continue
+		}
+
+		if edge.Prerequisite == edge.Node {
+			p.add(source.node, source.path+".node", "a node cannot be its own prerequisite")
+			continue
+		}
+		if first, repeated := seen[edge]; repeated {
+			p.add(source.item, source.path, "duplicate edge, first listed on line %d", first.Line)
+			continue
+		}
+		seen[edge] = source.item
+		graph.Edges = append(graph.Edges, &roadmapentity.Edge{PrerequisiteID: ids[edge.Prerequisite], NodeID: ids[edge.Node]})
+	}
+
+	var cycleErr *roadmapentity.CycleError
+	if _, err := graph.TopologicalOrder(); !errors.As(err, &cycleErr) {
+		return
+	}
+
+	cycle := make([]string, 0, len(cycleErr.NodeIDs)+1)
+	for _, id := range cycleErr.NodeIDs {
+		cycle = append(cycle, keys[id])
+	}
+	cycle = append(cycle, cycle[0])
+
+	closing := seen[roadmapdto.EdgeDocument{Prerequisite: cycle[0], Node: cycle[1]}]
+	p.add(closing, pathOfEdge(closing, sources), "prerequisites form a cycle: %s", strings.Join(cycle, " -> "))
+}
+
+func pathOfEdge(item *yaml.Node, sources []edgeSource) string {
+	for _, source := range sources {
+		if source.item == item {
+			return source.path
+		}
+	}
+	return "edges"
+}
@@ -0,0 +1,538 @@
+package roadmap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+)
+
+const testDocument = `schema_version: 1
+roadmap:
+  slug: go
+  title: Go
+  visibility: public
+nodes:
+  - key: basics
+    title: Basics
+    type: topic
+    position: {x: 0, y: 0}
+  - key: syntax
+    title: Syntax
+    type: topic
+    position: {x: 0, y: 100}
+    resources:
+      - url: https://go.dev/tour
+        title: Tour of Go
+        kind: course
+        language: en
+        estimated_minutes: 90
+edges:
+  - prerequisite: basics
+    node: syntax
+`
+
+type MockDocumentRepository struct {
+	mock.Mock
+
+	// Changes holds what the last successful Import call planned.
+	Changes *roadmaprepo.ImportChanges
+}
+
+// Import runs plan against the roadmap, graph and resources passed to Return
+// and returns the roadmap the planned changes leave behind.
+func (m *MockDocumentRepository) Import(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	slug string,
+	plan roadmaprepo.ImportPlanFunc,
+) (*roadmapentity.Roadmap, error) {
+	args := m.Called(ctx, ownerID, slug, plan)
+	if err := args.Error(3); err != nil {
+		return nil, err
+	}
+	var roadmap *roadmapentity.Roadmap
+	if args.Get(0) != nil {
+		roadmap = args.Get(0).(*roadmapentity.Roadmap)
+	}
+	changes, err := plan(roadmap, args.Get(1).(*roadmapentity.Graph), args.Get(2).([]*roadmapentity.Resource))
+	if err != nil {
+		return nil, err
+	}
+	m.Changes = changes
+	if changes.Roadmap != nil {
+		return changes.Roadmap, nil
+	}
+	return roadmap, nil
+}
+
+func decodeProblems(t *testing.T, data, format string) []roadmapdto.ImportProblem {
+	t.Helper()
+	_, err := decodeRoadmapDocument([]byte(data), format)
+	var documentErr *DocumentError
+	require.ErrorAs(t, err, &documentErr)
+	return documentErr.Problems
+}
+
+func TestDecodeRoadmapDocument(t *testing.T) {
+	doc, err := decodeRoadmapDocument([]byte(testDocument), DocumentFormatYAML)
+	require.NoError(t, err)
+
+	assert.Equal(t, "go", doc.Roadmap.Slug)
+	require.Len(t, doc.Nodes, 2)
+	assert.Equal(t, roadmapdto.PositionDocument{X: 0, Y: 100}, doc.Nodes[1].Position)
+	require.Len(t, doc.Nodes[1].Resources, 1)
+	assert.True(t, doc.Nodes[1].Resources[0].Free)
+	assert.Equal(t, 90, *doc.Nodes[1].Resources[0].EstimatedMinutes)
+	assert.Equal(t, []roadmapdto.EdgeDocument{{Prerequisite: "basics", Node: "syntax"}}, doc.Edges)
+}
+
+func TestDecodeRoadmapDocument_JSON(t *testing.T) {
+	var tree any
+	require.NoError(t, yaml.Unmarshal([]byte(testDocument), &tree))
+	data, err := json.MarshalIndent(tree, "", "  ")
+	require.NoError(t, err)
+
+	fromJSON, err := decodeRoadmapDocument(data, DocumentFormatJSON)
+	require.NoError(t, err)
+	fromYAML, err := decodeRoadmapDocument([]byte(testDocument), DocumentFormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, fromYAML, fromJSON)
+
+	// Escapes the YAML parser does not know are fine.
+	_, err = decodeRoadmapDocument([]byte(`{"schema_version": 1, "roadmap": {"slug": "go", "title": "Go \/ Web"}}`), DocumentFormatJSON)
+	assert.NoError(t, err)
+}
+
+func TestDecodeRoadmapDocument_ProblemsPointAtValues(t *testing.T) {
+	problems := decodeProblems(t, `schema_version: 1
+roadmap:
+  slug: go
+  title: Go
+  colour: blue
+nodes:
+  - key: basics
+    title: Basics
+    type: chapter
+    position: {x: left, y: 0}
+  - key: basics
+    title: Again
+    type: topic
+    resources:
+      - url: ftp://example.com
+        title: Files
+        kind: article
+        estimated_minutes: 0
+edges:
+  - prerequisite: basics
+    node: missing
+`, DocumentFormatYAML)
+
+	assert.Equal(t, []roadmapdto.ImportProblem{
+		{Line: 5, Column: 3, Path: "roadmap.colour", Message: "unknown field"},
+		{Line: 9, Column: 11, Path: "nodes[0].type", Message: "must be one of topic, subtopic, milestone"},
+		{Line: 10, Column: 19, Path: "nodes[0].position.x", Message: "must be a finite number"},
+		{Line: 11, Column: 10, Path: "nodes[1].key", Message: "duplicate node key, first used on line 7"},
+		{Line: 15, Column: 14, Path: "nodes[1].resources[0].url", Message: "must be an absolute http or https URL"},
+		{Line: 18, Column: 28, Path: "nodes[1].resources[0].estimated_minutes", Message: "must be between 1 and 100000"},
+		{Line: 21, Column: 11, Path: "edges[0].node", Message: `unknown node key "missing"`},
+	}, problems)
+}
+
+func TestDecodeRoadmapDocument_JSONProblemsPointAtValues(t *testing.T) {
+	problems := decodeProblems(t, `{
+  "schema_version": 2,
+  "roadmap": {"slug": "Go!", "title": ""},
+  "nodes": "none"
+}`, DocumentFormatJSON)
+
+	assert.Equal(t, []roadmapdto.ImportProblem{
+		{Line: 2, Column: 21, Path: "schema_version", Message: "unsupported schema version 2, expected 1"},
+		{Line: 3, Column: 23, Path: "roadmap.slug", Message: "must consist of lowercase letters, digits and single hyphens"},
+		{Line: 3, Column: 39, Path: "roadmap.title", Message: "must not be empty"},
+		{Line: 4, Column: 12, Path: "nodes", Message: "must be a list"},
+	}, problems)
+}
+
+func TestDecodeRoadmapDocument_SyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		line   int
+	}{
+		{name: "yaml", data: "schema_version: 1\nroadmap:\n  slug: go: web\n", format: DocumentFormatYAML, line: 3},
+		{name: "yaml tabs", data: "schema_version: 1\nroadmap:\n\tslug: go\n", format: DocumentFormatYAML, line: 3},
+		{name: "json", data: "{\n  \"schema_version\": 1,\n  \"roadmap\": {,}\n}", format: DocumentFormatJSON, line: 3},
+		{name: "json truncated", data: "{\n  \"schema_version\": 1,\n", format: DocumentFormatJSON, line: 3},
+		{name: "json trailing data", data: "{}\n{}", format: DocumentFormatJSON, line: 2},
+		{name: "empty", data: "  \n", format: DocumentFormatJSON, line: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := decodeProblems(t, tt.data, tt.format)
+			require.Len(t, problems, 1)
+			assert.Equal(t, tt.line, problems[0].Line, problems[0].Message)
+		})
+	}
+}
+
+func TestDecodeRoadmapDocument_RejectsAliases(t *testing.T) {
+	// Each level doubles the nodes the aliases would expand into.
+	var bomb strings.Builder
+	bomb.WriteString("schema_version: 1\nroadmap: {slug: go, title: Go}\nlevel0: &l0 [x, x]\n")
+	for i := 1; i <= 60; i++ {
+		fmt.Fprintf(&bomb, "level%d: &l%d [*l%d, *l%d]\n", i, i, i-1, i-1)
+	}
+
+	problems := decodeProblems(t, bomb.String(), DocumentFormatYAML)
+
+	require.Len(t, problems, maxDocumentProblems)
+	assert.Equal(t, roadmapdto.ImportProblem{Line: 4, Column: 14, Message: "aliases are not supported"}, problems[0])
+
+	problems = decodeProblems(t, `schema_version: 1
+roadmap: &meta {slug: go, title: Go}
+copy: *meta
+`, DocumentFormatYAML)
+	assert.Equal(t, []roadmapdto.ImportProblem{{Line: 3, Column: 7, Message: "aliases are not supported"}}, problems)
+}
+
+func TestDecodeRoadmapDocument_CapsProblems(t *testing.T) {
+	var doc strings.Builder
+	doc.WriteString("schema_version: 1\nroadmap: {slug: go, title: Go}\nnodes:\n")
+	for i := 0; i < maxDocumentProblems; i++ {
+		doc.WriteString("  - {key: Bad, title: '', type: chapter}\n")
+	}
+
+	problems := decodeProblems(t, doc.String(), DocumentFormatYAML)
+
+	assert.Len(t, problems, maxDocumentProblems)
+}
+
+func TestDecodeRoadmapDocument_Cycle(t *testing.T) {
+	problems := decodeProblems(t, testDocument+`  - prerequisite: syntax
+    node: basics
+`, DocumentFormatYAML)
+
+	require.Len(t, problems, 1)
+	assert.True(t, strings.HasPrefix(problems[0].Path, "edges["))
+	assert.Contains(t, problems[0].Message, "prerequisites form a cycle")
+	assert.Contains(t, []int{22, 24}, problems[0].Line)
+}
+
+type DocumentUseCaseTestSuite struct {
+	suite.Suite
+	roadmapRepo  *MockRoadmapRepository
+	graphRepo    *MockGraphRepository
+	resourceRepo *MockResourceRepository
+	documentRepo *MockDocumentRepository
+	owner        Actor
+	ownerID      uuid.UUID
+	roadmap      *roadmapentity.Roadmap
+	graph        *roadmapentity.Graph
+	resources    []*roadmapentity.Resource
+	ctx          context.Context
+}
+
+// SetupTest creates the roadmap of testDocument as an earlier import of it
+// left it, except that syntax was added by hand and has no key.
+func (s *DocumentUseCaseTestSuite) SetupTest() {
+	s.roadmapRepo = new(MockRoadmapRepository)
+	s.graphRepo = new(MockGraphRepository)
+	s.resourceRepo = new(MockResourceRepository)
+	s.documentRepo = new(MockDocumentRepository)
+
+	s.ownerID = uuid.New()
+	s.owner = Actor{UserID: s.ownerID.String()}
+	s.roadmap = &roadmapentity.Roadmap{
+		ID:         uuid.New(),
+		OwnerID:    s.ownerID,
+		Title:      "Go",
+		Slug:       "go",
+		Visibility: roadmapentity.VisibilityPublic,
+	}
+
+	basics := &roadmapentity.Node{
+		ID: uuid.New(), RoadmapID: s.roadmap.ID, Key: "basics", Title: "Basics", Type: roadmapentity.NodeTypeTopic,
+	}
+	syntax := &roadmapentity.Node{
+		ID: uuid.New(), RoadmapID: s.roadmap.ID, Title: "Syntax", Type: roadmapentity.NodeTypeTopic, PositionY: 100,
+	}
+	// Nodes are listed out of learning order on purpose.
+	s.graph = &roadmapentity.Graph{
+		Nodes: []*roadmapentity.Node{syntax, basics},
+		Edges: []*roadmapentity.Edge{{RoadmapID: s.roadmap.ID, PrerequisiteID: basics.ID, NodeID: syntax.ID}},
+	}
+
+	minutes := 90
+	s.resources = []*roadmapentity.Resource{{
+		ID:               uuid.New(),
+		RoadmapID:        s.roadmap.ID,
+		NodeID:           syntax.ID,
+		URL:              "https://go.dev/tour",
+		NormalizedURL:    "https://go.dev/tour",
+		Title:            "Tour of Go",
+		Kind:             roadmapentity.ResourceKindCourse,
+		IsFree:           true,
+		Language:         "en",
+		EstimatedMinutes: &minutes,
+	}}
+	s.ctx = context.Background()
+
+	s.roadmapRepo.On("GetByID", s.ctx, s.roadmap.ID).Return(s.roadmap, nil).Maybe()
+	s.graphRepo.On("GetGraph", s.ctx, s.roadmap.ID).Return(s.graph, nil).Maybe()
+	s.resourceRepo.On("ListByRoadmap", s.ctx, s.roadmap.ID).Return(s.resources, nil).Maybe()
+}
+
+func (s *DocumentUseCaseTestSuite) TearDownTest() {
+	s.roadmapRepo.AssertExpectations(s.T())
+	s.graphRepo.AssertExpectations(s.T())
+	s.resourceRepo.AssertExpectations(s.T())
+	s.documentRepo.AssertExpectations(s.T())
+}
+
+// expectImport makes Import plan against the suite's roadmap, graph and
+// resources.
+func (s *DocumentUseCaseTestSuite) expectImport() {
+	s.documentRepo.On("Import", s.ctx, s.ownerID, "go", mock.Anything).
+		Return(s.roadmap, s.graph, s.resources, nil).Once()
+}
+
+func (s *DocumentUseCaseTestSuite) importDocument(data string, dryRun bool) (roadmapdto.ImportRoadmapResponse, error) {
+	return NewImportRoadmapUseCase(s.documentRepo).
+		Execute(s.ctx, s.owner, []byte(data), DocumentFormatYAML, dryRun)
+}
+
+func (s *DocumentUseCaseTestSuite) TestExport() {
+	doc, err := NewExportRoadmapUseCase(s.roadmapRepo, s.graphRepo, s.resourceRepo).
+		Execute(s.ctx, s.owner, s.roadmap.ID.String())
+
+	s.Require().NoError(err)
+	s.Equal(roadmapdto.RoadmapSchemaVersion, doc.SchemaVersion)
+	s.Require().Len(doc.Nodes, 2)
+	s.Equal("basics", doc.Nodes[0].Key)
+	s.Equal(s.graph.Nodes[0].ID.String(), doc.Nodes[1].Key)
+	s.Equal("https://go.dev/tour", doc.Nodes[1].Resources[0].URL)
+	s.Equal([]roadmapdto.EdgeDocument{{Prerequisite: "basics", Node: doc.Nodes[1].Key}}, doc.Edges)
+}
+
+func (s *DocumentUseCaseTestSuite) TestExport_HiddenRoadmap() {
+	s.roadmap.Visibility = roadmapentity.VisibilityPrivate
+
+	_, err := NewExportRoadmapUseCase(s.roadmapRepo, s.graphRepo, s.resourceRepo).
+		Execute(s.ctx, Actor{UserID: uuid.NewString()}, s.roadmap.ID.String())
+
+	s.ErrorIs(err, ErrRoadmapNotFound)
+}
+
+func (s *DocumentUseCaseTestSuite) exportDocument() string {
+	doc, err := NewExportRoadmapUseCase(s.roadmapRepo, s.graphRepo, s.resourceRepo).
+		Execute(s.ctx, s.owner, s.roadmap.ID.String())
+	s.Require().NoError(err)
+	data, err := yaml.Marshal(doc)
+	s.Require().NoError(err)
+	return string(data)
+}
+
+func (s *DocumentUseCaseTestSuite) TestImport_ExportedDocumentChangesNothing() {
+	s.graph.Nodes[0].Key = "syntax"
+	doc := s.exportDocument()
+	s.expectImport()
+
+	response, err := s.importDocument(doc, false)
+
+	s.Require().NoError(err)
+	s.Equal(roadmapdto.ImportChanges{}, response.Changes)
+	s.Equal(&roadmaprepo.ImportChanges{}, s.documentRepo.Changes)
+	s.Equal(s.roadmap.ID, response.Roadmap.ID)
+}
+
+func (s *DocumentUseCaseTestSuite) TestImport_KeysNodesByTheirExportedID() {
+	syntax := s.graph.Nodes[0]
+	doc := s.exportDocument()
+	s.expectImport()
+
+	response, err := s.importDocument(doc, false)
+
+	s.Require().NoError(err)
+	s.Equal(roadmapdto.ImportChanges{NodesUpdated: 1}, response.Changes)
+	changes := s.documentRepo.Changes.Graph
+	s.Require().Len(changes.UpdateNodes, 1)
+	s.Equal(syntax.ID, changes.UpdateNodes[0].ID)
+	s.Equal(syntax.ID.String(), changes.UpdateNodes[0].Key)
+}
+
+const changedTestDocument = `schema_version: 1
+roadmap:
+  slug: go
+  title: Go
+  visibility: public
+nodes:
+  - key: basics
+    title: Go basics
+    type: topic
+    position: {x: 0, y: 0}
+    resources:
+      - url: https://go.dev/tour
+        title: Tour of Go
+        kind: course
+  - key: http
+    title: HTTP
+    type: topic
+    position: {x: 0, y: 200}
+    resources:
+      - url: https://pkg.go.dev/net/http
+        title: net/http
+        kind: article
+edges:
+  - prerequisite: basics
+    node: http
+`
+
+func (s *DocumentUseCaseTestSuite) TestImport_MatchesNodesByKey() {
+	basics, syntax := s.graph.Nodes[1], s.graph.Nodes[0]
+	syntax.Key = "syntax"
+	s.expectImport()
+
+	response, err := s.importDocument(changedTestDocument, false)
+
+	s.Require().NoError(err)
+	s.Equal(roadmapdto.ImportChanges{
+		NodesCreated:     1,
+		NodesUpdated:     1,
+		NodesDeleted:     1,
+		EdgesAdded:       1,
+		EdgesRemoved:     1,
+		ResourcesCreated: 2,
+		ResourcesDeleted: 1,
+	}, response.Changes)
+	s.Nil(s.documentRepo.Changes.Roadmap)
+
+	changes := s.documentRepo.Changes.Graph
+	s.Equal([]uuid.UUID{syntax.ID}, changes.DeleteNodes)
+	s.Empty(changes.RemoveEdges)
+	s.Require().Len(changes.UpdateNodes, 1)
+	s.Equal(basics.ID, changes.UpdateNodes[0].ID)
+	s.Equal("Go basics", changes.UpdateNodes[0].Title)
+	s.Require().Len(changes.CreateNodes, 1)
+	http := changes.CreateNodes[0]
+	s.Equal("http", http.Key)
+	s.Require().Len(changes.AddEdges, 1)
+	s.Equal(basics.ID, changes.AddEdges[0].PrerequisiteID)
+	s.Equal(http.ID, changes.AddEdges[0].NodeID)
+
+	// The resource moves with basics, so it is deleted and created there.
+	resources := s.documentRepo.Changes.Resources
+	s.Equal([]uuid.UUID{s.resources[0].ID}, resources.DeleteResources)
+	created := resources.CreateResources
+	s.Require().Len(created, 2)
+	s.Equal(basics.ID, created[0].NodeID)
+	s.Equal("https://go.dev/tour", created[0].NormalizedURL)
+	s.Equal(http.ID, created[1].NodeID)
+	s.True(created[1].IsFree)
+}
+
+func (s *DocumentUseCaseTestSuite) TestImport_DryRun() {
+	s.graph.Nodes[0].Key = "syntax"
+	s.expectImport()
+
+	response, err := s.importDocument(changedTestDocument, true)
+
+	s.Require().NoError(err)
+	s.True(response.DryRun)
+	s.Equal(s.roadmap.ID, response.Roadmap.ID)
+	s.Equal(roadmapdto.ImportChanges{
+		NodesCreated:     1,
+		NodesUpdated:     1,
+		NodesDeleted:     1,
+		EdgesAdded:       1,
+		EdgesRemoved:     1,
+		ResourcesCreated: 2,
+		ResourcesDeleted: 1,
+	}, response.Changes)
+	s.Nil(s.documentRepo.Changes, "the dry run aborts the import")
+}
+
+func (s *DocumentUseCaseTestSuite) TestImport_CreatesRoadmap() {
+	s.documentRepo.On("Import", s.ctx, s.ownerID, "web", mock.Anything).
+		Return(nil, &roadmapentity.Graph{}, []*roadmapentity.Resource(nil), nil).Once()
+
+	response, err := s.importDocument("schema_version: 1\nroadmap: {slug: web, title: Web}\nnodes:\n"+
+		"  - {key: html, title: HTML, type: topic, position: {x: 0, y: 0}}\n", false)
+
+	s.Require().NoError(err)
+	stored := s.documentRepo.Changes.Roadmap
+	s.Require().NotNil(stored)
+	s.Equal(s.ownerID, stored.OwnerID)
+	s.Equal("web", stored.Slug)
+	s.Equal(roadmapentity.VisibilityPrivate, stored.Visibility)
+	s.Require().Len(s.documentRepo.Changes.Graph.CreateNodes, 1)
+	s.Equal(stored.ID, s.documentRepo.Changes.Graph.CreateNodes[0].RoadmapID)
+	s.Equal(stored.ID, response.Roadmap.ID)
+	s.Equal(roadmapdto.ImportChanges{RoadmapCreated: true, NodesCreated: 1}, response.Changes)
+}
+
+func (s *DocumentUseCaseTestSuite) TestImport_ReordersResources() {
+	s.graph.Nodes[0].Key = "syntax"
+	s.resources = append(s.resources, &roadmapentity.Resource{
+		ID:            uuid.New(),
+		RoadmapID:     s.roadmap.ID,
+		NodeID:        s.graph.Nodes[0].ID,
+		URL:           "https://go.dev/doc/effective_go",
+		NormalizedURL: "https://go.dev/doc/effective_go",
+		Title:         "Effective Go",
+		Kind:          roadmapentity.ResourceKindBook,
+		IsFree:        true,
+		Position:      1,
+	})
+	s.expectImport()
+
+	doc := strings.Replace(testDocument, "    resources:\n", `    resources:
+      - url: https://go.dev/doc/effective_go
+        title: Effective Go
+        kind: book
+`, 1)
+	response, err := s.importDocument(doc, false)
+
+	s.Require().NoError(err)
+	s.Equal(roadmapdto.ImportChanges{ResourcesUpdated: 2}, response.Changes)
+	s.Equal([]roadmaprepo.ResourceOrder{{
+		NodeID:      s.graph.Nodes[0].ID,
+		ResourceIDs: []uuid.UUID{s.resources[1].ID, s.resources[0].ID},
+	}}, s.documentRepo.Changes.Resources.Reorders)
+}
+
+func (s *DocumentUseCaseTestSuite) TestImport_ConflictStoresNothing() {
+	s.documentRepo.On("Import", s.ctx, s.ownerID, "go", mock.Anything).
+		Return(nil, nil, nil, roadmaprepo.ErrResourceURLTaken).Once()
+
+	_, err := s.importDocument(testDocument, false)
+
+	s.ErrorIs(err, ErrResourceURLTaken)
+}
+
+func (s *DocumentUseCaseTestSuite) TestImport_InvalidDocumentStoresNothing() {
+	_, err := s.importDocument("schema_version: 1\nroadmap: {slug: go}\n", false)
+
+	var documentErr *DocumentError
+	s.Require().ErrorAs(err, &documentErr)
+	s.Equal("roadmap.title", documentErr.Problems[0].Path)
+	s.documentRepo.AssertNotCalled(s.T(), "Import", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDocumentUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(DocumentUseCaseTestSuite))
+}
@@ -0,0 +1,51 @@
+package roadmap
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+)
+
+var (
+	ErrInvalidTokenClaims = errors.New("invalid token claims")
+	ErrRoadmapNotFound    = errors.New("roadmap not found")
+	ErrRoadmapSlugTaken   = errors.New("roadmap slug already in use")
+	ErrBlankTitle         = errors.New("title must not be blank")
+	ErrInvalidSlug        = errors.New("slug must consist of lowercase letters, digits and single hyphens")
+	ErrNotRoadmapEditor   = errors.New("only the owner or a moderator can change this roadmap")
+	ErrNodeNotFound       = errors.New("node not found")
+	ErrNodeExists         = errors.New("node already exists")
+	ErrEdgeNotFound       = errors.New("edge not found")
+	ErrEdgeExists         = errors.New("edge already exists")
+	ErrSelfPrerequisite   = errors.New("a node cannot be its own prerequisite")
+	ErrGraphTooLarge      = errors.New("roadmap has too many nodes")
+	ErrResourceNotFound   = errors.New("resource not found")
+	ErrResourceURLTaken   = errors.New("resource url already in roadmap")
+	ErrInvalidResourceURL = errors.New("resource url must be an absolute http or https url")
+	ErrInvalidOrder       = errors.New("order must list every resource of the node once")
+)
+
+// PrerequisitesUnfinishedError is returned when nodes are marked done before
+// their prerequisites are done or skipped. Unfinished maps each such node to
+// its unfinished prerequisites.
+type PrerequisitesUnfinishedError struct {
+	Unfinished map[uuid.UUID][]uuid.UUID
+}
+
+func (e *PrerequisitesUnfinishedError) Error() string {
+	return fmt.Sprintf("%d nodes have unfinished prerequisites", len(e.Unfinished))
+}
+
+// DocumentError is returned for roadmap documents that cannot be imported.
+// Problems lists every problem found, in the order they appear in the
+// document.
+type DocumentError struct {
+	Problems []roadmapdto.ImportProblem
+}
+
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("roadmap document has %d problems", len(e.Problems))
+}
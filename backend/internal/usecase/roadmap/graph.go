@@ -0,0 +1,351 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+)
+
+// maxRoadmapNodes keeps graphs small enough to load and order in one go.
+const maxRoadmapNodes = 1000
+
+func newNodeResponse(node *roadmapentity.Node) roadmapdto.NodeResponse {
+	return roadmapdto.NodeResponse{
+		ID:          node.ID,
+		Key:         node.Key,
+		Title:       node.Title,
+		Description: node.Description,
+		Type:        node.Type,
+		PositionX:   node.PositionX,
+		PositionY:   node.PositionY,
+		CreatedAt:   node.CreatedAt,
+		UpdatedAt:   node.UpdatedAt,
+	}
+}
+
+func newGraphResponse(roadmapID uuid.UUID, graph *roadmapentity.Graph) roadmapdto.GraphResponse {
+	response := roadmapdto.GraphResponse{
+		RoadmapID: roadmapID,
+		Nodes:     make([]roadmapdto.NodeResponse, 0, len(graph.Nodes)),
+		Edges:     make([]roadmapdto.EdgeResponse, 0, len(graph.Edges)),
+	}
+	for _, node := range graph.Nodes {
+		response.Nodes = append(response.Nodes, newNodeResponse(node))
+	}
+	for _, edge := range graph.Edges {
+		response.Edges = append(response.Edges, roadmapdto.EdgeResponse{
+			PrerequisiteID: edge.PrerequisiteID,
+			NodeID:         edge.NodeID,
+		})
+	}
+	return response
+}
+
+type GetRoadmapGraphUseCase struct {
+	roadmapRepository roadmaprepo.RoadmapRepository
+	graphRepository   roadmaprepo.GraphRepository
+}
+
+func NewGetRoadmapGraphUseCase(
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	graphRepository roadmaprepo.GraphRepository,
+) *GetRoadmapGraphUseCase {
+	return &GetRoadmapGraphUseCase{
+		roadmapRepository: roadmapRepository,
+		graphRepository:   graphRepository,
+	}
+}
+
+func (u *GetRoadmapGraphUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+) (roadmapdto.GraphResponse, error) {
+	roadmap, err := getReadableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return roadmapdto.GraphResponse{}, err
+	}
+
+	graph, err := u.graphRepository.GetGraph(ctx, roadmap.ID)
+	if err != nil {
+		return roadmapdto.GraphResponse{}, err
+	}
+
+	return newGraphResponse(roadmap.ID, graph), nil
+}
+
+type GetNodeOrderUseCase struct {
+	roadmapRepository roadmaprepo.RoadmapRepository
+	graphRepository   roadmaprepo.GraphRepository
+}
+
+func NewGetNodeOrderUseCase(
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	graphRepository roadmaprepo.GraphRepository,
+) *GetNodeOrderUseCase {
+	return &GetNodeOrderUseCase{
+		roadmapRepository: roadmapRepository,
+		graphRepository:   graphRepository,
+	}
+}
+
+// Execute returns the nodes in an order in which they can be learned, every
+// node after its prerequisites.
+func (u *GetNodeOrderUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+) (roadmapdto.NodeOrderResponse, error) {
+	roadmap, err := getReadableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return roadmapdto.NodeOrderResponse{}, err
+	}
+
+	graph, err := u.graphRepository.GetGraph(ctx, roadmap.ID)
+	if err != nil {
+		return roadmapdto.NodeOrderResponse{}, err
+	}
+
+	nodes, err := graph.TopologicalOrder()
+	if err != nil {
+		return roadmapdto.NodeOrderResponse{}, err
+	}
+
+	response := roadmapdto.NodeOrderResponse{
+		RoadmapID: roadmap.ID,
+		Nodes:     make([]roadmapdto.NodeResponse, 0, len(nodes)),
+	}
+	for _, node := range nodes {
+		response.Nodes = append(response.Nodes, newNodeResponse(node))
+	}
+
+	return response, nil
+}
+
+type EditRoadmapGraphUseCase struct {
+	roadmapRepository roadmaprepo.RoadmapRepository
+	graphRepository   roadmaprepo.GraphRepository
+}
+
+func NewEditRoadmapGraphUseCase(
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	graphRepository roadmaprepo.GraphRepository,
+) *EditRoadmapGraphUseCase {
+	return &EditRoadmapGraphUseCase{
+		roadmapRepository: roadmapRepository,
+		graphRepository:   graphRepository,
+	}
+}
+
+// Execute applies a batch of node and edge changes in one transaction. The
+// batch is rejected as a whole if any change refers to a missing node or
+// edge, or if the resulting prerequisites would form a cycle, in which case
+// the error is a *roadmapentity.CycleError.
+func (u *EditRoadmapGraphUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+	req roadmapdto.EditGraphRequest,
+) (roadmapdto.GraphResponse, error) {
+	roadmap, err := getEditableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return roadmapdto.GraphResponse{}, err
+	}
+
+	graph, err := u.graphRepository.EditGraph(ctx, roadmap.ID, func(graph *roadmapentity.Graph) (*roadmaprepo.GraphChanges, error) {
+		return planGraphEdit(roadmap.ID, graph, req)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, roadmaprepo.ErrRoadmapNotFound):
+			return roadmapdto.GraphResponse{}, ErrRoadmapNotFound
+		case errors.Is(err, roadmaprepo.ErrNodeIDTaken):
+			return roadmapdto.GraphResponse{}, ErrNodeExists
+		}
+		return roadmapdto.GraphResponse{}, err
+	}
+
+	return newGraphResponse(roadmap.ID, graph), nil
+}
+
+type edgeKey struct {
+	prerequisiteID uuid.UUID
+	nodeID         uuid.UUID
+}
+
+// planGraphEdit checks req against the current graph and turns it into the
+// changes to store.
+func planGraphEdit(
+	roadmapID uuid.UUID,
+	graph *roadmapentity.Graph,
+	req roadmapdto.EditGraphRequest,
+) (*roadmaprepo.GraphChanges, error) {
+	changes := &roadmaprepo.GraphChanges{}
+
+	nodes := make(map[uuid.UUID]*roadmapentity.Node, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		nodes[node.ID] = node
+	}
+	edges := make(map[edgeKey]bool, len(graph.Edges))
+	for _, edge := range graph.Edges {
+		edges[edgeKey{edge.PrerequisiteID, edge.NodeID}] = true
+	}
+
+	for _, edge := range req.RemoveEdges {
+		key := edgeKey{edge.PrerequisiteID, edge.NodeID}
+		if !edges[key] {
+			return nil, fmt.Errorf("%w: %s -> %s", ErrEdgeNotFound, edge.PrerequisiteID, edge.NodeID)
+		}
+		delete(edges, key)
+		changes.RemoveEdges = append(changes.RemoveEdges, &roadmapentity.Edge{
+			RoadmapID:      roadmapID,
+			PrerequisiteID: edge.PrerequisiteID,
+			NodeID:         edge.NodeID,
+		})
+	}
+
+	for _, id := range req.DeleteNodes {
+		if _, ok := nodes[id]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, id)
+		}
+		delete(nodes, id)
+		changes.DeleteNodes = append(changes.DeleteNodes, id)
+	}
+	for key := range edges {
+		if nodes[key.prerequisiteID] == nil || nodes[key.nodeID] == nil {
+			delete(edges, key)
+		}
+	}
+
+	created := make(map[uuid.UUID]bool, len(req.CreateNodes))
+	for _, nodeReq := range req.CreateNodes {
+		id := uuid.New()
+		if nodeReq.ID != nil {
+			id = *nodeReq.ID
+		}
+		if _, exists := nodes[id]; exists {
+			return nil, fmt.Errorf("%w: %s", ErrNodeExists, id)
+		}
+		title := strings.TrimSpace(nodeReq.Title)
+		if title == "" {
+			return nil, fmt.Errorf("%w: node %s", ErrBlankTitle, id)
+		}
+
+		node := &roadmapentity.Node{
+			ID:          id,
+			RoadmapID:   roadmapID,
+			Title:       title,
+			Description: nodeReq.Description,
+			Type:        nodeReq.Type,
+			PositionX:   nodeReq.PositionX,
+			PositionY:   nodeReq.PositionY,
+		}
+		nodes[id] = node
+		created[id] = true
+		changes.CreateNodes = append(changes.CreateNodes, node)
+	}
+
+	for _, nodeReq := range req.UpdateNodes {
+		existing, ok := nodes[nodeReq.ID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, nodeReq.ID)
+		}
+
+		// Nodes created in this batch are inserted with their final values.
+		node := existing
+		if !created[node.ID] {
+			updated := *existing
+			node = &updated
+			nodes[node.ID] = node
+			changes.UpdateNodes = append(changes.UpdateNodes, node)
+		}
+
+		if nodeReq.Title != nil {
+			title := strings.TrimSpace(*nodeReq.Title)
+			if title == "" {
+				return nil, fmt.Errorf("%w: node %s", ErrBlankTitle, node.ID)
+			}
+			node.Title = title
+		}
+		if nodeReq.Description != nil {
+			node.Description = *nodeReq.Description
+		}
+		if nodeReq.Type != nil {
+			node.Type = *nodeReq.Type
+		}
+		if nodeReq.PositionX != nil {
+			node.PositionX = *nodeReq.PositionX
+		}
+		if nodeReq.PositionY != nil {
+			node.PositionY = *nodeReq.PositionY
+		}
+	}
+
+	for _, edge := range req.AddEdges {
+		if edge.PrerequisiteID == edge.NodeID {
+			return nil, fmt.Errorf("%w: %s", ErrSelfPrerequisite, edge.NodeID)
+		}
+		for _, id := range []uuid.UUID{edge.PrerequisiteID, edge.NodeID} {
+			if _, ok := nodes[id]; !ok {
+				return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, id)
+			}
+		}
+
+		key := edgeKey{edge.PrerequisiteID, edge.NodeID}
+		if edges[key] {
+			return nil, fmt.Errorf("%w: %s -> %s", ErrEdgeExists, edge.PrerequisiteID, edge.NodeID)
+		}
+		edges[key] = true
+		changes.AddEdges = append(changes.AddEdges, &roadmapentity.Edge{
+			RoadmapID:      roadmapID,
+			PrerequisiteID: edge.PrerequisiteID,
+			NodeID:         edge.NodeID,
+		})
+	}
+
+	if len(nodes) > maxRoadmapNodes {
+		return nil, ErrGraphTooLarge
+	}
+
+	if len(changes.AddEdges) > 0 {
+		if _, err := editedGraph(graph, nodes, created, edges, changes).TopologicalOrder(); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+// editedGraph assembles the graph planGraphEdit would leave behind, keeping
+// existing nodes and edges in their original order ahead of new ones.
+func editedGraph(
+	graph *roadmapentity.Graph,
+	nodes map[uuid.UUID]*roadmapentity.Node,
+	created map[uuid.UUID]bool,
+	edges map[edgeKey]bool,
+	changes *roadmaprepo.GraphChanges,
+) *roadmapentity.Graph {
+	edited := &roadmapentity.Graph{}
+	for _, node := range graph.Nodes {
+		if current, ok := nodes[node.ID]; ok && !created[node.ID] {
+			edited.Nodes = append(edited.Nodes, current)
+		}
+	}
+	edited.Nodes = append(edited.Nodes, changes.CreateNodes...)
+
+	for _, edge := range graph.Edges {
+		if edges[edgeKey{edge.PrerequisiteID, edge.NodeID}] {
+			edited.Edges = append(edited.Edges, edge)
+		}
+	}
+	edited.Edges = append(edited.Edges, changes.AddEdges...)
+
+	return edited
+}
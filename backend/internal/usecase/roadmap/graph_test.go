@@ -0,0 +1,310 @@
+package roadmap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+)
+
+type MockGraphRepository struct {
+	mock.Mock
+	// Changes holds what the last successful EditGraph call planned.
+	Changes *roadmaprepo.GraphChanges
+}
+
+func (m *MockGraphRepository) GetGraph(ctx context.Context, roadmapID uuid.UUID) (*roadmapentity.Graph, error) {
+	args := m.Called(ctx, roadmapID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Graph), args.Error(1)
+}
+
+// EditGraph runs edit against the graph passed to Return and returns that
+// graph unchanged.
+func (m *MockGraphRepository) EditGraph(
+	ctx context.Context,
+	roadmapID uuid.UUID,
+	edit roadmaprepo.GraphEditFunc,
+) (*roadmapentity.Graph, error) {
+	args := m.Called(ctx, roadmapID, edit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	graph := args.Get(0).(*roadmapentity.Graph)
+	changes, err := edit(graph)
+	if err != nil {
+		return nil, err
+	}
+	m.Changes = changes
+	return graph, args.Error(1)
+}
+
+type GraphUseCaseTestSuite struct {
+	suite.Suite
+	roadmapRepo *MockRoadmapRepository
+	graphRepo   *MockGraphRepository
+	owner       Actor
+	roadmap     *roadmapentity.Roadmap
+	graph       *roadmapentity.Graph
+	ctx         context.Context
+}
+
+// SetupTest creates a roadmap whose graph is the chain basics -> syntax ->
+// concurrency.
+func (s *GraphUseCaseTestSuite) SetupTest() {
+	s.roadmapRepo = new(MockRoadmapRepository)
+	s.graphRepo = new(MockGraphRepository)
+
+	ownerID := uuid.New()
+	s.owner = Actor{UserID: ownerID.String()}
+	s.roadmap = &roadmapentity.Roadmap{
+		ID:         uuid.New(),
+		OwnerID:    ownerID,
+		Title:      "Go",
+		Slug:       "go",
+		Visibility: roadmapentity.VisibilityPublic,
+	}
+
+	now := time.Now().UTC()
+	s.graph = &roadmapentity.Graph{}
+	for _, title := range []string{"Basics", "Syntax", "Concurrency"} {
+		s.graph.Nodes = append(s.graph.Nodes, &roadmapentity.Node{
+			ID:        uuid.New(),
+			RoadmapID: s.roadmap.ID,
+			Title:     title,
+			Type:      roadmapentity.NodeTypeTopic,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+	s.graph.Edges = []*roadmapentity.Edge{
+		{RoadmapID: s.roadmap.ID, PrerequisiteID: s.graph.Nodes[0].ID, NodeID: s.graph.Nodes[1].ID},
+		{RoadmapID: s.roadmap.ID, PrerequisiteID: s.graph.Nodes[1].ID, NodeID: s.graph.Nodes[2].ID},
+	}
+	s.ctx = context.Background()
+
+	s.roadmapRepo.On("GetByID", s.ctx, s.roadmap.ID).Return(s.roadmap, nil).Maybe()
+}
+
+func (s *GraphUseCaseTestSuite) TearDownTest() {
+	s.roadmapRepo.AssertExpectations(s.T())
+	s.graphRepo.AssertExpectations(s.T())
+}
+
+func (s *GraphUseCaseTestSuite) edit(req roadmapdto.EditGraphRequest) error {
+	s.graphRepo.On("EditGraph", s.ctx, s.roadmap.ID, mock.Anything).Return(s.graph, nil)
+
+	_, err := NewEditRoadmapGraphUseCase(s.roadmapRepo, s.graphRepo).Execute(s.ctx, s.owner, s.roadmap.ID.String(), req)
+	return err
+}
+
+func (s *GraphUseCaseTestSuite) TestGetGraph() {
+	s.graphRepo.On("GetGraph", s.ctx, s.roadmap.ID).Return(s.graph, nil)
+
+	response, err := NewGetRoadmapGraphUseCase(s.roadmapRepo, s.graphRepo).
+		Execute(s.ctx, Actor{UserID: uuid.NewString()}, s.roadmap.ID.String())
+
+	s.Require().NoError(err)
+	s.Len(response.Nodes, 3)
+	s.Equal(s.graph.Nodes[1].ID, response.Edges[0].NodeID)
+}
+
+func (s *GraphUseCaseTestSuite) TestNodeOrder() {
+	s.graph.Nodes[0], s.graph.Nodes[2] = s.graph.Nodes[2], s.graph.Nodes[0]
+	s.graphRepo.On("GetGraph", s.ctx, s.roadmap.ID).Return(s.graph, nil)
+
+	response, err := NewGetNodeOrderUseCase(s.roadmapRepo, s.graphRepo).Execute(s.ctx, s.owner, s.roadmap.ID.String())
+
+	s.Require().NoError(err)
+	s.Require().Len(response.Nodes, 3)
+	s.Equal("Basics", response.Nodes[0].Title)
+	s.Equal("Syntax", response.Nodes[1].Title)
+	s.Equal("Concurrency", response.Nodes[2].Title)
+}
+
+func (s *GraphUseCaseTestSuite) TestEdit_CreateNodesWithEdges() {
+	generics := uuid.New()
+	title := "Generics in depth"
+	x := 120.0
+
+	err := s.edit(roadmapdto.EditGraphRequest{
+		CreateNodes: []roadmapdto.CreateNodeRequest{
+			{ID: &generics, Title: "Generics", Type: roadmapentity.NodeTypeSubtopic},
+			{Title: "Go 1.18 released", Type: roadmapentity.NodeTypeMilestone},
+		},
+		UpdateNodes: []roadmapdto.UpdateNodeRequest{
+			{ID: generics, Title: &title},
+			{ID: s.graph.Nodes[2].ID, PositionX: &x},
+		},
+		AddEdges: []roadmapdto.EdgeRequest{
+			{PrerequisiteID: s.graph.Nodes[1].ID, NodeID: generics},
+		},
+	})
+
+	s.Require().NoError(err)
+	changes := s.graphRepo.Changes
+	s.Require().Len(changes.CreateNodes, 2)
+	s.Equal(generics, changes.CreateNodes[0].ID)
+	s.Equal("Generics in depth", changes.CreateNodes[0].Title)
+	s.NotEqual(uuid.Nil, changes.CreateNodes[1].ID)
+	s.Require().Len(changes.UpdateNodes, 1)
+	s.Equal(120.0, changes.UpdateNodes[0].PositionX)
+	s.Equal("Concurrency", changes.UpdateNodes[0].Title)
+	s.Zero(s.graph.Nodes[2].PositionX, "the loaded graph must not be modified")
+	s.Len(changes.AddEdges, 1)
+}
+
+func (s *GraphUseCaseTestSuite) TestEdit_RejectsCycle() {
+	err := s.edit(roadmapdto.EditGraphRequest{
+		AddEdges: []roadmapdto.EdgeRequest{
+			{PrerequisiteID: s.graph.Nodes[2].ID, NodeID: s.graph.Nodes[0].ID},
+		},
+	})
+
+	var cycleErr *roadmapentity.CycleError
+	s.Require().ErrorAs(err, &cycleErr)
+	s.Len(cycleErr.NodeIDs, 3)
+	s.Nil(s.graphRepo.Changes)
+}
+
+func (s *GraphUseCaseTestSuite) TestEdit_ReversingAnEdgeInOneBatch() {
+	err := s.edit(roadmapdto.EditGraphRequest{
+		RemoveEdges: []roadmapdto.EdgeRequest{
+			{PrerequisiteID: s.graph.Nodes[1].ID, NodeID: s.graph.Nodes[2].ID},
+		},
+		AddEdges: []roadmapdto.EdgeRequest{
+			{PrerequisiteID: s.graph.Nodes[2].ID, NodeID: s.graph.Nodes[1].ID},
+		},
+	})
+
+	s.NoError(err)
+}
+
+func (s *GraphUseCaseTestSuite) TestEdit_DeletedNodeLosesItsEdges() {
+	err := s.edit(roadmapdto.EditGraphRequest{
+		DeleteNodes: []uuid.UUID{s.graph.Nodes[1].ID},
+		AddEdges: []roadmapdto.EdgeRequest{
+			{PrerequisiteID: s.graph.Nodes[2].ID, NodeID: s.graph.Nodes[0].ID},
+		},
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]uuid.UUID{s.graph.Nodes[1].ID}, s.graphRepo.Changes.DeleteNodes)
+}
+
+func (s *GraphUseCaseTestSuite) TestEdit_RecreateDeletedNode() {
+	id := s.graph.Nodes[2].ID
+
+	err := s.edit(roadmapdto.EditGraphRequest{
+		DeleteNodes: []uuid.UUID{id},
+		CreateNodes: []roadmapdto.CreateNodeRequest{{ID: &id, Title: "Concurrency", Type: roadmapentity.NodeTypeTopic}},
+		AddEdges:    []roadmapdto.EdgeRequest{{PrerequisiteID: s.graph.Nodes[0].ID, NodeID: id}},
+	})
+
+	s.NoError(err)
+}
+
+func (s *GraphUseCaseTestSuite) TestEdit_InvalidChanges() {
+	missing := uuid.New()
+	existing := s.graph.Nodes[0].ID
+	blank := " "
+
+	tests := []struct {
+		name string
+		req  roadmapdto.EditGraphRequest
+		want error
+	}{
+		{
+			name: "unknown node in edge",
+			req:  roadmapdto.EditGraphRequest{AddEdges: []roadmapdto.EdgeRequest{{PrerequisiteID: existing, NodeID: missing}}},
+			want: ErrNodeNotFound,
+		},
+		{
+			name: "edge to deleted node",
+			req: roadmapdto.EditGraphRequest{
+				DeleteNodes: []uuid.UUID{s.graph.Nodes[2].ID},
+				AddEdges:    []roadmapdto.EdgeRequest{{PrerequisiteID: existing, NodeID: s.graph.Nodes[2].ID}},
+			},
+			want: ErrNodeNotFound,
+		},
+		{
+			name: "update unknown node",
+			req:  roadmapdto.EditGraphRequest{UpdateNodes: []roadmapdto.UpdateNodeRequest{{ID: missing}}},
+			want: ErrNodeNotFound,
+		},
+		{
+			name: "remove unknown edge",
+			req:  roadmapdto.EditGraphRequest{RemoveEdges: []roadmapdto.EdgeRequest{{PrerequisiteID: missing, NodeID: existing}}},
+			want: ErrEdgeNotFound,
+		},
+		{
+			name: "duplicate edge",
+			req: roadmapdto.EditGraphRequest{AddEdges: []roadmapdto.EdgeRequest{
+				{PrerequisiteID: existing, NodeID: s.graph.Nodes[1].ID},
+			}},
+			want: ErrEdgeExists,
+		},
+		{
+			name: "self prerequisite",
+			req:  roadmapdto.EditGraphRequest{AddEdges: []roadmapdto.EdgeRequest{{PrerequisiteID: existing, NodeID: existing}}},
+			want: ErrSelfPrerequisite,
+		},
+		{
+			name: "existing node id",
+			req: roadmapdto.EditGraphRequest{CreateNodes: []roadmapdto.CreateNodeRequest{
+				{ID: &existing, Title: "Again", Type: roadmapentity.NodeTypeTopic},
+			}},
+			want: ErrNodeExists,
+		},
+		{
+			name: "blank title on create",
+			req: roadmapdto.EditGraphRequest{CreateNodes: []roadmapdto.CreateNodeRequest{
+				{Title: "  \t ", Type: roadmapentity.NodeTypeTopic},
+			}},
+			want: ErrBlankTitle,
+		},
+		{
+			name: "blank title on update",
+			req: roadmapdto.EditGraphRequest{UpdateNodes: []roadmapdto.UpdateNodeRequest{
+				{ID: existing, Title: &blank},
+			}},
+			want: ErrBlankTitle,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			_, err := planGraphEdit(s.roadmap.ID, s.graph, tt.req)
+			s.ErrorIs(err, tt.want)
+		})
+	}
+}
+
+func (s *GraphUseCaseTestSuite) TestEdit_NotEditor() {
+	_, err := NewEditRoadmapGraphUseCase(s.roadmapRepo, s.graphRepo).
+		Execute(s.ctx, Actor{UserID: uuid.NewString()}, s.roadmap.ID.String(), roadmapdto.EditGraphRequest{})
+
+	s.ErrorIs(err, ErrNotRoadmapEditor)
+}
+
+func (s *GraphUseCaseTestSuite) TestEdit_NodeIDTakenByOtherRoadmap() {
+	s.graphRepo.On("EditGraph", s.ctx, s.roadmap.ID, mock.Anything).Return(nil, roadmaprepo.ErrNodeIDTaken)
+
+	_, err := NewEditRoadmapGraphUseCase(s.roadmapRepo, s.graphRepo).
+		Execute(s.ctx, s.owner, s.roadmap.ID.String(), roadmapdto.EditGraphRequest{})
+
+	s.ErrorIs(err, ErrNodeExists)
+}
+
+func TestGraphUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(GraphUseCaseTestSuite))
+}
@@ -0,0 +1,466 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+)
+
+type ExportRoadmapUseCase struct {
+	roadmapRepository  roadmaprepo.RoadmapRepository
+	graphRepository    roadmaprepo.GraphRepository
+	resourceRepository roadmaprepo.ResourceRepository
+}
+
+func NewExportRoadmapUseCase(
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	graphRepository roadmaprepo.GraphRepository,
+	resourceRepository roadmaprepo.ResourceRepository,
+) *ExportRoadmapUseCase {
+	return &ExportRoadmapUseCase{
+		roadmapRepository:  roadmapRepository,
+		graphRepository:    graphRepository,
+		resourceRepository: resourceRepository,
+	}
+}
+
+// Execute writes the roadmap as a document, with nodes in learning order.
+// Nodes without a key are keyed by their ID, so importing the document back
+// updates them and gives them that key.
+func (u *ExportRoadmapUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+) (*roadmapdto.RoadmapDocument, error) {
+	roadmap, err := getReadableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := u.graphRepository.GetGraph(ctx, roadmap.ID)
+	if err != nil {
+		return nil, err
+	}
+	ordered, err := graph.TopologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := u.resourceRepository.ListByRoadmap(ctx, roadmap.ID)
+	if err != nil {
+		return nil, err
+	}
+	byNode := make(map[uuid.UUID][]*roadmapentity.Resource, len(ordered))
+	for _, resource := range resources {
+		byNode[resource.NodeID] = append(byNode[resource.NodeID], resource)
+	}
+
+	doc := &roadmapdto.RoadmapDocument{
+		SchemaVersion: roadmapdto.RoadmapSchemaVersion,
+		Roadmap: roadmapdto.RoadmapMetadataDocument{
+			Slug:        roadmap.Slug,
+			Title:       roadmap.Title,
+			Description: roadmap.Description,
+			Visibility:  roadmap.Visibility,
+		},
+		Nodes: make([]roadmapdto.NodeDocument, 0, len(ordered)),
+		Edges: make([]roadmapdto.EdgeDocument, 0, len(graph.Edges)),
+	}
+
+	keys := make(map[uuid.UUID]string, len(ordered))
+	index := make(map[uuid.UUID]int, len(ordered))
+	for i, node := range ordered {
+		key := node.Key
+		if key == "" {
+			key = node.ID.String()
+		}
+		keys[node.ID], index[node.ID] = key, i
+
+		nodeResources := byNode[node.ID]
+		sort.SliceStable(nodeResources, func(i, j int) bool { return nodeResources[i].Position < nodeResources[j].Position })
+
+		nodeDoc := roadmapdto.NodeDocument{
+			Key:         key,
+			Title:       node.Title,
+			Description: node.Description,
+			Type:        node.Type,
+			Position:    roadmapdto.PositionDocument{X: node.PositionX, Y: node.PositionY},
+		}
+		for _, resource := range nodeResources {
+			nodeDoc.Resources = append(nodeDoc.Resources, roadmapdto.ResourceDocument{
+				URL:              resource.URL,
+				Title:            resource.Title,
+				Kind:             resource.Kind,
+				Free:             resource.IsFree,
+				Language:         resource.Language,
+				EstimatedMinutes: resource.EstimatedMinutes,
+			})
+		}
+		doc.Nodes = append(doc.Nodes, nodeDoc)
+	}
+
+	edges := slices.Clone(graph.Edges)
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if index[a.NodeID] != index[b.NodeID] {
+			return index[a.NodeID] < index[b.NodeID]
+		}
+		return index[a.PrerequisiteID] < index[b.PrerequisiteID]
+	})
+	for _, edge := range edges {
+		doc.Edges = append(doc.Edges, roadmapdto.EdgeDocument{
+			Prerequisite: keys[edge.PrerequisiteID],
+			Node:         keys[edge.NodeID],
+		})
+	}
+
+	return doc, nil
+}
+
+// errImportDryRun aborts the import transaction of a dry run once the
+// changes are planned.
+var errImportDryRun = errors.New("import dry run")
+
+type ImportRoadmapUseCase struct {
+	documentRepository roadmaprepo.DocumentRepository
+}
+
+func NewImportRoadmapUseCase(documentRepository roadmaprepo.DocumentRepository) *ImportRoadmapUseCase {
+	return &ImportRoadmapUseCase{
+		documentRepository: documentRepository,
+	}
+}
+
+// Execute imports a document in format into the actor's roadmap with the
+// document's slug, creating the roadmap if there is none. The roadmap is
+// made to match the document: nodes are matched by key and resources by
+// normalized URL, so importing the same document again changes nothing.
+// Documents that fail validation are rejected with a *DocumentError before
+// anything is stored. The metadata, graph and resources are stored in one
+// transaction, so a failed import changes nothing.
+func (u *ImportRoadmapUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	data []byte,
+	format string,
+	dryRun bool,
+) (roadmapdto.ImportRoadmapResponse, error) {
+	ownerID, err := actor.id()
+	if err != nil {
+		return roadmapdto.ImportRoadmapResponse{}, err
+	}
+
+	doc, err := decodeRoadmapDocument(data, format)
+	if err != nil {
+		return roadmapdto.ImportRoadmapResponse{}, err
+	}
+
+	response := roadmapdto.ImportRoadmapResponse{DryRun: dryRun}
+	var existing *roadmapentity.Roadmap
+	roadmap, err := u.documentRepository.Import(ctx, ownerID, doc.Roadmap.Slug, func(
+		roadmap *roadmapentity.Roadmap,
+		graph *roadmapentity.Graph,
+		resources []*roadmapentity.Resource,
+	) (*roadmaprepo.ImportChanges, error) {
+		existing = roadmap
+		importChanges := planImport(ownerID, roadmap, graph, resources, doc, &response.Changes)
+		if dryRun {
+			return nil, errImportDryRun
+		}
+		return importChanges, nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errImportDryRun):
+			if existing != nil {
+				roadmapResponse := newRoadmapResponse(existing)
+				response.Roadmap = &roadmapResponse
+			}
+			return response, nil
+		case errors.Is(err, roadmaprepo.ErrSlugTaken):
+			return roadmapdto.ImportRoadmapResponse{}, ErrRoadmapSlugTaken
+		case errors.Is(err, roadmaprepo.ErrNodeIDTaken), errors.Is(err, roadmaprepo.ErrNodeKeyTaken):
+			return roadmapdto.ImportRoadmapResponse{}, ErrNodeExists
+		}
+		return roadmapdto.ImportRoadmapResponse{}, resourceRepositoryError(err)
+	}
+
+	roadmapResponse := newRoadmapResponse(roadmap)
+	response.Roadmap = &roadmapResponse
+	return response, nil
+}
+
+// planImport turns the roadmap, which is nil if there is none yet, into the
+// one described by doc and counts the changes.
+func planImport(
+	ownerID uuid.UUID,
+	roadmap *roadmapentity.Roadmap,
+	graph *roadmapentity.Graph,
+	resources []*roadmapentity.Resource,
+	doc *roadmapdto.RoadmapDocument,
+	changes *roadmapdto.ImportChanges,
+) *roadmaprepo.ImportChanges {
+	importChanges := &roadmaprepo.ImportChanges{}
+	metadata := doc.Roadmap
+	switch {
+	case roadmap == nil:
+		changes.RoadmapCreated = true
+		importChanges.Roadmap = &roadmapentity.Roadmap{
+			ID:          uuid.New(),
+			OwnerID:     ownerID,
+			Title:       metadata.Title,
+			Slug:        metadata.Slug,
+			Description: metadata.Description,
+			Visibility:  metadata.Visibility,
+		}
+	case roadmapMetadataChanged(roadmap, metadata):
+		changes.RoadmapUpdated = true
+		updated := *roadmap
+		updated.Title = metadata.Title
+		updated.Description = metadata.Description
+		updated.Visibility = metadata.Visibility
+		importChanges.Roadmap = &updated
+	}
+
+	target := roadmap
+	if importChanges.Roadmap != nil {
+		target = importChanges.Roadmap
+	}
+
+	var nodeIDs map[string]uuid.UUID
+	importChanges.Graph, nodeIDs = planGraphImport(target.ID, graph, doc, changes)
+	importChanges.Resources = planResourceImport(target.ID, resources, doc, nodeIDs, changes)
+	return importChanges
+}
+
+func roadmapMetadataChanged(roadmap *roadmapentity.Roadmap, metadata roadmapdto.RoadmapMetadataDocument) bool {
+	return roadmap.Title != metadata.Title ||
+		roadmap.Description != metadata.Description ||
+		roadmap.Visibility != metadata.Visibility
+}
+
+// planGraphImport turns the graph into the one described by doc and counts
+// the changes. It returns the ID of every node of doc by key. Existing nodes
+// without a key are matched by their ID, which is what exports use as their
+// key.
+func planGraphImport(
+	roadmapID uuid.UUID,
+	graph *roadmapentity.Graph,
+	doc *roadmapdto.RoadmapDocument,
+	changes *roadmapdto.ImportChanges,
+) (roadmaprepo.GraphChanges, map[string]uuid.UUID) {
+	var graphChanges roadmaprepo.GraphChanges
+
+	byKey := make(map[string]*roadmapentity.Node, len(graph.Nodes))
+	byID := make(map[uuid.UUID]*roadmapentity.Node, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		byID[node.ID] = node
+		if node.Key != "" {
+			byKey[node.Key] = node
+		}
+	}
+
+	nodeIDs := make(map[string]uuid.UUID, len(doc.Nodes))
+	kept := make(map[uuid.UUID]bool, len(doc.Nodes))
+	for _, nodeDoc := range doc.Nodes {
+		existing := byKey[nodeDoc.Key]
+		if existing == nil {
+			if id, err := uuid.Parse(nodeDoc.Key); err == nil && byID[id] != nil && byID[id].Key == "" && !kept[id] {
+				existing = byID[id]
+			}
+		}
+
+		node := &roadmapentity.Node{
+			ID:          uuid.New(),
+			RoadmapID:   roadmapID,
+			Key:         nodeDoc.Key,
+			Title:       nodeDoc.Title,
+			Description: nodeDoc.Description,
+			Type:        nodeDoc.Type,
+			PositionX:   nodeDoc.Position.X,
+			PositionY:   nodeDoc.Position.Y,
+		}
+		if existing == nil {
+			graphChanges.CreateNodes = append(graphChanges.CreateNodes, node)
+			changes.NodesCreated++
+		} else {
+			node.ID, node.RoadmapID, node.CreatedAt = existing.ID, existing.RoadmapID, existing.CreatedAt
+			if nodeChanged(existing, node) {
+				graphChanges.UpdateNodes = append(graphChanges.UpdateNodes, node)
+				changes.NodesUpdated++
+			}
+		}
+		nodeIDs[nodeDoc.Key] = node.ID
+		kept[node.ID] = true
+	}
+
+	for _, node := range graph.Nodes {
+		if !kept[node.ID] {
+			graphChanges.DeleteNodes = append(graphChanges.DeleteNodes, node.ID)
+			changes.NodesDeleted++
+		}
+	}
+
+	wanted := make(map[edgeKey]bool, len(doc.Edges))
+	for _, edge := range doc.Edges {
+		wanted[edgeKey{nodeIDs[edge.Prerequisite], nodeIDs[edge.Node]}] = true
+	}
+
+	existing := make(map[edgeKey]bool, len(graph.Edges))
+	for _, edge := range graph.Edges {
+		key := edgeKey{edge.PrerequisiteID, edge.NodeID}
+		existing[key] = true
+		if wanted[key] {
+			continue
+		}
+		changes.EdgesRemoved++
+		// Edges of deleted nodes go with the node.
+		if kept[edge.PrerequisiteID] && kept[edge.NodeID] {
+			graphChanges.RemoveEdges = append(graphChanges.RemoveEdges, edge)
+		}
+	}
+	for _, edge := range doc.Edges {
+		key := edgeKey{nodeIDs[edge.Prerequisite], nodeIDs[edge.Node]}
+		if existing[key] {
+			continue
+		}
+		graphChanges.AddEdges = append(graphChanges.AddEdges, &roadmapentity.Edge{
+			RoadmapID:      roadmapID,
+			PrerequisiteID: key.prerequisiteID,
+			NodeID:         key.nodeID,
+		})
+		changes.EdgesAdded++
+	}
+
+	return graphChanges, nodeIDs
+}
+
+func nodeChanged(existing, node *roadmapentity.Node) bool {
+	return existing.Key != node.Key ||
+		existing.Title != node.Title ||
+		existing.Description != node.Description ||
+		existing.Type != node.Type ||
+		existing.PositionX != node.PositionX ||
+		existing.PositionY != node.PositionY
+}
+
+// planResourceImport turns the resources of the roadmap into those of doc and
+// counts the changes. nodeIDs maps node keys to the IDs planGraphImport gave
+// them.
+func planResourceImport(
+	roadmapID uuid.UUID,
+	resources []*roadmapentity.Resource,
+	doc *roadmapdto.RoadmapDocument,
+	nodeIDs map[string]uuid.UUID,
+	changes *roadmapdto.ImportChanges,
+) roadmaprepo.ResourceChanges {
+	var plan roadmaprepo.ResourceChanges
+	updated := make(map[uuid.UUID]bool)
+
+	byURL := make(map[string]*roadmapentity.Resource, len(resources))
+	byNode := make(map[uuid.UUID][]*roadmapentity.Resource)
+	for _, resource := range resources {
+		byURL[resource.NormalizedURL] = resource
+		byNode[resource.NodeID] = append(byNode[resource.NodeID], resource)
+	}
+
+	for _, nodeDoc := range doc.Nodes {
+		nodeID := nodeIDs[nodeDoc.Key]
+
+		order := make([]uuid.UUID, 0, len(nodeDoc.Resources))
+		var kept, created []uuid.UUID
+		for _, resourceDoc := range nodeDoc.Resources {
+			// The document has been validated, so the URL is valid.
+			resourceURL, normalizedURL, _ := normalizeResourceURL(resourceDoc.URL)
+			resource := &roadmapentity.Resource{
+				ID:               uuid.New(),
+				RoadmapID:        roadmapID,
+				NodeID:           nodeID,
+				URL:              resourceURL,
+				NormalizedURL:    normalizedURL,
+				Title:            resourceDoc.Title,
+				Kind:             resourceDoc.Kind,
+				IsFree:           resourceDoc.Free,
+				Language:         resourceDoc.Language,
+				EstimatedMinutes: resourceDoc.EstimatedMinutes,
+			}
+
+			existing := byURL[normalizedURL]
+			if existing == nil || existing.NodeID != nodeID {
+				plan.CreateResources = append(plan.CreateResources, resource)
+				created = append(created, resource.ID)
+				order = append(order, resource.ID)
+				changes.ResourcesCreated++
+				continue
+			}
+			delete(byURL, normalizedURL)
+
+			resource.ID, resource.RoadmapID, resource.CreatedAt = existing.ID, existing.RoadmapID, existing.CreatedAt
+			if resourceChanged(existing, resource) {
+				plan.UpdateResources = append(plan.UpdateResources, resource)
+				updated[resource.ID] = true
+				changes.ResourcesUpdated++
+			}
+			kept = append(kept, resource.ID)
+			order = append(order, resource.ID)
+		}
+
+		// Created resources are appended after the kept ones, which keep
+		// their relative order.
+		sort.SliceStable(kept, func(i, j int) bool {
+			return positionOf(byNode[nodeID], kept[i]) < positionOf(byNode[nodeID], kept[j])
+		})
+		appended := append(kept, created...)
+		if slices.Equal(order, appended) {
+			continue
+		}
+		plan.Reorders = append(plan.Reorders, roadmaprepo.ResourceOrder{NodeID: nodeID, ResourceIDs: order})
+		// Kept resources that only move are updated too.
+		for i, id := range order {
+			if id != appended[i] && !updated[id] && !slices.Contains(created, id) {
+				changes.ResourcesUpdated++
+			}
+		}
+	}
+
+	for _, resource := range resources {
+		if _, left := byURL[resource.NormalizedURL]; left {
+			plan.DeleteResources = append(plan.DeleteResources, resource.ID)
+			changes.ResourcesDeleted++
+		}
+	}
+
+	return plan
+}
+
+func positionOf(resources []*roadmapentity.Resource, id uuid.UUID) int {
+	for _, resource := range resources {
+		if resource.ID == id {
+			return resource.Position
+		}
+	}
+	return len(resources)
+}
+
+func resourceChanged(existing, resource *roadmapentity.Resource) bool {
+	return existing.URL != resource.URL ||
+		existing.Title != resource.Title ||
+		existing.Kind != resource.Kind ||
+		existing.IsFree != resource.IsFree ||
+		existing.Language != resource.Language ||
+		!equalMinutes(existing.EstimatedMinutes, resource.EstimatedMinutes)
+}
+
+func equalMinutes(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,361 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+)
+
+func newNodeProgressResponse(nodeID uuid.UUID, progress *roadmapentity.NodeProgress) roadmapdto.NodeProgressResponse {
+	if progress == nil {
+		return roadmapdto.NodeProgressResponse{
+			NodeID: nodeID,
+			Status: roadmapentity.ProgressNotStarted,
+		}
+	}
+
+	updatedAt := progress.UpdatedAt
+	return roadmapdto.NodeProgressResponse{
+		NodeID:                  nodeID,
+		Status:                  progress.Status,
+		PrerequisitesOverridden: progress.PrerequisitesOverridden,
+		StartedAt:               progress.StartedAt,
+		CompletedAt:             progress.CompletedAt,
+		UpdatedAt:               &updatedAt,
+	}
+}
+
+// newProgressResponse lists every node of the graph, including the ones the
+// user has not started.
+func newProgressResponse(
+	roadmapID uuid.UUID,
+	graph *roadmapentity.Graph,
+	progress map[uuid.UUID]*roadmapentity.NodeProgress,
+) roadmapdto.ProgressResponse {
+	response := roadmapdto.ProgressResponse{
+		Summary: summarizeProgress(roadmapID, graph, progress),
+		Nodes:   make([]roadmapdto.NodeProgressResponse, 0, len(graph.Nodes)),
+	}
+	for _, node := range graph.Nodes {
+		response.Nodes = append(response.Nodes, newNodeProgressResponse(node.ID, progress[node.ID]))
+	}
+	return response
+}
+
+func summarizeProgress(
+	roadmapID uuid.UUID,
+	graph *roadmapentity.Graph,
+	progress map[uuid.UUID]*roadmapentity.NodeProgress,
+) roadmapdto.ProgressSummaryResponse {
+	summary := roadmapdto.ProgressSummaryResponse{
+		RoadmapID:  roadmapID,
+		TotalNodes: len(graph.Nodes),
+	}
+
+	var doneWeight, countedWeight int
+	for _, node := range graph.Nodes {
+		weight := roadmapentity.NodeWeight(node.Type)
+
+		status := roadmapentity.ProgressNotStarted
+		if entry, ok := progress[node.ID]; ok {
+			status = entry.Status
+		}
+
+		switch status {
+		case roadmapentity.ProgressDone:
+			summary.Done++
+			doneWeight += weight
+			countedWeight += weight
+		case roadmapentity.ProgressSkipped:
+			summary.Skipped++
+		case roadmapentity.ProgressInProgress:
+			summary.InProgress++
+			countedWeight += weight
+		default:
+			summary.NotStarted++
+			countedWeight += weight
+		}
+	}
+
+	switch {
+	case countedWeight > 0:
+		summary.PercentDone = math.Round(float64(doneWeight)*1000/float64(countedWeight)) / 10
+	case summary.Skipped > 0:
+		summary.PercentDone = 100
+	}
+
+	return summary
+}
+
+// loadProgress returns the graph of the roadmap and the user's progress on
+// it by node ID.
+func loadProgress(
+	ctx context.Context,
+	graphRepository roadmaprepo.GraphRepository,
+	progressRepository roadmaprepo.ProgressRepository,
+	userID uuid.UUID,
+	roadmapID uuid.UUID,
+) (*roadmapentity.Graph, map[uuid.UUID]*roadmapentity.NodeProgress, error) {
+	graph, err := graphRepository.GetGraph(ctx, roadmapID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := progressRepository.ListByRoadmap(ctx, userID, roadmapID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	progress := make(map[uuid.UUID]*roadmapentity.NodeProgress, len(entries))
+	for _, entry := range entries {
+		progress[entry.NodeID] = entry
+	}
+	return graph, progress, nil
+}
+
+type GetProgressUseCase struct {
+	roadmapRepository  roadmaprepo.RoadmapRepository
+	graphRepository    roadmaprepo.GraphRepository
+	progressRepository roadmaprepo.ProgressRepository
+}
+
+func NewGetProgressUseCase(
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	graphRepository roadmaprepo.GraphRepository,
+	progressRepository roadmaprepo.ProgressRepository,
+) *GetProgressUseCase {
+	return &GetProgressUseCase{
+		roadmapRepository:  roadmapRepository,
+		graphRepository:    graphRepository,
+		progressRepository: progressRepository,
+	}
+}
+
+func (u *GetProgressUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+) (roadmapdto.ProgressResponse, error) {
+	roadmap, err := getReadableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return roadmapdto.ProgressResponse{}, err
+	}
+
+	userID, err := actor.id()
+	if err != nil {
+		return roadmapdto.ProgressResponse{}, err
+	}
+
+	graph, progress, err := loadProgress(ctx, u.graphRepository, u.progressRepository, userID, roadmap.ID)
+	if err != nil {
+		return roadmapdto.ProgressResponse{}, err
+	}
+
+	return newProgressResponse(roadmap.ID, graph, progress), nil
+}
+
+type UpdateProgressUseCase struct {
+	roadmapRepository  roadmaprepo.RoadmapRepository
+	progressRepository roadmaprepo.ProgressRepository
+}
+
+func NewUpdateProgressUseCase(
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	progressRepository roadmaprepo.ProgressRepository,
+) *UpdateProgressUseCase {
+	return &UpdateProgressUseCase{
+		roadmapRepository:  roadmapRepository,
+		progressRepository: progressRepository,
+	}
+}
+
+// Execute sets the actor's status on the requested nodes of any roadmap
+// they can read. Prerequisites are checked against the statuses the request
+// leaves behind, so a node and its prerequisites can be marked done
+// together. A *PrerequisitesUnfinishedError rejects the whole request. The
+// check and the save run in one transaction that holds the roadmap lock, so
+// a concurrent graph edit cannot add a prerequisite in between.
+func (u *UpdateProgressUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+	req roadmapdto.UpdateProgressRequest,
+) (roadmapdto.ProgressResponse, error) {
+	roadmap, err := getReadableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return roadmapdto.ProgressResponse{}, err
+	}
+
+	userID, err := actor.id()
+	if err != nil {
+		return roadmapdto.ProgressResponse{}, err
+	}
+
+	var (
+		graph    *roadmapentity.Graph
+		progress map[uuid.UUID]*roadmapentity.NodeProgress
+	)
+	err = u.progressRepository.EditProgress(ctx, userID, roadmap.ID, func(
+		current *roadmapentity.Graph,
+		entries []*roadmapentity.NodeProgress,
+	) ([]*roadmapentity.NodeProgress, error) {
+		graph = current
+		progress = make(map[uuid.UUID]*roadmapentity.NodeProgress, len(entries))
+		for _, entry := range entries {
+			progress[entry.NodeID] = entry
+		}
+		return planProgressUpdate(userID, roadmap.ID, graph, progress, req)
+	})
+	if err != nil {
+		if errors.Is(err, roadmaprepo.ErrRoadmapNotFound) {
+			return roadmapdto.ProgressResponse{}, ErrRoadmapNotFound
+		}
+		return roadmapdto.ProgressResponse{}, err
+	}
+
+	return newProgressResponse(roadmap.ID, graph, progress), nil
+}
+
+// planProgressUpdate validates req against the graph and returns the entries
+// to save, updating progress in place to the state after the save.
+func planProgressUpdate(
+	userID uuid.UUID,
+	roadmapID uuid.UUID,
+	graph *roadmapentity.Graph,
+	progress map[uuid.UUID]*roadmapentity.NodeProgress,
+	req roadmapdto.UpdateProgressRequest,
+) ([]*roadmapentity.NodeProgress, error) {
+	nodes := make(map[uuid.UUID]bool, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		nodes[node.ID] = true
+	}
+
+	statuses := make(map[uuid.UUID]string, len(req.Updates))
+	var order []uuid.UUID
+	for _, update := range req.Updates {
+		if !nodes[update.NodeID] {
+			return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, update.NodeID)
+		}
+		if _, seen := statuses[update.NodeID]; !seen {
+			order = append(order, update.NodeID)
+		}
+		statuses[update.NodeID] = update.Status
+	}
+
+	isFinished := func(nodeID uuid.UUID) bool {
+		if status, ok := statuses[nodeID]; ok {
+			return status == roadmapentity.ProgressDone || status == roadmapentity.ProgressSkipped
+		}
+		entry, ok := progress[nodeID]
+		return ok && entry.IsFinished()
+	}
+
+	prerequisites := graph.Prerequisites()
+	unfinished := make(map[uuid.UUID][]uuid.UUID)
+	for _, nodeID := range order {
+		if statuses[nodeID] != roadmapentity.ProgressDone {
+			continue
+		}
+		for _, prerequisite := range prerequisites[nodeID] {
+			if !isFinished(prerequisite) {
+				unfinished[nodeID] = append(unfinished[nodeID], prerequisite)
+			}
+		}
+	}
+	if len(unfinished) > 0 && !req.OverridePrerequisites {
+		return nil, &PrerequisitesUnfinishedError{Unfinished: unfinished}
+	}
+
+	now := time.Now().UTC()
+	var changes []*roadmapentity.NodeProgress
+	for _, nodeID := range order {
+		entry := progress[nodeID]
+		status := statuses[nodeID]
+		if (entry == nil && status == roadmapentity.ProgressNotStarted) || (entry != nil && entry.Status == status) {
+			continue
+		}
+
+		updated := &roadmapentity.NodeProgress{
+			UserID:    userID,
+			RoadmapID: roadmapID,
+			NodeID:    nodeID,
+		}
+		if entry != nil {
+			copied := *entry
+			updated = &copied
+		}
+		setProgressStatus(updated, status, len(unfinished[nodeID]) > 0, now)
+		changes = append(changes, updated)
+
+		if status == roadmapentity.ProgressNotStarted {
+			delete(progress, nodeID)
+		} else {
+			progress[nodeID] = updated
+		}
+	}
+
+	return changes, nil
+}
+
+// setProgressStatus moves progress to status. The start time is kept once
+// set, so reopening a finished node does not lose when it was first begun.
+func setProgressStatus(progress *roadmapentity.NodeProgress, status string, overridden bool, now time.Time) {
+	progress.Status = status
+	progress.PrerequisitesOverridden = false
+	progress.UpdatedAt = now
+
+	switch status {
+	case roadmapentity.ProgressNotStarted:
+		progress.StartedAt = nil
+		progress.CompletedAt = nil
+	case roadmapentity.ProgressInProgress:
+		if progress.StartedAt == nil {
+			progress.StartedAt = &now
+		}
+		progress.CompletedAt = nil
+	case roadmapentity.ProgressDone:
+		if progress.StartedAt == nil {
+			progress.StartedAt = &now
+		}
+		progress.CompletedAt = &now
+		progress.PrerequisitesOverridden = overridden
+	case roadmapentity.ProgressSkipped:
+		progress.CompletedAt = &now
+	}
+}
+
+type ExportUserProgressUseCase struct {
+	progressRepository roadmaprepo.ProgressRepository
+}
+
+func NewExportUserProgressUseCase(progressRepository roadmaprepo.ProgressRepository) *ExportUserProgressUseCase {
+	return &ExportUserProgressUseCase{
+		progressRepository: progressRepository,
+	}
+}
+
+// Execute returns the user's progress on every roadmap for the account data
+// export. It has the signature of an account export hook.
+func (u *ExportUserProgressUseCase) Execute(ctx context.Context, userID uuid.UUID) (any, error) {
+	progress, err := u.progressRepository.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]roadmapdto.NodeProgressExport, 0, len(progress))
+	for _, entry := range progress {
+		exports = append(exports, roadmapdto.NodeProgressExport{
+			RoadmapID:            entry.RoadmapID,
+			NodeProgressResponse: newNodeProgressResponse(entry.NodeID, entry),
+		})
+	}
+	return exports, nil
+}
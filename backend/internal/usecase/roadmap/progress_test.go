@@ -0,0 +1,350 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+)
+
+type MockProgressRepository struct {
+	mock.Mock
+
+	// Saved holds the entries the last successful EditProgress call returned.
+	Saved []*roadmapentity.NodeProgress
+}
+
+func (m *MockProgressRepository) ListByRoadmap(
+	ctx context.Context,
+	userID uuid.UUID,
+	roadmapID uuid.UUID,
+) ([]*roadmapentity.NodeProgress, error) {
+	args := m.Called(ctx, userID, roadmapID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*roadmapentity.NodeProgress), args.Error(1)
+}
+
+func (m *MockProgressRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*roadmapentity.NodeProgress, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*roadmapentity.NodeProgress), args.Error(1)
+}
+
+// EditProgress runs edit against the graph and progress passed to Return
+// and records the entries it returns in Saved.
+func (m *MockProgressRepository) EditProgress(
+	ctx context.Context,
+	userID, roadmapID uuid.UUID,
+	edit roadmaprepo.ProgressEditFunc,
+) error {
+	args := m.Called(ctx, userID, roadmapID, edit)
+	if err := args.Error(2); err != nil {
+		return err
+	}
+	changes, err := edit(args.Get(0).(*roadmapentity.Graph), args.Get(1).([]*roadmapentity.NodeProgress))
+	if err != nil {
+		return err
+	}
+	m.Saved = changes
+	return nil
+}
+
+type ProgressUseCaseTestSuite struct {
+	suite.Suite
+	roadmapRepo  *MockRoadmapRepository
+	graphRepo    *MockGraphRepository
+	progressRepo *MockProgressRepository
+	learnerID    uuid.UUID
+	learner      Actor
+	roadmap      *roadmapentity.Roadmap
+	graph        *roadmapentity.Graph
+	ctx          context.Context
+}
+
+// SetupTest creates a public roadmap whose graph is the chain basics (topic)
+// -> syntax (subtopic) -> project (milestone), read by a learner who does
+// not own it.
+func (s *ProgressUseCaseTestSuite) SetupTest() {
+	s.roadmapRepo = new(MockRoadmapRepository)
+	s.graphRepo = new(MockGraphRepository)
+	s.progressRepo = new(MockProgressRepository)
+
+	s.learnerID = uuid.New()
+	s.learner = Actor{UserID: s.learnerID.String()}
+	s.roadmap = &roadmapentity.Roadmap{
+		ID:         uuid.New(),
+		OwnerID:    uuid.New(),
+		Title:      "Go",
+		Slug:       "go",
+		Visibility: roadmapentity.VisibilityPublic,
+	}
+
+	s.graph = &roadmapentity.Graph{}
+	for _, nodeType := range []string{roadmapentity.NodeTypeTopic, roadmapentity.NodeTypeSubtopic, roadmapentity.NodeTypeMilestone} {
+		s.graph.Nodes = append(s.graph.Nodes, &roadmapentity.Node{
+			ID:        uuid.New(),
+			RoadmapID: s.roadmap.ID,
+			Title:     nodeType,
+			Type:      nodeType,
+		})
+	}
+	s.graph.Edges = []*roadmapentity.Edge{
+		{RoadmapID: s.roadmap.ID, PrerequisiteID: s.graph.Nodes[0].ID, NodeID: s.graph.Nodes[1].ID},
+		{RoadmapID: s.roadmap.ID, PrerequisiteID: s.graph.Nodes[1].ID, NodeID: s.graph.Nodes[2].ID},
+	}
+	s.ctx = context.Background()
+
+	s.roadmapRepo.On("GetByID", s.ctx, s.roadmap.ID).Return(s.roadmap, nil).Maybe()
+	s.graphRepo.On("GetGraph", s.ctx, s.roadmap.ID).Return(s.graph, nil).Maybe()
+}
+
+func (s *ProgressUseCaseTestSuite) TearDownTest() {
+	s.roadmapRepo.AssertExpectations(s.T())
+	s.graphRepo.AssertExpectations(s.T())
+	s.progressRepo.AssertExpectations(s.T())
+}
+
+func (s *ProgressUseCaseTestSuite) expectProgress(progress ...*roadmapentity.NodeProgress) {
+	s.progressRepo.On("ListByRoadmap", s.ctx, s.learnerID, s.roadmap.ID).Return(progress, nil)
+}
+
+// expectEdit makes EditProgress run against the suite's graph and the given
+// progress.
+func (s *ProgressUseCaseTestSuite) expectEdit(progress ...*roadmapentity.NodeProgress) {
+	s.progressRepo.On("EditProgress", s.ctx, s.learnerID, s.roadmap.ID, mock.Anything).
+		Return(s.graph, progress, nil)
+}
+
+func (s *ProgressUseCaseTestSuite) progress(node int, status string) *roadmapentity.NodeProgress {
+	startedAt := time.Now().UTC().Add(-time.Hour)
+	return &roadmapentity.NodeProgress{
+		UserID:    s.learnerID,
+		RoadmapID: s.roadmap.ID,
+		NodeID:    s.graph.Nodes[node].ID,
+		Status:    status,
+		StartedAt: &startedAt,
+		UpdatedAt: startedAt,
+	}
+}
+
+func (s *ProgressUseCaseTestSuite) update(req roadmapdto.UpdateProgressRequest) (roadmapdto.ProgressResponse, error) {
+	return NewUpdateProgressUseCase(s.roadmapRepo, s.progressRepo).
+		Execute(s.ctx, s.learner, s.roadmap.ID.String(), req)
+}
+
+func (s *ProgressUseCaseTestSuite) TestGet_DefaultsToNotStarted() {
+	s.expectProgress(s.progress(0, roadmapentity.ProgressInProgress))
+
+	response, err := NewGetProgressUseCase(s.roadmapRepo, s.graphRepo, s.progressRepo).
+		Execute(s.ctx, s.learner, s.roadmap.ID.String())
+
+	s.Require().NoError(err)
+	s.Require().Len(response.Nodes, 3)
+	s.Equal(roadmapentity.ProgressInProgress, response.Nodes[0].Status)
+	s.Equal(roadmapentity.ProgressNotStarted, response.Nodes[1].Status)
+	s.Nil(response.Nodes[1].UpdatedAt)
+	s.Equal(3, response.Summary.TotalNodes)
+	s.Equal(1, response.Summary.InProgress)
+	s.Equal(2, response.Summary.NotStarted)
+	s.Zero(response.Summary.PercentDone)
+}
+
+func (s *ProgressUseCaseTestSuite) TestGet_PrivateRoadmap() {
+	s.roadmap.Visibility = roadmapentity.VisibilityPrivate
+
+	_, err := NewGetProgressUseCase(s.roadmapRepo, s.graphRepo, s.progressRepo).
+		Execute(s.ctx, s.learner, s.roadmap.ID.String())
+
+	s.ErrorIs(err, ErrRoadmapNotFound)
+}
+
+func (s *ProgressUseCaseTestSuite) TestUpdate_MarksChainDoneTogether() {
+	s.expectEdit(s.progress(0, roadmapentity.ProgressInProgress))
+
+	response, err := s.update(roadmapdto.UpdateProgressRequest{
+		Updates: []roadmapdto.NodeStatusUpdate{
+			{NodeID: s.graph.Nodes[1].ID, Status: roadmapentity.ProgressDone},
+			{NodeID: s.graph.Nodes[0].ID, Status: roadmapentity.ProgressDone},
+		},
+	})
+
+	s.Require().NoError(err)
+	saved := s.progressRepo.Saved
+	s.Require().Len(saved, 2)
+	for _, entry := range saved {
+		s.Equal(roadmapentity.ProgressDone, entry.Status)
+		s.NotNil(entry.StartedAt)
+		s.NotNil(entry.CompletedAt)
+		s.False(entry.PrerequisitesOverridden)
+	}
+	s.True(saved[1].StartedAt.Before(*saved[1].CompletedAt), "start time of basics is kept")
+	s.Equal(2, response.Summary.Done)
+	// Topic (2) and subtopic (1) of 6 weighted points.
+	s.Equal(50.0, response.Summary.PercentDone)
+}
+
+func (s *ProgressUseCaseTestSuite) TestUpdate_UnfinishedPrerequisites() {
+	s.expectEdit(s.progress(0, roadmapentity.ProgressInProgress))
+
+	_, err := s.update(roadmapdto.UpdateProgressRequest{
+		Updates: []roadmapdto.NodeStatusUpdate{
+			{NodeID: s.graph.Nodes[1].ID, Status: roadmapentity.ProgressDone},
+		},
+	})
+
+	var unfinishedErr *PrerequisitesUnfinishedError
+	s.Require().ErrorAs(err, &unfinishedErr)
+	s.Equal(map[uuid.UUID][]uuid.UUID{s.graph.Nodes[1].ID: {s.graph.Nodes[0].ID}}, unfinishedErr.Unfinished)
+	s.Empty(s.progressRepo.Saved)
+}
+
+func (s *ProgressUseCaseTestSuite) TestUpdate_OverridePrerequisites() {
+	s.expectEdit()
+
+	response, err := s.update(roadmapdto.UpdateProgressRequest{
+		Updates: []roadmapdto.NodeStatusUpdate{
+			{NodeID: s.graph.Nodes[2].ID, Status: roadmapentity.ProgressDone},
+			{NodeID: s.graph.Nodes[0].ID, Status: roadmapentity.ProgressDone},
+		},
+		OverridePrerequisites: true,
+	})
+
+	s.Require().NoError(err)
+	saved := s.progressRepo.Saved
+	s.Require().Len(saved, 2)
+	s.True(saved[0].PrerequisitesOverridden)
+	s.False(saved[1].PrerequisitesOverridden)
+	s.True(response.Nodes[2].PrerequisitesOverridden)
+	// Milestone (3) and topic (2) of 6 weighted points.
+	s.Equal(83.3, response.Summary.PercentDone)
+}
+
+func (s *ProgressUseCaseTestSuite) TestUpdate_SkippedPrerequisitesAreFinished() {
+	s.expectEdit(s.progress(0, roadmapentity.ProgressSkipped))
+
+	response, err := s.update(roadmapdto.UpdateProgressRequest{
+		Updates: []roadmapdto.NodeStatusUpdate{
+			{NodeID: s.graph.Nodes[1].ID, Status: roadmapentity.ProgressDone},
+		},
+	})
+
+	s.Require().NoError(err)
+	s.Equal(1, response.Summary.Skipped)
+	// Subtopic (1) of the 4 weighted points that are not skipped.
+	s.Equal(25.0, response.Summary.PercentDone)
+}
+
+func (s *ProgressUseCaseTestSuite) TestUpdate_ResetAndUnchanged() {
+	s.expectEdit(s.progress(0, roadmapentity.ProgressDone), s.progress(1, roadmapentity.ProgressInProgress))
+
+	response, err := s.update(roadmapdto.UpdateProgressRequest{
+		Updates: []roadmapdto.NodeStatusUpdate{
+			{NodeID: s.graph.Nodes[0].ID, Status: roadmapentity.ProgressDone},
+			{NodeID: s.graph.Nodes[1].ID, Status: roadmapentity.ProgressNotStarted},
+			{NodeID: s.graph.Nodes[2].ID, Status: roadmapentity.ProgressNotStarted},
+		},
+	})
+
+	s.Require().NoError(err)
+	saved := s.progressRepo.Saved
+	s.Require().Len(saved, 1)
+	s.Equal(s.graph.Nodes[1].ID, saved[0].NodeID)
+	s.Equal(roadmapentity.ProgressNotStarted, saved[0].Status)
+	s.Nil(saved[0].StartedAt)
+	s.Equal(roadmapentity.ProgressNotStarted, response.Nodes[1].Status)
+	s.Nil(response.Nodes[1].StartedAt)
+}
+
+func (s *ProgressUseCaseTestSuite) TestUpdate_LastEntryWins() {
+	s.expectEdit()
+
+	_, err := s.update(roadmapdto.UpdateProgressRequest{
+		Updates: []roadmapdto.NodeStatusUpdate{
+			{NodeID: s.graph.Nodes[0].ID, Status: roadmapentity.ProgressDone},
+			{NodeID: s.graph.Nodes[0].ID, Status: roadmapentity.ProgressInProgress},
+		},
+	})
+
+	s.Require().NoError(err)
+	saved := s.progressRepo.Saved
+	s.Require().Len(saved, 1)
+	s.Equal(roadmapentity.ProgressInProgress, saved[0].Status)
+	s.Nil(saved[0].CompletedAt)
+}
+
+func (s *ProgressUseCaseTestSuite) TestUpdate_UnknownNode() {
+	s.expectEdit()
+
+	_, err := s.update(roadmapdto.UpdateProgressRequest{
+		Updates: []roadmapdto.NodeStatusUpdate{
+			{NodeID: uuid.New(), Status: roadmapentity.ProgressDone},
+		},
+	})
+
+	s.ErrorIs(err, ErrNodeNotFound)
+}
+
+func (s *ProgressUseCaseTestSuite) TestUpdate_SaveError() {
+	s.progressRepo.On("EditProgress", s.ctx, s.learnerID, s.roadmap.ID, mock.Anything).
+		Return(nil, nil, errors.New("database error"))
+
+	_, err := s.update(roadmapdto.UpdateProgressRequest{
+		Updates: []roadmapdto.NodeStatusUpdate{
+			{NodeID: s.graph.Nodes[0].ID, Status: roadmapentity.ProgressInProgress},
+		},
+	})
+
+	s.Error(err)
+}
+
+func (s *ProgressUseCaseTestSuite) TestUpdate_RoadmapDeletedConcurrently() {
+	s.progressRepo.On("EditProgress", s.ctx, s.learnerID, s.roadmap.ID, mock.Anything).
+		Return(nil, nil, roadmaprepo.ErrRoadmapNotFound)
+
+	_, err := s.update(roadmapdto.UpdateProgressRequest{
+		Updates: []roadmapdto.NodeStatusUpdate{
+			{NodeID: s.graph.Nodes[0].ID, Status: roadmapentity.ProgressInProgress},
+		},
+	})
+
+	s.ErrorIs(err, ErrRoadmapNotFound)
+}
+
+func (s *ProgressUseCaseTestSuite) TestSummary_AllSkipped() {
+	progress := make(map[uuid.UUID]*roadmapentity.NodeProgress)
+	for i := range s.graph.Nodes {
+		progress[s.graph.Nodes[i].ID] = s.progress(i, roadmapentity.ProgressSkipped)
+	}
+
+	s.Equal(100.0, summarizeProgress(s.roadmap.ID, s.graph, progress).PercentDone)
+	s.Zero(summarizeProgress(s.roadmap.ID, &roadmapentity.Graph{}, nil).PercentDone)
+}
+
+func (s *ProgressUseCaseTestSuite) TestExport() {
+	entry := s.progress(0, roadmapentity.ProgressDone)
+	s.progressRepo.On("ListByUser", s.ctx, s.learnerID).Return([]*roadmapentity.NodeProgress{entry}, nil)
+
+	export, err := NewExportUserProgressUseCase(s.progressRepo).Execute(s.ctx, s.learnerID)
+
+	s.Require().NoError(err)
+	exports := export.([]roadmapdto.NodeProgressExport)
+	s.Require().Len(exports, 1)
+	s.Equal(s.roadmap.ID, exports[0].RoadmapID)
+	s.Equal(entry.NodeID, exports[0].NodeID)
+}
+
+func TestProgressUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ProgressUseCaseTestSuite))
+}
@@ -0,0 +1,686 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+)
+
+// trackingParameters are removed from resource URLs along with every utm_
+// parameter; they differ between shares of the same page.
+var trackingParameters = map[string]bool{
+	"fbclid": true,
+	"gclid":  true,
+	"mc_cid": true,
+	"mc_eid": true,
+}
+
+// normalizeResourceURL returns the URL to store for raw and the normalized
+// form used to find duplicates. The stored URL has a lowercase host, no
+// default port and no tracking parameters. The normalized form further
+// ignores the scheme, a leading www., trailing slashes, the order of the
+// query parameters and the fragment.
+func normalizeResourceURL(raw string) (string, string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.User != nil {
+		return "", "", ErrInvalidResourceURL
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if host == "" {
+		return "", "", ErrInvalidResourceURL
+	}
+	port := parsed.Port()
+	if (parsed.Scheme == "http" && port == "80") || (parsed.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	switch {
+	case port != "":
+		parsed.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		parsed.Host = "[" + host + "]"
+	default:
+		parsed.Host = host
+	}
+
+	query := parsed.Query()
+	var tracked bool
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParameters[lower] {
+			query.Del(key)
+			tracked = true
+		}
+	}
+	if tracked {
+		parsed.RawQuery = query.Encode()
+	}
+
+	normalized := *parsed
+	normalized.Scheme = "https"
+	normalized.Host = strings.TrimPrefix(parsed.Host, "www.")
+	normalized.Path = strings.TrimRight(parsed.Path, "/")
+	normalized.RawPath = ""
+	normalized.RawQuery = query.Encode()
+	normalized.Fragment = ""
+	normalized.RawFragment = ""
+	normalized.ForceQuery = false
+
+	return parsed.String(), normalized.String(), nil
+}
+
+func newResourceResponse(
+	resource *roadmapentity.Resource,
+	completion *roadmapentity.ResourceCompletion,
+) roadmapdto.ResourceResponse {
+	response := roadmapdto.ResourceResponse{
+		ID:               resource.ID,
+		NodeID:           resource.NodeID,
+		URL:              resource.URL,
+		Title:            resource.Title,
+		Kind:             resource.Kind,
+		IsFree:           resource.IsFree,
+		Language:         resource.Language,
+		EstimatedMinutes: resource.EstimatedMinutes,
+		Position:         resource.Position,
+		CreatedAt:        resource.CreatedAt,
+		UpdatedAt:        resource.UpdatedAt,
+	}
+	if completion != nil {
+		completedAt := completion.CompletedAt
+		response.Completed = true
+		response.CompletedAt = &completedAt
+	}
+	return response
+}
+
+// loadCompletions returns the resources of the roadmap the user has
+// completed by resource ID.
+func loadCompletions(
+	ctx context.Context,
+	resourceRepository roadmaprepo.ResourceRepository,
+	userID uuid.UUID,
+	roadmapID uuid.UUID,
+) (map[uuid.UUID]*roadmapentity.ResourceCompletion, error) {
+	completions, err := resourceRepository.ListCompletionsByRoadmap(ctx, userID, roadmapID)
+	if err != nil {
+		return nil, err
+	}
+
+	byResource := make(map[uuid.UUID]*roadmapentity.ResourceCompletion, len(completions))
+	for _, completion := range completions {
+		byResource[completion.ResourceID] = completion
+	}
+	return byResource, nil
+}
+
+// getRoadmapResource loads a resource of the roadmap. Resources of other
+// roadmaps are reported as not found.
+func getRoadmapResource(
+	ctx context.Context,
+	resourceRepository roadmaprepo.ResourceRepository,
+	roadmap *roadmapentity.Roadmap,
+	resourceID string,
+) (*roadmapentity.Resource, error) {
+	id, err := uuid.Parse(resourceID)
+	if err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	resource, err := resourceRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, roadmaprepo.ErrResourceNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	if resource.RoadmapID != roadmap.ID {
+		return nil, ErrResourceNotFound
+	}
+
+	return resource, nil
+}
+
+func resourceRepositoryError(err error) error {
+	switch {
+	case errors.Is(err, roadmaprepo.ErrResourceURLTaken):
+		return ErrResourceURLTaken
+	case errors.Is(err, roadmaprepo.ErrResourceNotFound):
+		return ErrResourceNotFound
+	case errors.Is(err, roadmaprepo.ErrNodeNotFound):
+		return ErrNodeNotFound
+	}
+	return err
+}
+
+type ListResourcesUseCase struct {
+	roadmapRepository  roadmaprepo.RoadmapRepository
+	resourceRepository roadmaprepo.ResourceRepository
+}
+
+func NewListResourcesUseCase(
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	resourceRepository roadmaprepo.ResourceRepository,
+) *ListResourcesUseCase {
+	return &ListResourcesUseCase{
+		roadmapRepository:  roadmapRepository,
+		resourceRepository: resourceRepository,
+	}
+}
+
+func (u *ListResourcesUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+	req roadmapdto.ListResourcesRequest,
+) (roadmapdto.ListResourcesResponse, error) {
+	roadmap, err := getReadableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return roadmapdto.ListResourcesResponse{}, err
+	}
+
+	userID, err := actor.id()
+	if err != nil {
+		return roadmapdto.ListResourcesResponse{}, err
+	}
+
+	var resources []*roadmapentity.Resource
+	if req.NodeID != "" {
+		nodeID, parseErr := uuid.Parse(req.NodeID)
+		if parseErr != nil {
+			return roadmapdto.ListResourcesResponse{}, ErrNodeNotFound
+		}
+		resources, err = u.resourceRepository.ListByNode(ctx, nodeID)
+	} else {
+		resources, err = u.resourceRepository.ListByRoadmap(ctx, roadmap.ID)
+	}
+	if err != nil {
+		return roadmapdto.ListResourcesResponse{}, err
+	}
+
+	completions, err := loadCompletions(ctx, u.resourceRepository, userID, roadmap.ID)
+	if err != nil {
+		return roadmapdto.ListResourcesResponse{}, err
+	}
+
+	response := roadmapdto.ListResourcesResponse{
+		Resources: make([]roadmapdto.ResourceResponse, 0, len(resources)),
+	}
+	for _, resource := range resources {
+		if resource.RoadmapID != roadmap.ID {
+			continue
+		}
+		response.Resources = append(response.Resources, newResourceResponse(resource, completions[resource.ID]))
+	}
+	return response, nil
+}
+
+type CreateResourceUseCase struct {
+	roadmapRepository  roadmaprepo.RoadmapRepository
+	resourceRepository roadmaprepo.ResourceRepository
+}
+
+func NewCreateResourceUseCase(
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	resourceRepository roadmaprepo.ResourceRepository,
+) *CreateResourceUseCase {
+	return &CreateResourceUseCase{
+		roadmapRepository:  roadmapRepository,
+		resourceRepository: resourceRepository,
+	}
+}
+
+func (u *CreateResourceUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+	req roadmapdto.CreateResourceRequest,
+) (roadmapdto.ResourceResponse, error) {
+	roadmap, err := getEditableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return roadmapdto.ResourceResponse{}, err
+	}
+
+	resourceURL, normalizedURL, err := normalizeResourceURL(req.URL)
+	if err != nil {
+		return roadmapdto.ResourceResponse{}, err
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return roadmapdto.ResourceResponse{}, ErrBlankTitle
+	}
+
+	isFree := true
+	if req.IsFree != nil {
+		isFree = *req.IsFree
+	}
+
+	resource, err := u.resourceRepository.Create(ctx, &roadmapentity.Resource{
+		ID:               uuid.New(),
+		RoadmapID:        roadmap.ID,
+		NodeID:           req.NodeID,
+		URL:              resourceURL,
+		NormalizedURL:    normalizedURL,
+		Title:            title,
+		Kind:             req.Kind,
+		IsFree:           isFree,
+		Language:         req.Language,
+		EstimatedMinutes: req.EstimatedMinutes,
+	})
+	if err != nil {
+		return roadmapdto.ResourceResponse{}, resourceRepositoryError(err)
+	}
+
+	return newResourceResponse(resource, nil), nil
+}
+
+type UpdateResourceUseCase struct {
+	roadmapRepository  roadmaprepo.RoadmapRepository
+	resourceRepository roadmaprepo.ResourceRepository
+}
+
+func NewUpdateResourceUseCase(
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	resourceRepository roadmaprepo.ResourceRepository,
+) *UpdateResourceUseCase {
+	return &UpdateResourceUseCase{
+		roadmapRepository:  roadmapRepository,
+		resourceRepository: resourceRepository,
+	}
+}
+
+func (u *UpdateResourceUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+	resourceID string,
+	req roadmapdto.UpdateResourceRequest,
+) (roadmapdto.ResourceResponse, error) {
+	roadmap, err := getEditableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return roadmapdto.ResourceResponse{}, err
+	}
+
+	userID, err := actor.id()
+	if err != nil {
+		return roadmapdto.ResourceResponse{}, err
+	}
+
+	resource, err := getRoadmapResource(ctx, u.resourceRepository, roadmap, resourceID)
+	if err != nil {
+		return roadmapdto.ResourceResponse{}, err
+	}
+
+	if req.URL != nil {
+		resource.URL, resource.NormalizedURL, err = normalizeResourceURL(*req.URL)
+		if err != nil {
+			return roadmapdto.ResourceResponse{}, err
+		}
+	}
+	if req.Title != nil {
+		resource.Title = strings.TrimSpace(*req.Title)
+		if resource.Title == "" {
+			return roadmapdto.ResourceResponse{}, ErrBlankTitle
+		}
+	}
+	if req.Kind != nil {
+		resource.Kind = *req.Kind
+	}
+	if req.IsFree != nil {
+		resource.IsFree = *req.IsFree
+	}
+	if req.Language != nil {
+		resource.Language = *req.Language
+	}
+	if req.EstimatedMinutes != nil {
+		resource.EstimatedMinutes = req.EstimatedMinutes
+		if *req.EstimatedMinutes == 0 {
+			resource.EstimatedMinutes = nil
+		}
+	}
+
+	updated, err := u.resourceRepository.Update(ctx, resource)
+	if err != nil {
+		return roadmapdto.ResourceResponse{}, resourceRepositoryError(err)
+	}
+
+	completions, err := loadCompletions(ctx, u.resourceRepository, userID, roadmap.ID)
+	if err != nil {
+		return roadmapdto.ResourceResponse{}, err
+	}
+
+	return newResourceResponse(updated, completions[updated.ID]), nil
+}
+
+type DeleteResourceUseCase struct {
+	roadmapRepository  roadmaprepo.RoadmapRepository
+	resourceRepository roadmaprepo.ResourceRepository
+}
+
+func NewDeleteResourceUseCase(
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	resourceRepository roadmaprepo.ResourceRepository,
+) *DeleteResourceUseCase {
+	return &DeleteResourceUseCase{
+		roadmapRepository:  roadmapRepository,
+		resourceRepository: resourceRepository,
+	}
+}
+
+func (u *DeleteResourceUseCase) Execute(ctx context.Context, actor Actor, roadmapID string, resourceID string) error {
+	roadmap, err := getEditableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return err
+	}
+
+	resource, err := getRoadmapResource(ctx, u.resourceRepository, roadmap, resourceID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.resourceRepository.Delete(ctx, resource.ID); err != nil {
+		return resourceRepositoryError(err)
+	}
+
+	return nil
+}
+
+type ReorderResourcesUseCase struct {
+	roadmapRepository  roadmaprepo.RoadmapRepository
+	resourceRepository roadmaprepo.ResourceRepository
+}
+
+func NewReorderResourcesUseCase(
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	resourceRepository roadmaprepo.ResourceRepository,
+) *ReorderResourcesUseCase {
+	return &ReorderResourcesUseCase{
+		roadmapRepository:  roadmapRepository,
+		resourceRepository: resourceRepository,
+	}
+}
+
+// Execute puts the resources of a node in the requested order, which must
+// list each of them exactly once.
+func (u *ReorderResourcesUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+	req roadmapdto.ReorderResourcesRequest,
+) (roadmapdto.ListResourcesResponse, error) {
+	roadmap, err := getEditableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return roadmapdto.ListResourcesResponse{}, err
+	}
+
+	userID, err := actor.id()
+	if err != nil {
+		return roadmapdto.ListResourcesResponse{}, err
+	}
+
+	resources, err := u.resourceRepository.ListByNode(ctx, req.NodeID)
+	if err != nil {
+		return roadmapdto.ListResourcesResponse{}, err
+	}
+
+	byID := make(map[uuid.UUID]*roadmapentity.Resource, len(resources))
+	for _, resource := range resources {
+		if resource.RoadmapID != roadmap.ID {
+			return roadmapdto.ListResourcesResponse{}, ErrNodeNotFound
+		}
+		byID[resource.ID] = resource
+	}
+
+	if len(req.ResourceIDs) != len(resources) {
+		return roadmapdto.ListResourcesResponse{}, ErrInvalidOrder
+	}
+	ordered := make([]*roadmapentity.Resource, 0, len(resources))
+	for position, id := range req.ResourceIDs {
+		resource, ok := byID[id]
+		if !ok {
+			return roadmapdto.ListResourcesResponse{}, ErrInvalidOrder
+		}
+		delete(byID, id)
+		resource.Position = position
+		ordered = append(ordered, resource)
+	}
+
+	if err := u.resourceRepository.Reorder(ctx, req.NodeID, req.ResourceIDs); err != nil {
+		return roadmapdto.ListResourcesResponse{}, err
+	}
+
+	completions, err := loadCompletions(ctx, u.resourceRepository, userID, roadmap.ID)
+	if err != nil {
+		return roadmapdto.ListResourcesResponse{}, err
+	}
+
+	response := roadmapdto.ListResourcesResponse{
+		Resources: make([]roadmapdto.ResourceResponse, 0, len(ordered)),
+	}
+	for _, resource := range ordered {
+		response.Resources = append(response.Resources, newResourceResponse(resource, completions[resource.ID]))
+	}
+	return response, nil
+}
+
+type SetResourceCompletionUseCase struct {
+	roadmapRepository  roadmaprepo.RoadmapRepository
+	graphRepository    roadmaprepo.GraphRepository
+	resourceRepository roadmaprepo.ResourceRepository
+	progressRepository roadmaprepo.ProgressRepository
+}
+
+func NewSetResourceCompletionUseCase(
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	graphRepository roadmaprepo.GraphRepository,
+	resourceRepository roadmaprepo.ResourceRepository,
+	progressRepository roadmaprepo.ProgressRepository,
+) *SetResourceCompletionUseCase {
+	return &SetResourceCompletionUseCase{
+		roadmapRepository:  roadmapRepository,
+		graphRepository:    graphRepository,
+		resourceRepository: resourceRepository,
+		progressRepository: progressRepository,
+	}
+}
+
+// Execute marks a resource as completed by the actor, or unmarks it.
+// Completing a resource starts its node if it was not started, and
+// completing the last resource of a node marks the node done once its
+// prerequisites are finished. Unmarking a resource leaves the node as is.
+func (u *SetResourceCompletionUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+	resourceID string,
+	completed bool,
+) (roadmapdto.ResourceCompletionResponse, error) {
+	roadmap, err := getReadableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return roadmapdto.ResourceCompletionResponse{}, err
+	}
+
+	userID, err := actor.id()
+	if err != nil {
+		return roadmapdto.ResourceCompletionResponse{}, err
+	}
+
+	resource, err := getRoadmapResource(ctx, u.resourceRepository, roadmap, resourceID)
+	if err != nil {
+		return roadmapdto.ResourceCompletionResponse{}, err
+	}
+
+	now := time.Now().UTC()
+	var completedAt *time.Time
+	if completed {
+		completedAt = &now
+	}
+	if err := u.resourceRepository.SetCompleted(ctx, userID, resource.ID, completedAt); err != nil {
+		return roadmapdto.ResourceCompletionResponse{}, resourceRepositoryError(err)
+	}
+
+	completions, err := loadCompletions(ctx, u.resourceRepository, userID, roadmap.ID)
+	if err != nil {
+		return roadmapdto.ResourceCompletionResponse{}, err
+	}
+
+	var entry *roadmapentity.NodeProgress
+	if completed {
+		entry, err = u.advanceNode(ctx, userID, roadmap.ID, resource.NodeID, completions, now)
+		if err != nil {
+			return roadmapdto.ResourceCompletionResponse{}, err
+		}
+	} else {
+		_, progress, err := loadProgress(ctx, u.graphRepository, u.progressRepository, userID, roadmap.ID)
+		if err != nil {
+			return roadmapdto.ResourceCompletionResponse{}, err
+		}
+		entry = progress[resource.NodeID]
+	}
+
+	return roadmapdto.ResourceCompletionResponse{
+		Resource: newResourceResponse(resource, completions[resource.ID]),
+		Node:     newNodeProgressResponse(resource.NodeID, entry),
+	}, nil
+}
+
+// advanceNode moves the node forward after one of its resources has been
+// completed and returns its progress. The prerequisites are checked and the
+// progress saved through EditProgress, so a concurrent graph or progress
+// edit cannot change them in between.
+func (u *SetResourceCompletionUseCase) advanceNode(
+	ctx context.Context,
+	userID uuid.UUID,
+	roadmapID uuid.UUID,
+	nodeID uuid.UUID,
+	completions map[uuid.UUID]*roadmapentity.ResourceCompletion,
+	now time.Time,
+) (*roadmapentity.NodeProgress, error) {
+	resources, err := u.resourceRepository.ListByNode(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	allCompleted := true
+	for _, resource := range resources {
+		if completions[resource.ID] == nil {
+			allCompleted = false
+			break
+		}
+	}
+
+	var entry *roadmapentity.NodeProgress
+	err = u.progressRepository.EditProgress(ctx, userID, roadmapID, func(
+		graph *roadmapentity.Graph,
+		entries []*roadmapentity.NodeProgress,
+	) ([]*roadmapentity.NodeProgress, error) {
+		progress := make(map[uuid.UUID]*roadmapentity.NodeProgress, len(entries))
+		for _, entry := range entries {
+			progress[entry.NodeID] = entry
+		}
+
+		entry = progress[nodeID]
+		updated := planNodeAdvance(userID, roadmapID, nodeID, graph, progress, allCompleted, now)
+		if updated == nil {
+			return nil, nil
+		}
+		entry = updated
+		return []*roadmapentity.NodeProgress{updated}, nil
+	})
+	if err != nil {
+		if errors.Is(err, roadmaprepo.ErrRoadmapNotFound) {
+			return nil, ErrRoadmapNotFound
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// planNodeAdvance returns the new progress of the node, or nil if it stays
+// as is. A node is started by its first completed resource and done once
+// all its resources are completed and its prerequisites are finished.
+func planNodeAdvance(
+	userID uuid.UUID,
+	roadmapID uuid.UUID,
+	nodeID uuid.UUID,
+	graph *roadmapentity.Graph,
+	progress map[uuid.UUID]*roadmapentity.NodeProgress,
+	allCompleted bool,
+	now time.Time,
+) *roadmapentity.NodeProgress {
+	entry := progress[nodeID]
+	if entry != nil && entry.IsFinished() {
+		return nil
+	}
+
+	prerequisitesFinished := true
+	for _, prerequisite := range graph.Prerequisites()[nodeID] {
+		if p := progress[prerequisite]; p == nil || !p.IsFinished() {
+			prerequisitesFinished = false
+			break
+		}
+	}
+
+	var status string
+	switch {
+	case allCompleted && prerequisitesFinished:
+		status = roadmapentity.ProgressDone
+	case entry == nil:
+		status = roadmapentity.ProgressInProgress
+	default:
+		return nil
+	}
+
+	updated := &roadmapentity.NodeProgress{
+		UserID:    userID,
+		RoadmapID: roadmapID,
+		NodeID:    nodeID,
+	}
+	if entry != nil {
+		copied := *entry
+		updated = &copied
+	}
+	setProgressStatus(updated, status, false, now)
+	return updated
+}
+
+type ExportUserResourceCompletionsUseCase struct {
+	resourceRepository roadmaprepo.ResourceRepository
+}
+
+func NewExportUserResourceCompletionsUseCase(
+	resourceRepository roadmaprepo.ResourceRepository,
+) *ExportUserResourceCompletionsUseCase {
+	return &ExportUserResourceCompletionsUseCase{
+		resourceRepository: resourceRepository,
+	}
+}
+
+// Execute returns the resources the user has completed for the account data
+// export. It has the signature of an account export hook.
+func (u *ExportUserResourceCompletionsUseCase) Execute(ctx context.Context, userID uuid.UUID) (any, error) {
+	completions, err := u.resourceRepository.ListCompletionsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]roadmapdto.ResourceCompletionExport, 0, len(completions))
+	for _, completion := range completions {
+		exports = append(exports, roadmapdto.ResourceCompletionExport{
+			ResourceID:  completion.ResourceID,
+			RoadmapID:   completion.RoadmapID,
+			NodeID:      completion.NodeID,
+			CompletedAt: completion.CompletedAt,
+		})
+	}
+	return exports, nil
+}
@@ -0,0 +1,411 @@
+package roadmap
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+)
+
+const (
+	// maxSlugLength matches the roadmaps.slug column.
+	maxSlugLength = 100
+
+	// maxDerivedSlugSuffix bounds the numbered suffixes tried for a slug
+	// derived from a title before falling back to a random one.
+	maxDerivedSlugSuffix = 20
+)
+
+var (
+	slugPattern   = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Actor is the authenticated user a roadmap use case runs for. CanModerate
+// is set for holders of the roadmaps:moderate permission, who can read and
+// change every roadmap.
+type Actor struct {
+	UserID      string
+	CanModerate bool
+}
+
+func (a Actor) id() (uuid.UUID, error) {
+	id, err := uuid.Parse(a.UserID)
+	if err != nil {
+		return uuid.Nil, ErrInvalidTokenClaims
+	}
+	return id, nil
+}
+
+func newRoadmapResponse(roadmap *roadmapentity.Roadmap) roadmapdto.RoadmapResponse {
+	return roadmapdto.RoadmapResponse{
+		ID:          roadmap.ID,
+		OwnerID:     roadmap.OwnerID,
+		Title:       roadmap.Title,
+		Slug:        roadmap.Slug,
+		Description: roadmap.Description,
+		Visibility:  roadmap.Visibility,
+		CreatedAt:   roadmap.CreatedAt,
+		UpdatedAt:   roadmap.UpdatedAt,
+	}
+}
+
+// slugify derives a slug from a title by keeping ASCII letters and digits
+// and joining the runs in between with single hyphens.
+func slugify(title string) string {
+	slug := strings.Trim(slugSeparator.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	if slug == "" {
+		return "roadmap"
+	}
+	return slug
+}
+
+// derivedSlugCandidate returns the slug to try on the given attempt for a
+// slug derived from a title: the slug itself, then the slug with -2, -3 and
+// so on appended, and finally a random suffix. The suffix always fits
+// within maxSlugLength.
+func derivedSlugCandidate(base string, attempt int) string {
+	var suffix string
+	switch {
+	case attempt == 1:
+		return base
+	case attempt <= maxDerivedSlugSuffix:
+		suffix = "-" + strconv.Itoa(attempt)
+	default:
+		suffix = "-" + strings.ReplaceAll(uuid.NewString(), "-", "")[:8]
+	}
+	if len(base)+len(suffix) > maxSlugLength {
+		base = strings.TrimRight(base[:maxSlugLength-len(suffix)], "-")
+	}
+	return base + suffix
+}
+
+func validateSlug(slug string) error {
+	if len(slug) > maxSlugLength || !slugPattern.MatchString(slug) {
+		return ErrInvalidSlug
+	}
+	return nil
+}
+
+// getReadableRoadmap loads the roadmap if the actor may see it. Private
+// roadmaps of other users are reported as not found so their existence is
+// not revealed.
+func getReadableRoadmap(
+	ctx context.Context,
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	actor Actor,
+	roadmapID string,
+) (*roadmapentity.Roadmap, error) {
+	userID, err := actor.id()
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(roadmapID)
+	if err != nil {
+		return nil, ErrRoadmapNotFound
+	}
+
+	roadmap, err := roadmapRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, roadmaprepo.ErrRoadmapNotFound) {
+			return nil, ErrRoadmapNotFound
+		}
+		return nil, err
+	}
+
+	if !actor.CanModerate && !roadmap.IsVisibleTo(userID) {
+		return nil, ErrRoadmapNotFound
+	}
+
+	return roadmap, nil
+}
+
+// getEditableRoadmap is getReadableRoadmap for changes, which only the owner
+// and moderators may make.
+func getEditableRoadmap(
+	ctx context.Context,
+	roadmapRepository roadmaprepo.RoadmapRepository,
+	actor Actor,
+	roadmapID string,
+) (*roadmapentity.Roadmap, error) {
+	roadmap, err := getReadableRoadmap(ctx, roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := actor.id()
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanModerate && !roadmap.IsOwnedBy(userID) {
+		return nil, ErrNotRoadmapEditor
+	}
+
+	return roadmap, nil
+}
+
+type CreateRoadmapUseCase struct {
+	roadmapRepository roadmaprepo.RoadmapRepository
+}
+
+func NewCreateRoadmapUseCase(roadmapRepository roadmaprepo.RoadmapRepository) *CreateRoadmapUseCase {
+	return &CreateRoadmapUseCase{
+		roadmapRepository: roadmapRepository,
+	}
+}
+
+func (u *CreateRoadmapUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	req roadmapdto.CreateRoadmapRequest,
+) (roadmapdto.RoadmapResponse, error) {
+	ownerID, err := actor.id()
+	if err != nil {
+		return roadmapdto.RoadmapResponse{}, err
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return roadmapdto.RoadmapResponse{}, ErrBlankTitle
+	}
+	if req.Slug != "" {
+		if err := validateSlug(req.Slug); err != nil {
+			return roadmapdto.RoadmapResponse{}, err
+		}
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = roadmapentity.VisibilityPrivate
+	}
+
+	// A slug the client chose is tried once and reported if taken. A slug
+	// derived from the title is made unique by appending a number instead.
+	base := slugify(title)
+	for attempt := 1; ; attempt++ {
+		slug := req.Slug
+		if slug == "" {
+			slug = derivedSlugCandidate(base, attempt)
+		}
+
+		roadmap, err := u.roadmapRepository.Create(ctx, &roadmapentity.Roadmap{
+			ID:          uuid.New(),
+			OwnerID:     ownerID,
+			Title:       title,
+			Slug:        slug,
+			Description: req.Description,
+			Visibility:  visibility,
+		})
+		if err == nil {
+			return newRoadmapResponse(roadmap), nil
+		}
+		if !errors.Is(err, roadmaprepo.ErrSlugTaken) {
+			return roadmapdto.RoadmapResponse{}, err
+		}
+		if req.Slug != "" || attempt > maxDerivedSlugSuffix {
+			return roadmapdto.RoadmapResponse{}, ErrRoadmapSlugTaken
+		}
+	}
+}
+
+type GetRoadmapUseCase struct {
+	roadmapRepository roadmaprepo.RoadmapRepository
+}
+
+func NewGetRoadmapUseCase(roadmapRepository roadmaprepo.RoadmapRepository) *GetRoadmapUseCase {
+	return &GetRoadmapUseCase{
+		roadmapRepository: roadmapRepository,
+	}
+}
+
+func (u *GetRoadmapUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+) (roadmapdto.RoadmapResponse, error) {
+	roadmap, err := getReadableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return roadmapdto.RoadmapResponse{}, err
+	}
+
+	return newRoadmapResponse(roadmap), nil
+}
+
+type ListRoadmapsUseCase struct {
+	roadmapRepository roadmaprepo.RoadmapRepository
+}
+
+func NewListRoadmapsUseCase(roadmapRepository roadmaprepo.RoadmapRepository) *ListRoadmapsUseCase {
+	return &ListRoadmapsUseCase{
+		roadmapRepository: roadmapRepository,
+	}
+}
+
+// Execute lists the actor's own roadmaps and the public roadmaps of other
+// users. Unlisted roadmaps only show up for their owner.
+func (u *ListRoadmapsUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	req roadmapdto.ListRoadmapsRequest,
+) (roadmapdto.ListRoadmapsResponse, error) {
+	viewerID, err := actor.id()
+	if err != nil {
+		return roadmapdto.ListRoadmapsResponse{}, err
+	}
+
+	page := req.Page
+	if page == 0 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = roadmapdto.DefaultRoadmapPageSize
+	}
+
+	filter := roadmaprepo.RoadmapListFilter{
+		ViewerID: viewerID,
+		Limit:    pageSize,
+		Offset:   (page - 1) * pageSize,
+	}
+	if req.OwnerID != "" {
+		ownerID, err := uuid.Parse(req.OwnerID)
+		if err != nil {
+			return roadmapdto.ListRoadmapsResponse{}, err
+		}
+		filter.OwnerID = &ownerID
+	}
+
+	roadmaps, total, err := u.roadmapRepository.List(ctx, filter)
+	if err != nil {
+		return roadmapdto.ListRoadmapsResponse{}, err
+	}
+
+	response := roadmapdto.ListRoadmapsResponse{
+		Roadmaps: make([]roadmapdto.RoadmapResponse, 0, len(roadmaps)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for _, roadmap := range roadmaps {
+		response.Roadmaps = append(response.Roadmaps, newRoadmapResponse(roadmap))
+	}
+
+	return response, nil
+}
+
+type UpdateRoadmapUseCase struct {
+	roadmapRepository roadmaprepo.RoadmapRepository
+}
+
+func NewUpdateRoadmapUseCase(roadmapRepository roadmaprepo.RoadmapRepository) *UpdateRoadmapUseCase {
+	return &UpdateRoadmapUseCase{
+		roadmapRepository: roadmapRepository,
+	}
+}
+
+func (u *UpdateRoadmapUseCase) Execute(
+	ctx context.Context,
+	actor Actor,
+	roadmapID string,
+	req roadmapdto.UpdateRoadmapRequest,
+) (roadmapdto.RoadmapResponse, error) {
+	roadmap, err := getEditableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return roadmapdto.RoadmapResponse{}, err
+	}
+
+	if req.Slug != nil {
+		if err := validateSlug(*req.Slug); err != nil {
+			return roadmapdto.RoadmapResponse{}, err
+		}
+		roadmap.Slug = *req.Slug
+	}
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return roadmapdto.RoadmapResponse{}, ErrBlankTitle
+		}
+		roadmap.Title = title
+	}
+	if req.Description != nil {
+		roadmap.Description = *req.Description
+	}
+	if req.Visibility != nil {
+		roadmap.Visibility = *req.Visibility
+	}
+
+	updated, err := u.roadmapRepository.Update(ctx, roadmap)
+	if err != nil {
+		switch {
+		case errors.Is(err, roadmaprepo.ErrSlugTaken):
+			return roadmapdto.RoadmapResponse{}, ErrRoadmapSlugTaken
+		case errors.Is(err, roadmaprepo.ErrRoadmapNotFound):
+			return roadmapdto.RoadmapResponse{}, ErrRoadmapNotFound
+		}
+		return roadmapdto.RoadmapResponse{}, err
+	}
+
+	return newRoadmapResponse(updated), nil
+}
+
+type DeleteRoadmapUseCase struct {
+	roadmapRepository roadmaprepo.RoadmapRepository
+}
+
+func NewDeleteRoadmapUseCase(roadmapRepository roadmaprepo.RoadmapRepository) *DeleteRoadmapUseCase {
+	return &DeleteRoadmapUseCase{
+		roadmapRepository: roadmapRepository,
+	}
+}
+
+func (u *DeleteRoadmapUseCase) Execute(ctx context.Context, actor Actor, roadmapID string) error {
+	roadmap, err := getEditableRoadmap(ctx, u.roadmapRepository, actor, roadmapID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.roadmapRepository.Delete(ctx, roadmap.ID); err != nil {
+		if errors.Is(err, roadmaprepo.ErrRoadmapNotFound) {
+			return ErrRoadmapNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+type ExportUserRoadmapsUseCase struct {
+	roadmapRepository roadmaprepo.RoadmapRepository
+}
+
+func NewExportUserRoadmapsUseCase(roadmapRepository roadmaprepo.RoadmapRepository) *ExportUserRoadmapsUseCase {
+	return &ExportUserRoadmapsUseCase{
+		roadmapRepository: roadmapRepository,
+	}
+}
+
+// Execute returns the roadmaps the user owns for the account data export.
+// It has the signature of an account export hook.
+func (u *ExportUserRoadmapsUseCase) Execute(ctx context.Context, userID uuid.UUID) (any, error) {
+	roadmaps, err := u.roadmapRepository.ListByOwner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]roadmapdto.RoadmapResponse, 0, len(roadmaps))
+	for _, roadmap := range roadmaps {
+		responses = append(responses, newRoadmapResponse(roadmap))
+	}
+	return responses, nil
+}
@@ -0,0 +1,337 @@
+package roadmap
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	roadmapdto "roadmap/internal/domain/dto/roadmap"
+	roadmapentity "roadmap/internal/domain/entities/roadmap"
+	roadmaprepo "roadmap/internal/repository/roadmap"
+)
+
+type MockRoadmapRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoadmapRepository) Create(
+	ctx context.Context,
+	roadmap *roadmapentity.Roadmap,
+) (*roadmapentity.Roadmap, error) {
+	args := m.Called(ctx, roadmap)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Roadmap), args.Error(1)
+}
+
+func (m *MockRoadmapRepository) GetByID(ctx context.Context, id uuid.UUID) (*roadmapentity.Roadmap, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Roadmap), args.Error(1)
+}
+
+func (m *MockRoadmapRepository) GetByOwnerAndSlug(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	slug string,
+) (*roadmapentity.Roadmap, error) {
+	args := m.Called(ctx, ownerID, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Roadmap), args.Error(1)
+}
+
+func (m *MockRoadmapRepository) List(
+	ctx context.Context,
+	filter roadmaprepo.RoadmapListFilter,
+) ([]*roadmapentity.Roadmap, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*roadmapentity.Roadmap), args.Int(1), args.Error(2)
+}
+
+func (m *MockRoadmapRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*roadmapentity.Roadmap, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*roadmapentity.Roadmap), args.Error(1)
+}
+
+func (m *MockRoadmapRepository) Update(
+	ctx context.Context,
+	roadmap *roadmapentity.Roadmap,
+) (*roadmapentity.Roadmap, error) {
+	args := m.Called(ctx, roadmap)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*roadmapentity.Roadmap), args.Error(1)
+}
+
+func (m *MockRoadmapRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type RoadmapUseCaseTestSuite struct {
+	suite.Suite
+	roadmapRepo *MockRoadmapRepository
+	owner       Actor
+	other       Actor
+	moderator   Actor
+	roadmap     *roadmapentity.Roadmap
+	ctx         context.Context
+}
+
+func (s *RoadmapUseCaseTestSuite) SetupTest() {
+	s.roadmapRepo = new(MockRoadmapRepository)
+
+	ownerID := uuid.New()
+	s.owner = Actor{UserID: ownerID.String()}
+	s.other = Actor{UserID: uuid.NewString()}
+	s.moderator = Actor{UserID: uuid.NewString(), CanModerate: true}
+
+	now := time.Now().UTC()
+	s.roadmap = &roadmapentity.Roadmap{
+		ID:          uuid.New(),
+		OwnerID:     ownerID,
+		Title:       "Backend Developer",
+		Slug:        "backend-developer",
+		Description: "Everything a backend developer needs",
+		Visibility:  roadmapentity.VisibilityPrivate,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.ctx = context.Background()
+}
+
+func (s *RoadmapUseCaseTestSuite) TearDownTest() {
+	s.roadmapRepo.AssertExpectations(s.T())
+}
+
+func (s *RoadmapUseCaseTestSuite) TestCreate_DerivesSlugAndDefaults() {
+	var stored *roadmapentity.Roadmap
+	s.roadmapRepo.On("Create", s.ctx, mock.AnythingOfType("*roadmap.Roadmap")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*roadmapentity.Roadmap) }).
+		Return(s.roadmap, nil)
+
+	response, err := NewCreateRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, s.owner, roadmapdto.CreateRoadmapRequest{
+		Title: "  Go & PostgreSQL: The Basics ",
+	})
+
+	s.Require().NoError(err)
+	s.Equal(s.roadmap.ID, response.ID)
+	s.Equal("Go & PostgreSQL: The Basics", stored.Title)
+	s.Equal("go-postgresql-the-basics", stored.Slug)
+	s.Equal(roadmapentity.VisibilityPrivate, stored.Visibility)
+	s.Equal(s.owner.UserID, stored.OwnerID.String())
+}
+
+func (s *RoadmapUseCaseTestSuite) TestCreate_InvalidSlug() {
+	_, err := NewCreateRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, s.owner, roadmapdto.CreateRoadmapRequest{
+		Title: "Backend",
+		Slug:  "Not A Slug",
+	})
+
+	s.ErrorIs(err, ErrInvalidSlug)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestCreate_SlugTaken() {
+	s.roadmapRepo.On("Create", s.ctx, mock.AnythingOfType("*roadmap.Roadmap")).Return(nil, roadmaprepo.ErrSlugTaken).Once()
+
+	_, err := NewCreateRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, s.owner, roadmapdto.CreateRoadmapRequest{
+		Title: "Backend Developer",
+		Slug:  "backend",
+	})
+
+	s.ErrorIs(err, ErrRoadmapSlugTaken)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestCreate_DerivedSlugTakenGetsSuffix() {
+	var tried []string
+	s.roadmapRepo.On("Create", s.ctx, mock.AnythingOfType("*roadmap.Roadmap")).
+		Run(func(args mock.Arguments) { tried = append(tried, args.Get(1).(*roadmapentity.Roadmap).Slug) }).
+		Return(nil, roadmaprepo.ErrSlugTaken).Twice()
+	s.roadmapRepo.On("Create", s.ctx, mock.AnythingOfType("*roadmap.Roadmap")).
+		Run(func(args mock.Arguments) { tried = append(tried, args.Get(1).(*roadmapentity.Roadmap).Slug) }).
+		Return(s.roadmap, nil).Once()
+
+	_, err := NewCreateRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, s.owner, roadmapdto.CreateRoadmapRequest{
+		Title: "Backend Developer",
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"backend-developer", "backend-developer-2", "backend-developer-3"}, tried)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestCreate_BlankTitle() {
+	_, err := NewCreateRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, s.owner, roadmapdto.CreateRoadmapRequest{
+		Title: "   ",
+	})
+
+	s.ErrorIs(err, ErrBlankTitle)
+	s.roadmapRepo.AssertNotCalled(s.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestCreate_InvalidUserID() {
+	_, err := NewCreateRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, Actor{UserID: "invalid"},
+		roadmapdto.CreateRoadmapRequest{Title: "Backend"})
+
+	s.ErrorIs(err, ErrInvalidTokenClaims)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestGet_Visibility() {
+	s.roadmapRepo.On("GetByID", s.ctx, s.roadmap.ID).Return(s.roadmap, nil)
+	useCase := NewGetRoadmapUseCase(s.roadmapRepo)
+
+	_, err := useCase.Execute(s.ctx, s.owner, s.roadmap.ID.String())
+	s.NoError(err)
+
+	_, err = useCase.Execute(s.ctx, s.moderator, s.roadmap.ID.String())
+	s.NoError(err)
+
+	_, err = useCase.Execute(s.ctx, s.other, s.roadmap.ID.String())
+	s.ErrorIs(err, ErrRoadmapNotFound)
+
+	s.roadmap.Visibility = roadmapentity.VisibilityUnlisted
+	_, err = useCase.Execute(s.ctx, s.other, s.roadmap.ID.String())
+	s.NoError(err)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestGet_NotFound() {
+	s.roadmapRepo.On("GetByID", s.ctx, s.roadmap.ID).Return(nil, roadmaprepo.ErrRoadmapNotFound)
+
+	_, err := NewGetRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, s.owner, s.roadmap.ID.String())
+	s.ErrorIs(err, ErrRoadmapNotFound)
+
+	_, err = NewGetRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, s.owner, "not-a-uuid")
+	s.ErrorIs(err, ErrRoadmapNotFound)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestList_Pagination() {
+	ownerID := s.roadmap.OwnerID
+	s.roadmapRepo.On("List", s.ctx, mock.MatchedBy(func(filter roadmaprepo.RoadmapListFilter) bool {
+		return filter.ViewerID.String() == s.other.UserID && filter.OwnerID != nil && *filter.OwnerID == ownerID &&
+			filter.Limit == 10 && filter.Offset == 20
+	})).Return([]*roadmapentity.Roadmap{s.roadmap}, 21, nil)
+
+	response, err := NewListRoadmapsUseCase(s.roadmapRepo).Execute(s.ctx, s.other, roadmapdto.ListRoadmapsRequest{
+		OwnerID:  ownerID.String(),
+		Page:     3,
+		PageSize: 10,
+	})
+
+	s.Require().NoError(err)
+	s.Equal(21, response.Total)
+	s.Equal(3, response.Page)
+	s.Len(response.Roadmaps, 1)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestUpdate_ByOwner() {
+	s.roadmapRepo.On("GetByID", s.ctx, s.roadmap.ID).Return(s.roadmap, nil)
+	s.roadmapRepo.On("Update", s.ctx, mock.MatchedBy(func(roadmap *roadmapentity.Roadmap) bool {
+		return roadmap.Title == "Frontend" && roadmap.Slug == "frontend" &&
+			roadmap.Visibility == roadmapentity.VisibilityPublic && roadmap.Description == s.roadmap.Description
+	})).Return(s.roadmap, nil)
+
+	title, slug, visibility := "Frontend", "frontend", roadmapentity.VisibilityPublic
+	_, err := NewUpdateRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, s.owner, s.roadmap.ID.String(),
+		roadmapdto.UpdateRoadmapRequest{Title: &title, Slug: &slug, Visibility: &visibility})
+
+	s.NoError(err)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestUpdate_BlankTitle() {
+	s.roadmapRepo.On("GetByID", s.ctx, s.roadmap.ID).Return(s.roadmap, nil)
+
+	title := " \t "
+	_, err := NewUpdateRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, s.owner, s.roadmap.ID.String(),
+		roadmapdto.UpdateRoadmapRequest{Title: &title})
+
+	s.ErrorIs(err, ErrBlankTitle)
+	s.roadmapRepo.AssertNotCalled(s.T(), "Update", mock.Anything, mock.Anything)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestUpdate_ByOtherUser() {
+	s.roadmap.Visibility = roadmapentity.VisibilityPublic
+	s.roadmapRepo.On("GetByID", s.ctx, s.roadmap.ID).Return(s.roadmap, nil)
+
+	title := "Hijacked"
+	_, err := NewUpdateRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, s.other, s.roadmap.ID.String(),
+		roadmapdto.UpdateRoadmapRequest{Title: &title})
+
+	s.ErrorIs(err, ErrNotRoadmapEditor)
+	s.roadmapRepo.AssertNotCalled(s.T(), "Update", mock.Anything, mock.Anything)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestDelete_ByModerator() {
+	s.roadmapRepo.On("GetByID", s.ctx, s.roadmap.ID).Return(s.roadmap, nil)
+	s.roadmapRepo.On("Delete", s.ctx, s.roadmap.ID).Return(nil)
+
+	err := NewDeleteRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, s.moderator, s.roadmap.ID.String())
+
+	s.NoError(err)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestDelete_PrivateRoadmapOfOtherUser() {
+	s.roadmapRepo.On("GetByID", s.ctx, s.roadmap.ID).Return(s.roadmap, nil)
+
+	err := NewDeleteRoadmapUseCase(s.roadmapRepo).Execute(s.ctx, s.other, s.roadmap.ID.String())
+
+	s.ErrorIs(err, ErrRoadmapNotFound)
+}
+
+func (s *RoadmapUseCaseTestSuite) TestExport() {
+	s.roadmapRepo.On("ListByOwner", s.ctx, s.roadmap.OwnerID).Return([]*roadmapentity.Roadmap{s.roadmap}, nil)
+
+	export, err := NewExportUserRoadmapsUseCase(s.roadmapRepo).Execute(s.ctx, s.roadmap.OwnerID)
+
+	s.Require().NoError(err)
+	s.Equal([]roadmapdto.RoadmapResponse{newRoadmapResponse(s.roadmap)}, export)
+}
+
+func TestRoadmapUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(RoadmapUseCaseTestSuite))
+}
+
+func TestDerivedSlugCandidate(t *testing.T) {
+	assert.Equal(t, "backend", derivedSlugCandidate("backend", 1))
+	assert.Equal(t, "backend-2", derivedSlugCandidate("backend", 2))
+	assert.Equal(t, "backend-20", derivedSlugCandidate("backend", maxDerivedSlugSuffix))
+	assert.Regexp(t, `^backend-[0-9a-f]{8}$`, derivedSlugCandidate("backend", maxDerivedSlugSuffix+1))
+
+	long := strings.Repeat("a", maxSlugLength)
+	assert.Equal(t, strings.Repeat("a", maxSlugLength-2)+"-2", derivedSlugCandidate(long, 2))
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Backend Developer", "backend-developer"},
+		{"  --C++ / Rust--  ", "c-rust"},
+		{"DevOps 2025", "devops-2025"},
+		{"日本語", "roadmap"},
+		{strings.Repeat("a", 99) + " b", strings.Repeat("a", 99)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.want, slugify(tt.title))
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	userrepo "roadmap/internal/repository/user"
+)
+
+const (
+	// accessTokenPrefix marks personal access tokens so they are easy to spot
+	// in logs and secret scanners. It contains no "." and neither does the
+	// random part, which is how AuthMiddleware tells them apart from JWTs.
+	accessTokenPrefix = "rmp_"
+
+	// accessTokenDisplayLength is how much of the token is kept in clear so
+	// users can recognise it in listings.
+	accessTokenDisplayLength = 12
+
+	// accessTokenTouchInterval limits last-used writes to one per token per
+	// interval, so busy scripts don't turn every request into an UPDATE.
+	accessTokenTouchInterval = time.Minute
+)
+
+func newAccessTokenResponse(token *userentity.PersonalAccessToken) userdto.AccessTokenResponse {
+	scopes := token.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return userdto.AccessTokenResponse{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      scopes,
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
+
+// normalizeScopes drops duplicates while keeping the order requested.
+func normalizeScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized
+}
+
+type CreateAccessTokenUseCase struct {
+	tokenRepository userrepo.PersonalAccessTokenRepository
+}
+
+func NewCreateAccessTokenUseCase(tokenRepository userrepo.PersonalAccessTokenRepository) *CreateAccessTokenUseCase {
+	return &CreateAccessTokenUseCase{
+		tokenRepository: tokenRepository,
+	}
+}
+
+func (u *CreateAccessTokenUseCase) Execute(
+	ctx context.Context,
+	userID string,
+	req userdto.CreateAccessTokenRequest,
+) (userdto.CreateAccessTokenResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.CreateAccessTokenResponse{}, ErrInvalidTokenClaims
+	}
+
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return userdto.CreateAccessTokenResponse{}, ErrInvalidAccessTokenExpiry
+	}
+
+	secret, err := generateOpaqueToken()
+	if err != nil {
+		return userdto.CreateAccessTokenResponse{}, err
+	}
+	plaintext := accessTokenPrefix + secret
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil {
+		expiry := req.ExpiresAt.UTC()
+		expiresAt = &expiry
+	}
+
+	token, err := u.tokenRepository.Create(ctx, &userentity.PersonalAccessToken{
+		ID:          uuid.New(),
+		UserID:      id,
+		Name:        strings.TrimSpace(req.Name),
+		TokenHash:   hashOpaqueToken(plaintext),
+		TokenPrefix: plaintext[:accessTokenDisplayLength],
+		Scopes:      normalizeScopes(req.Scopes),
+		ExpiresAt:   expiresAt,
+		CreatedAt:   now,
+	})
+	if err != nil {
+		if errors.Is(err, userrepo.ErrAccessTokenNameTaken) {
+			return userdto.CreateAccessTokenResponse{}, ErrAccessTokenNameTaken
+		}
+		return userdto.CreateAccessTokenResponse{}, err
+	}
+
+	return userdto.CreateAccessTokenResponse{
+		AccessTokenResponse: newAccessTokenResponse(token),
+		Token:               plaintext,
+	}, nil
+}
+
+type ListAccessTokensUseCase struct {
+	tokenRepository userrepo.PersonalAccessTokenRepository
+}
+
+func NewListAccessTokensUseCase(tokenRepository userrepo.PersonalAccessTokenRepository) *ListAccessTokensUseCase {
+	return &ListAccessTokensUseCase{
+		tokenRepository: tokenRepository,
+	}
+}
+
+func (u *ListAccessTokensUseCase) Execute(ctx context.Context, userID string) (userdto.ListAccessTokensResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.ListAccessTokensResponse{}, ErrInvalidTokenClaims
+	}
+
+	tokens, err := u.tokenRepository.ListByUser(ctx, id)
+	if err != nil {
+		return userdto.ListAccessTokensResponse{}, err
+	}
+
+	response := userdto.ListAccessTokensResponse{
+		AccessTokens: make([]userdto.AccessTokenResponse, 0, len(tokens)),
+	}
+	for _, token := range tokens {
+		response.AccessTokens = append(response.AccessTokens, newAccessTokenResponse(token))
+	}
+
+	return response, nil
+}
+
+type GetAccessTokenUseCase struct {
+	tokenRepository userrepo.PersonalAccessTokenRepository
+}
+
+func NewGetAccessTokenUseCase(tokenRepository userrepo.PersonalAccessTokenRepository) *GetAccessTokenUseCase {
+	return &GetAccessTokenUseCase{
+		tokenRepository: tokenRepository,
+	}
+}
+
+func (u *GetAccessTokenUseCase) Execute(
+	ctx context.Context,
+	userID string,
+	tokenID string,
+) (userdto.AccessTokenResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.AccessTokenResponse{}, ErrInvalidTokenClaims
+	}
+
+	accessTokenID, err := uuid.Parse(tokenID)
+	if err != nil {
+		return userdto.AccessTokenResponse{}, ErrAccessTokenNotFound
+	}
+
+	token, err := u.tokenRepository.GetByID(ctx, accessTokenID, id)
+	if err != nil {
+		if errors.Is(err, userrepo.ErrAccessTokenNotFound) {
+			return userdto.AccessTokenResponse{}, ErrAccessTokenNotFound
+		}
+		return userdto.AccessTokenResponse{}, err
+	}
+
+	return newAccessTokenResponse(token), nil
+}
+
+type UpdateAccessTokenUseCase struct {
+	tokenRepository userrepo.PersonalAccessTokenRepository
+}
+
+func NewUpdateAccessTokenUseCase(tokenRepository userrepo.PersonalAccessTokenRepository) *UpdateAccessTokenUseCase {
+	return &UpdateAccessTokenUseCase{
+		tokenRepository: tokenRepository,
+	}
+}
+
+// Execute renames the token or changes its scopes. The secret and the expiry
+// cannot be changed; create a new token instead.
+func (u *UpdateAccessTokenUseCase) Execute(
+	ctx context.Context,
+	userID string,
+	tokenID string,
+	req userdto.UpdateAccessTokenRequest,
+) (userdto.AccessTokenResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.AccessTokenResponse{}, ErrInvalidTokenClaims
+	}
+
+	accessTokenID, err := uuid.Parse(tokenID)
+	if err != nil {
+		return userdto.AccessTokenResponse{}, ErrAccessTokenNotFound
+	}
+
+	token, err := u.tokenRepository.GetByID(ctx, accessTokenID, id)
+	if err != nil {
+		if errors.Is(err, userrepo.ErrAccessTokenNotFound) {
+			return userdto.AccessTokenResponse{}, ErrAccessTokenNotFound
+		}
+		return userdto.AccessTokenResponse{}, err
+	}
+
+	if req.Name != nil {
+		token.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Scopes != nil {
+		token.Scopes = normalizeScopes(req.Scopes)
+	}
+
+	updated, err := u.tokenRepository.Update(ctx, token)
+	if err != nil {
+		switch {
+		case errors.Is(err, userrepo.ErrAccessTokenNameTaken):
+			return userdto.AccessTokenResponse{}, ErrAccessTokenNameTaken
+		case errors.Is(err, userrepo.ErrAccessTokenNotFound):
+			return userdto.AccessTokenResponse{}, ErrAccessTokenNotFound
+		}
+		return userdto.AccessTokenResponse{}, err
+	}
+
+	return newAccessTokenResponse(updated), nil
+}
+
+type DeleteAccessTokenUseCase struct {
+	tokenRepository userrepo.PersonalAccessTokenRepository
+}
+
+func NewDeleteAccessTokenUseCase(tokenRepository userrepo.PersonalAccessTokenRepository) *DeleteAccessTokenUseCase {
+	return &DeleteAccessTokenUseCase{
+		tokenRepository: tokenRepository,
+	}
+}
+
+func (u *DeleteAccessTokenUseCase) Execute(ctx context.Context, userID string, tokenID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return ErrInvalidTokenClaims
+	}
+
+	accessTokenID, err := uuid.Parse(tokenID)
+	if err != nil {
+		return ErrAccessTokenNotFound
+	}
+
+	if err := u.tokenRepository.Delete(ctx, accessTokenID, id); err != nil {
+		if errors.Is(err, userrepo.ErrAccessTokenNotFound) {
+			return ErrAccessTokenNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// AccessTokenAuthenticator resolves personal access tokens for
+// middleware.AuthMiddleware. Tokens are looked up on every request, so
+// deleting one takes effect immediately.
+type AccessTokenAuthenticator struct {
+	tokenRepository userrepo.PersonalAccessTokenRepository
+	userRepository  userrepo.UserRepository
+}
+
+func NewAccessTokenAuthenticator(
+	tokenRepository userrepo.PersonalAccessTokenRepository,
+	userRepository userrepo.UserRepository,
+) *AccessTokenAuthenticator {
+	return &AccessTokenAuthenticator{
+		tokenRepository: tokenRepository,
+		userRepository:  userRepository,
+	}
+}
+
+// AuthenticateAccessToken returns nil claims without an error when the token
+// is unknown or expired.
+func (a *AccessTokenAuthenticator) AuthenticateAccessToken(
+	ctx context.Context,
+	plaintext string,
+) (*jwtservice.Claims, []string, error) {
+	if !strings.HasPrefix(plaintext, accessTokenPrefix) {
+		return nil, nil, nil
+	}
+
+	token, err := a.tokenRepository.GetByHash(ctx, hashOpaqueToken(plaintext))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if token.IsExpired(now) {
+		return nil, nil, nil
+	}
+
+	user, err := a.userRepository.GetByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= accessTokenTouchInterval {
+		if err := a.tokenRepository.UpdateLastUsed(ctx, token.ID, now); err != nil {
+			log.Printf("failed to record access token use %s: %v", token.ID, err)
+		}
+	}
+
+	claims := &jwtservice.Claims{
+		UserID:        user.ID.String(),
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.IsEmailVerified(),
+		Roles:         user.Roles,
+		Permissions:   user.Permissions,
+	}
+	return claims, token.Scopes, nil
+}
@@ -0,0 +1,252 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type MockPersonalAccessTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockPersonalAccessTokenRepository) Create(
+	ctx context.Context,
+	token *userentity.PersonalAccessToken,
+) (*userentity.PersonalAccessToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) GetByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.PersonalAccessToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) GetByID(
+	ctx context.Context,
+	id uuid.UUID,
+	userID uuid.UUID,
+) (*userentity.PersonalAccessToken, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) ListByUser(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]*userentity.PersonalAccessToken, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*userentity.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) Update(
+	ctx context.Context,
+	token *userentity.PersonalAccessToken,
+) (*userentity.PersonalAccessToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockPersonalAccessTokenRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+func (m *MockPersonalAccessTokenRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+type AccessTokenUseCaseTestSuite struct {
+	suite.Suite
+	userRepo  *MockUserRepository
+	tokenRepo *MockPersonalAccessTokenRepository
+	user      *userentity.User
+	ctx       context.Context
+}
+
+func (s *AccessTokenUseCaseTestSuite) SetupTest() {
+	s.userRepo = new(MockUserRepository)
+	s.tokenRepo = new(MockPersonalAccessTokenRepository)
+	s.ctx = context.Background()
+
+	verifiedAt := time.Now()
+	s.user = &userentity.User{
+		ID:              uuid.New(),
+		Username:        "testuser",
+		Email:           "test@example.com",
+		EmailVerifiedAt: &verifiedAt,
+	}
+}
+
+func (s *AccessTokenUseCaseTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.tokenRepo.AssertExpectations(s.T())
+}
+
+// createToken runs CreateAccessTokenUseCase and returns the plaintext token
+// together with the record that was stored for it.
+func (s *AccessTokenUseCaseTestSuite) createToken(scopes []string) (string, *userentity.PersonalAccessToken) {
+	var stored *userentity.PersonalAccessToken
+	s.tokenRepo.On("Create", s.ctx, mock.AnythingOfType("*user.PersonalAccessToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*userentity.PersonalAccessToken) }).
+		Return(&userentity.PersonalAccessToken{ID: uuid.New(), Name: "CI", Scopes: scopes}, nil).Once()
+
+	response, err := NewCreateAccessTokenUseCase(s.tokenRepo).Execute(s.ctx, s.user.ID.String(),
+		userdto.CreateAccessTokenRequest{Name: " CI ", Scopes: scopes})
+	s.Require().NoError(err)
+
+	return response.Token, stored
+}
+
+func (s *AccessTokenUseCaseTestSuite) TestCreate_StoresHashOnly() {
+	token, stored := s.createToken([]string{"read", "read", "write"})
+
+	assert.True(s.T(), strings.HasPrefix(token, accessTokenPrefix))
+	assert.NotContains(s.T(), token, ".")
+	assert.Equal(s.T(), hashOpaqueToken(token), stored.TokenHash)
+	assert.Equal(s.T(), token[:accessTokenDisplayLength], stored.TokenPrefix)
+	assert.Equal(s.T(), "CI", stored.Name)
+	assert.Equal(s.T(), []string{"read", "write"}, stored.Scopes)
+	assert.Equal(s.T(), s.user.ID, stored.UserID)
+	assert.Nil(s.T(), stored.ExpiresAt)
+}
+
+func (s *AccessTokenUseCaseTestSuite) TestCreate_PastExpiry() {
+	past := time.Now().Add(-time.Hour)
+
+	_, err := NewCreateAccessTokenUseCase(s.tokenRepo).Execute(s.ctx, s.user.ID.String(),
+		userdto.CreateAccessTokenRequest{Name: "CI", Scopes: []string{"read"}, ExpiresAt: &past})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidAccessTokenExpiry)
+}
+
+func (s *AccessTokenUseCaseTestSuite) TestCreate_NameTaken() {
+	s.tokenRepo.On("Create", s.ctx, mock.AnythingOfType("*user.PersonalAccessToken")).
+		Return(nil, userrepo.ErrAccessTokenNameTaken)
+
+	_, err := NewCreateAccessTokenUseCase(s.tokenRepo).Execute(s.ctx, s.user.ID.String(),
+		userdto.CreateAccessTokenRequest{Name: "CI", Scopes: []string{"read"}})
+
+	assert.ErrorIs(s.T(), err, ErrAccessTokenNameTaken)
+}
+
+func (s *AccessTokenUseCaseTestSuite) TestUpdate_RenamesAndRescopes() {
+	stored := &userentity.PersonalAccessToken{ID: uuid.New(), UserID: s.user.ID, Name: "CI", Scopes: []string{"read"}}
+	s.tokenRepo.On("GetByID", s.ctx, stored.ID, s.user.ID).Return(stored, nil)
+	s.tokenRepo.On("Update", s.ctx, mock.MatchedBy(func(token *userentity.PersonalAccessToken) bool {
+		return token.Name == "Deploy" && assert.ObjectsAreEqual([]string{"write"}, token.Scopes)
+	})).Return(stored, nil)
+
+	name := "Deploy"
+	_, err := NewUpdateAccessTokenUseCase(s.tokenRepo).Execute(s.ctx, s.user.ID.String(), stored.ID.String(),
+		userdto.UpdateAccessTokenRequest{Name: &name, Scopes: []string{"write"}})
+
+	s.Require().NoError(err)
+}
+
+func (s *AccessTokenUseCaseTestSuite) TestGet_OtherUsersToken() {
+	tokenID := uuid.New()
+	s.tokenRepo.On("GetByID", s.ctx, tokenID, s.user.ID).Return(nil, userrepo.ErrAccessTokenNotFound)
+
+	_, err := NewGetAccessTokenUseCase(s.tokenRepo).Execute(s.ctx, s.user.ID.String(), tokenID.String())
+
+	assert.ErrorIs(s.T(), err, ErrAccessTokenNotFound)
+}
+
+func (s *AccessTokenUseCaseTestSuite) TestDelete() {
+	tokenID := uuid.New()
+	s.tokenRepo.On("Delete", s.ctx, tokenID, s.user.ID).Return(userrepo.ErrAccessTokenNotFound)
+
+	err := NewDeleteAccessTokenUseCase(s.tokenRepo).Execute(s.ctx, s.user.ID.String(), tokenID.String())
+	assert.ErrorIs(s.T(), err, ErrAccessTokenNotFound)
+
+	err = NewDeleteAccessTokenUseCase(s.tokenRepo).Execute(s.ctx, s.user.ID.String(), "not-a-uuid")
+	assert.ErrorIs(s.T(), err, ErrAccessTokenNotFound)
+}
+
+func (s *AccessTokenUseCaseTestSuite) TestAuthenticate_ReturnsOwnerClaims() {
+	token, stored := s.createToken([]string{"read"})
+	stored.ID = uuid.New()
+	s.tokenRepo.On("GetByHash", s.ctx, hashOpaqueToken(token)).Return(stored, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.tokenRepo.On("UpdateLastUsed", s.ctx, stored.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	claims, scopes, err := NewAccessTokenAuthenticator(s.tokenRepo, s.userRepo).AuthenticateAccessToken(s.ctx, token)
+
+	s.Require().NoError(err)
+	s.Require().NotNil(claims)
+	assert.Equal(s.T(), s.user.ID.String(), claims.UserID)
+	assert.Equal(s.T(), s.user.Email, claims.Email)
+	assert.True(s.T(), claims.EmailVerified)
+	assert.Equal(s.T(), []string{"read"}, scopes)
+}
+
+func (s *AccessTokenUseCaseTestSuite) TestAuthenticate_RecentUseNotRecorded() {
+	lastUsed := time.Now().Add(-10 * time.Second)
+	stored := &userentity.PersonalAccessToken{ID: uuid.New(), UserID: s.user.ID, LastUsedAt: &lastUsed}
+	s.tokenRepo.On("GetByHash", s.ctx, mock.Anything).Return(stored, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+
+	claims, _, err := NewAccessTokenAuthenticator(s.tokenRepo, s.userRepo).AuthenticateAccessToken(s.ctx, "rmp_token")
+
+	s.Require().NoError(err)
+	assert.NotNil(s.T(), claims)
+	s.tokenRepo.AssertNotCalled(s.T(), "UpdateLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *AccessTokenUseCaseTestSuite) TestAuthenticate_Rejects() {
+	authenticator := NewAccessTokenAuthenticator(s.tokenRepo, s.userRepo)
+
+	claims, _, err := authenticator.AuthenticateAccessToken(s.ctx, "no-prefix")
+	s.Require().NoError(err)
+	assert.Nil(s.T(), claims, "tokens without the prefix are never looked up")
+
+	s.tokenRepo.On("GetByHash", s.ctx, hashOpaqueToken("rmp_unknown")).
+		Return(nil, fmt.Errorf("access token not found: %w", pgx.ErrNoRows))
+	claims, _, err = authenticator.AuthenticateAccessToken(s.ctx, "rmp_unknown")
+	s.Require().NoError(err)
+	assert.Nil(s.T(), claims, "unknown token")
+
+	expired := time.Now().Add(-time.Minute)
+	s.tokenRepo.On("GetByHash", s.ctx, hashOpaqueToken("rmp_expired")).
+		Return(&userentity.PersonalAccessToken{ID: uuid.New(), UserID: s.user.ID, ExpiresAt: &expired}, nil)
+	claims, _, err = authenticator.AuthenticateAccessToken(s.ctx, "rmp_expired")
+	s.Require().NoError(err)
+	assert.Nil(s.T(), claims, "expired token")
+}
+
+func TestAccessTokenUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AccessTokenUseCaseTestSuite))
+}
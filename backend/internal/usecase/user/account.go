@@ -0,0 +1,362 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userrepo "roadmap/internal/repository/user"
+)
+
+const (
+	// purgeBatchSize is how many deleted accounts are purged per query.
+	purgeBatchSize = 100
+
+	// recentSignInWindow is how long after signing in a user without a
+	// password can delete their account without another confirmation.
+	recentSignInWindow = 10 * time.Minute
+)
+
+// Export sections of the data owned by the user package itself.
+const (
+	ExportSectionAccount      = "account"
+	ExportSectionSessions     = "sessions"
+	ExportSectionPasskeys     = "passkeys"
+	ExportSectionIdentities   = "identities"
+	ExportSectionAccessTokens = "access_tokens"
+)
+
+// AccountExportFunc returns the data an aggregate stores about the user, as
+// a value that can be encoded as JSON.
+type AccountExportFunc func(ctx context.Context, userID uuid.UUID) (any, error)
+
+// AccountDeletionFunc removes the data an aggregate stores about the user.
+type AccountDeletionFunc func(ctx context.Context, userID uuid.UUID) error
+
+// AccountDataHooks lets other aggregates take part in data exports and
+// account purges. Data kept in tables with a cascading foreign key to users
+// is removed with the account and only needs an exporter; a deletion hook is
+// for data the cascade does not reach, such as files or rows that must be
+// anonymized rather than removed. Hooks are registered at startup.
+type AccountDataHooks struct {
+	exporters map[string]AccountExportFunc
+	deleters  []AccountDeletionFunc
+}
+
+func NewAccountDataHooks() *AccountDataHooks {
+	return &AccountDataHooks{
+		exporters: make(map[string]AccountExportFunc),
+	}
+}
+
+// RegisterExporter adds a section to every data export. Registering a
+// section name twice, or one of the ExportSection names, panics.
+func (h *AccountDataHooks) RegisterExporter(section string, export AccountExportFunc) {
+	if _, exists := h.exporters[section]; exists || isBuiltInExportSection(section) {
+		panic(fmt.Sprintf("account export section %q is already registered", section))
+	}
+	h.exporters[section] = export
+}
+
+// RegisterDeleter adds a hook that runs when a deleted account is purged,
+// before the user row is removed. An error keeps the account for the next
+// purge run.
+func (h *AccountDataHooks) RegisterDeleter(deleteData AccountDeletionFunc) {
+	h.deleters = append(h.deleters, deleteData)
+}
+
+func isBuiltInExportSection(section string) bool {
+	switch section {
+	case ExportSectionAccount, ExportSectionSessions, ExportSectionPasskeys,
+		ExportSectionIdentities, ExportSectionAccessTokens:
+		return true
+	}
+	return false
+}
+
+type DeleteAccountUseCase struct {
+	userRepository         userrepo.UserRepository
+	refreshTokenRepository userrepo.RefreshTokenRepository
+	sessionRepository      userrepo.SessionRepository
+	passwordHasher         PasswordHasher
+	suspensionService      *SuspensionService
+	gracePeriod            time.Duration
+}
+
+func NewDeleteAccountUseCase(
+	userRepository userrepo.UserRepository,
+	refreshTokenRepository userrepo.RefreshTokenRepository,
+	sessionRepository userrepo.SessionRepository,
+	passwordHasher PasswordHasher,
+	suspensionService *SuspensionService,
+	gracePeriod time.Duration,
+) *DeleteAccountUseCase {
+	return &DeleteAccountUseCase{
+		userRepository:         userRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		sessionRepository:      sessionRepository,
+		passwordHasher:         passwordHasher,
+		suspensionService:      suspensionService,
+		gracePeriod:            gracePeriod,
+	}
+}
+
+// Execute deletes the user's own account once they have confirmed their
+// password. Users without a password, such as those who only sign in with
+// an identity provider, confirm by having signed in within
+// recentSignInWindow in the session the request was made from, so they get
+// ErrReauthenticationRequired otherwise. The account is signed out and
+// disabled right away and purged by PurgeDeletedAccountsUseCase after the
+// grace period.
+func (u *DeleteAccountUseCase) Execute(
+	ctx context.Context,
+	userID string,
+	sessionID string,
+	req userdto.DeleteAccountRequest,
+) (userdto.DeleteAccountResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.DeleteAccountResponse{}, ErrInvalidTokenClaims
+	}
+
+	user, err := u.userRepository.GetByID(ctx, id)
+	if err != nil {
+		return userdto.DeleteAccountResponse{}, err
+	}
+
+	if user.PasswordHash == "" {
+		if err := u.checkRecentSignIn(ctx, user.ID, sessionID); err != nil {
+			return userdto.DeleteAccountResponse{}, err
+		}
+	} else {
+		matches, err := u.passwordHasher.Verify(req.Password, user.PasswordHash)
+		if err != nil {
+			return userdto.DeleteAccountResponse{}, err
+		}
+		if !matches {
+			return userdto.DeleteAccountResponse{}, ErrIncorrectPassword
+		}
+	}
+
+	if err := u.suspensionService.SoftDelete(ctx, user.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return userdto.DeleteAccountResponse{}, ErrUserNotFound
+		}
+		return userdto.DeleteAccountResponse{}, err
+	}
+
+	if err := u.refreshTokenRepository.RevokeAllForUser(ctx, user.ID); err != nil {
+		log.Printf("Failed to revoke refresh tokens of deleted user %s: %v", user.ID, err)
+	}
+
+	return userdto.DeleteAccountResponse{
+		Message: "Account scheduled for deletion",
+		PurgeAt: time.Now().UTC().Add(u.gracePeriod),
+	}, nil
+}
+
+// checkRecentSignIn returns ErrReauthenticationRequired unless sessionID is
+// an active session of the user that started within recentSignInWindow.
+func (u *DeleteAccountUseCase) checkRecentSignIn(ctx context.Context, userID uuid.UUID, sessionID string) error {
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return ErrReauthenticationRequired
+	}
+
+	now := time.Now().UTC()
+	sessions, err := u.sessionRepository.ListActiveByUser(ctx, userID, now)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID == id && now.Sub(session.CreatedAt) <= recentSignInWindow {
+			return nil
+		}
+	}
+	return ErrReauthenticationRequired
+}
+
+type PurgeDeletedAccountsUseCase struct {
+	userRepository userrepo.UserRepository
+	hooks          *AccountDataHooks
+	gracePeriod    time.Duration
+}
+
+func NewPurgeDeletedAccountsUseCase(
+	userRepository userrepo.UserRepository,
+	hooks *AccountDataHooks,
+	gracePeriod time.Duration,
+) *PurgeDeletedAccountsUseCase {
+	return &PurgeDeletedAccountsUseCase{
+		userRepository: userRepository,
+		hooks:          hooks,
+		gracePeriod:    gracePeriod,
+	}
+}
+
+// Execute permanently removes the accounts deleted longer than the grace
+// period ago and returns how many were removed. Accounts whose deletion
+// hooks fail are kept and retried on the next run.
+func (u *PurgeDeletedAccountsUseCase) Execute(ctx context.Context) (int64, error) {
+	before := time.Now().UTC().Add(-u.gracePeriod)
+
+	var purged int64
+	for {
+		ids, err := u.userRepository.ListDeletedBefore(ctx, before, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		var batchPurged int
+		for _, id := range ids {
+			if err := u.purge(ctx, id); err != nil {
+				log.Printf("Failed to purge deleted user %s: %v", id, err)
+				continue
+			}
+			batchPurged++
+		}
+		purged += int64(batchPurged)
+
+		// A batch without progress would be listed again as is.
+		if len(ids) < purgeBatchSize || batchPurged == 0 {
+			return purged, nil
+		}
+	}
+}
+
+func (u *PurgeDeletedAccountsUseCase) purge(ctx context.Context, id uuid.UUID) error {
+	if u.hooks != nil {
+		for _, deleteData := range u.hooks.deleters {
+			if err := deleteData(ctx, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := u.userRepository.Delete(ctx, id); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	return nil
+}
+
+type ExportAccountDataUseCase struct {
+	userRepository                userrepo.UserRepository
+	sessionRepository             userrepo.SessionRepository
+	passkeyRepository             userrepo.PasskeyRepository
+	userIdentityRepository        userrepo.UserIdentityRepository
+	personalAccessTokenRepository userrepo.PersonalAccessTokenRepository
+	hooks                         *AccountDataHooks
+}
+
+func NewExportAccountDataUseCase(
+	userRepository userrepo.UserRepository,
+	sessionRepository userrepo.SessionRepository,
+	passkeyRepository userrepo.PasskeyRepository,
+	userIdentityRepository userrepo.UserIdentityRepository,
+	personalAccessTokenRepository userrepo.PersonalAccessTokenRepository,
+	hooks *AccountDataHooks,
+) *ExportAccountDataUseCase {
+	return &ExportAccountDataUseCase{
+		userRepository:                userRepository,
+		sessionRepository:             sessionRepository,
+		passkeyRepository:             passkeyRepository,
+		userIdentityRepository:        userIdentityRepository,
+		personalAccessTokenRepository: personalAccessTokenRepository,
+		hooks:                         hooks,
+	}
+}
+
+// Execute collects everything stored about the user: their account, sign-in
+// methods and sessions, and the sections of every registered exporter.
+// Secrets such as password and token hashes are left out.
+func (u *ExportAccountDataUseCase) Execute(ctx context.Context, userID string) (*userdto.AccountExport, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, ErrInvalidTokenClaims
+	}
+
+	user, err := u.userRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	export := &userdto.AccountExport{
+		UserID:     user.ID,
+		ExportedAt: now,
+		Sections: map[string]any{
+			ExportSectionAccount: newProfileResponse(user),
+		},
+	}
+
+	sessions, err := u.sessionRepository.ListActiveByUser(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+	sessionResponses := make([]userdto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		sessionResponses = append(sessionResponses, userdto.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+		})
+	}
+	export.Sections[ExportSectionSessions] = sessionResponses
+
+	passkeys, err := u.passkeyRepository.ListByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	passkeyResponses := make([]userdto.PasskeyResponse, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		passkeyResponses = append(passkeyResponses, newPasskeyResponse(passkey))
+	}
+	export.Sections[ExportSectionPasskeys] = passkeyResponses
+
+	identities, err := u.userIdentityRepository.ListByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	identityExports := make([]userdto.IdentityExport, 0, len(identities))
+	for _, identity := range identities {
+		identityExports = append(identityExports, userdto.IdentityExport{
+			Provider:    identity.Provider,
+			Subject:     identity.Subject,
+			Email:       identity.Email,
+			LastLoginAt: identity.LastLoginAt,
+			CreatedAt:   identity.CreatedAt,
+		})
+	}
+	export.Sections[ExportSectionIdentities] = identityExports
+
+	tokens, err := u.personalAccessTokenRepository.ListByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tokenResponses := make([]userdto.AccessTokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		tokenResponses = append(tokenResponses, newAccessTokenResponse(token))
+	}
+	export.Sections[ExportSectionAccessTokens] = tokenResponses
+
+	if u.hooks != nil {
+		for section, exportData := range u.hooks.exporters {
+			data, err := exportData(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to export %s: %w", section, err)
+			}
+			export.Sections[section] = data
+		}
+	}
+
+	return export, nil
+}
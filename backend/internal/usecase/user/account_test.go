@@ -0,0 +1,242 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+)
+
+type AccountUseCaseTestSuite struct {
+	suite.Suite
+	userRepo     *MockUserRepository
+	refreshRepo  *MockRefreshTokenRepository
+	sessionRepo  *MockSessionRepository
+	passkeyRepo  *MockPasskeyRepository
+	identityRepo *MockUserIdentityRepository
+	tokenRepo    *MockPersonalAccessTokenRepository
+	suspension   *SuspensionService
+	hooks        *AccountDataHooks
+	user         *userentity.User
+	ctx          context.Context
+}
+
+func (s *AccountUseCaseTestSuite) SetupTest() {
+	s.userRepo = new(MockUserRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.sessionRepo = new(MockSessionRepository)
+	s.passkeyRepo = new(MockPasskeyRepository)
+	s.identityRepo = new(MockUserIdentityRepository)
+	s.tokenRepo = new(MockPersonalAccessTokenRepository)
+	s.suspension = NewSuspensionService(s.userRepo, time.Minute)
+	s.hooks = NewAccountDataHooks()
+
+	passwordHash, _ := testPasswordHasher.Hash("SecurePass123!")
+	s.user = &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser",
+		Email:        "test@example.com",
+		PasswordHash: passwordHash,
+	}
+	s.ctx = context.Background()
+}
+
+func (s *AccountUseCaseTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.sessionRepo.AssertExpectations(s.T())
+	s.passkeyRepo.AssertExpectations(s.T())
+	s.identityRepo.AssertExpectations(s.T())
+	s.tokenRepo.AssertExpectations(s.T())
+}
+
+func (s *AccountUseCaseTestSuite) newDeleteUseCase() *DeleteAccountUseCase {
+	return NewDeleteAccountUseCase(s.userRepo, s.refreshRepo, s.sessionRepo, testPasswordHasher, s.suspension, 30*24*time.Hour)
+}
+
+func (s *AccountUseCaseTestSuite) TestDelete_Success() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("SoftDelete", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("RevokeAllForUser", s.ctx, s.user.ID).Return(nil)
+
+	response, err := s.newDeleteUseCase().Execute(s.ctx, s.user.ID.String(), "", userdto.DeleteAccountRequest{
+		Password: "SecurePass123!",
+	})
+
+	s.NoError(err)
+	s.WithinDuration(time.Now().UTC().Add(30*24*time.Hour), response.PurgeAt, time.Minute)
+
+	suspended, err := s.suspension.IsSuspended(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.True(suspended)
+}
+
+func (s *AccountUseCaseTestSuite) TestDelete_IncorrectPassword() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+
+	_, err := s.newDeleteUseCase().Execute(s.ctx, s.user.ID.String(), "", userdto.DeleteAccountRequest{
+		Password: "wrong-password",
+	})
+
+	s.ErrorIs(err, ErrIncorrectPassword)
+	s.userRepo.AssertNotCalled(s.T(), "SoftDelete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *AccountUseCaseTestSuite) TestDelete_AlreadyDeleted() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("SoftDelete", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).Return(pgx.ErrNoRows)
+
+	_, err := s.newDeleteUseCase().Execute(s.ctx, s.user.ID.String(), "", userdto.DeleteAccountRequest{
+		Password: "SecurePass123!",
+	})
+
+	s.ErrorIs(err, ErrUserNotFound)
+}
+
+func (s *AccountUseCaseTestSuite) TestDelete_InvalidUserID() {
+	_, err := s.newDeleteUseCase().Execute(s.ctx, "invalid", "", userdto.DeleteAccountRequest{Password: "x"})
+
+	s.ErrorIs(err, ErrInvalidTokenClaims)
+}
+
+func (s *AccountUseCaseTestSuite) TestDelete_WithoutPassword_RecentSignIn() {
+	s.user.PasswordHash = ""
+	session := &userentity.Session{ID: uuid.New(), UserID: s.user.ID, CreatedAt: time.Now().UTC().Add(-time.Minute)}
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.sessionRepo.On("ListActiveByUser", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).
+		Return([]*userentity.Session{session}, nil)
+	s.userRepo.On("SoftDelete", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("RevokeAllForUser", s.ctx, s.user.ID).Return(nil)
+
+	_, err := s.newDeleteUseCase().Execute(s.ctx, s.user.ID.String(), session.ID.String(), userdto.DeleteAccountRequest{})
+
+	s.NoError(err)
+}
+
+func (s *AccountUseCaseTestSuite) TestDelete_WithoutPassword_StaleSignIn() {
+	s.user.PasswordHash = ""
+	stale := &userentity.Session{ID: uuid.New(), UserID: s.user.ID, CreatedAt: time.Now().UTC().Add(-time.Hour)}
+	recent := &userentity.Session{ID: uuid.New(), UserID: s.user.ID, CreatedAt: time.Now().UTC()}
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.sessionRepo.On("ListActiveByUser", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).
+		Return([]*userentity.Session{recent, stale}, nil)
+
+	// Only the session the request was made from counts.
+	_, err := s.newDeleteUseCase().Execute(s.ctx, s.user.ID.String(), stale.ID.String(), userdto.DeleteAccountRequest{
+		Password: "anything",
+	})
+
+	s.ErrorIs(err, ErrReauthenticationRequired)
+	s.userRepo.AssertNotCalled(s.T(), "SoftDelete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *AccountUseCaseTestSuite) TestDelete_WithoutPassword_NoSession() {
+	s.user.PasswordHash = ""
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+
+	_, err := s.newDeleteUseCase().Execute(s.ctx, s.user.ID.String(), "", userdto.DeleteAccountRequest{})
+
+	s.ErrorIs(err, ErrReauthenticationRequired)
+}
+
+func (s *AccountUseCaseTestSuite) TestPurge_RunsDeletionHooks() {
+	kept := uuid.New()
+	var hooked []uuid.UUID
+	s.hooks.RegisterDeleter(func(_ context.Context, userID uuid.UUID) error {
+		hooked = append(hooked, userID)
+		if userID == kept {
+			return errors.New("storage unavailable")
+		}
+		return nil
+	})
+
+	s.userRepo.On("ListDeletedBefore", s.ctx, mock.AnythingOfType("time.Time"), purgeBatchSize).
+		Return([]uuid.UUID{s.user.ID, kept}, nil)
+	s.userRepo.On("Delete", s.ctx, s.user.ID).Return(nil)
+
+	purged, err := NewPurgeDeletedAccountsUseCase(s.userRepo, s.hooks, time.Hour).Execute(s.ctx)
+
+	s.NoError(err)
+	s.Equal(int64(1), purged)
+	s.Equal([]uuid.UUID{s.user.ID, kept}, hooked)
+	s.userRepo.AssertNotCalled(s.T(), "Delete", s.ctx, kept)
+}
+
+func (s *AccountUseCaseTestSuite) TestPurge_UsesGracePeriod() {
+	s.userRepo.On("ListDeletedBefore", s.ctx, mock.MatchedBy(func(before time.Time) bool {
+		return before.Sub(time.Now().UTC().Add(-48*time.Hour)).Abs() < time.Minute
+	}), purgeBatchSize).Return([]uuid.UUID{}, nil)
+
+	purged, err := NewPurgeDeletedAccountsUseCase(s.userRepo, nil, 48*time.Hour).Execute(s.ctx)
+
+	s.NoError(err)
+	s.Zero(purged)
+}
+
+func (s *AccountUseCaseTestSuite) TestExport_CollectsSections() {
+	now := time.Now().UTC()
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.sessionRepo.On("ListActiveByUser", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).
+		Return([]*userentity.Session{{ID: uuid.New(), UserID: s.user.ID, UserAgent: "test-agent", CreatedAt: now}}, nil)
+	s.passkeyRepo.On("ListByUser", s.ctx, s.user.ID).
+		Return([]*userentity.Passkey{{ID: uuid.New(), UserID: s.user.ID, Name: "Laptop"}}, nil)
+	s.identityRepo.On("ListByUser", s.ctx, s.user.ID).
+		Return([]*userentity.UserIdentity{{ID: uuid.New(), UserID: s.user.ID, Provider: "google", Subject: "123"}}, nil)
+	s.tokenRepo.On("ListByUser", s.ctx, s.user.ID).Return([]*userentity.PersonalAccessToken{}, nil)
+
+	s.hooks.RegisterExporter("roadmaps", func(_ context.Context, userID uuid.UUID) (any, error) {
+		return []string{"roadmap of " + userID.String()}, nil
+	})
+
+	useCase := NewExportAccountDataUseCase(s.userRepo, s.sessionRepo, s.passkeyRepo, s.identityRepo, s.tokenRepo, s.hooks)
+	export, err := useCase.Execute(s.ctx, s.user.ID.String())
+
+	s.Require().NoError(err)
+	s.Equal(s.user.ID, export.UserID)
+	s.Equal(newProfileResponse(s.user), export.Sections[ExportSectionAccount])
+	s.Len(export.Sections[ExportSectionSessions], 1)
+	s.Len(export.Sections[ExportSectionPasskeys], 1)
+	s.Equal("123", export.Sections[ExportSectionIdentities].([]userdto.IdentityExport)[0].Subject)
+	s.Empty(export.Sections[ExportSectionAccessTokens])
+	s.Equal([]string{"roadmap of " + s.user.ID.String()}, export.Sections["roadmaps"])
+}
+
+func (s *AccountUseCaseTestSuite) TestExport_HookError() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.sessionRepo.On("ListActiveByUser", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).
+		Return([]*userentity.Session{}, nil)
+	s.passkeyRepo.On("ListByUser", s.ctx, s.user.ID).Return([]*userentity.Passkey{}, nil)
+	s.identityRepo.On("ListByUser", s.ctx, s.user.ID).Return([]*userentity.UserIdentity{}, nil)
+	s.tokenRepo.On("ListByUser", s.ctx, s.user.ID).Return([]*userentity.PersonalAccessToken{}, nil)
+
+	s.hooks.RegisterExporter("roadmaps", func(context.Context, uuid.UUID) (any, error) {
+		return nil, errors.New("database error")
+	})
+
+	useCase := NewExportAccountDataUseCase(s.userRepo, s.sessionRepo, s.passkeyRepo, s.identityRepo, s.tokenRepo, s.hooks)
+	_, err := useCase.Execute(s.ctx, s.user.ID.String())
+
+	s.Error(err)
+}
+
+func TestAccountUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountUseCaseTestSuite))
+}
+
+func TestAccountDataHooks_RegisterExporterTwice(t *testing.T) {
+	hooks := NewAccountDataHooks()
+	export := func(context.Context, uuid.UUID) (any, error) { return nil, nil }
+	hooks.RegisterExporter("roadmaps", export)
+
+	assert.Panics(t, func() { hooks.RegisterExporter("roadmaps", export) })
+	assert.Panics(t, func() { hooks.RegisterExporter(ExportSectionAccount, export) })
+}
@@ -0,0 +1,295 @@
+package user
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	userrepo "roadmap/internal/repository/user"
+)
+
+func newAdminUserResponse(user *userentity.User) userdto.AdminUserResponse {
+	roles := user.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	return userdto.AdminUserResponse{
+		ID:               user.ID,
+		Username:         user.Username,
+		Email:            user.Email,
+		DisplayName:      user.DisplayName,
+		Bio:              user.Bio,
+		EmailVerified:    user.IsEmailVerified(),
+		Roles:            roles,
+		SuspendedAt:      user.SuspendedAt,
+		SuspensionReason: user.SuspensionReason,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+	}
+}
+
+// getOtherUser is getTargetUser for actions an administrator may not take on
+// their own account.
+func getOtherUser(
+	ctx context.Context,
+	userRepository userrepo.UserRepository,
+	actorID string,
+	userID string,
+) (*userentity.User, error) {
+	actor, err := uuid.Parse(actorID)
+	if err != nil {
+		return nil, ErrInvalidTokenClaims
+	}
+
+	user, err := getTargetUser(ctx, userRepository, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ID == actor {
+		return nil, ErrCannotModifySelf
+	}
+
+	return user, nil
+}
+
+type ListUsersUseCase struct {
+	userRepository userrepo.UserRepository
+}
+
+func NewListUsersUseCase(userRepository userrepo.UserRepository) *ListUsersUseCase {
+	return &ListUsersUseCase{
+		userRepository: userRepository,
+	}
+}
+
+func (u *ListUsersUseCase) Execute(ctx context.Context, req userdto.ListUsersRequest) (userdto.ListUsersResponse, error) {
+	if req.CreatedAfter != nil && req.CreatedBefore != nil && !req.CreatedBefore.After(*req.CreatedAfter) {
+		return userdto.ListUsersResponse{}, ErrInvalidCreatedRange
+	}
+
+	page := req.Page
+	if page == 0 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = userdto.DefaultUserPageSize
+	}
+
+	users, total, err := u.userRepository.List(ctx, userrepo.UserListFilter{
+		Email:         req.Email,
+		Username:      req.Username,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Limit:         pageSize,
+		Offset:        (page - 1) * pageSize,
+	})
+	if err != nil {
+		return userdto.ListUsersResponse{}, err
+	}
+
+	response := userdto.ListUsersResponse{
+		Users:    make([]userdto.AdminUserResponse, 0, len(users)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for _, user := range users {
+		response.Users = append(response.Users, newAdminUserResponse(user))
+	}
+
+	return response, nil
+}
+
+type GetUserUseCase struct {
+	userRepository userrepo.UserRepository
+}
+
+func NewGetUserUseCase(userRepository userrepo.UserRepository) *GetUserUseCase {
+	return &GetUserUseCase{
+		userRepository: userRepository,
+	}
+}
+
+func (u *GetUserUseCase) Execute(ctx context.Context, userID string) (userdto.AdminUserResponse, error) {
+	user, err := getTargetUser(ctx, u.userRepository, userID)
+	if err != nil {
+		return userdto.AdminUserResponse{}, err
+	}
+
+	return newAdminUserResponse(user), nil
+}
+
+type SuspendUserUseCase struct {
+	userRepository         userrepo.UserRepository
+	suspensionService      *SuspensionService
+	refreshTokenRepository userrepo.RefreshTokenRepository
+}
+
+func NewSuspendUserUseCase(
+	userRepository userrepo.UserRepository,
+	suspensionService *SuspensionService,
+	refreshTokenRepository userrepo.RefreshTokenRepository,
+) *SuspendUserUseCase {
+	return &SuspendUserUseCase{
+		userRepository:         userRepository,
+		suspensionService:      suspensionService,
+		refreshTokenRepository: refreshTokenRepository,
+	}
+}
+
+// Execute suspends userID. Suspending an already suspended user replaces the
+// reason. The user's refresh tokens are revoked; access tokens are rejected
+// by AuthMiddleware for as long as the suspension lasts.
+func (u *SuspendUserUseCase) Execute(
+	ctx context.Context,
+	actorID string,
+	userID string,
+	req userdto.SuspendUserRequest,
+) (userdto.AdminUserResponse, error) {
+	user, err := getOtherUser(ctx, u.userRepository, actorID, userID)
+	if err != nil {
+		return userdto.AdminUserResponse{}, err
+	}
+
+	if err := u.suspensionService.Suspend(ctx, user.ID, req.Reason); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return userdto.AdminUserResponse{}, ErrUserNotFound
+		}
+		return userdto.AdminUserResponse{}, err
+	}
+
+	if err := u.refreshTokenRepository.RevokeAllForUser(ctx, user.ID); err != nil {
+		return userdto.AdminUserResponse{}, err
+	}
+
+	user, err = u.userRepository.GetByID(ctx, user.ID)
+	if err != nil {
+		return userdto.AdminUserResponse{}, err
+	}
+
+	return newAdminUserResponse(user), nil
+}
+
+type UnsuspendUserUseCase struct {
+	userRepository    userrepo.UserRepository
+	suspensionService *SuspensionService
+}
+
+func NewUnsuspendUserUseCase(
+	userRepository userrepo.UserRepository,
+	suspensionService *SuspensionService,
+) *UnsuspendUserUseCase {
+	return &UnsuspendUserUseCase{
+		userRepository:    userRepository,
+		suspensionService: suspensionService,
+	}
+}
+
+func (u *UnsuspendUserUseCase) Execute(ctx context.Context, userID string) (userdto.AdminUserResponse, error) {
+	user, err := getTargetUser(ctx, u.userRepository, userID)
+	if err != nil {
+		return userdto.AdminUserResponse{}, err
+	}
+
+	if err := u.suspensionService.Unsuspend(ctx, user.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return userdto.AdminUserResponse{}, ErrUserNotFound
+		}
+		return userdto.AdminUserResponse{}, err
+	}
+
+	user, err = u.userRepository.GetByID(ctx, user.ID)
+	if err != nil {
+		return userdto.AdminUserResponse{}, err
+	}
+
+	return newAdminUserResponse(user), nil
+}
+
+type ForcePasswordResetUseCase struct {
+	userRepository         userrepo.UserRepository
+	refreshTokenRepository userrepo.RefreshTokenRepository
+	revocationService      *TokenRevocationService
+	resetSender            *PasswordResetSender
+}
+
+func NewForcePasswordResetUseCase(
+	userRepository userrepo.UserRepository,
+	refreshTokenRepository userrepo.RefreshTokenRepository,
+	revocationService *TokenRevocationService,
+	resetSender *PasswordResetSender,
+) *ForcePasswordResetUseCase {
+	return &ForcePasswordResetUseCase{
+		userRepository:         userRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		revocationService:      revocationService,
+		resetSender:            resetSender,
+	}
+}
+
+// Execute clears the user's password so it can no longer be used to log in,
+// signs the user out everywhere and mails them a password reset link.
+// Passkeys and linked identity providers keep working.
+func (u *ForcePasswordResetUseCase) Execute(ctx context.Context, actorID string, userID string) error {
+	user, err := getOtherUser(ctx, u.userRepository, actorID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.userRepository.UpdatePassword(ctx, user.ID, ""); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if err := u.revocationService.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := u.refreshTokenRepository.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return u.resetSender.SendForced(ctx, user)
+}
+
+type DeleteUserUseCase struct {
+	userRepository    userrepo.UserRepository
+	suspensionService *SuspensionService
+}
+
+func NewDeleteUserUseCase(
+	userRepository userrepo.UserRepository,
+	suspensionService *SuspensionService,
+) *DeleteUserUseCase {
+	return &DeleteUserUseCase{
+		userRepository:    userRepository,
+		suspensionService: suspensionService,
+	}
+}
+
+// Execute permanently deletes userID together with everything that belongs
+// to the account.
+func (u *DeleteUserUseCase) Execute(ctx context.Context, actorID string, userID string) error {
+	user, err := getOtherUser(ctx, u.userRepository, actorID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.suspensionService.Delete(ctx, user.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
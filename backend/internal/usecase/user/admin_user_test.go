@@ -0,0 +1,216 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/mailer"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type AdminUserUseCaseTestSuite struct {
+	suite.Suite
+	userRepo       *MockUserRepository
+	refreshRepo    *MockRefreshTokenRepository
+	revocationRepo *MockTokenRevocationRepository
+	resetRepo      *MockPasswordResetTokenRepository
+	mailer         *MockMailer
+	suspension     *SuspensionService
+	admin          *userentity.User
+	user           *userentity.User
+	ctx            context.Context
+}
+
+func (s *AdminUserUseCaseTestSuite) SetupTest() {
+	s.userRepo = new(MockUserRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.revocationRepo = new(MockTokenRevocationRepository)
+	s.resetRepo = new(MockPasswordResetTokenRepository)
+	s.mailer = new(MockMailer)
+	s.suspension = NewSuspensionService(s.userRepo, time.Minute)
+	s.ctx = context.Background()
+
+	s.admin = &userentity.User{ID: uuid.New(), Roles: []string{userentity.RoleAdmin, userentity.RoleMember}}
+	s.user = &userentity.User{
+		ID:       uuid.New(),
+		Username: "testuser",
+		Email:    "test@example.com",
+		Roles:    []string{userentity.RoleMember},
+	}
+}
+
+func (s *AdminUserUseCaseTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.revocationRepo.AssertExpectations(s.T())
+	s.resetRepo.AssertExpectations(s.T())
+	s.mailer.AssertExpectations(s.T())
+}
+
+func (s *AdminUserUseCaseTestSuite) TestListUsers_Paginates() {
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.userRepo.On("List", s.ctx, userrepo.UserListFilter{
+		Email:        "example",
+		CreatedAfter: &after,
+		Limit:        10,
+		Offset:       20,
+	}).Return([]*userentity.User{s.user}, 21, nil)
+
+	response, err := NewListUsersUseCase(s.userRepo).Execute(s.ctx, userdto.ListUsersRequest{
+		Email:        "example",
+		CreatedAfter: &after,
+		Page:         3,
+		PageSize:     10,
+	})
+
+	s.Require().NoError(err)
+	assert.Equal(s.T(), 21, response.Total)
+	assert.Equal(s.T(), 3, response.Page)
+	s.Require().Len(response.Users, 1)
+	assert.Equal(s.T(), s.user.ID, response.Users[0].ID)
+}
+
+func (s *AdminUserUseCaseTestSuite) TestListUsers_Defaults() {
+	s.userRepo.On("List", s.ctx, userrepo.UserListFilter{Limit: userdto.DefaultUserPageSize}).
+		Return([]*userentity.User{}, 0, nil)
+
+	response, err := NewListUsersUseCase(s.userRepo).Execute(s.ctx, userdto.ListUsersRequest{})
+
+	s.Require().NoError(err)
+	assert.Equal(s.T(), 1, response.Page)
+	assert.Equal(s.T(), userdto.DefaultUserPageSize, response.PageSize)
+	assert.NotNil(s.T(), response.Users)
+}
+
+func (s *AdminUserUseCaseTestSuite) TestListUsers_InvalidRange() {
+	now := time.Now()
+
+	_, err := NewListUsersUseCase(s.userRepo).Execute(s.ctx, userdto.ListUsersRequest{
+		CreatedAfter:  &now,
+		CreatedBefore: &now,
+	})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidCreatedRange)
+}
+
+func (s *AdminUserUseCaseTestSuite) TestSuspendUser() {
+	suspended := *s.user
+	suspendedAt := time.Now()
+	suspended.SuspendedAt = &suspendedAt
+	suspended.SuspensionReason = "spam"
+
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil).Once()
+	s.userRepo.On("Suspend", s.ctx, s.user.ID, "spam", mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("RevokeAllForUser", s.ctx, s.user.ID).Return(nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(&suspended, nil).Once()
+
+	response, err := NewSuspendUserUseCase(s.userRepo, s.suspension, s.refreshRepo).
+		Execute(s.ctx, s.admin.ID.String(), s.user.ID.String(), userdto.SuspendUserRequest{Reason: "spam"})
+
+	s.Require().NoError(err)
+	assert.NotNil(s.T(), response.SuspendedAt)
+	assert.Equal(s.T(), "spam", response.SuspensionReason)
+
+	// Answered from the cache without another repository call.
+	isSuspended, err := s.suspension.IsSuspended(s.ctx, s.user.ID.String())
+	s.Require().NoError(err)
+	assert.True(s.T(), isSuspended)
+}
+
+func (s *AdminUserUseCaseTestSuite) TestSuspendUser_Self() {
+	s.userRepo.On("GetByID", s.ctx, s.admin.ID).Return(s.admin, nil)
+
+	_, err := NewSuspendUserUseCase(s.userRepo, s.suspension, s.refreshRepo).
+		Execute(s.ctx, s.admin.ID.String(), s.admin.ID.String(), userdto.SuspendUserRequest{})
+
+	assert.ErrorIs(s.T(), err, ErrCannotModifySelf)
+}
+
+func (s *AdminUserUseCaseTestSuite) TestUnsuspendUser() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("Unsuspend", s.ctx, s.user.ID).Return(nil)
+
+	response, err := NewUnsuspendUserUseCase(s.userRepo, s.suspension).Execute(s.ctx, s.user.ID.String())
+
+	s.Require().NoError(err)
+	assert.Nil(s.T(), response.SuspendedAt)
+
+	isSuspended, err := s.suspension.IsSuspended(s.ctx, s.user.ID.String())
+	s.Require().NoError(err)
+	assert.False(s.T(), isSuspended)
+}
+
+func (s *AdminUserUseCaseTestSuite) TestForcePasswordReset() {
+	var sent mailer.Message
+
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("UpdatePassword", s.ctx, s.user.ID, "").Return(nil)
+	s.revocationRepo.On("SetTokensValidAfter", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("RevokeAllForUser", s.ctx, s.user.ID).Return(nil)
+	s.resetRepo.On("InvalidateForUser", s.ctx, s.user.ID).Return(nil)
+	s.resetRepo.On("Create", s.ctx, mock.AnythingOfType("*user.PasswordResetToken")).
+		Return(&userentity.PasswordResetToken{}, nil)
+	s.mailer.On("Send", s.ctx, mock.AnythingOfType("mailer.Message")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(mailer.Message) }).
+		Return(nil)
+
+	resetSender := NewPasswordResetSender(s.resetRepo, s.mailer, "https://app.example.com/reset-password", time.Hour)
+	err := NewForcePasswordResetUseCase(
+		s.userRepo, s.refreshRepo, NewTokenRevocationService(s.revocationRepo, time.Minute), resetSender,
+	).Execute(s.ctx, s.admin.ID.String(), s.user.ID.String())
+
+	s.Require().NoError(err)
+	assert.Equal(s.T(), s.user.Email, sent.To)
+	assert.Contains(s.T(), sent.Body, "https://app.example.com/reset-password?token=")
+}
+
+func (s *AdminUserUseCaseTestSuite) TestDeleteUser() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("Delete", s.ctx, s.user.ID).Return(nil)
+
+	err := NewDeleteUserUseCase(s.userRepo, s.suspension).Execute(s.ctx, s.admin.ID.String(), s.user.ID.String())
+
+	s.Require().NoError(err)
+
+	isSuspended, err := s.suspension.IsSuspended(s.ctx, s.user.ID.String())
+	s.Require().NoError(err)
+	assert.True(s.T(), isSuspended, "tokens of deleted users must be rejected")
+}
+
+func (s *AdminUserUseCaseTestSuite) TestDeleteUser_NotFound() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(nil, fmt.Errorf("user not found: %w", pgx.ErrNoRows))
+
+	err := NewDeleteUserUseCase(s.userRepo, s.suspension).Execute(s.ctx, s.admin.ID.String(), s.user.ID.String())
+
+	assert.ErrorIs(s.T(), err, ErrUserNotFound)
+}
+
+func (s *AdminUserUseCaseTestSuite) TestSuspensionService_CachesAnswers() {
+	s.userRepo.On("IsSuspended", s.ctx, s.user.ID).Return(false, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		suspended, err := s.suspension.IsSuspended(s.ctx, s.user.ID.String())
+		s.Require().NoError(err)
+		assert.False(s.T(), suspended)
+	}
+
+	missing := uuid.New()
+	s.userRepo.On("IsSuspended", s.ctx, missing).Return(false, fmt.Errorf("user not found: %w", pgx.ErrNoRows))
+	suspended, err := s.suspension.IsSuspended(s.ctx, missing.String())
+	s.Require().NoError(err)
+	assert.True(s.T(), suspended, "missing users count as suspended")
+}
+
+func TestAdminUserUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminUserUseCaseTestSuite))
+}
@@ -0,0 +1,95 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type ChangePasswordUseCase struct {
+	userRepository         userrepo.UserRepository
+	passwordHasher         PasswordHasher
+	passwordValidator      *PasswordValidator
+	refreshTokenRepository userrepo.RefreshTokenRepository
+	revocationService      *TokenRevocationService
+	tokenIssuer            *TokenIssuer
+}
+
+func NewChangePasswordUseCase(
+	userRepository userrepo.UserRepository,
+	passwordHasher PasswordHasher,
+	passwordValidator *PasswordValidator,
+	refreshTokenRepository userrepo.RefreshTokenRepository,
+	revocationService *TokenRevocationService,
+	tokenIssuer *TokenIssuer,
+) *ChangePasswordUseCase {
+	return &ChangePasswordUseCase{
+		userRepository:         userRepository,
+		passwordHasher:         passwordHasher,
+		passwordValidator:      passwordValidator,
+		refreshTokenRepository: refreshTokenRepository,
+		revocationService:      revocationService,
+		tokenIssuer:            tokenIssuer,
+	}
+}
+
+// Execute sets a new password once the current one is confirmed. Every
+// existing session of the user is revoked, including the one making the
+// request, which continues with the returned tokens.
+func (u *ChangePasswordUseCase) Execute(
+	ctx context.Context,
+	userID string,
+	req userdto.ChangePasswordRequest,
+	client userdto.ClientInfo,
+) (userdto.ChangePasswordResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.ChangePasswordResponse{}, ErrInvalidTokenClaims
+	}
+
+	user, err := u.userRepository.GetByID(ctx, id)
+	if err != nil {
+		return userdto.ChangePasswordResponse{}, err
+	}
+
+	matches, err := u.passwordHasher.Verify(req.CurrentPassword, user.PasswordHash)
+	if err != nil {
+		return userdto.ChangePasswordResponse{}, err
+	}
+	if !matches {
+		return userdto.ChangePasswordResponse{}, ErrIncorrectPassword
+	}
+
+	if err := u.passwordValidator.Validate(req.NewPassword, user.Email, user.Username); err != nil {
+		return userdto.ChangePasswordResponse{}, err
+	}
+
+	passwordHash, err := u.passwordHasher.Hash(req.NewPassword)
+	if err != nil {
+		return userdto.ChangePasswordResponse{}, err
+	}
+
+	if err := u.userRepository.UpdatePassword(ctx, user.ID, passwordHash); err != nil {
+		return userdto.ChangePasswordResponse{}, err
+	}
+
+	if err := u.revocationService.RevokeAllForUser(ctx, user.ID); err != nil {
+		return userdto.ChangePasswordResponse{}, err
+	}
+	if err := u.refreshTokenRepository.RevokeAllForUser(ctx, user.ID); err != nil {
+		return userdto.ChangePasswordResponse{}, err
+	}
+
+	tokens, err := u.tokenIssuer.Issue(ctx, user, client)
+	if err != nil {
+		return userdto.ChangePasswordResponse{}, err
+	}
+
+	return userdto.ChangePasswordResponse{
+		TokenPair: tokens,
+		Message:   "Password has been changed",
+	}, nil
+}
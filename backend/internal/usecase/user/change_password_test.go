@@ -0,0 +1,124 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+)
+
+type ChangePasswordUseCaseTestSuite struct {
+	suite.Suite
+	useCase        *ChangePasswordUseCase
+	mockRepo       *MockUserRepository
+	refreshRepo    *MockRefreshTokenRepository
+	revocationRepo *MockTokenRevocationRepository
+	user           *userentity.User
+	ctx            context.Context
+}
+
+func (s *ChangePasswordUseCaseTestSuite) SetupTest() {
+	s.mockRepo = new(MockUserRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.revocationRepo = new(MockTokenRevocationRepository)
+	s.useCase = NewChangePasswordUseCase(
+		s.mockRepo,
+		testPasswordHasher,
+		testPasswordValidator,
+		s.refreshRepo,
+		NewTokenRevocationService(s.revocationRepo, time.Minute),
+		newTestTokenIssuer(s.refreshRepo),
+	)
+	s.ctx = context.Background()
+
+	passwordHash, _ := testPasswordHasher.Hash("OldPassword123!")
+	s.user = &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser",
+		Email:        "test@example.com",
+		PasswordHash: passwordHash,
+	}
+}
+
+func (s *ChangePasswordUseCaseTestSuite) TearDownTest() {
+	s.mockRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.revocationRepo.AssertExpectations(s.T())
+}
+
+func (s *ChangePasswordUseCaseTestSuite) TestChangePassword_Success() {
+	s.mockRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.mockRepo.On("UpdatePassword", s.ctx, s.user.ID, mock.MatchedBy(func(hash string) bool {
+		matches, err := testPasswordHasher.Verify("NewPassword123!", hash)
+		return err == nil && matches
+	})).Return(nil)
+	s.revocationRepo.On("SetTokensValidAfter", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+	s.refreshRepo.On("RevokeAllForUser", s.ctx, s.user.ID).Return(nil).Once()
+	s.refreshRepo.On("Create", s.ctx, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil).Once()
+
+	response, err := s.useCase.Execute(s.ctx, s.user.ID.String(), userdto.ChangePasswordRequest{
+		CurrentPassword: "OldPassword123!",
+		NewPassword:     "NewPassword123!",
+	}, userdto.ClientInfo{})
+
+	s.Require().NoError(err)
+	s.NotEmpty(response.Token)
+	s.NotEmpty(response.RefreshToken)
+}
+
+func (s *ChangePasswordUseCaseTestSuite) TestChangePassword_RevocationFails() {
+	s.mockRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.mockRepo.On("UpdatePassword", s.ctx, s.user.ID, mock.AnythingOfType("string")).Return(nil)
+	s.revocationRepo.On("SetTokensValidAfter", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).
+		Return(errors.New("database error"))
+
+	_, err := s.useCase.Execute(s.ctx, s.user.ID.String(), userdto.ChangePasswordRequest{
+		CurrentPassword: "OldPassword123!",
+		NewPassword:     "NewPassword123!",
+	}, userdto.ClientInfo{})
+
+	s.Error(err)
+	s.refreshRepo.AssertNotCalled(s.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (s *ChangePasswordUseCaseTestSuite) TestChangePassword_IncorrectCurrentPassword() {
+	s.mockRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+
+	_, err := s.useCase.Execute(s.ctx, s.user.ID.String(), userdto.ChangePasswordRequest{
+		CurrentPassword: "WrongPassword123!",
+		NewPassword:     "NewPassword123!",
+	}, userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrIncorrectPassword)
+}
+
+func (s *ChangePasswordUseCaseTestSuite) TestChangePassword_WeakNewPassword() {
+	s.mockRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+
+	_, err := s.useCase.Execute(s.ctx, s.user.ID.String(), userdto.ChangePasswordRequest{
+		CurrentPassword: "OldPassword123!",
+		NewPassword:     "alllowercase",
+	}, userdto.ClientInfo{})
+
+	var passwordErr *PasswordValidationError
+	assert.ErrorAs(s.T(), err, &passwordErr)
+	s.mockRepo.AssertNotCalled(s.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ChangePasswordUseCaseTestSuite) TestChangePassword_InvalidUserID() {
+	_, err := s.useCase.Execute(s.ctx, "not-a-uuid", userdto.ChangePasswordRequest{}, userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidTokenClaims)
+}
+
+func TestChangePasswordUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ChangePasswordUseCaseTestSuite))
+}
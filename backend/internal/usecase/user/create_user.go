@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 
 	userdto "roadmap/internal/domain/dto/user"
 	userentity "roadmap/internal/domain/entities/user"
@@ -13,11 +12,21 @@ import (
 )
 
 type CreateUserUseCase struct {
-	userRepository userrepo.UserRepository
+	userRepository    userrepo.UserRepository
+	passwordHasher    PasswordHasher
+	passwordValidator *PasswordValidator
 }
 
-func NewCreateUserUseCase(userRepository userrepo.UserRepository) *CreateUserUseCase {
-	return &CreateUserUseCase{userRepository: userRepository}
+func NewCreateUserUseCase(
+	userRepository userrepo.UserRepository,
+	passwordHasher PasswordHasher,
+	passwordValidator *PasswordValidator,
+) *CreateUserUseCase {
+	return &CreateUserUseCase{
+		userRepository:    userRepository,
+		passwordHasher:    passwordHasher,
+		passwordValidator: passwordValidator,
+	}
 }
 
 func (u *CreateUserUseCase) Execute(
@@ -40,11 +49,11 @@ func (u *CreateUserUseCase) Execute(
 		return userdto.CreateUserResponse{}, ErrUsernameAlreadyExists
 	}
 
-	if err := validatePassword(req.Password); err != nil {
+	if err := u.passwordValidator.Validate(req.Password, req.Email, req.Username); err != nil {
 		return userdto.CreateUserResponse{}, err
 	}
 
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	passwordHash, err := u.passwordHasher.Hash(req.Password)
 	if err != nil {
 		return userdto.CreateUserResponse{}, err
 	}
@@ -54,7 +63,7 @@ func (u *CreateUserUseCase) Execute(
 		ID:           uuid.New(),
 		Username:     req.Username,
 		Email:        req.Email,
-		PasswordHash: string(passwordHash),
+		PasswordHash: passwordHash,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -14,6 +14,7 @@ import (
 
 	userdto "roadmap/internal/domain/dto/user"
 	userentity "roadmap/internal/domain/entities/user"
+	userrepo "roadmap/internal/repository/user"
 )
 
 type MockUserRepository struct {
@@ -54,6 +55,73 @@ func (m *MockUserRepository) UsernameExists(ctx context.Context, username string
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockUserRepository) UpdateProfile(ctx context.Context, user *userentity.User) (*userentity.User, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	args := m.Called(ctx, id, email)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	args := m.Called(ctx, id, verifiedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) List(
+	ctx context.Context,
+	filter userrepo.UserListFilter,
+) ([]*userentity.User, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*userentity.User), args.Int(1), args.Error(2)
+}
+
+func (m *MockUserRepository) IsSuspended(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Suspend(ctx context.Context, id uuid.UUID, reason string, suspendedAt time.Time) error {
+	args := m.Called(ctx, id, reason, suspendedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Unsuspend(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SoftDelete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
+	args := m.Called(ctx, id, deletedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
 type CreateUserUseCaseTestSuite struct {
 	suite.Suite
 	useCase      *CreateUserUseCase
@@ -65,7 +133,7 @@ type CreateUserUseCaseTestSuite struct {
 
 func (s *CreateUserUseCaseTestSuite) SetupTest() {
 	s.mockRepo = new(MockUserRepository)
-	s.useCase = NewCreateUserUseCase(s.mockRepo)
+	s.useCase = NewCreateUserUseCase(s.mockRepo, testPasswordHasher, testPasswordValidator)
 	s.ctx = context.Background()
 
 	s.validRequest = userdto.CreateUserRequest{
@@ -352,7 +420,7 @@ func (s *CreateUserUseCaseTestSuite) TestCreateUser_PasswordHashing() {
 	assert.NotNil(s.T(), capturedUser)
 	assert.NotEqual(s.T(), s.validRequest.Password, capturedUser.PasswordHash, "password should be hashed")
 	assert.NotEmpty(s.T(), capturedUser.PasswordHash, "password hash should not be empty")
-	assert.True(s.T(), len(capturedUser.PasswordHash) > 20, "bcrypt hash should be long enough")
+	assert.True(s.T(), len(capturedUser.PasswordHash) > 20, "password hash should be long enough")
 }
 
 func (s *CreateUserUseCaseTestSuite) TestCreateUser_ContextPropagation() {
@@ -872,14 +940,12 @@ func (s *CreateUserUseCaseTestSuite) TestCreateUser_PasswordHashing_Verification
 			assert.NotEqual(s.T(), tc.password, capturedUser.PasswordHash,
 				"password should be hashed, not stored as plain text")
 
-			assert.True(s.T(),
-				strings.HasPrefix(capturedUser.PasswordHash, "$2a$") ||
-					strings.HasPrefix(capturedUser.PasswordHash, "$2b$") ||
-					strings.HasPrefix(capturedUser.PasswordHash, "$2y$"),
-				"password hash should be bcrypt format")
+			assert.True(s.T(), strings.HasPrefix(capturedUser.PasswordHash, "$argon2id$v=19$"),
+				"password hash should be argon2id in PHC format")
 
-			assert.Equal(s.T(), 60, len(capturedUser.PasswordHash),
-				"bcrypt hash should be 60 characters long")
+			matches, err := testPasswordHasher.Verify(tc.password, capturedUser.PasswordHash)
+			assert.NoError(s.T(), err)
+			assert.True(s.T(), matches, "password hash should verify")
 
 			s.mockRepo.ExpectedCalls = nil
 			s.mockRepo.Calls = nil
@@ -0,0 +1,257 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/mailer"
+	userrepo "roadmap/internal/repository/user"
+)
+
+// EmailChangeLinks are the frontend pages that email change links point to.
+// The token is appended as the "token" query parameter.
+type EmailChangeLinks struct {
+	ConfirmURL string
+	CancelURL  string
+}
+
+type RequestEmailChangeUseCase struct {
+	userRepository        userrepo.UserRepository
+	passwordHasher        PasswordHasher
+	emailChangeRepository userrepo.EmailChangeRequestRepository
+	mailer                mailer.Mailer
+	links                 EmailChangeLinks
+	requestTTL            time.Duration
+}
+
+func NewRequestEmailChangeUseCase(
+	userRepository userrepo.UserRepository,
+	passwordHasher PasswordHasher,
+	emailChangeRepository userrepo.EmailChangeRequestRepository,
+	mailer mailer.Mailer,
+	links EmailChangeLinks,
+	requestTTL time.Duration,
+) *RequestEmailChangeUseCase {
+	return &RequestEmailChangeUseCase{
+		userRepository:        userRepository,
+		passwordHasher:        passwordHasher,
+		emailChangeRepository: emailChangeRepository,
+		mailer:                mailer,
+		links:                 links,
+		requestTTL:            requestTTL,
+	}
+}
+
+// Execute starts an email change. The address is not touched until the link
+// sent to the new address is opened; the old address gets a notice with a
+// link that cancels the request.
+func (u *RequestEmailChangeUseCase) Execute(
+	ctx context.Context,
+	userID string,
+	req userdto.ChangeEmailRequest,
+) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return ErrInvalidTokenClaims
+	}
+
+	user, err := u.userRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	matches, err := u.passwordHasher.Verify(req.Password, user.PasswordHash)
+	if err != nil {
+		return err
+	}
+	if !matches {
+		return ErrIncorrectPassword
+	}
+
+	newEmail := strings.TrimSpace(req.NewEmail)
+	if strings.EqualFold(newEmail, user.Email) {
+		return ErrEmailUnchanged
+	}
+
+	emailExists, err := u.userRepository.EmailExists(ctx, newEmail)
+	if err != nil {
+		return err
+	}
+	if emailExists {
+		return ErrEmailAlreadyExists
+	}
+
+	if err := u.emailChangeRepository.CancelPendingForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	confirmToken, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	cancelToken, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	request := &userentity.EmailChangeRequest{
+		ID:               uuid.New(),
+		UserID:           user.ID,
+		OldEmail:         user.Email,
+		NewEmail:         newEmail,
+		ConfirmTokenHash: hashOpaqueToken(confirmToken),
+		CancelTokenHash:  hashOpaqueToken(cancelToken),
+		ExpiresAt:        now.Add(u.requestTTL),
+		CreatedAt:        now,
+	}
+
+	if _, err := u.emailChangeRepository.Create(ctx, request); err != nil {
+		return err
+	}
+
+	confirmLink, err := tokenLink(u.links.ConfirmURL, confirmToken)
+	if err != nil {
+		return err
+	}
+	cancelLink, err := tokenLink(u.links.CancelURL, cancelToken)
+	if err != nil {
+		return err
+	}
+
+	err = u.mailer.Send(ctx, mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nOpen the link below to start using this address for your account. It expires in %s.\n\n%s\n",
+			user.Username, u.requestTTL, confirmLink,
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	// The notice is a safety net; the change can still be confirmed without it.
+	err = u.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your email address is being changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to change the email address of your account to %s.\n\n"+
+				"If this was not you, cancel the change and reset your password:\n\n%s\n",
+			user.Username, newEmail, cancelLink,
+		),
+	})
+	if err != nil {
+		log.Printf("Failed to send email change notice to user %s: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+type ConfirmEmailChangeUseCase struct {
+	userRepository          userrepo.UserRepository
+	emailChangeRepository   userrepo.EmailChangeRequestRepository
+	passwordResetRepository userrepo.PasswordResetTokenRepository
+	revocationService       *TokenRevocationService
+}
+
+func NewConfirmEmailChangeUseCase(
+	userRepository userrepo.UserRepository,
+	emailChangeRepository userrepo.EmailChangeRequestRepository,
+	passwordResetRepository userrepo.PasswordResetTokenRepository,
+	revocationService *TokenRevocationService,
+) *ConfirmEmailChangeUseCase {
+	return &ConfirmEmailChangeUseCase{
+		userRepository:          userRepository,
+		emailChangeRepository:   emailChangeRepository,
+		passwordResetRepository: passwordResetRepository,
+		revocationService:       revocationService,
+	}
+}
+
+// Execute swaps the user's email. Access tokens carry the email in their
+// claims, so every token issued before the swap is revoked; refresh tokens
+// stay valid because they reissue access tokens from the stored user.
+func (u *ConfirmEmailChangeUseCase) Execute(ctx context.Context, req userdto.EmailChangeTokenRequest) error {
+	request, err := u.emailChangeRepository.GetByConfirmHash(ctx, hashOpaqueToken(req.Token))
+	if err != nil {
+		return ErrInvalidEmailChangeToken
+	}
+	if !request.IsPending() || request.IsExpired(time.Now()) {
+		return ErrInvalidEmailChangeToken
+	}
+
+	user, err := u.userRepository.GetByID(ctx, request.UserID)
+	if err != nil {
+		return ErrInvalidEmailChangeToken
+	}
+	if user.Email != request.OldEmail {
+		return ErrInvalidEmailChangeToken
+	}
+
+	emailExists, err := u.userRepository.EmailExists(ctx, request.NewEmail)
+	if err != nil {
+		return err
+	}
+	if emailExists {
+		return ErrEmailAlreadyExists
+	}
+
+	if err := u.emailChangeRepository.MarkConfirmed(ctx, request.ID); err != nil {
+		if errors.Is(err, userrepo.ErrTokenAlreadyUsed) {
+			return ErrInvalidEmailChangeToken
+		}
+		return err
+	}
+
+	if err := u.userRepository.UpdateEmail(ctx, user.ID, request.NewEmail); err != nil {
+		if errors.Is(err, userrepo.ErrEmailTaken) {
+			return ErrEmailAlreadyExists
+		}
+		return err
+	}
+
+	// Reset links were mailed to the old address and must not outlive it.
+	if err := u.passwordResetRepository.InvalidateForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return u.revocationService.RevokeAllForUser(ctx, user.ID)
+}
+
+type CancelEmailChangeUseCase struct {
+	emailChangeRepository userrepo.EmailChangeRequestRepository
+}
+
+func NewCancelEmailChangeUseCase(emailChangeRepository userrepo.EmailChangeRequestRepository) *CancelEmailChangeUseCase {
+	return &CancelEmailChangeUseCase{
+		emailChangeRepository: emailChangeRepository,
+	}
+}
+
+func (u *CancelEmailChangeUseCase) Execute(ctx context.Context, req userdto.EmailChangeTokenRequest) error {
+	request, err := u.emailChangeRepository.GetByCancelHash(ctx, hashOpaqueToken(req.Token))
+	if err != nil {
+		return ErrInvalidEmailChangeToken
+	}
+	if !request.IsPending() || request.IsExpired(time.Now()) {
+		return ErrInvalidEmailChangeToken
+	}
+
+	if err := u.emailChangeRepository.MarkCancelled(ctx, request.ID); err != nil {
+		if errors.Is(err, userrepo.ErrTokenAlreadyUsed) {
+			return ErrInvalidEmailChangeToken
+		}
+		return err
+	}
+
+	return nil
+}
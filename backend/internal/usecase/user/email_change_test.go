@@ -0,0 +1,267 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/mailer"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type MockEmailChangeRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailChangeRequestRepository) Create(
+	ctx context.Context,
+	request *userentity.EmailChangeRequest,
+) (*userentity.EmailChangeRequest, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.EmailChangeRequest), args.Error(1)
+}
+
+func (m *MockEmailChangeRequestRepository) GetByConfirmHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.EmailChangeRequest, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.EmailChangeRequest), args.Error(1)
+}
+
+func (m *MockEmailChangeRequestRepository) GetByCancelHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.EmailChangeRequest, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.EmailChangeRequest), args.Error(1)
+}
+
+func (m *MockEmailChangeRequestRepository) MarkConfirmed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEmailChangeRequestRepository) MarkCancelled(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEmailChangeRequestRepository) CancelPendingForUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type EmailChangeUseCaseTestSuite struct {
+	suite.Suite
+	requestUseCase *RequestEmailChangeUseCase
+	confirmUseCase *ConfirmEmailChangeUseCase
+	cancelUseCase  *CancelEmailChangeUseCase
+	userRepo       *MockUserRepository
+	changeRepo     *MockEmailChangeRequestRepository
+	resetRepo      *MockPasswordResetTokenRepository
+	revocationRepo *MockTokenRevocationRepository
+	mailer         *MockMailer
+	user           *userentity.User
+	ctx            context.Context
+}
+
+func (s *EmailChangeUseCaseTestSuite) SetupTest() {
+	s.userRepo = new(MockUserRepository)
+	s.changeRepo = new(MockEmailChangeRequestRepository)
+	s.resetRepo = new(MockPasswordResetTokenRepository)
+	s.revocationRepo = new(MockTokenRevocationRepository)
+	s.mailer = new(MockMailer)
+	s.ctx = context.Background()
+
+	s.requestUseCase = NewRequestEmailChangeUseCase(s.userRepo, testPasswordHasher, s.changeRepo, s.mailer, EmailChangeLinks{
+		ConfirmURL: "https://app.example.com/confirm-email-change",
+		CancelURL:  "https://app.example.com/cancel-email-change",
+	}, time.Hour)
+	s.confirmUseCase = NewConfirmEmailChangeUseCase(
+		s.userRepo, s.changeRepo, s.resetRepo, NewTokenRevocationService(s.revocationRepo, time.Minute),
+	)
+	s.cancelUseCase = NewCancelEmailChangeUseCase(s.changeRepo)
+
+	passwordHash, _ := testPasswordHasher.Hash("Password123!")
+	s.user = &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser",
+		Email:        "old@example.com",
+		PasswordHash: passwordHash,
+	}
+}
+
+func (s *EmailChangeUseCaseTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.changeRepo.AssertExpectations(s.T())
+	s.resetRepo.AssertExpectations(s.T())
+	s.revocationRepo.AssertExpectations(s.T())
+	s.mailer.AssertExpectations(s.T())
+}
+
+func (s *EmailChangeUseCaseTestSuite) pendingRequest() *userentity.EmailChangeRequest {
+	return &userentity.EmailChangeRequest{
+		ID:               uuid.New(),
+		UserID:           s.user.ID,
+		OldEmail:         "old@example.com",
+		NewEmail:         "new@example.com",
+		ConfirmTokenHash: hashOpaqueToken("confirm-token"),
+		CancelTokenHash:  hashOpaqueToken("cancel-token"),
+		ExpiresAt:        time.Now().Add(time.Hour),
+	}
+}
+
+func (s *EmailChangeUseCaseTestSuite) TestRequest_SendsConfirmationAndNotice() {
+	var stored *userentity.EmailChangeRequest
+	var sent []mailer.Message
+
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("EmailExists", s.ctx, "new@example.com").Return(false, nil)
+	s.changeRepo.On("CancelPendingForUser", s.ctx, s.user.ID).Return(nil)
+	s.changeRepo.On("Create", s.ctx, mock.AnythingOfType("*user.EmailChangeRequest")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*userentity.EmailChangeRequest) }).
+		Return(&userentity.EmailChangeRequest{}, nil)
+	s.mailer.On("Send", s.ctx, mock.AnythingOfType("mailer.Message")).
+		Run(func(args mock.Arguments) { sent = append(sent, args.Get(1).(mailer.Message)) }).
+		Return(nil)
+
+	err := s.requestUseCase.Execute(s.ctx, s.user.ID.String(), userdto.ChangeEmailRequest{
+		NewEmail: "new@example.com",
+		Password: "Password123!",
+	})
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "old@example.com", stored.OldEmail)
+	assert.Equal(s.T(), "new@example.com", stored.NewEmail)
+	assert.Len(s.T(), sent, 2)
+	assert.Equal(s.T(), "new@example.com", sent[0].To)
+	assert.Contains(s.T(), sent[0].Body, "https://app.example.com/confirm-email-change?token=")
+	assert.Equal(s.T(), "old@example.com", sent[1].To)
+	assert.Contains(s.T(), sent[1].Body, "https://app.example.com/cancel-email-change?token=")
+	assert.False(s.T(), strings.Contains(sent[1].Body, "confirm-email-change"))
+}
+
+func (s *EmailChangeUseCaseTestSuite) TestRequest_IncorrectPassword() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+
+	err := s.requestUseCase.Execute(s.ctx, s.user.ID.String(), userdto.ChangeEmailRequest{
+		NewEmail: "new@example.com",
+		Password: "WrongPassword123!",
+	})
+
+	assert.ErrorIs(s.T(), err, ErrIncorrectPassword)
+}
+
+func (s *EmailChangeUseCaseTestSuite) TestRequest_EmailTaken() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("EmailExists", s.ctx, "new@example.com").Return(true, nil)
+
+	err := s.requestUseCase.Execute(s.ctx, s.user.ID.String(), userdto.ChangeEmailRequest{
+		NewEmail: "new@example.com",
+		Password: "Password123!",
+	})
+
+	assert.ErrorIs(s.T(), err, ErrEmailAlreadyExists)
+	s.changeRepo.AssertNotCalled(s.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (s *EmailChangeUseCaseTestSuite) TestRequest_SameEmail() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+
+	err := s.requestUseCase.Execute(s.ctx, s.user.ID.String(), userdto.ChangeEmailRequest{
+		NewEmail: "OLD@example.com",
+		Password: "Password123!",
+	})
+
+	assert.ErrorIs(s.T(), err, ErrEmailUnchanged)
+}
+
+func (s *EmailChangeUseCaseTestSuite) TestConfirm_SwapsEmailAndRevokesTokens() {
+	request := s.pendingRequest()
+	s.changeRepo.On("GetByConfirmHash", s.ctx, request.ConfirmTokenHash).Return(request, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("EmailExists", s.ctx, "new@example.com").Return(false, nil)
+	s.changeRepo.On("MarkConfirmed", s.ctx, request.ID).Return(nil)
+	s.userRepo.On("UpdateEmail", s.ctx, s.user.ID, "new@example.com").Return(nil)
+	s.resetRepo.On("InvalidateForUser", s.ctx, s.user.ID).Return(nil)
+	s.revocationRepo.On("SetTokensValidAfter", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := s.confirmUseCase.Execute(s.ctx, userdto.EmailChangeTokenRequest{Token: "confirm-token"})
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *EmailChangeUseCaseTestSuite) TestConfirm_CancelledRequest() {
+	request := s.pendingRequest()
+	cancelledAt := time.Now()
+	request.CancelledAt = &cancelledAt
+	s.changeRepo.On("GetByConfirmHash", s.ctx, request.ConfirmTokenHash).Return(request, nil)
+
+	err := s.confirmUseCase.Execute(s.ctx, userdto.EmailChangeTokenRequest{Token: "confirm-token"})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidEmailChangeToken)
+}
+
+func (s *EmailChangeUseCaseTestSuite) TestConfirm_EmailTakenInTheMeantime() {
+	request := s.pendingRequest()
+	s.changeRepo.On("GetByConfirmHash", s.ctx, request.ConfirmTokenHash).Return(request, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.userRepo.On("EmailExists", s.ctx, "new@example.com").Return(false, nil)
+	s.changeRepo.On("MarkConfirmed", s.ctx, request.ID).Return(nil)
+	s.userRepo.On("UpdateEmail", s.ctx, s.user.ID, "new@example.com").Return(userrepo.ErrEmailTaken)
+
+	err := s.confirmUseCase.Execute(s.ctx, userdto.EmailChangeTokenRequest{Token: "confirm-token"})
+
+	assert.ErrorIs(s.T(), err, ErrEmailAlreadyExists)
+}
+
+func (s *EmailChangeUseCaseTestSuite) TestConfirm_UnknownToken() {
+	s.changeRepo.On("GetByConfirmHash", s.ctx, hashOpaqueToken("bogus")).Return(nil, errors.New("not found"))
+
+	err := s.confirmUseCase.Execute(s.ctx, userdto.EmailChangeTokenRequest{Token: "bogus"})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidEmailChangeToken)
+}
+
+func (s *EmailChangeUseCaseTestSuite) TestCancel_Success() {
+	request := s.pendingRequest()
+	s.changeRepo.On("GetByCancelHash", s.ctx, request.CancelTokenHash).Return(request, nil)
+	s.changeRepo.On("MarkCancelled", s.ctx, request.ID).Return(nil)
+
+	err := s.cancelUseCase.Execute(s.ctx, userdto.EmailChangeTokenRequest{Token: "cancel-token"})
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *EmailChangeUseCaseTestSuite) TestCancel_AlreadyConfirmed() {
+	request := s.pendingRequest()
+	s.changeRepo.On("GetByCancelHash", s.ctx, request.CancelTokenHash).Return(request, nil)
+	s.changeRepo.On("MarkCancelled", s.ctx, request.ID).Return(userrepo.ErrTokenAlreadyUsed)
+
+	err := s.cancelUseCase.Execute(s.ctx, userdto.EmailChangeTokenRequest{Token: "cancel-token"})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidEmailChangeToken)
+}
+
+func TestEmailChangeUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailChangeUseCaseTestSuite))
+}
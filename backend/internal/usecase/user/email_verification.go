@@ -0,0 +1,201 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/mailer"
+	userrepo "roadmap/internal/repository/user"
+)
+
+// EmailVerificationSender issues verification tokens and mails the links that
+// redeem them.
+type EmailVerificationSender struct {
+	tokenRepository userrepo.EmailVerificationTokenRepository
+	mailer          mailer.Mailer
+	verifyURL       string
+	tokenTTL        time.Duration
+}
+
+// NewEmailVerificationSender creates a sender whose links point to verifyURL
+// with the token appended as the "token" query parameter.
+func NewEmailVerificationSender(
+	tokenRepository userrepo.EmailVerificationTokenRepository,
+	mailer mailer.Mailer,
+	verifyURL string,
+	tokenTTL time.Duration,
+) *EmailVerificationSender {
+	return &EmailVerificationSender{
+		tokenRepository: tokenRepository,
+		mailer:          mailer,
+		verifyURL:       verifyURL,
+		tokenTTL:        tokenTTL,
+	}
+}
+
+// Send mails a verification link for the user's current email address.
+func (s *EmailVerificationSender) Send(ctx context.Context, user *userentity.User) error {
+	rawToken, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	token := &userentity.EmailVerificationToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: hashOpaqueToken(rawToken),
+		ExpiresAt: now.Add(s.tokenTTL),
+		CreatedAt: now,
+	}
+
+	if _, err := s.tokenRepository.Create(ctx, token); err != nil {
+		return err
+	}
+
+	link, err := tokenLink(s.verifyURL, rawToken)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below. It expires in %s.\n\n%s\n",
+			user.Username, s.tokenTTL, link,
+		),
+	})
+}
+
+type VerifyEmailUseCase struct {
+	userRepository  userrepo.UserRepository
+	tokenRepository userrepo.EmailVerificationTokenRepository
+}
+
+func NewVerifyEmailUseCase(
+	userRepository userrepo.UserRepository,
+	tokenRepository userrepo.EmailVerificationTokenRepository,
+) *VerifyEmailUseCase {
+	return &VerifyEmailUseCase{
+		userRepository:  userRepository,
+		tokenRepository: tokenRepository,
+	}
+}
+
+// Execute redeems a verification token. A token only verifies the address it
+// was sent to, so it is rejected once the user's email has changed.
+func (u *VerifyEmailUseCase) Execute(ctx context.Context, req userdto.VerifyEmailRequest) error {
+	token, err := u.tokenRepository.GetByHash(ctx, hashOpaqueToken(req.Token))
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	if token.IsUsed() || token.IsExpired(time.Now()) {
+		return ErrInvalidVerificationToken
+	}
+
+	user, err := u.userRepository.GetByID(ctx, token.UserID)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	if user.Email != token.Email {
+		return ErrInvalidVerificationToken
+	}
+
+	if err := u.tokenRepository.MarkUsed(ctx, token.ID); err != nil {
+		if errors.Is(err, userrepo.ErrTokenAlreadyUsed) {
+			return ErrInvalidVerificationToken
+		}
+		return err
+	}
+
+	return u.userRepository.MarkEmailVerified(ctx, user.ID, time.Now().UTC())
+}
+
+// ResendPolicy limits how often a user can ask for another verification
+// email: at most MaxPerWindow emails per Window and none within Cooldown of
+// the previous one.
+type ResendPolicy struct {
+	Cooldown     time.Duration
+	MaxPerWindow int
+	Window       time.Duration
+}
+
+type ResendVerificationUseCase struct {
+	userRepository  userrepo.UserRepository
+	tokenRepository userrepo.EmailVerificationTokenRepository
+	sender          *EmailVerificationSender
+	policy          ResendPolicy
+}
+
+func NewResendVerificationUseCase(
+	userRepository userrepo.UserRepository,
+	tokenRepository userrepo.EmailVerificationTokenRepository,
+	sender *EmailVerificationSender,
+	policy ResendPolicy,
+) *ResendVerificationUseCase {
+	return &ResendVerificationUseCase{
+		userRepository:  userRepository,
+		tokenRepository: tokenRepository,
+		sender:          sender,
+		policy:          policy,
+	}
+}
+
+func (u *ResendVerificationUseCase) Execute(ctx context.Context, userID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return ErrInvalidTokenClaims
+	}
+
+	user, err := u.userRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	now := time.Now().UTC()
+	if err := u.checkThrottle(ctx, user.ID, now); err != nil {
+		return err
+	}
+
+	if err := u.tokenRepository.InvalidateForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return u.sender.Send(ctx, user)
+}
+
+func (u *ResendVerificationUseCase) checkThrottle(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	window := max(u.policy.Window, u.policy.Cooldown)
+	sent, err := u.tokenRepository.CreatedSince(ctx, userID, now.Add(-window))
+	if err != nil {
+		return err
+	}
+	if len(sent) == 0 {
+		return nil
+	}
+
+	var retryAfter time.Duration
+	if last := sent[len(sent)-1]; now.Sub(last) < u.policy.Cooldown {
+		retryAfter = u.policy.Cooldown - now.Sub(last)
+	}
+	if u.policy.MaxPerWindow > 0 && len(sent) >= u.policy.MaxPerWindow {
+		oldest := sent[len(sent)-u.policy.MaxPerWindow]
+		retryAfter = max(retryAfter, oldest.Add(u.policy.Window).Sub(now))
+	}
+
+	if retryAfter > 0 {
+		return &ThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
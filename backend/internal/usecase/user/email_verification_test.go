@@ -0,0 +1,244 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/mailer"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type MockEmailVerificationTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationTokenRepository) Create(
+	ctx context.Context,
+	token *userentity.EmailVerificationToken,
+) (*userentity.EmailVerificationToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.EmailVerificationToken), args.Error(1)
+}
+
+func (m *MockEmailVerificationTokenRepository) GetByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.EmailVerificationToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.EmailVerificationToken), args.Error(1)
+}
+
+func (m *MockEmailVerificationTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationTokenRepository) CreatedSince(
+	ctx context.Context,
+	userID uuid.UUID,
+	since time.Time,
+) ([]time.Time, error) {
+	args := m.Called(ctx, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+type EmailVerificationUseCaseTestSuite struct {
+	suite.Suite
+	verifyUseCase *VerifyEmailUseCase
+	resendUseCase *ResendVerificationUseCase
+	userRepo      *MockUserRepository
+	tokenRepo     *MockEmailVerificationTokenRepository
+	mailer        *MockMailer
+	user          *userentity.User
+	ctx           context.Context
+}
+
+func (s *EmailVerificationUseCaseTestSuite) SetupTest() {
+	s.userRepo = new(MockUserRepository)
+	s.tokenRepo = new(MockEmailVerificationTokenRepository)
+	s.mailer = new(MockMailer)
+	s.ctx = context.Background()
+
+	sender := NewEmailVerificationSender(s.tokenRepo, s.mailer, "https://app.example.com/verify-email", 24*time.Hour)
+	s.verifyUseCase = NewVerifyEmailUseCase(s.userRepo, s.tokenRepo)
+	s.resendUseCase = NewResendVerificationUseCase(s.userRepo, s.tokenRepo, sender, ResendPolicy{
+		Cooldown:     time.Minute,
+		MaxPerWindow: 3,
+		Window:       time.Hour,
+	})
+
+	s.user = &userentity.User{
+		ID:       uuid.New(),
+		Username: "testuser",
+		Email:    "test@example.com",
+	}
+}
+
+func (s *EmailVerificationUseCaseTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.tokenRepo.AssertExpectations(s.T())
+	s.mailer.AssertExpectations(s.T())
+}
+
+func (s *EmailVerificationUseCaseTestSuite) validToken() *userentity.EmailVerificationToken {
+	return &userentity.EmailVerificationToken{
+		ID:        uuid.New(),
+		UserID:    s.user.ID,
+		Email:     s.user.Email,
+		TokenHash: hashOpaqueToken("verify-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func (s *EmailVerificationUseCaseTestSuite) TestSend_MailsLinkForStoredToken() {
+	var stored *userentity.EmailVerificationToken
+	var sent mailer.Message
+
+	s.tokenRepo.On("Create", s.ctx, mock.AnythingOfType("*user.EmailVerificationToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*userentity.EmailVerificationToken) }).
+		Return(&userentity.EmailVerificationToken{}, nil)
+	s.mailer.On("Send", s.ctx, mock.AnythingOfType("mailer.Message")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(mailer.Message) }).
+		Return(nil)
+
+	err := s.resendUseCase.sender.Send(s.ctx, s.user)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), s.user.Email, stored.Email)
+	assert.Equal(s.T(), s.user.Email, sent.To)
+
+	const prefix = "https://app.example.com/verify-email?token="
+	start := strings.Index(sent.Body, prefix)
+	assert.GreaterOrEqual(s.T(), start, 0)
+	rawToken := strings.Fields(sent.Body[start+len(prefix):])[0]
+	assert.Equal(s.T(), stored.TokenHash, hashOpaqueToken(rawToken))
+}
+
+func (s *EmailVerificationUseCaseTestSuite) TestVerify_Success() {
+	token := s.validToken()
+	s.tokenRepo.On("GetByHash", s.ctx, token.TokenHash).Return(token, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.tokenRepo.On("MarkUsed", s.ctx, token.ID).Return(nil)
+	s.userRepo.On("MarkEmailVerified", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := s.verifyUseCase.Execute(s.ctx, userdto.VerifyEmailRequest{Token: "verify-token"})
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *EmailVerificationUseCaseTestSuite) TestVerify_ExpiredToken() {
+	token := s.validToken()
+	token.ExpiresAt = time.Now().Add(-time.Minute)
+	s.tokenRepo.On("GetByHash", s.ctx, token.TokenHash).Return(token, nil)
+
+	err := s.verifyUseCase.Execute(s.ctx, userdto.VerifyEmailRequest{Token: "verify-token"})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidVerificationToken)
+}
+
+func (s *EmailVerificationUseCaseTestSuite) TestVerify_TokenForPreviousEmail() {
+	token := s.validToken()
+	token.Email = "old@example.com"
+	s.tokenRepo.On("GetByHash", s.ctx, token.TokenHash).Return(token, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+
+	err := s.verifyUseCase.Execute(s.ctx, userdto.VerifyEmailRequest{Token: "verify-token"})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidVerificationToken)
+	s.tokenRepo.AssertNotCalled(s.T(), "MarkUsed", mock.Anything, mock.Anything)
+}
+
+func (s *EmailVerificationUseCaseTestSuite) TestVerify_AlreadyUsed() {
+	token := s.validToken()
+	s.tokenRepo.On("GetByHash", s.ctx, token.TokenHash).Return(token, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.tokenRepo.On("MarkUsed", s.ctx, token.ID).Return(userrepo.ErrTokenAlreadyUsed)
+
+	err := s.verifyUseCase.Execute(s.ctx, userdto.VerifyEmailRequest{Token: "verify-token"})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidVerificationToken)
+}
+
+func (s *EmailVerificationUseCaseTestSuite) TestResend_Success() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.tokenRepo.On("CreatedSince", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).
+		Return([]time.Time{time.Now().UTC().Add(-10 * time.Minute)}, nil)
+	s.tokenRepo.On("InvalidateForUser", s.ctx, s.user.ID).Return(nil)
+	s.tokenRepo.On("Create", s.ctx, mock.Anything).Return(&userentity.EmailVerificationToken{}, nil)
+	s.mailer.On("Send", s.ctx, mock.Anything).Return(nil)
+
+	err := s.resendUseCase.Execute(s.ctx, s.user.ID.String())
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *EmailVerificationUseCaseTestSuite) TestResend_Cooldown() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.tokenRepo.On("CreatedSince", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).
+		Return([]time.Time{time.Now().UTC().Add(-20 * time.Second)}, nil)
+
+	err := s.resendUseCase.Execute(s.ctx, s.user.ID.String())
+
+	var throttledErr *ThrottledError
+	assert.ErrorAs(s.T(), err, &throttledErr)
+	assert.InDelta(s.T(), 40*time.Second, throttledErr.RetryAfter, float64(2*time.Second))
+}
+
+func (s *EmailVerificationUseCaseTestSuite) TestResend_WindowLimit() {
+	now := time.Now().UTC()
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.tokenRepo.On("CreatedSince", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).
+		Return([]time.Time{now.Add(-50 * time.Minute), now.Add(-30 * time.Minute), now.Add(-10 * time.Minute)}, nil)
+
+	err := s.resendUseCase.Execute(s.ctx, s.user.ID.String())
+
+	var throttledErr *ThrottledError
+	assert.ErrorAs(s.T(), err, &throttledErr)
+	assert.InDelta(s.T(), 10*time.Minute, throttledErr.RetryAfter, float64(2*time.Second))
+}
+
+func (s *EmailVerificationUseCaseTestSuite) TestResend_AlreadyVerified() {
+	verifiedAt := time.Now()
+	s.user.EmailVerifiedAt = &verifiedAt
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+
+	err := s.resendUseCase.Execute(s.ctx, s.user.ID.String())
+
+	assert.ErrorIs(s.T(), err, ErrEmailAlreadyVerified)
+}
+
+func (s *EmailVerificationUseCaseTestSuite) TestResend_UserLookupError() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(nil, errors.New("database error"))
+
+	err := s.resendUseCase.Execute(s.ctx, s.user.ID.String())
+
+	assert.Error(s.T(), err)
+}
+
+func TestEmailVerificationUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailVerificationUseCaseTestSuite))
+}
@@ -1,17 +1,72 @@
 package user
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
-	ErrEmailAlreadyExists    = errors.New("email already exists")
-	ErrUsernameAlreadyExists = errors.New("username already exists")
-	ErrInvalidCredentials    = errors.New("invalid email or password")
+	ErrEmailAlreadyExists       = errors.New("email already exists")
+	ErrUsernameAlreadyExists    = errors.New("username already exists")
+	ErrInvalidCredentials       = errors.New("invalid email or password")
+	ErrInvalidRefreshToken      = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused       = errors.New("refresh token reuse detected")
+	ErrInvalidTokenClaims       = errors.New("invalid token claims")
+	ErrInvalidResetToken        = errors.New("invalid or expired password reset token")
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	ErrEmailAlreadyVerified     = errors.New("email already verified")
+	ErrIncorrectPassword        = errors.New("incorrect password")
+	ErrReauthenticationRequired = errors.New("sign in again to confirm this action")
+	ErrEmailUnchanged           = errors.New("new email matches the current email")
+	ErrInvalidEmailChangeToken  = errors.New("invalid or expired email change token")
+	ErrTwoFactorAlreadyEnabled  = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotPending      = errors.New("two-factor setup has not been started")
+	ErrInvalidTwoFactorCode     = errors.New("invalid two-factor code")
+	ErrInvalidMFAChallenge      = errors.New("invalid or expired mfa challenge")
+	ErrInvalidPasskeyChallenge  = errors.New("invalid or expired passkey challenge")
+	ErrPasskeyVerification      = errors.New("passkey verification failed")
+	ErrPasskeyCloned            = errors.New("passkey sign count regressed, authenticator may be cloned")
+	ErrPasskeyAlreadyRegistered = errors.New("passkey already registered")
+	ErrPasskeyNotFound          = errors.New("passkey not found")
+	ErrUnknownOIDCProvider      = errors.New("unknown identity provider")
+	ErrInvalidOIDCState         = errors.New("invalid or expired oidc state")
+	ErrOIDCVerification         = errors.New("identity provider sign-in failed")
+	ErrOIDCEmailMissing         = errors.New("identity provider did not return an email address")
+	ErrIdentityAlreadyLinked    = errors.New("identity already linked")
+	ErrAccessTokenNotFound      = errors.New("access token not found")
+	ErrAccessTokenNameTaken     = errors.New("access token name already in use")
+	ErrInvalidAccessTokenExpiry = errors.New("access token expiry must be in the future")
+	ErrUserNotFound             = errors.New("user not found")
+	ErrRoleNotFound             = errors.New("role not found")
+	ErrRoleNotAssignable        = errors.New("role cannot be granted or revoked")
+	ErrRoleAlreadyGranted       = errors.New("role already granted")
+	ErrRoleNotGranted           = errors.New("role not granted")
+	ErrCannotRevokeOwnAdmin     = errors.New("cannot revoke your own admin role")
+	ErrAccountSuspended         = errors.New("account suspended")
+	ErrCannotModifySelf         = errors.New("administrators cannot perform this action on their own account")
+	ErrInvalidCreatedRange      = errors.New("created_before must be later than created_after")
+	ErrSessionNotFound          = errors.New("session not found")
 )
 
+// PasswordValidationError is returned for passwords that do not meet the
+// password policy. Reasons holds one of the PasswordReason codes per failed
+// check, for clients to react to.
 type PasswordValidationError struct {
 	Message string
+	Reasons []string
 }
 
 func (e *PasswordValidationError) Error() string {
 	return e.Message
 }
+
+// ThrottledError is returned when an action was attempted too often.
+// RetryAfter tells the caller when the next attempt is allowed.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("too many attempts, retry after %s", e.RetryAfter.Round(time.Second))
+}
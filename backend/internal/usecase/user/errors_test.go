@@ -3,6 +3,7 @@ package user
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -45,3 +46,9 @@ func TestErrors_Is(t *testing.T) {
 	assert.False(t, errors.Is(ErrEmailAlreadyExists, ErrUsernameAlreadyExists))
 	assert.False(t, errors.Is(ErrEmailAlreadyExists, ErrInvalidCredentials))
 }
+
+func TestThrottledError_Error(t *testing.T) {
+	err := &ThrottledError{RetryAfter: 1500 * time.Millisecond}
+
+	assert.Equal(t, "too many attempts, retry after 2s", err.Error())
+}
@@ -2,48 +2,123 @@ package user
 
 import (
 	"context"
-	"fmt"
-
-	"golang.org/x/crypto/bcrypt"
 
 	userdto "roadmap/internal/domain/dto/user"
-	jwtservice "roadmap/internal/pkg/jwt"
+	userentity "roadmap/internal/domain/entities/user"
 	userrepo "roadmap/internal/repository/user"
 )
 
 type LoginUseCase struct {
 	userRepository userrepo.UserRepository
-	jwtService     *jwtservice.JWTService
+	passwordHasher PasswordHasher
+	tokenIssuer    *TokenIssuer
+	twoFactor      *TwoFactorChallenger
+	throttle       *LoginThrottle
 }
 
-func NewLoginUseCase(userRepository userrepo.UserRepository, jwtService *jwtservice.JWTService) *LoginUseCase {
+// NewLoginUseCase creates the password login. throttle may be nil to allow
+// unlimited attempts.
+func NewLoginUseCase(
+	userRepository userrepo.UserRepository,
+	passwordHasher PasswordHasher,
+	tokenIssuer *TokenIssuer,
+	twoFactor *TwoFactorChallenger,
+	throttle *LoginThrottle,
+) *LoginUseCase {
 	return &LoginUseCase{
 		userRepository: userRepository,
-		jwtService:     jwtService,
+		passwordHasher: passwordHasher,
+		tokenIssuer:    tokenIssuer,
+		twoFactor:      twoFactor,
+		throttle:       throttle,
 	}
 }
 
-func (u *LoginUseCase) Execute(ctx context.Context, req userdto.LoginRequest) (userdto.LoginResponse, error) {
+// Execute signs in with email and password. client describes the device the
+// attempt came from; its address is used for throttling and recorded with
+// the session. While the account or the address is throttled, attempts fail
+// with a *ThrottledError without the password being checked.
+func (u *LoginUseCase) Execute(
+	ctx context.Context,
+	req userdto.LoginRequest,
+	client userdto.ClientInfo,
+) (userdto.LoginResponse, error) {
+	if u.throttle != nil {
+		if err := u.throttle.Check(ctx, req.Email, client.IPAddress); err != nil {
+			return userdto.LoginResponse{}, err
+		}
+	}
+
 	user, err := u.userRepository.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return userdto.LoginResponse{}, ErrInvalidCredentials
+		return userdto.LoginResponse{}, u.fail(ctx, req.Email, client.IPAddress, nil)
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
+	matches, err := u.passwordHasher.Verify(req.Password, user.PasswordHash)
 	if err != nil {
-		return userdto.LoginResponse{}, ErrInvalidCredentials
+		return userdto.LoginResponse{}, err
+	}
+	if !matches {
+		return userdto.LoginResponse{}, u.fail(ctx, req.Email, client.IPAddress, user)
+	}
+
+	if u.throttle != nil {
+		if err := u.throttle.RecordSuccess(ctx, req.Email); err != nil {
+			return userdto.LoginResponse{}, err
+		}
+	}
+
+	upgradePasswordHash(ctx, u.userRepository, u.passwordHasher, user, req.Password)
+
+	return completeLogin(ctx, u.twoFactor, u.tokenIssuer, user, client)
+}
+
+// fail records a failed attempt and returns the error to report for it.
+func (u *LoginUseCase) fail(ctx context.Context, email, clientIP string, user *userentity.User) error {
+	if u.throttle != nil {
+		if err := u.throttle.RecordFailure(ctx, email, clientIP, user); err != nil {
+			return err
+		}
+	}
+	return ErrInvalidCredentials
+}
+
+// completeLogin finishes a first-factor sign-in: it hands out an MFA
+// challenge when the user has two-factor authentication enabled and tokens
+// for a new session on client otherwise.
+func completeLogin(
+	ctx context.Context,
+	twoFactor *TwoFactorChallenger,
+	tokenIssuer *TokenIssuer,
+	user *userentity.User,
+	client userdto.ClientInfo,
+) (userdto.LoginResponse, error) {
+	// Checked before the second factor so a suspended user is not asked for it.
+	if user.IsSuspended() {
+		return userdto.LoginResponse{}, ErrAccountSuspended
+	}
+
+	mfaRequired, err := twoFactor.Required(ctx, user.ID)
+	if err != nil {
+		return userdto.LoginResponse{}, err
+	}
+
+	if mfaRequired {
+		challenge, err := twoFactor.Issue(ctx, user.ID)
+		if err != nil {
+			return userdto.LoginResponse{}, err
+		}
+		return userdto.LoginResponse{
+			MFAChallenge: challenge,
+		}, nil
 	}
 
-	token, err := u.jwtService.GenerateToken(
-		user.ID.String(),
-		user.Username,
-		user.Email,
-	)
+	tokens, err := tokenIssuer.Issue(ctx, user, client)
 	if err != nil {
-		return userdto.LoginResponse{}, fmt.Errorf("failed to generate token: %w", err)
+		return userdto.LoginResponse{}, err
 	}
 
 	return userdto.LoginResponse{
-		Token: token,
+		TokenPair: tokens,
 	}, nil
 }
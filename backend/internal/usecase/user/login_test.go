@@ -9,19 +9,36 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/crypto/bcrypt"
 
 	userdto "roadmap/internal/domain/dto/user"
 	userentity "roadmap/internal/domain/entities/user"
 	jwtservice "roadmap/internal/pkg/jwt"
+	"roadmap/internal/pkg/password"
 )
 
+// testPasswordHasher keeps hashing in tests cheap.
+var testPasswordHasher = password.NewHasher(password.Argon2idParams{
+	Memory:      64,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+})
+
+// testPasswordValidator only applies the length and character class rules,
+// which the password fixtures are written against.
+var testPasswordValidator = NewPasswordValidator(PasswordPolicy{})
+
 type LoginUseCaseTestSuite struct {
 	suite.Suite
 	useCase      *LoginUseCase
 	mockRepo     *MockUserRepository
-	jwtService   *jwtservice.JWTService
+	refreshRepo  *MockRefreshTokenRepository
+	totpRepo     *MockTOTPRepository
+	mfaRepo      *MockMFAChallengeRepository
 	validRequest userdto.LoginRequest
 	validUser    *userentity.User
 	ctx          context.Context
@@ -29,8 +46,16 @@ type LoginUseCaseTestSuite struct {
 
 func (s *LoginUseCaseTestSuite) SetupTest() {
 	s.mockRepo = new(MockUserRepository)
-	s.jwtService = jwtservice.NewJWTService("test-secret-key", 24*3600*1000000000)
-	s.useCase = NewLoginUseCase(s.mockRepo, s.jwtService)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.totpRepo = new(MockTOTPRepository)
+	s.mfaRepo = new(MockMFAChallengeRepository)
+	s.useCase = NewLoginUseCase(
+		s.mockRepo,
+		testPasswordHasher,
+		newTestTokenIssuer(s.refreshRepo),
+		NewTwoFactorChallenger(s.totpRepo, s.mfaRepo, 5*time.Minute),
+		nil,
+	)
 	s.ctx = context.Background()
 
 	s.validRequest = userdto.LoginRequest{
@@ -38,13 +63,13 @@ func (s *LoginUseCaseTestSuite) SetupTest() {
 		Password: "SecurePass123!",
 	}
 
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(s.validRequest.Password), bcrypt.DefaultCost)
+	hashedPassword, _ := testPasswordHasher.Hash(s.validRequest.Password)
 	now := time.Now()
 	s.validUser = &userentity.User{
 		ID:           uuid.New(),
 		Username:     "testuser",
 		Email:        s.validRequest.Email,
-		PasswordHash: string(hashedPassword),
+		PasswordHash: hashedPassword,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -52,21 +77,137 @@ func (s *LoginUseCaseTestSuite) SetupTest() {
 
 func (s *LoginUseCaseTestSuite) TearDownTest() {
 	s.mockRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.totpRepo.AssertExpectations(s.T())
+	s.mfaRepo.AssertExpectations(s.T())
 }
 
 func (s *LoginUseCaseTestSuite) TestLogin_Success() {
 	s.mockRepo.On("GetByEmail", s.ctx, s.validRequest.Email).Return(s.validUser, nil)
+	s.totpRepo.On("GetByUserID", s.ctx, s.validUser.ID).Return(nil, pgx.ErrNoRows)
+	s.refreshRepo.On("Create", s.ctx, mock.MatchedBy(func(t *userentity.RefreshToken) bool {
+		return t.UserID == s.validUser.ID && t.TokenHash != ""
+	})).Return(&userentity.RefreshToken{}, nil)
 
-	response, err := s.useCase.Execute(s.ctx, s.validRequest)
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
 
 	assert.NoError(s.T(), err)
 	assert.NotEmpty(s.T(), response.Token)
+	assert.NotEmpty(s.T(), response.RefreshToken)
+	assert.True(s.T(), response.TokenExpiresAt.After(time.Now()))
+	assert.True(s.T(), response.RefreshTokenExpiresAt.After(response.TokenExpiresAt))
+}
+
+func (s *LoginUseCaseTestSuite) TestLogin_TokenCarriesPermissions() {
+	s.validUser.Roles = []string{userentity.RoleAdmin, userentity.RoleMember}
+	s.validUser.Permissions = []string{userentity.PermissionRolesManage, userentity.PermissionUsersCreate}
+	s.mockRepo.On("GetByEmail", s.ctx, s.validRequest.Email).Return(s.validUser, nil)
+	s.totpRepo.On("GetByUserID", s.ctx, s.validUser.ID).Return(nil, pgx.ErrNoRows)
+	s.refreshRepo.On("Create", s.ctx, mock.Anything).Return(&userentity.RefreshToken{}, nil)
+
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
+	s.Require().NoError(err)
+
+	claims, err := jwtservice.NewJWTService("test-secret-key", 15*time.Minute).ValidateToken(response.Token)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), s.validUser.Roles, claims.Roles)
+	assert.True(s.T(), claims.HasPermission(userentity.PermissionUsersCreate))
+}
+
+func (s *LoginUseCaseTestSuite) TestLogin_UpgradesBcryptHash() {
+	bcryptHash, _ := bcrypt.GenerateFromPassword([]byte(s.validRequest.Password), bcrypt.MinCost)
+	s.validUser.PasswordHash = string(bcryptHash)
+
+	var upgradedHash string
+	s.mockRepo.On("GetByEmail", s.ctx, s.validRequest.Email).Return(s.validUser, nil)
+	s.mockRepo.On("UpdatePassword", s.ctx, s.validUser.ID, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { upgradedHash = args.Get(2).(string) }).
+		Return(nil)
+	s.totpRepo.On("GetByUserID", s.ctx, s.validUser.ID).Return(nil, pgx.ErrNoRows)
+	s.refreshRepo.On("Create", s.ctx, mock.Anything).Return(&userentity.RefreshToken{}, nil)
+
+	_, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
+
+	s.Require().NoError(err)
+	assert.False(s.T(), testPasswordHasher.NeedsRehash(upgradedHash))
+	matches, err := testPasswordHasher.Verify(s.validRequest.Password, upgradedHash)
+	s.Require().NoError(err)
+	assert.True(s.T(), matches)
+}
+
+func (s *LoginUseCaseTestSuite) TestLogin_UpgradeFailureDoesNotFailLogin() {
+	outdated := password.NewHasher(password.Argon2idParams{Memory: 32, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16})
+	s.validUser.PasswordHash, _ = outdated.Hash(s.validRequest.Password)
+
+	s.mockRepo.On("GetByEmail", s.ctx, s.validRequest.Email).Return(s.validUser, nil)
+	s.mockRepo.On("UpdatePassword", s.ctx, s.validUser.ID, mock.AnythingOfType("string")).
+		Return(errors.New("database error"))
+	s.totpRepo.On("GetByUserID", s.ctx, s.validUser.ID).Return(nil, pgx.ErrNoRows)
+	s.refreshRepo.On("Create", s.ctx, mock.Anything).Return(&userentity.RefreshToken{}, nil)
+
+	_, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *LoginUseCaseTestSuite) TestLogin_Suspended() {
+	suspendedAt := time.Now()
+	s.validUser.SuspendedAt = &suspendedAt
+	s.mockRepo.On("GetByEmail", s.ctx, s.validRequest.Email).Return(s.validUser, nil)
+
+	_, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrAccountSuspended)
+}
+
+func (s *LoginUseCaseTestSuite) TestLogin_Throttled() {
+	throttleRepo := new(MockLoginThrottleRepository)
+	lockedUntil := time.Now().UTC().Add(time.Minute)
+	throttleRepo.On("Get", s.ctx, userentity.LoginThrottleScopeAccount, s.validRequest.Email).
+		Return(&userentity.LoginThrottle{Failures: 10, LockedUntil: &lockedUntil}, nil)
+	throttleRepo.On("Get", s.ctx, userentity.LoginThrottleScopeIP, "192.0.2.1").Return(nil, errThrottleNotFound)
+
+	useCase := NewLoginUseCase(s.mockRepo, testPasswordHasher, newTestTokenIssuer(s.refreshRepo),
+		NewTwoFactorChallenger(s.totpRepo, s.mfaRepo, 5*time.Minute),
+		NewLoginThrottle(throttleRepo, LoginThrottlePolicy{}, LoginThrottlePolicy{}, nil))
+
+	_, err := useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{IPAddress: "192.0.2.1"})
+
+	var throttledErr *ThrottledError
+	assert.ErrorAs(s.T(), err, &throttledErr)
+	throttleRepo.AssertExpectations(s.T())
+}
+
+func (s *LoginUseCaseTestSuite) TestLogin_ThrottleCountsFailuresAndResetsOnSuccess() {
+	throttleRepo := new(MockLoginThrottleRepository)
+	throttleRepo.On("Get", s.ctx, mock.Anything, mock.Anything).Return(nil, errThrottleNotFound)
+	throttleRepo.On("RecordFailure", s.ctx, userentity.LoginThrottleScopeAccount, s.validRequest.Email,
+		mock.Anything, mock.Anything).Return(&userentity.LoginThrottle{Failures: 1}, nil).Once()
+	throttleRepo.On("RecordFailure", s.ctx, userentity.LoginThrottleScopeIP, "192.0.2.1",
+		mock.Anything, mock.Anything).Return(&userentity.LoginThrottle{Failures: 1}, nil).Once()
+	throttleRepo.On("Reset", s.ctx, userentity.LoginThrottleScopeAccount, s.validRequest.Email).Return(nil).Once()
+	s.mockRepo.On("GetByEmail", s.ctx, s.validRequest.Email).Return(s.validUser, nil)
+	s.totpRepo.On("GetByUserID", s.ctx, s.validUser.ID).Return(nil, pgx.ErrNoRows)
+	s.refreshRepo.On("Create", s.ctx, mock.Anything).Return(&userentity.RefreshToken{}, nil)
+
+	useCase := NewLoginUseCase(s.mockRepo, testPasswordHasher, newTestTokenIssuer(s.refreshRepo),
+		NewTwoFactorChallenger(s.totpRepo, s.mfaRepo, 5*time.Minute),
+		NewLoginThrottle(throttleRepo, LoginThrottlePolicy{FreeAttempts: 3}, LoginThrottlePolicy{FreeAttempts: 3}, nil))
+
+	wrongPassword := s.validRequest
+	wrongPassword.Password = "WrongPassword123!"
+	_, err := useCase.Execute(s.ctx, wrongPassword, userdto.ClientInfo{IPAddress: "192.0.2.1"})
+	assert.ErrorIs(s.T(), err, ErrInvalidCredentials)
+
+	_, err = useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{IPAddress: "192.0.2.1"})
+	assert.NoError(s.T(), err)
+	throttleRepo.AssertExpectations(s.T())
 }
 
 func (s *LoginUseCaseTestSuite) TestLogin_UserNotFound() {
 	s.mockRepo.On("GetByEmail", s.ctx, s.validRequest.Email).Return(nil, pgx.ErrNoRows)
 
-	response, err := s.useCase.Execute(s.ctx, s.validRequest)
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
 
 	assert.Error(s.T(), err)
 	assert.Equal(s.T(), ErrInvalidCredentials, err)
@@ -88,7 +229,7 @@ func (s *LoginUseCaseTestSuite) TestLogin_WrongPassword() {
 	req := s.validRequest
 	req.Password = "WrongPassword123!"
 
-	response, err := s.useCase.Execute(s.ctx, req)
+	response, err := s.useCase.Execute(s.ctx, req, userdto.ClientInfo{})
 
 	assert.Error(s.T(), err)
 	assert.Equal(s.T(), ErrInvalidCredentials, err)
@@ -99,7 +240,7 @@ func (s *LoginUseCaseTestSuite) TestLogin_GetByEmailError() {
 	repoError := errors.New("database error")
 	s.mockRepo.On("GetByEmail", s.ctx, s.validRequest.Email).Return(nil, repoError)
 
-	response, err := s.useCase.Execute(s.ctx, s.validRequest)
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
 
 	assert.Error(s.T(), err)
 	assert.Equal(s.T(), ErrInvalidCredentials, err)
@@ -108,13 +249,60 @@ func (s *LoginUseCaseTestSuite) TestLogin_GetByEmailError() {
 
 func (s *LoginUseCaseTestSuite) TestLogin_JWTGenerationError() {
 	s.mockRepo.On("GetByEmail", s.ctx, s.validRequest.Email).Return(s.validUser, nil)
+	s.totpRepo.On("GetByUserID", s.ctx, s.validUser.ID).Return(nil, pgx.ErrNoRows)
+	s.refreshRepo.On("Create", s.ctx, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
 
-	response, err := s.useCase.Execute(s.ctx, s.validRequest)
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
 
 	assert.NoError(s.T(), err)
 	assert.NotEmpty(s.T(), response.Token)
 }
 
+func (s *LoginUseCaseTestSuite) TestLogin_RefreshTokenCreateError() {
+	repoError := errors.New("database error")
+	s.mockRepo.On("GetByEmail", s.ctx, s.validRequest.Email).Return(s.validUser, nil)
+	s.totpRepo.On("GetByUserID", s.ctx, s.validUser.ID).Return(nil, pgx.ErrNoRows)
+	s.refreshRepo.On("Create", s.ctx, mock.AnythingOfType("*user.RefreshToken")).Return(nil, repoError)
+
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
+
+	assert.Equal(s.T(), repoError, err)
+	assert.Empty(s.T(), response.Token)
+}
+
+func (s *LoginUseCaseTestSuite) TestLogin_TwoFactorRequired() {
+	confirmedAt := time.Now()
+	s.mockRepo.On("GetByEmail", s.ctx, s.validRequest.Email).Return(s.validUser, nil)
+	s.totpRepo.On("GetByUserID", s.ctx, s.validUser.ID).Return(&userentity.UserTOTP{
+		UserID:      s.validUser.ID,
+		ConfirmedAt: &confirmedAt,
+	}, nil)
+	s.mfaRepo.On("Create", s.ctx, mock.MatchedBy(func(c *userentity.MFAChallenge) bool {
+		return c.UserID == s.validUser.ID && c.TokenHash != ""
+	})).Return(&userentity.MFAChallenge{ExpiresAt: time.Now().Add(5 * time.Minute)}, nil)
+
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
+
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), response.Token)
+	assert.Empty(s.T(), response.RefreshToken)
+	s.Require().NotNil(response.MFAChallenge)
+	assert.True(s.T(), response.MFAChallenge.MFARequired)
+	assert.NotEmpty(s.T(), response.MFAChallenge.MFAToken)
+	s.refreshRepo.AssertNotCalled(s.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (s *LoginUseCaseTestSuite) TestLogin_TwoFactorLookupError() {
+	repoError := errors.New("database error")
+	s.mockRepo.On("GetByEmail", s.ctx, s.validRequest.Email).Return(s.validUser, nil)
+	s.totpRepo.On("GetByUserID", s.ctx, s.validUser.ID).Return(nil, repoError)
+
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
+
+	assert.Equal(s.T(), repoError, err)
+	assert.Empty(s.T(), response.Token)
+}
+
 func TestLoginUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(LoginUseCaseTestSuite))
 }
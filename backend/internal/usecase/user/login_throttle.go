@@ -0,0 +1,222 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/mailer"
+	userrepo "roadmap/internal/repository/user"
+)
+
+// maxBackoffShift keeps the exponential backoff from overflowing.
+const maxBackoffShift = 30
+
+// LoginThrottlePolicy decides how failed logins for one key are answered.
+// The first FreeAttempts failures cost nothing; every further failure
+// refuses attempts for BaseDelay, doubling per failure up to MaxDelay. Once
+// LockoutThreshold failures have been counted, attempts are refused for
+// LockoutDuration instead. Failures are forgotten after ForgetAfter without
+// a new one. Zero values disable the respective step.
+type LoginThrottlePolicy struct {
+	FreeAttempts     int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	ForgetAfter      time.Duration
+}
+
+// delay returns how long attempts are refused after the given number of
+// failures and whether that is a lockout rather than backoff.
+func (p LoginThrottlePolicy) delay(failures int) (time.Duration, bool) {
+	if p.LockoutThreshold > 0 && failures >= p.LockoutThreshold {
+		return p.LockoutDuration, true
+	}
+	if p.BaseDelay <= 0 || failures <= p.FreeAttempts {
+		return 0, false
+	}
+
+	delay := p.BaseDelay << min(failures-p.FreeAttempts-1, maxBackoffShift)
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		delay = p.MaxDelay
+	}
+	return delay, false
+}
+
+// LockoutNotifier tells account owners that sign-in to their account has
+// been locked after repeated failed attempts.
+type LockoutNotifier interface {
+	NotifyLockout(ctx context.Context, user *userentity.User, lockedUntil time.Time) error
+}
+
+// MailLockoutNotifier sends lockout notices by email.
+type MailLockoutNotifier struct {
+	mailer mailer.Mailer
+}
+
+func NewMailLockoutNotifier(mailer mailer.Mailer) *MailLockoutNotifier {
+	return &MailLockoutNotifier{
+		mailer: mailer,
+	}
+}
+
+func (n *MailLockoutNotifier) NotifyLockout(ctx context.Context, user *userentity.User, lockedUntil time.Time) error {
+	return n.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Sign-in to your account has been locked",
+		Body: fmt.Sprintf("Hi %s,\n\n", user.Username) +
+			fmt.Sprintf("After several failed login attempts, password sign-in to your account is locked until %s.\n\n",
+				lockedUntil.UTC().Format(time.RFC1123)) +
+			"If these attempts were not yours, someone may be guessing your password. " +
+			"We recommend changing it to a strong, unique one once you can sign in again.\n",
+	})
+}
+
+// LoginThrottle slows down password guessing. Failed logins are counted
+// per account and per client IP in the database, so limits hold across API
+// instances; either key being locked refuses the attempt.
+type LoginThrottle struct {
+	repository userrepo.LoginThrottleRepository
+	account    LoginThrottlePolicy
+	ip         LoginThrottlePolicy
+	notifier   LockoutNotifier
+}
+
+// NewLoginThrottle creates a throttle. notifier may be nil, in which case
+// lockouts are not announced to the account owner.
+func NewLoginThrottle(
+	repository userrepo.LoginThrottleRepository,
+	account LoginThrottlePolicy,
+	ip LoginThrottlePolicy,
+	notifier LockoutNotifier,
+) *LoginThrottle {
+	return &LoginThrottle{
+		repository: repository,
+		account:    account,
+		ip:         ip,
+		notifier:   notifier,
+	}
+}
+
+// accountKey normalizes email so that variants of the same address share
+// one counter, whether or not an account exists for it.
+func accountKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Check returns a *ThrottledError while attempts for email or clientIP are
+// refused. An empty clientIP is not checked.
+func (t *LoginThrottle) Check(ctx context.Context, email, clientIP string) error {
+	now := time.Now().UTC()
+
+	retryAfter, err := t.retryAfter(ctx, userentity.LoginThrottleScopeAccount, accountKey(email), now)
+	if err != nil {
+		return err
+	}
+
+	if clientIP != "" {
+		ipRetryAfter, err := t.retryAfter(ctx, userentity.LoginThrottleScopeIP, clientIP, now)
+		if err != nil {
+			return err
+		}
+		retryAfter = max(retryAfter, ipRetryAfter)
+	}
+
+	if retryAfter > 0 {
+		return &ThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+func (t *LoginThrottle) retryAfter(ctx context.Context, scope, key string, now time.Time) (time.Duration, error) {
+	throttle, err := t.repository.Get(ctx, scope, key)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return throttle.RetryAfter(now), nil
+}
+
+// RecordFailure counts a failed login. user is the account the email
+// belongs to, or nil if there is none; its owner is notified when the
+// failure locks the account.
+func (t *LoginThrottle) RecordFailure(ctx context.Context, email, clientIP string, user *userentity.User) error {
+	now := time.Now().UTC()
+
+	lockedUntil, lockout, err := t.recordFailure(ctx, userentity.LoginThrottleScopeAccount, accountKey(email), t.account, now)
+	if err != nil {
+		return err
+	}
+
+	if clientIP != "" {
+		if _, _, err := t.recordFailure(ctx, userentity.LoginThrottleScopeIP, clientIP, t.ip, now); err != nil {
+			return err
+		}
+	}
+
+	if lockout && user != nil && t.notifier != nil {
+		if err := t.notifier.NotifyLockout(ctx, user, lockedUntil); err != nil {
+			log.Printf("Failed to send lockout notice to user %s: %v", user.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// recordFailure counts a failure for key and locks it as policy demands.
+// Attempts on a locked key are refused before they are checked, so every
+// failure that reaches the lockout threshold starts a new lockout.
+func (t *LoginThrottle) recordFailure(
+	ctx context.Context,
+	scope, key string,
+	policy LoginThrottlePolicy,
+	now time.Time,
+) (time.Time, bool, error) {
+	forgetBefore := time.Time{}
+	if policy.ForgetAfter > 0 {
+		forgetBefore = now.Add(-policy.ForgetAfter)
+	}
+
+	throttle, err := t.repository.RecordFailure(ctx, scope, key, now, forgetBefore)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	delay, lockout := policy.delay(throttle.Failures)
+	if delay <= 0 {
+		return time.Time{}, false, nil
+	}
+
+	lockedUntil := now.Add(delay)
+	if err := t.repository.Lock(ctx, scope, key, lockedUntil); err != nil {
+		return time.Time{}, false, err
+	}
+
+	return lockedUntil, lockout, nil
+}
+
+// RecordSuccess clears the account's failures. The client IP's failures
+// are kept: signing in to one account must not reset guessing at others.
+func (t *LoginThrottle) RecordSuccess(ctx context.Context, email string) error {
+	return t.repository.Reset(ctx, userentity.LoginThrottleScopeAccount, accountKey(email))
+}
+
+// PurgeStale removes throttles whose failures have been forgotten. Nothing
+// is purged while either policy never forgets failures.
+func (t *LoginThrottle) PurgeStale(ctx context.Context) (int64, error) {
+	if t.account.ForgetAfter <= 0 || t.ip.ForgetAfter <= 0 {
+		return 0, nil
+	}
+
+	forgetAfter := max(t.account.ForgetAfter, t.ip.ForgetAfter)
+	return t.repository.DeleteStale(ctx, time.Now().UTC().Add(-forgetAfter))
+}
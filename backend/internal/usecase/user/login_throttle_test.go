@@ -0,0 +1,265 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/mailer"
+	"roadmap/internal/pkg/mailer/mailertest"
+)
+
+type MockLoginThrottleRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginThrottleRepository) Get(ctx context.Context, scope, key string) (*userentity.LoginThrottle, error) {
+	args := m.Called(ctx, scope, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.LoginThrottle), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) RecordFailure(
+	ctx context.Context,
+	scope, key string,
+	now, forgetBefore time.Time,
+) (*userentity.LoginThrottle, error) {
+	args := m.Called(ctx, scope, key, now, forgetBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.LoginThrottle), args.Error(1)
+}
+
+func (m *MockLoginThrottleRepository) Lock(ctx context.Context, scope, key string, until time.Time) error {
+	args := m.Called(ctx, scope, key, until)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleRepository) Reset(ctx context.Context, scope, key string) error {
+	args := m.Called(ctx, scope, key)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleRepository) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type stubLockoutNotifier struct {
+	notified []*userentity.User
+	err      error
+}
+
+func (n *stubLockoutNotifier) NotifyLockout(_ context.Context, user *userentity.User, _ time.Time) error {
+	n.notified = append(n.notified, user)
+	return n.err
+}
+
+var errThrottleNotFound = fmt.Errorf("login throttle not found: %w", pgx.ErrNoRows)
+
+func TestLoginThrottlePolicy_Delay(t *testing.T) {
+	policy := LoginThrottlePolicy{
+		FreeAttempts:     2,
+		BaseDelay:        time.Second,
+		MaxDelay:         5 * time.Second,
+		LockoutThreshold: 10,
+		LockoutDuration:  15 * time.Minute,
+	}
+
+	testCases := []struct {
+		failures        int
+		expectedDelay   time.Duration
+		expectedLockout bool
+	}{
+		{1, 0, false},
+		{2, 0, false},
+		{3, time.Second, false},
+		{4, 2 * time.Second, false},
+		{5, 4 * time.Second, false},
+		{6, 5 * time.Second, false},
+		{9, 5 * time.Second, false},
+		{10, 15 * time.Minute, true},
+		{11, 15 * time.Minute, true},
+	}
+
+	for _, tc := range testCases {
+		delay, lockout := policy.delay(tc.failures)
+		assert.Equal(t, tc.expectedDelay, delay, "failures=%d", tc.failures)
+		assert.Equal(t, tc.expectedLockout, lockout, "failures=%d", tc.failures)
+	}
+
+	delay, _ := LoginThrottlePolicy{BaseDelay: time.Hour, MaxDelay: 24 * time.Hour}.delay(1000)
+	assert.Equal(t, 24*time.Hour, delay, "large failure counts must not overflow")
+}
+
+type LoginThrottleTestSuite struct {
+	suite.Suite
+	repo     *MockLoginThrottleRepository
+	notifier *stubLockoutNotifier
+	throttle *LoginThrottle
+	user     *userentity.User
+	ctx      context.Context
+}
+
+func (s *LoginThrottleTestSuite) SetupTest() {
+	s.repo = new(MockLoginThrottleRepository)
+	s.notifier = &stubLockoutNotifier{}
+	s.throttle = NewLoginThrottle(
+		s.repo,
+		LoginThrottlePolicy{FreeAttempts: 2, BaseDelay: time.Second, LockoutThreshold: 5, LockoutDuration: time.Hour},
+		LoginThrottlePolicy{FreeAttempts: 50, BaseDelay: time.Second, ForgetAfter: time.Hour},
+		s.notifier,
+	)
+	s.user = &userentity.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com"}
+	s.ctx = context.Background()
+}
+
+func (s *LoginThrottleTestSuite) TearDownTest() {
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *LoginThrottleTestSuite) TestCheck_NotThrottled() {
+	s.repo.On("Get", s.ctx, userentity.LoginThrottleScopeAccount, "test@example.com").Return(nil, errThrottleNotFound)
+	s.repo.On("Get", s.ctx, userentity.LoginThrottleScopeIP, "192.0.2.1").
+		Return(&userentity.LoginThrottle{Failures: 3}, nil)
+
+	err := s.throttle.Check(s.ctx, " Test@Example.com", "192.0.2.1")
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *LoginThrottleTestSuite) TestCheck_Throttled() {
+	lockedUntil := time.Now().UTC().Add(30 * time.Second)
+	s.repo.On("Get", s.ctx, userentity.LoginThrottleScopeAccount, "test@example.com").
+		Return(&userentity.LoginThrottle{Failures: 4, LockedUntil: &lockedUntil}, nil)
+
+	err := s.throttle.Check(s.ctx, "test@example.com", "")
+
+	var throttledErr *ThrottledError
+	s.Require().ErrorAs(err, &throttledErr)
+	assert.InDelta(s.T(), 30, throttledErr.RetryAfter.Seconds(), 1)
+}
+
+func (s *LoginThrottleTestSuite) TestCheck_RepositoryError() {
+	s.repo.On("Get", s.ctx, userentity.LoginThrottleScopeAccount, "test@example.com").
+		Return(nil, errors.New("database error"))
+
+	err := s.throttle.Check(s.ctx, "test@example.com", "192.0.2.1")
+
+	assert.EqualError(s.T(), err, "database error")
+}
+
+func (s *LoginThrottleTestSuite) TestRecordFailure_Backoff() {
+	s.repo.On("RecordFailure", s.ctx, userentity.LoginThrottleScopeAccount, "test@example.com",
+		mock.AnythingOfType("time.Time"), time.Time{}).Return(&userentity.LoginThrottle{Failures: 3}, nil)
+	s.repo.On("Lock", s.ctx, userentity.LoginThrottleScopeAccount, "test@example.com",
+		mock.AnythingOfType("time.Time")).Return(nil)
+	s.repo.On("RecordFailure", s.ctx, userentity.LoginThrottleScopeIP, "192.0.2.1",
+		mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&userentity.LoginThrottle{Failures: 3}, nil)
+
+	err := s.throttle.RecordFailure(s.ctx, "test@example.com", "192.0.2.1", s.user)
+
+	s.Require().NoError(err)
+	assert.Empty(s.T(), s.notifier.notified, "backoff is not a lockout")
+}
+
+func (s *LoginThrottleTestSuite) TestRecordFailure_LockoutNotifiesOwner() {
+	var lockedUntil time.Time
+	s.repo.On("RecordFailure", s.ctx, userentity.LoginThrottleScopeAccount, "test@example.com",
+		mock.Anything, mock.Anything).Return(&userentity.LoginThrottle{Failures: 5}, nil)
+	s.repo.On("Lock", s.ctx, userentity.LoginThrottleScopeAccount, "test@example.com", mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) { lockedUntil = args.Get(3).(time.Time) }).
+		Return(nil)
+
+	err := s.throttle.RecordFailure(s.ctx, "test@example.com", "", s.user)
+
+	s.Require().NoError(err)
+	assert.WithinDuration(s.T(), time.Now().Add(time.Hour), lockedUntil, time.Minute)
+	assert.Equal(s.T(), []*userentity.User{s.user}, s.notifier.notified)
+}
+
+func (s *LoginThrottleTestSuite) TestRecordFailure_UnknownAccount() {
+	s.repo.On("RecordFailure", s.ctx, userentity.LoginThrottleScopeAccount, "nobody@example.com",
+		mock.Anything, mock.Anything).Return(&userentity.LoginThrottle{Failures: 5}, nil)
+	s.repo.On("Lock", s.ctx, userentity.LoginThrottleScopeAccount, "nobody@example.com", mock.Anything).Return(nil)
+
+	err := s.throttle.RecordFailure(s.ctx, "nobody@example.com", "", nil)
+
+	s.Require().NoError(err)
+	assert.Empty(s.T(), s.notifier.notified)
+}
+
+func (s *LoginThrottleTestSuite) TestRecordFailure_NotifierErrorIsLogged() {
+	s.notifier.err = errors.New("smtp unavailable")
+	s.repo.On("RecordFailure", s.ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&userentity.LoginThrottle{Failures: 5}, nil)
+	s.repo.On("Lock", s.ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := s.throttle.RecordFailure(s.ctx, "test@example.com", "", s.user)
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *LoginThrottleTestSuite) TestRecordSuccess_ResetsAccountOnly() {
+	s.repo.On("Reset", s.ctx, userentity.LoginThrottleScopeAccount, "test@example.com").Return(nil)
+
+	err := s.throttle.RecordSuccess(s.ctx, "TEST@example.com")
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *LoginThrottleTestSuite) TestPurgeStale() {
+	purged, err := s.throttle.PurgeStale(s.ctx)
+	s.Require().NoError(err)
+	assert.Zero(s.T(), purged, "the account policy never forgets failures")
+
+	throttle := NewLoginThrottle(s.repo, LoginThrottlePolicy{ForgetAfter: time.Hour},
+		LoginThrottlePolicy{ForgetAfter: 2 * time.Hour}, nil)
+	s.repo.On("DeleteStale", s.ctx, mock.MatchedBy(func(before time.Time) bool {
+		return before.Before(time.Now().Add(-2*time.Hour + time.Minute))
+	})).Return(int64(3), nil)
+
+	purged, err = throttle.PurgeStale(s.ctx)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), int64(3), purged)
+}
+
+func TestLoginThrottleTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginThrottleTestSuite))
+}
+
+func TestMailLockoutNotifier_SMTP(t *testing.T) {
+	sink, err := mailertest.NewSMTPSink()
+	require.NoError(t, err)
+	defer sink.Close()
+
+	notifier := NewMailLockoutNotifier(mailer.NewSMTPMailer(mailer.SMTPConfig{
+		Host: sink.Host,
+		Port: sink.Port,
+		From: "noreply@example.com",
+	}))
+	user := &userentity.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com"}
+	lockedUntil := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	require.NoError(t, notifier.NotifyLockout(context.Background(), user, lockedUntil))
+
+	message, err := sink.Receive(5 * time.Second)
+	require.NoError(t, err)
+	assert.Contains(t, message, "To: test@example.com\r\n")
+	assert.Contains(t, message, "Subject: Sign-in to your account has been locked\r\n")
+	assert.Contains(t, message, "Hi testuser,")
+	assert.Contains(t, message, "Wed, 02 Jan 2030 15:04:05 UTC")
+}
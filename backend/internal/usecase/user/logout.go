@@ -0,0 +1,76 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	userdto "roadmap/internal/domain/dto/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type LogoutUseCase struct {
+	refreshTokenRepository userrepo.RefreshTokenRepository
+	revocationService      *TokenRevocationService
+}
+
+func NewLogoutUseCase(
+	refreshTokenRepository userrepo.RefreshTokenRepository,
+	revocationService *TokenRevocationService,
+) *LogoutUseCase {
+	return &LogoutUseCase{
+		refreshTokenRepository: refreshTokenRepository,
+		revocationService:      revocationService,
+	}
+}
+
+// Execute revokes the access token described by claims and, when the client
+// passes it along, the refresh token family of the same login.
+func (u *LogoutUseCase) Execute(ctx context.Context, claims *jwtservice.Claims, req userdto.LogoutRequest) error {
+	if err := u.revocationService.RevokeToken(ctx, claims); err != nil {
+		return err
+	}
+
+	if req.RefreshToken == "" {
+		return nil
+	}
+
+	refreshToken, err := u.refreshTokenRepository.GetByHash(ctx, hashOpaqueToken(req.RefreshToken))
+	if err != nil {
+		return nil
+	}
+	if refreshToken.UserID.String() != claims.UserID {
+		return nil
+	}
+
+	return u.refreshTokenRepository.RevokeFamily(ctx, refreshToken.FamilyID)
+}
+
+type LogoutAllUseCase struct {
+	refreshTokenRepository userrepo.RefreshTokenRepository
+	revocationService      *TokenRevocationService
+}
+
+func NewLogoutAllUseCase(
+	refreshTokenRepository userrepo.RefreshTokenRepository,
+	revocationService *TokenRevocationService,
+) *LogoutAllUseCase {
+	return &LogoutAllUseCase{
+		refreshTokenRepository: refreshTokenRepository,
+		revocationService:      revocationService,
+	}
+}
+
+func (u *LogoutAllUseCase) Execute(ctx context.Context, userID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return ErrInvalidTokenClaims
+	}
+
+	if err := u.revocationService.RevokeAllForUser(ctx, id); err != nil {
+		return err
+	}
+
+	return u.refreshTokenRepository.RevokeAllForUser(ctx, id)
+}
@@ -0,0 +1,112 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+)
+
+type LogoutUseCaseTestSuite struct {
+	suite.Suite
+	logoutUseCase    *LogoutUseCase
+	logoutAllUseCase *LogoutAllUseCase
+	refreshRepo      *MockRefreshTokenRepository
+	revocationRepo   *MockTokenRevocationRepository
+	userID           uuid.UUID
+	claims           *jwtservice.Claims
+	ctx              context.Context
+}
+
+func (s *LogoutUseCaseTestSuite) SetupTest() {
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.revocationRepo = new(MockTokenRevocationRepository)
+	revocationService := NewTokenRevocationService(s.revocationRepo, time.Minute)
+	s.logoutUseCase = NewLogoutUseCase(s.refreshRepo, revocationService)
+	s.logoutAllUseCase = NewLogoutAllUseCase(s.refreshRepo, revocationService)
+	s.ctx = context.Background()
+	s.userID = uuid.New()
+
+	s.claims = &jwtservice.Claims{
+		UserID: s.userID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
+		},
+	}
+}
+
+func (s *LogoutUseCaseTestSuite) TearDownTest() {
+	s.refreshRepo.AssertExpectations(s.T())
+	s.revocationRepo.AssertExpectations(s.T())
+}
+
+func (s *LogoutUseCaseTestSuite) TestLogout_AccessTokenOnly() {
+	s.revocationRepo.On("RevokeToken", s.ctx, s.claims.ID, s.userID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := s.logoutUseCase.Execute(s.ctx, s.claims, userdto.LogoutRequest{})
+
+	assert.NoError(s.T(), err)
+	s.refreshRepo.AssertNotCalled(s.T(), "GetByHash", mock.Anything, mock.Anything)
+}
+
+func (s *LogoutUseCaseTestSuite) TestLogout_WithRefreshToken() {
+	refreshToken := &userentity.RefreshToken{ID: uuid.New(), UserID: s.userID, FamilyID: uuid.New()}
+	s.revocationRepo.On("RevokeToken", s.ctx, s.claims.ID, s.userID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("GetByHash", s.ctx, hashOpaqueToken("refresh")).Return(refreshToken, nil)
+	s.refreshRepo.On("RevokeFamily", s.ctx, refreshToken.FamilyID).Return(nil)
+
+	err := s.logoutUseCase.Execute(s.ctx, s.claims, userdto.LogoutRequest{RefreshToken: "refresh"})
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *LogoutUseCaseTestSuite) TestLogout_IgnoresRefreshTokenOfAnotherUser() {
+	refreshToken := &userentity.RefreshToken{ID: uuid.New(), UserID: uuid.New(), FamilyID: uuid.New()}
+	s.revocationRepo.On("RevokeToken", s.ctx, s.claims.ID, s.userID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("GetByHash", s.ctx, hashOpaqueToken("refresh")).Return(refreshToken, nil)
+
+	err := s.logoutUseCase.Execute(s.ctx, s.claims, userdto.LogoutRequest{RefreshToken: "refresh"})
+
+	assert.NoError(s.T(), err)
+	s.refreshRepo.AssertNotCalled(s.T(), "RevokeFamily", mock.Anything, mock.Anything)
+}
+
+func (s *LogoutUseCaseTestSuite) TestLogout_RevokeError() {
+	repoError := errors.New("database error")
+	s.revocationRepo.On("RevokeToken", s.ctx, s.claims.ID, s.userID, mock.AnythingOfType("time.Time")).Return(repoError)
+
+	err := s.logoutUseCase.Execute(s.ctx, s.claims, userdto.LogoutRequest{})
+
+	assert.Equal(s.T(), repoError, err)
+}
+
+func (s *LogoutUseCaseTestSuite) TestLogoutAll_Success() {
+	s.revocationRepo.On("SetTokensValidAfter", s.ctx, s.userID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("RevokeAllForUser", s.ctx, s.userID).Return(nil)
+
+	err := s.logoutAllUseCase.Execute(s.ctx, s.userID.String())
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *LogoutUseCaseTestSuite) TestLogoutAll_InvalidUserID() {
+	err := s.logoutAllUseCase.Execute(s.ctx, "not-a-uuid")
+
+	assert.ErrorIs(s.T(), err, ErrInvalidTokenClaims)
+}
+
+func TestLogoutUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(LogoutUseCaseTestSuite))
+}
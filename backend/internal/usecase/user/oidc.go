@@ -0,0 +1,445 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/oidc"
+	userrepo "roadmap/internal/repository/user"
+)
+
+const (
+	minUsernameLength   = 3
+	maxUsernameLength   = 100
+	maxDisplayNameRunes = 100
+
+	// maxUsernameAttempts bounds how many numbered variants of a taken
+	// username are tried for accounts created from an external identity.
+	maxUsernameAttempts = 5
+)
+
+var usernameUnsafeChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// OIDCProviders holds the configured identity providers by name.
+type OIDCProviders map[string]*oidc.Provider
+
+func NewOIDCProviders(providers ...*oidc.Provider) OIDCProviders {
+	registry := make(OIDCProviders, len(providers))
+	for _, provider := range providers {
+		registry[provider.Name()] = provider
+	}
+	return registry
+}
+
+// OIDCFlow runs the authorization code flow. The state handed to the
+// provider is stored hashed together with the nonce and PKCE verifier, and
+// can be redeemed exactly once. The client is expected to check that the
+// state it receives on the callback is the one it started with.
+type OIDCFlow struct {
+	providers         OIDCProviders
+	requestRepository userrepo.OIDCAuthRequestRepository
+	ttl               time.Duration
+}
+
+func NewOIDCFlow(
+	providers OIDCProviders,
+	requestRepository userrepo.OIDCAuthRequestRepository,
+	ttl time.Duration,
+) *OIDCFlow {
+	return &OIDCFlow{
+		providers:         providers,
+		requestRepository: requestRepository,
+		ttl:               ttl,
+	}
+}
+
+func (f *OIDCFlow) start(
+	ctx context.Context,
+	providerName string,
+	userID *uuid.UUID,
+) (userdto.OIDCAuthorizationResponse, error) {
+	provider, ok := f.providers[providerName]
+	if !ok {
+		return userdto.OIDCAuthorizationResponse{}, ErrUnknownOIDCProvider
+	}
+
+	state, err := generateOpaqueToken()
+	if err != nil {
+		return userdto.OIDCAuthorizationResponse{}, err
+	}
+	nonce, err := generateOpaqueToken()
+	if err != nil {
+		return userdto.OIDCAuthorizationResponse{}, err
+	}
+	codeVerifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return userdto.OIDCAuthorizationResponse{}, err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return userdto.OIDCAuthorizationResponse{}, err
+	}
+
+	now := time.Now()
+	request, err := f.requestRepository.Create(ctx, &userentity.OIDCAuthRequest{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Provider:     providerName,
+		StateHash:    hashOpaqueToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    now.Add(f.ttl),
+		CreatedAt:    now,
+	})
+	if err != nil {
+		return userdto.OIDCAuthorizationResponse{}, err
+	}
+
+	return userdto.OIDCAuthorizationResponse{
+		AuthorizationURL: authURL,
+		ExpiresAt:        request.ExpiresAt,
+	}, nil
+}
+
+// complete redeems the callback's state, exchanges the code and returns the
+// verified ID token.
+func (f *OIDCFlow) complete(
+	ctx context.Context,
+	providerName string,
+	req userdto.OIDCCallbackRequest,
+) (*userentity.OIDCAuthRequest, *oidc.IDToken, error) {
+	provider, ok := f.providers[providerName]
+	if !ok {
+		return nil, nil, ErrUnknownOIDCProvider
+	}
+
+	stored, err := f.requestRepository.GetByStateHash(ctx, hashOpaqueToken(req.State))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrInvalidOIDCState
+		}
+		return nil, nil, err
+	}
+
+	if stored.Provider != providerName || stored.IsUsed() || stored.IsExpired(time.Now()) {
+		return nil, nil, ErrInvalidOIDCState
+	}
+
+	if err := f.requestRepository.MarkUsed(ctx, stored.ID); err != nil {
+		if errors.Is(err, userrepo.ErrTokenAlreadyUsed) {
+			return nil, nil, ErrInvalidOIDCState
+		}
+		return nil, nil, err
+	}
+
+	token, err := provider.Exchange(ctx, req.Code, stored.CodeVerifier)
+	if err != nil {
+		log.Printf("OIDC code exchange with %s failed: %v", providerName, err)
+		return nil, nil, fmt.Errorf("%w: %v", ErrOIDCVerification, err)
+	}
+
+	idToken, err := provider.VerifyIDToken(ctx, token.IDToken, stored.Nonce)
+	if err != nil {
+		log.Printf("OIDC id token from %s rejected: %v", providerName, err)
+		return nil, nil, fmt.Errorf("%w: %v", ErrOIDCVerification, err)
+	}
+
+	return stored, idToken, nil
+}
+
+func newUserIdentityResponse(identity *userentity.UserIdentity) userdto.UserIdentityResponse {
+	return userdto.UserIdentityResponse{
+		ID:        identity.ID,
+		Provider:  identity.Provider,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
+
+type BeginOIDCLoginUseCase struct {
+	flow *OIDCFlow
+}
+
+func NewBeginOIDCLoginUseCase(flow *OIDCFlow) *BeginOIDCLoginUseCase {
+	return &BeginOIDCLoginUseCase{
+		flow: flow,
+	}
+}
+
+func (u *BeginOIDCLoginUseCase) Execute(ctx context.Context, provider string) (userdto.OIDCAuthorizationResponse, error) {
+	return u.flow.start(ctx, provider, nil)
+}
+
+type FinishOIDCLoginUseCase struct {
+	userRepository     userrepo.UserRepository
+	identityRepository userrepo.UserIdentityRepository
+	flow               *OIDCFlow
+	tokenIssuer        *TokenIssuer
+	twoFactor          *TwoFactorChallenger
+	verificationEmail  *EmailVerificationSender
+}
+
+func NewFinishOIDCLoginUseCase(
+	userRepository userrepo.UserRepository,
+	identityRepository userrepo.UserIdentityRepository,
+	flow *OIDCFlow,
+	tokenIssuer *TokenIssuer,
+	twoFactor *TwoFactorChallenger,
+	verificationEmail *EmailVerificationSender,
+) *FinishOIDCLoginUseCase {
+	return &FinishOIDCLoginUseCase{
+		userRepository:     userRepository,
+		identityRepository: identityRepository,
+		flow:               flow,
+		tokenIssuer:        tokenIssuer,
+		twoFactor:          twoFactor,
+		verificationEmail:  verificationEmail,
+	}
+}
+
+// Execute signs in the user linked to the external identity, creating an
+// account on first sign-in. An existing account with the same email is never
+// linked implicitly; its owner has to sign in and link the provider.
+func (u *FinishOIDCLoginUseCase) Execute(
+	ctx context.Context,
+	provider string,
+	req userdto.OIDCCallbackRequest,
+	client userdto.ClientInfo,
+) (userdto.LoginResponse, error) {
+	stored, idToken, err := u.flow.complete(ctx, provider, req)
+	if err != nil {
+		return userdto.LoginResponse{}, err
+	}
+	if stored.IsLink() {
+		return userdto.LoginResponse{}, ErrInvalidOIDCState
+	}
+
+	var user *userentity.User
+	identity, err := u.identityRepository.GetByProviderSubject(ctx, provider, idToken.Subject)
+	switch {
+	case err == nil:
+		user, err = u.userRepository.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return userdto.LoginResponse{}, err
+		}
+		if err := u.identityRepository.UpdateLastLogin(ctx, identity.ID, time.Now()); err != nil {
+			return userdto.LoginResponse{}, err
+		}
+	case errors.Is(err, pgx.ErrNoRows):
+		user, err = u.createAccount(ctx, provider, idToken)
+		if err != nil {
+			return userdto.LoginResponse{}, err
+		}
+	default:
+		return userdto.LoginResponse{}, err
+	}
+
+	return completeLogin(ctx, u.twoFactor, u.tokenIssuer, user, client)
+}
+
+func (u *FinishOIDCLoginUseCase) createAccount(
+	ctx context.Context,
+	provider string,
+	idToken *oidc.IDToken,
+) (*userentity.User, error) {
+	if idToken.Email == "" {
+		return nil, ErrOIDCEmailMissing
+	}
+
+	if err := checkEmailAvailable(ctx, u.userRepository, idToken.Email); err != nil {
+		return nil, err
+	}
+
+	username, err := u.availableUsername(ctx, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &userentity.User{
+		ID:          uuid.New(),
+		Username:    username,
+		Email:       idToken.Email,
+		DisplayName: truncateRunes(strings.TrimSpace(idToken.Name), maxDisplayNameRunes),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if idToken.EmailVerified {
+		user.EmailVerifiedAt = &now
+	}
+
+	createdUser, _, err := u.identityRepository.CreateWithUser(ctx, user, &userentity.UserIdentity{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		Provider:    provider,
+		Subject:     idToken.Subject,
+		Email:       idToken.Email,
+		LastLoginAt: &now,
+		CreatedAt:   now,
+	})
+	if err != nil {
+		if errors.Is(err, userrepo.ErrEmailTaken) {
+			return nil, ErrEmailAlreadyExists
+		}
+		if errors.Is(err, userrepo.ErrIdentityAlreadyLinked) {
+			return nil, ErrIdentityAlreadyLinked
+		}
+		return nil, err
+	}
+
+	if !createdUser.IsEmailVerified() {
+		if err := u.verificationEmail.Send(ctx, createdUser); err != nil {
+			log.Printf("Failed to send verification email to user %s: %v", createdUser.ID, err)
+		}
+	}
+
+	return createdUser, nil
+}
+
+// availableUsername derives a username from the ID token and, if it is
+// taken, tries numbered variants of it.
+func (u *FinishOIDCLoginUseCase) availableUsername(ctx context.Context, idToken *oidc.IDToken) (string, error) {
+	base := usernameFromClaims(idToken)
+	candidate := base
+
+	for attempt := 0; attempt < maxUsernameAttempts; attempt++ {
+		err := checkUsernameAvailable(ctx, u.userRepository, candidate)
+		if err == nil {
+			return candidate, nil
+		}
+		if !errors.Is(err, ErrUsernameAlreadyExists) {
+			return "", err
+		}
+
+		suffix, err := rand.Int(rand.Reader, big.NewInt(10000))
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s-%04d", base, suffix.Int64())
+	}
+
+	return "", ErrUsernameAlreadyExists
+}
+
+func usernameFromClaims(idToken *oidc.IDToken) string {
+	localPart, _, _ := strings.Cut(idToken.Email, "@")
+
+	for _, claim := range []string{idToken.PreferredUsername, localPart, idToken.Name} {
+		username := strings.Trim(usernameUnsafeChars.ReplaceAllString(claim, "-"), "-.")
+		if len(username) >= minUsernameLength {
+			// Leave room for the numbered suffix.
+			return truncateRunes(username, maxUsernameLength-5)
+		}
+	}
+
+	return "user"
+}
+
+func truncateRunes(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
+
+type BeginOIDCLinkUseCase struct {
+	flow *OIDCFlow
+}
+
+func NewBeginOIDCLinkUseCase(flow *OIDCFlow) *BeginOIDCLinkUseCase {
+	return &BeginOIDCLinkUseCase{
+		flow: flow,
+	}
+}
+
+func (u *BeginOIDCLinkUseCase) Execute(
+	ctx context.Context,
+	userID string,
+	provider string,
+) (userdto.OIDCAuthorizationResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.OIDCAuthorizationResponse{}, ErrInvalidTokenClaims
+	}
+
+	return u.flow.start(ctx, provider, &id)
+}
+
+type FinishOIDCLinkUseCase struct {
+	identityRepository userrepo.UserIdentityRepository
+	flow               *OIDCFlow
+}
+
+func NewFinishOIDCLinkUseCase(
+	identityRepository userrepo.UserIdentityRepository,
+	flow *OIDCFlow,
+) *FinishOIDCLinkUseCase {
+	return &FinishOIDCLinkUseCase{
+		identityRepository: identityRepository,
+		flow:               flow,
+	}
+}
+
+// Execute links the external identity to the signed-in user. Linking an
+// identity the user already has is a no-op.
+func (u *FinishOIDCLinkUseCase) Execute(
+	ctx context.Context,
+	userID string,
+	provider string,
+	req userdto.OIDCCallbackRequest,
+) (userdto.UserIdentityResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.UserIdentityResponse{}, ErrInvalidTokenClaims
+	}
+
+	stored, idToken, err := u.flow.complete(ctx, provider, req)
+	if err != nil {
+		return userdto.UserIdentityResponse{}, err
+	}
+	if !stored.IsLink() || *stored.UserID != id {
+		return userdto.UserIdentityResponse{}, ErrInvalidOIDCState
+	}
+
+	existing, err := u.identityRepository.GetByProviderSubject(ctx, provider, idToken.Subject)
+	if err == nil {
+		if existing.UserID != id {
+			return userdto.UserIdentityResponse{}, ErrIdentityAlreadyLinked
+		}
+		return newUserIdentityResponse(existing), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return userdto.UserIdentityResponse{}, err
+	}
+
+	identity, err := u.identityRepository.Create(ctx, &userentity.UserIdentity{
+		ID:        uuid.New(),
+		UserID:    id,
+		Provider:  provider,
+		Subject:   idToken.Subject,
+		Email:     idToken.Email,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, userrepo.ErrIdentityAlreadyLinked) {
+			return userdto.UserIdentityResponse{}, ErrIdentityAlreadyLinked
+		}
+		return userdto.UserIdentityResponse{}, err
+	}
+
+	return newUserIdentityResponse(identity), nil
+}
@@ -0,0 +1,383 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/oidc"
+	"roadmap/internal/pkg/oidc/oidctest"
+)
+
+type MockUserIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserIdentityRepository) Create(
+	ctx context.Context,
+	identity *userentity.UserIdentity,
+) (*userentity.UserIdentity, error) {
+	args := m.Called(ctx, identity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.UserIdentity), args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) CreateWithUser(
+	ctx context.Context,
+	user *userentity.User,
+	identity *userentity.UserIdentity,
+) (*userentity.User, *userentity.UserIdentity, error) {
+	args := m.Called(ctx, user, identity)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*userentity.User), args.Get(1).(*userentity.UserIdentity), args.Error(2)
+}
+
+func (m *MockUserIdentityRepository) GetByProviderSubject(
+	ctx context.Context,
+	provider, subject string,
+) (*userentity.UserIdentity, error) {
+	args := m.Called(ctx, provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.UserIdentity), args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*userentity.UserIdentity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*userentity.UserIdentity), args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, lastLoginAt time.Time) error {
+	args := m.Called(ctx, id, lastLoginAt)
+	return args.Error(0)
+}
+
+type MockOIDCAuthRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockOIDCAuthRequestRepository) Create(
+	ctx context.Context,
+	request *userentity.OIDCAuthRequest,
+) (*userentity.OIDCAuthRequest, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.OIDCAuthRequest), args.Error(1)
+}
+
+func (m *MockOIDCAuthRequestRepository) GetByStateHash(
+	ctx context.Context,
+	stateHash string,
+) (*userentity.OIDCAuthRequest, error) {
+	args := m.Called(ctx, stateHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.OIDCAuthRequest), args.Error(1)
+}
+
+func (m *MockOIDCAuthRequestRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+var errIdentityNotFound = fmt.Errorf("user identity not found: %w", pgx.ErrNoRows)
+
+type OIDCUseCaseTestSuite struct {
+	suite.Suite
+	server       *oidctest.Server
+	userRepo     *MockUserRepository
+	identityRepo *MockUserIdentityRepository
+	requestRepo  *MockOIDCAuthRequestRepository
+	refreshRepo  *MockRefreshTokenRepository
+	totpRepo     *MockTOTPRepository
+	mfaRepo      *MockMFAChallengeRepository
+	verifyRepo   *MockEmailVerificationTokenRepository
+	mailer       *MockMailer
+	beginLogin   *BeginOIDCLoginUseCase
+	finishLogin  *FinishOIDCLoginUseCase
+	beginLink    *BeginOIDCLinkUseCase
+	finishLink   *FinishOIDCLinkUseCase
+	user         *userentity.User
+	ctx          context.Context
+}
+
+func (s *OIDCUseCaseTestSuite) SetupTest() {
+	var err error
+	s.server, err = oidctest.NewServer("roadmap", "secret")
+	s.Require().NoError(err)
+
+	provider, err := oidc.NewProvider(oidc.Config{
+		Name:         "mock",
+		Issuer:       s.server.URL,
+		ClientID:     "roadmap",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:3000/auth/oidc/mock/callback",
+	})
+	s.Require().NoError(err)
+
+	s.userRepo = new(MockUserRepository)
+	s.identityRepo = new(MockUserIdentityRepository)
+	s.requestRepo = new(MockOIDCAuthRequestRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.totpRepo = new(MockTOTPRepository)
+	s.mfaRepo = new(MockMFAChallengeRepository)
+	s.verifyRepo = new(MockEmailVerificationTokenRepository)
+	s.mailer = new(MockMailer)
+	s.ctx = context.Background()
+
+	flow := NewOIDCFlow(NewOIDCProviders(provider), s.requestRepo, 10*time.Minute)
+	s.beginLogin = NewBeginOIDCLoginUseCase(flow)
+	s.finishLogin = NewFinishOIDCLoginUseCase(
+		s.userRepo,
+		s.identityRepo,
+		flow,
+		newTestTokenIssuer(s.refreshRepo),
+		NewTwoFactorChallenger(s.totpRepo, s.mfaRepo, 5*time.Minute),
+		NewEmailVerificationSender(s.verifyRepo, s.mailer, "https://app.example.com/verify-email", 24*time.Hour),
+	)
+	s.beginLink = NewBeginOIDCLinkUseCase(flow)
+	s.finishLink = NewFinishOIDCLinkUseCase(s.identityRepo, flow)
+
+	s.user = &userentity.User{
+		ID:       uuid.New(),
+		Username: "testuser",
+		Email:    "test@example.com",
+	}
+}
+
+func (s *OIDCUseCaseTestSuite) TearDownTest() {
+	s.server.Close()
+	s.userRepo.AssertExpectations(s.T())
+	s.identityRepo.AssertExpectations(s.T())
+	s.requestRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.verifyRepo.AssertExpectations(s.T())
+	s.mailer.AssertExpectations(s.T())
+}
+
+// authorize runs the redirect half of the flow against the mock provider
+// and returns the callback request, with the stored auth request set up to
+// be redeemed once.
+func (s *OIDCUseCaseTestSuite) authorize(begin func() (userdto.OIDCAuthorizationResponse, error)) userdto.OIDCCallbackRequest {
+	var stored *userentity.OIDCAuthRequest
+	s.requestRepo.On("Create", s.ctx, mock.AnythingOfType("*user.OIDCAuthRequest")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*userentity.OIDCAuthRequest) }).
+		Return(&userentity.OIDCAuthRequest{ExpiresAt: time.Now().Add(10 * time.Minute)}, nil).Once()
+
+	response, err := begin()
+	s.Require().NoError(err)
+
+	code, state, err := s.server.Authorize(response.AuthorizationURL)
+	s.Require().NoError(err)
+
+	s.requestRepo.On("GetByStateHash", s.ctx, hashOpaqueToken(state)).Return(stored, nil).Once()
+	s.requestRepo.On("MarkUsed", s.ctx, stored.ID).Return(nil).Once()
+
+	return userdto.OIDCCallbackRequest{Code: code, State: state}
+}
+
+func (s *OIDCUseCaseTestSuite) authorizeLogin() userdto.OIDCCallbackRequest {
+	return s.authorize(func() (userdto.OIDCAuthorizationResponse, error) {
+		return s.beginLogin.Execute(s.ctx, "mock")
+	})
+}
+
+func (s *OIDCUseCaseTestSuite) authorizeLink() userdto.OIDCCallbackRequest {
+	return s.authorize(func() (userdto.OIDCAuthorizationResponse, error) {
+		return s.beginLink.Execute(s.ctx, s.user.ID.String(), "mock")
+	})
+}
+
+func (s *OIDCUseCaseTestSuite) expectTokens() {
+	s.totpRepo.On("GetByUserID", s.ctx, mock.Anything).Return(nil, pgx.ErrNoRows)
+	s.refreshRepo.On("Create", s.ctx, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
+}
+
+func (s *OIDCUseCaseTestSuite) TestLogin_CreatesAccount() {
+	callback := s.authorizeLogin()
+
+	var created *userentity.User
+	var identity *userentity.UserIdentity
+	s.identityRepo.On("GetByProviderSubject", s.ctx, "mock", "oidctest-user").Return(nil, errIdentityNotFound)
+	s.userRepo.On("EmailExists", s.ctx, "oidc@example.com").Return(false, nil)
+	s.userRepo.On("UsernameExists", s.ctx, "oidc").Return(false, nil)
+	s.identityRepo.On("CreateWithUser", s.ctx, mock.AnythingOfType("*user.User"), mock.AnythingOfType("*user.UserIdentity")).
+		Run(func(args mock.Arguments) {
+			created = args.Get(1).(*userentity.User)
+			identity = args.Get(2).(*userentity.UserIdentity)
+		}).
+		Return(&userentity.User{ID: uuid.New(), Email: "oidc@example.com", EmailVerifiedAt: &time.Time{}}, &userentity.UserIdentity{}, nil)
+	s.expectTokens()
+
+	response, err := s.finishLogin.Execute(s.ctx, "mock", callback, userdto.ClientInfo{})
+
+	s.Require().NoError(err)
+	assert.NotEmpty(s.T(), response.Token)
+	assert.Equal(s.T(), "oidc", created.Username)
+	assert.Equal(s.T(), "OIDC User", created.DisplayName)
+	assert.Empty(s.T(), created.PasswordHash)
+	assert.True(s.T(), created.IsEmailVerified())
+	assert.Equal(s.T(), created.ID, identity.UserID)
+	assert.Equal(s.T(), "oidctest-user", identity.Subject)
+}
+
+func (s *OIDCUseCaseTestSuite) TestLogin_UnverifiedEmailGetsVerificationMail() {
+	s.server.SetIdentity(oidctest.Identity{Subject: "sub-2", Email: "new@example.com", PreferredUsername: "newbie"})
+	callback := s.authorizeLogin()
+
+	s.identityRepo.On("GetByProviderSubject", s.ctx, "mock", "sub-2").Return(nil, errIdentityNotFound)
+	s.userRepo.On("EmailExists", s.ctx, "new@example.com").Return(false, nil)
+	s.userRepo.On("UsernameExists", s.ctx, "newbie").Return(false, nil)
+	s.identityRepo.On("CreateWithUser", s.ctx, mock.MatchedBy(func(user *userentity.User) bool {
+		return user.EmailVerifiedAt == nil
+	}), mock.AnythingOfType("*user.UserIdentity")).
+		Return(&userentity.User{ID: uuid.New(), Email: "new@example.com"}, &userentity.UserIdentity{}, nil)
+	s.verifyRepo.On("Create", s.ctx, mock.AnythingOfType("*user.EmailVerificationToken")).
+		Return(&userentity.EmailVerificationToken{}, nil)
+	s.mailer.On("Send", s.ctx, mock.AnythingOfType("mailer.Message")).Return(nil)
+	s.expectTokens()
+
+	_, err := s.finishLogin.Execute(s.ctx, "mock", callback, userdto.ClientInfo{})
+
+	s.Require().NoError(err)
+}
+
+func (s *OIDCUseCaseTestSuite) TestLogin_TakenUsernameGetsSuffix() {
+	callback := s.authorizeLogin()
+
+	var created *userentity.User
+	s.identityRepo.On("GetByProviderSubject", s.ctx, "mock", "oidctest-user").Return(nil, errIdentityNotFound)
+	s.userRepo.On("EmailExists", s.ctx, "oidc@example.com").Return(false, nil)
+	s.userRepo.On("UsernameExists", s.ctx, "oidc").Return(true, nil)
+	s.userRepo.On("UsernameExists", s.ctx, mock.MatchedBy(func(username string) bool {
+		return strings.HasPrefix(username, "oidc-")
+	})).Return(false, nil)
+	s.identityRepo.On("CreateWithUser", s.ctx, mock.AnythingOfType("*user.User"), mock.AnythingOfType("*user.UserIdentity")).
+		Run(func(args mock.Arguments) { created = args.Get(1).(*userentity.User) }).
+		Return(&userentity.User{ID: uuid.New(), EmailVerifiedAt: &time.Time{}}, &userentity.UserIdentity{}, nil)
+	s.expectTokens()
+
+	_, err := s.finishLogin.Execute(s.ctx, "mock", callback, userdto.ClientInfo{})
+
+	s.Require().NoError(err)
+	assert.Regexp(s.T(), `^oidc-\d{4}$`, created.Username)
+}
+
+func (s *OIDCUseCaseTestSuite) TestLogin_ExistingIdentity() {
+	callback := s.authorizeLogin()
+
+	identity := &userentity.UserIdentity{ID: uuid.New(), UserID: s.user.ID, Provider: "mock", Subject: "oidctest-user"}
+	s.identityRepo.On("GetByProviderSubject", s.ctx, "mock", "oidctest-user").Return(identity, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.identityRepo.On("UpdateLastLogin", s.ctx, identity.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.expectTokens()
+
+	response, err := s.finishLogin.Execute(s.ctx, "mock", callback, userdto.ClientInfo{})
+
+	s.Require().NoError(err)
+	assert.NotEmpty(s.T(), response.RefreshToken)
+}
+
+func (s *OIDCUseCaseTestSuite) TestLogin_EmailOfExistingAccount() {
+	callback := s.authorizeLogin()
+
+	s.identityRepo.On("GetByProviderSubject", s.ctx, "mock", "oidctest-user").Return(nil, errIdentityNotFound)
+	s.userRepo.On("EmailExists", s.ctx, "oidc@example.com").Return(true, nil)
+
+	_, err := s.finishLogin.Execute(s.ctx, "mock", callback, userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrEmailAlreadyExists)
+}
+
+func (s *OIDCUseCaseTestSuite) TestLogin_UnknownProvider() {
+	_, err := s.beginLogin.Execute(s.ctx, "nope")
+	assert.ErrorIs(s.T(), err, ErrUnknownOIDCProvider)
+}
+
+func (s *OIDCUseCaseTestSuite) TestLogin_UsedState() {
+	s.requestRepo.On("GetByStateHash", s.ctx, hashOpaqueToken("state")).Return(&userentity.OIDCAuthRequest{
+		ID:        uuid.New(),
+		Provider:  "mock",
+		ExpiresAt: time.Now().Add(time.Minute),
+		UsedAt:    &time.Time{},
+	}, nil)
+
+	_, err := s.finishLogin.Execute(s.ctx, "mock", userdto.OIDCCallbackRequest{Code: "code", State: "state"}, userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidOIDCState)
+}
+
+func (s *OIDCUseCaseTestSuite) TestLogin_RejectsLinkState() {
+	callback := s.authorizeLink()
+
+	_, err := s.finishLogin.Execute(s.ctx, "mock", callback, userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidOIDCState)
+}
+
+func (s *OIDCUseCaseTestSuite) TestLogin_TamperedCode() {
+	callback := s.authorizeLogin()
+	callback.Code = "forged"
+
+	_, err := s.finishLogin.Execute(s.ctx, "mock", callback, userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrOIDCVerification)
+}
+
+func (s *OIDCUseCaseTestSuite) TestLink_CreatesIdentity() {
+	callback := s.authorizeLink()
+
+	s.identityRepo.On("GetByProviderSubject", s.ctx, "mock", "oidctest-user").Return(nil, errIdentityNotFound)
+	s.identityRepo.On("Create", s.ctx, mock.MatchedBy(func(identity *userentity.UserIdentity) bool {
+		return identity.UserID == s.user.ID && identity.Subject == "oidctest-user"
+	})).Return(&userentity.UserIdentity{ID: uuid.New(), Provider: "mock", Email: "oidc@example.com"}, nil)
+
+	response, err := s.finishLink.Execute(s.ctx, s.user.ID.String(), "mock", callback)
+
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "mock", response.Provider)
+}
+
+func (s *OIDCUseCaseTestSuite) TestLink_IdentityOfAnotherUser() {
+	callback := s.authorizeLink()
+
+	s.identityRepo.On("GetByProviderSubject", s.ctx, "mock", "oidctest-user").
+		Return(&userentity.UserIdentity{ID: uuid.New(), UserID: uuid.New()}, nil)
+
+	_, err := s.finishLink.Execute(s.ctx, s.user.ID.String(), "mock", callback)
+
+	assert.ErrorIs(s.T(), err, ErrIdentityAlreadyLinked)
+}
+
+func (s *OIDCUseCaseTestSuite) TestLink_StateOfAnotherUser() {
+	callback := s.authorizeLink()
+
+	_, err := s.finishLink.Execute(s.ctx, uuid.New().String(), "mock", callback)
+
+	assert.ErrorIs(s.T(), err, ErrInvalidOIDCState)
+}
+
+func TestOIDCUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCUseCaseTestSuite))
+}
@@ -0,0 +1,39 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+)
+
+const opaqueTokenBytes = 32
+
+func generateOpaqueToken() (string, error) {
+	buf := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenLink appends token to baseURL as the "token" query parameter.
+func tokenLink(baseURL, token string) (string, error) {
+	link, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid link URL %q: %w", baseURL, err)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String(), nil
+}
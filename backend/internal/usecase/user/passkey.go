@@ -0,0 +1,378 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/webauthn"
+	userrepo "roadmap/internal/repository/user"
+)
+
+const defaultPasskeyName = "Passkey"
+
+// PasskeyCeremony issues WebAuthn challenges and redeems them exactly once.
+// Challenges are looked up by the value the browser echoes back in
+// clientDataJSON, so clients need no separate session identifier.
+type PasskeyCeremony struct {
+	challengeRepository userrepo.WebAuthnChallengeRepository
+	webAuthn            *webauthn.WebAuthn
+}
+
+func NewPasskeyCeremony(
+	challengeRepository userrepo.WebAuthnChallengeRepository,
+	webAuthn *webauthn.WebAuthn,
+) *PasskeyCeremony {
+	return &PasskeyCeremony{
+		challengeRepository: challengeRepository,
+		webAuthn:            webAuthn,
+	}
+}
+
+func (c *PasskeyCeremony) start(ctx context.Context, userID *uuid.UUID, ceremony string) (string, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	_, err = c.challengeRepository.Create(ctx, &userentity.WebAuthnChallenge{
+		ID:            uuid.New(),
+		UserID:        userID,
+		ChallengeHash: hashOpaqueToken(challenge),
+		Ceremony:      ceremony,
+		ExpiresAt:     now.Add(c.webAuthn.Timeout()),
+		CreatedAt:     now,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return challenge, nil
+}
+
+func (c *PasskeyCeremony) redeem(
+	ctx context.Context,
+	clientDataJSON []byte,
+	ceremony string,
+) (*userentity.WebAuthnChallenge, string, error) {
+	challenge, err := webauthn.ChallengeFromClientData(clientDataJSON)
+	if err != nil {
+		return nil, "", ErrInvalidPasskeyChallenge
+	}
+
+	stored, err := c.challengeRepository.GetByHash(ctx, hashOpaqueToken(challenge))
+	if err != nil {
+		return nil, "", ErrInvalidPasskeyChallenge
+	}
+
+	if stored.Ceremony != ceremony || stored.IsUsed() || stored.IsExpired(time.Now()) {
+		return nil, "", ErrInvalidPasskeyChallenge
+	}
+
+	if err := c.challengeRepository.MarkUsed(ctx, stored.ID); err != nil {
+		if errors.Is(err, userrepo.ErrTokenAlreadyUsed) {
+			return nil, "", ErrInvalidPasskeyChallenge
+		}
+		return nil, "", err
+	}
+
+	return stored, challenge, nil
+}
+
+func newPasskeyResponse(passkey *userentity.Passkey) userdto.PasskeyResponse {
+	transports := passkey.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+
+	return userdto.PasskeyResponse{
+		ID:             passkey.ID,
+		Name:           passkey.Name,
+		Transports:     transports,
+		BackupEligible: passkey.BackupEligible,
+		LastUsedAt:     passkey.LastUsedAt,
+		CreatedAt:      passkey.CreatedAt,
+	}
+}
+
+type BeginPasskeyRegistrationUseCase struct {
+	userRepository    userrepo.UserRepository
+	passkeyRepository userrepo.PasskeyRepository
+	ceremony          *PasskeyCeremony
+}
+
+func NewBeginPasskeyRegistrationUseCase(
+	userRepository userrepo.UserRepository,
+	passkeyRepository userrepo.PasskeyRepository,
+	ceremony *PasskeyCeremony,
+) *BeginPasskeyRegistrationUseCase {
+	return &BeginPasskeyRegistrationUseCase{
+		userRepository:    userRepository,
+		passkeyRepository: passkeyRepository,
+		ceremony:          ceremony,
+	}
+}
+
+func (u *BeginPasskeyRegistrationUseCase) Execute(ctx context.Context, userID string) (webauthn.CreationOptions, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return webauthn.CreationOptions{}, ErrInvalidTokenClaims
+	}
+
+	user, err := u.userRepository.GetByID(ctx, id)
+	if err != nil {
+		return webauthn.CreationOptions{}, err
+	}
+
+	existing, err := u.passkeyRepository.ListByUser(ctx, user.ID)
+	if err != nil {
+		return webauthn.CreationOptions{}, err
+	}
+
+	exclude := make([]webauthn.CredentialDescriptor, 0, len(existing))
+	for _, passkey := range existing {
+		exclude = append(exclude, webauthn.NewCredentialDescriptor(passkey.CredentialID, passkey.Transports))
+	}
+
+	challenge, err := u.ceremony.start(ctx, &user.ID, userentity.CeremonyRegistration)
+	if err != nil {
+		return webauthn.CreationOptions{}, err
+	}
+
+	displayName := user.DisplayName
+	if displayName == "" {
+		displayName = user.Username
+	}
+
+	return u.ceremony.webAuthn.CreationOptions(webauthn.User{
+		ID:          user.ID[:],
+		Name:        user.Email,
+		DisplayName: displayName,
+	}, challenge, exclude), nil
+}
+
+type FinishPasskeyRegistrationUseCase struct {
+	passkeyRepository userrepo.PasskeyRepository
+	ceremony          *PasskeyCeremony
+}
+
+func NewFinishPasskeyRegistrationUseCase(
+	passkeyRepository userrepo.PasskeyRepository,
+	ceremony *PasskeyCeremony,
+) *FinishPasskeyRegistrationUseCase {
+	return &FinishPasskeyRegistrationUseCase{
+		passkeyRepository: passkeyRepository,
+		ceremony:          ceremony,
+	}
+}
+
+func (u *FinishPasskeyRegistrationUseCase) Execute(
+	ctx context.Context,
+	userID string,
+	req userdto.FinishPasskeyRegistrationRequest,
+) (userdto.PasskeyResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.PasskeyResponse{}, ErrInvalidTokenClaims
+	}
+
+	stored, challenge, err := u.ceremony.redeem(ctx, req.Credential.Response.ClientDataJSON, userentity.CeremonyRegistration)
+	if err != nil {
+		return userdto.PasskeyResponse{}, err
+	}
+	if stored.UserID == nil || *stored.UserID != id {
+		return userdto.PasskeyResponse{}, ErrInvalidPasskeyChallenge
+	}
+
+	credential, err := u.ceremony.webAuthn.VerifyRegistration(req.Credential, challenge)
+	if err != nil {
+		return userdto.PasskeyResponse{}, ErrPasskeyVerification
+	}
+
+	name := req.Name
+	if name == "" {
+		name = defaultPasskeyName
+	}
+
+	passkey, err := u.passkeyRepository.Create(ctx, &userentity.Passkey{
+		ID:             uuid.New(),
+		UserID:         id,
+		CredentialID:   credential.ID,
+		PublicKey:      credential.PublicKey,
+		SignCount:      credential.SignCount,
+		AAGUID:         credential.AAGUID,
+		Transports:     credential.Transports,
+		BackupEligible: credential.BackupEligible,
+		Name:           name,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, userrepo.ErrCredentialAlreadyRegistered) {
+			return userdto.PasskeyResponse{}, ErrPasskeyAlreadyRegistered
+		}
+		return userdto.PasskeyResponse{}, err
+	}
+
+	return newPasskeyResponse(passkey), nil
+}
+
+type BeginPasskeyLoginUseCase struct {
+	ceremony *PasskeyCeremony
+}
+
+func NewBeginPasskeyLoginUseCase(ceremony *PasskeyCeremony) *BeginPasskeyLoginUseCase {
+	return &BeginPasskeyLoginUseCase{
+		ceremony: ceremony,
+	}
+}
+
+// Execute starts a login with a discoverable credential: no account is named
+// up front, the authenticator picks the passkey and reports the user handle.
+func (u *BeginPasskeyLoginUseCase) Execute(ctx context.Context) (webauthn.RequestOptions, error) {
+	challenge, err := u.ceremony.start(ctx, nil, userentity.CeremonyAuthentication)
+	if err != nil {
+		return webauthn.RequestOptions{}, err
+	}
+
+	return u.ceremony.webAuthn.RequestOptions(challenge, []webauthn.CredentialDescriptor{}), nil
+}
+
+type FinishPasskeyLoginUseCase struct {
+	userRepository    userrepo.UserRepository
+	passkeyRepository userrepo.PasskeyRepository
+	ceremony          *PasskeyCeremony
+	tokenIssuer       *TokenIssuer
+}
+
+func NewFinishPasskeyLoginUseCase(
+	userRepository userrepo.UserRepository,
+	passkeyRepository userrepo.PasskeyRepository,
+	ceremony *PasskeyCeremony,
+	tokenIssuer *TokenIssuer,
+) *FinishPasskeyLoginUseCase {
+	return &FinishPasskeyLoginUseCase{
+		userRepository:    userRepository,
+		passkeyRepository: passkeyRepository,
+		ceremony:          ceremony,
+		tokenIssuer:       tokenIssuer,
+	}
+}
+
+// Execute verifies the assertion and issues the same tokens as a password
+// login. Passkeys require user verification, so they already satisfy a
+// second factor and skip the TOTP challenge.
+func (u *FinishPasskeyLoginUseCase) Execute(
+	ctx context.Context,
+	req webauthn.AssertionResponse,
+	client userdto.ClientInfo,
+) (userdto.LoginResponse, error) {
+	_, challenge, err := u.ceremony.redeem(ctx, req.Response.ClientDataJSON, userentity.CeremonyAuthentication)
+	if err != nil {
+		return userdto.LoginResponse{}, err
+	}
+
+	passkey, err := u.passkeyRepository.GetByCredentialID(ctx, req.RawID)
+	if err != nil {
+		return userdto.LoginResponse{}, ErrPasskeyVerification
+	}
+
+	if len(req.Response.UserHandle) > 0 && !bytes.Equal(req.Response.UserHandle, passkey.UserID[:]) {
+		return userdto.LoginResponse{}, ErrPasskeyVerification
+	}
+
+	signCount, err := u.ceremony.webAuthn.VerifyAssertion(req, challenge, passkey.PublicKey, passkey.SignCount)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCountRegression) {
+			log.Printf("Rejected passkey %s of user %s: sign count regressed", passkey.ID, passkey.UserID)
+			return userdto.LoginResponse{}, ErrPasskeyCloned
+		}
+		return userdto.LoginResponse{}, ErrPasskeyVerification
+	}
+
+	if err := u.passkeyRepository.UpdateSignCount(ctx, passkey.ID, signCount, time.Now()); err != nil {
+		return userdto.LoginResponse{}, err
+	}
+
+	user, err := u.userRepository.GetByID(ctx, passkey.UserID)
+	if err != nil {
+		return userdto.LoginResponse{}, err
+	}
+
+	tokens, err := u.tokenIssuer.Issue(ctx, user, client)
+	if err != nil {
+		return userdto.LoginResponse{}, err
+	}
+
+	return userdto.LoginResponse{
+		TokenPair: tokens,
+	}, nil
+}
+
+type ListPasskeysUseCase struct {
+	passkeyRepository userrepo.PasskeyRepository
+}
+
+func NewListPasskeysUseCase(passkeyRepository userrepo.PasskeyRepository) *ListPasskeysUseCase {
+	return &ListPasskeysUseCase{
+		passkeyRepository: passkeyRepository,
+	}
+}
+
+func (u *ListPasskeysUseCase) Execute(ctx context.Context, userID string) (userdto.ListPasskeysResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.ListPasskeysResponse{}, ErrInvalidTokenClaims
+	}
+
+	passkeys, err := u.passkeyRepository.ListByUser(ctx, id)
+	if err != nil {
+		return userdto.ListPasskeysResponse{}, err
+	}
+
+	response := userdto.ListPasskeysResponse{
+		Passkeys: make([]userdto.PasskeyResponse, 0, len(passkeys)),
+	}
+	for _, passkey := range passkeys {
+		response.Passkeys = append(response.Passkeys, newPasskeyResponse(passkey))
+	}
+
+	return response, nil
+}
+
+type DeletePasskeyUseCase struct {
+	passkeyRepository userrepo.PasskeyRepository
+}
+
+func NewDeletePasskeyUseCase(passkeyRepository userrepo.PasskeyRepository) *DeletePasskeyUseCase {
+	return &DeletePasskeyUseCase{
+		passkeyRepository: passkeyRepository,
+	}
+}
+
+func (u *DeletePasskeyUseCase) Execute(ctx context.Context, userID string, passkeyID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return ErrInvalidTokenClaims
+	}
+
+	keyID, err := uuid.Parse(passkeyID)
+	if err != nil {
+		return ErrPasskeyNotFound
+	}
+
+	if err := u.passkeyRepository.Delete(ctx, keyID, id); err != nil {
+		if errors.Is(err, userrepo.ErrPasskeyNotFound) {
+			return ErrPasskeyNotFound
+		}
+		return err
+	}
+
+	return nil
+}
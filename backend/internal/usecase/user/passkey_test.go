@@ -0,0 +1,343 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/webauthn"
+	"roadmap/internal/pkg/webauthn/webauthntest"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type MockPasskeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasskeyRepository) Create(ctx context.Context, passkey *userentity.Passkey) (*userentity.Passkey, error) {
+	args := m.Called(ctx, passkey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.Passkey), args.Error(1)
+}
+
+func (m *MockPasskeyRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*userentity.Passkey, error) {
+	args := m.Called(ctx, credentialID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.Passkey), args.Error(1)
+}
+
+func (m *MockPasskeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*userentity.Passkey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*userentity.Passkey), args.Error(1)
+}
+
+func (m *MockPasskeyRepository) UpdateSignCount(ctx context.Context, id uuid.UUID, signCount uint32, usedAt time.Time) error {
+	args := m.Called(ctx, id, signCount, usedAt)
+	return args.Error(0)
+}
+
+func (m *MockPasskeyRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+type MockWebAuthnChallengeRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebAuthnChallengeRepository) Create(
+	ctx context.Context,
+	challenge *userentity.WebAuthnChallenge,
+) (*userentity.WebAuthnChallenge, error) {
+	args := m.Called(ctx, challenge)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.WebAuthnChallenge), args.Error(1)
+}
+
+func (m *MockWebAuthnChallengeRepository) GetByHash(
+	ctx context.Context,
+	challengeHash string,
+) (*userentity.WebAuthnChallenge, error) {
+	args := m.Called(ctx, challengeHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.WebAuthnChallenge), args.Error(1)
+}
+
+func (m *MockWebAuthnChallengeRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+const (
+	testRPID   = "localhost"
+	testOrigin = "http://localhost:3000"
+)
+
+type PasskeyUseCaseTestSuite struct {
+	suite.Suite
+	userRepo      *MockUserRepository
+	passkeyRepo   *MockPasskeyRepository
+	challengeRepo *MockWebAuthnChallengeRepository
+	refreshRepo   *MockRefreshTokenRepository
+	ceremony      *PasskeyCeremony
+	authenticator *webauthntest.Authenticator
+	user          *userentity.User
+	ctx           context.Context
+}
+
+func (s *PasskeyUseCaseTestSuite) SetupTest() {
+	s.userRepo = new(MockUserRepository)
+	s.passkeyRepo = new(MockPasskeyRepository)
+	s.challengeRepo = new(MockWebAuthnChallengeRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.ctx = context.Background()
+
+	webAuthn, err := webauthn.New(webauthn.Config{RPID: testRPID, RPName: "Roadmap", Origins: []string{testOrigin}})
+	s.Require().NoError(err)
+	s.ceremony = NewPasskeyCeremony(s.challengeRepo, webAuthn)
+
+	s.user = &userentity.User{
+		ID:       uuid.New(),
+		Username: "testuser",
+		Email:    "test@example.com",
+	}
+
+	s.authenticator, err = webauthntest.NewAuthenticator(testRPID, testOrigin)
+	s.Require().NoError(err)
+	s.authenticator.UserHandle = s.user.ID[:]
+}
+
+func (s *PasskeyUseCaseTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.passkeyRepo.AssertExpectations(s.T())
+	s.challengeRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+}
+
+// pendingChallenge registers challenge as issued for ceremony and expects it
+// to be redeemed.
+func (s *PasskeyUseCaseTestSuite) pendingChallenge(challenge, ceremony string, userID *uuid.UUID) {
+	stored := &userentity.WebAuthnChallenge{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Ceremony:  ceremony,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	s.challengeRepo.On("GetByHash", s.ctx, hashOpaqueToken(challenge)).Return(stored, nil)
+	s.challengeRepo.On("MarkUsed", s.ctx, stored.ID).Return(nil)
+}
+
+// pendingChallengeWithoutRedeem registers a challenge that is rejected
+// before it is consumed.
+func (s *PasskeyUseCaseTestSuite) pendingChallengeWithoutRedeem(challenge, ceremony string) {
+	s.challengeRepo.On("GetByHash", s.ctx, hashOpaqueToken(challenge)).Return(&userentity.WebAuthnChallenge{
+		ID:        uuid.New(),
+		UserID:    &s.user.ID,
+		Ceremony:  ceremony,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil)
+}
+
+func (s *PasskeyUseCaseTestSuite) storedPasskey(signCount uint32) *userentity.Passkey {
+	return &userentity.Passkey{
+		ID:           uuid.New(),
+		UserID:       s.user.ID,
+		CredentialID: s.authenticator.CredentialID,
+		PublicKey:    s.authenticator.PublicKey(),
+		SignCount:    signCount,
+	}
+}
+
+func (s *PasskeyUseCaseTestSuite) TestBeginRegistration_ExcludesExistingCredentials() {
+	var stored *userentity.WebAuthnChallenge
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.passkeyRepo.On("ListByUser", s.ctx, s.user.ID).Return([]*userentity.Passkey{s.storedPasskey(0)}, nil)
+	s.challengeRepo.On("Create", s.ctx, mock.AnythingOfType("*user.WebAuthnChallenge")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*userentity.WebAuthnChallenge) }).
+		Return(&userentity.WebAuthnChallenge{}, nil)
+
+	options, err := NewBeginPasskeyRegistrationUseCase(s.userRepo, s.passkeyRepo, s.ceremony).
+		Execute(s.ctx, s.user.ID.String())
+
+	s.Require().NoError(err)
+	assert.Equal(s.T(), testRPID, options.PublicKey.RP.ID)
+	assert.Equal(s.T(), webauthn.Base64URL(s.user.ID[:]), options.PublicKey.User.ID)
+	assert.Equal(s.T(), "testuser", options.PublicKey.User.DisplayName)
+	assert.Len(s.T(), options.PublicKey.ExcludeCredentials, 1)
+	assert.Equal(s.T(), hashOpaqueToken(options.PublicKey.Challenge), stored.ChallengeHash)
+	assert.Equal(s.T(), userentity.CeremonyRegistration, stored.Ceremony)
+	assert.Equal(s.T(), s.user.ID, *stored.UserID)
+}
+
+func (s *PasskeyUseCaseTestSuite) TestFinishRegistration_StoresCredential() {
+	var created *userentity.Passkey
+	s.pendingChallenge("register-challenge", userentity.CeremonyRegistration, &s.user.ID)
+	s.passkeyRepo.On("Create", s.ctx, mock.AnythingOfType("*user.Passkey")).
+		Run(func(args mock.Arguments) { created = args.Get(1).(*userentity.Passkey) }).
+		Return(&userentity.Passkey{ID: uuid.New()}, nil)
+
+	response, err := NewFinishPasskeyRegistrationUseCase(s.passkeyRepo, s.ceremony).Execute(
+		s.ctx, s.user.ID.String(), userdto.FinishPasskeyRegistrationRequest{
+			Credential: s.authenticator.Register("register-challenge"),
+		},
+	)
+
+	s.Require().NoError(err)
+	assert.NotEqual(s.T(), uuid.Nil, response.ID)
+	assert.Equal(s.T(), "Passkey", created.Name)
+	assert.Equal(s.T(), s.authenticator.CredentialID, created.CredentialID)
+	assert.Equal(s.T(), s.authenticator.PublicKey(), created.PublicKey)
+	assert.Equal(s.T(), s.user.ID, created.UserID)
+}
+
+func (s *PasskeyUseCaseTestSuite) TestFinishRegistration_ChallengeOfAnotherUser() {
+	otherUser := uuid.New()
+	s.pendingChallenge("register-challenge", userentity.CeremonyRegistration, &otherUser)
+
+	_, err := NewFinishPasskeyRegistrationUseCase(s.passkeyRepo, s.ceremony).Execute(
+		s.ctx, s.user.ID.String(), userdto.FinishPasskeyRegistrationRequest{
+			Credential: s.authenticator.Register("register-challenge"),
+		},
+	)
+
+	assert.ErrorIs(s.T(), err, ErrInvalidPasskeyChallenge)
+}
+
+func (s *PasskeyUseCaseTestSuite) TestFinishRegistration_AlreadyRegistered() {
+	s.pendingChallenge("register-challenge", userentity.CeremonyRegistration, &s.user.ID)
+	s.passkeyRepo.On("Create", s.ctx, mock.Anything).Return(nil, userrepo.ErrCredentialAlreadyRegistered)
+
+	_, err := NewFinishPasskeyRegistrationUseCase(s.passkeyRepo, s.ceremony).Execute(
+		s.ctx, s.user.ID.String(), userdto.FinishPasskeyRegistrationRequest{
+			Credential: s.authenticator.Register("register-challenge"),
+		},
+	)
+
+	assert.ErrorIs(s.T(), err, ErrPasskeyAlreadyRegistered)
+}
+
+func (s *PasskeyUseCaseTestSuite) TestFinishRegistration_UsedChallenge() {
+	usedAt := time.Now()
+	s.challengeRepo.On("GetByHash", s.ctx, hashOpaqueToken("register-challenge")).Return(&userentity.WebAuthnChallenge{
+		ID:        uuid.New(),
+		UserID:    &s.user.ID,
+		Ceremony:  userentity.CeremonyRegistration,
+		ExpiresAt: time.Now().Add(time.Minute),
+		UsedAt:    &usedAt,
+	}, nil)
+
+	_, err := NewFinishPasskeyRegistrationUseCase(s.passkeyRepo, s.ceremony).Execute(
+		s.ctx, s.user.ID.String(), userdto.FinishPasskeyRegistrationRequest{
+			Credential: s.authenticator.Register("register-challenge"),
+		},
+	)
+
+	assert.ErrorIs(s.T(), err, ErrInvalidPasskeyChallenge)
+}
+
+func (s *PasskeyUseCaseTestSuite) TestLogin_IssuesTokens() {
+	s.challengeRepo.On("Create", s.ctx, mock.MatchedBy(func(c *userentity.WebAuthnChallenge) bool {
+		return c.UserID == nil && c.Ceremony == userentity.CeremonyAuthentication
+	})).Return(&userentity.WebAuthnChallenge{}, nil)
+
+	options, err := NewBeginPasskeyLoginUseCase(s.ceremony).Execute(s.ctx)
+	s.Require().NoError(err)
+	challenge := options.PublicKey.Challenge
+	assert.Empty(s.T(), options.PublicKey.AllowCredentials)
+
+	passkey := s.storedPasskey(0)
+	s.pendingChallenge(challenge, userentity.CeremonyAuthentication, nil)
+	s.passkeyRepo.On("GetByCredentialID", s.ctx, mock.Anything).Return(passkey, nil)
+	s.passkeyRepo.On("UpdateSignCount", s.ctx, passkey.ID, uint32(1), mock.AnythingOfType("time.Time")).Return(nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.refreshRepo.On("Create", s.ctx, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
+
+	response, err := NewFinishPasskeyLoginUseCase(s.userRepo, s.passkeyRepo, s.ceremony, newTestTokenIssuer(s.refreshRepo)).
+		Execute(s.ctx, s.authenticator.Assert(challenge), userdto.ClientInfo{})
+
+	s.Require().NoError(err)
+	assert.NotEmpty(s.T(), response.Token)
+	assert.NotEmpty(s.T(), response.RefreshToken)
+}
+
+func (s *PasskeyUseCaseTestSuite) TestLogin_ClonedAuthenticator() {
+	passkey := s.storedPasskey(5)
+	s.pendingChallenge("login-challenge", userentity.CeremonyAuthentication, nil)
+	s.passkeyRepo.On("GetByCredentialID", s.ctx, mock.Anything).Return(passkey, nil)
+
+	_, err := NewFinishPasskeyLoginUseCase(s.userRepo, s.passkeyRepo, s.ceremony, newTestTokenIssuer(s.refreshRepo)).
+		Execute(s.ctx, s.authenticator.Assert("login-challenge"), userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrPasskeyCloned)
+	s.passkeyRepo.AssertNotCalled(s.T(), "UpdateSignCount", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *PasskeyUseCaseTestSuite) TestLogin_UserHandleMismatch() {
+	passkey := s.storedPasskey(0)
+	passkey.UserID = uuid.New()
+	s.pendingChallenge("login-challenge", userentity.CeremonyAuthentication, nil)
+	s.passkeyRepo.On("GetByCredentialID", s.ctx, mock.Anything).Return(passkey, nil)
+
+	_, err := NewFinishPasskeyLoginUseCase(s.userRepo, s.passkeyRepo, s.ceremony, newTestTokenIssuer(s.refreshRepo)).
+		Execute(s.ctx, s.authenticator.Assert("login-challenge"), userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrPasskeyVerification)
+}
+
+func (s *PasskeyUseCaseTestSuite) TestLogin_RegistrationChallengeRejected() {
+	s.pendingChallengeWithoutRedeem("login-challenge", userentity.CeremonyRegistration)
+
+	_, err := NewFinishPasskeyLoginUseCase(s.userRepo, s.passkeyRepo, s.ceremony, newTestTokenIssuer(s.refreshRepo)).
+		Execute(s.ctx, s.authenticator.Assert("login-challenge"), userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidPasskeyChallenge)
+}
+
+func (s *PasskeyUseCaseTestSuite) TestList() {
+	lastUsed := time.Now()
+	passkey := s.storedPasskey(3)
+	passkey.Name = "Laptop"
+	passkey.LastUsedAt = &lastUsed
+	s.passkeyRepo.On("ListByUser", s.ctx, s.user.ID).Return([]*userentity.Passkey{passkey}, nil)
+
+	response, err := NewListPasskeysUseCase(s.passkeyRepo).Execute(s.ctx, s.user.ID.String())
+
+	s.Require().NoError(err)
+	s.Require().Len(response.Passkeys, 1)
+	assert.Equal(s.T(), passkey.ID, response.Passkeys[0].ID)
+	assert.Equal(s.T(), "Laptop", response.Passkeys[0].Name)
+	assert.Equal(s.T(), []string{}, response.Passkeys[0].Transports)
+}
+
+func (s *PasskeyUseCaseTestSuite) TestDelete() {
+	passkeyID := uuid.New()
+	s.passkeyRepo.On("Delete", s.ctx, passkeyID, s.user.ID).Return(userrepo.ErrPasskeyNotFound)
+
+	err := NewDeletePasskeyUseCase(s.passkeyRepo).Execute(s.ctx, s.user.ID.String(), passkeyID.String())
+	assert.ErrorIs(s.T(), err, ErrPasskeyNotFound)
+
+	err = NewDeletePasskeyUseCase(s.passkeyRepo).Execute(s.ctx, s.user.ID.String(), "not-a-uuid")
+	assert.ErrorIs(s.T(), err, ErrPasskeyNotFound)
+}
+
+func TestPasskeyUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(PasskeyUseCaseTestSuite))
+}
@@ -0,0 +1,47 @@
+package user
+
+import (
+	"context"
+	"log"
+
+	userentity "roadmap/internal/domain/entities/user"
+	userrepo "roadmap/internal/repository/user"
+)
+
+// PasswordHasher hashes new passwords and verifies them against stored
+// hashes, including hashes made by an algorithm or with parameters that are
+// no longer used for new ones. It is implemented by password.Hasher.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify reports whether password matches hash. It only fails when hash
+	// cannot be read.
+	Verify(password, hash string) (bool, error)
+	NeedsRehash(hash string) bool
+}
+
+// upgradePasswordHash replaces the user's password hash with one made by the
+// current algorithm and parameters once password is known to be correct.
+// Failures are only logged since the old hash keeps working.
+func upgradePasswordHash(
+	ctx context.Context,
+	userRepository userrepo.UserRepository,
+	passwordHasher PasswordHasher,
+	user *userentity.User,
+	password string,
+) {
+	if !passwordHasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	passwordHash, err := passwordHasher.Hash(password)
+	if err != nil {
+		log.Printf("Failed to rehash password of user %s: %v", user.ID, err)
+		return
+	}
+
+	if err := userRepository.UpdatePassword(ctx, user.ID, passwordHash); err != nil {
+		log.Printf("Failed to upgrade password hash of user %s: %v", user.ID, err)
+		return
+	}
+	user.PasswordHash = passwordHash
+}
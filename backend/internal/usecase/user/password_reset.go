@@ -0,0 +1,229 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/mailer"
+	userrepo "roadmap/internal/repository/user"
+)
+
+// PasswordResetSender issues password reset tokens and mails the links that
+// redeem them. Issuing a token invalidates the user's earlier ones. Delivery
+// failures are logged rather than returned: the caller must not be able to
+// tell them apart from success.
+type PasswordResetSender struct {
+	passwordResetRepository userrepo.PasswordResetTokenRepository
+	mailer                  mailer.Mailer
+	resetURL                string
+	tokenTTL                time.Duration
+}
+
+// NewPasswordResetSender creates a sender whose links point to resetURL with
+// the token appended as the "token" query parameter.
+func NewPasswordResetSender(
+	passwordResetRepository userrepo.PasswordResetTokenRepository,
+	mailer mailer.Mailer,
+	resetURL string,
+	tokenTTL time.Duration,
+) *PasswordResetSender {
+	return &PasswordResetSender{
+		passwordResetRepository: passwordResetRepository,
+		mailer:                  mailer,
+		resetURL:                resetURL,
+		tokenTTL:                tokenTTL,
+	}
+}
+
+// Send mails a reset link requested by the user.
+func (s *PasswordResetSender) Send(ctx context.Context, user *userentity.User) error {
+	return s.send(ctx, user, "Reset your password",
+		"Use the link below to choose a new password. It expires in %s.\n\n%s\n\n"+
+			"If you did not request a password reset, you can ignore this email.\n")
+}
+
+// SendForced mails a reset link after an administrator cleared the user's
+// password.
+func (s *PasswordResetSender) SendForced(ctx context.Context, user *userentity.User) error {
+	return s.send(ctx, user, "Your password has been reset",
+		"An administrator has reset the password of your account and signed you out everywhere. "+
+			"Use the link below to choose a new password. It expires in %s.\n\n%s\n")
+}
+
+func (s *PasswordResetSender) send(ctx context.Context, user *userentity.User, subject, body string) error {
+	if err := s.passwordResetRepository.InvalidateForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	rawToken, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	token := &userentity.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hashOpaqueToken(rawToken),
+		ExpiresAt: now.Add(s.tokenTTL),
+		CreatedAt: now,
+	}
+
+	if _, err := s.passwordResetRepository.Create(ctx, token); err != nil {
+		return err
+	}
+
+	link, err := tokenLink(s.resetURL, rawToken)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: subject,
+		Body:    fmt.Sprintf("Hi %s,\n\n", user.Username) + fmt.Sprintf(body, s.tokenTTL, link),
+	}
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("Failed to send password reset email to user %s: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// resetLinkSendTimeout bounds the background work of one forgot password
+// request, so a stalled database or mail server does not pile up goroutines.
+const resetLinkSendTimeout = time.Minute
+
+type ForgotPasswordUseCase struct {
+	userRepository userrepo.UserRepository
+	sender         *PasswordResetSender
+	pending        sync.WaitGroup
+}
+
+// NewForgotPasswordUseCase creates the use case that emails reset links
+// through sender.
+func NewForgotPasswordUseCase(
+	userRepository userrepo.UserRepository,
+	sender *PasswordResetSender,
+) *ForgotPasswordUseCase {
+	return &ForgotPasswordUseCase{
+		userRepository: userRepository,
+		sender:         sender,
+	}
+}
+
+// Execute sends a reset link if an account with the email exists. It reports
+// success for unknown emails as well so the endpoint cannot be used to find
+// out which emails are registered. The link is issued and mailed in the
+// background: waiting for the mail server would make known emails answer
+// measurably slower than unknown ones.
+func (u *ForgotPasswordUseCase) Execute(ctx context.Context, req userdto.ForgotPasswordRequest) error {
+	user, err := u.userRepository.GetByEmail(ctx, req.Email)
+	if err != nil {
+		return nil
+	}
+
+	u.pending.Add(1)
+	go func() {
+		defer u.pending.Done()
+
+		sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), resetLinkSendTimeout)
+		defer cancel()
+
+		if err := u.sender.Send(sendCtx, user); err != nil {
+			log.Printf("Failed to issue password reset token for user %s: %v", user.ID, err)
+		}
+	}()
+
+	return nil
+}
+
+// Wait blocks until the reset links of every earlier Execute call have been
+// sent.
+func (u *ForgotPasswordUseCase) Wait() {
+	u.pending.Wait()
+}
+
+type ResetPasswordUseCase struct {
+	userRepository          userrepo.UserRepository
+	passwordHasher          PasswordHasher
+	passwordValidator       *PasswordValidator
+	passwordResetRepository userrepo.PasswordResetTokenRepository
+	refreshTokenRepository  userrepo.RefreshTokenRepository
+	revocationService       *TokenRevocationService
+}
+
+func NewResetPasswordUseCase(
+	userRepository userrepo.UserRepository,
+	passwordHasher PasswordHasher,
+	passwordValidator *PasswordValidator,
+	passwordResetRepository userrepo.PasswordResetTokenRepository,
+	refreshTokenRepository userrepo.RefreshTokenRepository,
+	revocationService *TokenRevocationService,
+) *ResetPasswordUseCase {
+	return &ResetPasswordUseCase{
+		userRepository:          userRepository,
+		passwordHasher:          passwordHasher,
+		passwordValidator:       passwordValidator,
+		passwordResetRepository: passwordResetRepository,
+		refreshTokenRepository:  refreshTokenRepository,
+		revocationService:       revocationService,
+	}
+}
+
+// Execute sets a new password using a reset token. The token is consumed
+// before the password changes, and every existing session of the user is
+// revoked afterwards.
+func (u *ResetPasswordUseCase) Execute(ctx context.Context, req userdto.ResetPasswordRequest) error {
+	token, err := u.passwordResetRepository.GetByHash(ctx, hashOpaqueToken(req.Token))
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	if token.IsUsed() || token.IsExpired(time.Now()) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := u.userRepository.GetByID(ctx, token.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.passwordValidator.Validate(req.NewPassword, user.Email, user.Username); err != nil {
+		return err
+	}
+
+	passwordHash, err := u.passwordHasher.Hash(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := u.passwordResetRepository.MarkUsed(ctx, token.ID); err != nil {
+		if errors.Is(err, userrepo.ErrTokenAlreadyUsed) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if err := u.userRepository.UpdatePassword(ctx, token.UserID, passwordHash); err != nil {
+		return err
+	}
+
+	if err := u.passwordResetRepository.InvalidateForUser(ctx, token.UserID); err != nil {
+		return err
+	}
+
+	if err := u.revocationService.RevokeAllForUser(ctx, token.UserID); err != nil {
+		return err
+	}
+
+	return u.refreshTokenRepository.RevokeAllForUser(ctx, token.UserID)
+}
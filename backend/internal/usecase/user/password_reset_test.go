@@ -0,0 +1,275 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/mailer"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type MockPasswordResetTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetTokenRepository) Create(
+	ctx context.Context,
+	token *userentity.PasswordResetToken,
+) (*userentity.PasswordResetToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetTokenRepository) GetByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*userentity.PasswordResetToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(ctx context.Context, msg mailer.Message) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+type PasswordResetUseCaseTestSuite struct {
+	suite.Suite
+	forgotUseCase  *ForgotPasswordUseCase
+	resetUseCase   *ResetPasswordUseCase
+	userRepo       *MockUserRepository
+	resetRepo      *MockPasswordResetTokenRepository
+	refreshRepo    *MockRefreshTokenRepository
+	revocationRepo *MockTokenRevocationRepository
+	mailer         *MockMailer
+	user           *userentity.User
+	ctx            context.Context
+}
+
+func (s *PasswordResetUseCaseTestSuite) SetupTest() {
+	s.userRepo = new(MockUserRepository)
+	s.resetRepo = new(MockPasswordResetTokenRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.revocationRepo = new(MockTokenRevocationRepository)
+	s.mailer = new(MockMailer)
+	s.ctx = context.Background()
+
+	s.forgotUseCase = NewForgotPasswordUseCase(
+		s.userRepo, NewPasswordResetSender(s.resetRepo, s.mailer, "https://app.example.com/reset-password", time.Hour),
+	)
+	s.resetUseCase = NewResetPasswordUseCase(
+		s.userRepo, testPasswordHasher, testPasswordValidator, s.resetRepo, s.refreshRepo, NewTokenRevocationService(s.revocationRepo, time.Minute),
+	)
+
+	s.user = &userentity.User{
+		ID:       uuid.New(),
+		Username: "testuser",
+		Email:    "test@example.com",
+	}
+}
+
+func (s *PasswordResetUseCaseTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.resetRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.revocationRepo.AssertExpectations(s.T())
+	s.mailer.AssertExpectations(s.T())
+}
+
+func (s *PasswordResetUseCaseTestSuite) TestForgotPassword_SendsLink() {
+	var stored *userentity.PasswordResetToken
+	var sent mailer.Message
+
+	s.userRepo.On("GetByEmail", s.ctx, s.user.Email).Return(s.user, nil)
+	s.resetRepo.On("InvalidateForUser", mock.Anything, s.user.ID).Return(nil)
+	s.resetRepo.On("Create", mock.Anything, mock.AnythingOfType("*user.PasswordResetToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*userentity.PasswordResetToken) }).
+		Return(&userentity.PasswordResetToken{}, nil)
+	s.mailer.On("Send", mock.Anything, mock.AnythingOfType("mailer.Message")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(mailer.Message) }).
+		Return(nil)
+
+	err := s.forgotUseCase.Execute(s.ctx, userdto.ForgotPasswordRequest{Email: s.user.Email})
+	s.forgotUseCase.Wait()
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), s.user.ID, stored.UserID)
+	assert.WithinDuration(s.T(), time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+	assert.Equal(s.T(), s.user.Email, sent.To)
+
+	const prefix = "https://app.example.com/reset-password?token="
+	start := strings.Index(sent.Body, prefix)
+	assert.GreaterOrEqual(s.T(), start, 0)
+	rawToken := strings.Fields(sent.Body[start+len(prefix):])[0]
+	assert.Equal(s.T(), stored.TokenHash, hashOpaqueToken(rawToken))
+}
+
+func (s *PasswordResetUseCaseTestSuite) TestForgotPassword_UnknownEmail() {
+	s.userRepo.On("GetByEmail", s.ctx, "unknown@example.com").Return(nil, errors.New("user not found"))
+
+	err := s.forgotUseCase.Execute(s.ctx, userdto.ForgotPasswordRequest{Email: "unknown@example.com"})
+	s.forgotUseCase.Wait()
+
+	assert.NoError(s.T(), err)
+	s.resetRepo.AssertNotCalled(s.T(), "Create", mock.Anything, mock.Anything)
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything, mock.Anything)
+}
+
+func (s *PasswordResetUseCaseTestSuite) TestForgotPassword_MailerErrorIsHidden() {
+	s.userRepo.On("GetByEmail", s.ctx, s.user.Email).Return(s.user, nil)
+	s.resetRepo.On("InvalidateForUser", mock.Anything, s.user.ID).Return(nil)
+	s.resetRepo.On("Create", mock.Anything, mock.Anything).Return(&userentity.PasswordResetToken{}, nil)
+	s.mailer.On("Send", mock.Anything, mock.Anything).Return(errors.New("smtp unavailable"))
+
+	err := s.forgotUseCase.Execute(s.ctx, userdto.ForgotPasswordRequest{Email: s.user.Email})
+	s.forgotUseCase.Wait()
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *PasswordResetUseCaseTestSuite) TestForgotPassword_DoesNotWaitForMailer() {
+	release := make(chan struct{})
+	ctx, cancel := context.WithCancel(s.ctx)
+
+	s.userRepo.On("GetByEmail", ctx, s.user.Email).Return(s.user, nil)
+	s.resetRepo.On("InvalidateForUser", mock.Anything, s.user.ID).Return(nil)
+	s.resetRepo.On("Create", mock.Anything, mock.Anything).Return(&userentity.PasswordResetToken{}, nil)
+	s.mailer.On("Send", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			<-release
+			// The request has finished by now; its cancellation must not
+			// abort the delivery.
+			assert.NoError(s.T(), args.Get(0).(context.Context).Err())
+		}).
+		Return(nil)
+
+	err := s.forgotUseCase.Execute(ctx, userdto.ForgotPasswordRequest{Email: s.user.Email})
+	cancel()
+
+	assert.NoError(s.T(), err)
+	close(release)
+	s.forgotUseCase.Wait()
+}
+
+func (s *PasswordResetUseCaseTestSuite) validToken() *userentity.PasswordResetToken {
+	return &userentity.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    s.user.ID,
+		TokenHash: hashOpaqueToken("reset-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func (s *PasswordResetUseCaseTestSuite) TestResetPassword_Success() {
+	token := s.validToken()
+	s.resetRepo.On("GetByHash", s.ctx, token.TokenHash).Return(token, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.resetRepo.On("MarkUsed", s.ctx, token.ID).Return(nil)
+	s.userRepo.On("UpdatePassword", s.ctx, s.user.ID, mock.AnythingOfType("string")).Return(nil)
+	s.resetRepo.On("InvalidateForUser", s.ctx, s.user.ID).Return(nil)
+	s.revocationRepo.On("SetTokensValidAfter", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.refreshRepo.On("RevokeAllForUser", s.ctx, s.user.ID).Return(nil)
+
+	err := s.resetUseCase.Execute(s.ctx, userdto.ResetPasswordRequest{
+		Token:       "reset-token",
+		NewPassword: "NewPassword123!",
+	})
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *PasswordResetUseCaseTestSuite) TestResetPassword_UnknownToken() {
+	s.resetRepo.On("GetByHash", s.ctx, hashOpaqueToken("bogus")).Return(nil, errors.New("not found"))
+
+	err := s.resetUseCase.Execute(s.ctx, userdto.ResetPasswordRequest{Token: "bogus", NewPassword: "NewPassword123!"})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidResetToken)
+}
+
+func (s *PasswordResetUseCaseTestSuite) TestResetPassword_ExpiredToken() {
+	token := s.validToken()
+	token.ExpiresAt = time.Now().Add(-time.Minute)
+	s.resetRepo.On("GetByHash", s.ctx, token.TokenHash).Return(token, nil)
+
+	err := s.resetUseCase.Execute(s.ctx, userdto.ResetPasswordRequest{
+		Token:       "reset-token",
+		NewPassword: "NewPassword123!",
+	})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidResetToken)
+}
+
+func (s *PasswordResetUseCaseTestSuite) TestResetPassword_UsedToken() {
+	token := s.validToken()
+	usedAt := time.Now()
+	token.UsedAt = &usedAt
+	s.resetRepo.On("GetByHash", s.ctx, token.TokenHash).Return(token, nil)
+
+	err := s.resetUseCase.Execute(s.ctx, userdto.ResetPasswordRequest{
+		Token:       "reset-token",
+		NewPassword: "NewPassword123!",
+	})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidResetToken)
+}
+
+func (s *PasswordResetUseCaseTestSuite) TestResetPassword_ConcurrentRedemption() {
+	token := s.validToken()
+	s.resetRepo.On("GetByHash", s.ctx, token.TokenHash).Return(token, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.resetRepo.On("MarkUsed", s.ctx, token.ID).Return(userrepo.ErrTokenAlreadyUsed)
+
+	err := s.resetUseCase.Execute(s.ctx, userdto.ResetPasswordRequest{
+		Token:       "reset-token",
+		NewPassword: "NewPassword123!",
+	})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidResetToken)
+	s.userRepo.AssertNotCalled(s.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *PasswordResetUseCaseTestSuite) TestResetPassword_WeakPassword() {
+	token := s.validToken()
+	s.resetRepo.On("GetByHash", s.ctx, token.TokenHash).Return(token, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+
+	err := s.resetUseCase.Execute(s.ctx, userdto.ResetPasswordRequest{Token: "reset-token", NewPassword: "weak"})
+
+	var passwordErr *PasswordValidationError
+	assert.ErrorAs(s.T(), err, &passwordErr)
+	s.resetRepo.AssertNotCalled(s.T(), "MarkUsed", mock.Anything, mock.Anything)
+}
+
+func TestPasswordResetUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordResetUseCaseTestSuite))
+}
@@ -4,14 +4,125 @@ import (
 	"fmt"
 	"strings"
 	"unicode"
+
+	"roadmap/internal/pkg/password"
+)
+
+// Reasons a password can be rejected for.
+const (
+	PasswordReasonTooShort          = "too_short"
+	PasswordReasonMissingCharacters = "missing_characters"
+	PasswordReasonCommon            = "common"
+	PasswordReasonContainsUserInfo  = "contains_user_info"
+	PasswordReasonTooWeak           = "too_weak"
+	PasswordReasonBreached          = "breached"
 )
 
+// minUserInfoLength keeps very short usernames from ruling out every
+// password that happens to contain them.
+const minUserInfoLength = 3
+
+// BreachedPasswordChecker looks passwords up in a corpus of passwords
+// exposed in data breaches. It is implemented by password.BreachedFile.
+type BreachedPasswordChecker interface {
+	Contains(password string) (bool, error)
+}
+
+// PasswordPolicy selects the checks new passwords have to pass on top of
+// the length and character class rules.
+type PasswordPolicy struct {
+	RejectCommon   bool
+	RejectUserInfo bool
+	// MinStrength is the lowest acceptable password.Strength score. Zero
+	// accepts any.
+	MinStrength int
+	// Breached may be nil to skip the breach lookup.
+	Breached BreachedPasswordChecker
+}
+
+var DefaultPasswordPolicy = PasswordPolicy{
+	RejectCommon:   true,
+	RejectUserInfo: true,
+	MinStrength:    password.StrengthStrong,
+}
+
+// PasswordValidator checks new passwords against a PasswordPolicy.
+type PasswordValidator struct {
+	policy PasswordPolicy
+}
+
+func NewPasswordValidator(policy PasswordPolicy) *PasswordValidator {
+	return &PasswordValidator{
+		policy: policy,
+	}
+}
+
+// Validate checks newPassword for the account with the given email and
+// username. Passwords breaking the length or character class rules are
+// rejected for that alone; otherwise every failed check of the policy is
+// reported.
+func (v *PasswordValidator) Validate(newPassword, email, username string) error {
+	if err := validatePassword(newPassword); err != nil {
+		return err
+	}
+
+	var messages, reasons []string
+	reject := func(reason, message string) {
+		reasons = append(reasons, reason)
+		messages = append(messages, message)
+	}
+
+	if v.policy.RejectCommon && password.IsCommon(newPassword) {
+		reject(PasswordReasonCommon, "password is too common")
+	}
+
+	if v.policy.RejectUserInfo && containsUserInfo(newPassword, email, username) {
+		reject(PasswordReasonContainsUserInfo, "password must not contain your email address or username")
+	}
+
+	if v.policy.MinStrength > 0 && password.Strength(newPassword, email, username) < v.policy.MinStrength {
+		reject(PasswordReasonTooWeak, "password is too easy to guess")
+	}
+
+	if v.policy.Breached != nil {
+		breached, err := v.policy.Breached.Contains(newPassword)
+		if err != nil {
+			return err
+		}
+		if breached {
+			reject(PasswordReasonBreached, "password has appeared in a data breach")
+		}
+	}
+
+	if len(reasons) > 0 {
+		return &PasswordValidationError{
+			Message: strings.Join(messages, "; "),
+			Reasons: reasons,
+		}
+	}
+	return nil
+}
+
+func containsUserInfo(newPassword, email, username string) bool {
+	lower := strings.ToLower(newPassword)
+
+	localPart, _, _ := strings.Cut(email, "@")
+	for _, info := range []string{email, localPart, username} {
+		info = strings.ToLower(strings.TrimSpace(info))
+		if len(info) >= minUserInfoLength && strings.Contains(lower, info) {
+			return true
+		}
+	}
+	return false
+}
+
 func validatePassword(password string) error {
 	const minLength = 8
 
 	if len(password) < minLength {
 		return &PasswordValidationError{
 			Message: fmt.Sprintf("password must be at least %d characters long", minLength),
+			Reasons: []string{PasswordReasonTooShort},
 		}
 	}
 
@@ -52,6 +163,7 @@ func validatePassword(password string) error {
 	if len(missing) > 0 {
 		return &PasswordValidationError{
 			Message: fmt.Sprintf("password must contain at least one: %s", strings.Join(missing, ", ")),
+			Reasons: []string{PasswordReasonMissingCharacters},
 		}
 	}
 
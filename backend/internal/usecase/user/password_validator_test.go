@@ -0,0 +1,107 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubBreachedChecker struct {
+	breached map[string]bool
+	err      error
+}
+
+func (c stubBreachedChecker) Contains(password string) (bool, error) {
+	return c.breached[password], c.err
+}
+
+func TestPasswordValidator_DefaultPolicy(t *testing.T) {
+	validator := NewPasswordValidator(DefaultPasswordPolicy)
+
+	err := validator.Validate("Password1!", "jane@example.com", "jane")
+
+	var passwordErr *PasswordValidationError
+	require.ErrorAs(t, err, &passwordErr)
+	assert.Equal(t, []string{PasswordReasonCommon, PasswordReasonTooWeak}, passwordErr.Reasons)
+	assert.Equal(t, "password is too common; password is too easy to guess", passwordErr.Error())
+
+	assert.NoError(t, validator.Validate("Xk9#mQ2$vL7!", "jane@example.com", "jane"))
+}
+
+func TestPasswordValidator_CompositionRulesComeFirst(t *testing.T) {
+	validator := NewPasswordValidator(DefaultPasswordPolicy)
+
+	err := validator.Validate("password", "jane@example.com", "jane")
+
+	var passwordErr *PasswordValidationError
+	require.ErrorAs(t, err, &passwordErr)
+	assert.Equal(t, []string{PasswordReasonMissingCharacters}, passwordErr.Reasons)
+}
+
+func TestPasswordValidator_UserInfo(t *testing.T) {
+	validator := NewPasswordValidator(PasswordPolicy{RejectUserInfo: true})
+
+	testCases := []struct {
+		name     string
+		password string
+		rejected bool
+	}{
+		{"username", "Xk9#Jane2024!", true},
+		{"email local part", "Jane.Doe#9xQ", true},
+		{"full email", "jane.doe@example.com1A", true},
+		{"unrelated", "Xk9#mQ2$vL7!", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validator.Validate(tc.password, "jane.doe@example.com", "jane")
+
+			if !tc.rejected {
+				assert.NoError(t, err)
+				return
+			}
+			var passwordErr *PasswordValidationError
+			require.ErrorAs(t, err, &passwordErr)
+			assert.Equal(t, []string{PasswordReasonContainsUserInfo}, passwordErr.Reasons)
+		})
+	}
+}
+
+func TestPasswordValidator_ShortUsernameIsIgnored(t *testing.T) {
+	validator := NewPasswordValidator(PasswordPolicy{RejectUserInfo: true})
+
+	assert.NoError(t, validator.Validate("Xk9#mQ2$vL7!", "x@example.com", "mq"))
+}
+
+func TestPasswordValidator_Breached(t *testing.T) {
+	validator := NewPasswordValidator(PasswordPolicy{
+		Breached: stubBreachedChecker{breached: map[string]bool{"Tr0ub4dor&3": true}},
+	})
+
+	err := validator.Validate("Tr0ub4dor&3", "jane@example.com", "jane")
+
+	var passwordErr *PasswordValidationError
+	require.ErrorAs(t, err, &passwordErr)
+	assert.Equal(t, []string{PasswordReasonBreached}, passwordErr.Reasons)
+
+	assert.NoError(t, validator.Validate("Xk9#mQ2$vL7!", "jane@example.com", "jane"))
+}
+
+func TestPasswordValidator_BreachedCheckerError(t *testing.T) {
+	checkErr := errors.New("read failed")
+	validator := NewPasswordValidator(PasswordPolicy{Breached: stubBreachedChecker{err: checkErr}})
+
+	err := validator.Validate("Xk9#mQ2$vL7!", "jane@example.com", "jane")
+
+	assert.ErrorIs(t, err, checkErr)
+	var passwordErr *PasswordValidationError
+	assert.False(t, errors.As(err, &passwordErr))
+}
+
+func TestPasswordValidator_ZeroPolicy(t *testing.T) {
+	validator := NewPasswordValidator(PasswordPolicy{})
+
+	assert.NoError(t, validator.Validate("Pass123!", "pass@example.com", "pass"))
+}
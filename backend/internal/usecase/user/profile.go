@@ -0,0 +1,102 @@
+package user
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	userrepo "roadmap/internal/repository/user"
+)
+
+func newProfileResponse(user *userentity.User) userdto.ProfileResponse {
+	return userdto.ProfileResponse{
+		UserID:        user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		DisplayName:   user.DisplayName,
+		Bio:           user.Bio,
+		EmailVerified: user.IsEmailVerified(),
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+type GetProfileUseCase struct {
+	userRepository userrepo.UserRepository
+}
+
+func NewGetProfileUseCase(userRepository userrepo.UserRepository) *GetProfileUseCase {
+	return &GetProfileUseCase{
+		userRepository: userRepository,
+	}
+}
+
+func (u *GetProfileUseCase) Execute(ctx context.Context, userID string) (userdto.ProfileResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.ProfileResponse{}, ErrInvalidTokenClaims
+	}
+
+	user, err := u.userRepository.GetByID(ctx, id)
+	if err != nil {
+		return userdto.ProfileResponse{}, err
+	}
+
+	return newProfileResponse(user), nil
+}
+
+type UpdateProfileUseCase struct {
+	userRepository userrepo.UserRepository
+}
+
+func NewUpdateProfileUseCase(userRepository userrepo.UserRepository) *UpdateProfileUseCase {
+	return &UpdateProfileUseCase{
+		userRepository: userRepository,
+	}
+}
+
+func (u *UpdateProfileUseCase) Execute(
+	ctx context.Context,
+	userID string,
+	req userdto.UpdateProfileRequest,
+) (userdto.ProfileResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.ProfileResponse{}, ErrInvalidTokenClaims
+	}
+
+	user, err := u.userRepository.GetByID(ctx, id)
+	if err != nil {
+		return userdto.ProfileResponse{}, err
+	}
+
+	if req.Username != nil {
+		username := strings.TrimSpace(*req.Username)
+		if username != user.Username {
+			usernameExists, err := u.userRepository.UsernameExists(ctx, username)
+			if err != nil {
+				return userdto.ProfileResponse{}, err
+			}
+			if usernameExists {
+				return userdto.ProfileResponse{}, ErrUsernameAlreadyExists
+			}
+			user.Username = username
+		}
+	}
+	if req.DisplayName != nil {
+		user.DisplayName = strings.TrimSpace(*req.DisplayName)
+	}
+	if req.Bio != nil {
+		user.Bio = strings.TrimSpace(*req.Bio)
+	}
+
+	updatedUser, err := u.userRepository.UpdateProfile(ctx, user)
+	if err != nil {
+		return userdto.ProfileResponse{}, err
+	}
+
+	return newProfileResponse(updatedUser), nil
+}
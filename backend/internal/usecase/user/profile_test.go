@@ -0,0 +1,119 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+)
+
+type ProfileUseCaseTestSuite struct {
+	suite.Suite
+	getUseCase    *GetProfileUseCase
+	updateUseCase *UpdateProfileUseCase
+	mockRepo      *MockUserRepository
+	user          *userentity.User
+	ctx           context.Context
+}
+
+func (s *ProfileUseCaseTestSuite) SetupTest() {
+	s.mockRepo = new(MockUserRepository)
+	s.getUseCase = NewGetProfileUseCase(s.mockRepo)
+	s.updateUseCase = NewUpdateProfileUseCase(s.mockRepo)
+	s.ctx = context.Background()
+
+	now := time.Now()
+	s.user = &userentity.User{
+		ID:          uuid.New(),
+		Username:    "testuser",
+		Email:       "test@example.com",
+		DisplayName: "Test User",
+		Bio:         "Hello",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+func (s *ProfileUseCaseTestSuite) TearDownTest() {
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+func (s *ProfileUseCaseTestSuite) TestGetProfile_LoadsFreshData() {
+	s.mockRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+
+	response, err := s.getUseCase.Execute(s.ctx, s.user.ID.String())
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), s.user.ID, response.UserID)
+	assert.Equal(s.T(), s.user.Username, response.Username)
+	assert.Equal(s.T(), s.user.DisplayName, response.DisplayName)
+	assert.Equal(s.T(), s.user.Bio, response.Bio)
+	assert.False(s.T(), response.EmailVerified)
+}
+
+func (s *ProfileUseCaseTestSuite) TestGetProfile_InvalidUserID() {
+	_, err := s.getUseCase.Execute(s.ctx, "not-a-uuid")
+
+	assert.ErrorIs(s.T(), err, ErrInvalidTokenClaims)
+}
+
+func (s *ProfileUseCaseTestSuite) TestUpdateProfile_PartialUpdate() {
+	displayName := "  New Name "
+	s.mockRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.mockRepo.On("UpdateProfile", s.ctx, mock.MatchedBy(func(user *userentity.User) bool {
+		return user.Username == "testuser" && user.DisplayName == "New Name" && user.Bio == "Hello"
+	})).Return(s.user, nil)
+
+	response, err := s.updateUseCase.Execute(s.ctx, s.user.ID.String(), userdto.UpdateProfileRequest{
+		DisplayName: &displayName,
+	})
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "New Name", response.DisplayName)
+	s.mockRepo.AssertNotCalled(s.T(), "UsernameExists", mock.Anything, mock.Anything)
+}
+
+func (s *ProfileUseCaseTestSuite) TestUpdateProfile_SameUsernameSkipsUniquenessCheck() {
+	username := "testuser"
+	s.mockRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.mockRepo.On("UpdateProfile", s.ctx, s.user).Return(s.user, nil)
+
+	_, err := s.updateUseCase.Execute(s.ctx, s.user.ID.String(), userdto.UpdateProfileRequest{Username: &username})
+
+	assert.NoError(s.T(), err)
+	s.mockRepo.AssertNotCalled(s.T(), "UsernameExists", mock.Anything, mock.Anything)
+}
+
+func (s *ProfileUseCaseTestSuite) TestUpdateProfile_UsernameTaken() {
+	username := "taken"
+	s.mockRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.mockRepo.On("UsernameExists", s.ctx, "taken").Return(true, nil)
+
+	_, err := s.updateUseCase.Execute(s.ctx, s.user.ID.String(), userdto.UpdateProfileRequest{Username: &username})
+
+	assert.ErrorIs(s.T(), err, ErrUsernameAlreadyExists)
+	s.mockRepo.AssertNotCalled(s.T(), "UpdateProfile", mock.Anything, mock.Anything)
+}
+
+func (s *ProfileUseCaseTestSuite) TestUpdateProfile_RepositoryError() {
+	repoError := errors.New("database error")
+	bio := "bio"
+	s.mockRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.mockRepo.On("UpdateProfile", s.ctx, s.user).Return(nil, repoError)
+
+	_, err := s.updateUseCase.Execute(s.ctx, s.user.ID.String(), userdto.UpdateProfileRequest{Bio: &bio})
+
+	assert.Equal(s.T(), repoError, err)
+}
+
+func TestProfileUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ProfileUseCaseTestSuite))
+}
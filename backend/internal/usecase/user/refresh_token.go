@@ -0,0 +1,74 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type RefreshTokenUseCase struct {
+	userRepository         userrepo.UserRepository
+	refreshTokenRepository userrepo.RefreshTokenRepository
+	tokenIssuer            *TokenIssuer
+}
+
+func NewRefreshTokenUseCase(
+	userRepository userrepo.UserRepository,
+	refreshTokenRepository userrepo.RefreshTokenRepository,
+	tokenIssuer *TokenIssuer,
+) *RefreshTokenUseCase {
+	return &RefreshTokenUseCase{
+		userRepository:         userRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		tokenIssuer:            tokenIssuer,
+	}
+}
+
+func (u *RefreshTokenUseCase) Execute(
+	ctx context.Context,
+	req userdto.RefreshTokenRequest,
+) (userdto.RefreshTokenResponse, error) {
+	current, err := u.refreshTokenRepository.GetByHash(ctx, hashOpaqueToken(req.RefreshToken))
+	if err != nil {
+		return userdto.RefreshTokenResponse{}, ErrInvalidRefreshToken
+	}
+
+	if current.IsRevoked() {
+		return userdto.RefreshTokenResponse{}, u.revokeFamily(ctx, current.FamilyID)
+	}
+
+	if current.IsExpired(time.Now()) {
+		return userdto.RefreshTokenResponse{}, ErrInvalidRefreshToken
+	}
+
+	user, err := u.userRepository.GetByID(ctx, current.UserID)
+	if err != nil {
+		return userdto.RefreshTokenResponse{}, ErrInvalidRefreshToken
+	}
+
+	tokens, err := u.tokenIssuer.Rotate(ctx, user, current)
+	if err != nil {
+		if errors.Is(err, userrepo.ErrRefreshTokenRevoked) {
+			return userdto.RefreshTokenResponse{}, u.revokeFamily(ctx, current.FamilyID)
+		}
+		return userdto.RefreshTokenResponse{}, err
+	}
+
+	return userdto.RefreshTokenResponse{TokenPair: tokens}, nil
+}
+
+// revokeFamily is called when a refresh token that has already been rotated is
+// presented again. Either the legitimate client or an attacker holds a stolen
+// copy, so every token descending from the same login is revoked.
+func (u *RefreshTokenUseCase) revokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	if err := u.refreshTokenRepository.RevokeFamily(ctx, familyID); err != nil {
+		return fmt.Errorf("%w: %v", ErrRefreshTokenReused, err)
+	}
+	return ErrRefreshTokenReused
+}
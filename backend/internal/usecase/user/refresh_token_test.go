@@ -0,0 +1,224 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(
+	ctx context.Context,
+	token *userentity.RefreshToken,
+) (*userentity.RefreshToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*userentity.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Rotate(
+	ctx context.Context,
+	oldID uuid.UUID,
+	replacement *userentity.RefreshToken,
+) (*userentity.RefreshToken, error) {
+	args := m.Called(ctx, oldID, replacement)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func newTestTokenIssuer(refreshRepo userrepo.RefreshTokenRepository) *TokenIssuer {
+	return NewTokenIssuer(
+		jwtservice.NewJWTService("test-secret-key", 15*time.Minute),
+		refreshRepo,
+		nil,
+		30*24*time.Hour,
+	)
+}
+
+type RefreshTokenUseCaseTestSuite struct {
+	suite.Suite
+	useCase     *RefreshTokenUseCase
+	mockRepo    *MockUserRepository
+	refreshRepo *MockRefreshTokenRepository
+	validUser   *userentity.User
+	current     *userentity.RefreshToken
+	rawToken    string
+	ctx         context.Context
+}
+
+func (s *RefreshTokenUseCaseTestSuite) SetupTest() {
+	s.mockRepo = new(MockUserRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.useCase = NewRefreshTokenUseCase(s.mockRepo, s.refreshRepo, newTestTokenIssuer(s.refreshRepo))
+	s.ctx = context.Background()
+
+	now := time.Now()
+	s.validUser = &userentity.User{
+		ID:           uuid.New(),
+		Username:     "testuser",
+		Email:        "test@example.com",
+		PasswordHash: "$2a$10$hashedpassword",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	s.rawToken = "raw-refresh-token"
+	s.current = &userentity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    s.validUser.ID,
+		FamilyID:  uuid.New(),
+		TokenHash: hashOpaqueToken(s.rawToken),
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	}
+}
+
+func (s *RefreshTokenUseCaseTestSuite) TearDownTest() {
+	s.mockRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+}
+
+func (s *RefreshTokenUseCaseTestSuite) TestRefresh_Success() {
+	s.refreshRepo.On("GetByHash", s.ctx, s.current.TokenHash).Return(s.current, nil)
+	s.mockRepo.On("GetByID", s.ctx, s.validUser.ID).Return(s.validUser, nil)
+	s.refreshRepo.On("Rotate", s.ctx, s.current.ID, mock.MatchedBy(func(t *userentity.RefreshToken) bool {
+		return t.FamilyID == s.current.FamilyID && t.UserID == s.validUser.ID && t.TokenHash != s.current.TokenHash
+	})).Return(s.current, nil)
+
+	response, err := s.useCase.Execute(s.ctx, userdto.RefreshTokenRequest{RefreshToken: s.rawToken})
+
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), response.Token)
+	assert.NotEmpty(s.T(), response.RefreshToken)
+	assert.NotEqual(s.T(), s.rawToken, response.RefreshToken)
+	assert.True(s.T(), response.TokenExpiresAt.Before(response.RefreshTokenExpiresAt))
+}
+
+func (s *RefreshTokenUseCaseTestSuite) TestRefresh_UnknownToken() {
+	s.refreshRepo.On("GetByHash", s.ctx, s.current.TokenHash).Return(nil, pgx.ErrNoRows)
+
+	response, err := s.useCase.Execute(s.ctx, userdto.RefreshTokenRequest{RefreshToken: s.rawToken})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidRefreshToken)
+	assert.Empty(s.T(), response.Token)
+}
+
+func (s *RefreshTokenUseCaseTestSuite) TestRefresh_ExpiredToken() {
+	s.current.ExpiresAt = time.Now().Add(-time.Minute)
+	s.refreshRepo.On("GetByHash", s.ctx, s.current.TokenHash).Return(s.current, nil)
+
+	response, err := s.useCase.Execute(s.ctx, userdto.RefreshTokenRequest{RefreshToken: s.rawToken})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidRefreshToken)
+	assert.Empty(s.T(), response.Token)
+	s.refreshRepo.AssertNotCalled(s.T(), "Rotate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *RefreshTokenUseCaseTestSuite) TestRefresh_ReusedTokenRevokesFamily() {
+	revokedAt := time.Now().Add(-time.Minute)
+	s.current.RevokedAt = &revokedAt
+	s.refreshRepo.On("GetByHash", s.ctx, s.current.TokenHash).Return(s.current, nil)
+	s.refreshRepo.On("RevokeFamily", s.ctx, s.current.FamilyID).Return(nil)
+
+	response, err := s.useCase.Execute(s.ctx, userdto.RefreshTokenRequest{RefreshToken: s.rawToken})
+
+	assert.ErrorIs(s.T(), err, ErrRefreshTokenReused)
+	assert.Empty(s.T(), response.Token)
+	s.mockRepo.AssertNotCalled(s.T(), "GetByID", mock.Anything, mock.Anything)
+}
+
+func (s *RefreshTokenUseCaseTestSuite) TestRefresh_ConcurrentRotationRevokesFamily() {
+	s.refreshRepo.On("GetByHash", s.ctx, s.current.TokenHash).Return(s.current, nil)
+	s.mockRepo.On("GetByID", s.ctx, s.validUser.ID).Return(s.validUser, nil)
+	s.refreshRepo.On("Rotate", s.ctx, s.current.ID, mock.Anything).Return(nil, userrepo.ErrRefreshTokenRevoked)
+	s.refreshRepo.On("RevokeFamily", s.ctx, s.current.FamilyID).Return(nil)
+
+	_, err := s.useCase.Execute(s.ctx, userdto.RefreshTokenRequest{RefreshToken: s.rawToken})
+
+	assert.ErrorIs(s.T(), err, ErrRefreshTokenReused)
+}
+
+func (s *RefreshTokenUseCaseTestSuite) TestRefresh_RevokeFamilyError() {
+	revokedAt := time.Now()
+	s.current.RevokedAt = &revokedAt
+	s.refreshRepo.On("GetByHash", s.ctx, s.current.TokenHash).Return(s.current, nil)
+	s.refreshRepo.On("RevokeFamily", s.ctx, s.current.FamilyID).Return(errors.New("database error"))
+
+	_, err := s.useCase.Execute(s.ctx, userdto.RefreshTokenRequest{RefreshToken: s.rawToken})
+
+	assert.ErrorIs(s.T(), err, ErrRefreshTokenReused)
+	assert.Contains(s.T(), err.Error(), "database error")
+}
+
+func (s *RefreshTokenUseCaseTestSuite) TestRefresh_UserNotFound() {
+	s.refreshRepo.On("GetByHash", s.ctx, s.current.TokenHash).Return(s.current, nil)
+	s.mockRepo.On("GetByID", s.ctx, s.validUser.ID).Return(nil, pgx.ErrNoRows)
+
+	_, err := s.useCase.Execute(s.ctx, userdto.RefreshTokenRequest{RefreshToken: s.rawToken})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidRefreshToken)
+}
+
+func (s *RefreshTokenUseCaseTestSuite) TestRefresh_RotateError() {
+	repoError := errors.New("database error")
+	s.refreshRepo.On("GetByHash", s.ctx, s.current.TokenHash).Return(s.current, nil)
+	s.mockRepo.On("GetByID", s.ctx, s.validUser.ID).Return(s.validUser, nil)
+	s.refreshRepo.On("Rotate", s.ctx, s.current.ID, mock.Anything).Return(nil, repoError)
+
+	_, err := s.useCase.Execute(s.ctx, userdto.RefreshTokenRequest{RefreshToken: s.rawToken})
+
+	assert.Equal(s.T(), repoError, err)
+}
+
+func TestRefreshTokenUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenUseCaseTestSuite))
+}
+
+func TestOpaqueToken_UniqueAndHashed(t *testing.T) {
+	token1, err := generateOpaqueToken()
+	assert.NoError(t, err)
+	token2, err := generateOpaqueToken()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, token1, token2)
+	assert.Len(t, hashOpaqueToken(token1), 64)
+	assert.Equal(t, hashOpaqueToken(token1), hashOpaqueToken(token1))
+	assert.NotEqual(t, hashOpaqueToken(token1), hashOpaqueToken(token2))
+}
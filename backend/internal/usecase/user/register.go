@@ -2,52 +2,58 @@ package user
 
 import (
 	"context"
-	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 
 	userdto "roadmap/internal/domain/dto/user"
 	userentity "roadmap/internal/domain/entities/user"
-	jwtservice "roadmap/internal/pkg/jwt"
 	userrepo "roadmap/internal/repository/user"
 )
 
 type RegisterUseCase struct {
-	userRepository userrepo.UserRepository
-	jwtService     *jwtservice.JWTService
+	userRepository    userrepo.UserRepository
+	passwordHasher    PasswordHasher
+	passwordValidator *PasswordValidator
+	tokenIssuer       *TokenIssuer
+	verificationEmail *EmailVerificationSender
 }
 
-func NewRegisterUseCase(userRepository userrepo.UserRepository, jwtService *jwtservice.JWTService) *RegisterUseCase {
+func NewRegisterUseCase(
+	userRepository userrepo.UserRepository,
+	passwordHasher PasswordHasher,
+	passwordValidator *PasswordValidator,
+	tokenIssuer *TokenIssuer,
+	verificationEmail *EmailVerificationSender,
+) *RegisterUseCase {
 	return &RegisterUseCase{
-		userRepository: userRepository,
-		jwtService:     jwtService,
+		userRepository:    userRepository,
+		passwordHasher:    passwordHasher,
+		passwordValidator: passwordValidator,
+		tokenIssuer:       tokenIssuer,
+		verificationEmail: verificationEmail,
 	}
 }
 
-func (u *RegisterUseCase) Execute(ctx context.Context, req userdto.RegisterRequest) (userdto.RegisterResponse, error) {
-	emailExists, err := u.userRepository.EmailExists(ctx, req.Email)
-	if err != nil {
+func (u *RegisterUseCase) Execute(
+	ctx context.Context,
+	req userdto.RegisterRequest,
+	client userdto.ClientInfo,
+) (userdto.RegisterResponse, error) {
+	if err := checkEmailAvailable(ctx, u.userRepository, req.Email); err != nil {
 		return userdto.RegisterResponse{}, err
 	}
-	if emailExists {
-		return userdto.RegisterResponse{}, ErrEmailAlreadyExists
-	}
 
-	usernameExists, err := u.userRepository.UsernameExists(ctx, req.Username)
-	if err != nil {
+	if err := checkUsernameAvailable(ctx, u.userRepository, req.Username); err != nil {
 		return userdto.RegisterResponse{}, err
 	}
-	if usernameExists {
-		return userdto.RegisterResponse{}, ErrUsernameAlreadyExists
-	}
 
-	if err := validatePassword(req.Password); err != nil {
+	if err := u.passwordValidator.Validate(req.Password, req.Email, req.Username); err != nil {
 		return userdto.RegisterResponse{}, err
 	}
 
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	passwordHash, err := u.passwordHasher.Hash(req.Password)
 	if err != nil {
 		return userdto.RegisterResponse{}, err
 	}
@@ -57,7 +63,7 @@ func (u *RegisterUseCase) Execute(ctx context.Context, req userdto.RegisterReque
 		ID:           uuid.New(),
 		Username:     req.Username,
 		Email:        req.Email,
-		PasswordHash: string(passwordHash),
+		PasswordHash: passwordHash,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -67,21 +73,47 @@ func (u *RegisterUseCase) Execute(ctx context.Context, req userdto.RegisterReque
 		return userdto.RegisterResponse{}, err
 	}
 
-	token, err := u.jwtService.GenerateToken(
-		createdUser.ID.String(),
-		createdUser.Username,
-		createdUser.Email,
-	)
+	// The account already exists at this point; if the email cannot be sent
+	// the user can ask for another one through the resend endpoint.
+	if err := u.verificationEmail.Send(ctx, createdUser); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", createdUser.ID, err)
+	}
+
+	tokens, err := u.tokenIssuer.Issue(ctx, createdUser, client)
 	if err != nil {
-		return userdto.RegisterResponse{}, fmt.Errorf("failed to generate token: %w", err)
+		return userdto.RegisterResponse{}, err
 	}
 
 	return userdto.RegisterResponse{
 		ID:        createdUser.ID,
 		Username:  createdUser.Username,
 		Email:     createdUser.Email,
-		Token:     token,
 		CreatedAt: createdUser.CreatedAt,
 		UpdatedAt: createdUser.UpdatedAt,
+		TokenPair: tokens,
 	}, nil
 }
+
+// checkEmailAvailable and checkUsernameAvailable are shared by every flow
+// that creates an account.
+func checkEmailAvailable(ctx context.Context, userRepository userrepo.UserRepository, email string) error {
+	exists, err := userRepository.EmailExists(ctx, email)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrEmailAlreadyExists
+	}
+	return nil
+}
+
+func checkUsernameAvailable(ctx context.Context, userRepository userrepo.UserRepository, username string) error {
+	exists, err := userRepository.UsernameExists(ctx, username)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrUsernameAlreadyExists
+	}
+	return nil
+}
@@ -13,14 +13,15 @@ import (
 
 	userdto "roadmap/internal/domain/dto/user"
 	userentity "roadmap/internal/domain/entities/user"
-	jwtservice "roadmap/internal/pkg/jwt"
 )
 
 type RegisterUseCaseTestSuite struct {
 	suite.Suite
 	useCase      *RegisterUseCase
 	mockRepo     *MockUserRepository
-	jwtService   *jwtservice.JWTService
+	refreshRepo  *MockRefreshTokenRepository
+	verifyRepo   *MockEmailVerificationTokenRepository
+	mailer       *MockMailer
 	validRequest userdto.RegisterRequest
 	validUser    *userentity.User
 	ctx          context.Context
@@ -28,8 +29,16 @@ type RegisterUseCaseTestSuite struct {
 
 func (s *RegisterUseCaseTestSuite) SetupTest() {
 	s.mockRepo = new(MockUserRepository)
-	s.jwtService = jwtservice.NewJWTService("test-secret-key", 24*3600*1000000000)
-	s.useCase = NewRegisterUseCase(s.mockRepo, s.jwtService)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.verifyRepo = new(MockEmailVerificationTokenRepository)
+	s.mailer = new(MockMailer)
+	s.useCase = NewRegisterUseCase(
+		s.mockRepo,
+		testPasswordHasher,
+		testPasswordValidator,
+		newTestTokenIssuer(s.refreshRepo),
+		NewEmailVerificationSender(s.verifyRepo, s.mailer, "https://app.example.com/verify-email", 24*time.Hour),
+	)
 	s.ctx = context.Background()
 
 	s.validRequest = userdto.RegisterRequest{
@@ -51,20 +60,28 @@ func (s *RegisterUseCaseTestSuite) SetupTest() {
 
 func (s *RegisterUseCaseTestSuite) TearDownTest() {
 	s.mockRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+	s.verifyRepo.AssertExpectations(s.T())
+	s.mailer.AssertExpectations(s.T())
 }
 
 func (s *RegisterUseCaseTestSuite) TestRegister_Success() {
 	s.mockRepo.On("EmailExists", s.ctx, s.validRequest.Email).Return(false, nil)
 	s.mockRepo.On("UsernameExists", s.ctx, s.validRequest.Username).Return(false, nil)
 	s.mockRepo.On("Create", s.ctx, mock.AnythingOfType("*user.User")).Return(s.validUser, nil)
+	s.verifyRepo.On("Create", s.ctx, mock.AnythingOfType("*user.EmailVerificationToken")).
+		Return(&userentity.EmailVerificationToken{}, nil)
+	s.mailer.On("Send", s.ctx, mock.AnythingOfType("mailer.Message")).Return(nil)
+	s.refreshRepo.On("Create", s.ctx, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
 
-	response, err := s.useCase.Execute(s.ctx, s.validRequest)
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
 
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), s.validUser.ID, response.ID)
 	assert.Equal(s.T(), s.validUser.Username, response.Username)
 	assert.Equal(s.T(), s.validUser.Email, response.Email)
 	assert.NotEmpty(s.T(), response.Token)
+	assert.NotEmpty(s.T(), response.RefreshToken)
 	assert.False(s.T(), response.CreatedAt.IsZero())
 	assert.False(s.T(), response.UpdatedAt.IsZero())
 }
@@ -72,7 +89,7 @@ func (s *RegisterUseCaseTestSuite) TestRegister_Success() {
 func (s *RegisterUseCaseTestSuite) TestRegister_EmailAlreadyExists() {
 	s.mockRepo.On("EmailExists", s.ctx, s.validRequest.Email).Return(true, nil)
 
-	response, err := s.useCase.Execute(s.ctx, s.validRequest)
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
 
 	assert.Error(s.T(), err)
 	assert.Equal(s.T(), ErrEmailAlreadyExists, err)
@@ -84,7 +101,7 @@ func (s *RegisterUseCaseTestSuite) TestRegister_UsernameAlreadyExists() {
 	s.mockRepo.On("EmailExists", s.ctx, s.validRequest.Email).Return(false, nil)
 	s.mockRepo.On("UsernameExists", s.ctx, s.validRequest.Username).Return(true, nil)
 
-	response, err := s.useCase.Execute(s.ctx, s.validRequest)
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
 
 	assert.Error(s.T(), err)
 	assert.Equal(s.T(), ErrUsernameAlreadyExists, err)
@@ -115,7 +132,7 @@ func (s *RegisterUseCaseTestSuite) TestRegister_InvalidPassword() {
 			s.mockRepo.On("EmailExists", s.ctx, req.Email).Return(false, nil)
 			s.mockRepo.On("UsernameExists", s.ctx, req.Username).Return(false, nil)
 
-			response, err := s.useCase.Execute(s.ctx, req)
+			response, err := s.useCase.Execute(s.ctx, req, userdto.ClientInfo{})
 
 			assert.Error(s.T(), err)
 			var passwordErr *PasswordValidationError
@@ -130,7 +147,7 @@ func (s *RegisterUseCaseTestSuite) TestRegister_EmailExistsError() {
 	repoError := errors.New("database error")
 	s.mockRepo.On("EmailExists", s.ctx, s.validRequest.Email).Return(false, repoError)
 
-	response, err := s.useCase.Execute(s.ctx, s.validRequest)
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
 
 	assert.Error(s.T(), err)
 	assert.Equal(s.T(), repoError, err)
@@ -142,7 +159,7 @@ func (s *RegisterUseCaseTestSuite) TestRegister_UsernameExistsError() {
 	repoError := errors.New("database error")
 	s.mockRepo.On("UsernameExists", s.ctx, s.validRequest.Username).Return(false, repoError)
 
-	response, err := s.useCase.Execute(s.ctx, s.validRequest)
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
 
 	assert.Error(s.T(), err)
 	assert.Equal(s.T(), repoError, err)
@@ -155,7 +172,7 @@ func (s *RegisterUseCaseTestSuite) TestRegister_CreateError() {
 	repoError := errors.New("create error")
 	s.mockRepo.On("Create", s.ctx, mock.AnythingOfType("*user.User")).Return(nil, repoError)
 
-	response, err := s.useCase.Execute(s.ctx, s.validRequest)
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
 
 	assert.Error(s.T(), err)
 	assert.Equal(s.T(), repoError, err)
@@ -166,13 +183,32 @@ func (s *RegisterUseCaseTestSuite) TestRegister_JWTGenerationError() {
 	s.mockRepo.On("EmailExists", s.ctx, s.validRequest.Email).Return(false, nil)
 	s.mockRepo.On("UsernameExists", s.ctx, s.validRequest.Username).Return(false, nil)
 	s.mockRepo.On("Create", s.ctx, mock.AnythingOfType("*user.User")).Return(s.validUser, nil)
+	s.verifyRepo.On("Create", s.ctx, mock.AnythingOfType("*user.EmailVerificationToken")).
+		Return(&userentity.EmailVerificationToken{}, nil)
+	s.mailer.On("Send", s.ctx, mock.AnythingOfType("mailer.Message")).Return(nil)
+	s.refreshRepo.On("Create", s.ctx, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
 
-	response, err := s.useCase.Execute(s.ctx, s.validRequest)
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
 
 	assert.NoError(s.T(), err)
 	assert.NotEmpty(s.T(), response.Token)
 }
 
+func (s *RegisterUseCaseTestSuite) TestRegister_VerificationEmailFailureDoesNotFail() {
+	s.mockRepo.On("EmailExists", s.ctx, s.validRequest.Email).Return(false, nil)
+	s.mockRepo.On("UsernameExists", s.ctx, s.validRequest.Username).Return(false, nil)
+	s.mockRepo.On("Create", s.ctx, mock.AnythingOfType("*user.User")).Return(s.validUser, nil)
+	s.verifyRepo.On("Create", s.ctx, mock.AnythingOfType("*user.EmailVerificationToken")).
+		Return(nil, errors.New("database error"))
+	s.refreshRepo.On("Create", s.ctx, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
+
+	response, err := s.useCase.Execute(s.ctx, s.validRequest, userdto.ClientInfo{})
+
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), response.Token)
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything, mock.Anything)
+}
+
 func TestRegisterUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(RegisterUseCaseTestSuite))
 }
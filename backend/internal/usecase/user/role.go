@@ -0,0 +1,242 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	userrepo "roadmap/internal/repository/user"
+)
+
+func newUserRolesResponse(user *userentity.User) userdto.UserRolesResponse {
+	roles := user.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	permissions := user.Permissions
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	return userdto.UserRolesResponse{
+		UserID:      user.ID,
+		Roles:       roles,
+		Permissions: permissions,
+	}
+}
+
+// getTargetUser loads the user an administrator is acting on.
+func getTargetUser(ctx context.Context, userRepository userrepo.UserRepository, userID string) (*userentity.User, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	user, err := userRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// checkAssignableRole verifies that role exists and is not the implicit
+// member role.
+func checkAssignableRole(ctx context.Context, roleRepository userrepo.RoleRepository, role string) error {
+	if role == userentity.RoleMember {
+		return ErrRoleNotAssignable
+	}
+
+	if _, err := roleRepository.GetByName(ctx, role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+type ListRolesUseCase struct {
+	roleRepository userrepo.RoleRepository
+}
+
+func NewListRolesUseCase(roleRepository userrepo.RoleRepository) *ListRolesUseCase {
+	return &ListRolesUseCase{
+		roleRepository: roleRepository,
+	}
+}
+
+func (u *ListRolesUseCase) Execute(ctx context.Context) (userdto.ListRolesResponse, error) {
+	roles, err := u.roleRepository.List(ctx)
+	if err != nil {
+		return userdto.ListRolesResponse{}, err
+	}
+
+	response := userdto.ListRolesResponse{
+		Roles: make([]userdto.RoleResponse, 0, len(roles)),
+	}
+	for _, role := range roles {
+		permissions := role.Permissions
+		if permissions == nil {
+			permissions = []string{}
+		}
+		response.Roles = append(response.Roles, userdto.RoleResponse{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: permissions,
+		})
+	}
+
+	return response, nil
+}
+
+type GetUserRolesUseCase struct {
+	userRepository userrepo.UserRepository
+}
+
+func NewGetUserRolesUseCase(userRepository userrepo.UserRepository) *GetUserRolesUseCase {
+	return &GetUserRolesUseCase{
+		userRepository: userRepository,
+	}
+}
+
+func (u *GetUserRolesUseCase) Execute(ctx context.Context, userID string) (userdto.UserRolesResponse, error) {
+	user, err := getTargetUser(ctx, u.userRepository, userID)
+	if err != nil {
+		return userdto.UserRolesResponse{}, err
+	}
+
+	return newUserRolesResponse(user), nil
+}
+
+type GrantRoleUseCase struct {
+	userRepository userrepo.UserRepository
+	roleRepository userrepo.RoleRepository
+}
+
+func NewGrantRoleUseCase(
+	userRepository userrepo.UserRepository,
+	roleRepository userrepo.RoleRepository,
+) *GrantRoleUseCase {
+	return &GrantRoleUseCase{
+		userRepository: userRepository,
+		roleRepository: roleRepository,
+	}
+}
+
+// Execute grants req.Role to userID on behalf of actorID. The new
+// permissions show up in the user's tokens from their next refresh.
+func (u *GrantRoleUseCase) Execute(
+	ctx context.Context,
+	actorID string,
+	userID string,
+	req userdto.GrantRoleRequest,
+) (userdto.UserRolesResponse, error) {
+	actor, err := uuid.Parse(actorID)
+	if err != nil {
+		return userdto.UserRolesResponse{}, ErrInvalidTokenClaims
+	}
+
+	if err := checkAssignableRole(ctx, u.roleRepository, req.Role); err != nil {
+		return userdto.UserRolesResponse{}, err
+	}
+
+	user, err := getTargetUser(ctx, u.userRepository, userID)
+	if err != nil {
+		return userdto.UserRolesResponse{}, err
+	}
+
+	err = u.roleRepository.Grant(ctx, &userentity.UserRole{
+		UserID:    user.ID,
+		Role:      req.Role,
+		GrantedBy: &actor,
+		GrantedAt: time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, userrepo.ErrRoleAlreadyGranted) {
+			return userdto.UserRolesResponse{}, ErrRoleAlreadyGranted
+		}
+		return userdto.UserRolesResponse{}, err
+	}
+
+	user, err = u.userRepository.GetByID(ctx, user.ID)
+	if err != nil {
+		return userdto.UserRolesResponse{}, err
+	}
+
+	return newUserRolesResponse(user), nil
+}
+
+type RevokeRoleUseCase struct {
+	userRepository    userrepo.UserRepository
+	roleRepository    userrepo.RoleRepository
+	revocationService *TokenRevocationService
+}
+
+func NewRevokeRoleUseCase(
+	userRepository userrepo.UserRepository,
+	roleRepository userrepo.RoleRepository,
+	revocationService *TokenRevocationService,
+) *RevokeRoleUseCase {
+	return &RevokeRoleUseCase{
+		userRepository:    userRepository,
+		roleRepository:    roleRepository,
+		revocationService: revocationService,
+	}
+}
+
+// Execute revokes role from userID. The user's access tokens are revoked as
+// well so that permissions they still carry stop working immediately; the
+// user's next refresh picks up the reduced set.
+func (u *RevokeRoleUseCase) Execute(
+	ctx context.Context,
+	actorID string,
+	userID string,
+	role string,
+) (userdto.UserRolesResponse, error) {
+	actor, err := uuid.Parse(actorID)
+	if err != nil {
+		return userdto.UserRolesResponse{}, ErrInvalidTokenClaims
+	}
+
+	if err := checkAssignableRole(ctx, u.roleRepository, role); err != nil {
+		return userdto.UserRolesResponse{}, err
+	}
+
+	user, err := getTargetUser(ctx, u.userRepository, userID)
+	if err != nil {
+		return userdto.UserRolesResponse{}, err
+	}
+
+	// Keeps the last administrator from locking everyone out by accident.
+	if user.ID == actor && role == userentity.RoleAdmin {
+		return userdto.UserRolesResponse{}, ErrCannotRevokeOwnAdmin
+	}
+
+	if err := u.roleRepository.Revoke(ctx, user.ID, role); err != nil {
+		if errors.Is(err, userrepo.ErrRoleNotGranted) {
+			return userdto.UserRolesResponse{}, ErrRoleNotGranted
+		}
+		return userdto.UserRolesResponse{}, err
+	}
+
+	if err := u.revocationService.RevokeAllForUser(ctx, user.ID); err != nil {
+		return userdto.UserRolesResponse{}, err
+	}
+
+	user, err = u.userRepository.GetByID(ctx, user.ID)
+	if err != nil {
+		return userdto.UserRolesResponse{}, err
+	}
+
+	return newUserRolesResponse(user), nil
+}
@@ -0,0 +1,177 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) List(ctx context.Context) ([]*userentity.Role, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*userentity.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) GetByName(ctx context.Context, name string) (*userentity.Role, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) Grant(ctx context.Context, userRole *userentity.UserRole) error {
+	args := m.Called(ctx, userRole)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Revoke(ctx context.Context, userID uuid.UUID, role string) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
+type RoleUseCaseTestSuite struct {
+	suite.Suite
+	userRepo       *MockUserRepository
+	roleRepo       *MockRoleRepository
+	revocationRepo *MockTokenRevocationRepository
+	admin          *userentity.User
+	user           *userentity.User
+	ctx            context.Context
+}
+
+func (s *RoleUseCaseTestSuite) SetupTest() {
+	s.userRepo = new(MockUserRepository)
+	s.roleRepo = new(MockRoleRepository)
+	s.revocationRepo = new(MockTokenRevocationRepository)
+	s.ctx = context.Background()
+
+	s.admin = &userentity.User{
+		ID:    uuid.New(),
+		Roles: []string{userentity.RoleAdmin, userentity.RoleMember},
+	}
+	s.user = &userentity.User{
+		ID:          uuid.New(),
+		Roles:       []string{userentity.RoleMember},
+		Permissions: []string{userentity.PermissionRoadmapsCreate},
+	}
+}
+
+func (s *RoleUseCaseTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.roleRepo.AssertExpectations(s.T())
+	s.revocationRepo.AssertExpectations(s.T())
+}
+
+func (s *RoleUseCaseTestSuite) TestGrant() {
+	moderator := &userentity.User{
+		ID:          s.user.ID,
+		Roles:       []string{userentity.RoleMember, userentity.RoleModerator},
+		Permissions: []string{userentity.PermissionRoadmapsCreate, userentity.PermissionUsersRead},
+	}
+	s.roleRepo.On("GetByName", s.ctx, userentity.RoleModerator).Return(&userentity.Role{Name: userentity.RoleModerator}, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil).Once()
+	s.roleRepo.On("Grant", s.ctx, mock.MatchedBy(func(userRole *userentity.UserRole) bool {
+		return userRole.UserID == s.user.ID && userRole.Role == userentity.RoleModerator &&
+			userRole.GrantedBy != nil && *userRole.GrantedBy == s.admin.ID
+	})).Return(nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(moderator, nil).Once()
+
+	response, err := NewGrantRoleUseCase(s.userRepo, s.roleRepo).Execute(s.ctx, s.admin.ID.String(), s.user.ID.String(),
+		userdto.GrantRoleRequest{Role: userentity.RoleModerator})
+
+	s.Require().NoError(err)
+	assert.Contains(s.T(), response.Roles, userentity.RoleModerator)
+	assert.Contains(s.T(), response.Permissions, userentity.PermissionUsersRead)
+}
+
+func (s *RoleUseCaseTestSuite) TestGrant_Rejects() {
+	grant := NewGrantRoleUseCase(s.userRepo, s.roleRepo)
+
+	_, err := grant.Execute(s.ctx, s.admin.ID.String(), s.user.ID.String(), userdto.GrantRoleRequest{Role: userentity.RoleMember})
+	assert.ErrorIs(s.T(), err, ErrRoleNotAssignable)
+
+	s.roleRepo.On("GetByName", s.ctx, "superuser").Return(nil, fmt.Errorf("role not found: %w", pgx.ErrNoRows))
+	_, err = grant.Execute(s.ctx, s.admin.ID.String(), s.user.ID.String(), userdto.GrantRoleRequest{Role: "superuser"})
+	assert.ErrorIs(s.T(), err, ErrRoleNotFound)
+
+	s.roleRepo.On("GetByName", s.ctx, userentity.RoleAdmin).Return(&userentity.Role{Name: userentity.RoleAdmin}, nil)
+	_, err = grant.Execute(s.ctx, s.admin.ID.String(), "not-a-uuid", userdto.GrantRoleRequest{Role: userentity.RoleAdmin})
+	assert.ErrorIs(s.T(), err, ErrUserNotFound)
+
+	s.userRepo.On("GetByID", s.ctx, s.admin.ID).Return(s.admin, nil)
+	s.roleRepo.On("Grant", s.ctx, mock.Anything).Return(userrepo.ErrRoleAlreadyGranted)
+	_, err = grant.Execute(s.ctx, s.admin.ID.String(), s.admin.ID.String(), userdto.GrantRoleRequest{Role: userentity.RoleAdmin})
+	assert.ErrorIs(s.T(), err, ErrRoleAlreadyGranted)
+}
+
+func (s *RoleUseCaseTestSuite) TestRevoke_RevokesAccessTokens() {
+	s.roleRepo.On("GetByName", s.ctx, userentity.RoleModerator).Return(&userentity.Role{Name: userentity.RoleModerator}, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.roleRepo.On("Revoke", s.ctx, s.user.ID, userentity.RoleModerator).Return(nil)
+	s.revocationRepo.On("SetTokensValidAfter", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	revocationService := NewTokenRevocationService(s.revocationRepo, time.Minute)
+	response, err := NewRevokeRoleUseCase(s.userRepo, s.roleRepo, revocationService).
+		Execute(s.ctx, s.admin.ID.String(), s.user.ID.String(), userentity.RoleModerator)
+
+	s.Require().NoError(err)
+	assert.Equal(s.T(), []string{userentity.RoleMember}, response.Roles)
+}
+
+func (s *RoleUseCaseTestSuite) TestRevoke_OwnAdminRole() {
+	s.roleRepo.On("GetByName", s.ctx, userentity.RoleAdmin).Return(&userentity.Role{Name: userentity.RoleAdmin}, nil)
+	s.userRepo.On("GetByID", s.ctx, s.admin.ID).Return(s.admin, nil)
+
+	revocationService := NewTokenRevocationService(s.revocationRepo, time.Minute)
+	_, err := NewRevokeRoleUseCase(s.userRepo, s.roleRepo, revocationService).
+		Execute(s.ctx, s.admin.ID.String(), s.admin.ID.String(), userentity.RoleAdmin)
+
+	assert.ErrorIs(s.T(), err, ErrCannotRevokeOwnAdmin)
+}
+
+func (s *RoleUseCaseTestSuite) TestRevoke_NotGranted() {
+	s.roleRepo.On("GetByName", s.ctx, userentity.RoleAdmin).Return(&userentity.Role{Name: userentity.RoleAdmin}, nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.roleRepo.On("Revoke", s.ctx, s.user.ID, userentity.RoleAdmin).Return(userrepo.ErrRoleNotGranted)
+
+	revocationService := NewTokenRevocationService(s.revocationRepo, time.Minute)
+	_, err := NewRevokeRoleUseCase(s.userRepo, s.roleRepo, revocationService).
+		Execute(s.ctx, s.admin.ID.String(), s.user.ID.String(), userentity.RoleAdmin)
+
+	assert.ErrorIs(s.T(), err, ErrRoleNotGranted)
+}
+
+func (s *RoleUseCaseTestSuite) TestListRoles() {
+	s.roleRepo.On("List", s.ctx).Return([]*userentity.Role{
+		{Name: userentity.RoleAdmin, Permissions: []string{userentity.PermissionRolesManage}},
+		{Name: userentity.RoleMember},
+	}, nil)
+
+	response, err := NewListRolesUseCase(s.roleRepo).Execute(s.ctx)
+
+	s.Require().NoError(err)
+	s.Require().Len(response.Roles, 2)
+	assert.Equal(s.T(), []string{}, response.Roles[1].Permissions)
+}
+
+func TestRoleUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(RoleUseCaseTestSuite))
+}
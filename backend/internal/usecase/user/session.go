@@ -0,0 +1,218 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	userrepo "roadmap/internal/repository/user"
+)
+
+const (
+	maxSessionCacheEntries = 10000
+
+	// maxUserAgentLength matches the sessions.user_agent column.
+	maxUserAgentLength = 512
+)
+
+// SessionService records a session for every sign-in and tells
+// AuthMiddleware whether the session an access token was issued for has
+// been revoked.
+//
+// Answers are cached in process like those of TokenRevocationService:
+// revocations made through this service are visible immediately, revocations
+// made by other API instances once the cached answer is older than cacheTTL.
+type SessionService struct {
+	sessionRepository userrepo.SessionRepository
+	cacheTTL          time.Duration
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]sessionStatus
+}
+
+type sessionStatus struct {
+	revoked     bool
+	cachedUntil time.Time
+}
+
+func NewSessionService(sessionRepository userrepo.SessionRepository, cacheTTL time.Duration) *SessionService {
+	return &SessionService{
+		sessionRepository: sessionRepository,
+		cacheTTL:          cacheTTL,
+		cache:             make(map[uuid.UUID]sessionStatus),
+	}
+}
+
+// Start records the session of a sign-in whose refresh token family is id.
+func (s *SessionService) Start(
+	ctx context.Context,
+	id uuid.UUID,
+	userID uuid.UUID,
+	client userdto.ClientInfo,
+) error {
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+	}
+
+	now := time.Now().UTC()
+	_, err := s.sessionRepository.Create(ctx, &userentity.Session{
+		ID:         id,
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IPAddress:  client.IPAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	})
+	return err
+}
+
+// Touch moves the last-seen time of the session forward. It is called when
+// the session's refresh token is rotated, so the time is as precise as the
+// access token lifetime. Failures are only logged.
+func (s *SessionService) Touch(ctx context.Context, id uuid.UUID) {
+	if err := s.sessionRepository.Touch(ctx, id, time.Now().UTC()); err != nil {
+		log.Printf("Failed to update last seen time of session %s: %v", id, err)
+	}
+}
+
+// Revoke ends the session: its refresh tokens stop working right away and
+// its access tokens are rejected by AuthMiddleware.
+func (s *SessionService) Revoke(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	now := time.Now()
+	if err := s.sessionRepository.Revoke(ctx, id, userID, now.UTC()); err != nil {
+		if errors.Is(err, userrepo.ErrSessionNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+
+	s.set(id, true, now)
+	return nil
+}
+
+// IsSessionRevoked reports true for revoked sessions and for session IDs that
+// cannot be parsed. Unknown sessions, such as those of sign-ins made before
+// sessions were recorded, count as active.
+func (s *SessionService) IsSessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return true, nil
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[id]
+	s.mu.Unlock()
+	if ok && now.Before(cached.cachedUntil) {
+		return cached.revoked, nil
+	}
+
+	revoked, err := s.sessionRepository.IsRevoked(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	s.set(id, revoked, now)
+	return revoked, nil
+}
+
+// PurgeExpired drops sessions whose refresh tokens have all expired.
+func (s *SessionService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.sessionRepository.DeleteExpired(ctx, time.Now().UTC())
+}
+
+func (s *SessionService) set(id uuid.UUID, revoked bool, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.cache) >= maxSessionCacheEntries {
+		for sessionID, status := range s.cache {
+			if now.After(status.cachedUntil) {
+				delete(s.cache, sessionID)
+			}
+		}
+	}
+	s.cache[id] = sessionStatus{
+		revoked:     revoked,
+		cachedUntil: now.Add(s.cacheTTL),
+	}
+}
+
+type ListSessionsUseCase struct {
+	sessionRepository userrepo.SessionRepository
+}
+
+func NewListSessionsUseCase(sessionRepository userrepo.SessionRepository) *ListSessionsUseCase {
+	return &ListSessionsUseCase{
+		sessionRepository: sessionRepository,
+	}
+}
+
+// Execute lists the user's active sessions. currentSessionID is the session
+// of the token making the request; it may be empty.
+func (u *ListSessionsUseCase) Execute(
+	ctx context.Context,
+	userID string,
+	currentSessionID string,
+) (userdto.ListSessionsResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.ListSessionsResponse{}, ErrInvalidTokenClaims
+	}
+
+	sessions, err := u.sessionRepository.ListActiveByUser(ctx, id, time.Now().UTC())
+	if err != nil {
+		return userdto.ListSessionsResponse{}, err
+	}
+
+	response := userdto.ListSessionsResponse{
+		Sessions: make([]userdto.SessionResponse, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, userdto.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.ID.String() == currentSessionID,
+		})
+	}
+
+	return response, nil
+}
+
+type RevokeSessionUseCase struct {
+	sessionService *SessionService
+}
+
+func NewRevokeSessionUseCase(sessionService *SessionService) *RevokeSessionUseCase {
+	return &RevokeSessionUseCase{
+		sessionService: sessionService,
+	}
+}
+
+// Execute signs the user out of one of their sessions, which may be the
+// current one.
+func (u *RevokeSessionUseCase) Execute(ctx context.Context, userID string, sessionID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return ErrInvalidTokenClaims
+	}
+
+	targetID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return ErrSessionNotFound
+	}
+
+	return u.sessionService.Revoke(ctx, targetID, id)
+}
@@ -0,0 +1,239 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(ctx context.Context, session *userentity.Session) (*userentity.Session, error) {
+	args := m.Called(ctx, session)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) ListActiveByUser(
+	ctx context.Context,
+	userID uuid.UUID,
+	now time.Time,
+) ([]*userentity.Session, error) {
+	args := m.Called(ctx, userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*userentity.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Touch(ctx context.Context, id uuid.UUID, lastSeenAt time.Time) error {
+	args := m.Called(ctx, id, lastSeenAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) Revoke(ctx context.Context, id uuid.UUID, userID uuid.UUID, revokedAt time.Time) error {
+	args := m.Called(ctx, id, userID, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) IsRevoked(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type SessionUseCaseTestSuite struct {
+	suite.Suite
+	sessionRepo *MockSessionRepository
+	refreshRepo *MockRefreshTokenRepository
+	service     *SessionService
+	jwtService  *jwtservice.JWTService
+	tokenIssuer *TokenIssuer
+	user        *userentity.User
+	ctx         context.Context
+}
+
+func (s *SessionUseCaseTestSuite) SetupTest() {
+	s.sessionRepo = new(MockSessionRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.service = NewSessionService(s.sessionRepo, time.Minute)
+	s.jwtService = jwtservice.NewJWTService("test-secret-key", 15*time.Minute)
+	s.tokenIssuer = NewTokenIssuer(s.jwtService, s.refreshRepo, s.service, 30*24*time.Hour)
+	s.user = &userentity.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com"}
+	s.ctx = context.Background()
+}
+
+func (s *SessionUseCaseTestSuite) TearDownTest() {
+	s.sessionRepo.AssertExpectations(s.T())
+}
+
+func (s *SessionUseCaseTestSuite) TestIssue_StartsSession() {
+	var refreshToken *userentity.RefreshToken
+	s.refreshRepo.On("Create", s.ctx, mock.AnythingOfType("*user.RefreshToken")).
+		Run(func(args mock.Arguments) {
+			refreshToken = args.Get(1).(*userentity.RefreshToken)
+		}).
+		Return(&userentity.RefreshToken{}, nil)
+
+	var session *userentity.Session
+	s.sessionRepo.On("Create", s.ctx, mock.AnythingOfType("*user.Session")).
+		Run(func(args mock.Arguments) {
+			session = args.Get(1).(*userentity.Session)
+		}).
+		Return(&userentity.Session{}, nil)
+
+	tokens, err := s.tokenIssuer.Issue(s.ctx, s.user, userdto.ClientInfo{
+		IPAddress: "192.0.2.1",
+		UserAgent: strings.Repeat("a", maxUserAgentLength+10),
+	})
+
+	s.Require().NoError(err)
+	assert.Equal(s.T(), refreshToken.FamilyID, session.ID)
+	assert.Equal(s.T(), s.user.ID, session.UserID)
+	assert.Equal(s.T(), "192.0.2.1", session.IPAddress)
+	assert.Len(s.T(), session.UserAgent, maxUserAgentLength)
+
+	claims, err := s.jwtService.ValidateToken(tokens.Token)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), session.ID.String(), claims.SessionID)
+}
+
+func (s *SessionUseCaseTestSuite) TestIssue_SessionFailureFailsSignIn() {
+	s.refreshRepo.On("Create", s.ctx, mock.Anything).Return(&userentity.RefreshToken{}, nil)
+	s.sessionRepo.On("Create", s.ctx, mock.Anything).Return(nil, errors.New("database error"))
+
+	_, err := s.tokenIssuer.Issue(s.ctx, s.user, userdto.ClientInfo{})
+
+	assert.Error(s.T(), err)
+}
+
+func (s *SessionUseCaseTestSuite) TestRotate_TouchesSession() {
+	current := &userentity.RefreshToken{ID: uuid.New(), UserID: s.user.ID, FamilyID: uuid.New()}
+	s.refreshRepo.On("Rotate", s.ctx, current.ID, mock.Anything).Return(&userentity.RefreshToken{}, nil)
+	s.sessionRepo.On("Touch", s.ctx, current.FamilyID, mock.AnythingOfType("time.Time")).
+		Return(errors.New("database error"))
+
+	tokens, err := s.tokenIssuer.Rotate(s.ctx, s.user, current)
+
+	s.Require().NoError(err, "a failed touch does not fail the refresh")
+	claims, err := s.jwtService.ValidateToken(tokens.Token)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), current.FamilyID.String(), claims.SessionID)
+}
+
+func (s *SessionUseCaseTestSuite) TestIsSessionRevoked_Cached() {
+	sessionID := uuid.New()
+	s.sessionRepo.On("IsRevoked", s.ctx, sessionID).Return(false, nil).Once()
+
+	for i := 0; i < 3; i++ {
+		revoked, err := s.service.IsSessionRevoked(s.ctx, sessionID.String())
+		s.Require().NoError(err)
+		assert.False(s.T(), revoked)
+	}
+}
+
+func (s *SessionUseCaseTestSuite) TestIsSessionRevoked_InvalidID() {
+	revoked, err := s.service.IsSessionRevoked(s.ctx, "not-a-uuid")
+
+	s.Require().NoError(err)
+	assert.True(s.T(), revoked)
+}
+
+func (s *SessionUseCaseTestSuite) TestIsSessionRevoked_Error() {
+	sessionID := uuid.New()
+	s.sessionRepo.On("IsRevoked", s.ctx, sessionID).Return(false, errors.New("database error"))
+
+	_, err := s.service.IsSessionRevoked(s.ctx, sessionID.String())
+
+	assert.Error(s.T(), err)
+}
+
+func (s *SessionUseCaseTestSuite) TestRevoke_VisibleImmediately() {
+	sessionID := uuid.New()
+	s.sessionRepo.On("IsRevoked", s.ctx, sessionID).Return(false, nil).Once()
+	s.sessionRepo.On("Revoke", s.ctx, sessionID, s.user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	revoked, err := s.service.IsSessionRevoked(s.ctx, sessionID.String())
+	s.Require().NoError(err)
+	assert.False(s.T(), revoked)
+
+	err = NewRevokeSessionUseCase(s.service).Execute(s.ctx, s.user.ID.String(), sessionID.String())
+	s.Require().NoError(err)
+
+	revoked, err = s.service.IsSessionRevoked(s.ctx, sessionID.String())
+	s.Require().NoError(err)
+	assert.True(s.T(), revoked)
+}
+
+func (s *SessionUseCaseTestSuite) TestRevoke_NotFound() {
+	sessionID := uuid.New()
+	s.sessionRepo.On("Revoke", s.ctx, sessionID, s.user.ID, mock.Anything).Return(userrepo.ErrSessionNotFound)
+
+	err := NewRevokeSessionUseCase(s.service).Execute(s.ctx, s.user.ID.String(), sessionID.String())
+	assert.ErrorIs(s.T(), err, ErrSessionNotFound)
+
+	err = NewRevokeSessionUseCase(s.service).Execute(s.ctx, s.user.ID.String(), "not-a-uuid")
+	assert.ErrorIs(s.T(), err, ErrSessionNotFound)
+
+	err = NewRevokeSessionUseCase(s.service).Execute(s.ctx, "not-a-uuid", sessionID.String())
+	assert.ErrorIs(s.T(), err, ErrInvalidTokenClaims)
+}
+
+func (s *SessionUseCaseTestSuite) TestListSessions_MarksCurrent() {
+	now := time.Now().UTC()
+	current := &userentity.Session{ID: uuid.New(), UserID: s.user.ID, UserAgent: "Firefox", CreatedAt: now, LastSeenAt: now}
+	other := &userentity.Session{ID: uuid.New(), UserID: s.user.ID, UserAgent: "curl", CreatedAt: now, LastSeenAt: now}
+	s.sessionRepo.On("ListActiveByUser", s.ctx, s.user.ID, mock.AnythingOfType("time.Time")).
+		Return([]*userentity.Session{current, other}, nil)
+
+	response, err := NewListSessionsUseCase(s.sessionRepo).Execute(s.ctx, s.user.ID.String(), current.ID.String())
+
+	s.Require().NoError(err)
+	s.Require().Len(response.Sessions, 2)
+	assert.True(s.T(), response.Sessions[0].Current)
+	assert.Equal(s.T(), "Firefox", response.Sessions[0].UserAgent)
+	assert.False(s.T(), response.Sessions[1].Current)
+}
+
+func (s *SessionUseCaseTestSuite) TestListSessions_Empty() {
+	s.sessionRepo.On("ListActiveByUser", s.ctx, s.user.ID, mock.Anything).Return([]*userentity.Session{}, nil)
+
+	response, err := NewListSessionsUseCase(s.sessionRepo).Execute(s.ctx, s.user.ID.String(), "")
+
+	s.Require().NoError(err)
+	assert.NotNil(s.T(), response.Sessions)
+	assert.Empty(s.T(), response.Sessions)
+}
+
+func (s *SessionUseCaseTestSuite) TestPurgeExpired() {
+	s.sessionRepo.On("DeleteExpired", s.ctx, mock.AnythingOfType("time.Time")).Return(int64(3), nil)
+
+	purged, err := s.service.PurgeExpired(s.ctx)
+
+	s.Require().NoError(err)
+	assert.Equal(s.T(), int64(3), purged)
+}
+
+func TestSessionUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionUseCaseTestSuite))
+}
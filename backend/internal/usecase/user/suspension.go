@@ -0,0 +1,129 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	userrepo "roadmap/internal/repository/user"
+)
+
+const maxSuspensionCacheEntries = 10000
+
+// SuspensionService tells AuthMiddleware whether the owner of an otherwise
+// valid token has been suspended or deleted since it was issued.
+//
+// Answers are cached in process like those of TokenRevocationService: changes
+// made through this service are visible immediately, changes made by other
+// API instances once the cached answer is older than cacheTTL.
+type SuspensionService struct {
+	userRepository userrepo.UserRepository
+	cacheTTL       time.Duration
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]suspensionStatus
+}
+
+type suspensionStatus struct {
+	suspended   bool
+	cachedUntil time.Time
+}
+
+func NewSuspensionService(userRepository userrepo.UserRepository, cacheTTL time.Duration) *SuspensionService {
+	return &SuspensionService{
+		userRepository: userRepository,
+		cacheTTL:       cacheTTL,
+		cache:          make(map[uuid.UUID]suspensionStatus),
+	}
+}
+
+// IsSuspended reports true for suspended accounts and for accounts that no
+// longer exist.
+func (s *SuspensionService) IsSuspended(ctx context.Context, userID string) (bool, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return true, nil
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[id]
+	s.mu.Unlock()
+	if ok && now.Before(cached.cachedUntil) {
+		return cached.suspended, nil
+	}
+
+	suspended, err := s.userRepository.IsSuspended(ctx, id)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return false, err
+		}
+		suspended = true
+	}
+
+	s.set(id, suspended, now)
+	return suspended, nil
+}
+
+func (s *SuspensionService) Suspend(ctx context.Context, id uuid.UUID, reason string) error {
+	now := time.Now()
+	if err := s.userRepository.Suspend(ctx, id, reason, now.UTC()); err != nil {
+		return err
+	}
+
+	s.set(id, true, now)
+	return nil
+}
+
+func (s *SuspensionService) Unsuspend(ctx context.Context, id uuid.UUID) error {
+	if err := s.userRepository.Unsuspend(ctx, id); err != nil {
+		return err
+	}
+
+	s.set(id, false, time.Now())
+	return nil
+}
+
+// Delete removes the account and rejects its remaining tokens right away.
+func (s *SuspensionService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.userRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.set(id, true, time.Now())
+	return nil
+}
+
+// SoftDelete marks the account deleted and rejects its remaining tokens
+// right away.
+func (s *SuspensionService) SoftDelete(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	if err := s.userRepository.SoftDelete(ctx, id, now.UTC()); err != nil {
+		return err
+	}
+
+	s.set(id, true, now)
+	return nil
+}
+
+func (s *SuspensionService) set(id uuid.UUID, suspended bool, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.cache) >= maxSuspensionCacheEntries {
+		for userID, status := range s.cache {
+			if now.After(status.cachedUntil) {
+				delete(s.cache, userID)
+			}
+		}
+	}
+	s.cache[id] = suspensionStatus{
+		suspended:   suspended,
+		cachedUntil: now.Add(s.cacheTTL),
+	}
+}
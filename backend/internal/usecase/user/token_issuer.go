@@ -0,0 +1,142 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	jwtservice "roadmap/internal/pkg/jwt"
+	userrepo "roadmap/internal/repository/user"
+)
+
+// TokenIssuer hands out a short-lived access token together with an opaque
+// refresh token persisted through RefreshTokenRepository.
+type TokenIssuer struct {
+	jwtService             *jwtservice.JWTService
+	refreshTokenRepository userrepo.RefreshTokenRepository
+	sessionService         *SessionService
+	refreshTokenTTL        time.Duration
+}
+
+// NewTokenIssuer creates the issuer used by every sign-in method.
+// sessionService may be nil to not record sessions.
+func NewTokenIssuer(
+	jwtService *jwtservice.JWTService,
+	refreshTokenRepository userrepo.RefreshTokenRepository,
+	sessionService *SessionService,
+	refreshTokenTTL time.Duration,
+) *TokenIssuer {
+	return &TokenIssuer{
+		jwtService:             jwtService,
+		refreshTokenRepository: refreshTokenRepository,
+		sessionService:         sessionService,
+		refreshTokenTTL:        refreshTokenTTL,
+	}
+}
+
+// Issue starts a new refresh token family for user, and with it a session
+// on the device described by client. Suspended users get
+// ErrAccountSuspended, which makes every sign-in method respect suspension.
+func (i *TokenIssuer) Issue(
+	ctx context.Context,
+	user *userentity.User,
+	client userdto.ClientInfo,
+) (userdto.TokenPair, error) {
+	if user.IsSuspended() {
+		return userdto.TokenPair{}, ErrAccountSuspended
+	}
+
+	refreshToken, rawRefreshToken, err := i.newRefreshToken(user.ID, uuid.New())
+	if err != nil {
+		return userdto.TokenPair{}, err
+	}
+
+	if _, err := i.refreshTokenRepository.Create(ctx, refreshToken); err != nil {
+		return userdto.TokenPair{}, err
+	}
+
+	// Recorded after the refresh token so PurgeExpired never finds the
+	// session without one.
+	if i.sessionService != nil {
+		if err := i.sessionService.Start(ctx, refreshToken.FamilyID, user.ID, client); err != nil {
+			return userdto.TokenPair{}, err
+		}
+	}
+
+	return i.tokenPair(user, refreshToken, rawRefreshToken)
+}
+
+// Rotate replaces current with a new refresh token in the same family and
+// session.
+func (i *TokenIssuer) Rotate(
+	ctx context.Context,
+	user *userentity.User,
+	current *userentity.RefreshToken,
+) (userdto.TokenPair, error) {
+	if user.IsSuspended() {
+		return userdto.TokenPair{}, ErrAccountSuspended
+	}
+
+	refreshToken, rawRefreshToken, err := i.newRefreshToken(user.ID, current.FamilyID)
+	if err != nil {
+		return userdto.TokenPair{}, err
+	}
+
+	if _, err := i.refreshTokenRepository.Rotate(ctx, current.ID, refreshToken); err != nil {
+		return userdto.TokenPair{}, err
+	}
+
+	if i.sessionService != nil {
+		i.sessionService.Touch(ctx, current.FamilyID)
+	}
+
+	return i.tokenPair(user, refreshToken, rawRefreshToken)
+}
+
+func (i *TokenIssuer) newRefreshToken(userID, familyID uuid.UUID) (*userentity.RefreshToken, string, error) {
+	rawToken, err := generateOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now().UTC()
+	return &userentity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashOpaqueToken(rawToken),
+		ExpiresAt: now.Add(i.refreshTokenTTL),
+		CreatedAt: now,
+	}, rawToken, nil
+}
+
+func (i *TokenIssuer) tokenPair(
+	user *userentity.User,
+	refreshToken *userentity.RefreshToken,
+	rawRefreshToken string,
+) (userdto.TokenPair, error) {
+	accessTokenExpiresAt := time.Now().Add(i.jwtService.ExpiresIn())
+	accessToken, err := i.jwtService.GenerateTokenWithClaims(jwtservice.Claims{
+		UserID:        user.ID.String(),
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.IsEmailVerified(),
+		Roles:         user.Roles,
+		Permissions:   user.Permissions,
+		SessionID:     refreshToken.FamilyID.String(),
+	})
+	if err != nil {
+		return userdto.TokenPair{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return userdto.TokenPair{
+		Token:                 accessToken,
+		TokenExpiresAt:        accessTokenExpiresAt,
+		RefreshToken:          rawRefreshToken,
+		RefreshTokenExpiresAt: refreshToken.ExpiresAt,
+	}, nil
+}
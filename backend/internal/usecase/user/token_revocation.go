@@ -0,0 +1,213 @@
+package user
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	jwtservice "roadmap/internal/pkg/jwt"
+	userrepo "roadmap/internal/repository/user"
+)
+
+const maxRevocationCacheEntries = 10000
+
+// TokenRevocationService decides whether an access token that is otherwise
+// valid has been revoked, either individually by its jti or because the user
+// signed out everywhere after it was issued.
+//
+// Lookups are cached in process. Revocations made through this service are
+// visible immediately; revocations made by other API instances become visible
+// once the cached answer is older than cacheTTL.
+type TokenRevocationService struct {
+	repository userrepo.TokenRevocationRepository
+	cacheTTL   time.Duration
+
+	mu            sync.Mutex
+	revokedTokens map[string]time.Time
+	activeTokens  map[string]time.Time
+	cutoffs       map[uuid.UUID]tokenCutoff
+}
+
+type tokenCutoff struct {
+	validAfter  time.Time
+	cachedUntil time.Time
+}
+
+func NewTokenRevocationService(
+	repository userrepo.TokenRevocationRepository,
+	cacheTTL time.Duration,
+) *TokenRevocationService {
+	return &TokenRevocationService{
+		repository:    repository,
+		cacheTTL:      cacheTTL,
+		revokedTokens: make(map[string]time.Time),
+		activeTokens:  make(map[string]time.Time),
+		cutoffs:       make(map[uuid.UUID]tokenCutoff),
+	}
+}
+
+func (s *TokenRevocationService) RevokeToken(ctx context.Context, claims *jwtservice.Claims) error {
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return ErrInvalidTokenClaims
+	}
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return ErrInvalidTokenClaims
+	}
+
+	expiresAt := claims.ExpiresAt.Time.UTC()
+	if err := s.repository.RevokeToken(ctx, claims.ID, userID, expiresAt); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.activeTokens, claims.ID)
+	s.revokedTokens[claims.ID] = expiresAt
+
+	return nil
+}
+
+// RevokeAllForUser invalidates every access token issued to the user before
+// now. The cutoff is kept to the microsecond, like the issue time tokens
+// record, so a token issued right after the revocation, such as on signing
+// in again after a password reset, stays valid while one issued earlier in
+// the same second does not.
+func (s *TokenRevocationService) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	validAfter := time.Now().UTC().Truncate(time.Microsecond)
+	if err := s.repository.SetTokensValidAfter(ctx, userID, validAfter); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cutoffs[userID] = tokenCutoff{
+		validAfter:  validAfter,
+		cachedUntil: validAfter.Add(s.cacheTTL),
+	}
+
+	return nil
+}
+
+func (s *TokenRevocationService) IsRevoked(ctx context.Context, claims *jwtservice.Claims) (bool, error) {
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return true, nil
+	}
+
+	validAfter, err := s.tokensValidAfter(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !validAfter.IsZero() {
+		// Tokens without a microsecond issue time count as issued at the
+		// start of their second, so they are rejected for the whole second of
+		// the cutoff.
+		if issuedAt := claims.IssuedAtTime(); issuedAt.IsZero() || issuedAt.Before(validAfter) {
+			return true, nil
+		}
+	}
+
+	if claims.ID == "" {
+		return false, nil
+	}
+
+	return s.isTokenRevoked(ctx, claims.ID)
+}
+
+func (s *TokenRevocationService) tokensValidAfter(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.cutoffs[userID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.cachedUntil) {
+		return cached.validAfter, nil
+	}
+
+	validAfter, err := s.repository.GetTokensValidAfter(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cutoffs) >= maxRevocationCacheEntries {
+		s.pruneLocked(now)
+	}
+	if current, ok := s.cutoffs[userID]; ok && current.validAfter.After(validAfter) {
+		validAfter = current.validAfter
+	}
+	s.cutoffs[userID] = tokenCutoff{
+		validAfter:  validAfter,
+		cachedUntil: now.Add(s.cacheTTL),
+	}
+
+	return validAfter, nil
+}
+
+func (s *TokenRevocationService) isTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	if _, ok := s.revokedTokens[jti]; ok {
+		s.mu.Unlock()
+		return true, nil
+	}
+	if cachedUntil, ok := s.activeTokens[jti]; ok && now.Before(cachedUntil) {
+		s.mu.Unlock()
+		return false, nil
+	}
+	s.mu.Unlock()
+
+	revoked, err := s.repository.IsTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.revokedTokens)+len(s.activeTokens) >= maxRevocationCacheEntries {
+		s.pruneLocked(now)
+	}
+	if revoked {
+		// The exact expiry is unknown here; keeping the entry for the cache
+		// TTL is enough because expired tokens are rejected before this check.
+		s.revokedTokens[jti] = now.Add(s.cacheTTL)
+	} else {
+		s.activeTokens[jti] = now.Add(s.cacheTTL)
+	}
+
+	return revoked, nil
+}
+
+// PurgeExpired drops revocation records of tokens that have expired anyway.
+func (s *TokenRevocationService) PurgeExpired(ctx context.Context) (int64, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	s.pruneLocked(now)
+	s.mu.Unlock()
+
+	return s.repository.DeleteExpired(ctx, now.UTC())
+}
+
+func (s *TokenRevocationService) pruneLocked(now time.Time) {
+	for jti, expiresAt := range s.revokedTokens {
+		if now.After(expiresAt) {
+			delete(s.revokedTokens, jti)
+		}
+	}
+	for jti, cachedUntil := range s.activeTokens {
+		if now.After(cachedUntil) {
+			delete(s.activeTokens, jti)
+		}
+	}
+	for userID, cutoff := range s.cutoffs {
+		if now.After(cutoff.cachedUntil) {
+			delete(s.cutoffs, userID)
+		}
+	}
+}
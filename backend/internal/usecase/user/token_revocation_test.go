@@ -0,0 +1,234 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	jwtservice "roadmap/internal/pkg/jwt"
+)
+
+type MockTokenRevocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRevocationRepository) RevokeToken(
+	ctx context.Context,
+	jti string,
+	userID uuid.UUID,
+	expiresAt time.Time,
+) error {
+	args := m.Called(ctx, jti, userID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	args := m.Called(ctx, jti)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRevocationRepository) SetTokensValidAfter(
+	ctx context.Context,
+	userID uuid.UUID,
+	validAfter time.Time,
+) error {
+	args := m.Called(ctx, userID, validAfter)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) GetTokensValidAfter(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockTokenRevocationRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type TokenRevocationServiceTestSuite struct {
+	suite.Suite
+	service  *TokenRevocationService
+	mockRepo *MockTokenRevocationRepository
+	userID   uuid.UUID
+	claims   *jwtservice.Claims
+	ctx      context.Context
+}
+
+func (s *TokenRevocationServiceTestSuite) SetupTest() {
+	s.mockRepo = new(MockTokenRevocationRepository)
+	s.service = NewTokenRevocationService(s.mockRepo, time.Minute)
+	s.ctx = context.Background()
+	s.userID = uuid.New()
+
+	issuedAt := time.Now().Add(-time.Minute)
+	s.claims = &jwtservice.Claims{
+		UserID:   s.userID.String(),
+		Username: "testuser",
+		Email:    "test@example.com",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(15 * time.Minute)),
+		},
+	}
+}
+
+func (s *TokenRevocationServiceTestSuite) TearDownTest() {
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+func (s *TokenRevocationServiceTestSuite) TestIsRevoked_NotRevokedIsCached() {
+	s.mockRepo.On("GetTokensValidAfter", s.ctx, s.userID).Return(time.Time{}, nil).Once()
+	s.mockRepo.On("IsTokenRevoked", s.ctx, s.claims.ID).Return(false, nil).Once()
+
+	for i := 0; i < 3; i++ {
+		revoked, err := s.service.IsRevoked(s.ctx, s.claims)
+		assert.NoError(s.T(), err)
+		assert.False(s.T(), revoked)
+	}
+}
+
+func (s *TokenRevocationServiceTestSuite) TestIsRevoked_RevokedInDatabase() {
+	s.mockRepo.On("GetTokensValidAfter", s.ctx, s.userID).Return(time.Time{}, nil).Once()
+	s.mockRepo.On("IsTokenRevoked", s.ctx, s.claims.ID).Return(true, nil).Once()
+
+	revoked, err := s.service.IsRevoked(s.ctx, s.claims)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+
+	revoked, err = s.service.IsRevoked(s.ctx, s.claims)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+}
+
+func (s *TokenRevocationServiceTestSuite) TestRevokeToken_VisibleImmediately() {
+	s.mockRepo.On("GetTokensValidAfter", s.ctx, s.userID).Return(time.Time{}, nil).Once()
+	s.mockRepo.On("IsTokenRevoked", s.ctx, s.claims.ID).Return(false, nil).Once()
+	s.mockRepo.On("RevokeToken", s.ctx, s.claims.ID, s.userID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	revoked, err := s.service.IsRevoked(s.ctx, s.claims)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+
+	assert.NoError(s.T(), s.service.RevokeToken(s.ctx, s.claims))
+
+	revoked, err = s.service.IsRevoked(s.ctx, s.claims)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+}
+
+func (s *TokenRevocationServiceTestSuite) TestRevokeToken_MissingJTI() {
+	s.claims.ID = ""
+
+	err := s.service.RevokeToken(s.ctx, s.claims)
+
+	assert.ErrorIs(s.T(), err, ErrInvalidTokenClaims)
+}
+
+func (s *TokenRevocationServiceTestSuite) TestRevokeAllForUser_RejectsEarlierTokens() {
+	s.mockRepo.On("SetTokensValidAfter", s.ctx, s.userID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	assert.NoError(s.T(), s.service.RevokeAllForUser(s.ctx, s.userID))
+
+	revoked, err := s.service.IsRevoked(s.ctx, s.claims)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+	s.mockRepo.AssertNotCalled(s.T(), "IsTokenRevoked", mock.Anything, mock.Anything)
+}
+
+func (s *TokenRevocationServiceTestSuite) TestRevokeAllForUser_AcceptsTokenIssuedRightAfter() {
+	s.mockRepo.On("SetTokensValidAfter", s.ctx, s.userID, mock.MatchedBy(func(validAfter time.Time) bool {
+		return validAfter.Equal(validAfter.Truncate(time.Microsecond))
+	})).Return(nil)
+
+	assert.NoError(s.T(), s.service.RevokeAllForUser(s.ctx, s.userID))
+
+	// Signing in again straight away issues a token in the same second.
+	jwtService := jwtservice.NewJWTService("test-secret", 15*time.Minute)
+	token, err := jwtService.GenerateToken(s.userID.String(), "testuser", "test@example.com")
+	s.Require().NoError(err)
+	claims, err := jwtService.ValidateToken(token)
+	s.Require().NoError(err)
+	s.mockRepo.On("IsTokenRevoked", s.ctx, claims.ID).Return(false, nil)
+
+	revoked, err := s.service.IsRevoked(s.ctx, claims)
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+}
+
+func (s *TokenRevocationServiceTestSuite) TestIsRevoked_TokenIssuedEarlierInCutoffSecond() {
+	validAfter := time.Now().UTC().Truncate(time.Second).Add(500 * time.Millisecond)
+	s.mockRepo.On("GetTokensValidAfter", s.ctx, s.userID).Return(validAfter, nil)
+
+	issuedAt := validAfter.Add(-time.Millisecond)
+	s.claims.IssuedAt = jwt.NewNumericDate(issuedAt)
+	s.claims.IssuedAtMicros = issuedAt.UnixMicro()
+
+	revoked, err := s.service.IsRevoked(s.ctx, s.claims)
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+}
+
+func (s *TokenRevocationServiceTestSuite) TestIsRevoked_SecondPrecisionTokenInCutoffSecond() {
+	validAfter := time.Now().UTC().Truncate(time.Second).Add(500 * time.Millisecond)
+	s.mockRepo.On("GetTokensValidAfter", s.ctx, s.userID).Return(validAfter, nil)
+
+	// Without iat_us the token may have been issued anywhere in the second.
+	s.claims.IssuedAt = jwt.NewNumericDate(validAfter.Add(100 * time.Millisecond))
+
+	revoked, err := s.service.IsRevoked(s.ctx, s.claims)
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+}
+
+func (s *TokenRevocationServiceTestSuite) TestIsRevoked_TokenIssuedAfterCutoff() {
+	s.mockRepo.On("GetTokensValidAfter", s.ctx, s.userID).Return(time.Now().Add(-time.Hour), nil)
+	s.mockRepo.On("IsTokenRevoked", s.ctx, s.claims.ID).Return(false, nil)
+
+	revoked, err := s.service.IsRevoked(s.ctx, s.claims)
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+}
+
+func (s *TokenRevocationServiceTestSuite) TestIsRevoked_RepositoryError() {
+	repoError := errors.New("database error")
+	s.mockRepo.On("GetTokensValidAfter", s.ctx, s.userID).Return(time.Time{}, repoError)
+
+	_, err := s.service.IsRevoked(s.ctx, s.claims)
+
+	assert.Equal(s.T(), repoError, err)
+}
+
+func (s *TokenRevocationServiceTestSuite) TestIsRevoked_InvalidUserID() {
+	s.claims.UserID = "not-a-uuid"
+
+	revoked, err := s.service.IsRevoked(s.ctx, s.claims)
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+}
+
+func (s *TokenRevocationServiceTestSuite) TestPurgeExpired() {
+	s.mockRepo.On("DeleteExpired", s.ctx, mock.AnythingOfType("time.Time")).Return(int64(3), nil)
+
+	purged, err := s.service.PurgeExpired(s.ctx)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(3), purged)
+}
+
+func TestTokenRevocationServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenRevocationServiceTestSuite))
+}
@@ -0,0 +1,337 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/totp"
+	userrepo "roadmap/internal/repository/user"
+)
+
+const (
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 7
+
+	// totpSkew accepts codes from one step before and after the current one
+	// to tolerate clock drift between server and device.
+	totpSkew = 1
+
+	// maxMFAAttempts caps code attempts per challenge; six digit codes would
+	// otherwise be brute-forceable within the challenge lifetime.
+	maxMFAAttempts = 5
+)
+
+type TwoFactorSetupUseCase struct {
+	userRepository userrepo.UserRepository
+	totpRepository userrepo.TOTPRepository
+	issuer         string
+}
+
+func NewTwoFactorSetupUseCase(
+	userRepository userrepo.UserRepository,
+	totpRepository userrepo.TOTPRepository,
+	issuer string,
+) *TwoFactorSetupUseCase {
+	return &TwoFactorSetupUseCase{
+		userRepository: userRepository,
+		totpRepository: totpRepository,
+		issuer:         issuer,
+	}
+}
+
+// Execute generates a new pending secret. Calling it again before the
+// secret is confirmed replaces the previous one.
+func (u *TwoFactorSetupUseCase) Execute(ctx context.Context, userID string) (userdto.TwoFactorSetupResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.TwoFactorSetupResponse{}, ErrInvalidTokenClaims
+	}
+
+	user, err := u.userRepository.GetByID(ctx, id)
+	if err != nil {
+		return userdto.TwoFactorSetupResponse{}, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return userdto.TwoFactorSetupResponse{}, err
+	}
+
+	_, err = u.totpRepository.Upsert(ctx, &userentity.UserTOTP{
+		UserID:    user.ID,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, userrepo.ErrTOTPAlreadyConfirmed) {
+			return userdto.TwoFactorSetupResponse{}, ErrTwoFactorAlreadyEnabled
+		}
+		return userdto.TwoFactorSetupResponse{}, err
+	}
+
+	return userdto.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(u.issuer, user.Email, secret),
+	}, nil
+}
+
+type TwoFactorConfirmUseCase struct {
+	totpRepository         userrepo.TOTPRepository
+	recoveryCodeRepository userrepo.RecoveryCodeRepository
+}
+
+func NewTwoFactorConfirmUseCase(
+	totpRepository userrepo.TOTPRepository,
+	recoveryCodeRepository userrepo.RecoveryCodeRepository,
+) *TwoFactorConfirmUseCase {
+	return &TwoFactorConfirmUseCase{
+		totpRepository:         totpRepository,
+		recoveryCodeRepository: recoveryCodeRepository,
+	}
+}
+
+// Execute enables two-factor authentication once the user proves their
+// authenticator produces valid codes. The recovery codes are only ever
+// returned here; just their hashes are stored.
+func (u *TwoFactorConfirmUseCase) Execute(
+	ctx context.Context,
+	userID string,
+	req userdto.TwoFactorConfirmRequest,
+) (userdto.TwoFactorConfirmResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return userdto.TwoFactorConfirmResponse{}, ErrInvalidTokenClaims
+	}
+
+	pending, err := u.totpRepository.GetByUserID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return userdto.TwoFactorConfirmResponse{}, ErrTwoFactorNotPending
+		}
+		return userdto.TwoFactorConfirmResponse{}, err
+	}
+
+	if pending.IsEnabled() {
+		return userdto.TwoFactorConfirmResponse{}, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := totp.Validate(pending.Secret, req.Code, time.Now(), totpSkew)
+	if !ok {
+		return userdto.TwoFactorConfirmResponse{}, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return userdto.TwoFactorConfirmResponse{}, err
+	}
+
+	if err := u.recoveryCodeRepository.Replace(ctx, id, hashes); err != nil {
+		return userdto.TwoFactorConfirmResponse{}, err
+	}
+
+	if err := u.totpRepository.Confirm(ctx, id, step); err != nil {
+		if errors.Is(err, userrepo.ErrTOTPAlreadyConfirmed) {
+			return userdto.TwoFactorConfirmResponse{}, ErrTwoFactorAlreadyEnabled
+		}
+		return userdto.TwoFactorConfirmResponse{}, err
+	}
+
+	return userdto.TwoFactorConfirmResponse{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// TwoFactorChallenger decides whether a login needs a second factor and, if
+// so, issues the short-lived challenge token the client exchanges at
+// /users/login/2fa.
+type TwoFactorChallenger struct {
+	totpRepository         userrepo.TOTPRepository
+	mfaChallengeRepository userrepo.MFAChallengeRepository
+	challengeTTL           time.Duration
+}
+
+func NewTwoFactorChallenger(
+	totpRepository userrepo.TOTPRepository,
+	mfaChallengeRepository userrepo.MFAChallengeRepository,
+	challengeTTL time.Duration,
+) *TwoFactorChallenger {
+	return &TwoFactorChallenger{
+		totpRepository:         totpRepository,
+		mfaChallengeRepository: mfaChallengeRepository,
+		challengeTTL:           challengeTTL,
+	}
+}
+
+func (c *TwoFactorChallenger) Required(ctx context.Context, userID uuid.UUID) (bool, error) {
+	enrolled, err := c.totpRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return enrolled.IsEnabled(), nil
+}
+
+func (c *TwoFactorChallenger) Issue(ctx context.Context, userID uuid.UUID) (*userdto.MFAChallengeResponse, error) {
+	token, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	challenge, err := c.mfaChallengeRepository.Create(ctx, &userentity.MFAChallenge{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: hashOpaqueToken(token),
+		ExpiresAt: now.Add(c.challengeTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &userdto.MFAChallengeResponse{
+		MFARequired:       true,
+		MFAToken:          token,
+		MFATokenExpiresAt: challenge.ExpiresAt,
+	}, nil
+}
+
+type TwoFactorLoginUseCase struct {
+	userRepository         userrepo.UserRepository
+	totpRepository         userrepo.TOTPRepository
+	recoveryCodeRepository userrepo.RecoveryCodeRepository
+	mfaChallengeRepository userrepo.MFAChallengeRepository
+	tokenIssuer            *TokenIssuer
+}
+
+func NewTwoFactorLoginUseCase(
+	userRepository userrepo.UserRepository,
+	totpRepository userrepo.TOTPRepository,
+	recoveryCodeRepository userrepo.RecoveryCodeRepository,
+	mfaChallengeRepository userrepo.MFAChallengeRepository,
+	tokenIssuer *TokenIssuer,
+) *TwoFactorLoginUseCase {
+	return &TwoFactorLoginUseCase{
+		userRepository:         userRepository,
+		totpRepository:         totpRepository,
+		recoveryCodeRepository: recoveryCodeRepository,
+		mfaChallengeRepository: mfaChallengeRepository,
+		tokenIssuer:            tokenIssuer,
+	}
+}
+
+func (u *TwoFactorLoginUseCase) Execute(
+	ctx context.Context,
+	req userdto.TwoFactorLoginRequest,
+	client userdto.ClientInfo,
+) (userdto.LoginResponse, error) {
+	challenge, err := u.mfaChallengeRepository.GetByHash(ctx, hashOpaqueToken(req.MFAToken))
+	if err != nil {
+		return userdto.LoginResponse{}, ErrInvalidMFAChallenge
+	}
+
+	if challenge.IsUsed() || challenge.IsExpired(time.Now()) {
+		return userdto.LoginResponse{}, ErrInvalidMFAChallenge
+	}
+
+	// The attempt is reserved before the code is checked so concurrent
+	// requests cannot exceed the cap between reading and incrementing it.
+	if err := u.mfaChallengeRepository.ReserveAttempt(ctx, challenge.ID, maxMFAAttempts); err != nil {
+		if errors.Is(err, userrepo.ErrMFAAttemptsExhausted) {
+			return userdto.LoginResponse{}, ErrInvalidMFAChallenge
+		}
+		return userdto.LoginResponse{}, err
+	}
+
+	enrolled, err := u.totpRepository.GetByUserID(ctx, challenge.UserID)
+	if err != nil || !enrolled.IsEnabled() {
+		return userdto.LoginResponse{}, ErrInvalidMFAChallenge
+	}
+
+	if err := u.verifyCode(ctx, enrolled, req.Code); err != nil {
+		return userdto.LoginResponse{}, err
+	}
+
+	if err := u.mfaChallengeRepository.MarkUsed(ctx, challenge.ID); err != nil {
+		if errors.Is(err, userrepo.ErrTokenAlreadyUsed) {
+			return userdto.LoginResponse{}, ErrInvalidMFAChallenge
+		}
+		return userdto.LoginResponse{}, err
+	}
+
+	user, err := u.userRepository.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		return userdto.LoginResponse{}, err
+	}
+
+	tokens, err := u.tokenIssuer.Issue(ctx, user, client)
+	if err != nil {
+		return userdto.LoginResponse{}, err
+	}
+
+	return userdto.LoginResponse{
+		TokenPair: tokens,
+	}, nil
+}
+
+// verifyCode accepts a current TOTP code or an unused recovery code. Each
+// TOTP step and each recovery code is accepted at most once.
+func (u *TwoFactorLoginUseCase) verifyCode(ctx context.Context, enrolled *userentity.UserTOTP, code string) error {
+	if step, ok := totp.Validate(enrolled.Secret, code, time.Now(), totpSkew); ok {
+		err := u.totpRepository.RecordUsedStep(ctx, enrolled.UserID, step)
+		if errors.Is(err, userrepo.ErrTokenAlreadyUsed) {
+			return ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return ErrInvalidTwoFactorCode
+	}
+
+	err := u.recoveryCodeRepository.Consume(ctx, enrolled.UserID, hashOpaqueToken(normalized))
+	if errors.Is(err, userrepo.ErrTokenAlreadyUsed) {
+		return ErrInvalidTwoFactorCode
+	}
+	return err
+}
+
+// generateRecoveryCodes returns codes formatted as XXXXX-XXXXX for display
+// together with the hashes of their normalized form.
+func generateRecoveryCodes() ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+
+		raw := encoding.EncodeToString(buf)[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashOpaqueToken(raw))
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	replacer := strings.NewReplacer("-", "", " ", "")
+	return strings.ToUpper(replacer.Replace(code))
+}
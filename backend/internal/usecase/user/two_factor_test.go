@@ -0,0 +1,400 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	userdto "roadmap/internal/domain/dto/user"
+	userentity "roadmap/internal/domain/entities/user"
+	"roadmap/internal/pkg/totp"
+	userrepo "roadmap/internal/repository/user"
+)
+
+type MockTOTPRepository struct {
+	mock.Mock
+}
+
+func (m *MockTOTPRepository) Upsert(ctx context.Context, enrolled *userentity.UserTOTP) (*userentity.UserTOTP, error) {
+	args := m.Called(ctx, enrolled)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.UserTOTP), args.Error(1)
+}
+
+func (m *MockTOTPRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*userentity.UserTOTP, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.UserTOTP), args.Error(1)
+}
+
+func (m *MockTOTPRepository) Confirm(ctx context.Context, userID uuid.UUID, step int64) error {
+	args := m.Called(ctx, userID, step)
+	return args.Error(0)
+}
+
+func (m *MockTOTPRepository) RecordUsedStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	args := m.Called(ctx, userID, step)
+	return args.Error(0)
+}
+
+type MockRecoveryCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecoveryCodeRepository) Replace(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	args := m.Called(ctx, userID, codeHashes)
+	return args.Error(0)
+}
+
+func (m *MockRecoveryCodeRepository) Consume(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Error(0)
+}
+
+type MockMFAChallengeRepository struct {
+	mock.Mock
+}
+
+func (m *MockMFAChallengeRepository) Create(
+	ctx context.Context,
+	challenge *userentity.MFAChallenge,
+) (*userentity.MFAChallenge, error) {
+	args := m.Called(ctx, challenge)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.MFAChallenge), args.Error(1)
+}
+
+func (m *MockMFAChallengeRepository) GetByHash(ctx context.Context, tokenHash string) (*userentity.MFAChallenge, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userentity.MFAChallenge), args.Error(1)
+}
+
+func (m *MockMFAChallengeRepository) ReserveAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) error {
+	args := m.Called(ctx, id, maxAttempts)
+	return args.Error(0)
+}
+
+func (m *MockMFAChallengeRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type TwoFactorUseCaseTestSuite struct {
+	suite.Suite
+	setupUseCase   *TwoFactorSetupUseCase
+	confirmUseCase *TwoFactorConfirmUseCase
+	loginUseCase   *TwoFactorLoginUseCase
+	userRepo       *MockUserRepository
+	totpRepo       *MockTOTPRepository
+	recoveryRepo   *MockRecoveryCodeRepository
+	challengeRepo  *MockMFAChallengeRepository
+	refreshRepo    *MockRefreshTokenRepository
+	user           *userentity.User
+	secret         string
+	ctx            context.Context
+}
+
+func (s *TwoFactorUseCaseTestSuite) SetupTest() {
+	s.userRepo = new(MockUserRepository)
+	s.totpRepo = new(MockTOTPRepository)
+	s.recoveryRepo = new(MockRecoveryCodeRepository)
+	s.challengeRepo = new(MockMFAChallengeRepository)
+	s.refreshRepo = new(MockRefreshTokenRepository)
+	s.ctx = context.Background()
+
+	s.setupUseCase = NewTwoFactorSetupUseCase(s.userRepo, s.totpRepo, "Roadmap")
+	s.confirmUseCase = NewTwoFactorConfirmUseCase(s.totpRepo, s.recoveryRepo)
+	s.loginUseCase = NewTwoFactorLoginUseCase(
+		s.userRepo, s.totpRepo, s.recoveryRepo, s.challengeRepo, newTestTokenIssuer(s.refreshRepo),
+	)
+
+	s.user = &userentity.User{
+		ID:       uuid.New(),
+		Username: "testuser",
+		Email:    "test@example.com",
+	}
+
+	secret, err := totp.GenerateSecret()
+	s.Require().NoError(err)
+	s.secret = secret
+}
+
+func (s *TwoFactorUseCaseTestSuite) TearDownTest() {
+	s.userRepo.AssertExpectations(s.T())
+	s.totpRepo.AssertExpectations(s.T())
+	s.recoveryRepo.AssertExpectations(s.T())
+	s.challengeRepo.AssertExpectations(s.T())
+	s.refreshRepo.AssertExpectations(s.T())
+}
+
+func (s *TwoFactorUseCaseTestSuite) currentCode() string {
+	code, err := totp.Code(s.secret, totp.Step(time.Now()))
+	s.Require().NoError(err)
+	return code
+}
+
+func (s *TwoFactorUseCaseTestSuite) enrolled() *userentity.UserTOTP {
+	confirmedAt := time.Now().Add(-time.Hour)
+	return &userentity.UserTOTP{
+		UserID:      s.user.ID,
+		Secret:      s.secret,
+		ConfirmedAt: &confirmedAt,
+	}
+}
+
+func (s *TwoFactorUseCaseTestSuite) challenge() *userentity.MFAChallenge {
+	return &userentity.MFAChallenge{
+		ID:        uuid.New(),
+		UserID:    s.user.ID,
+		TokenHash: hashOpaqueToken("mfa-token"),
+		ExpiresAt: time.Now().Add(5 * time.Minute),
+	}
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestSetup_ReturnsOTPAuthURI() {
+	var stored *userentity.UserTOTP
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.totpRepo.On("Upsert", s.ctx, mock.AnythingOfType("*user.UserTOTP")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*userentity.UserTOTP) }).
+		Return(&userentity.UserTOTP{}, nil)
+
+	response, err := s.setupUseCase.Execute(s.ctx, s.user.ID.String())
+
+	s.Require().NoError(err)
+	assert.Equal(s.T(), stored.Secret, response.Secret)
+	assert.Nil(s.T(), stored.ConfirmedAt)
+
+	uri, err := url.Parse(response.OTPAuthURI)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "otpauth", uri.Scheme)
+	assert.Equal(s.T(), "/Roadmap:test@example.com", uri.Path)
+	assert.Equal(s.T(), response.Secret, uri.Query().Get("secret"))
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestSetup_AlreadyEnabled() {
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.totpRepo.On("Upsert", s.ctx, mock.Anything).Return(nil, userrepo.ErrTOTPAlreadyConfirmed)
+
+	_, err := s.setupUseCase.Execute(s.ctx, s.user.ID.String())
+
+	assert.ErrorIs(s.T(), err, ErrTwoFactorAlreadyEnabled)
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestSetup_InvalidUserID() {
+	_, err := s.setupUseCase.Execute(s.ctx, "not-a-uuid")
+
+	assert.ErrorIs(s.T(), err, ErrInvalidTokenClaims)
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestConfirm_EnablesAndReturnsRecoveryCodes() {
+	var hashes []string
+	s.totpRepo.On("GetByUserID", s.ctx, s.user.ID).Return(&userentity.UserTOTP{UserID: s.user.ID, Secret: s.secret}, nil)
+	s.recoveryRepo.On("Replace", s.ctx, s.user.ID, mock.Anything).
+		Run(func(args mock.Arguments) { hashes = args.Get(2).([]string) }).
+		Return(nil)
+	s.totpRepo.On("Confirm", s.ctx, s.user.ID, mock.AnythingOfType("int64")).Return(nil)
+
+	response, err := s.confirmUseCase.Execute(s.ctx, s.user.ID.String(), userdto.TwoFactorConfirmRequest{
+		Code: s.currentCode(),
+	})
+
+	s.Require().NoError(err)
+	assert.Len(s.T(), response.RecoveryCodes, recoveryCodeCount)
+	assert.Len(s.T(), hashes, recoveryCodeCount)
+	for i, code := range response.RecoveryCodes {
+		assert.Regexp(s.T(), `^[A-Z2-7]{5}-[A-Z2-7]{5}$`, code)
+		assert.Equal(s.T(), hashOpaqueToken(normalizeRecoveryCode(code)), hashes[i])
+	}
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestConfirm_WrongCode() {
+	s.totpRepo.On("GetByUserID", s.ctx, s.user.ID).Return(&userentity.UserTOTP{UserID: s.user.ID, Secret: s.secret}, nil)
+
+	_, err := s.confirmUseCase.Execute(s.ctx, s.user.ID.String(), userdto.TwoFactorConfirmRequest{
+		Code: "abcdef",
+	})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidTwoFactorCode)
+	s.recoveryRepo.AssertNotCalled(s.T(), "Replace", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestConfirm_NotStarted() {
+	s.totpRepo.On("GetByUserID", s.ctx, s.user.ID).Return(nil, pgx.ErrNoRows)
+
+	_, err := s.confirmUseCase.Execute(s.ctx, s.user.ID.String(), userdto.TwoFactorConfirmRequest{
+		Code: "123456",
+	})
+
+	assert.ErrorIs(s.T(), err, ErrTwoFactorNotPending)
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestConfirm_AlreadyEnabled() {
+	s.totpRepo.On("GetByUserID", s.ctx, s.user.ID).Return(s.enrolled(), nil)
+
+	_, err := s.confirmUseCase.Execute(s.ctx, s.user.ID.String(), userdto.TwoFactorConfirmRequest{
+		Code: s.currentCode(),
+	})
+
+	assert.ErrorIs(s.T(), err, ErrTwoFactorAlreadyEnabled)
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestLogin_WithTOTPCode() {
+	challenge := s.challenge()
+	s.challengeRepo.On("GetByHash", s.ctx, hashOpaqueToken("mfa-token")).Return(challenge, nil)
+	s.challengeRepo.On("ReserveAttempt", s.ctx, challenge.ID, maxMFAAttempts).Return(nil)
+	s.totpRepo.On("GetByUserID", s.ctx, s.user.ID).Return(s.enrolled(), nil)
+	s.totpRepo.On("RecordUsedStep", s.ctx, s.user.ID, mock.AnythingOfType("int64")).Return(nil)
+	s.challengeRepo.On("MarkUsed", s.ctx, challenge.ID).Return(nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.refreshRepo.On("Create", s.ctx, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
+
+	response, err := s.loginUseCase.Execute(s.ctx, userdto.TwoFactorLoginRequest{
+		MFAToken: "mfa-token",
+		Code:     s.currentCode(),
+	}, userdto.ClientInfo{})
+
+	s.Require().NoError(err)
+	assert.NotEmpty(s.T(), response.Token)
+	assert.NotEmpty(s.T(), response.RefreshToken)
+	assert.Nil(s.T(), response.MFAChallenge)
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestLogin_ReplayedTOTPCode() {
+	challenge := s.challenge()
+	s.challengeRepo.On("GetByHash", s.ctx, hashOpaqueToken("mfa-token")).Return(challenge, nil)
+	s.challengeRepo.On("ReserveAttempt", s.ctx, challenge.ID, maxMFAAttempts).Return(nil)
+	s.totpRepo.On("GetByUserID", s.ctx, s.user.ID).Return(s.enrolled(), nil)
+	s.totpRepo.On("RecordUsedStep", s.ctx, s.user.ID, mock.AnythingOfType("int64")).Return(userrepo.ErrTokenAlreadyUsed)
+
+	response, err := s.loginUseCase.Execute(s.ctx, userdto.TwoFactorLoginRequest{
+		MFAToken: "mfa-token",
+		Code:     s.currentCode(),
+	}, userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidTwoFactorCode)
+	assert.Empty(s.T(), response.Token)
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestLogin_WithRecoveryCode() {
+	challenge := s.challenge()
+	s.challengeRepo.On("GetByHash", s.ctx, hashOpaqueToken("mfa-token")).Return(challenge, nil)
+	s.challengeRepo.On("ReserveAttempt", s.ctx, challenge.ID, maxMFAAttempts).Return(nil)
+	s.totpRepo.On("GetByUserID", s.ctx, s.user.ID).Return(s.enrolled(), nil)
+	s.recoveryRepo.On("Consume", s.ctx, s.user.ID, hashOpaqueToken("ABCDEFGHIJ")).Return(nil)
+	s.challengeRepo.On("MarkUsed", s.ctx, challenge.ID).Return(nil)
+	s.userRepo.On("GetByID", s.ctx, s.user.ID).Return(s.user, nil)
+	s.refreshRepo.On("Create", s.ctx, mock.AnythingOfType("*user.RefreshToken")).Return(&userentity.RefreshToken{}, nil)
+
+	response, err := s.loginUseCase.Execute(s.ctx, userdto.TwoFactorLoginRequest{
+		MFAToken: "mfa-token",
+		Code:     "abcde-fghij",
+	}, userdto.ClientInfo{})
+
+	s.Require().NoError(err)
+	assert.NotEmpty(s.T(), response.Token)
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestLogin_UsedRecoveryCode() {
+	challenge := s.challenge()
+	s.challengeRepo.On("GetByHash", s.ctx, hashOpaqueToken("mfa-token")).Return(challenge, nil)
+	s.challengeRepo.On("ReserveAttempt", s.ctx, challenge.ID, maxMFAAttempts).Return(nil)
+	s.totpRepo.On("GetByUserID", s.ctx, s.user.ID).Return(s.enrolled(), nil)
+	s.recoveryRepo.On("Consume", s.ctx, s.user.ID, hashOpaqueToken("ABCDEFGHIJ")).Return(userrepo.ErrTokenAlreadyUsed)
+
+	_, err := s.loginUseCase.Execute(s.ctx, userdto.TwoFactorLoginRequest{
+		MFAToken: "mfa-token",
+		Code:     "ABCDE-FGHIJ",
+	}, userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidTwoFactorCode)
+	s.challengeRepo.AssertNotCalled(s.T(), "MarkUsed", mock.Anything, mock.Anything)
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestLogin_InvalidChallenge() {
+	expired := s.challenge()
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+
+	usedAt := time.Now()
+	used := s.challenge()
+	used.UsedAt = &usedAt
+
+	for _, challenge := range []*userentity.MFAChallenge{expired, used} {
+		s.challengeRepo.On("GetByHash", s.ctx, hashOpaqueToken("mfa-token")).Return(challenge, nil).Once()
+
+		_, err := s.loginUseCase.Execute(s.ctx, userdto.TwoFactorLoginRequest{
+			MFAToken: "mfa-token",
+			Code:     s.currentCode(),
+		}, userdto.ClientInfo{})
+
+		assert.ErrorIs(s.T(), err, ErrInvalidMFAChallenge)
+	}
+
+	s.challengeRepo.On("GetByHash", s.ctx, hashOpaqueToken("unknown")).Return(nil, pgx.ErrNoRows)
+	_, err := s.loginUseCase.Execute(s.ctx, userdto.TwoFactorLoginRequest{
+		MFAToken: "unknown",
+		Code:     s.currentCode(),
+	}, userdto.ClientInfo{})
+	assert.ErrorIs(s.T(), err, ErrInvalidMFAChallenge)
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestLogin_AttemptsExhausted() {
+	challenge := s.challenge()
+	challenge.Attempts = maxMFAAttempts
+	s.challengeRepo.On("GetByHash", s.ctx, hashOpaqueToken("mfa-token")).Return(challenge, nil)
+	s.challengeRepo.On("ReserveAttempt", s.ctx, challenge.ID, maxMFAAttempts).Return(userrepo.ErrMFAAttemptsExhausted)
+
+	_, err := s.loginUseCase.Execute(s.ctx, userdto.TwoFactorLoginRequest{
+		MFAToken: "mfa-token",
+		Code:     s.currentCode(),
+	}, userdto.ClientInfo{})
+
+	assert.ErrorIs(s.T(), err, ErrInvalidMFAChallenge)
+	s.totpRepo.AssertNotCalled(s.T(), "GetByUserID", mock.Anything, mock.Anything)
+	s.totpRepo.AssertNotCalled(s.T(), "RecordUsedStep", mock.Anything, mock.Anything, mock.Anything)
+	s.challengeRepo.AssertNotCalled(s.T(), "MarkUsed", mock.Anything, mock.Anything)
+}
+
+func (s *TwoFactorUseCaseTestSuite) TestChallenger_Required() {
+	challenger := NewTwoFactorChallenger(s.totpRepo, s.challengeRepo, 5*time.Minute)
+
+	s.totpRepo.On("GetByUserID", s.ctx, s.user.ID).Return(nil, pgx.ErrNoRows).Once()
+	required, err := challenger.Required(s.ctx, s.user.ID)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), required)
+
+	s.totpRepo.On("GetByUserID", s.ctx, s.user.ID).Return(&userentity.UserTOTP{UserID: s.user.ID}, nil).Once()
+	required, err = challenger.Required(s.ctx, s.user.ID)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), required)
+
+	s.totpRepo.On("GetByUserID", s.ctx, s.user.ID).Return(s.enrolled(), nil).Once()
+	required, err = challenger.Required(s.ctx, s.user.ID)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), required)
+
+	repoError := errors.New("database error")
+	s.totpRepo.On("GetByUserID", s.ctx, s.user.ID).Return(nil, repoError).Once()
+	_, err = challenger.Required(s.ctx, s.user.ID)
+	assert.Equal(s.T(), repoError, err)
+}
+
+func TestTwoFactorUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorUseCaseTestSuite))
+}
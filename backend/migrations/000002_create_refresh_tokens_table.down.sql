@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+DROP INDEX IF EXISTS idx_refresh_tokens_user_id;
+
+-- Drop refresh_tokens table
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,8 @@
+-- Drop user_token_cutoffs table
+DROP TABLE IF EXISTS user_token_cutoffs;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_revoked_access_tokens_expires_at;
+
+-- Drop revoked_access_tokens table
+DROP TABLE IF EXISTS revoked_access_tokens;
@@ -0,0 +1,17 @@
+-- Create revoked_access_tokens table
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Create index on expires_at for purging rows of tokens that expired anyway
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_expires_at ON revoked_access_tokens(expires_at);
+
+-- Create user_token_cutoffs table: access tokens issued at or before
+-- tokens_valid_after are rejected for the user
+CREATE TABLE IF NOT EXISTS user_token_cutoffs (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    tokens_valid_after TIMESTAMP NOT NULL
+);
@@ -0,0 +1,20 @@
+-- Track when a user confirmed ownership of their email address
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP;
+
+-- Accounts created before verification existed are treated as verified
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+-- Create email_verification_tokens table
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Create index for invalidating tokens and throttling resends per user
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user_id_created_at
+    ON email_verification_tokens(user_id, created_at);
@@ -0,0 +1,3 @@
+-- Drop profile fields
+ALTER TABLE users DROP COLUMN IF EXISTS bio;
+ALTER TABLE users DROP COLUMN IF EXISTS display_name;
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_email_change_requests_user_id;
+
+-- Drop email_change_requests table
+DROP TABLE IF EXISTS email_change_requests;
@@ -0,0 +1,16 @@
+-- Create email_change_requests table
+CREATE TABLE IF NOT EXISTS email_change_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_email VARCHAR(255) NOT NULL,
+    new_email VARCHAR(255) NOT NULL,
+    confirm_token_hash VARCHAR(64) UNIQUE NOT NULL,
+    cancel_token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Create index on user_id for cancelling pending requests of a user
+CREATE INDEX IF NOT EXISTS idx_email_change_requests_user_id ON email_change_requests(user_id);
@@ -0,0 +1,8 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_mfa_challenges_expires_at;
+DROP INDEX IF EXISTS idx_recovery_codes_user_id;
+
+-- Drop two-factor tables
+DROP TABLE IF EXISTS mfa_challenges;
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS user_totp;
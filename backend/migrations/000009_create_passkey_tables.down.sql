@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_webauthn_challenges_expires_at;
+DROP INDEX IF EXISTS idx_passkey_credentials_user_id;
+
+-- Drop passkey tables
+DROP TABLE IF EXISTS webauthn_challenges;
+DROP TABLE IF EXISTS passkey_credentials;
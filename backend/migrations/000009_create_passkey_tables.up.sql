@@ -0,0 +1,31 @@
+-- Create passkey_credentials table
+CREATE TABLE IF NOT EXISTS passkey_credentials (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credential_id BYTEA UNIQUE NOT NULL,
+    public_key BYTEA NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    aaguid BYTEA,
+    transports TEXT[] NOT NULL DEFAULT '{}',
+    backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Create webauthn_challenges table for in-flight ceremonies
+CREATE TABLE IF NOT EXISTS webauthn_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    challenge_hash VARCHAR(64) UNIQUE NOT NULL,
+    ceremony VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Create index on user_id for listing a user's passkeys
+CREATE INDEX IF NOT EXISTS idx_passkey_credentials_user_id ON passkey_credentials(user_id);
+
+-- Create index on expires_at for purging stale challenges
+CREATE INDEX IF NOT EXISTS idx_webauthn_challenges_expires_at ON webauthn_challenges(expires_at);
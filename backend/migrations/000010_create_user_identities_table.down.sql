@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_oidc_auth_requests_expires_at;
+
+-- Drop identity tables
+DROP TABLE IF EXISTS oidc_auth_requests;
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,28 @@
+-- Create user_identities table linking external OIDC subjects to users
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    last_login_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (provider, subject),
+    UNIQUE (user_id, provider)
+);
+
+-- Create oidc_auth_requests table for in-flight authorization code flows
+CREATE TABLE IF NOT EXISTS oidc_auth_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    state_hash VARCHAR(64) UNIQUE NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Create index on expires_at for purging stale authorization requests
+CREATE INDEX IF NOT EXISTS idx_oidc_auth_requests_expires_at ON oidc_auth_requests(expires_at);
@@ -0,0 +1,61 @@
+-- Create roles table
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Create permissions table
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Create role_permissions table
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+-- Create user_roles table; every user implicitly holds the member role, so it is never stored
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE CHECK (role <> 'member'),
+    granted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    granted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, role)
+);
+
+-- Create index on role for listing the holders of a role
+CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles(role);
+
+-- Seed roles
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Full access to user and role administration'),
+    ('moderator', 'Can review users and moderate roadmaps'),
+    ('member', 'Default role held by every account')
+ON CONFLICT (name) DO NOTHING;
+
+-- Seed permissions
+INSERT INTO permissions (name, description) VALUES
+    ('users:create', 'Create accounts on behalf of others'),
+    ('users:read', 'View any user account'),
+    ('users:manage', 'Suspend, reset and delete user accounts'),
+    ('roles:manage', 'Grant and revoke roles'),
+    ('roadmaps:create', 'Create roadmaps'),
+    ('roadmaps:moderate', 'Edit or remove any roadmap')
+ON CONFLICT (name) DO NOTHING;
+
+-- Seed role permissions
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'users:create'),
+    ('admin', 'users:read'),
+    ('admin', 'users:manage'),
+    ('admin', 'roles:manage'),
+    ('admin', 'roadmaps:moderate'),
+    ('moderator', 'users:read'),
+    ('moderator', 'roadmaps:moderate'),
+    ('member', 'roadmaps:create')
+ON CONFLICT (role, permission) DO NOTHING;
@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_users_created_at;
+
+-- Drop suspension fields
+ALTER TABLE users DROP COLUMN IF EXISTS suspension_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
@@ -0,0 +1,6 @@
+-- Add suspension fields to users
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspension_reason VARCHAR(500) NOT NULL DEFAULT '';
+
+-- Create index on created_at for paginated admin listings
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_login_throttles_last_failure_at;
+
+-- Drop login_throttles table
+DROP TABLE IF EXISTS login_throttles;
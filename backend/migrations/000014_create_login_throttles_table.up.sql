@@ -0,0 +1,12 @@
+-- Create login_throttles table
+CREATE TABLE IF NOT EXISTS login_throttles (
+    scope VARCHAR(10) NOT NULL CHECK (scope IN ('account', 'ip')),
+    key VARCHAR(255) NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP,
+    PRIMARY KEY (scope, key)
+);
+
+-- Create index on last_failure_at for purging stale entries
+CREATE INDEX IF NOT EXISTS idx_login_throttles_last_failure_at ON login_throttles(last_failure_at);
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_rate_limit_buckets_expires_at;
+
+-- Drop rate_limit_buckets table
+DROP TABLE IF EXISTS rate_limit_buckets;
@@ -0,0 +1,13 @@
+-- Create sessions table
+CREATE TABLE IF NOT EXISTS sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+-- Create index on user_id for listing the sessions of a user
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
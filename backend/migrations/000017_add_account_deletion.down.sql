@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_users_deleted_at;
+
+-- Drop soft deletion field
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,5 @@
+-- Add soft deletion to users; deleted accounts are purged after a grace period
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- Create partial index on deleted_at for the purge job
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_roadmaps_updated_at ON roadmaps;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_roadmaps_public_created_at;
+DROP INDEX IF EXISTS idx_roadmaps_owner_id_slug;
+
+-- Drop roadmaps table
+DROP TABLE IF EXISTS roadmaps;
@@ -0,0 +1,23 @@
+-- Create roadmaps table
+CREATE TABLE IF NOT EXISTS roadmaps (
+    id UUID PRIMARY KEY,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    visibility VARCHAR(20) NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'unlisted', 'public')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Create unique index on slug per owner; it also serves listing by owner
+CREATE UNIQUE INDEX IF NOT EXISTS idx_roadmaps_owner_id_slug ON roadmaps(owner_id, slug);
+
+-- Create index on public roadmaps for listing them newest first
+CREATE INDEX IF NOT EXISTS idx_roadmaps_public_created_at ON roadmaps(created_at DESC) WHERE visibility = 'public';
+
+-- Create trigger to automatically update updated_at on row update
+CREATE TRIGGER update_roadmaps_updated_at
+    BEFORE UPDATE ON roadmaps
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
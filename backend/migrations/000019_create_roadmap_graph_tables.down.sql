@@ -0,0 +1,11 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_roadmap_nodes_updated_at ON roadmap_nodes;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_roadmap_edges_node_id;
+
+-- Drop roadmap_edges table
+DROP TABLE IF EXISTS roadmap_edges;
+
+-- Drop roadmap_nodes table
+DROP TABLE IF EXISTS roadmap_nodes;
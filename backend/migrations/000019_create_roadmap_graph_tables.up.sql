@@ -0,0 +1,35 @@
+-- Create roadmap_nodes table
+CREATE TABLE IF NOT EXISTS roadmap_nodes (
+    id UUID PRIMARY KEY,
+    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    type VARCHAR(20) NOT NULL CHECK (type IN ('topic', 'subtopic', 'milestone')),
+    position_x DOUBLE PRECISION NOT NULL DEFAULT 0,
+    position_y DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    -- Lets edges reference a node together with its roadmap
+    UNIQUE (roadmap_id, id)
+);
+
+-- Create roadmap_edges table; node_id cannot be started before prerequisite_id is done
+CREATE TABLE IF NOT EXISTS roadmap_edges (
+    roadmap_id UUID NOT NULL REFERENCES roadmaps(id) ON DELETE CASCADE,
+    prerequisite_id UUID NOT NULL,
+    node_id UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (roadmap_id, prerequisite_id, node_id),
+    FOREIGN KEY (roadmap_id, prerequisite_id) REFERENCES roadmap_nodes(roadmap_id, id) ON DELETE CASCADE,
+    FOREIGN KEY (roadmap_id, node_id) REFERENCES roadmap_nodes(roadmap_id, id) ON DELETE CASCADE,
+    CHECK (prerequisite_id <> node_id)
+);
+
+-- Create index on node_id for finding the prerequisites of a node
+CREATE INDEX IF NOT EXISTS idx_roadmap_edges_node_id ON roadmap_edges(roadmap_id, node_id);
+
+-- Create trigger to automatically update updated_at on row update
+CREATE TRIGGER update_roadmap_nodes_updated_at
+    BEFORE UPDATE ON roadmap_nodes
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_node_progress_user_id_roadmap_id;
+
+-- Drop node_progress table
+DROP TABLE IF EXISTS node_progress;
@@ -0,0 +1,16 @@
+-- Create node_progress table; nodes without a row have not been started
+CREATE TABLE IF NOT EXISTS node_progress (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    roadmap_id UUID NOT NULL,
+    node_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('in_progress', 'done', 'skipped')),
+    prerequisites_overridden BOOLEAN NOT NULL DEFAULT FALSE,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, node_id),
+    FOREIGN KEY (roadmap_id, node_id) REFERENCES roadmap_nodes(roadmap_id, id) ON DELETE CASCADE
+);
+
+-- Create index on user_id and roadmap_id for loading a user's progress on a roadmap
+CREATE INDEX IF NOT EXISTS idx_node_progress_user_id_roadmap_id ON node_progress(user_id, roadmap_id);
@@ -0,0 +1,13 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_node_resources_updated_at ON node_resources;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_resource_completions_resource_id;
+DROP INDEX IF EXISTS idx_node_resources_node_id_position;
+DROP INDEX IF EXISTS idx_node_resources_roadmap_id_normalized_url;
+
+-- Drop resource_completions table
+DROP TABLE IF EXISTS resource_completions;
+
+-- Drop node_resources table
+DROP TABLE IF EXISTS node_resources;
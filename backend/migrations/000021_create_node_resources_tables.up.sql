@@ -0,0 +1,40 @@
+-- Create node_resources table; normalized_url identifies a resource within its roadmap
+CREATE TABLE IF NOT EXISTS node_resources (
+    id UUID PRIMARY KEY,
+    roadmap_id UUID NOT NULL,
+    node_id UUID NOT NULL,
+    url TEXT NOT NULL,
+    normalized_url TEXT NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('article', 'video', 'course', 'book', 'other')),
+    is_free BOOLEAN NOT NULL DEFAULT TRUE,
+    language VARCHAR(35) NOT NULL DEFAULT '',
+    estimated_minutes INTEGER CHECK (estimated_minutes > 0),
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (roadmap_id, node_id) REFERENCES roadmap_nodes(roadmap_id, id) ON DELETE CASCADE
+);
+
+-- Create unique index on normalized_url so a roadmap lists each resource once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_node_resources_roadmap_id_normalized_url ON node_resources(roadmap_id, normalized_url);
+
+-- Create index on node_id for listing the resources of a node in order
+CREATE INDEX IF NOT EXISTS idx_node_resources_node_id_position ON node_resources(node_id, position);
+
+-- Create resource_completions table
+CREATE TABLE IF NOT EXISTS resource_completions (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    resource_id UUID NOT NULL REFERENCES node_resources(id) ON DELETE CASCADE,
+    completed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, resource_id)
+);
+
+-- Create index on resource_id for the cascade from node_resources
+CREATE INDEX IF NOT EXISTS idx_resource_completions_resource_id ON resource_completions(resource_id);
+
+-- Create trigger to automatically update updated_at on row update
+CREATE TRIGGER update_node_resources_updated_at
+    BEFORE UPDATE ON node_resources
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_roadmap_nodes_roadmap_id_key;
+
+-- Drop key field
+ALTER TABLE roadmap_nodes DROP COLUMN IF EXISTS key;
@@ -0,0 +1,5 @@
+-- Add key column; it identifies a node across imports of the same roadmap
+ALTER TABLE roadmap_nodes ADD COLUMN IF NOT EXISTS key VARCHAR(100);
+
+-- Create unique index on key so a roadmap has one node per key
+CREATE UNIQUE INDEX IF NOT EXISTS idx_roadmap_nodes_roadmap_id_key ON roadmap_nodes(roadmap_id, key);